@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -11,6 +12,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"surge-tui/internal/app"
 	"surge-tui/internal/config"
+	"surge-tui/internal/control"
+	"surge-tui/internal/logging"
 )
 
 func main() {
@@ -19,6 +22,9 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	for _, conflict := range cfg.KeybindingConflicts() {
+		log.Printf("Keybinding conflict %s", conflict)
+	}
 
 	// Создаем контекст с отменой для graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -32,14 +38,16 @@ func main() {
 		cancel()
 	}()
 
-	// Определяем путь к проекту из аргументов командной строки
-	projectPath := ""
-	if len(os.Args) > 1 {
-		projectPath = os.Args[1]
-	}
+	// Флаги командной строки; путь к проекту — первый позиционный аргумент
+	noStartupActions := flag.Bool("no-startup-actions", false, "skip startup.actions from the config")
+	flag.Parse()
+	projectPath := flag.Arg(0)
 
 	// Создаем и запускаем приложение
 	application := app.New(cfg, projectPath)
+	if *noStartupActions {
+		application.DisableStartupActions()
+	}
 
 	program := tea.NewProgram(
 		application,
@@ -47,13 +55,31 @@ func main() {
 		tea.WithMouseCellMotion(), // Поддержка мыши
 	)
 
+	// Управляющий сокет: внешние инструменты вызывают команды через program.Send
+	var controlServer *control.Server
+	if cfg.Control.Enabled {
+		socketPath := cfg.Control.SocketPath
+		if socketPath == "" {
+			socketPath = control.DefaultSocketPath()
+		}
+		controlServer, err = control.Listen(socketPath, application.ControlHandler(program.Send))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Control socket disabled: %v\n", err)
+		}
+	}
+
 	// Запускаем в отдельной горутине для обработки контекста
 	go func() {
 		<-ctx.Done()
 		program.Quit()
 	}()
 
-	if _, err := program.Run(); err != nil {
+	_, err = program.Run()
+	logging.Default().Close()
+	if controlServer != nil {
+		controlServer.Close()
+	}
+	if err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
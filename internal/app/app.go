@@ -4,13 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"surge-tui/internal/config"
 	core "surge-tui/internal/core/surge"
+	"surge-tui/internal/logging"
 	"surge-tui/internal/platform"
 	"surge-tui/internal/ui/components"
 	"surge-tui/internal/ui/screens"
@@ -23,18 +22,22 @@ type ScreenType int
 const (
 	ProjectScreen ScreenType = iota
 	EditorScreen
+	DiagnosticsScreen
 	BuildScreen
 	FixModeScreen
 	CommandPaletteScreen
 	SettingsScreen
 	HelpScreen
 	LogsScreen
+	ProjectPickerScreen
 )
 
 // App представляет главное приложение
 type App struct {
 	config        *config.Config
 	currentScreen ScreenType
+	paletteOrigin ScreenType       // экран, с которого открыта палитра команд
+	currentIssue  screens.IssueRef // проблема, выбранная на экране диагностики или в Fix Mode
 	screens       map[ScreenType]screens.Screen
 	router        *ScreenRouter
 	eventBus      *EventBus
@@ -45,14 +48,49 @@ type App struct {
 	projectPath    string
 	lastOpenedFile string
 	unsavedFiles   map[string]bool
-	lastError      error
+	diagnostics    map[string][]screens.EditorDiagnostic
+	diagErrors     int // счётчики последних diagnostics для статус-бара
+	diagWarnings   int
+	projectOps     *screens.ProjectOps // операция, меняющая файлы проекта (общая для экранов)
 
 	// Surge CLI
-	surgeClient    *core.Client
-	surgeAvailable bool
-	surgeVersion   string
-
-	quitDialog *components.ConfirmDialog
+	surgeClient     core.SurgeRunner
+	surgeAvailable  bool
+	surgeVersion    string
+	surgeChecking   bool          // идёт проверка; в статус-баре «checking…»
+	surgeChecked    bool          // первая проверка завершилась
+	surgeErr        error         // причина последней неудачной проверки
+	surgeAnnounce   bool          // сообщить результат текущей проверки, даже если он не изменился
+	surgeCheckSeq   int           // результаты старых проверок (до смены пути) отбрасываются
+	surgeRetryAt    time.Time     // раньше этого ленивая перепроверка не запускается
+	surgeRetryDelay time.Duration // растёт после каждой неудачной проверки
+
+	// Действия запуска выполняются один раз после первой загрузки проекта,
+	// сессия — один раз для каждого открытого проекта
+	startupDone     bool
+	sessionRestored bool
+
+	// Пофайловая диагностика: номер последнего запроса и отмена текущего запуска
+	fileDiagSeq    int
+	fileDiagCancel context.CancelFunc
+	// Номер последнего набора диагностик: экранам раздаётся только он
+	diagApplySeq int
+
+	// Выход при несохранённых вкладках: сохранить всё, выйти без сохранения, отмена
+	quitDialog *components.ChoiceDialog
+	// Подтверждение смены проекта при несохранённых вкладках
+	switchDialog *components.ConfirmDialog
+	// Предупреждение о привязках, которые терминал не передаст (при запуске)
+	keyWarnDialog *components.ChoiceDialog
+	keyDebug      *components.KeyDebugOverlay
+	helpOverlay   *components.HelpOverlay
+	// Уведомления над статус-баром: ошибки фоновых команд, surge, сохранение
+	toasts *components.Toasts
+	// Отчёт о проверке окружения: проверки без surge делаются в Init,
+	// результат surge дописывается после первой его проверки
+	issuePanel  *components.IssuePanel
+	health      []healthIssue
+	healthShown []healthIssue // проблемы в панели, по индексам её строк
 }
 
 type projectInitCommander interface {
@@ -60,27 +98,67 @@ type projectInitCommander interface {
 	InitProjectInSelectedDir() tea.Cmd
 }
 
+type unsavedReporter interface {
+	UnsavedTabs() []string
+}
+
 type escHandler interface {
 	HandleGlobalEsc() (bool, tea.Cmd)
 }
 
-// New создает новое приложение
+// keyClaimer — экран, которому отдельные клавиши сейчас нужны самому, раньше
+// глобальных привязок (Tab в редакторе — отступ, а не смена экрана).
+type keyClaimer interface {
+	screens.Screen
+	ClaimsKey(key string) bool
+}
+
+// exitConfirmer — экран, который не отпускает с несохранёнными правками и
+// спрашивает, что с ними делать; resume повторяет переход после ответа.
+type exitConfirmer interface {
+	ConfirmExit(resume tea.Cmd) tea.Cmd
+}
+
+// keyCapturer — экран, которому следующее нажатие нужно целиком, мимо
+// реестра команд (запись новой привязки в настройках).
+type keyCapturer interface {
+	screens.Screen
+	CapturingKey() bool
+}
+
+// New создает новое приложение с клиентом surge из конфига
 func New(cfg *config.Config, projectPath string) *App {
+	client := core.NewClient(cfg.SurgeBinary)
+	client.SetTimeout(cfg.Surge.Timeout())
+	client.SetStallThreshold(cfg.Surge.StallThreshold())
+	return NewWithRunner(cfg, projectPath, client)
+}
+
+// NewWithRunner создает приложение с заданной реализацией surge
+// (например, testsupport.FakeRunner вместо реального бинарника).
+func NewWithRunner(cfg *config.Config, projectPath string, runner core.SurgeRunner) *App {
 	app := &App{
 		config:         cfg,
 		projectPath:    projectPath,
+		surgeClient:    runner,
 		lastOpenedFile: "",
 		screens:        make(map[ScreenType]screens.Screen),
 		eventBus:       NewEventBus(),
-		theme:          styles.NewTheme(cfg.Theme),
+		theme:          themeFromConfig(cfg),
 		unsavedFiles:   make(map[string]bool),
 		commands:       NewCommandRegistry(),
-		quitDialog:     components.NewConfirmDialog("Quit surge-tui", "Exit the application? Unsaved changes may be lost."),
+		quitDialog:     components.NewChoiceDialog("Quit surge-tui", "", quitOptions...),
+		switchDialog:   components.NewConfirmDialog("Open Project", ""),
+		keyDebug:       components.NewKeyDebugOverlay(16),
+		helpOverlay:    components.NewHelpOverlay(),
+		issuePanel:     components.NewIssuePanel(),
+		toasts:         components.NewToasts(3),
+		projectOps:     screens.NewProjectOps(),
 	}
 
-	if app.quitDialog != nil {
-		app.quitDialog.ConfirmText = "Quit"
-		app.quitDialog.CancelText = "Cancel"
+	if app.switchDialog != nil {
+		app.switchDialog.ConfirmText = "Discard"
+		app.switchDialog.CancelText = "Cancel"
 	}
 
 	// Путь к проекту: CLI → конфиг → текущая директория
@@ -92,15 +170,19 @@ func New(cfg *config.Config, projectPath string) *App {
 		}
 	}
 
-	// Инициализируем клиента surge с путём из конфига
-	app.surgeClient = core.NewClient(cfg.SurgeBinary)
-
 	// Инициализируем роутер
 	app.router = NewScreenRouter(app)
 
 	// Регистрируем базовые глобальные команды из конфига
 	app.registerBaseCommands()
 
+	// Диалоги и оверлеи берут цвета из темы
+	components.UsePalette(app.theme.Palette())
+
+	// Журнал приложения пишет в logging.file_path
+	logging.Default().Configure(loggingOptions(cfg))
+	logging.Infof("surge-tui started in %s", app.projectPath)
+
 	// Создаем экраны (ленивая инициализация)
 	app.initScreens()
 
@@ -112,10 +194,11 @@ func (a *App) Init() tea.Cmd {
 	// Создаем первый экран
 	a.currentScreen = ProjectScreen
 	a.screens[ProjectScreen] = a.createScreen(ProjectScreen)
+	a.health = a.collectHealth()
 
 	// Инициализируем экран
 	if screen := a.getCurrentScreen(); screen != nil {
-		return tea.Batch(screen.Init(), a.checkSurgeAvailability())
+		return tea.Batch(screen.Init(), a.startSurgeCheck(false), a.scheduleSurgePoll(), a.showKeybindingWarnings(), a.rememberProject(a.projectPath))
 	}
 
 	return nil
@@ -123,9 +206,31 @@ func (a *App) Init() tea.Cmd {
 
 // Update обрабатывает сообщения (Bubble Tea)
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Режим отладки клавиш перехватывает весь ввод до закрытия
+	if a.keyDebug != nil {
+		if handled, cmd := a.keyDebug.Update(msg); handled {
+			return a, cmd
+		}
+	}
+	if a.helpOverlay != nil && a.helpOverlay.Update(msg) {
+		return a, nil
+	}
+	// Панель проблем уступает ввод диалогам, открытым поверх неё
+	if a.issuePanel != nil && !a.dialogVisible() {
+		if handled, chosen := a.issuePanel.Update(msg); handled {
+			return a, a.fixHealthIssue(chosen)
+		}
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return a.handleGlobalKeys(msg)
+	case tea.MouseMsg:
+		if !a.dialogVisible() {
+			if handled, cmd := a.handleStatusBarMouse(msg); handled {
+				return a, cmd
+			}
+		}
 	case tea.WindowSizeMsg:
 		return a.handleWindowResize(msg)
 	case ScreenSwitchMsg:
@@ -133,13 +238,18 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ErrorMsg:
 		return a.handleError(msg)
 	case SurgeAvailabilityMsg:
-		a.surgeAvailable = msg.Available
-		a.surgeVersion = msg.Version
-		if msg.Err != nil {
-			a.lastError = msg.Err
-		}
+		return a, a.handleSurgeAvailability(msg)
+	case surgePollMsg:
+		return a, a.handleSurgePoll()
+	case screens.NotifyMsg:
+		return a, a.notify(msg.Level, msg.Text)
+	case components.ToastExpiredMsg:
+		a.toasts.Dismiss(msg.ID)
 		return a, nil
 	case screens.CommandExecuteMsg:
+		if msg.Run != nil {
+			return a, msg.Run()
+		}
 		var cmds []tea.Cmd
 		if back := a.router.GoBack(); back != nil {
 			cmds = append(cmds, back)
@@ -147,60 +257,79 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if run := a.commands.Run(msg.ID, a); run != nil {
 			cmds = append(cmds, run)
 		}
-		return a, tea.Batch(cmds...)
+		// команда выполняется после возврата с палитры: Go Back считает
+		// историю уже без неё
+		return a, tea.Sequence(cmds...)
 	case screens.CommandPaletteClosedMsg:
 		return a, a.router.GoBack()
-	case screens.ConfigChangedMsg:
-		if msg.Config != nil {
-			*a.config = *msg.Config
-			a.theme = styles.NewTheme(a.config.Theme)
-			a.rebuildCommandBindings()
+	case goBackMsg:
+		return a, a.router.GoBack()
+	case screens.IssuesResolvedMsg:
+		if ds, ok := a.screens[DiagnosticsScreen].(*screens.DiagnosticsScreen); ok && ds != nil {
+			ds.MarkResolved(msg.Issues)
 		}
 		return a, nil
+	case screens.ConfigChangedMsg:
+		return a, a.handleConfigChanged(msg)
+	case screens.ThemeChangedMsg:
+		a.applyTheme(msg.Theme)
+		return a, nil
+	case screens.BuildEvent:
+		return a, a.deliverTo(BuildScreen, msg)
+	case screens.FixModeEvent:
+		return a, a.deliverTo(FixModeScreen, msg)
+	case screens.FormatDoneMsg:
+		return a, a.deliverTo(ProjectScreen, msg)
+	case screens.ExternalEditorClosedMsg:
+		return a, a.deliverTo(ProjectScreen, msg)
+	case controlRequestMsg:
+		return a, a.handleControl(msg)
+	case controlFixAppliedMsg:
+		return a, a.handleControlFixApplied(msg)
+	case screens.DiagnosticsPublishedMsg:
+		return a, a.handleDiagnosticsPublished(msg)
+	case screens.FileSavedMsg:
+		return a, a.scheduleDiagOnSave(msg.Path)
+	case screens.RunFileDiagnosticsMsg:
+		return a, a.scheduleFileDiagnostics(msg.Path)
+	case fileDiagTickMsg:
+		return a, a.runFileDiagnostics(msg)
+	case diagApplyTickMsg:
+		a.publishDiagnostics(msg)
+		return a, nil
+	case screens.ProjectLoadedMsg:
+		// вкладки сессии открываются до действий запуска: open: из конфига — поверх них
+		return a, tea.Sequence(a.restoreSession(), a.runStartupActions())
+	case screens.CopyPathMsg:
+		return a, screens.CopyPath(msg.Path, a.projectPath, msg.Relative)
+	case screens.PathCopiedMsg:
+		a.notifyCurrent(screens.PathCopiedStatus(msg))
+		return a, nil
 	case screens.OpenLocationMsg:
 		return a, a.handleOpenLocation(msg)
 	case screens.OpenFixModeMsg:
 		return a, a.handleOpenFixMode(msg)
-	case ProjectInitializedMsg:
-		if msg.Err != nil {
-			a.lastError = msg.Err
-		} else {
-			if msg.Path != "" {
-				a.projectPath = msg.Path
-			}
-			var cmds []tea.Cmd
-			newScreen := a.createScreen(ProjectScreen)
-			// передаем последнюю известную геометрию
-			if a.theme.Width() > 0 && a.theme.Height() > 0 {
-				if updated, cmd := newScreen.Update(tea.WindowSizeMsg{Width: a.theme.Width(), Height: a.theme.Height()}); updated != nil {
-					newScreen = updated
-					if cmd != nil {
-						cmds = append(cmds, cmd)
-					}
-				}
-			}
-			if initCmd := newScreen.Init(); initCmd != nil {
-				cmds = append(cmds, initCmd)
-			}
-			a.screens[ProjectScreen] = newScreen
-			if a.currentScreen == ProjectScreen {
-				if enter := newScreen.OnEnter(); enter != nil {
-					cmds = append(cmds, enter)
-				}
-			}
-			if len(cmds) > 0 {
-				return a, tea.Batch(cmds...)
-			}
-			if fixScreen, ok := a.screens[FixModeScreen].(*screens.FixModeScreen); ok && fixScreen != nil {
-				fixScreen.SetProjectPath(a.projectPath)
-			}
-		}
-		return a, nil
-	case quitConfirmedMsg:
+	case screens.DiagnoseDirMsg:
+		return a, a.handleDiagnoseDir(msg)
+	case screens.FixDirMsg:
+		return a, a.handleFixDir(msg)
+	case screens.ProjectInitializedMsg:
+		return a, a.handleProjectInitialized(msg)
+	case screens.ProjectChosenMsg:
+		return a, a.handleProjectChosen(msg)
+	case screens.ProjectPickerClosedMsg:
+		return a, a.router.GoBack()
+	case projectSwitchConfirmedMsg:
 		if msg.confirmed {
-			return a, tea.Quit
+			return a, a.switchProject(msg.path)
 		}
 		return a, nil
+	case quitChoiceMsg:
+		return a, a.handleQuitChoice(msg)
+	case userCommandDoneMsg:
+		return a, a.handleUserCommandDone(msg)
+	case keyWarningsChoiceMsg:
+		return a, a.handleKeyWarningsChoice(msg)
 	}
 
 	// Передаем сообщение текущему экрану
@@ -221,7 +350,7 @@ func (a *App) View() string {
 		return "Loading..."
 	}
 
-	view := currentScreen.View()
+	view := a.overlayToasts(currentScreen.View())
 
 	// Добавляем статус-бар
 	statusBar := a.renderStatusBar()
@@ -229,6 +358,18 @@ func (a *App) View() string {
 	content := fmt.Sprintf("%s\n%s", view, statusBar)
 	if a.quitDialog != nil && a.quitDialog.Visible {
 		content = fmt.Sprintf("%s\n%s", content, a.quitDialog.View())
+	} else if a.switchDialog != nil && a.switchDialog.Visible {
+		content = fmt.Sprintf("%s\n%s", content, a.switchDialog.View())
+	} else if a.keyWarnDialog != nil && a.keyWarnDialog.Visible {
+		content = fmt.Sprintf("%s\n%s", content, a.keyWarnDialog.View())
+	} else if a.issuePanel != nil && a.issuePanel.Visible {
+		content = fmt.Sprintf("%s\n%s", content, a.issuePanel.View())
+	}
+	if a.keyDebug != nil && a.keyDebug.Visible {
+		content = fmt.Sprintf("%s\n%s", content, a.keyDebug.View())
+	}
+	if a.helpOverlay != nil && a.helpOverlay.Visible {
+		content = fmt.Sprintf("%s\n%s", content, a.helpOverlay.View())
 	}
 
 	return content
@@ -245,68 +386,75 @@ func (a *App) initScreens() {
 	// Пока просто заглушки
 }
 
-// createScreen создает экран по типу
+// createScreen создает экран по типу и применяет к нему текущую тему
 func (a *App) createScreen(screenType ScreenType) screens.Screen {
+	screen := a.newScreen(screenType)
+	a.themeScreen(screen)
+	return screen
+}
+
+func (a *App) newScreen(screenType ScreenType) screens.Screen {
 	switch screenType {
 	case ProjectScreen:
-		return screens.NewProjectScreenReal(a.projectPath)
+		ps := screens.NewProjectScreenReal(a.projectPath)
+		ps.SetDiagnostics(a.diagnostics)
+		ps.SetSurgeClient(a.surgeClient)
+		ps.SetProjectOps(a.projectOps)
+		ps.SetSurgeAvailable(a.surgeAvailable)
+		ps.SetKeyHints(a.config.UI.PanelHints, a.commandKeys(ProjectScreen))
+		ps.SetEditorConfig(a.config.Editor)
+		ps.SetTestsConfig(a.config.Tests)
+		ps.SetTreeIgnore(a.config.UI.TreeIgnore)
+		ps.SetProblemsHeight(a.config.UI.ProblemsHeight)
+		ps.SetSessionEnabled(a.config.Startup.RestoreSession)
+		ps.SetFollowActiveTab(a.config.UI.FollowActiveTab)
+		return ps
 	case EditorScreen:
-		return screens.NewEditorScreen()
+		es := screens.NewEditorScreen()
+		es.SetDiagnostics(a.diagnostics)
+		return es
+	case DiagnosticsScreen:
+		ds := screens.NewDiagnosticsScreen(a.projectPath, a.surgeClient)
+		ds.SetProjectOps(a.projectOps)
+		ds.SetWatchInterval(a.watchInterval())
+		ds.SetCacheMaxAge(a.config.Surge.CacheAge())
+		return ds
 	case BuildScreen:
-		return screens.NewDiagnosticsScreen(a.projectPath, a.surgeClient)
+		return screens.NewBuildScreen(a.projectPath, a.surgeClient)
 	case FixModeScreen:
-		return screens.NewFixModeScreen(a.projectPath, a.surgeClient)
+		fs := screens.NewFixModeScreen(a.projectPath, a.surgeClient)
+		fs.SetDiffContext(a.config.FixMode.DiffContext)
+		fs.SetCacheMaxAge(a.config.Surge.CacheAge())
+		fs.SetBuffers(projectBuffers{app: a})
+		fs.SetProjectOps(a.projectOps)
+		return fs
 	case CommandPaletteScreen:
 		return screens.NewCommandPaletteScreen(a.commandFetcher())
 	case SettingsScreen:
-		return screens.NewSettingsScreen(a.config)
+		ss := screens.NewSettingsScreen(a.config)
+		ss.SetCommandCatalog(a.bindingCatalog())
+		return ss
 	case HelpScreen:
 		return screens.NewPlaceholderScreen("Help")
 	case LogsScreen:
-		return screens.NewPlaceholderScreen("Logs")
+		return screens.NewLogsScreen(logging.Default())
+	case ProjectPickerScreen:
+		return screens.NewProjectPickerScreen(a.projectPath)
 	default:
 		return screens.NewPlaceholderScreen("Unknown")
 	}
 }
 
-// renderStatusBar отрисовывает статус-бар
-func (a *App) renderStatusBar() string {
-	proj := a.projectLabel()
-	surge := "Surge: unknown"
-	if a.surgeAvailable {
-		if a.surgeVersion != "" {
-			surge = "Surge: " + a.surgeVersion
-		} else {
-			surge = "Surge: available"
-		}
-	} else {
-		surge = "Surge: not found"
-	}
-	keyLabel := func(id, fallback string) string {
-		if a.config != nil && a.config.Keybindings != nil {
-			if key := strings.TrimSpace(a.config.Keybindings[id]); key != "" {
-				return prettifyKey(key)
-			}
-		}
-		return prettifyKey(fallback)
-	}
-	help := fmt.Sprintf("%s Quit • %s Commands • %s Switch Screens",
-		keyLabel("quit", "ctrl+q"),
-		keyLabel("command_palette", "ctrl+p"),
-		keyLabel("switch_screen", "tab"),
-	)
-	return a.theme.StatusBar(fmt.Sprintf("%s | %s | %s", proj, surge, help))
-}
-
 // registerBaseCommands wires global commands from config keybindings.
 func (a *App) registerBaseCommands() {
 	kb := a.config.Keybindings
-	// Helper to register a global command
-	reg := func(id, title, key string, run func(*App) tea.Cmd, enabled func(*App) bool) {
+	// Helper to register a global command bound to keybindings.<binding>
+	reg := func(id, title, binding string, run func(*App) tea.Cmd, enabled func(*App) bool) {
 		cmd := &Command{
 			ID:      id,
 			Title:   title,
-			Key:     key,
+			Key:     kb[binding],
+			Binding: binding,
 			Screen:  nil,
 			Enabled: enabled,
 			Run:     run,
@@ -314,26 +462,94 @@ func (a *App) registerBaseCommands() {
 		a.commands.Register(cmd)
 	}
 
-	reg("quit", "Quit", kb["quit"], func(a *App) tea.Cmd { return a.requestQuit() }, nil)
-	reg("open_settings", "Open Settings", kb["settings"], func(a *App) tea.Cmd { return a.router.SwitchTo(SettingsScreen) }, nil)
-	reg("open_fix_mode", "Fix Mode", kb["fix_mode"], func(a *App) tea.Cmd { return a.router.SwitchTo(FixModeScreen) }, nil)
-	reg("open_workspace", "Workspace", kb["workspace"], func(a *App) tea.Cmd { return a.router.SwitchTo(ProjectScreen) }, nil)
-	reg("open_diagnostics", "Diagnostics", kb["build"], func(a *App) tea.Cmd { return a.router.SwitchTo(BuildScreen) }, nil)
-	reg("command_palette", "Command Palette", kb["command_palette"], func(a *App) tea.Cmd { return a.router.SwitchTo(CommandPaletteScreen) }, nil)
-	reg("switch_screen", "Next Screen", kb["switch_screen"], func(a *App) tea.Cmd { return a.router.SwitchToNext() }, nil)
-	reg("switch_screen_back", "Prev Screen", kb["switch_screen_back"], func(a *App) tea.Cmd { return a.router.SwitchToPrevious() }, nil)
-	reg("init_project", "Init Project", kb["init_project"], func(a *App) tea.Cmd { return a.initProject() }, func(a *App) bool {
-		if !a.surgeAvailable || a.surgeClient == nil || a.currentScreen != ProjectScreen {
+	reg("quit", "Quit", "quit", func(a *App) tea.Cmd { return a.requestQuit() }, nil)
+	reg("open_settings", "Open Settings", "settings", func(a *App) tea.Cmd { return a.router.SwitchTo(SettingsScreen) }, nil)
+	reg("open_fix_mode", "Fix Mode", "fix_mode", func(a *App) tea.Cmd { return a.router.SwitchTo(FixModeScreen) }, nil)
+	reg("open_workspace", "Workspace", "workspace", func(a *App) tea.Cmd { return a.router.SwitchTo(ProjectScreen) }, nil)
+	reg("open_diagnostics", "Diagnostics", "diagnostics", func(a *App) tea.Cmd { return a.router.SwitchTo(DiagnosticsScreen) }, nil)
+	reg("open_logs", "Logs", "logs", func(a *App) tea.Cmd { return a.router.SwitchTo(LogsScreen) }, nil)
+	reg("run_build", "Build Project", "build", func(a *App) tea.Cmd { return a.runBuild() }, nil)
+	reg("command_palette", "Command Palette", "command_palette", func(a *App) tea.Cmd { return a.openCommandPalette() }, nil)
+	reg("switch_screen", "Next Screen", "switch_screen", func(a *App) tea.Cmd { return a.router.SwitchToNext() }, nil)
+	reg("switch_screen_back", "Prev Screen", "switch_screen_back", func(a *App) tea.Cmd { return a.router.SwitchToPrevious() }, nil)
+	reg("go_back", "Go Back", "go_back", func(a *App) tea.Cmd { return goBack }, func(a *App) bool { return a.router.CanNavigateBack() })
+	reg("open_project", "Open Project…", "open_project", func(a *App) tea.Cmd { return a.openProjectPicker() }, nil)
+	reg("init_project", "Init Project", "init_project", func(a *App) tea.Cmd { return a.initProject() }, func(a *App) bool {
+		if !a.surgeAvailable || a.surgeClient == nil {
 			return false
 		}
-		screen := a.getCurrentScreen()
-		if commander, ok := screen.(projectInitCommander); ok {
-			return commander.CanInitProject()
-		}
-		return false
+		commander, ok := a.commandTarget().(projectInitCommander)
+		return ok && commander.CanInitProject()
+	})
+	reg("format_file", "Format File", "format_file", func(a *App) tea.Cmd { return a.formatFile() }, func(a *App) bool {
+		formatter, ok := a.commandTarget().(fileFormatter)
+		return ok && a.surgeAvailable && formatter.CanFormatFile()
+	})
+	reg("format_project", "Format Project", "format_project", func(a *App) tea.Cmd { return a.formatProject() }, func(a *App) bool {
+		return a.surgeAvailable && a.isSurgeProject()
+	})
+	reg("recheck_surge", "Recheck Surge", "recheck_surge", (*App).recheckSurge, nil)
+	reg("health_report", "Health Report", "health_report", (*App).showHealthReport, nil)
+	// без surge эти команды сначала перепроверяют его наличие
+	for _, id := range []string{"run_build", "init_project", "format_file", "format_project"} {
+		a.commands.Get(id).NeedsSurge = true
+	}
+	reg("quick_open", "Quick Open File", "quick_open", func(a *App) tea.Cmd { return a.openQuickOpen() }, nil)
+	reg("new_scratch", "New Scratch Buffer", "new_scratch", func(a *App) tea.Cmd { return a.openScratchBuffer() }, nil)
+	reg("revert_file", "Revert File", "revert_file", func(a *App) tea.Cmd { return a.revertFile() }, func(a *App) bool {
+		_, ok := a.commandTarget().(fileReverter)
+		return ok
+	})
+	reg("rollback_buffer", "Rollback Buffer to…", "rollback_buffer", (*App).rollbackBuffer, (*App).canRollbackBuffer)
+	reg("move_file", "Move File…", "move_file", func(a *App) tea.Cmd { return a.moveFile() }, func(a *App) bool {
+		mover, ok := a.commandTarget().(fileMover)
+		return ok && mover.CanMoveFile()
 	})
-	reg("help", "Help", kb["help"], func(a *App) tea.Cmd { return a.router.SwitchTo(HelpScreen) }, nil)
+	reg("copy_to_file", "Copy to New File…", "copy_to_file", func(a *App) tea.Cmd { return a.copyToFile() }, func(a *App) bool {
+		copier, ok := a.commandTarget().(fileCopier)
+		return ok && copier.CanCopyToFile()
+	})
+	reg("extract_to_file", "Extract Selection to New File…", "extract_to_file", func(a *App) tea.Cmd { return a.extractSelection() }, func(a *App) bool {
+		extractor, ok := a.commandTarget().(selectionExtractor)
+		return ok && extractor.CanExtractSelection()
+	})
+	reg("external_editor", "Open in External Editor", "external_editor", func(a *App) tea.Cmd { return a.openExternalEditor() }, func(a *App) bool {
+		launcher, ok := a.commandTarget().(externalEditorLauncher)
+		return ok && launcher.CanOpenExternalEditor()
+	})
+	reg("rename_pattern", "Rename with Pattern…", "rename_pattern", func(a *App) tea.Cmd { return a.renameWithPattern() }, func(a *App) bool {
+		renamer, ok := a.commandTarget().(patternRenamer)
+		return ok && renamer.CanRenameWithPattern()
+	})
+	reg("toggle_problems", "Toggle Problems Drawer", "toggle_problems", func(a *App) tea.Cmd { return a.toggleProblems() }, func(a *App) bool {
+		_, ok := a.commandTarget().(problemsToggler)
+		return ok
+	})
+	reg("next_change", "Next Unsaved Change", "next_change", func(a *App) tea.Cmd { return a.jumpToChange(1) }, (*App).canNavigateChanges)
+	reg("prev_change", "Previous Unsaved Change", "prev_change", func(a *App) tea.Cmd { return a.jumpToChange(-1) }, (*App).canNavigateChanges)
+	reg("next_symbol", "Next Function or Type", "next_symbol", func(a *App) tea.Cmd { return a.jumpToSymbol(1) }, (*App).canNavigateSymbols)
+	reg("prev_symbol", "Previous Function or Type", "prev_symbol", func(a *App) tea.Cmd { return a.jumpToSymbol(-1) }, (*App).canNavigateSymbols)
+	reg("sort_lines", "Sort Lines", "sort_lines", func(a *App) tea.Cmd { return a.sortLines(false) }, (*App).canTransformLines)
+	reg("sort_lines_unique", "Sort Lines (Unique)", "sort_lines_unique", func(a *App) tea.Cmd { return a.sortLines(true) }, (*App).canTransformLines)
+	reg("reverse_lines", "Reverse Lines", "reverse_lines", (*App).reverseLines, (*App).canTransformLines)
+	reg("toggle_comment", "Toggle Comment", "toggle_comment", (*App).toggleComment, (*App).canToggleComment)
+	reg("jump_to_bracket", "Jump to Matching Bracket", "jump_to_bracket", (*App).jumpToBracket, (*App).canJumpToBracket)
+	reg("toggle_test_file", "Toggle Test File", "toggle_test_file", (*App).toggleTestFile, (*App).canToggleTestFile)
+	reg("status_history", "Recent Messages", "status_history", func(a *App) tea.Cmd { return a.showStatusHistory() }, func(a *App) bool {
+		_, ok := a.commandTarget().(statusHistorian)
+		return ok
+	})
+	reg("revert_hunk", "Revert Change Under Cursor", "revert_hunk", func(a *App) tea.Cmd { return a.revertHunk() }, (*App).canNavigateChanges)
+	reg("copy_path", "Copy Path", "copy_path", func(a *App) tea.Cmd { return a.copySelectedPath(false) }, (*App).hasSelectedPath)
+	reg("copy_relative_path", "Copy Relative Path", "copy_relative_path", func(a *App) tea.Cmd { return a.copySelectedPath(true) }, (*App).hasSelectedPath)
+	reg("help", "Help", "help", func(a *App) tea.Cmd { return a.router.SwitchTo(HelpScreen) }, nil)
+	reg("debug_keys", "Debug Keys", "debug_keys", func(a *App) tea.Cmd {
+		a.keyDebug.Show()
+		return nil
+	}, nil)
 
+	a.registerScreenCommands()
+	a.registerUserCommands()
 }
 
 func (a *App) rebuildCommandBindings() {
@@ -363,63 +579,7 @@ func (a *App) commandFetcher() screens.CommandFetcher {
 				RawKey:  cmd.Key,
 			})
 		}
-		return entries
-	}
-}
-
-func (a *App) handleOpenLocation(msg screens.OpenLocationMsg) tea.Cmd {
-	var cmds []tea.Cmd
-
-	if msg.FilePath != "" {
-		if screen, ok := a.screens[ProjectScreen].(*screens.ProjectScreenReal); ok && screen != nil {
-			screen.OpenLocation(msg.FilePath, msg.Line, msg.Column)
-		}
-	}
-
-	cmds = append(cmds, a.router.SwitchTo(ProjectScreen))
-	return tea.Batch(cmds...)
-}
-
-func (a *App) handleOpenFixMode(msg screens.OpenFixModeMsg) tea.Cmd {
-	var cmds []tea.Cmd
-	screenIface := a.screens[FixModeScreen]
-	if screenIface == nil {
-		screenIface = a.createScreen(FixModeScreen)
-		a.screens[FixModeScreen] = screenIface
-		if init := screenIface.Init(); init != nil {
-			cmds = append(cmds, init)
-		}
-	}
-	if screen, ok := screenIface.(*screens.FixModeScreen); ok && screen != nil {
-		if a.projectPath != "" {
-			screen.SetProjectPath(a.projectPath)
-		}
-		screen.FocusFix(msg.FilePath, msg.FixID)
-	}
-	cmds = append(cmds, a.router.SwitchTo(FixModeScreen))
-	return tea.Batch(cmds...)
-}
-
-func (a *App) screenTitle(screen ScreenType) string {
-	switch screen {
-	case ProjectScreen:
-		return "Project"
-	case EditorScreen:
-		return "Editor"
-	case BuildScreen:
-		return "Build"
-	case FixModeScreen:
-		return "Fix Mode"
-	case CommandPaletteScreen:
-		return "Command Palette"
-	case SettingsScreen:
-		return "Settings"
-	case HelpScreen:
-		return "Help"
-	case LogsScreen:
-		return "Logs"
-	default:
-		return "Unknown"
+		return append(entries, a.paletteEntries()...)
 	}
 }
 
@@ -432,74 +592,10 @@ func prettifyKey(key string) string {
 // ScreenSwitchMsg сообщение о переключении экрана
 type ScreenSwitchMsg struct {
 	ScreenType ScreenType
+	back       bool // возврат по истории роутера
 }
 
 // ErrorMsg сообщение об ошибке
 type ErrorMsg struct {
 	Error error
 }
-
-// Вспомогательные сообщения
-type SurgeAvailabilityMsg struct {
-	Available bool
-	Version   string
-	Err       error
-}
-
-type ProjectInitializedMsg struct {
-	Path string
-	Err  error
-}
-
-type quitConfirmedMsg struct {
-	confirmed bool
-}
-
-// checkSurgeAvailability проверяет наличие surge и версию
-func (a *App) checkSurgeAvailability() tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-		if a.surgeClient == nil {
-			return SurgeAvailabilityMsg{Available: false}
-		}
-		err := a.surgeClient.CheckAvailable(ctx)
-		if err != nil {
-			return SurgeAvailabilityMsg{Available: false, Err: err}
-		}
-		ver, _ := a.surgeClient.GetVersion(ctx)
-		return SurgeAvailabilityMsg{Available: true, Version: ver}
-	}
-}
-
-// initProject вызывает `surge init` для текущего выбраного пути на экране проекта
-func (a *App) initProject() tea.Cmd {
-	if a.surgeClient == nil || !a.surgeAvailable {
-		return nil
-	}
-	if screen := a.getCurrentScreen(); screen != nil {
-		if commander, ok := screen.(projectInitCommander); ok {
-			return commander.InitProjectInSelectedDir()
-		}
-	}
-	return nil
-}
-
-// projectLabel формирует подпись проекта для статус-бара
-func (a *App) projectLabel() string {
-	if a.projectPath == "" {
-		return "Project: (none)"
-	}
-	return "Project: " + filepath.Base(a.projectPath)
-}
-
-// isSurgeProject проверяет наличие surge.toml в корне
-func (a *App) isSurgeProject() bool {
-	if a.projectPath == "" {
-		return false
-	}
-	if st, err := os.Stat(filepath.Join(a.projectPath, "surge.toml")); err == nil && !st.IsDir() {
-		return true
-	}
-	return false
-}
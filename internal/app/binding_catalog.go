@@ -0,0 +1,33 @@
+package app
+
+import (
+	"surge-tui/internal/ui/screens"
+)
+
+// bindingCatalog перечисляет команды реестра для редактора привязок в
+// настройках: ID пункта — имя привязки в keybindings (у пользовательских
+// команд — user:<id>), Context — группа: Global или название экрана.
+func (a *App) bindingCatalog() screens.CommandFetcher {
+	return func() []screens.CommandEntry {
+		commands := a.commands.All()
+		entries := make([]screens.CommandEntry, 0, len(commands))
+		for _, cmd := range commands {
+			id, group := cmd.Binding, "Global"
+			if id == "" {
+				id, group = cmd.ID, screens.UserCommandsGroup
+			}
+			if cmd.Screen != nil {
+				group = a.screenTitle(*cmd.Screen)
+			}
+			entries = append(entries, screens.CommandEntry{
+				ID:      id,
+				Title:   cmd.Title,
+				Key:     prettifyKey(cmd.Key),
+				Context: group,
+				Enabled: true,
+				RawKey:  cmd.Key,
+			})
+		}
+		return entries
+	}
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/ui/screens"
+)
+
+// projectBuffers отдаёт Fix Mode вкладки экрана проекта. Экран ищется при
+// каждом вызове: при смене проекта он создаётся заново.
+type projectBuffers struct {
+	app *App
+}
+
+func (b projectBuffers) project() *screens.ProjectScreenReal {
+	ps, _ := b.app.screens[ProjectScreen].(*screens.ProjectScreenReal)
+	return ps
+}
+
+func (b projectBuffers) UnsavedFiles() []string {
+	if ps := b.project(); ps != nil {
+		return ps.UnsavedFiles()
+	}
+	return nil
+}
+
+func (b projectBuffers) SaveFile(path string) (tea.Cmd, error) {
+	if ps := b.project(); ps != nil {
+		return ps.SaveFile(path)
+	}
+	return nil, nil
+}
+
+func (b projectBuffers) ReloadFile(path string) error {
+	if ps := b.project(); ps != nil {
+		return ps.ReloadFile(path)
+	}
+	return nil
+}
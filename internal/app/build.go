@@ -0,0 +1,26 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/ui/screens"
+)
+
+// runBuild открывает экран сборки и запускает `surge build`.
+func (a *App) runBuild() tea.Cmd {
+	return tea.Sequence(
+		a.router.SwitchTo(BuildScreen),
+		func() tea.Msg { return screens.StartBuildMsg{} },
+	)
+}
+
+// deliverTo передаёт сообщение экрану, даже если активен другой: так вывод сборки
+// продолжает читаться, пока пользователь работает в редакторе.
+func (a *App) deliverTo(screenType ScreenType, msg tea.Msg) tea.Cmd {
+	screen := a.screens[screenType]
+	if screen == nil {
+		return nil
+	}
+	updated, cmd := screen.Update(msg)
+	a.screens[screenType] = updated
+	return cmd
+}
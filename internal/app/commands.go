@@ -12,9 +12,12 @@ type Command struct {
 	ID      string
 	Title   string
 	Key     string
+	Binding string      // name in config keybindings; empty for user commands
 	Screen  *ScreenType // nil → global
 	Enabled func(*App) bool
 	Run     func(*App) tea.Cmd
+	// NeedsSurge: invoking the command while surge is missing re-checks it first
+	NeedsSurge bool
 }
 
 // CommandRegistry stores commands and resolves them by key and screen.
@@ -46,6 +49,15 @@ func (r *CommandRegistry) Register(cmd *Command) {
 
 // Resolve returns the first matching command for key and screen.
 func (r *CommandRegistry) Resolve(key string, screen ScreenType) *Command {
+	return r.resolve(key, &screen)
+}
+
+// ResolveGlobal returns the global command bound to key, ignoring screen commands.
+func (r *CommandRegistry) ResolveGlobal(key string) *Command {
+	return r.resolve(key, nil)
+}
+
+func (r *CommandRegistry) resolve(key string, screen *ScreenType) *Command {
 	canonical := platform.CanonicalKeyForLookup(key)
 	if canonical == "" {
 		canonical = key
@@ -61,7 +73,7 @@ func (r *CommandRegistry) Resolve(key string, screen ScreenType) *Command {
 			global = c
 			continue
 		}
-		if *c.Screen == screen {
+		if screen != nil && *c.Screen == *screen {
 			return c
 		}
 	}
@@ -94,11 +106,19 @@ func (r *CommandRegistry) Run(id string, app *App) tea.Cmd {
 	if cmd == nil {
 		return nil
 	}
-	if cmd.Enabled != nil && !cmd.Enabled(app) {
-		return nil
+	return cmd.execute(app)
+}
+
+// execute runs the command if it is enabled. A surge-dependent command invoked
+// while surge is missing also schedules a rate-limited availability re-check,
+// so a fixed installation is picked up without a restart.
+func (c *Command) execute(app *App) tea.Cmd {
+	var recheck tea.Cmd
+	if c.NeedsSurge && !app.surgeAvailable {
+		recheck = app.lazySurgeRecheck()
 	}
-	if cmd.Run == nil {
-		return nil
+	if c.Run == nil || (c.Enabled != nil && !c.Enabled(app)) {
+		return recheck
 	}
-	return cmd.Run(app)
+	return tea.Batch(recheck, c.Run(app))
 }
@@ -0,0 +1,53 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/logging"
+	"surge-tui/internal/ui/screens"
+)
+
+// handleConfigChanged применяет сохранённые настройки: клиент surge,
+// привязки, журнал и настройки экранов меняются сразу, тема — следующим
+// сообщением ThemeChangedMsg.
+func (a *App) handleConfigChanged(msg screens.ConfigChangedMsg) tea.Cmd {
+	if msg.Config == nil {
+		return nil
+	}
+	binaryChanged := msg.Config.SurgeBinary != a.config.SurgeBinary
+	followChanged := msg.Config.UI.FollowActiveTab != a.config.UI.FollowActiveTab
+	*a.config = *msg.Config
+	if setter, ok := a.surgeClient.(surgeTimeoutSetter); ok {
+		setter.SetTimeout(a.config.Surge.Timeout())
+	}
+	if setter, ok := a.surgeClient.(surgeStallSetter); ok {
+		setter.SetStallThreshold(a.config.Surge.StallThreshold())
+	}
+	a.rebuildCommandBindings()
+	a.applyKeyHints()
+	logging.Default().Configure(loggingOptions(a.config))
+	if fs, ok := a.screens[FixModeScreen].(*screens.FixModeScreen); ok {
+		fs.SetDiffContext(a.config.FixMode.DiffContext)
+		fs.SetCacheMaxAge(a.config.Surge.CacheAge())
+	}
+	if ds, ok := a.screens[DiagnosticsScreen].(*screens.DiagnosticsScreen); ok {
+		ds.SetWatchInterval(a.watchInterval())
+		ds.SetCacheMaxAge(a.config.Surge.CacheAge())
+	}
+	if ps, ok := a.screens[ProjectScreen].(*screens.ProjectScreenReal); ok {
+		ps.SetEditorConfig(a.config.Editor)
+		ps.SetTestsConfig(a.config.Tests)
+		ps.SetTreeIgnore(a.config.UI.TreeIgnore)
+		ps.SetProblemsHeight(a.config.UI.ProblemsHeight)
+		ps.SetSessionEnabled(a.config.Startup.RestoreSession)
+		if followChanged {
+			// переключение из палитры живёт до смены самого ключа
+			ps.SetFollowActiveTab(a.config.UI.FollowActiveTab)
+		}
+	}
+	theme := themeFromConfig(a.config)
+	cmds := []tea.Cmd{func() tea.Msg { return screens.ThemeChangedMsg{Theme: theme} }}
+	if binaryChanged {
+		cmds = append(cmds, a.setSurgeBinary(a.config.SurgeBinary))
+	}
+	return tea.Batch(cmds...)
+}
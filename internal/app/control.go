@@ -0,0 +1,289 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/control"
+	"surge-tui/internal/syntax"
+	"surge-tui/internal/ui/screens"
+)
+
+const controlReplyTimeout = 60 * time.Second // сколько вызов ждёт ответа приложения
+
+// controlRequestMsg — вызов управляющего сокета; выполняется в Update,
+// ответ уходит в reply.
+type controlRequestMsg struct {
+	method string
+	params json.RawMessage
+	reply  chan controlReply
+}
+
+type controlReply struct {
+	result any
+	err    error
+}
+
+// controlFixAppliedMsg — surge применил фикс по вызову apply-fix-by-id.
+type controlFixAppliedMsg struct {
+	req   controlRequestMsg
+	path  string
+	fixID string
+	token int // токен в ProjectOps, освобождается при обработке
+	err   error
+}
+
+type unsavedFileReporter interface {
+	UnsavedFiles() []string
+}
+
+type fileReloader interface {
+	ReloadFile(path string) error
+}
+
+// controlStatus — ответ query-status.
+type controlStatus struct {
+	Project        string            `json:"project"`
+	Screen         string            `json:"screen"`
+	SurgeAvailable bool              `json:"surge_available"`
+	SurgeVersion   string            `json:"surge_version,omitempty"`
+	DirtyFiles     []string          `json:"dirty_files"`
+	Diagnostics    controlDiagCounts `json:"diagnostics"`
+}
+
+// controlDiagCounts — итоги последнего запуска диагностики.
+type controlDiagCounts struct {
+	Files    int `json:"files"`
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Infos    int `json:"infos"`
+}
+
+func (m controlRequestMsg) respond(result any, err error) {
+	select {
+	case m.reply <- controlReply{result: result, err: err}:
+	default: // ответ уже отправлен
+	}
+}
+
+// ControlHandler возвращает обработчик управляющего сокета. send — program.Send:
+// вызовы выполняются в цикле Update наравне с вводом пользователя.
+func (a *App) ControlHandler(send func(tea.Msg)) control.Handler {
+	return func(ctx context.Context, method string, params json.RawMessage) (any, error) {
+		reply := make(chan controlReply, 1)
+		go send(controlRequestMsg{method: method, params: params, reply: reply})
+
+		timer := time.NewTimer(controlReplyTimeout)
+		defer timer.Stop()
+		select {
+		case r := <-reply:
+			return r.result, r.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return nil, errors.New("timed out waiting for surge-tui")
+		}
+	}
+}
+
+// handleControl переводит вызов в обычные сообщения приложения.
+func (a *App) handleControl(msg controlRequestMsg) tea.Cmd {
+	switch msg.method {
+	case "open-location":
+		return a.controlOpenLocation(msg)
+	case "run-diagnostics":
+		return a.controlRunDiagnostics(msg)
+	case "apply-fix-by-id":
+		return a.controlApplyFix(msg)
+	case "query-status":
+		msg.respond(a.controlStatus(), nil)
+		return nil
+	default:
+		msg.respond(nil, control.Errorf(control.CodeMethodNotFound, "unknown method %q", msg.method))
+		return nil
+	}
+}
+
+func (a *App) controlOpenLocation(msg controlRequestMsg) tea.Cmd {
+	var params struct {
+		Path   string `json:"path"`
+		Line   int    `json:"line"`
+		Column int    `json:"column"`
+	}
+	if err := control.DecodeParams(msg.params, &params); err != nil {
+		msg.respond(nil, err)
+		return nil
+	}
+	path, err := a.controlFilePath(params.Path)
+	if err != nil {
+		msg.respond(nil, err)
+		return nil
+	}
+	location := screens.OpenLocationMsg{FilePath: path, Line: max(params.Line, 1), Column: max(params.Column, 1)}
+	msg.respond(map[string]any{"path": path, "line": location.Line, "column": location.Column}, nil)
+	return func() tea.Msg { return location }
+}
+
+func (a *App) controlRunDiagnostics(msg controlRequestMsg) tea.Cmd {
+	var params struct {
+		Path string `json:"path"`
+	}
+	if err := control.DecodeParams(msg.params, &params); err != nil {
+		msg.respond(nil, err)
+		return nil
+	}
+	if err := a.controlSurgeReady(); err != nil {
+		msg.respond(nil, err)
+		return nil
+	}
+	if params.Path == "" {
+		msg.respond(map[string]any{"started": true, "path": a.projectPath}, nil)
+		return screens.CollectDiagnostics(a.surgeClient, a.projectPath)
+	}
+
+	path, err := a.controlFilePath(params.Path)
+	if err != nil {
+		msg.respond(nil, err)
+		return nil
+	}
+	if !syntax.SupportsFile(path) {
+		msg.respond(nil, control.Errorf(control.CodeInvalidParams, "%s is not a surge source file", path))
+		return nil
+	}
+	msg.respond(map[string]any{"started": true, "path": path}, nil)
+	return func() tea.Msg { return screens.RunFileDiagnosticsMsg{Path: path} }
+}
+
+// controlApplyFix применяет фикс через surge и отвечает после перезагрузки вкладки.
+// Файлы с несохранёнными правками не трогаются: surge их перезаписал бы.
+// Пока идёт другая операция над файлами проекта, вызов отклоняется как занятый.
+func (a *App) controlApplyFix(msg controlRequestMsg) tea.Cmd {
+	var params struct {
+		Path  string `json:"path"`
+		FixID string `json:"fix_id"`
+	}
+	if err := control.DecodeParams(msg.params, &params); err != nil {
+		msg.respond(nil, err)
+		return nil
+	}
+	if strings.TrimSpace(params.FixID) == "" {
+		msg.respond(nil, control.Errorf(control.CodeInvalidParams, "fix_id is required"))
+		return nil
+	}
+	path, err := a.controlFilePath(params.Path)
+	if err != nil {
+		msg.respond(nil, err)
+		return nil
+	}
+	if err := a.controlSurgeReady(); err != nil {
+		msg.respond(nil, err)
+		return nil
+	}
+	for _, dirty := range a.unsavedPaths() {
+		if dirty == path {
+			msg.respond(nil, fmt.Errorf("%s has unsaved changes", path))
+			return nil
+		}
+	}
+
+	client := a.surgeClient
+	fixID := params.FixID
+	token, other := a.projectOps.Begin("applying fix " + fixID + " via control")
+	if other != "" {
+		msg.respond(nil, fmt.Errorf("busy: %s", other))
+		return nil
+	}
+	return func() tea.Msg {
+		err := client.ApplyFixByID(context.Background(), path, fixID)
+		return controlFixAppliedMsg{req: msg, path: path, fixID: fixID, token: token, err: err}
+	}
+}
+
+func (a *App) handleControlFixApplied(msg controlFixAppliedMsg) tea.Cmd {
+	a.projectOps.End(msg.token)
+	if msg.err != nil {
+		msg.req.respond(nil, msg.err)
+		return nil
+	}
+	if reloader, ok := a.screens[ProjectScreen].(fileReloader); ok {
+		if err := reloader.ReloadFile(msg.path); err != nil {
+			msg.req.respond(nil, fmt.Errorf("fix applied, but reload failed: %w", err))
+			return nil
+		}
+	}
+	if notifier, ok := a.screens[ProjectScreen].(statusNotifier); ok {
+		notifier.Notify("Applied fix " + msg.fixID)
+	}
+	msg.req.respond(map[string]any{"path": msg.path, "fix_id": msg.fixID}, nil)
+	return func() tea.Msg { return screens.RunFileDiagnosticsMsg{Path: msg.path} }
+}
+
+func (a *App) controlStatus() controlStatus {
+	status := controlStatus{
+		Project:        a.projectPath,
+		Screen:         a.screenTitle(a.currentScreen),
+		SurgeAvailable: a.surgeAvailable,
+		SurgeVersion:   a.surgeVersion,
+		DirtyFiles:     append([]string{}, a.unsavedPaths()...),
+	}
+	for _, diags := range a.diagnostics {
+		if len(diags) == 0 {
+			continue
+		}
+		status.Diagnostics.Files++
+		for _, d := range diags {
+			switch strings.ToLower(d.Severity) {
+			case "error":
+				status.Diagnostics.Errors++
+			case "warning":
+				status.Diagnostics.Warnings++
+			default:
+				status.Diagnostics.Infos++
+			}
+		}
+	}
+	return status
+}
+
+func (a *App) unsavedPaths() []string {
+	if reporter, ok := a.screens[ProjectScreen].(unsavedFileReporter); ok {
+		return reporter.UnsavedFiles()
+	}
+	return nil
+}
+
+// controlFilePath приводит путь вызова к абсолютному и проверяет, что это файл.
+func (a *App) controlFilePath(path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", control.Errorf(control.CodeInvalidParams, "path is required")
+	}
+	if !filepath.IsAbs(path) && a.projectPath != "" {
+		path = filepath.Join(a.projectPath, path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", control.Errorf(control.CodeInvalidParams, "%v", err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", control.Errorf(control.CodeInvalidParams, "%v", err)
+	}
+	if info.IsDir() {
+		return "", control.Errorf(control.CodeInvalidParams, "%s is a directory", abs)
+	}
+	return abs, nil
+}
+
+func (a *App) controlSurgeReady() error {
+	if a.surgeClient == nil || !a.surgeAvailable {
+		return errors.New("surge is not available")
+	}
+	return nil
+}
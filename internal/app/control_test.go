@@ -0,0 +1,98 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"surge-tui/internal/config"
+	"surge-tui/internal/core/surge/testsupport"
+	"surge-tui/internal/ui/screens"
+)
+
+// applyFixRequest собирает вызов apply-fix-by-id с каналом для ответа.
+func applyFixRequest(t *testing.T, path, fixID string) (controlRequestMsg, chan controlReply) {
+	t.Helper()
+	params, err := json.Marshal(map[string]string{"path": path, "fix_id": fixID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply := make(chan controlReply, 1)
+	return controlRequestMsg{method: "apply-fix-by-id", params: params, reply: reply}, reply
+}
+
+// apply-fix-by-id занимает тот же реестр операций, что и экраны: поверх идущей
+// операции вызов отклоняется, а своя держится до обработки результата.
+func TestControlApplyFixClaimsProjectOps(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.sg")
+	if err := os.WriteFile(path, []byte("fn main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runner := testsupport.NewFakeRunner(nil)
+	a := NewWithRunner(config.DefaultConfig(), dir, runner)
+	a.surgeAvailable = true
+	a.screens[ProjectScreen] = screens.NewPlaceholderScreen("Project")
+
+	token, _ := a.projectOps.Begin("formatting the project")
+	req, reply := applyFixRequest(t, path, "fix-1")
+	if cmd := a.handleControl(req); cmd != nil {
+		t.Fatalf("apply-fix-by-id started while the project was busy")
+	}
+	if r := <-reply; r.err == nil || !strings.Contains(r.err.Error(), "busy: formatting the project") {
+		t.Fatalf("reply error = %v, want busy", r.err)
+	}
+	a.projectOps.End(token)
+
+	req, reply = applyFixRequest(t, path, "fix-1")
+	cmd := a.handleControl(req)
+	if cmd == nil {
+		t.Fatalf("apply-fix-by-id did not start")
+	}
+	if other := a.projectOps.Busy(); other == "" {
+		t.Fatalf("apply-fix-by-id did not claim project ops")
+	}
+	if _, other := a.projectOps.Begin("formatting the project"); other == "" {
+		t.Fatalf("formatting started while a control fix was running")
+	}
+
+	applied, ok := cmd().(controlFixAppliedMsg)
+	if !ok {
+		t.Fatalf("command did not return controlFixAppliedMsg")
+	}
+	a.Update(applied)
+	if r := <-reply; r.err != nil {
+		t.Fatalf("reply error = %v", r.err)
+	}
+	if other := a.projectOps.Busy(); other != "" {
+		t.Errorf("project ops still held by %q after the fix was applied", other)
+	}
+	if got := runner.AppliedFixIDs(); !slices.Equal(got, []string{"fix-1"}) {
+		t.Errorf("applied fixes = %v, want [fix-1]", got)
+	}
+}
+
+// Неудачный фикс тоже освобождает реестр.
+func TestControlApplyFixReleasesProjectOpsOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.sg")
+	if err := os.WriteFile(path, []byte("fn main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runner := testsupport.NewFakeRunner(nil)
+	runner.FixErr = os.ErrPermission
+	a := NewWithRunner(config.DefaultConfig(), dir, runner)
+	a.surgeAvailable = true
+
+	req, reply := applyFixRequest(t, path, "fix-1")
+	a.Update(a.handleControl(req)())
+	if r := <-reply; r.err == nil {
+		t.Fatalf("failed fix was reported as applied")
+	}
+	if other := a.projectOps.Busy(); other != "" {
+		t.Errorf("project ops still held by %q after the fix failed", other)
+	}
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/fs"
+	"surge-tui/internal/logging"
+	"surge-tui/internal/syntax"
+	"surge-tui/internal/ui/screens"
+)
+
+// fileDiagDelay склеивает быстрые последовательные сохранения в один запуск.
+const fileDiagDelay = 300 * time.Millisecond
+
+// diagApplyDelay склеивает серию результатов surge diag (наблюдение, запуски
+// по файлам) в одну раздачу экранам.
+const diagApplyDelay = 50 * time.Millisecond
+
+type diagnosticsSink interface {
+	SetDiagnostics(diags map[string][]screens.EditorDiagnostic)
+}
+
+type statusNotifier interface {
+	Notify(msg string)
+}
+
+type fileDiagTickMsg struct {
+	seq  int
+	path string
+}
+
+type diagApplyTickMsg struct {
+	seq int
+}
+
+// applyDiagnostics запоминает последние результаты surge diag и откладывает
+// их раздачу редакторам на diagApplyDelay: из серии быстрых результатов
+// экраны получат только последний.
+func (a *App) applyDiagnostics(diags map[string][]screens.EditorDiagnostic) tea.Cmd {
+	a.diagnostics = diags
+	a.diagErrors, a.diagWarnings = 0, 0
+	for _, fileDiags := range diags {
+		for _, diag := range fileDiags {
+			switch diag.Severity {
+			case "error":
+				a.diagErrors++
+			case "warning":
+				a.diagWarnings++
+			}
+		}
+	}
+	a.diagApplySeq++
+	tick := diagApplyTickMsg{seq: a.diagApplySeq}
+	return tea.Tick(diagApplyDelay, func(time.Time) tea.Msg { return tick })
+}
+
+// publishDiagnostics раздаёт экранам последний набор диагностик.
+func (a *App) publishDiagnostics(msg diagApplyTickMsg) {
+	if msg.seq != a.diagApplySeq {
+		return // за это время пришёл более новый набор
+	}
+	for _, screen := range a.screens {
+		if sink, ok := screen.(diagnosticsSink); ok {
+			sink.SetDiagnostics(a.diagnostics)
+		}
+	}
+}
+
+// handleDiagnosticsPublished применяет результаты полного, пофайлового или
+// ограниченного каталогом запуска.
+func (a *App) handleDiagnosticsPublished(msg screens.DiagnosticsPublishedMsg) tea.Cmd {
+	if msg.Err != nil {
+		if errors.Is(msg.Err, context.Canceled) {
+			return nil
+		}
+		logging.Errorf("diagnostics %s: %v", cmp.Or(msg.Path, a.projectPath), msg.Err)
+		return a.notify(logging.LevelError, "Diagnostics failed: "+msg.Err.Error())
+	}
+	if msg.Path == "" && msg.Dir == "" {
+		return a.applyDiagnostics(screens.GroupEditorDiagnostics(msg.Entries))
+	}
+	if msg.Path == "" {
+		// запуск по каталогу: диагностики файлов вне него остаются прежними
+		merged := screens.GroupEditorDiagnostics(msg.Entries)
+		for path, diags := range a.diagnostics {
+			if !fs.IsWithin(path, msg.Dir) {
+				merged[path] = diags
+			}
+		}
+		return a.applyDiagnostics(merged)
+	}
+
+	var cmd tea.Cmd
+	fileDiags := screens.GroupEditorDiagnostics(msg.Entries)[msg.Path]
+	if !screens.SameEditorDiagnostics(a.diagnostics[msg.Path], fileDiags) {
+		merged := make(map[string][]screens.EditorDiagnostic, len(a.diagnostics)+1)
+		for path, diags := range a.diagnostics {
+			if path != msg.Path {
+				merged[path] = diags
+			}
+		}
+		if len(fileDiags) > 0 {
+			merged[msg.Path] = fileDiags
+		}
+		cmd = a.applyDiagnostics(merged)
+	}
+
+	if ds, ok := a.screens[DiagnosticsScreen].(*screens.DiagnosticsScreen); ok && ds != nil {
+		ds.ReplaceFileDiagnostics(msg.Path, msg.Entries)
+	}
+	if notifier, ok := a.screens[ProjectScreen].(statusNotifier); ok {
+		notifier.Notify(screens.DiagnosticsSummary(msg.Entries))
+	}
+	return cmd
+}
+
+// scheduleDiagOnSave запускает диагностику сохранённого файла, если включён editor.diag_on_save.
+func (a *App) scheduleDiagOnSave(path string) tea.Cmd {
+	if a.config == nil || !a.config.Editor.DiagOnSave {
+		return nil
+	}
+	return a.scheduleFileDiagnostics(path)
+}
+
+// scheduleFileDiagnostics откладывает `surge diag` для одного файла.
+// Новый запрос отменяет незавершённый запуск.
+func (a *App) scheduleFileDiagnostics(path string) tea.Cmd {
+	if a.surgeClient == nil || !a.surgeAvailable {
+		return a.lazySurgeRecheck()
+	}
+	if path == "" || !syntax.SupportsFile(path) {
+		return nil
+	}
+	if a.fileDiagCancel != nil {
+		a.fileDiagCancel()
+		a.fileDiagCancel = nil
+	}
+	a.fileDiagSeq++
+	tick := fileDiagTickMsg{seq: a.fileDiagSeq, path: filepath.Clean(path)}
+	return tea.Tick(fileDiagDelay, func(time.Time) tea.Msg { return tick })
+}
+
+func (a *App) runFileDiagnostics(msg fileDiagTickMsg) tea.Cmd {
+	if msg.seq != a.fileDiagSeq {
+		return nil // за это время пришёл более новый запрос
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.fileDiagCancel = cancel
+	client := a.surgeClient
+	projectPath := a.projectPath
+	return func() tea.Msg {
+		defer cancel()
+		return screens.DiagnoseFile(ctx, client, projectPath, msg.path)
+	}
+}
+
+// watchInterval — интервал опроса для режима наблюдения экрана диагностики
+// (performance.refresh_rate; используется, только если fsnotify недоступен).
+func (a *App) watchInterval() time.Duration {
+	if a.config == nil {
+		return 0
+	}
+	return time.Duration(a.config.Performance.RefreshRate) * time.Millisecond
+}
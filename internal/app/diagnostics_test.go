@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+
+	"surge-tui/internal/config"
+	"surge-tui/internal/core/surge/testsupport"
+	"surge-tui/internal/ui/screens"
+)
+
+// sinkScreen запоминает наборы диагностик, дошедшие до экрана.
+type sinkScreen struct {
+	*screens.PlaceholderScreen
+	sets []map[string][]screens.EditorDiagnostic
+}
+
+func (s *sinkScreen) SetDiagnostics(diags map[string][]screens.EditorDiagnostic) {
+	s.sets = append(s.sets, diags)
+}
+
+// Серия быстрых результатов раздаётся экранам один раз — последним набором,
+// а счётчики статус-бара обновляются сразу.
+func TestDiagnosticsBurstIsCoalesced(t *testing.T) {
+	a := NewWithRunner(config.DefaultConfig(), t.TempDir(), testsupport.NewFakeRunner(nil))
+	sink := &sinkScreen{PlaceholderScreen: screens.NewPlaceholderScreen("Project")}
+	a.screens[ProjectScreen] = sink
+
+	var ticks []diagApplyTickMsg
+	for _, severity := range []string{"warning", "error", "error"} {
+		diags := map[string][]screens.EditorDiagnostic{
+			"/p/main.sg": {{Line: 1, EndLine: 1, Severity: severity}},
+		}
+		if cmd := a.applyDiagnostics(diags); cmd == nil {
+			t.Fatal("applyDiagnostics returned no tick")
+		}
+		ticks = append(ticks, diagApplyTickMsg{seq: a.diagApplySeq})
+		if len(sink.sets) != 0 {
+			t.Fatalf("diagnostics reached the screen before the tick")
+		}
+	}
+	if a.diagErrors != 1 || a.diagWarnings != 0 {
+		t.Errorf("counters = %d errors, %d warnings; want 1, 0", a.diagErrors, a.diagWarnings)
+	}
+
+	for _, tick := range ticks {
+		a.publishDiagnostics(tick)
+	}
+	if len(sink.sets) != 1 {
+		t.Fatalf("screen got %d sets, want 1", len(sink.sets))
+	}
+	if got := sink.sets[0]["/p/main.sg"][0].Severity; got != "error" {
+		t.Errorf("screen got severity %q, want the last set", got)
+	}
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/logging"
 	"surge-tui/internal/platform"
 )
 
@@ -16,25 +17,51 @@ func (a *App) handleGlobalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return a, nil
 	}
+	if a.switchDialog != nil && a.switchDialog.Visible {
+		return a, a.switchDialog.Update(msg)
+	}
+	if a.keyWarnDialog != nil && a.keyWarnDialog.Visible {
+		return a, a.keyWarnDialog.Update(msg)
+	}
 
-	// Сначала пытаемся найти команду через реестр
-	if cmd := a.commands.Resolve(rawKey, a.currentScreen); cmd != nil {
-		if cmd.Enabled == nil || cmd.Enabled(a) {
-			return a, cmd.Run(a)
+	// Экран, ждущий нажатие для новой привязки, получает его целиком
+	if capturer, ok := a.getCurrentScreen().(keyCapturer); ok && capturer.CapturingKey() {
+		updatedScreen, cmd := capturer.Update(msg)
+		a.screens[a.currentScreen] = updatedScreen
+		return a, cmd
+	}
+
+	// Esc сначала предлагаем экрану: закрыть фильтр, отменить правку и т.п.
+	// Иначе привязка workspace ("esc") перехватила бы его в реестре.
+	if canonicalKey == "esc" {
+		if handler, ok := a.getCurrentScreen().(escHandler); ok {
+			if handled, cmd := handler.HandleGlobalEsc(); handled {
+				return a, cmd
+			}
 		}
-		return a, nil
+	}
+
+	// Tab и Shift+Tab при вводе и выделении в редакторе — отступы
+	if claimer, ok := a.getCurrentScreen().(keyClaimer); ok && claimer.ClaimsKey(canonicalKey) {
+		updatedScreen, cmd := claimer.Update(msg)
+		a.screens[a.currentScreen] = updatedScreen
+		return a, cmd
+	}
+
+	// Сначала пытаемся найти команду через реестр. Клавиши команд экрана
+	// во время ввода текста или в диалоге остаются экрану.
+	cmd := a.commands.Resolve(rawKey, a.currentScreen)
+	if cmd != nil && cmd.Screen != nil && !a.screenKeysActive() {
+		cmd = a.commands.ResolveGlobal(rawKey)
+	}
+	if cmd != nil {
+		return a, cmd.execute(a)
 	}
 
 	switch {
 	case platform.MatchesKey(rawKey, "ctrl+c"):
 		return a, a.requestQuit()
 	case canonicalKey == "esc":
-		current := a.getCurrentScreen()
-		if handler, ok := current.(escHandler); ok {
-			if handled, cmd := handler.HandleGlobalEsc(); handled {
-				return a, cmd
-			}
-		}
 		return a, a.router.SwitchTo(ProjectScreen)
 	}
 
@@ -71,15 +98,16 @@ func (a *App) handleWindowResize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
 
 // handleScreenSwitch обрабатывает переключение экранов
 func (a *App) handleScreenSwitch(msg ScreenSwitchMsg) (tea.Model, tea.Cmd) {
-	// Проверяем, можно ли покинуть текущий экран
-	currentScreen := a.getCurrentScreen()
-	if currentScreen != nil && !currentScreen.CanExit() {
-		// Показываем диалог подтверждения
-		return a, nil // TODO: реализовать диалог
+	if cmd, refused := a.checkExit(msg); refused {
+		return a, cmd
 	}
+	a.router.commit(a.currentScreen, msg)
+	a.clearBackCrumb(msg)
+	currentScreen := a.getCurrentScreen()
 
 	// Выходим из текущего экрана
 	var cmds []tea.Cmd
+	a.rememberIssue(currentScreen)
 	if currentScreen != nil {
 		if exit := currentScreen.OnExit(); exit != nil {
 			cmds = append(cmds, exit)
@@ -117,6 +145,7 @@ func (a *App) handleScreenSwitch(msg ScreenSwitchMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Входим в новый экран
+	a.focusIssue(newScreen)
 	if newScreen != nil {
 		if enter := newScreen.OnEnter(); enter != nil {
 			cmds = append(cmds, enter)
@@ -129,24 +158,41 @@ func (a *App) handleScreenSwitch(msg ScreenSwitchMsg) (tea.Model, tea.Cmd) {
 	return a, tea.Batch(cmds...)
 }
 
-// handleError обрабатывает ошибки
-func (a *App) handleError(msg ErrorMsg) (tea.Model, tea.Cmd) {
-	a.lastError = msg.Error
-	// TODO: показать уведомление об ошибке
-	return a, nil
-}
-
-func (a *App) requestQuit() tea.Cmd {
-	if a.quitDialog == nil {
-		return tea.Quit
+// checkExit спрашивает покидаемый экран, можно ли уйти. Палитра команд
+// возвращает на тот же экран, поэтому её открытие не спрашивает, а уход
+// из палитры — это уход с экрана, с которого её открыли: он показывается
+// снова, чтобы был виден его диалог.
+func (a *App) checkExit(msg ScreenSwitchMsg) (tea.Cmd, bool) {
+	leaving := a.currentScreen
+	if leaving == CommandPaletteScreen {
+		leaving = a.paletteOrigin
+	}
+	screen := a.screens[leaving]
+	if msg.ScreenType == leaving || msg.ScreenType == CommandPaletteScreen || screen == nil || screen.CanExit() {
+		return nil, false
 	}
-	if a.quitDialog.Visible {
-		return nil
+	confirmer, ok := screen.(exitConfirmer)
+	if !ok {
+		return nil, true
 	}
+	confirm := confirmer.ConfirmExit(func() tea.Msg { return msg })
+	if leaving != a.currentScreen {
+		return tea.Batch(a.router.GoBack(), confirm), true
+	}
+	return confirm, true
+}
 
-	ch := a.quitDialog.Show()
-	return func() tea.Msg {
-		confirmed := <-ch
-		return quitConfirmedMsg{confirmed: confirmed}
+// handleError показывает ошибку фоновой команды уведомлением.
+func (a *App) handleError(msg ErrorMsg) (tea.Model, tea.Cmd) {
+	if msg.Error == nil {
+		return a, nil
 	}
+	return a, a.notify(logging.LevelError, msg.Error.Error())
+}
+
+// dialogVisible сообщает, открыт ли диалог уровня приложения.
+func (a *App) dialogVisible() bool {
+	return (a.quitDialog != nil && a.quitDialog.Visible) ||
+		(a.switchDialog != nil && a.switchDialog.Visible) ||
+		(a.keyWarnDialog != nil && a.keyWarnDialog.Visible)
 }
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+	"surge-tui/internal/core/surge/testsupport"
+	"surge-tui/internal/ui/screens"
+)
+
+// claimingScreen забирает клавишу claimed и запоминает, какие нажатия дошли до него.
+type claimingScreen struct {
+	*screens.PlaceholderScreen
+	claimed string
+	keys    []string
+}
+
+func (s *claimingScreen) ClaimsKey(key string) bool { return key == s.claimed }
+
+func (s *claimingScreen) Update(msg tea.Msg) (screens.Screen, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		s.keys = append(s.keys, key.String())
+	}
+	return s, nil
+}
+
+func TestClaimedKeysBypassGlobalBindings(t *testing.T) {
+	a := NewWithRunner(config.DefaultConfig(), t.TempDir(), testsupport.NewFakeRunner(nil))
+	screen := &claimingScreen{PlaceholderScreen: screens.NewPlaceholderScreen("Project"), claimed: "tab"}
+	a.screens[ProjectScreen] = screen
+	a.currentScreen = ProjectScreen
+
+	a.handleGlobalKeys(tea.KeyMsg{Type: tea.KeyTab})
+	if len(screen.keys) != 1 || screen.keys[0] != "tab" {
+		t.Fatalf("claimed Tab did not reach the screen: %v", screen.keys)
+	}
+
+	// без заявки Tab остаётся глобальной привязкой switch_screen
+	screen.claimed = ""
+	if _, cmd := a.handleGlobalKeys(tea.KeyMsg{Type: tea.KeyTab}); cmd == nil {
+		t.Errorf("unclaimed Tab did not switch screens")
+	}
+	if len(screen.keys) != 1 {
+		t.Errorf("unclaimed Tab reached the screen: %v", screen.keys)
+	}
+}
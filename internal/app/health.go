@@ -0,0 +1,165 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/logging"
+	"surge-tui/internal/platform"
+	"surge-tui/internal/ui/components"
+	"surge-tui/internal/ui/screens"
+)
+
+// Проверка окружения при запуске: surge, конфиг, проект, журнал и цвета
+// терминала. Если что-то не так, поверх интерфейса открывается панель со
+// списком проблем; без проблем проверка ничего не показывает. Отчёт пишется
+// в журнал и доступен из палитры (Health Report).
+
+// healthIssue — проблема окружения и поле настроек, где она исправляется.
+type healthIssue struct {
+	components.Issue
+	field    screens.SettingsField
+	hasField bool
+}
+
+// settingsFieldFocuser — экран настроек, умеющий выбрать поле.
+type settingsFieldFocuser interface {
+	FocusField(field screens.SettingsField)
+}
+
+// configKeyFields — поля настроек для ключей конфига из config.Warnings.
+var configKeyFields = map[string]screens.SettingsField{
+	"theme":                     screens.ThemeField,
+	"surge.timeout_seconds":     screens.SurgeTimeoutField,
+	"surge.cache_seconds":       screens.DiagCacheField,
+	"surge.stall_seconds":       screens.SurgeStallField,
+	"editor.tab_size":           screens.TabSizeField,
+	"editor.auto_save_delay":    screens.AutoSaveDelayField,
+	"performance.max_file_size": screens.MaxFileSizeField,
+	"performance.refresh_rate":  screens.RefreshRateField,
+	"logging.level":             screens.LogLevelField,
+}
+
+func newHealthIssue(title, detail, remedy string) healthIssue {
+	return healthIssue{Issue: components.Issue{Title: title, Detail: detail, Remedy: remedy}}
+}
+
+// withField привязывает проблему к полю настроек: Enter в панели откроет его.
+func (h healthIssue) withField(field screens.SettingsField) healthIssue {
+	h.field, h.hasField = field, true
+	h.Action = "Settings → " + field.String()
+	return h
+}
+
+// collectHealth выполняет быстрые проверки, которым не нужен surge.
+func (a *App) collectHealth() []healthIssue {
+	var issues []healthIssue
+
+	for _, w := range a.config.Warnings {
+		field, inSettings := configKeyFields[w.Key]
+		remedy := "Correct the value in the config file"
+		if inSettings {
+			remedy = "Set a valid value in Settings and save"
+		}
+		issue := newHealthIssue("Config "+w.Key, w.Message, remedy)
+		if inSettings {
+			issue = issue.withField(field)
+		}
+		issues = append(issues, issue)
+	}
+
+	if info, err := os.Stat(a.projectPath); err != nil {
+		issues = append(issues, newHealthIssue("Project path unavailable", err.Error(),
+			"Open another project (Open Project…) or change the default project").withField(screens.DefaultProjectField))
+	} else if !info.IsDir() {
+		issues = append(issues, newHealthIssue("Project path is not a directory", a.projectPath,
+			"Open a project directory (Open Project…) or change the default project").withField(screens.DefaultProjectField))
+	}
+
+	if path, err := logging.Default().FilePath(); path != "" && err != nil {
+		issues = append(issues, newHealthIssue("Log file is not writable", err.Error(),
+			"Set logging.file_path to a writable location; until then the log is kept only in memory"))
+	}
+
+	if term := platform.DetectTerminal(); !term.TrueColor() {
+		issues = append(issues, newHealthIssue("No truecolor support", term.Name()+" does not advertise 24-bit color",
+			"Export COLORTERM=truecolor if the terminal supports it; otherwise theme colors are approximated").withField(screens.ThemeField))
+	}
+	return issues
+}
+
+// surgeHealth — проблема с surge по результату последней проверки.
+func (a *App) surgeHealth() (healthIssue, bool) {
+	if !a.surgeChecked || a.surgeAvailable {
+		return healthIssue{}, false
+	}
+	detail := "surge is not in PATH"
+	if a.config.SurgeBinary != "" {
+		detail = a.config.SurgeBinary + " does not run"
+	}
+	if a.surgeErr != nil {
+		detail = a.surgeErr.Error()
+	}
+	issue := newHealthIssue("Surge not found", detail, "Install surge or set the path to its binary")
+	return issue.withField(screens.SurgeBinaryField), true
+}
+
+// reportHealth дописывает к проверкам запуска результат проверки surge,
+// пишет отчёт в журнал и показывает панель, если есть проблемы. startup —
+// отчёт запуска: без проблем он ничего не показывает.
+func (a *App) reportHealth(startup bool) tea.Cmd {
+	issues := a.health
+	if issue, ok := a.surgeHealth(); ok {
+		issues = append(issues[:len(issues):len(issues)], issue)
+	}
+	a.healthShown = issues
+
+	if len(issues) == 0 {
+		logging.Infof("Health check: no problems found")
+		if startup {
+			return nil
+		}
+		return a.notify(logging.LevelInfo, "Health check: no problems found")
+	}
+	for _, issue := range issues {
+		logging.Warnf("Health check: %s: %s (%s)", issue.Title, issue.Detail, issue.Remedy)
+	}
+	panel := make([]components.Issue, len(issues))
+	for i, issue := range issues {
+		panel[i] = issue.Issue
+	}
+	title := fmt.Sprintf("Health check: %d problem(s)", len(issues))
+	a.issuePanel.Show(title, panel, a.theme.Width(), max(a.theme.Height()/2, 8))
+	return nil
+}
+
+// showHealthReport заново проверяет окружение по команде палитры.
+func (a *App) showHealthReport() tea.Cmd {
+	a.health = a.collectHealth()
+	return a.reportHealth(false)
+}
+
+// fixHealthIssue открывает настройки на поле выбранной в панели проблемы.
+func (a *App) fixHealthIssue(index int) tea.Cmd {
+	if index < 0 || index >= len(a.healthShown) || !a.healthShown[index].hasField {
+		return nil
+	}
+	field := a.healthShown[index].field
+	screen, init := a.ensureScreen(SettingsScreen)
+	if focuser, ok := screen.(settingsFieldFocuser); ok {
+		focuser.FocusField(field)
+	}
+	return tea.Batch(init, a.router.SwitchTo(SettingsScreen))
+}
+
+// ensureScreen создаёт экран, если его ещё нет, и возвращает его вместе с
+// командой Init нового экрана.
+func (a *App) ensureScreen(screenType ScreenType) (screens.Screen, tea.Cmd) {
+	if screen := a.screens[screenType]; screen != nil {
+		return screen, nil
+	}
+	screen := a.createScreen(screenType)
+	a.screens[screenType] = screen
+	return screen, screen.Init()
+}
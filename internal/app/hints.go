@@ -0,0 +1,28 @@
+package app
+
+type keyHintSetter interface {
+	SetKeyHints(enabled bool, commands map[string]string)
+}
+
+// commandKeys возвращает отображаемые клавиши команд реестра, доступных на экране.
+func (a *App) commandKeys(screen ScreenType) map[string]string {
+	keys := make(map[string]string)
+	for _, cmd := range a.commands.All() {
+		if cmd.Screen != nil && *cmd.Screen != screen {
+			continue
+		}
+		if display := prettifyKey(cmd.Key); display != "" {
+			keys[cmd.ID] = display
+		}
+	}
+	return keys
+}
+
+// applyKeyHints передаёт экранам актуальные привязки для подсказок в панелях.
+func (a *App) applyKeyHints() {
+	for screenType, screen := range a.screens {
+		if setter, ok := screen.(keyHintSetter); ok {
+			setter.SetKeyHints(a.config.UI.PanelHints, a.commandKeys(screenType))
+		}
+	}
+}
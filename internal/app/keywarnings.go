@@ -0,0 +1,82 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+	"surge-tui/internal/platform"
+	"surge-tui/internal/ui/components"
+)
+
+const maxKeyWarningLines = 8
+
+// keyWarningsChoiceMsg — ответ на предупреждение о ненадёжных привязках.
+type keyWarningsChoiceMsg struct {
+	openSettings bool
+}
+
+// keybindingFocuser — экран настроек, умеющий открыть редактор привязок.
+type keybindingFocuser interface {
+	FocusKeybindings()
+}
+
+// showKeybindingWarnings один раз при запуске показывает привязки,
+// которые текущий терминал, скорее всего, не передаст, и клавиши,
+// назначенные нескольким действиям одной области.
+func (a *App) showKeybindingWarnings() tea.Cmd {
+	term := platform.DetectTerminal()
+	warnings := a.config.KeybindingWarnings(term)
+	conflicts := a.config.KeybindingConflicts()
+	if len(warnings) == 0 && len(conflicts) == 0 {
+		return nil
+	}
+	title := "Keybindings " + term.Name() + " may not send"
+	if len(warnings) == 0 {
+		title = "Keybinding conflicts"
+	}
+	a.keyWarnDialog = components.NewChoiceDialog(
+		title,
+		keybindingWarningsText(warnings, conflicts),
+		"OK", "Open Settings",
+	)
+	ch := a.keyWarnDialog.Show()
+	return func() tea.Msg {
+		return keyWarningsChoiceMsg{openSettings: <-ch == 1}
+	}
+}
+
+func keybindingWarningsText(warnings []config.KeybindingWarning, conflicts []config.KeybindingConflict) string {
+	width := 0
+	for _, w := range warnings {
+		width = max(width, len(w.Action))
+	}
+	lines := make([]string, 0, maxKeyWarningLines+1)
+	for _, w := range warnings {
+		line := fmt.Sprintf("%-*s  %s — %s", width, w.Action, platform.DisplayKey(w.Key), w.Reason)
+		if w.Suggestion != "" {
+			line += "; try " + platform.DisplayKey(w.Suggestion)
+		}
+		lines = append(lines, line)
+	}
+	for _, c := range conflicts {
+		lines = append(lines, "Conflict "+c.String())
+	}
+	if len(lines) > maxKeyWarningLines {
+		more := len(lines) - maxKeyWarningLines
+		lines = append(lines[:maxKeyWarningLines], fmt.Sprintf("…and %d more", more))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (a *App) handleKeyWarningsChoice(msg keyWarningsChoiceMsg) tea.Cmd {
+	if !msg.openSettings {
+		return nil
+	}
+	screen, init := a.ensureScreen(SettingsScreen)
+	if focuser, ok := screen.(keybindingFocuser); ok {
+		focuser.FocusKeybindings()
+	}
+	return tea.Batch(init, a.router.SwitchTo(SettingsScreen))
+}
@@ -0,0 +1,26 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"surge-tui/internal/config"
+	"surge-tui/internal/logging"
+)
+
+// loggingOptions переводит секции logging и performance конфига в настройки журнала.
+func loggingOptions(cfg *config.Config) logging.Options {
+	path := cfg.Logging.FilePath
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return logging.Options{
+		Level:      logging.ParseLevel(cfg.Logging.Level),
+		FilePath:   path,
+		MaxSize:    cfg.Logging.MaxSize,
+		MaxEntries: cfg.Performance.MaxLogEntries,
+	}
+}
@@ -0,0 +1,424 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/ui/screens"
+)
+
+func (a *App) handleOpenLocation(msg screens.OpenLocationMsg) tea.Cmd {
+	var cmds []tea.Cmd
+
+	if msg.FilePath != "" {
+		if screen, ok := a.screens[ProjectScreen].(*screens.ProjectScreenReal); ok && screen != nil {
+			screen.OpenLocation(msg.FilePath, msg.Line, msg.Column)
+			screen.SetBackTo(msg.From)
+		}
+	}
+
+	cmds = append(cmds, a.router.SwitchTo(ProjectScreen))
+	return tea.Batch(cmds...)
+}
+
+// goBackMsg просит вернуться к предыдущему экрану. История читается, когда
+// сообщение дошло, а не когда создана команда: из палитры Go Back сначала
+// возвращается на её экран.
+type goBackMsg struct{}
+
+func goBack() tea.Msg { return goBackMsg{} }
+
+// clearBackCrumb убирает крошку «назад» с экрана проекта, когда с него
+// уходят: история роутера после этого ведёт уже не туда. Палитра
+// возвращает на тот же экран и крошку не трогает.
+func (a *App) clearBackCrumb(msg ScreenSwitchMsg) {
+	if a.currentScreen != ProjectScreen || msg.ScreenType == ProjectScreen || msg.ScreenType == CommandPaletteScreen {
+		return
+	}
+	if screen, ok := a.screens[ProjectScreen].(*screens.ProjectScreenReal); ok && screen != nil {
+		screen.SetBackTo("")
+	}
+}
+
+// issueTracker — экран с «текущей проблемой»: диагностика и Fix Mode.
+type issueTracker interface {
+	CurrentIssue() (screens.IssueRef, bool)
+	FocusIssue(ref screens.IssueRef)
+}
+
+// rememberIssue запоминает проблему под курсором экрана, с которого уходят.
+func (a *App) rememberIssue(screen screens.Screen) {
+	if tracker, ok := screen.(issueTracker); ok {
+		if issue, ok := tracker.CurrentIssue(); ok {
+			a.currentIssue = issue
+		}
+	}
+}
+
+// focusIssue выбирает запомненную проблему на экране, на который пришли.
+func (a *App) focusIssue(screen screens.Screen) {
+	if tracker, ok := screen.(issueTracker); ok && !a.currentIssue.IsZero() {
+		tracker.FocusIssue(a.currentIssue)
+	}
+}
+
+// openScratchBuffer открывает scratch-буфер во вкладках рабочей области.
+func (a *App) openScratchBuffer() tea.Cmd {
+	if screen, ok := a.screens[ProjectScreen].(*screens.ProjectScreenReal); ok && screen != nil {
+		screen.OpenScratchBuffer()
+	}
+	return a.router.SwitchTo(ProjectScreen)
+}
+
+// openQuickOpen показывает быстрое открытие файла на экране проекта.
+func (a *App) openQuickOpen() tea.Cmd {
+	var cmd tea.Cmd
+	if screen, ok := a.screens[ProjectScreen].(*screens.ProjectScreenReal); ok && screen != nil {
+		cmd = screen.OpenQuickOpen()
+	}
+	return tea.Batch(cmd, a.router.SwitchTo(ProjectScreen))
+}
+
+func (a *App) handleOpenFixMode(msg screens.OpenFixModeMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	screenIface := a.screens[FixModeScreen]
+	if screenIface == nil {
+		screenIface = a.createScreen(FixModeScreen)
+		a.screens[FixModeScreen] = screenIface
+		if init := screenIface.Init(); init != nil {
+			cmds = append(cmds, init)
+		}
+	}
+	if screen, ok := screenIface.(*screens.FixModeScreen); ok && screen != nil {
+		if a.projectPath != "" {
+			screen.SetProjectPath(a.projectPath)
+		}
+		screen.FocusFix(msg.FilePath, msg.FixID)
+	}
+	cmds = append(cmds, a.router.SwitchTo(FixModeScreen))
+	return tea.Batch(cmds...)
+}
+
+// handleDiagnoseDir открывает экран диагностики, ограниченный каталогом.
+func (a *App) handleDiagnoseDir(msg screens.DiagnoseDirMsg) tea.Cmd {
+	screen, init := a.ensureScreen(DiagnosticsScreen)
+	var run tea.Cmd
+	if ds, ok := screen.(*screens.DiagnosticsScreen); ok {
+		run = ds.SetScope(msg.Dir)
+	}
+	return tea.Batch(init, run, a.router.SwitchTo(DiagnosticsScreen))
+}
+
+// handleFixDir открывает Fix Mode для каталога; после загрузки фиксов экран
+// спросит, применить ли их все.
+func (a *App) handleFixDir(msg screens.FixDirMsg) tea.Cmd {
+	screen, init := a.ensureScreen(FixModeScreen)
+	if fs, ok := screen.(*screens.FixModeScreen); ok {
+		fs.SetScope(msg.Dir, true)
+	}
+	return tea.Batch(init, a.router.SwitchTo(FixModeScreen))
+}
+
+// pathSelector — экран, у которого есть путь под курсором.
+type pathSelector interface {
+	SelectedPath() string
+}
+
+func (a *App) hasSelectedPath() bool {
+	selector, ok := a.commandTarget().(pathSelector)
+	return ok && selector.SelectedPath() != ""
+}
+
+// copySelectedPath копирует путь под курсором экрана команды.
+func (a *App) copySelectedPath(relative bool) tea.Cmd {
+	selector, ok := a.commandTarget().(pathSelector)
+	if !ok {
+		return nil
+	}
+	return screens.CopyPath(selector.SelectedPath(), a.projectPath, relative)
+}
+
+// notifyCurrent показывает сообщение в статусе текущего экрана, если он умеет.
+func (a *App) notifyCurrent(msg string) {
+	if notifier, ok := a.getCurrentScreen().(statusNotifier); ok {
+		notifier.Notify(msg)
+	}
+}
+
+// statusHistorian хранит последние сообщения строки статуса.
+type statusHistorian interface {
+	StatusHistory() []string
+}
+
+// showStatusHistory показывает последние сообщения экрана команды в оверлее справки.
+func (a *App) showStatusHistory() tea.Cmd {
+	historian, ok := a.commandTarget().(statusHistorian)
+	if !ok || a.helpOverlay == nil {
+		return nil
+	}
+	lines := historian.StatusHistory()
+	if len(lines) == 0 {
+		lines = []string{"(no messages yet)"}
+	}
+	a.helpOverlay.Show("Recent Messages", lines, a.theme.Height()/2)
+	return nil
+}
+
+type fileReverter interface {
+	RevertFile() tea.Cmd
+}
+
+func (a *App) openCommandPalette() tea.Cmd {
+	if a.currentScreen != CommandPaletteScreen {
+		a.paletteOrigin = a.currentScreen
+	}
+	return a.router.SwitchTo(CommandPaletteScreen)
+}
+
+// commandTarget возвращает экран, к которому относится команда: пока открыта
+// палитра, это экран, с которого её вызвали.
+func (a *App) commandTarget() screens.Screen {
+	if a.currentScreen == CommandPaletteScreen {
+		return a.screens[a.paletteOrigin]
+	}
+	return a.getCurrentScreen()
+}
+
+func (a *App) revertFile() tea.Cmd {
+	if reverter, ok := a.commandTarget().(fileReverter); ok {
+		return reverter.RevertFile()
+	}
+	return nil
+}
+
+// bufferRollbacker откатывает активную вкладку к прошлому состоянию по времени.
+type bufferRollbacker interface {
+	CanRollbackBuffer() bool
+	RollbackBuffer() tea.Cmd
+}
+
+func (a *App) canRollbackBuffer() bool {
+	rollbacker, ok := a.commandTarget().(bufferRollbacker)
+	return ok && rollbacker.CanRollbackBuffer()
+}
+
+func (a *App) rollbackBuffer() tea.Cmd {
+	if rollbacker, ok := a.commandTarget().(bufferRollbacker); ok {
+		return rollbacker.RollbackBuffer()
+	}
+	return nil
+}
+
+type fileMover interface {
+	CanMoveFile() bool
+	MoveFile() tea.Cmd
+}
+
+func (a *App) moveFile() tea.Cmd {
+	if mover, ok := a.commandTarget().(fileMover); ok {
+		return mover.MoveFile()
+	}
+	return nil
+}
+
+type fileCopier interface {
+	CanCopyToFile() bool
+	CopyToFile() tea.Cmd
+}
+
+func (a *App) copyToFile() tea.Cmd {
+	if copier, ok := a.commandTarget().(fileCopier); ok {
+		return copier.CopyToFile()
+	}
+	return nil
+}
+
+type selectionExtractor interface {
+	CanExtractSelection() bool
+	ExtractSelection() tea.Cmd
+}
+
+func (a *App) extractSelection() tea.Cmd {
+	if extractor, ok := a.commandTarget().(selectionExtractor); ok {
+		return extractor.ExtractSelection()
+	}
+	return nil
+}
+
+type externalEditorLauncher interface {
+	CanOpenExternalEditor() bool
+	OpenExternalEditor() tea.Cmd
+}
+
+func (a *App) openExternalEditor() tea.Cmd {
+	if launcher, ok := a.commandTarget().(externalEditorLauncher); ok {
+		return launcher.OpenExternalEditor()
+	}
+	return nil
+}
+
+// patternRenamer переименовывает отмеченные элементы дерева по шаблону.
+type patternRenamer interface {
+	CanRenameWithPattern() bool
+	RenameWithPattern() tea.Cmd
+}
+
+func (a *App) renameWithPattern() tea.Cmd {
+	if renamer, ok := a.commandTarget().(patternRenamer); ok {
+		return renamer.RenameWithPattern()
+	}
+	return nil
+}
+
+type problemsToggler interface {
+	ToggleProblems() tea.Cmd
+}
+
+func (a *App) toggleProblems() tea.Cmd {
+	if toggler, ok := a.commandTarget().(problemsToggler); ok {
+		return toggler.ToggleProblems()
+	}
+	return nil
+}
+
+// changeNavigator обходит несохранённые изменения активной вкладки.
+type changeNavigator interface {
+	CanNavigateChanges() bool
+	JumpToChange(dir int) tea.Cmd
+	RevertHunk() tea.Cmd
+}
+
+func (a *App) canNavigateChanges() bool {
+	navigator, ok := a.commandTarget().(changeNavigator)
+	return ok && navigator.CanNavigateChanges()
+}
+
+func (a *App) jumpToChange(dir int) tea.Cmd {
+	if navigator, ok := a.commandTarget().(changeNavigator); ok {
+		return navigator.JumpToChange(dir)
+	}
+	return nil
+}
+
+func (a *App) revertHunk() tea.Cmd {
+	if navigator, ok := a.commandTarget().(changeNavigator); ok {
+		return navigator.RevertHunk()
+	}
+	return nil
+}
+
+// symbolNavigator обходит объявления функций и типов активной вкладки.
+type symbolNavigator interface {
+	CanNavigateSymbols() bool
+	JumpToSymbol(dir int) tea.Cmd
+}
+
+func (a *App) canNavigateSymbols() bool {
+	navigator, ok := a.commandTarget().(symbolNavigator)
+	return ok && navigator.CanNavigateSymbols()
+}
+
+func (a *App) jumpToSymbol(dir int) tea.Cmd {
+	if navigator, ok := a.commandTarget().(symbolNavigator); ok {
+		return navigator.JumpToSymbol(dir)
+	}
+	return nil
+}
+
+// lineTransformer сортирует и разворачивает выделенные строки активной вкладки.
+type lineTransformer interface {
+	CanTransformLines() bool
+	SortLines(unique bool) tea.Cmd
+	ReverseLines() tea.Cmd
+}
+
+func (a *App) canTransformLines() bool {
+	transformer, ok := a.commandTarget().(lineTransformer)
+	return ok && transformer.CanTransformLines()
+}
+
+func (a *App) sortLines(unique bool) tea.Cmd {
+	if transformer, ok := a.commandTarget().(lineTransformer); ok {
+		return transformer.SortLines(unique)
+	}
+	return nil
+}
+
+func (a *App) reverseLines() tea.Cmd {
+	if transformer, ok := a.commandTarget().(lineTransformer); ok {
+		return transformer.ReverseLines()
+	}
+	return nil
+}
+
+// commentToggler комментирует строки активной вкладки.
+type commentToggler interface {
+	CanToggleComment() bool
+	ToggleComment() tea.Cmd
+}
+
+func (a *App) canToggleComment() bool {
+	toggler, ok := a.commandTarget().(commentToggler)
+	return ok && toggler.CanToggleComment()
+}
+
+func (a *App) toggleComment() tea.Cmd {
+	if toggler, ok := a.commandTarget().(commentToggler); ok {
+		return toggler.ToggleComment()
+	}
+	return nil
+}
+
+// bracketJumper переводит курсор к парной скобке.
+type bracketJumper interface {
+	CanJumpToBracket() bool
+	JumpToBracket() tea.Cmd
+}
+
+func (a *App) canJumpToBracket() bool {
+	jumper, ok := a.commandTarget().(bracketJumper)
+	return ok && jumper.CanJumpToBracket()
+}
+
+func (a *App) jumpToBracket() tea.Cmd {
+	if jumper, ok := a.commandTarget().(bracketJumper); ok {
+		return jumper.JumpToBracket()
+	}
+	return nil
+}
+
+// testFileToggler переключает активную вкладку между исходником и тестом.
+type testFileToggler interface {
+	CanToggleTestFile() bool
+	ToggleTestFile() tea.Cmd
+}
+
+func (a *App) canToggleTestFile() bool {
+	toggler, ok := a.commandTarget().(testFileToggler)
+	return ok && toggler.CanToggleTestFile()
+}
+
+func (a *App) toggleTestFile() tea.Cmd {
+	if toggler, ok := a.commandTarget().(testFileToggler); ok {
+		return toggler.ToggleTestFile()
+	}
+	return nil
+}
+
+type fileFormatter interface {
+	CanFormatFile() bool
+	FormatFile() tea.Cmd
+}
+
+func (a *App) formatFile() tea.Cmd {
+	if formatter, ok := a.commandTarget().(fileFormatter); ok {
+		return formatter.FormatFile()
+	}
+	return nil
+}
+
+// formatProject запускает `surge fmt` для всего проекта из рабочей области.
+func (a *App) formatProject() tea.Cmd {
+	if ps, ok := a.screens[ProjectScreen].(*screens.ProjectScreenReal); ok && ps != nil {
+		return ps.FormatProject()
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/logging"
+)
+
+// notify показывает уведомление над статус-баром и записывает его в
+// историю уведомлений журнала (экран логов, фильтр n).
+func (a *App) notify(level logging.Level, text string) tea.Cmd {
+	text = strings.TrimSpace(text)
+	if text == "" || a.toasts == nil {
+		return nil
+	}
+	logging.Default().Notice(level, text)
+	return a.toasts.Push(level, text)
+}
+
+// overlayToasts кладёт уведомления поверх нижних строк экрана, чтобы высота
+// интерфейса не менялась и статус-бар оставался на месте.
+func (a *App) overlayToasts(view string) string {
+	if a.toasts == nil || a.toasts.Len() == 0 {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	toasts := a.toasts.Lines(max(a.theme.Width(), 20))
+	if len(toasts) > len(lines) {
+		toasts = toasts[len(toasts)-len(lines):]
+	}
+	copy(lines[len(lines)-len(toasts):], toasts)
+	return strings.Join(lines, "\n")
+}
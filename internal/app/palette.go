@@ -0,0 +1,47 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/ui/screens"
+)
+
+// paletteProvider — экран, добавляющий в палитру свои пункты перехода
+// (вкладки, закладки, недавние файлы). Пункты запрашиваются при каждом
+// открытии палитры и идут после команд реестра.
+type paletteProvider interface {
+	PaletteEntries() []screens.CommandEntry
+}
+
+// paletteEntries собирает пункты экранов в порядке ScreenType.
+func (a *App) paletteEntries() []screens.CommandEntry {
+	var entries []screens.CommandEntry
+	for screen := ProjectScreen; screen <= ProjectPickerScreen; screen++ {
+		provider, ok := a.screens[screen].(paletteProvider)
+		if !ok {
+			continue
+		}
+		for _, entry := range provider.PaletteEntries() {
+			if entry.Run == nil {
+				continue
+			}
+			entry.Run = a.paletteRun(screen, entry.Run)
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// paletteRun закрывает палитру, выполняет пункт и показывает экран, которому
+// он принадлежит. Если палитру открыли с другого экрана, он остаётся в
+// истории: «назад» вернёт к нему.
+func (a *App) paletteRun(screen ScreenType, run func() tea.Cmd) func() tea.Cmd {
+	return func() tea.Cmd {
+		var show tea.Cmd
+		if a.paletteOrigin == screen {
+			show = a.router.GoBack()
+		} else {
+			show = func() tea.Msg { return ScreenSwitchMsg{ScreenType: screen} }
+		}
+		return tea.Batch(show, run())
+	}
+}
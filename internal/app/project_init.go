@@ -0,0 +1,69 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/logging"
+	"surge-tui/internal/ui/screens"
+)
+
+// initProject вызывает `surge init` для текущего выбраного пути на экране проекта
+func (a *App) initProject() tea.Cmd {
+	if a.surgeClient == nil || !a.surgeAvailable {
+		return nil
+	}
+	if commander, ok := a.commandTarget().(projectInitCommander); ok {
+		return commander.InitProjectInSelectedDir()
+	}
+	return nil
+}
+
+// handleProjectInitialized открывает инициализированный каталог как проект.
+// Если это текущий корень или есть несохранённые правки, экран проекта
+// не пересоздаётся — он только обновляет дерево.
+func (a *App) handleProjectInitialized(msg screens.ProjectInitializedMsg) tea.Cmd {
+	if msg.Err != nil {
+		logging.Errorf("surge init %s: %v", msg.Path, msg.Err)
+		return tea.Batch(a.deliverTo(ProjectScreen, msg), a.notify(logging.LevelError, "surge init failed: "+msg.Err.Error()))
+	}
+	if msg.Path == "" || msg.Path == a.projectPath || len(a.unsavedPaths()) > 0 {
+		return a.deliverTo(ProjectScreen, msg)
+	}
+
+	a.projectPath = msg.Path
+	cmd := a.reloadProjectScreen()
+	if notifier, ok := a.screens[ProjectScreen].(statusNotifier); ok {
+		notifier.Notify("Initialized surge project in " + msg.Path)
+	}
+	return cmd
+}
+
+// reloadProjectScreen пересоздаёт экран проекта для a.projectPath.
+func (a *App) reloadProjectScreen() tea.Cmd {
+	var cmds []tea.Cmd
+	if stopper, ok := a.screens[ProjectScreen].(buildStopper); ok {
+		stopper.Stop()
+	}
+	newScreen := a.createScreen(ProjectScreen)
+	// передаем последнюю известную геометрию
+	if a.theme.Width() > 0 && a.theme.Height() > 0 {
+		if updated, cmd := newScreen.Update(tea.WindowSizeMsg{Width: a.theme.Width(), Height: a.theme.Height()}); updated != nil {
+			newScreen = updated
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	}
+	if initCmd := newScreen.Init(); initCmd != nil {
+		cmds = append(cmds, initCmd)
+	}
+	a.screens[ProjectScreen] = newScreen
+	if a.currentScreen == ProjectScreen {
+		if enter := newScreen.OnEnter(); enter != nil {
+			cmds = append(cmds, enter)
+		}
+	}
+	if fixScreen, ok := a.screens[FixModeScreen].(*screens.FixModeScreen); ok && fixScreen != nil {
+		fixScreen.SetProjectPath(a.projectPath)
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+	"surge-tui/internal/logging"
+	"surge-tui/internal/ui/screens"
+)
+
+// projectSwitchConfirmedMsg — ответ на предупреждение о несохранённых вкладках.
+type projectSwitchConfirmedMsg struct {
+	path      string
+	confirmed bool
+}
+
+type buildStopper interface {
+	Stop()
+}
+
+// openProjectPicker показывает выбор каталога проекта.
+func (a *App) openProjectPicker() tea.Cmd {
+	if picker, ok := a.screens[ProjectPickerScreen].(*screens.ProjectPickerScreen); ok {
+		picker.SetProjectPath(a.projectPath)
+	}
+	return a.router.SwitchTo(ProjectPickerScreen)
+}
+
+// handleProjectChosen открывает выбранный каталог. Несохранённые вкладки
+// закрываются только после подтверждения, как при закрытии вкладки.
+func (a *App) handleProjectChosen(msg screens.ProjectChosenMsg) tea.Cmd {
+	path, err := filepath.Abs(msg.Path)
+	if err != nil {
+		return a.notify(logging.LevelError, fmt.Sprintf("Cannot open %s: %v", msg.Path, err))
+	}
+	if path == a.projectPath {
+		return a.router.GoBack()
+	}
+	if reporter, ok := a.screens[ProjectScreen].(unsavedReporter); ok && a.switchDialog != nil {
+		if unsaved := reporter.UnsavedTabs(); len(unsaved) > 0 {
+			a.switchDialog.Description = fmt.Sprintf("Unsaved changes in %s will be lost. Open %s anyway?",
+				strings.Join(unsaved, ", "), filepath.Base(path))
+			ch := a.switchDialog.Show()
+			return func() tea.Msg {
+				return projectSwitchConfirmedMsg{path: path, confirmed: <-ch}
+			}
+		}
+	}
+	return a.switchProject(path)
+}
+
+// switchProject делает path текущим проектом: экраны, привязанные к старому
+// проекту, останавливаются и создаются заново при следующем открытии.
+func (a *App) switchProject(path string) tea.Cmd {
+	if a.fileDiagCancel != nil {
+		a.fileDiagCancel()
+		a.fileDiagCancel = nil
+	}
+	for _, screenType := range []ScreenType{DiagnosticsScreen, BuildScreen, FixModeScreen} {
+		screen := a.screens[screenType]
+		if screen == nil {
+			continue
+		}
+		if stopper, ok := screen.(buildStopper); ok {
+			stopper.Stop()
+		}
+		screen.OnExit()
+		delete(a.screens, screenType)
+	}
+
+	a.saveSession()
+	a.projectPath = path
+	logging.Infof("opened project %s", path)
+	a.sessionRestored = false
+	a.lastOpenedFile = ""
+	a.diagnostics = nil
+	a.diagErrors, a.diagWarnings = 0, 0
+	clear(a.unsavedFiles)
+
+	cmd := a.reloadProjectScreen()
+	if notifier, ok := a.screens[ProjectScreen].(statusNotifier); ok {
+		notifier.Notify("Opened project " + path)
+	}
+	a.router.ClearHistory()
+	return tea.Batch(cmd, a.rememberProject(path), func() tea.Msg {
+		return ScreenSwitchMsg{ScreenType: ProjectScreen}
+	})
+}
+
+// rememberProject добавляет проект в список недавних.
+func (a *App) rememberProject(path string) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, err := config.AddRecentProject(path); err != nil {
+			return ErrorMsg{Error: fmt.Errorf("recent projects: %w", err)}
+		}
+		return nil
+	}
+}
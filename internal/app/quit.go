@@ -0,0 +1,121 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Выход спрашивает подтверждение, только если есть несохранённые вкладки.
+
+const maxQuitListedFiles = 8
+
+// Варианты диалога выхода по порядку кнопок.
+const (
+	quitSaveAll = iota
+	quitDiscard
+	quitCancel
+)
+
+var quitOptions = []string{"Save All & Quit", "Quit Without Saving", "Cancel"}
+
+// quitChoiceMsg — ответ на диалог выхода (индекс варианта или ChoiceCanceled).
+type quitChoiceMsg struct {
+	choice int
+}
+
+// allSaver — экран, умеющий сохранить все свои изменённые буферы.
+type allSaver interface {
+	SaveAllTabs() error
+}
+
+func (a *App) requestQuit() tea.Cmd {
+	if a.quitDialog == nil {
+		a.saveSession()
+		return tea.Quit
+	}
+	if a.quitDialog.Visible {
+		return nil
+	}
+	unsaved := a.collectUnsaved()
+	if len(unsaved) == 0 {
+		a.saveSession()
+		return tea.Quit
+	}
+	a.quitDialog.Description = unsavedDescription(unsaved)
+	ch := a.quitDialog.Show()
+	return func() tea.Msg {
+		return quitChoiceMsg{choice: <-ch}
+	}
+}
+
+// collectUnsaved опрашивает экраны о несохранённых буферах, обновляет
+// a.unsavedFiles и возвращает имена вкладок для показа.
+func (a *App) collectUnsaved() []string {
+	clear(a.unsavedFiles)
+	var names []string
+	for screenType := ProjectScreen; screenType <= ProjectPickerScreen; screenType++ {
+		screen := a.screens[screenType]
+		if reporter, ok := screen.(unsavedReporter); ok {
+			names = append(names, reporter.UnsavedTabs()...)
+		}
+		if reporter, ok := screen.(unsavedFileReporter); ok {
+			for _, path := range reporter.UnsavedFiles() {
+				a.unsavedFiles[path] = true
+			}
+		}
+	}
+	return names
+}
+
+func unsavedDescription(names []string) string {
+	header := "Unsaved changes in 1 file:"
+	if len(names) != 1 {
+		header = fmt.Sprintf("Unsaved changes in %d files:", len(names))
+	}
+	lines := []string{header}
+	for i, name := range names {
+		if i == maxQuitListedFiles {
+			lines = append(lines, fmt.Sprintf("  …and %d more", len(names)-i))
+			break
+		}
+		lines = append(lines, "  "+name)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// handleQuitChoice выполняет выбранный вариант. Если сохранить хотя бы одну
+// вкладку не удалось, выход отменяется, а причина видна на экране проекта.
+func (a *App) handleQuitChoice(msg quitChoiceMsg) tea.Cmd {
+	switch msg.choice {
+	case quitSaveAll:
+		if err := a.saveAllUnsaved(); err != nil {
+			if notifier, ok := a.screens[ProjectScreen].(statusNotifier); ok {
+				notifier.Notify(fmt.Sprintf("Save failed: %v; quit aborted", err))
+			}
+			return a.router.SwitchTo(ProjectScreen)
+		}
+	case quitDiscard:
+	default:
+		return nil
+	}
+	a.saveSession()
+	return tea.Quit
+}
+
+func (a *App) saveAllUnsaved() error {
+	for screenType := ProjectScreen; screenType <= ProjectPickerScreen; screenType++ {
+		saver, ok := a.screens[screenType].(allSaver)
+		if !ok {
+			continue
+		}
+		if err := saver.SaveAllTabs(); err != nil {
+			return err
+		}
+	}
+	if unsaved := a.collectUnsaved(); len(unsaved) > 0 {
+		return fmt.Errorf("%s still unsaved", strings.Join(unsaved, ", "))
+	}
+	return nil
+}
@@ -18,13 +18,9 @@ func NewScreenRouter(app *App) *ScreenRouter {
 	}
 }
 
-// SwitchTo переключается на указанный экран
+// SwitchTo переключается на указанный экран. История меняется, только
+// когда переход состоялся: экран с несохранёнными правками может его отклонить.
 func (r *ScreenRouter) SwitchTo(screenType ScreenType) tea.Cmd {
-	// Добавляем текущий экран в историю
-	if len(r.history) == 0 || r.history[len(r.history)-1] != r.app.currentScreen {
-		r.history = append(r.history, r.app.currentScreen)
-	}
-
 	return func() tea.Msg {
 		return ScreenSwitchMsg{ScreenType: screenType}
 	}
@@ -48,12 +44,29 @@ func (r *ScreenRouter) GoBack() tea.Cmd {
 		return nil
 	}
 
-	// Берем последний экран из истории
+	// Берем последний экран из истории; снимется он при переходе
 	lastScreen := r.history[len(r.history)-1]
-	r.history = r.history[:len(r.history)-1]
 
 	return func() tea.Msg {
-		return ScreenSwitchMsg{ScreenType: lastScreen}
+		return ScreenSwitchMsg{ScreenType: lastScreen, back: true}
+	}
+}
+
+// commit записывает состоявшийся переход с экрана from в историю. Палитра
+// в историю не попадает: «назад» после её пункта ведёт к экрану, с
+// которого её открыли.
+func (r *ScreenRouter) commit(from ScreenType, msg ScreenSwitchMsg) {
+	if msg.back {
+		if n := len(r.history); n > 0 && r.history[n-1] == msg.ScreenType {
+			r.history = r.history[:n-1]
+		}
+		return
+	}
+	if from == CommandPaletteScreen {
+		return
+	}
+	if len(r.history) == 0 || r.history[len(r.history)-1] != from {
+		r.history = append(r.history, from)
 	}
 }
 
@@ -61,9 +74,11 @@ func (r *ScreenRouter) GoBack() tea.Cmd {
 func (r *ScreenRouter) getNextScreen(current ScreenType) ScreenType {
 	screens := []ScreenType{
 		ProjectScreen,
+		DiagnosticsScreen,
 		BuildScreen,
 		FixModeScreen,
 		SettingsScreen,
+		LogsScreen,
 		HelpScreen,
 	}
 
@@ -80,9 +95,11 @@ func (r *ScreenRouter) getNextScreen(current ScreenType) ScreenType {
 func (r *ScreenRouter) getPreviousScreen(current ScreenType) ScreenType {
 	screens := []ScreenType{
 		ProjectScreen,
+		DiagnosticsScreen,
 		BuildScreen,
 		FixModeScreen,
 		SettingsScreen,
+		LogsScreen,
 		HelpScreen,
 	}
 
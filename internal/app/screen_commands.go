@@ -0,0 +1,83 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// screenCommander — экран с командами в реестре; клавиши команд берутся
+// из секции keybindings.<экран> конфига.
+type screenCommander interface {
+	// CommandKeysActive сообщает, что нажатие сейчас можно считать командой
+	// экрана, а не вводом (текст в редакторе, открытый диалог).
+	CommandKeysActive() bool
+	RunScreenCommand(action string) tea.Cmd
+}
+
+// registerScreenCommands регистрирует команды экранов с клавишами из
+// keybindings.<экран>.<действие>; ID команды совпадает с именем привязки.
+func (a *App) registerScreenCommands() {
+	kb := a.config.Keybindings
+	regScreen := func(scope, action, title string) {
+		screen, ok := screenByName(scope)
+		if !ok {
+			return
+		}
+		id := scope + "." + action
+		a.commands.Register(&Command{
+			ID:      id,
+			Title:   title,
+			Key:     kb[id],
+			Binding: id,
+			Screen:  &screen,
+			Enabled: func(a *App) bool {
+				_, ok := a.screens[screen].(screenCommander)
+				return ok && a.commandScreen() == screen
+			},
+			Run: func(a *App) tea.Cmd {
+				if commander, ok := a.screens[screen].(screenCommander); ok {
+					return commander.RunScreenCommand(action)
+				}
+				return nil
+			},
+		})
+	}
+
+	regScreen("project", "new_file", "New File…")
+	regScreen("project", "new_dir", "New Directory…")
+	regScreen("project", "rename", "Rename…")
+	regScreen("project", "rename_pattern", "Rename with Pattern…")
+	regScreen("project", "delete", "Delete")
+	regScreen("project", "copy", "Copy Entry")
+	regScreen("project", "cut", "Cut Entry")
+	regScreen("project", "paste", "Paste Entry")
+	regScreen("project", "duplicate", "Duplicate Entry")
+	regScreen("project", "copy_relative_path", "Copy Relative Path")
+	regScreen("project", "toggle_hidden", "Toggle Hidden Entries")
+	regScreen("project", "toggle_ignored", "Toggle Gitignored Entries")
+	regScreen("project", "filter_surge", "Toggle .sg Filter")
+	regScreen("project", "clear_filters", "Clear Tree Filters")
+	regScreen("project", "mark", "Mark Entry")
+	regScreen("project", "clear_marks", "Clear Marks")
+	regScreen("project", "format_project", "Format Project")
+	regScreen("project", "init_project", "Init Project")
+	regScreen("project", "diag_here", "Run Diagnostics in Directory")
+	regScreen("project", "fix_here", "Fix All in Directory")
+	regScreen("project", "diag_legend", "Diagnostics Legend")
+	regScreen("project", "open_file", "Open Selected File")
+	regScreen("project", "follow_tab", "Toggle Follow Active Tab")
+}
+
+// commandScreen — экран, к которому относится команда: при открытой палитре
+// это экран, с которого её открыли.
+func (a *App) commandScreen() ScreenType {
+	if a.currentScreen == CommandPaletteScreen {
+		return a.paletteOrigin
+	}
+	return a.currentScreen
+}
+
+// screenKeysActive сообщает, что текущий экран принимает клавиши своих команд.
+func (a *App) screenKeysActive() bool {
+	commander, ok := a.getCurrentScreen().(screenCommander)
+	return ok && commander.CommandKeysActive()
+}
@@ -0,0 +1,54 @@
+package app
+
+import "strings"
+
+func (a *App) screenTitle(screen ScreenType) string {
+	switch screen {
+	case ProjectScreen:
+		return "Project"
+	case EditorScreen:
+		return "Editor"
+	case DiagnosticsScreen:
+		return "Diagnostics"
+	case BuildScreen:
+		return "Build"
+	case FixModeScreen:
+		return "Fix Mode"
+	case CommandPaletteScreen:
+		return "Command Palette"
+	case SettingsScreen:
+		return "Settings"
+	case HelpScreen:
+		return "Help"
+	case LogsScreen:
+		return "Logs"
+	case ProjectPickerScreen:
+		return "Open Project"
+	default:
+		return "Unknown"
+	}
+}
+
+// screenByName находит экран по имени из конфига (например, "diagnostics" или "fix_mode").
+func screenByName(name string) (ScreenType, bool) {
+	switch strings.ToLower(name) {
+	case "project", "workspace":
+		return ProjectScreen, true
+	case "editor":
+		return EditorScreen, true
+	case "diagnostics", "diag":
+		return DiagnosticsScreen, true
+	case "build":
+		return BuildScreen, true
+	case "fix", "fix_mode", "fixes":
+		return FixModeScreen, true
+	case "settings":
+		return SettingsScreen, true
+	case "help":
+		return HelpScreen, true
+	case "logs":
+		return LogsScreen, true
+	default:
+		return 0, false
+	}
+}
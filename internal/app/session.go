@@ -0,0 +1,71 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+	"surge-tui/internal/ui/screens"
+)
+
+// sessionScreenName — имя экрана для файла сессии. Восстанавливаются только
+// экраны, которые имеют смысл сразу после запуска; остальные пишутся как project.
+func sessionScreenName(screen ScreenType) string {
+	switch screen {
+	case DiagnosticsScreen:
+		return "diagnostics"
+	case BuildScreen:
+		return "build"
+	default:
+		return "project"
+	}
+}
+
+func (a *App) sessionEnabled() bool {
+	return a.config != nil && a.config.Startup.RestoreSession
+}
+
+// restoreSession открывает вкладки и экран прошлого запуска проекта.
+// Вызывается один раз на проект, после загрузки дерева.
+func (a *App) restoreSession() tea.Cmd {
+	if a.sessionRestored || !a.sessionEnabled() {
+		a.sessionRestored = true
+		return nil
+	}
+	a.sessionRestored = true
+	session, err := config.LoadSession(a.projectPath)
+	if err != nil {
+		return func() tea.Msg {
+			return ErrorMsg{Error: fmt.Errorf("session not restored: %w", err)}
+		}
+	}
+	if session == nil {
+		return nil
+	}
+	cmd := a.deliverTo(ProjectScreen, screens.RestoreSessionMsg{Session: session})
+	screen, ok := screenByName(session.Screen)
+	if !ok || screen == ProjectScreen || a.currentScreen != ProjectScreen {
+		return cmd
+	}
+	if ps, ok := a.screens[ProjectScreen].(*screens.ProjectScreenReal); ok && ps.DialogVisible() {
+		return cmd // вопрос о копии вкладки не должен оказаться на скрытом экране
+	}
+	return tea.Batch(cmd, a.router.SwitchTo(screen))
+}
+
+// saveSession записывает сессию текущего проекта с экраном, на котором
+// пользователь находится (для палитры — экран, с которого её открыли).
+func (a *App) saveSession() {
+	if !a.sessionEnabled() {
+		return
+	}
+	ps, ok := a.screens[ProjectScreen].(*screens.ProjectScreenReal)
+	if !ok || ps == nil {
+		return
+	}
+	screen := a.currentScreen
+	if screen == CommandPaletteScreen {
+		screen = a.paletteOrigin
+	}
+	ps.SaveSession(sessionScreenName(screen))
+}
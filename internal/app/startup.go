@@ -0,0 +1,117 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/ui/screens"
+)
+
+// DisableStartupActions отключает действия запуска из конфига (флаг --no-startup-actions).
+func (a *App) DisableStartupActions() {
+	a.startupDone = true
+}
+
+// runStartupActions выполняет config.Startup.Actions по порядку.
+// Ошибка одного действия превращается в ErrorMsg и не прерывает остальные.
+func (a *App) runStartupActions() tea.Cmd {
+	if a.startupDone || a.config == nil {
+		return nil
+	}
+	a.startupDone = true
+
+	var cmds []tea.Cmd
+	for _, action := range a.config.Startup.Actions {
+		cmd, err := a.startupAction(action)
+		if err != nil {
+			cmds = append(cmds, startupWarning(action, err))
+			continue
+		}
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	return tea.Sequence(cmds...)
+}
+
+// startupAction переводит строку действия в команду приложения.
+func (a *App) startupAction(action string) (tea.Cmd, error) {
+	kind, arg, _ := strings.Cut(strings.TrimSpace(action), ":")
+	arg = strings.TrimSpace(arg)
+
+	switch strings.ToLower(kind) {
+	case "open":
+		return a.startupOpen(arg)
+	case "diagnostics", "diag":
+		if a.surgeClient == nil {
+			return nil, fmt.Errorf("surge client is not configured")
+		}
+		return screens.CollectDiagnostics(a.surgeClient, a.projectPath), nil
+	case "screen":
+		screen, ok := screenByName(arg)
+		if !ok {
+			return nil, fmt.Errorf("unknown screen %q", arg)
+		}
+		return a.router.SwitchTo(screen), nil
+	default:
+		return nil, fmt.Errorf("unknown action")
+	}
+}
+
+// startupOpen разбирает "path[:line[:col]]" относительно корня проекта.
+func (a *App) startupOpen(arg string) (tea.Cmd, error) {
+	path, line, col := splitLocation(arg)
+	if path == "" {
+		return nil, fmt.Errorf("missing file path")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(a.projectPath, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+	msg := screens.OpenLocationMsg{FilePath: path, Line: line, Column: col}
+	return func() tea.Msg { return msg }, nil
+}
+
+// splitLocation отделяет необязательные ":line" и ":col" от пути.
+func splitLocation(arg string) (string, int, int) {
+	var nums []int
+	for len(nums) < 2 {
+		i := strings.LastIndex(arg, ":")
+		if i < 0 {
+			break
+		}
+		n, err := strconv.Atoi(arg[i+1:])
+		if err != nil || n < 1 {
+			break
+		}
+		nums = append([]int{n}, nums...)
+		arg = arg[:i]
+	}
+	line, col := 1, 1
+	if len(nums) > 0 {
+		line = nums[0]
+	}
+	if len(nums) > 1 {
+		col = nums[1]
+	}
+	return arg, line, col
+}
+
+func startupWarning(action string, err error) tea.Cmd {
+	return func() tea.Msg {
+		return ErrorMsg{Error: fmt.Errorf("startup action %q skipped: %w", action, err)}
+	}
+}
@@ -0,0 +1,209 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/ui/screens"
+)
+
+const statusBarSeparator = " | "
+
+// statusSegment — часть статус-бара; клик по ней выполняет action (nil —
+// сегмент не кликабелен). На узком окне сегменты с меньшим priority
+// убираются первыми.
+type statusSegment struct {
+	text     string
+	priority int
+	action   func(a *App) tea.Cmd
+}
+
+// statusRegion — колонки сегмента на экране, [start, end).
+type statusRegion struct {
+	start, end int
+	action     func(a *App) tea.Cmd
+}
+
+// editorStatusReporter — экран с вкладками редактора: имя активного файла,
+// признак правок и позиция курсора.
+type editorStatusReporter interface {
+	EditorStatus() (screens.EditorStatus, bool)
+}
+
+func (a *App) statusSegments() []statusSegment {
+	surge := "Surge: unknown"
+	switch {
+	case a.surgeChecking && (!a.surgeChecked || a.surgeAnnounce):
+		// фоновые проверки идут молча, чтобы сегмент не мигал раз в минуту
+		surge = "Surge: checking…"
+	case a.surgeAvailable && a.surgeVersion != "":
+		surge = "Surge: " + a.surgeVersion
+	case a.surgeAvailable:
+		surge = "Surge: available"
+	default:
+		surge = "Surge: not found"
+	}
+	keyLabel := func(id, fallback string) string {
+		if a.config != nil && a.config.Keybindings != nil {
+			if key := strings.TrimSpace(a.config.Keybindings[id]); key != "" {
+				return prettifyKey(key)
+			}
+		}
+		return prettifyKey(fallback)
+	}
+	help := fmt.Sprintf("%s Quit • %s Commands • %s Switch Screens",
+		keyLabel("quit", "ctrl+q"),
+		keyLabel("command_palette", "ctrl+p"),
+		keyLabel("switch_screen", "tab"),
+	)
+
+	segments := []statusSegment{
+		{text: "[" + a.screenTitle(a.currentScreen) + "]", priority: 2, action: (*App).openCommandPalette},
+		{text: a.projectLabel(), priority: 5, action: func(a *App) tea.Cmd { return a.commands.Run("open_project", a) }},
+	}
+	if reporter, ok := a.screens[ProjectScreen].(editorStatusReporter); ok {
+		if status, ok := reporter.EditorStatus(); ok {
+			name := status.Name
+			if status.Dirty {
+				name += " ●"
+			}
+			segments = append(segments, statusSegment{text: name, priority: 7, action: func(a *App) tea.Cmd {
+				return a.router.SwitchTo(ProjectScreen)
+			}})
+			// позиция курсора нужна, только пока редактор перед глазами
+			if a.currentScreen == ProjectScreen && status.Focused {
+				segments = append(segments, statusSegment{
+					text:     fmt.Sprintf("Ln %d, Col %d", status.Line, status.Column),
+					priority: 3,
+				})
+			}
+		}
+	}
+	if a.diagnostics != nil {
+		segments = append(segments, statusSegment{
+			text:     fmt.Sprintf("✖ %d ⚠ %d", a.diagErrors, a.diagWarnings),
+			priority: 6,
+			action:   func(a *App) tea.Cmd { return a.router.SwitchTo(DiagnosticsScreen) },
+		})
+	}
+	return append(segments,
+		statusSegment{text: surge, priority: 4, action: (*App).recheckSurge},
+		statusSegment{text: help, priority: 1, action: (*App).showHelpOverlay},
+	)
+}
+
+// visibleStatusSegments убирает сегменты с наименьшим приоритетом, пока
+// строка не поместится в width колонок; последний оставшийся сегмент
+// укорачивается многоточием. width <= 0 — ширина ещё неизвестна.
+func (a *App) visibleStatusSegments(width int) []statusSegment {
+	segments := a.statusSegments()
+	if width <= 0 {
+		return segments
+	}
+	available := max(width-2, 1) // Padding(0, 1) статус-бара
+	for len(segments) > 1 && statusTextWidth(segments) > available {
+		lowest := 0
+		for i, segment := range segments {
+			if segment.priority < segments[lowest].priority {
+				lowest = i
+			}
+		}
+		segments = slices.Delete(segments, lowest, lowest+1)
+	}
+	if len(segments) == 1 && lipgloss.Width(segments[0].text) > available {
+		segments[0].text = truncateStatus(segments[0].text, available)
+	}
+	return segments
+}
+
+func statusTextWidth(segments []statusSegment) int {
+	width := 0
+	for i, segment := range segments {
+		if i > 0 {
+			width += lipgloss.Width(statusBarSeparator)
+		}
+		width += lipgloss.Width(segment.text)
+	}
+	return width
+}
+
+// truncateStatus укорачивает текст до width колонок, заканчивая многоточием.
+func truncateStatus(text string, width int) string {
+	runes := []rune(text)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// renderStatusBar отрисовывает статус-бар
+func (a *App) renderStatusBar() string {
+	segments := a.visibleStatusSegments(a.theme.Width())
+	texts := make([]string, len(segments))
+	for i, segment := range segments {
+		texts[i] = segment.text
+	}
+	return a.theme.StatusBar(strings.Join(texts, statusBarSeparator))
+}
+
+// statusRegions считает колонки сегментов по текущей ширине окна, поэтому
+// после resize или смены текста области клика всегда совпадают с отрисовкой.
+func (a *App) statusRegions() []statusRegion {
+	width := a.theme.Width()
+	x := 1 // Padding(0, 1) статус-бара
+	var regions []statusRegion
+	for i, segment := range a.visibleStatusSegments(width) {
+		if i > 0 {
+			x += lipgloss.Width(statusBarSeparator)
+		}
+		end := x + lipgloss.Width(segment.text)
+		if width > 0 {
+			end = min(end, width)
+		}
+		if x < end && segment.action != nil {
+			regions = append(regions, statusRegion{start: x, end: end, action: segment.action})
+		}
+		x += lipgloss.Width(segment.text)
+	}
+	return regions
+}
+
+// handleStatusBarMouse обрабатывает клик по статус-бару (последняя строка окна).
+// Движение и отпускание кнопки уходят экрану: перетаскивание могло начаться в нём.
+func (a *App) handleStatusBarMouse(msg tea.MouseMsg) (bool, tea.Cmd) {
+	height := a.theme.Height()
+	if height <= 0 || msg.Y != height-1 {
+		return false, nil
+	}
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return false, nil
+	}
+	for _, region := range a.statusRegions() {
+		if msg.X >= region.start && msg.X < region.end {
+			return true, region.action(a)
+		}
+	}
+	return true, nil
+}
+
+// showHelpOverlay показывает полную справку текущего экрана.
+func (a *App) showHelpOverlay() tea.Cmd {
+	screen := a.getCurrentScreen()
+	if screen == nil || a.helpOverlay == nil {
+		return nil
+	}
+	a.helpOverlay.Show("Help: "+screen.Title(), screen.FullHelp(), a.theme.Height()/2)
+	return nil
+}
+
+// projectLabel формирует подпись проекта для статус-бара
+func (a *App) projectLabel() string {
+	if a.projectPath == "" {
+		return "Project: (none)"
+	}
+	return "Project: " + filepath.Base(a.projectPath)
+}
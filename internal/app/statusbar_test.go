@@ -0,0 +1,117 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/config"
+	"surge-tui/internal/core/surge/testsupport"
+	"surge-tui/internal/ui/screens"
+)
+
+// editorScreen отдаёт статус-бару состояние активной вкладки.
+type editorScreen struct {
+	*screens.PlaceholderScreen
+	status screens.EditorStatus
+}
+
+func (s *editorScreen) EditorStatus() (screens.EditorStatus, bool) { return s.status, true }
+
+// newStatusApp — приложение со всеми сегментами статус-бара: вкладка с
+// курсором, счётчики диагностик и версия surge.
+func newStatusApp(t *testing.T) *App {
+	t.Helper()
+	a := NewWithRunner(config.DefaultConfig(), t.TempDir(), testsupport.NewFakeRunner(nil))
+	a.screens[ProjectScreen] = &editorScreen{
+		PlaceholderScreen: screens.NewPlaceholderScreen("Project"),
+		status:            screens.EditorStatus{Name: "main.sg", Dirty: true, Line: 12, Column: 4, Focused: true},
+	}
+	a.currentScreen = ProjectScreen
+	a.diagnostics = map[string][]screens.EditorDiagnostic{}
+	a.diagErrors, a.diagWarnings = 2, 5
+	a.surgeChecking = false
+	a.surgeAvailable, a.surgeVersion = true, "0.9.1"
+	return a
+}
+
+func segmentPriorities(segments []statusSegment) []int {
+	var out []int
+	for _, s := range segments {
+		out = append(out, s.priority)
+	}
+	return out
+}
+
+// По мере сужения окна сегменты уходят строго по возрастанию приоритета, а
+// оставшиеся сохраняют порядок.
+func TestStatusSegmentsElideByPriority(t *testing.T) {
+	a := newStatusApp(t)
+	all := a.statusSegments()
+	if got := segmentPriorities(all); !reflect.DeepEqual(got, []int{2, 5, 7, 3, 6, 4, 1}) {
+		t.Fatalf("segments = %v, want every segment", got)
+	}
+
+	full := statusTextWidth(all) + 2
+	if got := a.visibleStatusSegments(full); len(got) != len(all) {
+		t.Errorf("full width dropped segments: %v", segmentPriorities(got))
+	}
+	if got := a.visibleStatusSegments(0); len(got) != len(all) {
+		t.Errorf("unknown width dropped segments: %v", segmentPriorities(got))
+	}
+
+	// ожидаемый порядок ухода: помощь, экран, курсор, surge, проект, диагностики
+	dropOrder := []int{1, 2, 3, 4, 5, 6}
+	for width := full - 1; width > 0; width-- {
+		got := a.visibleStatusSegments(width)
+		want := segmentPriorities(all)
+		for _, p := range dropOrder[:len(all)-len(got)] {
+			i := 0
+			for want[i] != p {
+				i++
+			}
+			want = append(want[:i:i], want[i+1:]...)
+		}
+		if !reflect.DeepEqual(segmentPriorities(got), want) {
+			t.Fatalf("width %d: segments %v, want %v", width, segmentPriorities(got), want)
+		}
+		if w := statusTextWidth(got); w > max(width-2, 1) {
+			t.Fatalf("width %d: text is %d columns wide", width, w)
+		}
+	}
+}
+
+// Последний сегмент укорачивается многоточием, а не исчезает.
+func TestStatusLastSegmentTruncated(t *testing.T) {
+	a := newStatusApp(t)
+	got := a.visibleStatusSegments(8)
+	if len(got) != 1 || got[0].priority != 7 {
+		t.Fatalf("segments = %v, want only the file", segmentPriorities(got))
+	}
+	if got[0].text != "main.…" {
+		t.Errorf("text = %q, want %q", got[0].text, "main.…")
+	}
+}
+
+func TestTruncateStatus(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"Project: surge", 8, "Project…"},
+		{"✖ 2 ⚠ 5", 4, "✖ 2…"},
+		{"файл.sg", 5, "файл…"},
+		{"漢字テキスト", 5, "漢字…"},
+		{"abc", 1, "…"},
+	}
+	for _, tt := range tests {
+		got := truncateStatus(tt.text, tt.width)
+		if got != tt.want {
+			t.Errorf("truncateStatus(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+		if w := lipgloss.Width(got); w > max(tt.width, 1) {
+			t.Errorf("truncateStatus(%q, %d) is %d columns wide", tt.text, tt.width, w)
+		}
+	}
+}
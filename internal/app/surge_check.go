@@ -0,0 +1,207 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/logging"
+	"surge-tui/internal/ui/screens"
+)
+
+// Паузы ленивой перепроверки surge: после каждой неудачи пауза удваивается,
+// чтобы команды, которым нужен surge, не запускали exec на каждое нажатие.
+const (
+	surgeRetryMin = 5 * time.Second
+	surgeRetryMax = 5 * time.Minute
+)
+
+// surgePollInterval — фоновая проверка: surge, установленный или удалённый
+// после запуска, замечается без действий пользователя.
+const surgePollInterval = time.Minute
+
+type surgePollMsg struct{}
+
+// surgeRetrier — экран, который после появления surge повторяет то, что
+// не удалось без него.
+type surgeRetrier interface {
+	RetryAfterSurge() tea.Cmd
+}
+
+// surgeBinarySetter — клиент surge, которому можно сменить путь к бинарю.
+type surgeBinarySetter interface {
+	SetBinaryPath(path string)
+}
+
+// surgeTimeoutSetter — клиент surge с настраиваемым таймаутом запусков.
+type surgeTimeoutSetter interface {
+	SetTimeout(timeout time.Duration)
+}
+
+// surgeStallSetter — клиент surge, предупреждающий о долгом молчании запуска.
+type surgeStallSetter interface {
+	SetStallThreshold(threshold time.Duration)
+}
+
+// startSurgeCheck запускает проверку surge; результат предыдущей незавершённой
+// проверки будет отброшен. announce — сообщить результат, даже если он не
+// изменился.
+func (a *App) startSurgeCheck(announce bool) tea.Cmd {
+	a.surgeCheckSeq++
+	a.surgeChecking = true
+	a.surgeAnnounce = announce
+	return a.checkSurgeAvailability(a.surgeCheckSeq)
+}
+
+// scheduleSurgePoll заводит следующую фоновую проверку. Цепочка одна на всё
+// время работы: её начинает Init, продолжает handleSurgePoll.
+func (a *App) scheduleSurgePoll() tea.Cmd {
+	return tea.Tick(surgePollInterval, func(time.Time) tea.Msg { return surgePollMsg{} })
+}
+
+func (a *App) handleSurgePoll() tea.Cmd {
+	var check tea.Cmd
+	if !a.surgeChecking {
+		check = a.startSurgeCheck(false)
+	}
+	return tea.Batch(check, a.scheduleSurgePoll())
+}
+
+// recheckSurge повторно проверяет доступность surge по запросу пользователя
+// (палитра, клик в статус-баре).
+func (a *App) recheckSurge() tea.Cmd {
+	if a.surgeChecking {
+		return nil
+	}
+	return a.startSurgeCheck(true)
+}
+
+// lazySurgeRecheck перепроверяет отсутствующий surge перед действием, которому
+// он нужен, но не чаще, чем позволяет пауза после прошлой неудачи.
+func (a *App) lazySurgeRecheck() tea.Cmd {
+	if a.surgeAvailable || a.surgeChecking || time.Now().Before(a.surgeRetryAt) {
+		return nil
+	}
+	return a.startSurgeCheck(false)
+}
+
+// setSurgeBinary переключает клиент на новый путь из настроек и сразу
+// проверяет его.
+func (a *App) setSurgeBinary(path string) tea.Cmd {
+	setter, ok := a.surgeClient.(surgeBinarySetter)
+	if !ok {
+		return nil
+	}
+	setter.SetBinaryPath(path)
+	a.surgeRetryAt, a.surgeRetryDelay = time.Time{}, 0
+	return a.startSurgeCheck(true)
+}
+
+// handleSurgeAvailability применяет результат проверки и сообщает, если
+// surge появился или пропал, а также если его нет уже при запуске.
+func (a *App) handleSurgeAvailability(msg SurgeAvailabilityMsg) tea.Cmd {
+	if msg.seq != a.surgeCheckSeq {
+		return nil // за это время сменился путь или началась новая проверка
+	}
+	first := !a.surgeChecked
+	changed := !first && msg.Available != a.surgeAvailable
+	announce := a.surgeAnnounce || changed || (first && !msg.Available)
+
+	a.surgeChecking = false
+	a.surgeChecked = true
+	a.surgeAnnounce = false
+	a.surgeAvailable = msg.Available
+	a.surgeVersion = msg.Version
+	a.surgeErr = msg.Err
+	if ps, ok := a.screens[ProjectScreen].(*screens.ProjectScreenReal); ok {
+		ps.SetSurgeAvailable(msg.Available)
+	}
+
+	if msg.Available {
+		a.surgeRetryAt, a.surgeRetryDelay = time.Time{}, 0
+	} else {
+		a.surgeRetryDelay = min(max(a.surgeRetryDelay*2, surgeRetryMin), surgeRetryMax)
+		a.surgeRetryAt = time.Now().Add(a.surgeRetryDelay)
+	}
+	var retry tea.Cmd
+	if changed && msg.Available {
+		retry = a.surgeBecameAvailable()
+	}
+	if first {
+		// отчёт запуска ждал результата surge
+		retry = tea.Batch(retry, a.reportHealth(true))
+	}
+	if !announce {
+		return retry
+	}
+	level := logging.LevelInfo
+	if !msg.Available {
+		level = logging.LevelError
+	}
+	return tea.Batch(retry, a.notify(level, a.surgeStatusText(msg)))
+}
+
+// surgeBecameAvailable обновляет открытую палитру (команды surge становятся
+// доступны) и даёт текущему экрану повторить неудавшуюся без surge загрузку.
+// Остальные экраны получают сообщения, только пока они на экране, и сами
+// перезагружаются при входе.
+func (a *App) surgeBecameAvailable() tea.Cmd {
+	if palette, ok := a.screens[CommandPaletteScreen].(*screens.CommandPaletteScreen); ok && palette != nil {
+		palette.Refresh()
+	}
+	if retrier, ok := a.getCurrentScreen().(surgeRetrier); ok {
+		return retrier.RetryAfterSurge()
+	}
+	return nil
+}
+
+func (a *App) surgeStatusText(msg SurgeAvailabilityMsg) string {
+	switch {
+	case msg.Available && msg.Version != "":
+		return "Surge found: " + msg.Version
+	case msg.Available:
+		return "Surge found"
+	case msg.Err != nil:
+		return "Surge not found: " + msg.Err.Error()
+	}
+	return "Surge not found"
+}
+
+// SurgeAvailabilityMsg — результат проверки surge.
+type SurgeAvailabilityMsg struct {
+	Available bool
+	Version   string
+	Err       error
+	seq       int // номер проверки (surgeCheckSeq)
+}
+
+// checkSurgeAvailability проверяет наличие surge и версию
+func (a *App) checkSurgeAvailability(seq int) tea.Cmd {
+	client := a.surgeClient
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if client == nil {
+			return SurgeAvailabilityMsg{Available: false, seq: seq}
+		}
+		err := client.CheckAvailable(ctx)
+		if err != nil {
+			return SurgeAvailabilityMsg{Available: false, Err: err, seq: seq}
+		}
+		ver, _ := client.GetVersion(ctx)
+		return SurgeAvailabilityMsg{Available: true, Version: ver, seq: seq}
+	}
+}
+
+// isSurgeProject проверяет наличие surge.toml в корне
+func (a *App) isSurgeProject() bool {
+	if a.projectPath == "" {
+		return false
+	}
+	if st, err := os.Stat(filepath.Join(a.projectPath, "surge.toml")); err == nil && !st.IsDir() {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"surge-tui/internal/config"
+	"surge-tui/internal/syntax"
+	"surge-tui/internal/ui/components"
+	"surge-tui/internal/ui/screens"
+	"surge-tui/internal/ui/styles"
+)
+
+type highlightThemeSetter interface {
+	SetHighlightTheme(theme *syntax.HighlightTheme)
+}
+
+// highlightTheme возвращает тему подсветки или nil, если подсветка выключена.
+func (a *App) highlightTheme() *syntax.HighlightTheme {
+	if a.config == nil || !a.config.Editor.SyntaxHighlight {
+		return nil
+	}
+	return a.theme.Highlight()
+}
+
+// themeFromConfig строит тему config.Theme: встроенную или из config.Themes.
+func themeFromConfig(cfg *config.Config) *styles.Theme {
+	def, ok := cfg.Themes[cfg.Theme]
+	if !ok {
+		return styles.NewTheme(cfg.Theme)
+	}
+	return styles.NewCustomTheme(cfg.Theme, styles.CustomTheme{
+		Base:       def.Base,
+		Background: def.Background,
+		Text:       def.Text,
+		Accent:     def.Accent,
+		Error:      def.Error,
+		Warning:    def.Warning,
+		Success:    def.Success,
+		Selection:  def.Selection,
+		Syntax:     def.Syntax,
+	})
+}
+
+type iconSetter interface {
+	SetIcons(icons styles.IconSet)
+}
+
+// iconSet собирает значки файлов из ui.icons и ui.file_icons с цветами темы.
+func (a *App) iconSet() styles.IconSet {
+	overrides := make(map[string]styles.IconSpec, len(a.config.UI.FileIcons))
+	for key, icon := range a.config.UI.FileIcons {
+		overrides[key] = styles.IconSpec{Glyph: icon.Glyph, Color: icon.Color}
+	}
+	return a.theme.Icons(a.config.UI.Icons, overrides)
+}
+
+// applyTheme делает theme текущей и рассылает её созданным экранам и
+// компонентам: цвета, подсветка, рамки фокуса и значки меняются сразу.
+func (a *App) applyTheme(theme *styles.Theme) {
+	theme.SetDimensions(a.theme.Width(), a.theme.Height())
+	a.theme = theme
+	components.UsePalette(theme.Palette())
+	for _, screen := range a.screens {
+		a.themeScreen(screen)
+	}
+}
+
+// themeScreen передаёт экрану тему, подсветку синтаксиса и значки.
+func (a *App) themeScreen(screen screens.Screen) {
+	if screen == nil {
+		return
+	}
+	screen.SetTheme(a.theme)
+	if setter, ok := screen.(iconSetter); ok {
+		setter.SetIcons(a.iconSet())
+	}
+	if setter, ok := screen.(highlightThemeSetter); ok {
+		setter.SetHighlightTheme(a.highlightTheme())
+	}
+}
@@ -0,0 +1,227 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/config"
+	"surge-tui/internal/platform"
+)
+
+// Пользовательские команды из секции commands конфига. inline-команда
+// выполняется в фоне, её вывод показывается в оверлее поверх экрана;
+// external-команда получает терминал (как внешний редактор), TUI ждёт её.
+// Подстановки {file}, {line}, {dir}, {project} раскрываются до запуска.
+
+const (
+	userCommandPrefix   = "user:"
+	userCommandTimeout  = 5 * time.Minute
+	userCommandMaxLines = 5000
+)
+
+var placeholderPattern = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// activeFileReporter — экран с открытым файлом для подстановки {file}.
+type activeFileReporter interface {
+	ActiveFile() (string, int)
+}
+
+// userCommandDoneMsg — команда завершилась; output пуст у external-команд.
+type userCommandDoneMsg struct {
+	command config.UserCommand
+	output  string
+	err     error
+}
+
+// registerUserCommands добавляет команды конфига в реестр. Клавиша, уже
+// занятая встроенной привязкой, не назначается: команда остаётся в палитре.
+func (a *App) registerUserCommands() {
+	for _, command := range a.config.Commands {
+		key := command.Key
+		if key != "" && a.keyTaken(key) {
+			key = ""
+		}
+		a.commands.Register(&Command{
+			ID:    userCommandPrefix + command.ID,
+			Title: command.Title,
+			Key:   key,
+			Run:   func(a *App) tea.Cmd { return a.runUserCommand(command) },
+		})
+	}
+}
+
+// keyTaken сообщает, что клавиша уже привязана к команде приложения или
+// к действию из keybindings (например, save, который обрабатывает экран).
+func (a *App) keyTaken(key string) bool {
+	if a.commands.Resolve(key, ProjectScreen) != nil {
+		return true
+	}
+	for _, bound := range a.config.Keybindings {
+		if bound != "" && platform.MatchesKey(key, bound) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) runUserCommand(command config.UserCommand) tea.Cmd {
+	line, cwd, err := a.expandUserCommand(command)
+	if err != nil {
+		a.reportUserCommand(command.Title, err.Error())
+		return nil
+	}
+
+	if command.Mode == config.CommandModeExternal {
+		cmd := platform.ShellCommand(context.Background(), line)
+		cmd.Dir = cwd
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return userCommandDoneMsg{command: command, err: err}
+		})
+	}
+
+	a.reportUserCommand(command.Title, "Running "+command.Title+"…")
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), userCommandTimeout)
+		defer cancel()
+		cmd := platform.ShellCommand(ctx, line)
+		cmd.Dir = cwd
+		output, err := cmd.CombinedOutput()
+		if ctx.Err() != nil {
+			err = fmt.Errorf("timed out after %s", userCommandTimeout)
+		}
+		return userCommandDoneMsg{command: command, output: string(output), err: err}
+	}
+}
+
+// expandUserCommand подставляет значения в команду и рабочий каталог.
+func (a *App) expandUserCommand(command config.UserCommand) (string, string, error) {
+	values := map[string]string{"project": a.projectPath}
+	if reporter, ok := a.screens[ProjectScreen].(activeFileReporter); ok {
+		if path, line := reporter.ActiveFile(); path != "" {
+			values["file"] = path
+			values["dir"] = filepath.Dir(path)
+			values["line"] = strconv.Itoa(line)
+		}
+	}
+
+	line, err := expandPlaceholders(command.Cmd, values, platform.ShellQuote)
+	if err != nil {
+		return "", "", err
+	}
+	cwd, err := expandPlaceholders(command.Cwd, values, func(s string) string { return s })
+	if err != nil {
+		return "", "", err
+	}
+	if cwd == "" {
+		cwd = a.projectPath
+	} else if !filepath.IsAbs(cwd) {
+		cwd = filepath.Join(a.projectPath, cwd)
+	}
+	return line, cwd, nil
+}
+
+// expandPlaceholders заменяет {name} значениями через quote. Неизвестное
+// имя или значение, которого сейчас нет (нет открытого файла), — ошибка:
+// команда с пустым путём могла бы сделать не то.
+func expandPlaceholders(text string, values map[string]string, quote func(string) string) (string, error) {
+	var missing error
+	expanded := placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := match[1 : len(match)-1]
+		value, ok := values[name]
+		if missing == nil {
+			switch {
+			case name != "file" && name != "line" && name != "dir" && name != "project":
+				missing = fmt.Errorf("unknown placeholder %s (use {file}, {line}, {dir} or {project})", match)
+			case !ok:
+				missing = fmt.Errorf("%s needs an open file", match)
+			}
+		}
+		return quote(value)
+	})
+	if missing != nil {
+		return "", missing
+	}
+	return expanded, nil
+}
+
+func (a *App) handleUserCommandDone(msg userCommandDoneMsg) tea.Cmd {
+	title := msg.command.Title
+	if msg.command.Mode == config.CommandModeExternal {
+		if msg.err != nil {
+			a.reportUserCommand(title, fmt.Sprintf("%s: %v", title, msg.err))
+		} else {
+			a.notifyCurrent(title + " finished")
+		}
+		return nil
+	}
+
+	status := "exit 0"
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(msg.err, &exitErr):
+		status = fmt.Sprintf("exit %d", exitErr.ExitCode())
+	case msg.err != nil:
+		status = msg.err.Error()
+	}
+	a.showUserCommandOutput(title+" — "+status, msg.output)
+	return nil
+}
+
+// reportUserCommand показывает сообщение в статусе экрана команды, а если
+// экран статуса не имеет — в оверлее.
+func (a *App) reportUserCommand(title, text string) {
+	if notifier, ok := a.commandTarget().(statusNotifier); ok {
+		notifier.Notify(text)
+		return
+	}
+	a.showUserCommandOutput(title, text)
+}
+
+// showUserCommandOutput открывает вывод в оверлее справки: последние
+// userCommandMaxLines строк без управляющих последовательностей, обрезанные
+// по ширине окна.
+func (a *App) showUserCommandOutput(title, output string) {
+	if a.helpOverlay == nil {
+		return
+	}
+	output = strings.TrimRight(output, "\n")
+	if output == "" {
+		output = "(no output)"
+	}
+	lines := strings.Split(output, "\n")
+	if len(lines) > userCommandMaxLines {
+		lines = lines[len(lines)-userCommandMaxLines:]
+	}
+	clip := lipgloss.NewStyle().MaxWidth(max(a.theme.Width()-4, 20))
+	for i, line := range lines {
+		lines[i] = clip.Render(sanitizeOutputLine(line))
+	}
+	a.helpOverlay.Show(title, lines, a.theme.Height()/2)
+}
+
+var escapePattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+// sanitizeOutputLine убирает цвета и управляющие символы, табуляцию
+// заменяет пробелами, а перезапись строки через \r оставляет последней.
+func sanitizeOutputLine(line string) string {
+	line = escapePattern.ReplaceAllString(line, "")
+	if i := strings.LastIndex(strings.TrimRight(line, "\r"), "\r"); i >= 0 {
+		line = line[i+1:]
+	}
+	line = strings.ReplaceAll(line, "\t", "    ")
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, line)
+}
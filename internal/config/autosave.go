@@ -0,0 +1,85 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Autosave — несохранённое содержимое буфера на случай падения.
+// Это не сохранение файла: сам файл не трогается до явного Save.
+type Autosave struct {
+	Path    string    `json:"path"`
+	SavedAt time.Time `json:"saved_at"`
+	Content string    `json:"content"`
+}
+
+// AutosaveDir — общий каталог копий: ~/.cache/surge-tui/autosave.
+func AutosaveDir() string {
+	return filepath.Join(getCacheDir(), "autosave")
+}
+
+// autosavePath — имя копии по хэшу абсолютного пути файла, с именем файла
+// для читаемости.
+func autosavePath(path string) string {
+	return hashedPath(AutosaveDir(), path)
+}
+
+// hashedPath — JSON-файл в dir для пути path: хэш пути и его последний элемент.
+func hashedPath(dir, path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+"-"+filepath.Base(path)+".json")
+}
+
+// WriteAutosave записывает копию буфера файла path.
+func WriteAutosave(path, content string, now time.Time) error {
+	if err := os.MkdirAll(AutosaveDir(), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(Autosave{Path: path, SavedAt: now, Content: content})
+	if err != nil {
+		return err
+	}
+	return writeReplace(autosavePath(path), data)
+}
+
+// writeReplace пишет data через временный файл, чтобы падение во время
+// записи не испортило прошлую версию.
+func writeReplace(target string, data []byte) error {
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, target)
+}
+
+// LoadAutosave возвращает копию буфера файла path или nil, если её нет.
+func LoadAutosave(path string) (*Autosave, error) {
+	data, err := os.ReadFile(autosavePath(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var autosave Autosave
+	if err := json.Unmarshal(data, &autosave); err != nil {
+		return nil, err
+	}
+	if autosave.Path != path {
+		return nil, nil // коллизия хэша: копия чужая
+	}
+	return &autosave, nil
+}
+
+// RemoveAutosave удаляет копию буфера файла path; отсутствие копии — не ошибка.
+func RemoveAutosave(path string) error {
+	err := os.Remove(autosavePath(path))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
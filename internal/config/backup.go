@@ -0,0 +1,48 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupDir — каталог резервных копий буферов, которые заменило содержимое
+// с диска: ~/.cache/surge-tui/backups. В каталог проекта копии не пишутся.
+func BackupDir() string {
+	return filepath.Join(getCacheDir(), "backups")
+}
+
+// WriteBackup сохраняет content буфера файла path под именем
+// <хэш пути>-<имя>.mine-20060102-150405 и возвращает путь копии.
+// Существующие копии не перезаписываются.
+func WriteBackup(path string, content []byte, now time.Time) (string, error) {
+	dir := BackupDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(path))
+	base := filepath.Join(dir, hex.EncodeToString(sum[:8])+"-"+filepath.Base(path)+".mine-"+now.Format("20060102-150405"))
+	backup := base
+	for i := 2; ; i++ {
+		f, err := os.OpenFile(backup, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if os.IsExist(err) {
+			backup = fmt.Sprintf("%s-%d", base, i)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = f.Write(content)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(backup)
+			return "", err
+		}
+		return backup, nil
+	}
+}
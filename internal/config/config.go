@@ -2,76 +2,115 @@ package config
 
 import (
 	"context"
+	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"unicode"
 
 	"gopkg.in/yaml.v3"
-	"surge-tui/internal/platform"
 )
 
 // Config конфигурация приложения
 type Config struct {
 	// Внешний вид
-	Theme string `yaml:"theme"` // "dark" или "light"
+	Theme  string              `yaml:"theme"`            // "dark", "light" или имя из themes
+	Themes map[string]ThemeDef `yaml:"themes,omitempty"` // пользовательские темы
 
 	// Пути
 	SurgeBinary    string `yaml:"surge_binary"`    // Путь к бинарю surge
 	DefaultProject string `yaml:"default_project"` // Путь к проекту по умолчанию
 
+	// Запуски surge
+	Surge SurgeConfig `yaml:"surge"`
+
 	// Редактор
 	Editor EditorConfig `yaml:"editor"`
 
-	// Горячие клавиши
-	Keybindings map[string]string `yaml:"keybindings"`
+	// Fix Mode
+	FixMode FixModeConfig `yaml:"fix_mode"`
+
+	// Парные тестовые файлы
+	Tests TestsConfig `yaml:"tests"`
+
+	// Интерфейс
+	UI UIConfig `yaml:"ui"`
+
+	// Горячие клавиши; команды экранов — во вложенных секциях (project: {new_file: a})
+	Keybindings KeyMap `yaml:"keybindings"`
 
 	// Производительность
 	Performance PerformanceConfig `yaml:"performance"`
 
 	// Логирование
 	Logging LoggingConfig `yaml:"logging"`
-}
 
-// EditorConfig настройки редактора
-type EditorConfig struct {
-	TabSize         int    `yaml:"tab_size"`
-	UseSpaces       bool   `yaml:"use_spaces"`
-	AutoSave        bool   `yaml:"auto_save"`
-	AutoSaveDelay   int    `yaml:"auto_save_delay"` // в секундах
-	ExternalEditor  string `yaml:"external_editor"` // команда для внешнего редактора
-	SyntaxHighlight bool   `yaml:"syntax_highlight"`
+	// Действия при запуске
+	Startup StartupConfig `yaml:"startup"`
+
+	// Управляющий сокет
+	Control ControlConfig `yaml:"control"`
+
+	// Пользовательские команды палитры
+	Commands []UserCommand `yaml:"commands"`
+
+	// Warnings — значения, которые Validate заменил значениями по умолчанию
+	Warnings []Warning `yaml:"-"`
 }
 
-// PerformanceConfig настройки производительности
-type PerformanceConfig struct {
-	MaxFileSize   int64 `yaml:"max_file_size"`   // Максимальный размер файла в байтах
-	MaxLogEntries int   `yaml:"max_log_entries"` // Максимальное количество записей в логе
-	RefreshRate   int   `yaml:"refresh_rate"`    // Частота обновления UI в миллисекундах
-	MemoryLimit   int64 `yaml:"memory_limit"`    // Лимит памяти в байтах
+// Warning — неверное значение конфига: ключ YAML и что с ним сделано.
+type Warning struct {
+	Key     string
+	Message string
 }
 
-// LoggingConfig настройки логирования
-type LoggingConfig struct {
-	Level    string `yaml:"level"`     // debug, info, warn, error
-	FilePath string `yaml:"file_path"` // Путь к файлу логов
-	MaxSize  int64  `yaml:"max_size"`  // Максимальный размер файла логов в байтах
+func (w Warning) String() string {
+	return w.Key + ": " + w.Message
 }
 
 // DefaultConfig возвращает конфигурацию по умолчанию
 func DefaultConfig() *Config {
 	return &Config{
-		Theme:          "dark",
+		Theme:          ThemeDark,
 		SurgeBinary:    "surge", // Ищем в PATH
 		DefaultProject: "",
 
+		Surge: SurgeConfig{
+			TimeoutSeconds: 60,
+			CacheSeconds:   300,
+			StallSeconds:   20,
+		},
+
 		Editor: EditorConfig{
-			TabSize:         4,
-			UseSpaces:       true,
-			AutoSave:        true,
-			AutoSaveDelay:   30,
-			ExternalEditor:  os.Getenv("EDITOR"),
-			SyntaxHighlight: true,
+			TabSize:             4,
+			UseSpaces:           true,
+			AutoIndent:          true,
+			WarnConflictMarkers: true,
+			AutoSave:            true,
+			AutoSaveDelay:       30,
+			ExternalEditor:      os.Getenv("EDITOR"),
+			SyntaxHighlight:     true,
+			WordChars:           "_",
+		},
+
+		FixMode: FixModeConfig{
+			DiffContext: 3,
+		},
+
+		Tests: TestsConfig{
+			Suffix:   "_test",
+			Dir:      "tests",
+			Template: "// Tests for {file}\n\nfn test_{name}() {\n}\n",
+		},
+
+		UI: UIConfig{
+			PanelHints:     true,
+			TreeIgnore:     []string{".git", "target", "node_modules"},
+			ProblemsHeight: 3,
+			Icons:          "nerd",
 		},
 
 		Keybindings: defaultKeybindings(),
@@ -88,6 +127,10 @@ func DefaultConfig() *Config {
 			FilePath: "",               // Будет определен автоматически
 			MaxSize:  10 * 1024 * 1024, // 10 MB
 		},
+
+		Startup: StartupConfig{
+			RestoreSession: true,
+		},
 	}
 }
 
@@ -135,19 +178,21 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
-func (c *Config) applyKeybindingDefaults(defaults map[string]string) {
-	if defaults == nil {
-		return
-	}
-	if c.Keybindings == nil {
-		c.Keybindings = make(map[string]string, len(defaults))
-	}
-	for key, value := range defaults {
-		current, ok := c.Keybindings[key]
-		if !ok || strings.TrimSpace(current) == "" {
-			c.Keybindings[key] = value
+// Clone возвращает копию конфига, не разделяющую привязки и списки с оригиналом.
+func (c *Config) Clone() *Config {
+	clone := *c
+	clone.Keybindings = maps.Clone(c.Keybindings)
+	clone.Startup.Actions = slices.Clone(c.Startup.Actions)
+	clone.Commands = slices.Clone(c.Commands)
+	clone.UI.FileIcons = maps.Clone(c.UI.FileIcons)
+	if c.Themes != nil {
+		clone.Themes = make(map[string]ThemeDef, len(c.Themes))
+		for name, def := range c.Themes {
+			def.Syntax = maps.Clone(def.Syntax)
+			clone.Themes[name] = def
 		}
 	}
+	return &clone
 }
 
 // Save сохраняет конфигурацию в файл
@@ -162,6 +207,9 @@ func (c *Config) Save(path string) error {
 	if err != nil {
 		return err
 	}
+	if len(c.Themes) == 0 {
+		data = append(data, themesExample...)
+	}
 
 	// Записываем файл
 	return os.WriteFile(path, data, 0644)
@@ -195,6 +243,11 @@ func getConfigPath() (string, error) {
 
 // getDefaultLogPath возвращает путь к файлу логов по умолчанию
 func getDefaultLogPath() string {
+	return filepath.Join(getCacheDir(), "app.log")
+}
+
+// getCacheDir возвращает каталог кэша приложения
+func getCacheDir() string {
 	// Пробуем получить XDG_CACHE_HOME
 	cacheDir := os.Getenv("XDG_CACHE_HOME")
 	if cacheDir == "" {
@@ -203,7 +256,7 @@ func getDefaultLogPath() string {
 		cacheDir = filepath.Join(homeDir, ".cache")
 	}
 
-	return filepath.Join(cacheDir, "surge-tui", "app.log")
+	return filepath.Join(cacheDir, "surge-tui")
 }
 
 // ValidateSurgeBinary проверяет доступность surge binary с таймаутом.
@@ -215,65 +268,165 @@ func (c *Config) ValidateSurgeBinary(ctx context.Context) error {
 	return cmd.Run()
 }
 
-// Validate проверяет корректность конфигурации
+// NormalizeWordChars убирает из набора символов слова пробельные символы и
+// повторы, сохраняя порядок.
+func NormalizeWordChars(chars string) string {
+	var b strings.Builder
+	for _, r := range chars {
+		if unicode.IsSpace(r) || strings.ContainsRune(b.String(), r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Validate проверяет корректность конфигурации. Неверные значения
+// заменяются значениями по умолчанию и перечисляются в c.Warnings.
 func (c *Config) Validate() error {
-	// Проверяем тему
-	if c.Theme != "dark" && c.Theme != "light" {
-		c.Theme = "dark"
+	c.Warnings = nil
+
+	// Проверяем темы: имена встроенных заняты, основа — встроенная тема
+	for name, def := range c.Themes {
+		if name == ThemeDark || name == ThemeLight || strings.TrimSpace(name) == "" {
+			c.warn("themes", "theme name %q is reserved or empty, ignored", name)
+			delete(c.Themes, name)
+			continue
+		}
+		if def.Base != ThemeLight {
+			def.Base = ThemeDark
+		}
+		c.Themes[name] = def
+	}
+	if _, custom := c.Themes[c.Theme]; !custom && c.Theme != ThemeDark && c.Theme != ThemeLight {
+		c.warn("theme", "unknown theme %q, using %s", c.Theme, ThemeDark)
+		c.Theme = ThemeDark
+	}
+
+	// Проверяем таймаут surge
+	if c.Surge.TimeoutSeconds < 0 {
+		c.warn("surge.timeout_seconds", "%d is negative, using 60", c.Surge.TimeoutSeconds)
+		c.Surge.TimeoutSeconds = 60
+	}
+	if c.Surge.CacheSeconds < 0 {
+		c.warn("surge.cache_seconds", "%d is negative, using 300", c.Surge.CacheSeconds)
+		c.Surge.CacheSeconds = 300
+	}
+	if c.Surge.StallSeconds < 0 {
+		c.warn("surge.stall_seconds", "%d is negative, using 20", c.Surge.StallSeconds)
+		c.Surge.StallSeconds = 20
 	}
 
 	// Проверяем размер табуляции
 	if c.Editor.TabSize < 1 || c.Editor.TabSize > 16 {
+		c.warn("editor.tab_size", "%d is outside 1–16, using 4", c.Editor.TabSize)
 		c.Editor.TabSize = 4
 	}
 
 	// Проверяем задержку автосохранения
 	if c.Editor.AutoSaveDelay < 1 {
+		c.warn("editor.auto_save_delay", "%d is less than 1, using 30", c.Editor.AutoSaveDelay)
 		c.Editor.AutoSaveDelay = 30
 	}
 
+	c.Editor.WordChars = NormalizeWordChars(c.Editor.WordChars)
+
+	// Проверяем контекст предпросмотра фиксов
+	if c.FixMode.DiffContext < 0 || c.FixMode.DiffContext > 50 {
+		c.warn("fix_mode.diff_context", "%d is outside 0–50, using 3", c.FixMode.DiffContext)
+		c.FixMode.DiffContext = 3
+	}
+
+	// Проверяем суффикс тестов: без него тест совпал бы с исходником
+	c.Tests.Suffix = strings.TrimSpace(c.Tests.Suffix)
+	if c.Tests.Suffix == "" || strings.ContainsAny(c.Tests.Suffix, `/\`) {
+		c.warn("tests.suffix", "%q is empty or contains a slash, using _test", c.Tests.Suffix)
+		c.Tests.Suffix = "_test"
+	}
+	c.Tests.Dir = strings.Trim(filepath.ToSlash(strings.TrimSpace(c.Tests.Dir)), "/")
+
+	// Проверяем высоту панели проблем
+	if c.UI.ProblemsHeight < 1 || c.UI.ProblemsHeight > 20 {
+		c.warn("ui.problems_height", "%d is outside 1–20, using 3", c.UI.ProblemsHeight)
+		c.UI.ProblemsHeight = 3
+	}
+
+	// Проверяем набор значков
+	if c.UI.Icons != "nerd" && c.UI.Icons != "ascii" {
+		c.warn("ui.icons", "unknown icon set %q, using nerd", c.UI.Icons)
+		c.UI.Icons = "nerd"
+	}
+
 	// Проверяем лимиты производительности
 	if c.Performance.MaxFileSize < 1024 {
+		c.warn("performance.max_file_size", "%d is less than 1024 bytes, using 10 MB", c.Performance.MaxFileSize)
 		c.Performance.MaxFileSize = 10 * 1024 * 1024
 	}
 
 	if c.Performance.RefreshRate < 10 || c.Performance.RefreshRate > 1000 {
+		c.warn("performance.refresh_rate", "%d is outside 10–1000, using 50", c.Performance.RefreshRate)
 		c.Performance.RefreshRate = 50
 	}
 
+	if c.Performance.MaxLogEntries < 10 {
+		c.warn("performance.max_log_entries", "%d is less than 10, using 1000", c.Performance.MaxLogEntries)
+		c.Performance.MaxLogEntries = 1000
+	}
+
 	// Проверяем уровень логирования
 	validLevels := map[string]bool{
 		"debug": true, "info": true, "warn": true, "error": true,
 	}
 	if !validLevels[c.Logging.Level] {
+		c.warn("logging.level", "unknown level %q, using info", c.Logging.Level)
 		c.Logging.Level = "info"
 	}
+	if c.Logging.MaxSize < 1024 {
+		c.warn("logging.max_size", "%d is less than 1024 bytes, using 10 MB", c.Logging.MaxSize)
+		c.Logging.MaxSize = 10 * 1024 * 1024
+	}
+
+	// Убираем пустые действия запуска
+	actions := c.Startup.Actions[:0]
+	for _, action := range c.Startup.Actions {
+		if action = strings.TrimSpace(action); action != "" {
+			actions = append(actions, action)
+		}
+	}
+	c.Startup.Actions = actions
+
+	c.Commands = validCommands(c.Commands)
 
 	return nil
 }
 
-func defaultKeybindings() map[string]string {
-	primary := platform.PrimaryModifierKey()
-	kb := map[string]string{
-		"quit":               primary + "+q",
-		"command_palette":    primary + "+p",
-		"help":               "f1",
-		"settings":           primary + "+comma",
-		"workspace":          "esc",
-		"fix_mode":           primary + "+f",
-		"save":               primary + "+s",
-		"build":              primary + "+b",
-		"undo":               primary + "+z",
-		"redo":               primary + "+y",
-		"external_editor":    primary + "+e",
-		"switch_screen":      "tab",
-		"switch_screen_back": "shift+tab",
-		"init_project":       primary + "+i",
-	}
-
-	if platform.IsMac() {
-		kb["redo"] = "shift+" + primary + "+z"
-	}
-
-	return kb
+func (c *Config) warn(key, format string, args ...any) {
+	c.Warnings = append(c.Warnings, Warning{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// validCommands убирает команды без id или cmd и повторы id, заполняет
+// заголовок и режим по умолчанию.
+func validCommands(commands []UserCommand) []UserCommand {
+	seen := make(map[string]bool, len(commands))
+	valid := commands[:0]
+	for _, command := range commands {
+		command.ID = strings.TrimSpace(command.ID)
+		command.Cmd = strings.TrimSpace(command.Cmd)
+		if command.ID == "" || command.Cmd == "" || seen[command.ID] {
+			continue
+		}
+		seen[command.ID] = true
+		if command.Title = strings.TrimSpace(command.Title); command.Title == "" {
+			command.Title = command.ID
+		}
+		command.Key = strings.TrimSpace(command.Key)
+		switch mode := strings.ToLower(strings.TrimSpace(command.Mode)); mode {
+		case CommandModeExternal:
+			command.Mode = mode
+		default:
+			command.Mode = CommandModeInline
+		}
+		valid = append(valid, command)
+	}
+	return valid
 }
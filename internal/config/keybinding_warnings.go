@@ -0,0 +1,46 @@
+package config
+
+import (
+	"sort"
+
+	"surge-tui/internal/platform"
+)
+
+// KeybindingWarning — привязка, которую терминал, скорее всего, не передаст приложению.
+type KeybindingWarning struct {
+	Action string
+	platform.ChordWarning
+}
+
+// KeybindingWarnings проверяет привязки по таблице ненадёжных сочетаний из platform.
+// Замена не предлагается, если она уже занята другой командой или предложена раньше.
+func (c *Config) KeybindingWarnings(term platform.Terminal) []KeybindingWarning {
+	used := make(map[string]bool, len(c.Keybindings))
+	for _, key := range c.Keybindings {
+		if canonical := platform.CanonicalKeyForLookup(key); canonical != "" {
+			used[canonical] = true
+		}
+	}
+
+	actions := make([]string, 0, len(c.Keybindings))
+	for action := range c.Keybindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	var warnings []KeybindingWarning
+	for _, action := range actions {
+		warning, risky := platform.CheckChord(c.Keybindings[action], term)
+		if !risky {
+			continue
+		}
+		suggestion := platform.CanonicalKeyForLookup(warning.Suggestion)
+		if used[suggestion] {
+			warning.Suggestion = ""
+		} else if suggestion != "" {
+			used[suggestion] = true // одну замену предлагаем одной команде
+		}
+		warnings = append(warnings, KeybindingWarning{Action: action, ChordWarning: warning})
+	}
+	return warnings
+}
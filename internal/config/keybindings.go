@@ -0,0 +1,219 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"surge-tui/internal/platform"
+)
+
+// GlobalKeyScope — область привязок, действующих на любом экране.
+const GlobalKeyScope = "global"
+
+// KeyMap — привязки клавиш: действие → клавиша. Команды экранов хранятся под
+// составным именем "экран.действие", а в YAML записываются вложенной секцией:
+//
+//	keybindings:
+//	  quit: ctrl+q
+//	  project:
+//	    new_file: a
+type KeyMap map[string]string
+
+// UnmarshalYAML раскладывает вложенные секции экранов в составные имена.
+func (m *KeyMap) UnmarshalYAML(node *yaml.Node) error {
+	var raw map[string]yaml.Node
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	if *m == nil {
+		*m = make(KeyMap, len(raw))
+	}
+	for name, value := range raw {
+		if value.Kind != yaml.MappingNode {
+			var key string
+			if err := value.Decode(&key); err != nil {
+				return fmt.Errorf("keybindings.%s: %w", name, err)
+			}
+			(*m)[name] = key
+			continue
+		}
+		var scoped map[string]string
+		if err := value.Decode(&scoped); err != nil {
+			return fmt.Errorf("keybindings.%s: %w", name, err)
+		}
+		for action, key := range scoped {
+			(*m)[name+"."+action] = key
+		}
+	}
+	return nil
+}
+
+// MarshalYAML собирает составные имена обратно во вложенные секции.
+func (m KeyMap) MarshalYAML() (any, error) {
+	out := make(map[string]any, len(m))
+	for action, key := range m {
+		scope, name := KeybindingScope(action)
+		if scope == GlobalKeyScope {
+			out[action] = key
+			continue
+		}
+		section, _ := out[scope].(map[string]string)
+		if section == nil {
+			section = make(map[string]string)
+			out[scope] = section
+		}
+		section[name] = key
+	}
+	return out, nil
+}
+
+// KeybindingScope делит имя действия на область и имя внутри неё:
+// "project.new_file" → ("project", "new_file"), "quit" → ("global", "quit").
+func KeybindingScope(action string) (scope, name string) {
+	if i := strings.IndexByte(action, '.'); i > 0 {
+		return action[:i], action[i+1:]
+	}
+	return GlobalKeyScope, action
+}
+
+// defaultScreenKeybindings — клавиши команд дерева проекта. Они срабатывают,
+// только когда фокус в дереве, поэтому могут быть одиночными буквами.
+func defaultScreenKeybindings() map[string]string {
+	return map[string]string{
+		"project.new_file":           "n",
+		"project.new_dir":            "N",
+		"project.rename":             "r",
+		"project.rename_pattern":     "R",
+		"project.delete":             "delete",
+		"project.copy":               "y",
+		"project.cut":                "x",
+		"project.paste":              "p",
+		"project.duplicate":          "D",
+		"project.copy_relative_path": "Y",
+		"project.toggle_hidden":      "h",
+		"project.toggle_ignored":     "I",
+		"project.filter_surge":       "s",
+		"project.clear_filters":      "C",
+		"project.mark":               "m",
+		"project.clear_marks":        "M",
+		"project.format_project":     "f",
+		"project.init_project":       "i",
+		"project.diag_here":          "c",
+		"project.fix_here":           "F",
+		"project.diag_legend":        "d",
+		"project.open_file":          "alt+enter",
+		"project.follow_tab":         "L",
+	}
+}
+
+// KeybindingConflict — одна клавиша у нескольких действий одной области.
+// Привязки разных областей не конфликтуют: команда экрана перекрывает
+// глобальную только на своём экране.
+type KeybindingConflict struct {
+	Scope   string
+	Key     string   // каноническая клавиша
+	Actions []string // полные имена действий по алфавиту
+}
+
+func (c KeybindingConflict) String() string {
+	return fmt.Sprintf("%s: %s bound to %s", c.Scope, platform.DisplayKey(c.Key), strings.Join(c.Actions, ", "))
+}
+
+// KeybindingConflicts находит клавиши, назначенные нескольким действиям
+// в пределах одной области. Клавиши сравниваются в каноническом виде.
+func (c *Config) KeybindingConflicts() []KeybindingConflict {
+	type slot struct{ scope, key string }
+	groups := make(map[slot][]string)
+	for action, key := range c.Keybindings {
+		canonical := platform.CanonicalKeyForLookup(key)
+		if canonical == "" {
+			continue
+		}
+		scope, _ := KeybindingScope(action)
+		s := slot{scope, canonical}
+		groups[s] = append(groups[s], action)
+	}
+
+	var conflicts []KeybindingConflict
+	for s, actions := range groups {
+		if len(actions) < 2 {
+			continue
+		}
+		slices.Sort(actions)
+		conflicts = append(conflicts, KeybindingConflict{Scope: s.scope, Key: s.key, Actions: actions})
+	}
+	slices.SortFunc(conflicts, func(a, b KeybindingConflict) int {
+		return strings.Compare(a.Actions[0], b.Actions[0])
+	})
+	return conflicts
+}
+
+// applyKeybindingDefaults дописывает отсутствующие и пустые привязки из defaults.
+func (c *Config) applyKeybindingDefaults(defaults map[string]string) {
+	if defaults == nil {
+		return
+	}
+	if c.Keybindings == nil {
+		c.Keybindings = make(map[string]string, len(defaults))
+	}
+	for key, value := range defaults {
+		current, ok := c.Keybindings[key]
+		if !ok || strings.TrimSpace(current) == "" {
+			c.Keybindings[key] = value
+		}
+	}
+}
+
+func defaultKeybindings() map[string]string {
+	primary := platform.PrimaryModifierKey()
+	kb := map[string]string{
+		"quit":               primary + "+q",
+		"command_palette":    primary + "+p",
+		"help":               "f1",
+		"settings":           "f2", // Ctrl+, терминалы не передают
+		"workspace":          "esc",
+		"fix_mode":           primary + "+f",
+		"save":               primary + "+s",
+		"build":              primary + "+b",
+		"diagnostics":        "f8",
+		"logs":               "f9",
+		"undo":               primary + "+z",
+		"redo":               primary + "+y",
+		"external_editor":    primary + "+e",
+		"switch_screen":      "tab",
+		"switch_screen_back": "shift+tab",
+		"init_project":       "", // Ctrl+I неотличим от Tab; только палитра
+		"open_project":       primary + "+o",
+		"new_scratch":        primary + "+n",
+		"quick_open":         primary + "+t", // Ctrl+O занят открытием проекта
+		"format_file":        "alt+f",
+		"copy_path":          "", // только палитра; Y в дереве копирует относительный путь
+		"copy_relative_path": "",
+		"move_file":          "", // только палитра; в редакторе — :saveas path --move
+		"copy_to_file":       "", // только палитра; в редакторе — :saveas path --copy
+		"extract_to_file":    "", // только палитра, при выделении в редакторе
+		"rename_pattern":     "", // только палитра; в дереве — R
+		"toggle_problems":    "alt+p",
+		"next_change":        "", // только палитра; в редакторе — ]c, [c и do
+		"prev_change":        "",
+		"next_symbol":        "", // только палитра; в редакторе — ]f и [f
+		"prev_symbol":        "",
+		"revert_hunk":        "",
+		"rollback_buffer":    "", // только палитра: последнее сохранение, 1/5/15 минут назад, открытие
+		"sort_lines":         "", // только палитра; можно назначить клавишу
+		"sort_lines_unique":  "",
+		"reverse_lines":      "",
+		"toggle_comment":     "ctrl+/", // терминал шлёт его как Ctrl+_
+		"jump_to_bracket":    "ctrl+]", // в нормальном режиме редактора — ещё и %
+		"toggle_test_file":   "alt+t",
+		"status_history":     "alt+m",
+		"recheck_surge":      "", // только палитра; или клик по «Surge:» в статус-баре
+		"health_report":      "", // только палитра; при запуске показывается сам, если есть проблемы
+		"go_back":            "alt+b",
+	}
+	maps.Copy(kb, defaultScreenKeybindings())
+	return kb
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Секции конфига: темы, редактор, запуски surge, интерфейс и остальные
+// настройки, которые экраны получают целиком.
+
+// Встроенные темы
+const (
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+)
+
+// ThemeDef — пользовательская тема. Цвета — #RRGGBB, #RGB или номер ANSI;
+// незаданные и неверные берутся из встроенной темы Base (dark по умолчанию).
+type ThemeDef struct {
+	Base       string `yaml:"base,omitempty"`
+	Background string `yaml:"background,omitempty"`
+	Text       string `yaml:"text,omitempty"`
+	Accent     string `yaml:"accent,omitempty"`
+	Error      string `yaml:"error,omitempty"`
+	Warning    string `yaml:"warning,omitempty"`
+	Success    string `yaml:"success,omitempty"`
+	Selection  string `yaml:"selection,omitempty"`
+	// Syntax — цвета подсветки по виду токена: text, keyword, type, literal,
+	// string, number, comment, attribute, operator, punctuation
+	Syntax map[string]string `yaml:"syntax,omitempty"`
+}
+
+// themesExample дописывается в конфиг без пользовательских тем: yaml.Marshal
+// не сохраняет комментарии, а пример должен быть под рукой.
+const themesExample = `
+# Пользовательские темы выбираются по имени в theme. Незаданные цвета
+# берутся из base (dark или light).
+#
+# themes:
+#   solarized:
+#     base: dark
+#     background: "#002B36"
+#     text: "#93A1A1"
+#     accent: "#268BD2"
+#     error: "#DC322F"
+#     warning: "#B58900"
+#     success: "#859900"
+#     selection: "#073642"
+#     syntax:
+#       keyword: "#859900"
+#       type: "#B58900"
+#       string: "#2AA198"
+#       number: "#D33682"
+#       comment: "#586E75"
+`
+
+// ThemeNames возвращает встроенные темы и затем пользовательские по алфавиту.
+func (c *Config) ThemeNames() []string {
+	names := []string{ThemeDark, ThemeLight}
+	custom := slices.Sorted(maps.Keys(c.Themes))
+	return append(names, custom...)
+}
+
+// EditorConfig настройки редактора
+type EditorConfig struct {
+	TabSize         int    `yaml:"tab_size"`
+	UseSpaces       bool   `yaml:"use_spaces"`
+	AutoIndent      bool   `yaml:"auto_indent"` // Enter повторяет отступ строки, после { и ( — на уровень глубже
+	AutoSave        bool   `yaml:"auto_save"`
+	AutoSaveDelay   int    `yaml:"auto_save_delay"` // в секундах
+	ExternalEditor  string `yaml:"external_editor"` // команда для внешнего редактора
+	SyntaxHighlight bool   `yaml:"syntax_highlight"`
+	DiagOnSave      bool   `yaml:"diag_on_save"`   // surge diag для файла после сохранения
+	FormatOnSave    bool   `yaml:"format_on_save"` // surge fmt для .sg файла после сохранения
+	// Предупреждения при сохранении из редактора; автосохранение не проверяется
+	WarnConflictMarkers bool `yaml:"warn_conflict_markers"` // маркеры конфликта слияния <<<<<<<
+	WarnErrorsOnSave    bool `yaml:"warn_errors_on_save"`   // строки с ошибками последнего surge diag
+	// WordChars — символы, которые кроме букв и цифр входят в слово:
+	// движения w/b/e, выделение двойным щелчком, Ctrl+D
+	WordChars string `yaml:"word_chars"`
+	// ExtractPlaceholder — что остаётся на месте выделения, перенесённого в
+	// новый файл; {path} — путь нового файла от корня проекта, пусто — ничего
+	ExtractPlaceholder string `yaml:"extract_placeholder"`
+}
+
+// SurgeConfig настройки запусков surge CLI
+type SurgeConfig struct {
+	TimeoutSeconds int `yaml:"timeout_seconds"` // предел одного запуска (diag, fix, fmt, init); 0 — без ограничения
+	CacheSeconds   int `yaml:"cache_seconds"`   // сколько результат diag показывается при возврате на экран без перезапуска; 0 — всегда перезапускать
+	StallSeconds   int `yaml:"stall_seconds"`   // после скольких секунд без вывода surge экран предупреждает о зависании; 0 — не предупреждать
+}
+
+// Timeout возвращает таймаут запуска surge; 0 — без ограничения.
+func (s SurgeConfig) Timeout() time.Duration {
+	return time.Duration(s.TimeoutSeconds) * time.Second
+}
+
+// StallThreshold возвращает порог молчания surge; 0 — не предупреждать.
+func (s SurgeConfig) StallThreshold() time.Duration {
+	return time.Duration(s.StallSeconds) * time.Second
+}
+
+// CacheAge возвращает срок, в течение которого результат diag считается свежим.
+func (s SurgeConfig) CacheAge() time.Duration {
+	return time.Duration(s.CacheSeconds) * time.Second
+}
+
+// FixModeConfig настройки экрана Fix Mode
+type FixModeConfig struct {
+	DiffContext int `yaml:"diff_context"` // строк контекста вокруг правки в предпросмотре
+}
+
+// TestsConfig — как искать тест исходника: foo.sg ↔ foo_test.sg рядом
+// или в зеркальном каталоге tests/ от корня проекта
+type TestsConfig struct {
+	Suffix string `yaml:"suffix"` // окончание имени теста перед расширением
+	Dir    string `yaml:"dir"`    // каталог зеркальной раскладки; пусто — только рядом
+	// Template — текст нового теста; {name} — имя исходника без расширения,
+	// {file} — путь исходника от корня проекта
+	Template string `yaml:"template"`
+}
+
+// UIConfig настройки интерфейса
+type UIConfig struct {
+	PanelHints     bool     `yaml:"panel_hints"`     // подсказки клавиш в подвале панели с фокусом
+	TreeIgnore     []string `yaml:"tree_ignore"`     // имена, которые дерево проекта не читает
+	ProblemsHeight int      `yaml:"problems_height"` // строк в панели проблем экрана проекта
+	// FollowActiveTab — дерево проекта выделяет файл активной вкладки
+	FollowActiveTab bool `yaml:"follow_active_tab"`
+	// Icons — набор значков файлов: "nerd" (Nerd Font) или "ascii"
+	Icons string `yaml:"icons"`
+	// FileIcons заменяет значки: ключ — расширение (".sg") или dir, dir_open,
+	// file, loading
+	FileIcons map[string]FileIcon `yaml:"file_icons,omitempty"`
+}
+
+// FileIcon — значок файла; Color — имя цвета темы (accent, text_dim, ...)
+// или #RRGGBB. Пустое поле оставляет значение по умолчанию.
+type FileIcon struct {
+	Glyph string `yaml:"glyph,omitempty"`
+	Color string `yaml:"color,omitempty"`
+}
+
+// PerformanceConfig настройки производительности
+type PerformanceConfig struct {
+	MaxFileSize   int64 `yaml:"max_file_size"`   // Максимальный размер файла в байтах
+	MaxLogEntries int   `yaml:"max_log_entries"` // Максимальное количество записей в логе
+	RefreshRate   int   `yaml:"refresh_rate"`    // Частота обновления UI в миллисекундах
+	MemoryLimit   int64 `yaml:"memory_limit"`    // Лимит памяти в байтах
+}
+
+// LoggingConfig настройки логирования
+type LoggingConfig struct {
+	Level    string `yaml:"level"`     // debug, info, warn, error
+	FilePath string `yaml:"file_path"` // Путь к файлу логов
+	MaxSize  int64  `yaml:"max_size"`  // Максимальный размер файла логов в байтах
+}
+
+// StartupConfig действия, выполняемые после загрузки проекта
+type StartupConfig struct {
+	// Actions выполняются по порядку: "open:path[:line[:col]]", "diagnostics",
+	// "screen:<name>"
+	Actions []string `yaml:"actions"`
+
+	// RestoreSession открывает вкладки, позиции курсора и экран прошлого
+	// запуска; действия запуска выполняются после восстановления
+	RestoreSession bool `yaml:"restore_session"`
+}
+
+// ControlConfig настройки управляющего сокета (JSON-RPC для внешних инструментов)
+type ControlConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	SocketPath string `yaml:"socket_path"` // пусто — $XDG_RUNTIME_DIR/surge-tui/control.sock
+}
+
+// Режимы пользовательских команд
+const (
+	CommandModeInline   = "inline"   // вывод собирается и показывается в оверлее
+	CommandModeExternal = "external" // команда получает терминал, TUI ждёт её завершения
+)
+
+// UserCommand — команда проекта в палитре (тесты, git status и т.п.).
+// В Cmd и Cwd подставляются {file}, {line}, {dir} и {project}.
+type UserCommand struct {
+	ID    string `yaml:"id"`
+	Title string `yaml:"title"`
+	Cmd   string `yaml:"cmd"`
+	Key   string `yaml:"key,omitempty"`
+	Cwd   string `yaml:"cwd,omitempty"`  // пусто — корень проекта; относительный путь — от корня
+	Mode  string `yaml:"mode,omitempty"` // inline (по умолчанию) или external
+}
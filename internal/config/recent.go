@@ -0,0 +1,133 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaxRecentProjects — сколько недавних проектов хранится в списке.
+const MaxRecentProjects = 10
+
+// recentProjects — файл recent_projects.yaml рядом с config.yaml.
+type recentProjects struct {
+	Projects []string `yaml:"projects"`
+}
+
+// recentProjectsPath возвращает путь к списку недавних проектов
+func recentProjectsPath() (string, error) {
+	configPath, err := getConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "recent_projects.yaml"), nil
+}
+
+// LoadRecentProjects возвращает недавние проекты, последний открытый — первым.
+// Отсутствующий файл — не ошибка, а пустой список.
+func LoadRecentProjects() ([]string, error) {
+	path, err := recentProjectsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var recent recentProjects
+	if err := yaml.Unmarshal(data, &recent); err != nil {
+		return nil, err
+	}
+	return recent.Projects, nil
+}
+
+// AddRecentProject поднимает проект в начало списка и сохраняет список.
+func AddRecentProject(project string) ([]string, error) {
+	project, err := filepath.Abs(project)
+	if err != nil {
+		return nil, err
+	}
+	// Битый файл не должен мешать запомнить проект — он просто перезаписывается
+	projects, _ := LoadRecentProjects()
+	projects = slices.DeleteFunc(projects, func(p string) bool { return p == project })
+	projects = append([]string{project}, projects...)
+	if len(projects) > MaxRecentProjects {
+		projects = projects[:MaxRecentProjects]
+	}
+
+	path, err := recentProjectsPath()
+	if err != nil {
+		return projects, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return projects, err
+	}
+	data, err := yaml.Marshal(recentProjects{Projects: projects})
+	if err != nil {
+		return projects, err
+	}
+	return projects, os.WriteFile(path, data, 0644)
+}
+
+// MaxRecentFiles — сколько недавних файлов хранится для одного проекта.
+const MaxRecentFiles = 20
+
+// recentFilesPath — недавние файлы всех проектов лежат в кэше:
+// recent_files.json, ключ — путь проекта.
+func recentFilesPath() string {
+	return filepath.Join(getCacheDir(), "recent_files.json")
+}
+
+func loadRecentFiles() (map[string][]string, error) {
+	data, err := os.ReadFile(recentFilesPath())
+	if os.IsNotExist(err) {
+		return map[string][]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	byProject := make(map[string][]string)
+	if err := json.Unmarshal(data, &byProject); err != nil {
+		return nil, err
+	}
+	return byProject, nil
+}
+
+// LoadRecentFiles возвращает недавно открытые файлы проекта, последний — первым.
+func LoadRecentFiles(project string) ([]string, error) {
+	byProject, err := loadRecentFiles()
+	if err != nil {
+		return nil, err
+	}
+	return byProject[project], nil
+}
+
+// AddRecentFile поднимает файл в начало списка проекта и сохраняет кэш.
+func AddRecentFile(project, path string) ([]string, error) {
+	// Битый кэш не должен мешать — он просто перезаписывается
+	byProject, err := loadRecentFiles()
+	if err != nil {
+		byProject = make(map[string][]string)
+	}
+	files := slices.DeleteFunc(byProject[project], func(p string) bool { return p == path })
+	files = append([]string{path}, files...)
+	if len(files) > MaxRecentFiles {
+		files = files[:MaxRecentFiles]
+	}
+	byProject[project] = files
+
+	if err := os.MkdirAll(getCacheDir(), 0755); err != nil {
+		return files, err
+	}
+	data, err := json.MarshalIndent(byProject, "", "  ")
+	if err != nil {
+		return files, err
+	}
+	return files, os.WriteFile(recentFilesPath(), data, 0644)
+}
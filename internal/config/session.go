@@ -0,0 +1,61 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Session — открытые вкладки проекта и положение в них, чтобы после
+// перезапуска вернуться туда же.
+type Session struct {
+	Project   string       `json:"project"`
+	Tabs      []SessionTab `json:"tabs"`
+	ActiveTab int          `json:"active_tab"`
+	Focus     string       `json:"focus"`  // "tree" или "editor"
+	Screen    string       `json:"screen"` // имя экрана, как в startup "screen:<name>"
+}
+
+// SessionTab — вкладка сессии; строка и колонка считаются с 1.
+type SessionTab struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Scroll int    `json:"scroll"`
+}
+
+// SessionDir — каталог сессий: ~/.cache/surge-tui/sessions.
+func SessionDir() string {
+	return filepath.Join(getCacheDir(), "sessions")
+}
+
+// LoadSession возвращает сессию проекта или nil, если её нет.
+func LoadSession(project string) (*Session, error) {
+	data, err := os.ReadFile(hashedPath(SessionDir(), project))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	if session.Project != project {
+		return nil, nil // коллизия хэша: сессия чужая
+	}
+	return &session, nil
+}
+
+// SaveSession записывает сессию проекта session.Project.
+func SaveSession(session *Session) error {
+	if err := os.MkdirAll(SessionDir(), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeReplace(hashedPath(SessionDir(), session.Project), data)
+}
@@ -0,0 +1,236 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	maxRequestSize = 1 << 20 // байт в одной строке запроса
+	dialTimeout    = 500 * time.Millisecond
+)
+
+// Handler выполняет вызов method. ctx отменяется, когда клиент отключился
+// или сервер закрывается.
+type Handler func(ctx context.Context, method string, params json.RawMessage) (any, error)
+
+// Server принимает JSON-RPC вызовы на unix-сокете.
+type Server struct {
+	path     string
+	listener net.Listener
+	handler  Handler
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// DefaultSocketPath — сокет в $XDG_RUNTIME_DIR, иначе во временном каталоге пользователя.
+func DefaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "surge-tui", "control.sock")
+	}
+	return filepath.Join(os.TempDir(), "surge-tui-"+strconv.Itoa(os.Getuid()), "control.sock")
+}
+
+// Listen открывает сокет path и начинает принимать соединения.
+// Оставшийся от упавшего процесса сокет удаляется; если на нём кто-то
+// отвечает, возвращается ошибка. Каталог сокета по умолчанию должен быть
+// личным каталогом пользователя, а сам сокет сразу создаётся с правами 0600.
+func Listen(path string, handler Handler) (*Server, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	if dir == filepath.Dir(DefaultSocketPath()) {
+		// во временном каталоге его мог заранее создать другой пользователь
+		if err := checkPrivateDir(dir); err != nil {
+			return nil, err
+		}
+	}
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	listener, err := listenPrivate(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		path:     path,
+		listener: listener,
+		handler:  handler,
+		ctx:      ctx,
+		cancel:   cancel,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.acceptLoop()
+	return s, nil
+}
+
+// checkPrivateDir проверяет, что dir — настоящий каталог (не ссылка),
+// принадлежит текущему пользователю и закрыт для остальных (0700).
+func checkPrivateDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	uid, ok := fileOwner(info)
+	if !ok {
+		return nil // владельца и права unix на этой платформе не проверить
+	}
+	if uid != os.Getuid() {
+		return fmt.Errorf("%s is owned by another user (uid %d)", dir, uid)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		return fmt.Errorf("%s has mode %#o, want 0700", dir, perm)
+	}
+	return nil
+}
+
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	if conn, err := net.DialTimeout("unix", path, dialTimeout); err == nil {
+		conn.Close()
+		return fmt.Errorf("another surge-tui is listening on %s", path)
+	}
+	return os.Remove(path)
+}
+
+// Path возвращает путь сокета.
+func (s *Server) Path() string {
+	return s.path
+}
+
+// Close перестаёт принимать соединения, обрывает открытые и удаляет сокет.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.cancel()
+	err := s.listener.Close()
+	s.wg.Wait()
+	if rmErr := os.Remove(s.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) && err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return // листенер закрыт
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn обрабатывает вызовы соединения по очереди, в порядке поступления.
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxRequestSize)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		resp := s.handle(ctx, line)
+		if resp == nil {
+			continue
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle выполняет один запрос; nil означает уведомление без ответа.
+func (s *Server) handle(ctx context.Context, line []byte) *Response {
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &Response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: Errorf(CodeParseError, "parse error: %v", err)}
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return &Response{JSONRPC: "2.0", ID: idOrNull(req.ID), Error: Errorf(CodeInvalidRequest, "invalid request")}
+	}
+
+	result, err := s.handler(ctx, req.Method, req.Params)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	resp := &Response{JSONRPC: "2.0", ID: req.ID}
+	if err != nil {
+		resp.Error = toError(err)
+	} else {
+		if result == nil {
+			result = struct{}{}
+		}
+		resp.Result = result
+	}
+	return resp
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
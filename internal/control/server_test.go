@@ -0,0 +1,118 @@
+//go:build unix
+
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func nopHandler(context.Context, string, json.RawMessage) (any, error) { return nil, nil }
+
+func TestCheckPrivateDir(t *testing.T) {
+	base := t.TempDir()
+	private := filepath.Join(base, "private")
+	if err := os.Mkdir(private, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	open := filepath.Join(base, "open")
+	if err := os.Mkdir(open, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(open, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(base, "link")
+	if err := os.Symlink(private, link); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(base, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		ok   bool
+	}{
+		{"личный каталог", private, true},
+		{"открыт для остальных", open, false},
+		{"символическая ссылка", link, false},
+		{"файл", file, false},
+		{"нет каталога", filepath.Join(base, "missing"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkPrivateDir(tt.dir); (err == nil) != tt.ok {
+				t.Errorf("checkPrivateDir(%s) = %v, want ok=%v", tt.dir, err, tt.ok)
+			}
+		})
+	}
+}
+
+// defaultSocketIn направляет сокет по умолчанию во временный каталог base.
+func defaultSocketIn(t *testing.T, base string) string {
+	t.Helper()
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("TMPDIR", base)
+	return DefaultSocketPath()
+}
+
+func TestListenCreatesPrivateSocket(t *testing.T) {
+	path := defaultSocketIn(t, t.TempDir())
+	s, err := Listen(path, nopHandler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for p, want := range map[string]os.FileMode{filepath.Dir(path): 0o700, path: 0o600} {
+		info, err := os.Lstat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s mode = %#o, want %#o", p, got, want)
+		}
+	}
+}
+
+// Каталог по умолчанию во временном каталоге, заранее созданный открытым
+// или подменённый ссылкой, не принимается.
+func TestListenRefusesForeignDefaultDir(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "surge-tui-"+strconv.Itoa(os.Getuid()))
+
+	t.Run("открытый каталог", func(t *testing.T) {
+		if err := os.Mkdir(dir, 0o700); err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		if err := os.Chmod(dir, 0o777); err != nil {
+			t.Fatal(err)
+		}
+		if s, err := Listen(defaultSocketIn(t, base), nopHandler); err == nil {
+			s.Close()
+			t.Fatal("Listen accepted a world-writable socket directory")
+		}
+	})
+	t.Run("ссылка на чужой каталог", func(t *testing.T) {
+		target := t.TempDir()
+		if err := os.Symlink(target, dir); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(dir)
+		if s, err := Listen(defaultSocketIn(t, base), nopHandler); err == nil {
+			s.Close()
+			t.Fatal("Listen followed a symlinked socket directory")
+		}
+		if entries, _ := os.ReadDir(target); len(entries) != 0 {
+			t.Errorf("socket created through the symlink: %v", entries)
+		}
+	})
+}
@@ -0,0 +1,27 @@
+//go:build !unix
+
+package control
+
+import (
+	"net"
+	"os"
+)
+
+// listenPrivate создаёт сокет и оставляет доступ к нему только владельцу.
+func listenPrivate(path string) (net.Listener, error) {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		listener.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	return listener, nil
+}
+
+// fileOwner на этой платформе владельца не сообщает.
+func fileOwner(os.FileInfo) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package control
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenPrivate создаёт сокет с правами 0600 сразу при bind: umask на время
+// вызова закрывает окно, в котором сокет доступен остальным до chmod.
+func listenPrivate(path string) (net.Listener, error) {
+	old := syscall.Umask(0o177)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
+
+// fileOwner возвращает uid владельца файла.
+func fileOwner(info os.FileInfo) (int, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return int(stat.Uid), true
+}
@@ -0,0 +1,146 @@
+package surge
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BuildStream — источник строки вывода сборки.
+type BuildStream int
+
+const (
+	BuildStdout BuildStream = iota
+	BuildStderr
+)
+
+// maxBuildLine — самая длинная строка вывода, которую читает сканер.
+const maxBuildLine = 1024 * 1024
+
+// BuildLine — одна строка вывода `surge build`. Diagnostic заполнен,
+// если строка stdout оказалась JSON-диагностикой.
+type BuildLine struct {
+	Stream     BuildStream
+	Text       string
+	Diagnostic *Diagnostic
+}
+
+// BuildRun — запущенная сборка. Lines нужно читать до закрытия:
+// иначе процесс заблокируется на записи в pipe.
+type BuildRun struct {
+	Lines <-chan BuildLine
+
+	done     chan struct{}
+	exitCode int
+	err      error
+}
+
+// Wait ждёт завершения процесса и возвращает код выхода. Ошибка возвращается,
+// только если процесс не удалось довести до конца (например, отмена через ctx).
+func (r *BuildRun) Wait() (int, error) {
+	<-r.done
+	return r.exitCode, r.err
+}
+
+// ReplayBuild возвращает уже завершённую сборку с заданным выводом (для подмен клиента).
+func ReplayBuild(lines []BuildLine, exitCode int, err error) *BuildRun {
+	ch := make(chan BuildLine, len(lines))
+	for _, line := range lines {
+		ch <- line
+	}
+	close(ch)
+	done := make(chan struct{})
+	close(done)
+	return &BuildRun{Lines: ch, done: done, exitCode: exitCode, err: err}
+}
+
+// StartBuild запускает `surge build` и отдаёт stdout и stderr построчно по мере вывода.
+// Current surge build doesn't support --format=json yet, so JSON lines are parsed when present.
+func (c *Client) StartBuild(ctx context.Context, projectPath string) (*BuildRun, error) {
+	cmd := c.command(ctx, "build", projectPath)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		logRun(cmd, start, err)
+		return nil, cliError(ctx, cmd, nil, err)
+	}
+
+	lines := make(chan BuildLine, 64)
+	run := &BuildRun{Lines: lines, done: make(chan struct{})}
+
+	var readers sync.WaitGroup
+	scan := func(stream BuildStream, pipe io.Reader) {
+		defer readers.Done()
+		scanner := bufio.NewScanner(pipe)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxBuildLine)
+		for scanner.Scan() {
+			line := BuildLine{Stream: stream, Text: scanner.Text()}
+			if stream == BuildStdout {
+				line.Diagnostic = parseBuildDiagnostic(line.Text)
+			}
+			lines <- line
+		}
+	}
+	readers.Add(2)
+	go scan(BuildStdout, stdout)
+	go scan(BuildStderr, stderr)
+
+	// При отмене закрываем pipe сами: дочерние процессы surge могут держать их
+	// открытыми и после того, как сам surge убит.
+	readDone := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = stdout.Close()
+			_ = stderr.Close()
+		case <-readDone:
+		}
+	}()
+
+	go func() {
+		// Wait закрывает pipe, поэтому вызывается после того, как оба чтения закончились
+		readers.Wait()
+		close(readDone)
+		waitErr := cmd.Wait()
+		logRun(cmd, start, waitErr)
+		var exitErr *exec.ExitError
+		switch {
+		case ctx.Err() != nil:
+			run.exitCode, run.err = -1, ctx.Err()
+		case errors.As(waitErr, &exitErr):
+			run.exitCode = exitErr.ExitCode()
+		case waitErr != nil:
+			run.exitCode, run.err = -1, waitErr
+		}
+		close(lines)
+		close(run.done)
+	}()
+	return run, nil
+}
+
+// parseBuildDiagnostic разбирает JSON-диагностику; прочие строки, включая JSON
+// без сообщения, диагностикой не считаются.
+func parseBuildDiagnostic(line string) *Diagnostic {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil
+	}
+	var diagnostic Diagnostic
+	if err := json.Unmarshal([]byte(trimmed), &diagnostic); err != nil || diagnostic.Message == "" {
+		return nil
+	}
+	return &diagnostic
+}
@@ -1,47 +1,107 @@
 package surge
 
 import (
-	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
+
+	"surge-tui/internal/logging"
 )
 
+// DefaultTimeout — таймаут запуска surge, пока он не задан из конфига.
+const DefaultTimeout = 60 * time.Second
+
 // Client клиент для взаимодействия с surge CLI
 type Client struct {
+	mu         sync.RWMutex // binaryPath и timeout меняются из настроек во время запусков
 	binaryPath string
 	timeout    time.Duration
+	stall      time.Duration // порог молчания, см. WithStallWatch
 }
 
 // NewClient создает новый клиент surge
 func NewClient(binaryPath string) *Client {
 	return &Client{
 		binaryPath: binaryPath,
-		timeout:    30 * time.Second, // По умолчанию 30 секунд
+		timeout:    DefaultTimeout,
+		stall:      DefaultStallThreshold,
 	}
 }
 
-// SetTimeout устанавливает таймаут для операций
+// SetTimeout устанавливает таймаут для операций; 0 — без ограничения.
+// Таймаут действует, только если у контекста вызова нет своего срока.
+// Сборка (StartBuild) им не ограничивается: её отменяет пользователь.
 func (c *Client) SetTimeout(timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.timeout = timeout
 }
 
+// waitDelay — сколько после отмены ждать закрытия вывода: дочерние процессы
+// surge могут держать pipe открытым и после того, как сам он убит.
+const waitDelay = time.Second
+
+// command готовит запуск surge с аргументами args.
+func (c *Client) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, c.binary(), args...)
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
+
+// withTimeout ограничивает ctx таймаутом клиента, если у ctx нет своего срока.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	c.mu.RLock()
+	timeout := c.timeout
+	c.mu.RUnlock()
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// SetBinaryPath меняет путь к бинарю surge; уже запущенные команды
+// доработают со старым.
+func (c *Client) SetBinaryPath(binaryPath string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.binaryPath = binaryPath
+}
+
+func (c *Client) binary() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.binaryPath
+}
+
 // CheckAvailable проверяет доступность surge CLI
 func (c *Client) CheckAvailable(ctx context.Context) error {
-	cmd := exec.CommandContext(ctx, c.binaryPath, "--version")
-	return cmd.Run()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	cmd := c.command(ctx, "--version")
+	_, err := c.run(ctx, cmd)
+	return err
 }
 
 // GetVersion возвращает версию surge
 func (c *Client) GetVersion(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, c.binaryPath, "--version")
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	cmd := c.command(ctx, "--version")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	start := time.Now()
 	output, err := cmd.Output()
+	logRun(cmd, start, err)
 	if err != nil {
-		return "", err
+		logOutput(cmd, stderr.Bytes())
+		return "", cliError(ctx, cmd, stderr.Bytes(), err)
 	}
 	return strings.TrimSpace(string(output)), nil
 }
@@ -49,32 +109,35 @@ func (c *Client) GetVersion(ctx context.Context) (string, error) {
 // Diagnose запускает `surge diag --format=json` по пути к файлу или директории.
 // Для директории CLI возвращает JSON-объект вида map[string]DiagnosticsOutput.
 // Для файла — объект DiagnosticsOutput.
+//
+// Ненулевой код выхода при разборчивом JSON — не ошибка (surge так сообщает
+// о найденных ошибках), он остаётся в ExitCode. Ошибки — *CLIError: бинарь
+// не найден (ErrNotFound) или вывод не JSON (ErrInvalidOutput).
 func (c *Client) Diagnose(ctx context.Context, targetPath string, withNotes, withFixes bool) (*DiagResponse, error) {
-	if targetPath == "" {
-		targetPath = "."
-	}
-
-	args := []string{"diag", "--format", "json"}
-	if withNotes {
-		args = append(args, "--with-notes")
-	}
-	if withFixes {
-		args = append(args, "--suggest")
-	}
-	args = append(args, "--fullpath")
-	args = append(args, targetPath)
-
-	cmd := exec.CommandContext(ctx, c.binaryPath, args...)
-	out, err := cmd.CombinedOutput()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	cmd := c.command(ctx, diagArgs(targetPath, withNotes, withFixes)...)
+	watch := c.watch(ctx, cmd.Args)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = io.MultiWriter(&stdout, watch)
+	cmd.Stderr = io.MultiWriter(&stderr, watch)
+	start := time.Now()
+	err := cmd.Run()
+	logRun(cmd, start, err)
+	out := stdout.Bytes()
 
 	resp := &DiagResponse{Raw: out, ExitCode: 0}
+	if err != nil && ctx.Err() != nil {
+		resp.Err = watch.finish(cliError(ctx, cmd, stderr.Bytes(), err))
+		return resp, resp.Err
+	}
 	if err != nil {
 		var ee *exec.ExitError
 		if errors.As(err, &ee) {
 			resp.ExitCode = ee.ExitCode()
 		} else {
-			resp.Err = err
-			return resp, err
+			resp.Err = cliError(ctx, cmd, stderr.Bytes(), err)
+			return resp, resp.Err
 		}
 	}
 
@@ -88,74 +151,115 @@ func (c *Client) Diagnose(ctx context.Context, targetPath string, withNotes, wit
 	// Падение на map — не обязательно ошибка: возможно одиночный файл
 	var single DiagnosticsOutput
 	if uerr := json.Unmarshal(out, &single); uerr != nil {
-		resp.Err = uerr
-		return resp, uerr
+		logOutput(cmd, stderr.Bytes())
+		resp.Err = &CLIError{
+			Args:     cmd.Args[1:],
+			ExitCode: resp.ExitCode,
+			Stderr:   trimOutput(stderr.Bytes()),
+			Err:      fmt.Errorf("%w: %v", ErrInvalidOutput, uerr),
+		}
+		return resp, resp.Err
 	}
 	resp.Single = &single
 	return resp, nil
 }
 
-// BuildProject запускает сборку проекта
-func (c *Client) BuildProject(ctx context.Context, projectPath string) (*BuildResult, error) {
-	// Note: current surge build is a stub and doesn't support --format=json yet.
-	// We invoke without format flag and attempt to parse JSON lines if present.
-	cmd := exec.CommandContext(ctx, c.binaryPath, "build", projectPath)
+// DiagnoseFiles запускает `surge diag` для файлов paths, не трогая
+// остальной проект: так изменившийся файл проверяется за доли секунды.
+func (c *Client) DiagnoseFiles(ctx context.Context, paths []string, withNotes, withFixes bool) (*DiagResponse, error) {
+	return DiagnoseEach(ctx, paths, func(ctx context.Context, path string) (*DiagResponse, error) {
+		return c.Diagnose(ctx, path, withNotes, withFixes)
+	})
+}
 
-	result := &BuildResult{
-		ProjectPath: projectPath,
-		StartTime:   time.Now(),
+// DiagnoseEach диагностирует paths по одному через diagnose и собирает
+// ответы в пакет по путям. Первая ошибка прерывает обход; код выхода —
+// наибольший из полученных.
+func DiagnoseEach(ctx context.Context, paths []string, diagnose func(ctx context.Context, path string) (*DiagResponse, error)) (*DiagResponse, error) {
+	resp := &DiagResponse{Batch: make(map[string]DiagnosticsOutput, len(paths))}
+	for _, path := range paths {
+		one, err := diagnose(ctx, path)
+		if err != nil {
+			return one, err
+		}
+		resp.ExitCode = max(resp.ExitCode, one.ExitCode)
+		if one.Single != nil {
+			resp.Batch[path] = *one.Single
+		}
+		for file, out := range one.Batch {
+			resp.Batch[file] = out
+		}
 	}
+	return resp, nil
+}
 
-	// Запускаем команду и читаем вывод
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return result, err
+// diagArgs собирает аргументы `surge diag --format=json`.
+func diagArgs(targetPath string, withNotes, withFixes bool) []string {
+	if targetPath == "" {
+		targetPath = "."
 	}
 
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return result, err
+	args := []string{"diag", "--format", "json"}
+	if withNotes {
+		args = append(args, "--with-notes")
 	}
-
-	if err := cmd.Start(); err != nil {
-		return result, err
+	if withFixes {
+		args = append(args, "--suggest")
 	}
+	args = append(args, "--fullpath")
+	return append(args, targetPath)
+}
 
-	// Читаем stdout для диагностик
-	go func() {
-		scanner := bufio.NewScanner(stdout)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if diagnostic := c.parseDiagnostic(line); diagnostic != nil {
-				result.Diagnostics = append(result.Diagnostics, *diagnostic)
-			}
-		}
-	}()
+// BuildProject запускает сборку проекта и ждёт её окончания.
+// Вывод читается через StartBuild одним потребителем, поэтому без гонок.
+func (c *Client) BuildProject(ctx context.Context, projectPath string) (*BuildResult, error) {
+	result := &BuildResult{
+		ProjectPath: projectPath,
+		StartTime:   time.Now(),
+	}
 
-	// Читаем stderr для ошибок
-	go func() {
-		scanner := bufio.NewScanner(stderr)
-		for scanner.Scan() {
-			result.ErrorOutput = append(result.ErrorOutput, scanner.Text())
+	run, err := c.StartBuild(ctx, projectPath)
+	if err != nil {
+		return result, err
+	}
+	for line := range run.Lines {
+		switch {
+		case line.Diagnostic != nil:
+			result.Diagnostics = append(result.Diagnostics, *line.Diagnostic)
+		case line.Stream == BuildStderr:
+			result.ErrorOutput = append(result.ErrorOutput, line.Text)
 		}
-	}()
+	}
 
-	err = cmd.Wait()
+	exitCode, err := run.Wait()
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
-	result.Success = (err == nil)
-
+	result.Success = err == nil && exitCode == 0
 	if err != nil {
 		result.Error = err
+	} else if exitCode != 0 {
+		result.Error = fmt.Errorf("surge build exited with code %d", exitCode)
 	}
 
 	return result, nil
 }
 
 // InitProject initializes a surge project at the given path.
+// Вывод surge попадает в *CLIError.
 func (c *Client) InitProject(ctx context.Context, projectPath string) error {
-	cmd := exec.CommandContext(ctx, c.binaryPath, "init", projectPath)
-	return cmd.Run()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	_, err := c.run(ctx, c.command(ctx, "init", projectPath))
+	return err
+}
+
+// Format форматирует файл или все исходники каталога через `surge fmt`.
+// Вывод surge (обычно ошибка разбора) попадает в *CLIError.
+func (c *Client) Format(ctx context.Context, path string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	_, err := c.run(ctx, c.command(ctx, "fmt", path))
+	return err
 }
 
 // ListFixes возвращает доступные фиксы через `surge diag --format=json --suggest`.
@@ -213,137 +317,66 @@ func (c *Client) ApplyFixByID(ctx context.Context, filePath, fixID string) error
 	if fixID == "" {
 		return fmt.Errorf("empty fix id")
 	}
-	cmd := exec.CommandContext(ctx, c.binaryPath, "fix", "--id", fixID, filePath)
-	return cmd.Run()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	_, err := c.run(ctx, c.command(ctx, "fix", "--id", fixID, filePath))
+	return err
 }
 
 // ApplyAllFixes применяет все безопасные фиксы (к файлу или директории).
 func (c *Client) ApplyAllFixes(ctx context.Context, targetPath string) error {
-	cmd := exec.CommandContext(ctx, c.binaryPath, "fix", "--all", targetPath)
-	return cmd.Run()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	_, err := c.run(ctx, c.command(ctx, "fix", "--all", targetPath))
+	return err
 }
 
 // ApplyOneFix применяет один первый доступный фикс (к файлу или директории).
 func (c *Client) ApplyOneFix(ctx context.Context, targetPath string) error {
-	cmd := exec.CommandContext(ctx, c.binaryPath, "fix", "--once", targetPath)
-	return cmd.Run()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	_, err := c.run(ctx, c.command(ctx, "fix", "--once", targetPath))
+	return err
 }
 
-// parseDiagnostic парсит строку диагностики из JSON
-func (c *Client) parseDiagnostic(line string) *Diagnostic {
-	var diagnostic Diagnostic
-	if err := json.Unmarshal([]byte(line), &diagnostic); err != nil {
-		return nil
+// run запускает cmd и ждёт его, читая вывод по мере появления: так сторож
+// видит, что surge ещё работает. Неудача возвращается как *CLIError с
+// выводом surge; вывод также пишется в журнал.
+func (c *Client) run(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	watch := c.watch(ctx, cmd.Args)
+	var out bytes.Buffer
+	// один и тот же writer: exec отдаст stdout и stderr в общий pipe
+	sink := io.MultiWriter(&out, watch)
+	cmd.Stdout, cmd.Stderr = sink, sink
+	start := time.Now()
+	err := cmd.Run()
+	logRun(cmd, start, err)
+	if err != nil {
+		logOutput(cmd, out.Bytes())
 	}
-	return &diagnostic
-}
-
-// BuildResult результат сборки
-type BuildResult struct {
-	ProjectPath string
-	Success     bool
-	StartTime   time.Time
-	EndTime     time.Time
-	Duration    time.Duration
-	Diagnostics []Diagnostic
-	ErrorOutput []string
-	Error       error
+	return out.Bytes(), watch.finish(cliError(ctx, cmd, out.Bytes(), err))
 }
 
-// Diagnostic диагностическое сообщение
-type Diagnostic struct {
-	Level   string    `json:"level"`   // error, warning, info
-	Code    string    `json:"code"`    // Код ошибки
-	Message string    `json:"message"` // Текст сообщения
-	File    string    `json:"file"`    // Путь к файлу
-	Line    int       `json:"line"`    // Номер строки
-	Column  int       `json:"column"`  // Номер колонки
-	Span    SpanInfo  `json:"span"`    // Информация о диапазоне
-	Fixes   []FixJSON `json:"fixes"`   // Доступные автофиксы
-}
-
-// SpanInfo информация о диапазоне в файле
-type SpanInfo struct {
-	StartLine   int `json:"start_line"`
-	StartColumn int `json:"start_column"`
-	EndLine     int `json:"end_line"`
-	EndColumn   int `json:"end_column"`
-}
-
-// String возвращает строковое представление диагностики
-func (d *Diagnostic) String() string {
-	return fmt.Sprintf("%s:%d:%d: %s: %s [%s]",
-		d.File, d.Line, d.Column, d.Level, d.Message, d.Code)
-}
-
-// IsError проверяет, является ли диагностика ошибкой
-func (d *Diagnostic) IsError() bool {
-	return d.Level == "error"
-}
-
-// IsWarning проверяет, является ли диагностика предупреждением
-func (d *Diagnostic) IsWarning() bool {
-	return d.Level == "warning"
-}
-
-// ===== JSON контракты для surge diag --format=json =====
-
-// LocationJSON описывает местоположение в файле
-type LocationJSON struct {
-	File      string `json:"file"`
-	StartByte uint32 `json:"start_byte"`
-	EndByte   uint32 `json:"end_byte"`
-	StartLine uint32 `json:"start_line,omitempty"`
-	StartCol  uint32 `json:"start_col,omitempty"`
-	EndLine   uint32 `json:"end_line,omitempty"`
-	EndCol    uint32 `json:"end_col,omitempty"`
-}
-
-// NoteJSON — дополнительная заметка
-type NoteJSON struct {
-	Message  string       `json:"message"`
-	Location LocationJSON `json:"location"`
-}
-
-// FixEditJSON — одно редактирование
-type FixEditJSON struct {
-	Location LocationJSON `json:"location"`
-	NewText  string       `json:"new_text"`
-	OldText  string       `json:"old_text,omitempty"`
-}
-
-// FixJSON — описание автофикса
-type FixJSON struct {
-	ID            string        `json:"id,omitempty"`
-	Title         string        `json:"title"`
-	Kind          string        `json:"kind"`
-	Applicability string        `json:"applicability"`
-	IsPreferred   bool          `json:"is_preferred,omitempty"`
-	BuildError    string        `json:"build_error,omitempty"`
-	Edits         []FixEditJSON `json:"edits,omitempty"`
-}
-
-// DiagnosticJSON — диагностика (JSON)
-type DiagnosticJSON struct {
-	Severity string       `json:"severity"`
-	Code     string       `json:"code"`
-	Message  string       `json:"message"`
-	Location LocationJSON `json:"location"`
-	Notes    []NoteJSON   `json:"notes,omitempty"`
-	Fixes    []FixJSON    `json:"fixes,omitempty"`
-}
-
-// DiagnosticsOutput — корень JSON для одного файла
-type DiagnosticsOutput struct {
-	Diagnostics []DiagnosticJSON `json:"diagnostics"`
-	Count       int              `json:"count"`
+// logOutput записывает в журнал вывод неудачного запуска.
+func logOutput(cmd *exec.Cmd, output []byte) {
+	if text := trimOutput(output); text != "" {
+		logging.Infof("%s output:\n%s", strings.Join(cmd.Args, " "), text)
+	}
 }
 
-// DiagResponse — объединённый ответ от diag
-type DiagResponse struct {
-	Single   *DiagnosticsOutput
-	Batch    map[string]DiagnosticsOutput
-	ExitCode int
-	Raw      []byte
-	Err      error
+// logRun записывает запуск surge в журнал: командную строку, длительность и
+// код выхода. Ошибка запуска (бинарь не найден, нет прав) — уровень error;
+// ненулевой код выхода — обычный результат diag и build, поэтому info.
+func logRun(cmd *exec.Cmd, start time.Time, err error) {
+	line := strings.Join(cmd.Args, " ")
+	elapsed := time.Since(start).Round(time.Millisecond)
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		logging.Infof("%s (%s, exit 0)", line, elapsed)
+	case errors.As(err, &exitErr):
+		logging.Infof("%s (%s, exit %d)", line, elapsed, exitErr.ExitCode())
+	default:
+		logging.Errorf("%s (%s): %v", line, elapsed, err)
+	}
 }
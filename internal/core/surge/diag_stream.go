@@ -0,0 +1,178 @@
+package surge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"time"
+)
+
+// DiagFile — диагностики одного файла из потока `surge diag`.
+type DiagFile struct {
+	Path   string // ключ пакета; пусто, если диагностировался один файл
+	Output DiagnosticsOutput
+}
+
+// DiagRun — запущенный `surge diag`. Files нужно читать до закрытия:
+// иначе процесс заблокируется на записи в pipe.
+type DiagRun struct {
+	Files <-chan DiagFile
+
+	done     chan struct{}
+	exitCode int
+	err      error
+}
+
+// Wait ждёт завершения процесса и возвращает код выхода. Ошибка — отмена,
+// сбой запуска или вывод, который не удалось разобрать.
+func (r *DiagRun) Wait() (int, error) {
+	<-r.done
+	return r.exitCode, r.err
+}
+
+// ReplayDiag возвращает уже завершённый запуск с ответом resp (для подмен клиента).
+// Файлы пакета отдаются в порядке путей.
+func ReplayDiag(resp *DiagResponse, err error) *DiagRun {
+	var files []DiagFile
+	exitCode := 0
+	if resp != nil {
+		exitCode = resp.ExitCode
+		paths := make([]string, 0, len(resp.Batch))
+		for path := range resp.Batch {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			files = append(files, DiagFile{Path: path, Output: resp.Batch[path]})
+		}
+		if resp.Single != nil {
+			files = append(files, DiagFile{Output: *resp.Single})
+		}
+	}
+	ch := make(chan DiagFile, len(files))
+	for _, file := range files {
+		ch <- file
+	}
+	close(ch)
+	done := make(chan struct{})
+	close(done)
+	return &DiagRun{Files: ch, done: done, exitCode: exitCode, err: err}
+}
+
+// StartDiagnose запускает `surge diag --format=json` и отдаёт результаты по файлам
+// по мере разбора вывода, не дожидаясь конца JSON.
+func (c *Client) StartDiagnose(ctx context.Context, targetPath string, withNotes, withFixes bool) (*DiagRun, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	cmd := c.command(ctx, diagArgs(targetPath, withNotes, withFixes)...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	watch := c.watch(ctx, cmd.Args)
+	var stderr bytes.Buffer
+	cmd.Stderr = io.MultiWriter(&stderr, watch)
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		logRun(cmd, start, err)
+		cancel()
+		return nil, cliError(ctx, cmd, nil, err)
+	}
+
+	files := make(chan DiagFile, 16)
+	run := &DiagRun{Files: files, done: make(chan struct{})}
+
+	// При отмене закрываем pipe сами, как и в StartBuild
+	readDone := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = stdout.Close()
+		case <-readDone:
+		}
+	}()
+
+	go func() {
+		decodeErr := decodeDiagStream(io.TeeReader(stdout, watch), func(file DiagFile) { files <- file })
+		if decodeErr != nil {
+			_, _ = io.Copy(io.Discard, stdout)
+		}
+		close(readDone)
+		waitErr := cmd.Wait()
+		logRun(cmd, start, waitErr)
+		var exitErr *exec.ExitError
+		switch {
+		case ctx.Err() != nil:
+			run.exitCode, run.err = -1, watch.finish(cliError(ctx, cmd, stderr.Bytes(), ctx.Err()))
+		case errors.As(waitErr, &exitErr):
+			run.exitCode = exitErr.ExitCode()
+		case waitErr != nil:
+			run.exitCode, run.err = -1, cliError(ctx, cmd, stderr.Bytes(), waitErr)
+		}
+		if run.err == nil && decodeErr != nil {
+			logOutput(cmd, stderr.Bytes())
+			run.err = &CLIError{
+				Args:     cmd.Args[1:],
+				ExitCode: run.exitCode,
+				Stderr:   trimOutput(stderr.Bytes()),
+				Err:      fmt.Errorf("%w: %v", ErrInvalidOutput, decodeErr),
+			}
+		}
+		cancel()
+		close(files)
+		close(run.done)
+	}()
+	return run, nil
+}
+
+// decodeDiagStream разбирает вывод diag потоково. Для каталога это объект
+// {"путь": DiagnosticsOutput, ...} — каждый файл отдаётся, как только разобран.
+// Для одного файла — сам DiagnosticsOutput, он отдаётся целиком в конце.
+func decodeDiagStream(r io.Reader, emit func(DiagFile)) error {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("unexpected token %v", tok)
+	}
+
+	var single *DiagnosticsOutput
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		switch key {
+		case "diagnostics", "count":
+			if single == nil {
+				single = &DiagnosticsOutput{}
+			}
+			if key == "count" {
+				err = dec.Decode(&single.Count)
+			} else {
+				err = dec.Decode(&single.Diagnostics)
+			}
+		default:
+			var out DiagnosticsOutput
+			if err = dec.Decode(&out); err == nil {
+				emit(DiagFile{Path: key, Output: out})
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("key %q: %w", key, err)
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	if single != nil {
+		emit(DiagFile{Output: *single})
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package surge
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// Правки фикса приходят в произвольном порядке. Позиция задаётся строкой и колонкой
+// (1-based, колонки в рунах, конец не включается, EndCol 0 — до конца строки)
+// или, если строк нет, байтовыми смещениями StartByte/EndByte.
+
+// ErrOverlappingEdits — правки фикса пересекаются, применять их по отдельности нельзя.
+var ErrOverlappingEdits = errors.New("fix edits overlap")
+
+// ResolvedEdit — правка с границами в байтах содержимого файла.
+// Edit.Location дополнена строками и колонками, даже если CLI прислал только байты.
+type ResolvedEdit struct {
+	Edit  FixEditJSON
+	Index int // позиция в исходном массиве
+	Start int
+	End   int
+}
+
+// EditOverlap — пара пересекающихся правок (индексы в исходном массиве).
+type EditOverlap struct {
+	First  int
+	Second int
+}
+
+func (o EditOverlap) String() string {
+	return fmt.Sprintf("edits %d and %d overlap", o.First+1, o.Second+1)
+}
+
+// NormalizeEdits переводит позиции правок в смещения content, сортирует их
+// по началу и находит пересечения. Правки, которые просто стыкуются, и вставки
+// в одну точку пересечением не считаются; вставки сохраняют исходный порядок.
+func NormalizeEdits(content []byte, edits []FixEditJSON) ([]ResolvedEdit, []EditOverlap, error) {
+	starts := lineStarts(content)
+	resolved := make([]ResolvedEdit, 0, len(edits))
+	for i, edit := range edits {
+		start, end, err := editOffsets(content, starts, edit.Location)
+		if err != nil {
+			return nil, nil, fmt.Errorf("edit %d: %w", i+1, err)
+		}
+		edit.Location = withLineInfo(content, starts, edit.Location, start, end)
+		resolved = append(resolved, ResolvedEdit{Edit: edit, Index: i, Start: start, End: end})
+	}
+	sort.SliceStable(resolved, func(i, j int) bool {
+		if resolved[i].Start != resolved[j].Start {
+			return resolved[i].Start < resolved[j].Start
+		}
+		return resolved[i].End < resolved[j].End
+	})
+
+	var overlaps []EditOverlap
+	reach, reachIndex := -1, -1 // самый дальний конец среди предыдущих правок
+	for _, r := range resolved {
+		if r.Start < reach {
+			first, second := min(reachIndex, r.Index), max(reachIndex, r.Index)
+			overlaps = append(overlaps, EditOverlap{First: first, Second: second})
+		}
+		if r.End > reach {
+			reach, reachIndex = r.End, r.Index
+		}
+	}
+	return resolved, overlaps, nil
+}
+
+// OverlapError собирает пересечения в ошибку ErrOverlappingEdits или возвращает nil.
+func OverlapError(overlaps []EditOverlap) error {
+	if len(overlaps) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrOverlappingEdits, overlaps[0])
+}
+
+// ApplyEdits применяет правки к content с конца файла, чтобы смещения ранних правок
+// оставались верными. При пересечениях content не меняется.
+func ApplyEdits(content []byte, edits []FixEditJSON) ([]byte, error) {
+	resolved, overlaps, err := NormalizeEdits(content, edits)
+	if err != nil {
+		return nil, err
+	}
+	if err := OverlapError(overlaps); err != nil {
+		return nil, err
+	}
+	out := append([]byte(nil), content...)
+	for i := len(resolved) - 1; i >= 0; i-- {
+		r := resolved[i]
+		if old := r.Edit.OldText; old != "" && string(out[r.Start:r.End]) != old {
+			return nil, fmt.Errorf("edit %d: file differs from the fix", r.Index+1)
+		}
+		tail := append([]byte(r.Edit.NewText), out[r.End:]...)
+		out = append(out[:r.Start], tail...)
+	}
+	return out, nil
+}
+
+// lineStarts возвращает смещения начала каждой строки.
+func lineStarts(content []byte) []int {
+	starts := []int{0}
+	for i, b := range content {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+func lineEnd(content []byte, starts []int, line int) int {
+	if line+1 < len(starts) {
+		return starts[line+1] - 1
+	}
+	return len(content)
+}
+
+// columnOffset переводит 0-based колонку в рунах в смещение внутри строки,
+// не выходя за её конец.
+func columnOffset(content []byte, starts []int, line, col int) int {
+	offset, end := starts[line], lineEnd(content, starts, line)
+	for ; col > 0 && offset < end; col-- {
+		_, size := utf8.DecodeRune(content[offset:end])
+		offset += size
+	}
+	return offset
+}
+
+func editOffsets(content []byte, starts []int, loc LocationJSON) (int, int, error) {
+	startLine, endLine := int(loc.StartLine), int(loc.EndLine)
+	if startLine == 0 && endLine == 0 {
+		start, end := int(loc.StartByte), int(loc.EndByte)
+		if end < start || end > len(content) {
+			return 0, 0, fmt.Errorf("byte range %d-%d is outside the file", start, end)
+		}
+		return start, end, nil
+	}
+	if startLine == 0 {
+		startLine = endLine
+	}
+	if endLine == 0 {
+		endLine = startLine
+	}
+	if startLine > len(starts) || endLine < startLine {
+		return 0, 0, fmt.Errorf("range %d-%d is outside the file", startLine, endLine)
+	}
+	endLine = min(endLine, len(starts))
+
+	start := columnOffset(content, starts, startLine-1, int(loc.StartCol)-1)
+	end := lineEnd(content, starts, endLine-1)
+	if loc.EndCol > 0 {
+		end = columnOffset(content, starts, endLine-1, int(loc.EndCol)-1)
+	}
+	return start, max(start, end), nil
+}
+
+// withLineInfo заполняет строки и колонки по смещениям, сохраняя байты.
+func withLineInfo(content []byte, starts []int, loc LocationJSON, start, end int) LocationJSON {
+	position := func(offset int) (uint32, uint32) {
+		line := sort.Search(len(starts), func(i int) bool { return starts[i] > offset }) - 1
+		col := utf8.RuneCount(content[starts[line]:offset])
+		return uint32(line + 1), uint32(col + 1)
+	}
+	loc.StartLine, loc.StartCol = position(start)
+	loc.EndLine, loc.EndCol = position(end)
+	loc.StartByte, loc.EndByte = uint32(start), uint32(end)
+	return loc
+}
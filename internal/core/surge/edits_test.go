@@ -0,0 +1,252 @@
+package surge
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// at — правка по строкам и колонкам (1-based, колонки в рунах).
+func at(startLine, startCol, endLine, endCol uint32, newText string) FixEditJSON {
+	return FixEditJSON{
+		Location: LocationJSON{StartLine: startLine, StartCol: startCol, EndLine: endLine, EndCol: endCol},
+		NewText:  newText,
+	}
+}
+
+// bytesAt — правка только по байтовым смещениям.
+func bytesAt(start, end uint32, newText string) FixEditJSON {
+	return FixEditJSON{Location: LocationJSON{StartByte: start, EndByte: end}, NewText: newText}
+}
+
+func TestApplyEdits(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		edits   []FixEditJSON
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "одна замена",
+			content: "let x = 1\n",
+			edits:   []FixEditJSON{at(1, 5, 1, 6, "y")},
+			want:    "let y = 1\n",
+		},
+		{
+			name:    "правки в обратном порядке",
+			content: "a b c\n",
+			edits:   []FixEditJSON{at(1, 5, 1, 6, "C"), at(1, 3, 1, 4, "B"), at(1, 1, 1, 2, "A")},
+			want:    "A B C\n",
+		},
+		{
+			name:    "стыкующиеся правки",
+			content: "abcdef",
+			edits:   []FixEditJSON{at(1, 4, 1, 7, "XYZ"), at(1, 1, 1, 4, "123")},
+			want:    "123XYZ",
+		},
+		{
+			name:    "вставки в одну точку сохраняют порядок",
+			content: "ab",
+			edits:   []FixEditJSON{at(1, 2, 1, 2, "1"), at(1, 2, 1, 2, "2")},
+			want:    "a12b",
+		},
+		{
+			name:    "правки на разных строках снизу вверх",
+			content: "one\ntwo\nthree\n",
+			edits:   []FixEditJSON{at(3, 1, 3, 6, "3"), at(1, 1, 1, 4, "1"), at(2, 1, 2, 4, "2")},
+			want:    "1\n2\n3\n",
+		},
+		{
+			name:    "удаление нескольких строк",
+			content: "keep\ndrop1\ndrop2\nkeep\n",
+			edits:   []FixEditJSON{at(2, 1, 4, 1, "")},
+			want:    "keep\nkeep\n",
+		},
+		{
+			name:    "EndCol 0 — до конца строки",
+			content: "fn main() { old }\nnext\n",
+			edits:   []FixEditJSON{at(1, 11, 1, 0, "{}")},
+			want:    "fn main() {}\nnext\n",
+		},
+		{
+			name:    "вставка в конец файла без перевода строки",
+			content: "last",
+			edits:   []FixEditJSON{at(1, 5, 1, 5, ";\n")},
+			want:    "last;\n",
+		},
+		{
+			name:    "вставка в пустую последнюю строку",
+			content: "line\n",
+			edits:   []FixEditJSON{at(2, 1, 2, 1, "tail\n")},
+			want:    "line\ntail\n",
+		},
+		{
+			name:    "колонка за концом строки упирается в конец",
+			content: "ab\ncd\n",
+			edits:   []FixEditJSON{at(1, 10, 1, 10, "!")},
+			want:    "ab!\ncd\n",
+		},
+		{
+			name:    "колонки в рунах, а не в байтах",
+			content: "héllo wörld\n",
+			edits:   []FixEditJSON{at(1, 7, 1, 12, "мир")},
+			want:    "héllo мир\n",
+		},
+		{
+			name:    "замена многобайтовой руны",
+			content: "a→b\n",
+			edits:   []FixEditJSON{at(1, 2, 1, 3, "->")},
+			want:    "a->b\n",
+		},
+		{
+			name:    "только байтовые смещения",
+			content: "héllo wörld",
+			edits:   []FixEditJSON{bytesAt(7, 13, "мир"), bytesAt(0, 1, "H")},
+			want:    "Héllo мир",
+		},
+		{
+			name:    "байтовая вставка в конец",
+			content: "abc",
+			edits:   []FixEditJSON{bytesAt(3, 3, "d")},
+			want:    "abcd",
+		},
+		{
+			name:    "вложенные правки",
+			content: "abcdef",
+			edits:   []FixEditJSON{at(1, 1, 1, 7, "X"), at(1, 3, 1, 4, "Y")},
+			wantErr: ErrOverlappingEdits,
+		},
+		{
+			name:    "частичное пересечение",
+			content: "abcdef",
+			edits:   []FixEditJSON{at(1, 4, 1, 7, "X"), at(1, 1, 1, 5, "Y")},
+			wantErr: ErrOverlappingEdits,
+		},
+		{
+			name:    "вставка внутри заменяемого диапазона",
+			content: "abcdef",
+			edits:   []FixEditJSON{at(1, 2, 1, 5, "X"), at(1, 3, 1, 3, "Y")},
+			wantErr: ErrOverlappingEdits,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := []byte(tt.content)
+			got, err := ApplyEdits(content, tt.edits)
+			if string(content) != tt.content {
+				t.Fatalf("ApplyEdits modified its input: %q", content)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyEdits: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyEditsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		edit    FixEditJSON
+	}{
+		{"строка за концом файла", "a\nb", at(5, 1, 5, 2, "x")},
+		{"конец раньше начала", "a\nb\nc", at(3, 1, 2, 1, "x")},
+		{"байты за концом файла", "abc", bytesAt(2, 10, "x")},
+		{"байтовый конец раньше начала", "abc", bytesAt(2, 1, "x")},
+		{"старый текст не совпадает", "let x = 1", FixEditJSON{Location: at(1, 5, 1, 6, "").Location, NewText: "y", OldText: "z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ApplyEdits([]byte(tt.content), []FixEditJSON{tt.edit}); err == nil {
+				t.Errorf("ApplyEdits = %q, want an error", got)
+			} else if errors.Is(err, ErrOverlappingEdits) {
+				t.Errorf("err = %v, not an overlap", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeEditsOrderAndOverlaps(t *testing.T) {
+	content := []byte("0123456789\nabcdefghij\n")
+	tests := []struct {
+		name     string
+		edits    []FixEditJSON
+		order    []int // исходные индексы после сортировки
+		overlaps []EditOverlap
+	}{
+		{
+			name:  "обратный порядок",
+			edits: []FixEditJSON{at(2, 1, 2, 3, ""), at(1, 5, 1, 6, ""), at(1, 1, 1, 2, "")},
+			order: []int{2, 1, 0},
+		},
+		{
+			name:  "стык не пересечение",
+			edits: []FixEditJSON{at(1, 3, 1, 5, ""), at(1, 1, 1, 3, "")},
+			order: []int{1, 0},
+		},
+		{
+			name:     "вложенная правка",
+			edits:    []FixEditJSON{at(1, 1, 2, 1, ""), at(1, 4, 1, 6, "")},
+			order:    []int{0, 1},
+			overlaps: []EditOverlap{{First: 0, Second: 1}},
+		},
+		{
+			name:     "пересечение с дальней, а не соседней правкой",
+			edits:    []FixEditJSON{at(1, 1, 1, 9, ""), at(1, 2, 1, 3, ""), at(1, 5, 1, 6, "")},
+			order:    []int{0, 1, 2},
+			overlaps: []EditOverlap{{First: 0, Second: 1}, {First: 0, Second: 2}},
+		},
+		{
+			name:  "байты вперемешку со строками",
+			edits: []FixEditJSON{bytesAt(11, 12, ""), at(1, 1, 1, 2, "")},
+			order: []int{1, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, overlaps, err := NormalizeEdits(content, tt.edits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var order []int
+			for _, r := range resolved {
+				order = append(order, r.Index)
+			}
+			if !reflect.DeepEqual(order, tt.order) {
+				t.Errorf("order = %v, want %v", order, tt.order)
+			}
+			if !reflect.DeepEqual(overlaps, tt.overlaps) {
+				t.Errorf("overlaps = %v, want %v", overlaps, tt.overlaps)
+			}
+			if err := OverlapError(overlaps); (err != nil) != (len(tt.overlaps) > 0) {
+				t.Errorf("OverlapError = %v", err)
+			}
+		})
+	}
+}
+
+// Правка из одних байтов получает строки и колонки в рунах.
+func TestNormalizeEditsFillsLineInfo(t *testing.T) {
+	content := []byte("héllo\nwörld\n")
+	resolved, _, err := NormalizeEdits(content, []FixEditJSON{bytesAt(8, 10, "o")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := LocationJSON{StartByte: 8, EndByte: 10, StartLine: 2, StartCol: 2, EndLine: 2, EndCol: 3}
+	if got := resolved[0].Edit.Location; got != want {
+		t.Errorf("location = %+v, want %+v", got, want)
+	}
+	if r := resolved[0]; r.Start != 8 || r.End != 10 {
+		t.Errorf("offsets = %d-%d, want 8-10", r.Start, r.End)
+	}
+}
@@ -0,0 +1,127 @@
+package surge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound — бинарь surge не найден (нет в PATH или по пути surge_binary).
+	ErrNotFound = errors.New("surge binary not found")
+	// ErrInvalidOutput — surge отработал, но его вывод не разбирается как JSON.
+	ErrInvalidOutput = errors.New("invalid JSON output")
+)
+
+// stderrLimit — сколько байт вывода surge хранится в ошибке.
+const stderrLimit = 4096
+
+// CLIError — неудачный запуск surge: аргументы, код выхода и вывод,
+// по которому видно, почему он не отработал. Error() даёт одну строку для
+// статуса и уведомлений, полный вывод пишется в журнал.
+type CLIError struct {
+	Args     []string      // аргументы без имени бинаря
+	ExitCode int           // -1, если процесс не запустился
+	Stderr   string        // вывод surge без пробелов по краям
+	Err      error         // исходная ошибка; ErrNotFound и ErrInvalidOutput проверяются errors.Is
+	Idle     time.Duration // сколько surge молчал перед таймаутом
+}
+
+func (e *CLIError) Error() string {
+	command := "surge"
+	if len(e.Args) > 0 {
+		command += " " + e.Args[0]
+	}
+	var msg string
+	switch {
+	case errors.Is(e.Err, ErrNotFound):
+		return e.Err.Error()
+	case errors.Is(e.Err, context.DeadlineExceeded):
+		msg = command + " timed out"
+		if e.Idle > 0 {
+			msg += fmt.Sprintf(" after %s without output", e.Idle)
+		}
+		if line := e.LastLine(); line != "" {
+			msg += "; last output: " + line
+		}
+		return msg
+	case errors.Is(e.Err, ErrInvalidOutput):
+		msg = fmt.Sprintf("%s: %v", command, e.Err)
+	case e.ExitCode >= 0:
+		msg = fmt.Sprintf("%s exited with code %d", command, e.ExitCode)
+	default:
+		msg = fmt.Sprintf("%s: %v", command, e.Err)
+	}
+	if line := e.FirstLine(); line != "" {
+		msg += ": " + line
+	}
+	return msg
+}
+
+func (e *CLIError) Unwrap() error {
+	return e.Err
+}
+
+// FirstLine возвращает первую непустую строку вывода surge.
+func (e *CLIError) FirstLine() string {
+	for _, line := range strings.Split(e.Stderr, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// LastLine возвращает последнюю непустую строку вывода surge.
+func (e *CLIError) LastLine() string {
+	lines := strings.Split(e.Stderr, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// cliError оборачивает ошибку запуска cmd в *CLIError. Отмена контекста
+// возвращается как есть: это не сбой surge, и экраны проверяют её через
+// errors.Is. Истёкший таймаут — *CLIError с context.DeadlineExceeded.
+func cliError(ctx context.Context, cmd *exec.Cmd, output []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	ctxErr := ctx.Err()
+	if errors.Is(ctxErr, context.Canceled) {
+		return ctxErr
+	}
+	e := &CLIError{ExitCode: -1, Stderr: trimOutput(output), Err: err}
+	if ctxErr != nil {
+		e.Err = ctxErr // процесс убит по таймауту: код выхода ничего не говорит
+	}
+	if len(cmd.Args) > 1 {
+		e.Args = cmd.Args[1:]
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case ctxErr != nil:
+	case errors.As(err, &exitErr):
+		e.ExitCode = exitErr.ExitCode()
+	case errors.Is(err, exec.ErrNotFound), errors.Is(err, fs.ErrNotExist):
+		e.Err = fmt.Errorf("%w: %s", ErrNotFound, cmd.Path)
+	}
+	return e
+}
+
+// trimOutput обрезает вывод до stderrLimit байт, оставляя конец: причина
+// ошибки обычно в последних строках.
+func trimOutput(output []byte) string {
+	text := strings.TrimSpace(string(output))
+	if len(text) > stderrLimit {
+		text = "…" + strings.TrimSpace(strings.ToValidUTF8(text[len(text)-stderrLimit:], ""))
+	}
+	return text
+}
@@ -0,0 +1,22 @@
+package surge
+
+import "context"
+
+// SurgeRunner — операции surge CLI, которые нужны экранам. Экраны и App
+// принимают интерфейс, чтобы UI-логику можно было гонять без бинарника
+// (см. пакет testsupport).
+type SurgeRunner interface {
+	CheckAvailable(ctx context.Context) error
+	GetVersion(ctx context.Context) (string, error)
+	Diagnose(ctx context.Context, targetPath string, withNotes, withFixes bool) (*DiagResponse, error)
+	StartDiagnose(ctx context.Context, targetPath string, withNotes, withFixes bool) (*DiagRun, error)
+	DiagnoseFiles(ctx context.Context, paths []string, withNotes, withFixes bool) (*DiagResponse, error)
+	InitProject(ctx context.Context, projectPath string) error
+	ApplyFixByID(ctx context.Context, filePath, fixID string) error
+	ApplyAllFixes(ctx context.Context, targetPath string) error
+	ApplyOneFix(ctx context.Context, targetPath string) error
+	Format(ctx context.Context, path string) error
+	StartBuild(ctx context.Context, projectPath string) (*BuildRun, error)
+}
+
+var _ SurgeRunner = (*Client)(nil)
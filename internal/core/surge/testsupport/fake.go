@@ -0,0 +1,197 @@
+// Package testsupport содержит подмены surge CLI для проверки UI без бинарника.
+package testsupport
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"surge-tui/internal/core/surge"
+)
+
+// FakeRunner — реализация surge.SurgeRunner в памяти. Diagnose отдаёт заготовленные
+// ответы, применённые фиксы, вызовы init и fmt только записываются.
+// Методы безопасны для вызова из команд Bubble Tea (разные горутины).
+type FakeRunner struct {
+	mu sync.Mutex
+
+	Version     string
+	Unavailable bool                           // CheckAvailable вернёт ошибку
+	Responses   map[string]*surge.DiagResponse // ответ Diagnose по targetPath
+	Default     *surge.DiagResponse            // ответ, если пути нет в Responses
+	FixErr      error                          // ошибка для ApplyFixByID, ApplyAllFixes и ApplyOneFix
+	BuildOutput []surge.BuildLine              // вывод StartBuild
+	BuildExit   int                            // код выхода StartBuild
+	FormatErr   error                          // ошибка для Format
+	FormatFunc  func(path string) error        // подмена форматтера, например запись файла
+
+	appliedIDs  []string
+	appliedAll  []string
+	appliedOnce []string
+	initialized []string
+	diagnosed   []string
+	formatted   []string
+}
+
+// ErrUnavailable возвращает CheckAvailable, когда Unavailable выставлен.
+var ErrUnavailable = errors.New("surge binary not available")
+
+// NewFakeRunner создает подмену, которая на любой путь отвечает resp.
+func NewFakeRunner(resp *surge.DiagResponse) *FakeRunner {
+	return &FakeRunner{
+		Version:   "surge (fake)",
+		Responses: make(map[string]*surge.DiagResponse),
+		Default:   resp,
+	}
+}
+
+var _ surge.SurgeRunner = (*FakeRunner)(nil)
+
+func (f *FakeRunner) CheckAvailable(ctx context.Context) error {
+	if f.Unavailable {
+		return ErrUnavailable
+	}
+	return ctx.Err()
+}
+
+func (f *FakeRunner) GetVersion(ctx context.Context) (string, error) {
+	if err := f.CheckAvailable(ctx); err != nil {
+		return "", err
+	}
+	return f.Version, nil
+}
+
+func (f *FakeRunner) Diagnose(ctx context.Context, targetPath string, withNotes, withFixes bool) (*surge.DiagResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.diagnosed = append(f.diagnosed, targetPath)
+	resp, ok := f.Responses[targetPath]
+	if !ok {
+		resp = f.Default
+	}
+	if resp == nil {
+		return &surge.DiagResponse{Single: &surge.DiagnosticsOutput{}}, nil
+	}
+	return resp, resp.Err
+}
+
+// StartDiagnose отдаёт тот же ответ, что и Diagnose, по файлам.
+func (f *FakeRunner) StartDiagnose(ctx context.Context, targetPath string, withNotes, withFixes bool) (*surge.DiagRun, error) {
+	resp, err := f.Diagnose(ctx, targetPath, withNotes, withFixes)
+	if resp == nil && err != nil {
+		return nil, err
+	}
+	return surge.ReplayDiag(resp, err), nil
+}
+
+// DiagnoseFiles вызывает Diagnose для каждого пути и собирает ответы в пакет.
+func (f *FakeRunner) DiagnoseFiles(ctx context.Context, paths []string, withNotes, withFixes bool) (*surge.DiagResponse, error) {
+	return surge.DiagnoseEach(ctx, paths, func(ctx context.Context, path string) (*surge.DiagResponse, error) {
+		return f.Diagnose(ctx, path, withNotes, withFixes)
+	})
+}
+
+func (f *FakeRunner) InitProject(ctx context.Context, projectPath string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.initialized = append(f.initialized, projectPath)
+	return nil
+}
+
+func (f *FakeRunner) ApplyFixByID(ctx context.Context, filePath, fixID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.FixErr != nil {
+		return f.FixErr
+	}
+	f.appliedIDs = append(f.appliedIDs, fixID)
+	return nil
+}
+
+func (f *FakeRunner) ApplyAllFixes(ctx context.Context, targetPath string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.FixErr != nil {
+		return f.FixErr
+	}
+	f.appliedAll = append(f.appliedAll, targetPath)
+	return nil
+}
+
+func (f *FakeRunner) ApplyOneFix(ctx context.Context, targetPath string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.FixErr != nil {
+		return f.FixErr
+	}
+	f.appliedOnce = append(f.appliedOnce, targetPath)
+	return nil
+}
+
+// Format записывает путь и вызывает FormatFunc, если она задана.
+func (f *FakeRunner) Format(ctx context.Context, path string) error {
+	f.mu.Lock()
+	f.formatted = append(f.formatted, path)
+	format, err := f.FormatFunc, f.FormatErr
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if format != nil {
+		return format(path)
+	}
+	return ctx.Err()
+}
+
+// StartBuild сразу возвращает завершённую сборку с BuildOutput и BuildExit.
+func (f *FakeRunner) StartBuild(ctx context.Context, projectPath string) (*surge.BuildRun, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return surge.ReplayBuild(f.BuildOutput, f.BuildExit, nil), nil
+}
+
+// AppliedFixIDs возвращает ID фиксов, применённых через ApplyFixByID, в порядке вызовов.
+func (f *FakeRunner) AppliedFixIDs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.appliedIDs...)
+}
+
+// AppliedAll возвращает пути, для которых вызывался ApplyAllFixes.
+func (f *FakeRunner) AppliedAll() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.appliedAll...)
+}
+
+// AppliedOnce возвращает пути, для которых вызывался ApplyOneFix.
+func (f *FakeRunner) AppliedOnce() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.appliedOnce...)
+}
+
+// Initialized возвращает пути, для которых вызывался InitProject.
+func (f *FakeRunner) Initialized() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.initialized...)
+}
+
+// Diagnosed возвращает пути всех вызовов Diagnose.
+func (f *FakeRunner) Diagnosed() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.diagnosed...)
+}
+
+// Formatted возвращает пути всех вызовов Format.
+func (f *FakeRunner) Formatted() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.formatted...)
+}
@@ -0,0 +1,45 @@
+package testsupport
+
+import "surge-tui/internal/core/surge"
+
+// SingleFile собирает ответ diag для одного файла; путь проставляется
+// в Location.File тех диагностик, где он пустой.
+func SingleFile(path string, diags ...surge.DiagnosticJSON) *surge.DiagResponse {
+	return &surge.DiagResponse{Single: fileOutput(path, diags)}
+}
+
+// Batch собирает ответ diag для директории: путь файла → его диагностики.
+func Batch(files map[string][]surge.DiagnosticJSON) *surge.DiagResponse {
+	batch := make(map[string]surge.DiagnosticsOutput, len(files))
+	for path, diags := range files {
+		batch[path] = *fileOutput(path, diags)
+	}
+	return &surge.DiagResponse{Batch: batch}
+}
+
+// Diag — диагностика с позицией line:col (с единицы) и необязательными фиксами.
+func Diag(severity, code, message string, line, col uint32, fixes ...surge.FixJSON) surge.DiagnosticJSON {
+	return surge.DiagnosticJSON{
+		Severity: severity,
+		Code:     code,
+		Message:  message,
+		Location: surge.LocationJSON{StartLine: line, StartCol: col, EndLine: line, EndCol: col},
+		Fixes:    fixes,
+	}
+}
+
+// SafeFix — фикс с ID; Fix Mode показывает его и без режима suggested.
+func SafeFix(id, title string) surge.FixJSON {
+	return surge.FixJSON{ID: id, Title: title, Kind: "quickfix", Applicability: "safe"}
+}
+
+func fileOutput(path string, diags []surge.DiagnosticJSON) *surge.DiagnosticsOutput {
+	out := &surge.DiagnosticsOutput{Count: len(diags)}
+	for _, d := range diags {
+		if d.Location.File == "" {
+			d.Location.File = path
+		}
+		out.Diagnostics = append(out.Diagnostics, d)
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package surge
+
+import (
+	"fmt"
+	"time"
+)
+
+// BuildResult результат сборки
+type BuildResult struct {
+	ProjectPath string
+	Success     bool
+	StartTime   time.Time
+	EndTime     time.Time
+	Duration    time.Duration
+	Diagnostics []Diagnostic
+	ErrorOutput []string
+	Error       error
+}
+
+// Diagnostic диагностическое сообщение
+type Diagnostic struct {
+	Level   string    `json:"level"`   // error, warning, info
+	Code    string    `json:"code"`    // Код ошибки
+	Message string    `json:"message"` // Текст сообщения
+	File    string    `json:"file"`    // Путь к файлу
+	Line    int       `json:"line"`    // Номер строки
+	Column  int       `json:"column"`  // Номер колонки
+	Span    SpanInfo  `json:"span"`    // Информация о диапазоне
+	Fixes   []FixJSON `json:"fixes"`   // Доступные автофиксы
+}
+
+// SpanInfo информация о диапазоне в файле
+type SpanInfo struct {
+	StartLine   int `json:"start_line"`
+	StartColumn int `json:"start_column"`
+	EndLine     int `json:"end_line"`
+	EndColumn   int `json:"end_column"`
+}
+
+// String возвращает строковое представление диагностики
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s [%s]",
+		d.File, d.Line, d.Column, d.Level, d.Message, d.Code)
+}
+
+// IsError проверяет, является ли диагностика ошибкой
+func (d *Diagnostic) IsError() bool {
+	return d.Level == "error"
+}
+
+// IsWarning проверяет, является ли диагностика предупреждением
+func (d *Diagnostic) IsWarning() bool {
+	return d.Level == "warning"
+}
+
+// ===== JSON контракты для surge diag --format=json =====
+
+// LocationJSON описывает местоположение в файле
+type LocationJSON struct {
+	File      string `json:"file"`
+	StartByte uint32 `json:"start_byte"`
+	EndByte   uint32 `json:"end_byte"`
+	StartLine uint32 `json:"start_line,omitempty"`
+	StartCol  uint32 `json:"start_col,omitempty"`
+	EndLine   uint32 `json:"end_line,omitempty"`
+	EndCol    uint32 `json:"end_col,omitempty"`
+}
+
+// NoteJSON — дополнительная заметка
+type NoteJSON struct {
+	Message  string       `json:"message"`
+	Location LocationJSON `json:"location"`
+}
+
+// FixEditJSON — одно редактирование
+type FixEditJSON struct {
+	Location LocationJSON `json:"location"`
+	NewText  string       `json:"new_text"`
+	OldText  string       `json:"old_text,omitempty"`
+}
+
+// FixJSON — описание автофикса
+type FixJSON struct {
+	ID            string        `json:"id,omitempty"`
+	Title         string        `json:"title"`
+	Kind          string        `json:"kind"`
+	Applicability string        `json:"applicability"`
+	IsPreferred   bool          `json:"is_preferred,omitempty"`
+	BuildError    string        `json:"build_error,omitempty"`
+	Edits         []FixEditJSON `json:"edits,omitempty"`
+}
+
+// DiagnosticJSON — диагностика (JSON)
+type DiagnosticJSON struct {
+	Severity string       `json:"severity"`
+	Code     string       `json:"code"`
+	Message  string       `json:"message"`
+	Location LocationJSON `json:"location"`
+	Notes    []NoteJSON   `json:"notes,omitempty"`
+	Fixes    []FixJSON    `json:"fixes,omitempty"`
+}
+
+// DiagnosticsOutput — корень JSON для одного файла
+type DiagnosticsOutput struct {
+	Diagnostics []DiagnosticJSON `json:"diagnostics"`
+	Count       int              `json:"count"`
+}
+
+// DiagResponse — объединённый ответ от diag
+type DiagResponse struct {
+	Single   *DiagnosticsOutput
+	Batch    map[string]DiagnosticsOutput
+	ExitCode int
+	Raw      []byte
+	Err      error
+}
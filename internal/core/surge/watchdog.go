@@ -0,0 +1,135 @@
+package surge
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Сторож запуска: вывод surge читается по мере появления, и если процесс
+// молчит дольше порога, подписчик из контекста узнаёт об этом, пока запуск
+// ещё идёт. Процесс при этом не убивается. Если запуск всё же упрётся в
+// таймаут, ошибка покажет последние строки вывода и сколько surge молчал.
+
+// DefaultStallThreshold — порог молчания surge, пока он не задан из конфига.
+const DefaultStallThreshold = 20 * time.Second
+
+// stallTick — как часто сторож проверяет молчание.
+const stallTick = time.Second
+
+// Stall — запуск surge молчит дольше порога.
+type Stall struct {
+	Command string        // "surge diag"
+	Silent  time.Duration // 0 — вывод снова пошёл
+}
+
+type stallKey struct{}
+
+// WithStallWatch подписывает notify на молчание запусков surge с контекстом
+// ctx. notify вызывается из горутины сторожа раз в секунду, пока запуск
+// молчит, и ещё раз с нулевым Silent, когда вывод возобновился.
+func WithStallWatch(ctx context.Context, notify func(Stall)) context.Context {
+	return context.WithValue(ctx, stallKey{}, notify)
+}
+
+// SetStallThreshold задаёт, после скольких секунд молчания surge подписчики
+// получают предупреждение; 0 — не предупреждать.
+func (c *Client) SetStallThreshold(threshold time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stall = threshold
+}
+
+// outputWatch принимает вывод запуска: помнит время последней записи и
+// последние stderrLimit байт.
+type outputWatch struct {
+	mu   sync.Mutex
+	last time.Time
+	tail []byte
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// watch начинает следить за выводом запуска с аргументами args (как в
+// cmd.Args). Сторож молчания запускается, только если в ctx есть подписчик
+// и порог задан, и живёт до finish или конца ctx.
+func (c *Client) watch(ctx context.Context, args []string) *outputWatch {
+	c.mu.RLock()
+	threshold := c.stall
+	c.mu.RUnlock()
+	w := &outputWatch{last: time.Now(), stop: make(chan struct{})}
+	notify, _ := ctx.Value(stallKey{}).(func(Stall))
+	if notify == nil || threshold <= 0 {
+		return w
+	}
+	command := "surge"
+	if len(args) > 1 {
+		command += " " + args[1]
+	}
+	go w.guard(ctx, command, threshold, notify)
+	return w
+}
+
+func (w *outputWatch) guard(ctx context.Context, command string, threshold time.Duration, notify func(Stall)) {
+	ticker := time.NewTicker(stallTick)
+	defer ticker.Stop()
+	stalled := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			silent := w.silence()
+			switch {
+			case silent >= threshold:
+				stalled = true
+				notify(Stall{Command: command, Silent: silent.Truncate(time.Second)})
+			case stalled:
+				stalled = false
+				notify(Stall{Command: command})
+			}
+		}
+	}
+}
+
+func (w *outputWatch) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.last = time.Now()
+	w.tail = append(w.tail, p...)
+	if extra := len(w.tail) - stderrLimit; extra > 0 {
+		w.tail = append(w.tail[:0], w.tail[extra:]...)
+	}
+	return len(p), nil
+}
+
+// silence — сколько запуск ничего не выводил.
+func (w *outputWatch) silence() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return time.Since(w.last)
+}
+
+func (w *outputWatch) output() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]byte(nil), w.tail...)
+}
+
+// finish останавливает сторожа. Ошибку таймаута дополняет тем, что surge
+// вывел последним, и тем, сколько он перед этим молчал.
+func (w *outputWatch) finish(err error) error {
+	w.once.Do(func() { close(w.stop) })
+	var cliErr *CLIError
+	if errors.As(err, &cliErr) && errors.Is(cliErr.Err, context.DeadlineExceeded) {
+		cliErr.Idle = w.silence().Truncate(time.Second)
+		if tail := trimOutput(w.output()); tail != "" {
+			cliErr.Stderr = tail
+		}
+	}
+	return err
+}
@@ -0,0 +1,178 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// CopyProgress получает число скопированных файлов из total.
+type CopyProgress func(done, total int)
+
+// CountFiles считает файлы (не каталоги) в path; для файла — 1.
+func CountFiles(path string) int {
+	count := 0
+	_ = filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+// CopyEntry рекурсивно копирует файл или каталог src в dst с сохранением
+// прав доступа. Символические ссылки копируются как ссылки. dst не должен
+// существовать; progress (может быть nil) вызывается после каждого файла.
+// Если копирование прервалось, частично созданный dst удаляется.
+func CopyEntry(src, dst string, progress CopyProgress) error {
+	if IsWithin(dst, src) {
+		return fmt.Errorf("cannot copy %s into itself", filepath.Base(src))
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("%s already exists", filepath.Base(dst))
+	}
+	if err := copyTree(src, dst, progress); err != nil {
+		_ = os.RemoveAll(dst)
+		return err
+	}
+	return nil
+}
+
+// dirMode — каталог копии и права, которые он получит после копирования.
+type dirMode struct {
+	path string
+	perm os.FileMode
+}
+
+func copyTree(src, dst string, progress CopyProgress) error {
+	total := 0
+	if progress != nil {
+		total = CountFiles(src)
+	}
+	done := 0
+	// каталоги создаются с правом записи для владельца, иначе в каталог только
+	// для чтения не записать детей; исходные права возвращаются в конце
+	var restore []dirMode
+	err := filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			perm := info.Mode().Perm()
+			if perm|0o700 != perm {
+				restore = append(restore, dirMode{path: target, perm: perm})
+			}
+			return os.Mkdir(target, perm|0o700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+		default:
+			if err := copyFile(path, target, info.Mode().Perm()); err != nil {
+				return err
+			}
+		}
+		done++
+		if progress != nil {
+			progress(done, total)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// вложенные каталоги раньше внешних: закрытый внешний не мешает chmod внутри
+	for i := len(restore) - 1; i >= 0; i-- {
+		if err := os.Chmod(restore[i].path, restore[i].perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MoveEntry переносит src в dst. Между файловыми системами, где rename
+// невозможен, src копируется и затем удаляется.
+func MoveEntry(src, dst string, progress CopyProgress) error {
+	if IsWithin(dst, src) {
+		return fmt.Errorf("cannot move %s into itself", filepath.Base(src))
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("%s already exists", filepath.Base(dst))
+	}
+	err := os.Rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := CopyEntry(src, dst, progress); err != nil {
+		_ = os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// IsWithin сообщает, совпадает ли path с dir или лежит внутри него.
+func IsWithin(path, dir string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// FreeName возвращает имя для копии name в dir, которое ещё не занято:
+// name, затем "base copy.ext", "base copy 2.ext" и т.д.
+func FreeName(dir, name string) string {
+	if _, err := os.Lstat(filepath.Join(dir, name)); os.IsNotExist(err) {
+		return name
+	}
+	ext := filepath.Ext(name)
+	if ext == name { // скрытый файл вида .env
+		ext = ""
+	}
+	base := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		candidate := base + " copy" + ext
+		if i > 1 {
+			candidate = fmt.Sprintf("%s copy %d%s", base, i, ext)
+		}
+		if _, err := os.Lstat(filepath.Join(dir, candidate)); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,81 @@
+package fs
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// unreadableEntry создаёт в dir сокет: WalkDir видит его как файл, а открыть
+// на чтение его нельзя даже root'у.
+func unreadableEntry(t *testing.T, dir string) {
+	t.Helper()
+	l, err := net.Listen("unix", filepath.Join(dir, "sock"))
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+}
+
+func TestCopyEntryRemovesPartialCopy(t *testing.T) {
+	base := t.TempDir()
+	src := filepath.Join(base, "src")
+	if err := os.MkdirAll(filepath.Join(src, "a"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "a", "file"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unreadableEntry(t, src)
+
+	dst := filepath.Join(base, "dst")
+	if err := CopyEntry(src, dst, nil); err == nil {
+		t.Fatal("CopyEntry succeeded on an unreadable entry")
+	}
+	if _, err := os.Lstat(dst); !os.IsNotExist(err) {
+		t.Errorf("partial copy left at %s (err=%v)", dst, err)
+	}
+}
+
+func TestCopyEntryKeepsDirModes(t *testing.T) {
+	base := t.TempDir()
+	src := filepath.Join(base, "src")
+	inner := filepath.Join(src, "inner")
+	if err := os.MkdirAll(inner, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(inner, "file"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	modes := map[string]os.FileMode{"": 0o555, "inner": 0o500}
+	for rel, perm := range modes {
+		if err := os.Chmod(filepath.Join(src, rel), perm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dst := filepath.Join(base, "dst")
+	// каталоги только для чтения иначе не удалит очистка TempDir
+	t.Cleanup(func() {
+		for rel := range modes {
+			os.Chmod(filepath.Join(src, rel), 0o755)
+			os.Chmod(filepath.Join(dst, rel), 0o755)
+		}
+	})
+
+	if err := CopyEntry(src, dst, nil); err != nil {
+		t.Fatal(err)
+	}
+	for rel, want := range modes {
+		info, err := os.Stat(filepath.Join(dst, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%q mode = %#o, want %#o", rel, got, want)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(dst, "inner", "file")); err != nil || string(data) != "x" {
+		t.Errorf("copied file = %q, %v", data, err)
+	}
+}
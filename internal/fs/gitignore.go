@@ -0,0 +1,193 @@
+package fs
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitIgnore проверяет пути проекта по файлам .gitignore — корневому и
+// вложенным — с обычной семантикой git: комментарии, отрицание «!»,
+// шаблоны только для каталогов «dir/», привязка к каталогу файла через «/»
+// и «**» для любого числа каталогов. Правила каталога читаются при первом
+// обращении и перечитываются, когда .gitignore изменился. Безопасен для
+// одновременного использования из нескольких горутин.
+type GitIgnore struct {
+	root string
+
+	mu   sync.Mutex
+	dirs map[string]*ignoreFile
+}
+
+// ignoreFile — правила одного .gitignore и время его изменения.
+type ignoreFile struct {
+	modTime time.Time
+	rules   []ignoreRule
+}
+
+// ignoreRule — одна строка .gitignore.
+type ignoreRule struct {
+	segments []string // шаблон, разбитый по «/»
+	negate   bool     // «!шаблон» возвращает исключённое
+	dirOnly  bool     // «шаблон/» — только каталоги
+	anchored bool     // шаблон со «/» сопоставляется от каталога .gitignore
+}
+
+// NewGitIgnore создает проверку для проекта с корнем root.
+func NewGitIgnore(root string) *GitIgnore {
+	return &GitIgnore{root: filepath.Clean(root), dirs: make(map[string]*ignoreFile)}
+}
+
+// Ignored сообщает, исключён ли путь. Решает последнее подходящее правило;
+// правила вложенного .gitignore сильнее правил вышестоящих. Родительские
+// каталоги не проверяются: дерево и обход не заходят в исключённые каталоги,
+// а вернуть файл из исключённого каталога git тоже не позволяет.
+func (g *GitIgnore) Ignored(p string, isDir bool) bool {
+	if g == nil {
+		return false
+	}
+	rel, err := filepath.Rel(g.root, filepath.Clean(p))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	ignored := false
+	dir := g.root
+	parts := strings.Split(rel, "/")
+	for i := range parts {
+		sub := strings.Join(parts[i:], "/")
+		for _, rule := range g.rules(dir) {
+			if rule.match(sub, isDir) {
+				ignored = !rule.negate
+			}
+		}
+		dir = filepath.Join(dir, parts[i])
+	}
+	return ignored
+}
+
+// rules возвращает правила .gitignore каталога dir, перечитывая изменённый файл.
+func (g *GitIgnore) rules(dir string) []ignoreRule {
+	file := filepath.Join(dir, ".gitignore")
+	info, err := os.Stat(file)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err != nil || info.IsDir() {
+		delete(g.dirs, dir)
+		return nil
+	}
+	if cached, ok := g.dirs[dir]; ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.rules
+	}
+	cached := &ignoreFile{modTime: info.ModTime(), rules: readIgnoreFile(file)}
+	g.dirs[dir] = cached
+	return cached.rules
+}
+
+func readIgnoreFile(file string) []ignoreRule {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreLine(scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseIgnoreLine разбирает строку .gitignore; пустые строки и комментарии
+// дают ok == false.
+func parseIgnoreLine(line string) (ignoreRule, bool) {
+	line = strings.TrimSuffix(line, "\r")
+	line = trimIgnoreSpaces(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	var rule ignoreRule
+	switch {
+	case strings.HasPrefix(line, "!"):
+		rule.negate = true
+		line = line[1:]
+	case strings.HasPrefix(line, `\!`), strings.HasPrefix(line, `\#`):
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	// «/» в начале или середине привязывает шаблон к каталогу .gitignore
+	rule.anchored = strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	rule.segments = strings.Split(line, "/")
+	return rule, true
+}
+
+// trimIgnoreSpaces убирает пробелы в конце строки, кроме экранированных «\ ».
+func trimIgnoreSpaces(line string) string {
+	end := len(line)
+	for end > 0 && line[end-1] == ' ' {
+		if end > 1 && line[end-2] == '\\' {
+			break
+		}
+		end--
+	}
+	return line[:end]
+}
+
+// match сопоставляет путь rel (через «/», от каталога .gitignore).
+func (r ignoreRule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	parts := strings.Split(rel, "/")
+	if !r.anchored {
+		// шаблон без «/» сравнивается с именем на любой глубине
+		return matchSegment(r.segments[0], parts[len(parts)-1])
+	}
+	return matchSegments(r.segments, parts)
+}
+
+// matchSegments сопоставляет шаблон по частям: «**» — любое число каталогов,
+// «**» в конце — всё внутри, но не сам каталог.
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return len(parts) > 0
+			}
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 || !matchSegment(pattern[0], parts[0]) {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+func matchSegment(pattern, name string) bool {
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
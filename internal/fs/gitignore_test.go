@@ -0,0 +1,127 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeIgnore записывает .gitignore в каталог dir проекта root.
+func writeIgnore(t *testing.T, root, dir, content string) string {
+	t.Helper()
+	full := filepath.Join(root, dir)
+	if err := os.MkdirAll(full, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(full, ".gitignore")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestGitIgnore(t *testing.T) {
+	root := t.TempDir()
+	writeIgnore(t, root, "", `# комментарий
+*.log
+!keep.log
+build/
+/root-only.txt
+docs/*.tmp
+**/gen/**
+a/**/z.txt
+\#hash
+`)
+	writeIgnore(t, root, "sub", `!*.log
+secret.txt
+/only-here.txt
+`)
+	g := NewGitIgnore(root)
+
+	tests := []struct {
+		name  string
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"шаблон по имени", "app.log", false, true},
+		{"шаблон по имени в глубине", "deep/x/app.log", false, true},
+		{"отрицание", "keep.log", false, false},
+		{"комментарий не правило", "# комментарий", false, false},
+		{"экранированная решётка", "#hash", false, true},
+
+		{"dir/ для каталога", "build", true, true},
+		{"dir/ не для файла", "build", false, false},
+		{"dir/ на любой глубине", "src/build", true, true},
+
+		{"привязка к корню", "root-only.txt", false, true},
+		{"привязанный шаблон не в глубине", "src/root-only.txt", false, false},
+		{"шаблон со слешем от корня", "docs/a.tmp", false, true},
+		{"звёздочка не пересекает каталоги", "docs/sub/a.tmp", false, false},
+		{"шаблон со слешем не в подкаталоге", "other/docs/a.tmp", false, false},
+
+		{"**/x/** в корне", "gen/x.go", false, true},
+		{"**/x/** в глубине", "a/b/gen/x.go", false, true},
+		{"**/x/** не сам каталог", "gen", true, false},
+		{"a/**/z без промежуточных", "a/z.txt", false, true},
+		{"a/**/z через каталоги", "a/b/c/z.txt", false, true},
+		{"a/**/z не от корня", "b/a/z.txt", false, false},
+
+		{"вложенный отменяет корневой", "sub/app.log", false, false},
+		{"вложенный не действует выше", "secret.txt", false, false},
+		{"правило вложенного", "sub/secret.txt", false, true},
+		{"привязка к вложенному каталогу", "sub/only-here.txt", false, true},
+		{"привязка вложенного не в глубине", "sub/deeper/only-here.txt", false, false},
+		{"корневое правило во вложенном каталоге", "sub/build", true, true},
+
+		{"сам корень", "", true, false},
+		{"вне проекта", "../elsewhere.log", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.Ignored(filepath.Join(root, tt.path), tt.isDir); got != tt.want {
+				t.Errorf("Ignored(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+// Изменённый .gitignore перечитывается при следующей проверке.
+func TestGitIgnoreReloadsChangedFile(t *testing.T) {
+	root := t.TempDir()
+	file := writeIgnore(t, root, "", "*.tmp\n")
+	g := NewGitIgnore(root)
+	path := filepath.Join(root, "x.tmp")
+	if !g.Ignored(path, false) {
+		t.Fatal("*.tmp did not match")
+	}
+
+	if err := os.WriteFile(file, []byte("*.bak\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if g.Ignored(path, false) {
+		t.Error("stale rules used after .gitignore changed")
+	}
+	if !g.Ignored(filepath.Join(root, "x.bak"), false) {
+		t.Error("new rule not applied")
+	}
+
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	if g.Ignored(filepath.Join(root, "x.bak"), false) {
+		t.Error("rules kept after .gitignore was removed")
+	}
+}
+
+func TestGitIgnoreNil(t *testing.T) {
+	var g *GitIgnore
+	if g.Ignored("/any/path", false) {
+		t.Error("nil GitIgnore ignored a path")
+	}
+}
@@ -0,0 +1,219 @@
+package fs
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SkipWatchDirs — директории, которые наблюдатель проекта не обходит:
+// в больших деревьях они дают тысячи лишних watch-дескрипторов.
+var SkipWatchDirs = map[string]bool{
+	".git":         true,
+	".hg":          true,
+	".svn":         true,
+	"node_modules": true,
+}
+
+// minPollInterval — нижняя граница опроса: refresh_rate рассчитан на отрисовку UI,
+// обходить дерево проекта так часто слишком дорого.
+const minPollInterval = 250 * time.Millisecond
+
+// ProjectWatcher сообщает об изменениях файлов проекта, прошедших фильтр match.
+// Работает через fsnotify, а если он недоступен — опрашивает дерево по таймеру.
+type ProjectWatcher struct {
+	root    string
+	match   func(path string) bool
+	events  chan struct{} // ёмкость 1: пачка событий схлопывается в одно
+	done    chan struct{}
+	once    sync.Once
+	fw      *FileWatcher
+	polling bool
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// WatchProject начинает наблюдение за root. pollInterval используется только
+// в режиме опроса и не бывает меньше minPollInterval.
+func WatchProject(root string, match func(path string) bool, pollInterval time.Duration) *ProjectWatcher {
+	pw := &ProjectWatcher{
+		root:   filepath.Clean(root),
+		match:  match,
+		events: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	if err := pw.startNotify(); err != nil {
+		pw.polling = true
+		go pw.pollLoop(max(pollInterval, minPollInterval))
+	}
+	return pw
+}
+
+// Polling сообщает, что fsnotify недоступен и изменения ищутся опросом.
+func (pw *ProjectWatcher) Polling() bool {
+	return pw.polling
+}
+
+// Next ждёт изменения и затем тишины длиной quiet, чтобы пачка событий
+// (сохранение, git checkout) дала один сигнал. Возвращает false после Close.
+func (pw *ProjectWatcher) Next(quiet time.Duration) bool {
+	select {
+	case <-pw.done:
+		return false
+	case <-pw.events:
+	}
+	timer := time.NewTimer(quiet)
+	defer timer.Stop()
+	for {
+		select {
+		case <-pw.done:
+			return false
+		case <-pw.events:
+			timer.Reset(quiet)
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// Close останавливает наблюдение; повторные вызовы безопасны.
+func (pw *ProjectWatcher) Close() {
+	pw.once.Do(func() {
+		close(pw.done)
+		if pw.fw != nil {
+			_ = pw.fw.Close()
+		}
+	})
+}
+
+func (pw *ProjectWatcher) startNotify() error {
+	fw, err := NewFileWatcher(context.Background())
+	if err != nil {
+		return err
+	}
+	pw.fw = fw
+	fw.AddCallback(pw.root, pw.handleEvent)
+	if err := pw.watchTree(pw.root); err != nil {
+		_ = fw.Close()
+		pw.fw = nil
+		return err
+	}
+	return nil
+}
+
+// watchTree добавляет в fsnotify dir и все вложенные директории (он не рекурсивный).
+func (pw *ProjectWatcher) watchTree(dir string) error {
+	return walkProject(dir, func(path string, d os.DirEntry) error {
+		if d.IsDir() {
+			return pw.fw.Watch(path)
+		}
+		return nil
+	})
+}
+
+func (pw *ProjectWatcher) handleEvent(event FileChangeEvent) {
+	if event.IsDir {
+		if event.Operation == FileCreated && !SkipWatchDirs[filepath.Base(event.Path)] {
+			_ = pw.watchTree(event.Path)
+		}
+		return
+	}
+	if pw.match(event.Path) {
+		pw.notify()
+	}
+}
+
+func (pw *ProjectWatcher) notify() {
+	select {
+	case pw.events <- struct{}{}:
+	default:
+	}
+}
+
+func (pw *ProjectWatcher) pollLoop(interval time.Duration) {
+	prev := pw.snapshot()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pw.done:
+			return
+		case <-ticker.C:
+			next := pw.snapshot()
+			if !maps.Equal(prev, next) {
+				pw.notify()
+			}
+			prev = next
+		}
+	}
+}
+
+// snapshot собирает время изменения и размер подходящих файлов.
+func (pw *ProjectWatcher) snapshot() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	_ = walkProject(pw.root, func(path string, d os.DirEntry) error {
+		if d.IsDir() || !pw.match(path) {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+		return nil
+	})
+	return stamps
+}
+
+// ProjectStamp — отпечаток файлов проекта: их число, общий размер и время
+// последнего изменения. Разные отпечатки значат, что файлы добавлялись,
+// удалялись или менялись.
+type ProjectStamp struct {
+	Files  int
+	Size   int64
+	Newest time.Time
+}
+
+// Equal сообщает, что отпечатки совпадают.
+func (s ProjectStamp) Equal(other ProjectStamp) bool {
+	return s.Files == other.Files && s.Size == other.Size && s.Newest.Equal(other.Newest)
+}
+
+// StampProject снимает отпечаток файлов root, прошедших фильтр match.
+func StampProject(root string, match func(path string) bool) ProjectStamp {
+	var stamp ProjectStamp
+	_ = walkProject(filepath.Clean(root), func(path string, d os.DirEntry) error {
+		if d.IsDir() || !match(path) {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			stamp.Files++
+			stamp.Size += info.Size()
+			if info.ModTime().After(stamp.Newest) {
+				stamp.Newest = info.ModTime()
+			}
+		}
+		return nil
+	})
+	return stamp
+}
+
+// walkProject обходит дерево, пропуская SkipWatchDirs и недоступные подкаталоги.
+func walkProject(root string, fn func(path string, d os.DirEntry) error) error {
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if d.IsDir() && path != root && SkipWatchDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		return fn(path, d)
+	})
+}
@@ -17,6 +17,15 @@ type FileNode struct {
 	Parent   *FileNode   `json:"-"`
 	Level    int         `json:"level"`
 	Expanded bool        `json:"expanded"`
+	Loading  bool        `json:"-"` // содержимое каталога читается в фоне
+	loaded   bool        // содержимое каталога прочитано
+
+	// Marked — узел отмечен для групповой операции. Отметка живёт в самом
+	// узле, поэтому переживает сворачивание и пропадает при пересборке дерева.
+	Marked bool `json:"-"`
+
+	// Placeholder — строка «загрузка…» под читающимся каталогом; не файл
+	Placeholder bool `json:"-"`
 }
 
 // FileTree дерево файлов
@@ -25,17 +34,45 @@ type FileTree struct {
 	FlatList    []*FileNode // Плоский список для навигации
 	Selected    int
 	ShowHidden  bool
+	ShowIgnored bool // Показывать исключённое .gitignore
 	FilterSurge bool // Показывать только .sg файлы
+	DirsOnly    bool // Только каталоги; вложенные читаются при раскрытии
+	Lazy        bool // Вложенные каталоги читаются при первом раскрытии
+
+	// Ignore — имена файлов и каталогов, которые не читаются (.git, node_modules)
+	Ignore map[string]bool
+
+	// GitIgnore — правила .gitignore проекта; nil — не применяются
+	GitIgnore *GitIgnore
+
+	// Visible скрывает узлы только в плоском списке, не перечитывая диск;
+	// nil — показываются все прочитанные узлы. Корень виден всегда.
+	Visible func(node *FileNode) bool
 }
 
 // NewFileTree создает новое дерево файлов
 func NewFileTree(rootPath string) (*FileTree, error) {
-	tree := &FileTree{
-		Selected:    0,
-		ShowHidden:  false,
-		FilterSurge: false,
+	return newTree(&FileTree{}, rootPath)
+}
+
+// NewDirTree создает дерево только из каталогов. Содержимое вложенных
+// каталогов читается при раскрытии, поэтому корнем может быть и $HOME.
+func NewDirTree(rootPath string) (*FileTree, error) {
+	return newTree(&FileTree{DirsOnly: true}, rootPath)
+}
+
+// NewLazyFileTree создает дерево, в котором сразу читается только корень,
+// а вложенные каталоги — при раскрытии (см. StartLoad). Записи с именами
+// из ignore и исключённые .gitignore проекта пропускаются.
+func NewLazyFileTree(rootPath string, ignore []string) (*FileTree, error) {
+	tree := &FileTree{Lazy: true, Ignore: make(map[string]bool, len(ignore)), GitIgnore: NewGitIgnore(rootPath)}
+	for _, name := range ignore {
+		tree.Ignore[name] = true
 	}
+	return newTree(tree, rootPath)
+}
 
+func newTree(tree *FileTree, rootPath string) (*FileTree, error) {
 	root, err := tree.buildNode(rootPath, nil, 0)
 	if err != nil {
 		return nil, err
@@ -65,46 +102,193 @@ func (ft *FileTree) buildNode(path string, parent *FileNode, level int) (*FileNo
 		Expanded: false,
 	}
 
-	// Для директорий загружаем содержимое
-	if node.IsDir {
-		entries, err := os.ReadDir(path)
-		if err != nil {
-			return node, nil // Возвращаем узел без детей если нет доступа
+	// Для директорий загружаем содержимое; в ленивом дереве — только корень
+	if node.IsDir && (!ft.lazy() || level == 0) {
+		ft.loadChildren(node)
+	}
+
+	return node, nil
+}
+
+func (ft *FileTree) lazy() bool {
+	return ft.DirsOnly || ft.Lazy
+}
+
+// loadChildren читает содержимое каталога
+func (ft *FileTree) loadChildren(node *FileNode) {
+	node.Children = ft.readChildren(node)
+	node.loaded = true
+	node.Loading = false
+}
+
+// readChildren читает детей каталога, не меняя сам узел.
+func (ft *FileTree) readChildren(node *FileNode) []*FileNode {
+	entries, err := os.ReadDir(node.Path)
+	if err != nil {
+		return nil // Оставляем узел без детей если нет доступа
+	}
+
+	var children []*FileNode
+	for _, entry := range entries {
+		if !ft.includeEntry(node.Path, entry) {
+			continue
 		}
 
-		for _, entry := range entries {
-			// Пропускаем скрытые файлы если не включен показ
-			if !ft.ShowHidden && strings.HasPrefix(entry.Name(), ".") {
-				continue
-			}
+		childPath := filepath.Join(node.Path, entry.Name())
+		child, err := ft.buildNode(childPath, node, node.Level+1)
+		if err != nil || (ft.DirsOnly && !child.IsDir) {
+			continue // Пропускаем проблемные файлы
+		}
 
-			// Фильтр по .sg файлам
-			if ft.FilterSurge && !entry.IsDir() {
-				if !strings.HasSuffix(entry.Name(), ".sg") {
-					continue
-				}
-			}
+		children = append(children, child)
+	}
 
-			childPath := filepath.Join(path, entry.Name())
-			child, err := ft.buildNode(childPath, node, level+1)
-			if err != nil {
-				continue // Пропускаем проблемные файлы
-			}
+	// Сортируем: сначала директории, потом файлы, по алфавиту
+	sort.Slice(children, func(i, j int) bool {
+		a, b := children[i], children[j]
+		if a.IsDir != b.IsDir {
+			return a.IsDir // Директории идут первыми
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return children
+}
+
+// includeEntry применяет фильтры чтения к записи каталога dir.
+func (ft *FileTree) includeEntry(dir string, entry os.DirEntry) bool {
+	// Пропускаем скрытые файлы если не включен показ
+	if !ft.ShowHidden && strings.HasPrefix(entry.Name(), ".") {
+		return false
+	}
+	if ft.Ignore[entry.Name()] {
+		return false
+	}
+	if !ft.ShowIgnored && ft.GitIgnore.Ignored(filepath.Join(dir, entry.Name()), entry.IsDir()) {
+		return false
+	}
+
+	// Фильтр по .sg файлам
+	if ft.FilterSurge && !entry.IsDir() && !strings.HasSuffix(entry.Name(), ".sg") {
+		return false
+	}
 
-			node.Children = append(node.Children, child)
+	// Ссылки проверяем через Stat: они могут указывать на каталог
+	if ft.DirsOnly && !entry.IsDir() && entry.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+	return true
+}
+
+// FilterCounts — сколько записей каталога скрыто каждым фильтром показа.
+type FilterCounts struct {
+	Hidden   int // имена с точкой, пока ShowHidden выключен
+	Ignored  int // исключённые .gitignore, пока ShowIgnored выключен
+	NonSurge int // файлы не .sg, пока включён FilterSurge
+}
+
+// Total возвращает, сколько записей скрыто всеми фильтрами вместе.
+func (c FilterCounts) Total() int {
+	return c.Hidden + c.Ignored + c.NonSurge
+}
+
+// CountFiltered перечитывает каталог dir и считает записи, которые скрыли
+// фильтры показа. Имена из Ignore не считаются: они не показываются никогда.
+func (ft *FileTree) CountFiltered(dir string) FilterCounts {
+	var counts FilterCounts
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return counts
+	}
+	for _, entry := range entries {
+		switch name := entry.Name(); {
+		case ft.Ignore[name]:
+		case !ft.ShowHidden && strings.HasPrefix(name, "."):
+			counts.Hidden++
+		case !ft.ShowIgnored && ft.GitIgnore.Ignored(filepath.Join(dir, name), entry.IsDir()):
+			counts.Ignored++
+		case ft.FilterSurge && !entry.IsDir() && !strings.HasSuffix(name, ".sg"):
+			counts.NonSurge++
 		}
+	}
+	return counts
+}
 
-		// Сортируем: сначала директории, потом файлы, по алфавиту
-		sort.Slice(node.Children, func(i, j int) bool {
-			a, b := node.Children[i], node.Children[j]
-			if a.IsDir != b.IsDir {
-				return a.IsDir // Директории идут первыми
-			}
-			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
-		})
+// StartLoad раскрывает непрочитанный каталог с индексом index, показывая под
+// ним строку-заглушку, и возвращает функцию чтения. Функция не трогает дерево
+// и может работать в горутине; результат передаётся в FinishLoad.
+// Для прочитанных каталогов и файлов возвращает nil.
+func (ft *FileTree) StartLoad(index int) (*FileNode, func() []*FileNode) {
+	if index < 0 || index >= len(ft.FlatList) {
+		return nil, nil
+	}
+	node := ft.FlatList[index]
+	if !node.IsDir || node.loaded || node.Loading {
+		return nil, nil
 	}
+	node.Expanded = true
+	node.Loading = true
+	ft.rebuildFlatList()
 
-	return node, nil
+	snapshot := *ft // фильтры на момент раскрытия
+	return node, func() []*FileNode { return snapshot.readChildren(node) }
+}
+
+// FinishLoad подставляет прочитанных детей. Если узел за это время выпал
+// из дерева (Refresh), результат отбрасывается и возвращается false.
+func (ft *FileTree) FinishLoad(node *FileNode, children []*FileNode) bool {
+	top := node
+	for top.Parent != nil {
+		top = top.Parent
+	}
+	if top != ft.Root || node.loaded {
+		return false
+	}
+	var selected *FileNode
+	if ft.Selected >= 0 && ft.Selected < len(ft.FlatList) {
+		selected = ft.FlatList[ft.Selected]
+	}
+	node.Children = children
+	node.loaded = true
+	node.Loading = false
+	ft.rebuildFlatList()
+	for i, n := range ft.FlatList {
+		if n == selected {
+			ft.Selected = i
+			break
+		}
+	}
+	// курсор на заглушке переходит на первого ребёнка или остаётся на строке
+	ft.SetSelected(ft.Selected)
+	return true
+}
+
+// ListFiles обходит диск от корня с фильтрами дерева и возвращает пути всех
+// файлов, включая непрочитанные каталоги. Дерево не меняется.
+func (ft *FileTree) ListFiles() []string {
+	if ft.Root == nil {
+		return nil
+	}
+	var files []string
+	var walk func(dir string)
+	walk = func(dir string) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			if !ft.includeEntry(dir, entry) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if entry.IsDir() {
+				walk(path)
+			} else {
+				files = append(files, path)
+			}
+		}
+	}
+	walk(ft.Root.Path)
+	return files
 }
 
 // rebuildFlatList пересобирает плоский список для навигации
@@ -117,8 +301,20 @@ func (ft *FileTree) rebuildFlatList() {
 
 // addToFlatList добавляет узел и его видимых детей в плоский список
 func (ft *FileTree) addToFlatList(node *FileNode) {
+	if ft.Visible != nil && node != ft.Root && !ft.Visible(node) {
+		return
+	}
 	ft.FlatList = append(ft.FlatList, node)
 
+	if node.IsDir && node.Expanded && node.Loading {
+		ft.FlatList = append(ft.FlatList, &FileNode{
+			Name:        "loading…",
+			Parent:      node,
+			Level:       node.Level + 1,
+			Placeholder: true,
+		})
+		return
+	}
 	if node.IsDir && node.Expanded {
 		for _, child := range node.Children {
 			ft.addToFlatList(child)
@@ -134,6 +330,9 @@ func (ft *FileTree) ToggleExpanded(index int) {
 
 	node := ft.FlatList[index]
 	if node.IsDir {
+		if !node.loaded && !node.Loading {
+			ft.loadChildren(node)
+		}
 		node.Expanded = !node.Expanded
 		ft.rebuildFlatList()
 
@@ -160,34 +359,61 @@ func (ft *FileTree) SetSelected(index int) {
 	}
 }
 
-// GetSelected возвращает выбранный узел
+// GetSelected возвращает выбранный узел; на строке-заглушке — nil
 func (ft *FileTree) GetSelected() *FileNode {
-	if ft.Selected < 0 || ft.Selected >= len(ft.FlatList) {
+	if ft.Selected < 0 || ft.Selected >= len(ft.FlatList) || ft.FlatList[ft.Selected].Placeholder {
 		return nil
 	}
 	return ft.FlatList[ft.Selected]
 }
 
 // SetShowHidden устанавливает показ скрытых файлов
-func (ft *FileTree) SetShowHidden(show bool) {
+func (ft *FileTree) SetShowHidden(show bool) error {
 	if ft.ShowHidden != show {
 		ft.ShowHidden = show
-		ft.Refresh()
+		return ft.Refresh()
+	}
+	return nil
+}
+
+// SetShowIgnored включает показ исключённого .gitignore
+func (ft *FileTree) SetShowIgnored(show bool) error {
+	if ft.ShowIgnored != show {
+		ft.ShowIgnored = show
+		return ft.Refresh()
+	}
+	return nil
+}
+
+// SetVisible задаёт фильтр отображения и пересобирает плоский список,
+// оставляя выбор на том же узле, если он остался виден.
+func (ft *FileTree) SetVisible(visible func(node *FileNode) bool) {
+	selected := ft.GetSelected()
+	ft.Visible = visible
+	ft.rebuildFlatList()
+	ft.Selected = min(ft.Selected, max(len(ft.FlatList)-1, 0))
+	for i, node := range ft.FlatList {
+		if node == selected {
+			ft.Selected = i
+			break
+		}
 	}
 }
 
 // SetFilterSurge устанавливает фильтр по .sg файлам
-func (ft *FileTree) SetFilterSurge(filter bool) {
+func (ft *FileTree) SetFilterSurge(filter bool) error {
 	if ft.FilterSurge != filter {
 		ft.FilterSurge = filter
-		ft.Refresh()
+		return ft.Refresh()
 	}
+	return nil
 }
 
-// Refresh обновляет дерево из файловой системы
-func (ft *FileTree) Refresh() {
+// Refresh обновляет дерево из файловой системы. При ошибке (например,
+// корень пропал) дерево остаётся прежним, а ошибка возвращается вызывающему.
+func (ft *FileTree) Refresh() error {
 	if ft.Root == nil {
-		return
+		return nil
 	}
 
 	// Сохраняем состояние разворота
@@ -196,7 +422,7 @@ func (ft *FileTree) Refresh() {
 	// Пересобираем дерево
 	newRoot, err := ft.buildNode(ft.Root.Path, nil, 0)
 	if err != nil {
-		return
+		return err
 	}
 
 	// Восстанавливаем состояние разворота
@@ -209,6 +435,7 @@ func (ft *FileTree) Refresh() {
 	if ft.Selected >= len(ft.FlatList) {
 		ft.Selected = len(ft.FlatList) - 1
 	}
+	return nil
 }
 
 // getExpandedPaths собирает пути развернутых директорий
@@ -231,6 +458,9 @@ func (ft *FileTree) collectExpandedPaths(node *FileNode, expanded map[string]boo
 // restoreExpandedPaths восстанавливает состояние разворота
 func (ft *FileTree) restoreExpandedPaths(node *FileNode, expanded map[string]bool) {
 	if node.IsDir && expanded[node.Path] {
+		if !node.loaded {
+			ft.loadChildren(node)
+		}
 		node.Expanded = true
 		for _, child := range node.Children {
 			ft.restoreExpandedPaths(child, expanded)
@@ -238,28 +468,35 @@ func (ft *FileTree) restoreExpandedPaths(node *FileNode, expanded map[string]boo
 	}
 }
 
-// GetDisplayName возвращает имя для отображения с учетом уровня
-func (node *FileNode) GetDisplayName() string {
-	indent := strings.Repeat("  ", node.Level)
+// Indent возвращает отступ узла по его уровню; значок и имя рисует экран.
+func (node *FileNode) Indent() string {
+	return strings.Repeat("  ", node.Level)
+}
 
-	if node.IsDir {
-		if node.Expanded {
-			return indent + "📂 " + node.Name
-		} else {
-			return indent + "📁 " + node.Name
+// MarkedNodes возвращает отмеченные узлы в порядке обхода дерева, включая
+// узлы внутри свёрнутых каталогов.
+func (ft *FileTree) MarkedNodes() []*FileNode {
+	var marked []*FileNode
+	var walk func(node *FileNode)
+	walk = func(node *FileNode) {
+		if node.Marked {
+			marked = append(marked, node)
+		}
+		for _, child := range node.Children {
+			walk(child)
 		}
 	}
+	if ft.Root != nil {
+		walk(ft.Root)
+	}
+	return marked
+}
 
-	// Иконки для файлов по расширению
-	ext := strings.ToLower(filepath.Ext(node.Name))
-	switch ext {
-	case ".sg":
-		return indent + "📜 " + node.Name
-	case ".md":
-		return indent + "📝 " + node.Name
-	case ".json", ".yaml", ".yml":
-		return indent + "⚙️ " + node.Name
-	default:
-		return indent + "📄 " + node.Name
+// ClearMarks снимает все отметки и возвращает, сколько их было.
+func (ft *FileTree) ClearMarks() int {
+	marked := ft.MarkedNodes()
+	for _, node := range marked {
+		node.Marked = false
 	}
+	return len(marked)
 }
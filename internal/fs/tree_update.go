@@ -0,0 +1,220 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Точечные изменения загруженного дерева: выделение пути с раскрытием
+// каталогов и слияние перечитанных каталогов без полной перезагрузки.
+
+// Reveal раскрывает каталоги на пути к path и выделяет его узел.
+// Непрочитанные предки читаются сразу, как в ToggleExpanded. Возвращает
+// false, если узла нет в списке: путь вне корня, отфильтрован или один из
+// предков ещё читается в фоне.
+func (ft *FileTree) Reveal(path string) bool {
+	if ft.Root == nil {
+		return false
+	}
+	root, err := filepath.Abs(ft.Root.Path)
+	if err != nil {
+		return false
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	node := ft.Root
+	if rel != "." {
+		for _, name := range strings.Split(rel, string(filepath.Separator)) {
+			if !node.IsDir || node.Loading {
+				return false
+			}
+			if !node.loaded {
+				ft.loadChildren(node)
+			}
+			var next *FileNode
+			for _, child := range node.Children {
+				if child.Name == name {
+					next = child
+					break
+				}
+			}
+			if next == nil {
+				return false
+			}
+			node = next
+		}
+	}
+	collapsed := false
+	for dir := node.Parent; dir != nil; dir = dir.Parent {
+		if !dir.Expanded {
+			dir.Expanded = true
+			collapsed = true
+		}
+	}
+	selected := ft.GetSelected()
+	if collapsed {
+		ft.rebuildFlatList()
+	}
+	for i, n := range ft.FlatList {
+		if n == node {
+			ft.Selected = i
+			return true
+		}
+	}
+	// узел скрыт фильтром Visible: курсор остаётся на прежнем узле
+	for i, n := range ft.FlatList {
+		if n == selected {
+			ft.Selected = i
+			break
+		}
+	}
+	ft.SetSelected(ft.Selected)
+	return false
+}
+
+// LoadedDirs возвращает пути прочитанных каталогов — их и нужно наблюдать.
+func (ft *FileTree) LoadedDirs() []string {
+	var dirs []string
+	var walk func(node *FileNode)
+	walk = func(node *FileNode) {
+		if !node.IsDir || !node.loaded {
+			return
+		}
+		dirs = append(dirs, node.Path)
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	if ft.Root != nil {
+		walk(ft.Root)
+	}
+	return dirs
+}
+
+// DirReader возвращает функцию, перечитывающую каталоги paths с фильтрами
+// дерева на момент вызова. Функция не трогает дерево и может работать в
+// горутине; результат передаётся в MergeDirs. Пропавший каталог даёт nil.
+func (ft *FileTree) DirReader(paths []string) func() map[string][]*FileNode {
+	snapshot := *ft
+	return func() map[string][]*FileNode {
+		read := make(map[string][]*FileNode, len(paths))
+		for _, path := range paths {
+			if info, err := os.Stat(path); err != nil || !info.IsDir() {
+				read[path] = nil
+				continue
+			}
+			children := snapshot.readChildren(&FileNode{Path: path})
+			if children == nil {
+				children = []*FileNode{}
+			}
+			read[path] = children
+		}
+		return read
+	}
+}
+
+// MergeDirs подставляет перечитанное содержимое каталогов: новые записи
+// вставляются, пропавшие удаляются, оставшиеся узлы сохраняют раскрытие и
+// детей. Выбор остаётся на том же пути; если выбранный узел исчез —
+// на строке с тем же номером. Возвращает true, если дерево изменилось.
+func (ft *FileTree) MergeDirs(read map[string][]*FileNode) bool {
+	var selectedPath string
+	if node := ft.GetSelected(); node != nil {
+		selectedPath = node.Path
+	}
+
+	changed := false
+	for path, children := range read {
+		node := ft.findLoaded(path)
+		if node == nil || children == nil {
+			continue // каталог не прочитан или исчез: изменение придёт от родителя
+		}
+		if ft.mergeChildren(node, children) {
+			changed = true
+		}
+	}
+	if !changed {
+		return false
+	}
+
+	ft.rebuildFlatList()
+	for i, node := range ft.FlatList {
+		if node.Path == selectedPath && !node.Placeholder {
+			ft.Selected = i
+			return true
+		}
+	}
+	ft.SetSelected(ft.Selected)
+	return true
+}
+
+// findLoaded ищет прочитанный каталог по пути.
+func (ft *FileTree) findLoaded(path string) *FileNode {
+	if ft.Root == nil {
+		return nil
+	}
+	rel, err := filepath.Rel(ft.Root.Path, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	node := ft.Root
+	if rel != "." {
+		for _, name := range strings.Split(rel, string(filepath.Separator)) {
+			var next *FileNode
+			for _, child := range node.Children {
+				if child.Name == name {
+					next = child
+					break
+				}
+			}
+			if next == nil {
+				return nil
+			}
+			node = next
+		}
+	}
+	if !node.IsDir || !node.loaded {
+		return nil
+	}
+	return node
+}
+
+// mergeChildren сливает fresh (уже отсортированных) детей с текущими.
+func (ft *FileTree) mergeChildren(node *FileNode, fresh []*FileNode) bool {
+	existing := make(map[string]*FileNode, len(node.Children))
+	for _, child := range node.Children {
+		existing[child.Name] = child
+	}
+
+	changed := len(fresh) != len(node.Children)
+	merged := make([]*FileNode, 0, len(fresh))
+	for i, child := range fresh {
+		if old, ok := existing[child.Name]; ok && old.IsDir == child.IsDir {
+			old.Size = child.Size
+			merged = append(merged, old)
+			if !changed && node.Children[i] != old {
+				changed = true
+			}
+			continue
+		}
+		child.Parent = node
+		setLevel(child, node.Level+1)
+		merged = append(merged, child)
+		changed = true
+	}
+	node.Children = merged
+	return changed
+}
+
+func setLevel(node *FileNode, level int) {
+	node.Level = level
+	for _, child := range node.Children {
+		setLevel(child, level+1)
+	}
+}
@@ -0,0 +1,192 @@
+package fs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// TreeWatcher следит за прочитанными каталогами дерева (не рекурсивно) и
+// сообщает, какие из них изменились. Если fsnotify недоступен, списки
+// каталогов сравниваются по таймеру.
+type TreeWatcher struct {
+	root    string
+	fw      *FileWatcher
+	polling bool
+
+	mu      sync.Mutex
+	watched map[string]bool
+	changed map[string]bool
+
+	events chan struct{} // ёмкость 1: пачка событий схлопывается в одно
+	done   chan struct{}
+	once   sync.Once
+}
+
+// WatchTree начинает наблюдение за деревом с корнем root. Набор каталогов
+// задаётся через Sync; pollInterval используется только в режиме опроса.
+func WatchTree(root string, pollInterval time.Duration) *TreeWatcher {
+	tw := &TreeWatcher{
+		root:    filepath.Clean(root),
+		watched: make(map[string]bool),
+		changed: make(map[string]bool),
+		events:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	fw, err := NewFileWatcher(context.Background())
+	if err != nil {
+		tw.polling = true
+		go tw.pollLoop(max(pollInterval, minPollInterval))
+		return tw
+	}
+	tw.fw = fw
+	fw.AddCallback(tw.root, tw.handleEvent)
+	return tw
+}
+
+// Polling сообщает, что fsnotify недоступен и изменения ищутся опросом.
+func (tw *TreeWatcher) Polling() bool {
+	return tw.polling
+}
+
+// Sync приводит набор наблюдаемых каталогов к dirs.
+func (tw *TreeWatcher) Sync(dirs []string) {
+	next := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		next[filepath.Clean(dir)] = true
+	}
+
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	for dir := range tw.watched {
+		if !next[dir] {
+			if tw.fw != nil {
+				_ = tw.fw.Unwatch(dir)
+			}
+			delete(tw.watched, dir)
+		}
+	}
+	for dir := range next {
+		if tw.watched[dir] {
+			continue
+		}
+		// каталог мог исчезнуть между чтением и Sync — тогда его изменит родитель
+		if tw.fw != nil && tw.fw.Watch(dir) != nil {
+			continue
+		}
+		tw.watched[dir] = true
+	}
+}
+
+// Next ждёт изменения и затем тишины длиной quiet и возвращает изменившиеся
+// каталоги. После Close возвращает false.
+func (tw *TreeWatcher) Next(quiet time.Duration) ([]string, bool) {
+	select {
+	case <-tw.done:
+		return nil, false
+	case <-tw.events:
+	}
+	timer := time.NewTimer(quiet)
+	defer timer.Stop()
+	for {
+		select {
+		case <-tw.done:
+			return nil, false
+		case <-tw.events:
+			timer.Reset(quiet)
+		case <-timer.C:
+			tw.mu.Lock()
+			dirs := make([]string, 0, len(tw.changed))
+			for dir := range tw.changed {
+				dirs = append(dirs, dir)
+			}
+			clear(tw.changed)
+			tw.mu.Unlock()
+			slices.Sort(dirs)
+			return dirs, true
+		}
+	}
+}
+
+// Close останавливает наблюдение; повторные вызовы безопасны.
+func (tw *TreeWatcher) Close() {
+	tw.once.Do(func() {
+		close(tw.done)
+		if tw.fw != nil {
+			_ = tw.fw.Close()
+		}
+	})
+}
+
+func (tw *TreeWatcher) handleEvent(event FileChangeEvent) {
+	if event.Operation == FileModified {
+		return // содержимое файла дерево не показывает
+	}
+	tw.markChanged(filepath.Dir(event.Path))
+}
+
+func (tw *TreeWatcher) markChanged(dir string) {
+	tw.mu.Lock()
+	if !tw.watched[dir] {
+		tw.mu.Unlock()
+		return
+	}
+	tw.changed[dir] = true
+	tw.mu.Unlock()
+	select {
+	case tw.events <- struct{}{}:
+	default:
+	}
+}
+
+func (tw *TreeWatcher) pollLoop(interval time.Duration) {
+	prev := make(map[string][]string)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tw.done:
+			return
+		case <-ticker.C:
+			tw.mu.Lock()
+			dirs := make([]string, 0, len(tw.watched))
+			for dir := range tw.watched {
+				dirs = append(dirs, dir)
+			}
+			tw.mu.Unlock()
+
+			next := make(map[string][]string, len(dirs))
+			for _, dir := range dirs {
+				names := listNames(dir)
+				if old, ok := prev[dir]; ok && !slices.Equal(old, names) {
+					tw.markChanged(dir)
+					if names == nil {
+						tw.markChanged(filepath.Dir(dir)) // каталог удалён
+					}
+				}
+				next[dir] = names
+			}
+			prev = next
+		}
+	}
+}
+
+// listNames возвращает отсортированные имена записей каталога с признаком каталога.
+func listNames(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += string(filepath.Separator)
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -2,6 +2,7 @@ package fs
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"sync"
 
@@ -172,10 +173,9 @@ func (fw *FileWatcher) pathMatches(filePath, watchPath string) bool {
 
 // isDirectory проверяет, является ли путь директорией
 func (fw *FileWatcher) isDirectory(path string) bool {
-	// Эта функция может быть не точной для удаленных файлов
-	// В идеале нужно кешировать информацию о типах файлов
-	// TODO: улучшить определение типа файла
-	return false
+	// Для удалённых путей Stat не сработает, они считаются файлами
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
 
 // String возвращает строковое представление операции
@@ -0,0 +1,276 @@
+// Package logging — журнал приложения: записи уровня не ниже заданного
+// пишутся в файл с ротацией по размеру и хранятся в кольцевом буфере
+// последних записей для экрана логов.
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level — важность записи.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// ParseLevel разбирает уровень из конфига (debug, info, warn, error);
+// неизвестное значение даёт info.
+func ParseLevel(s string) Level {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug
+	case "warn", "warning":
+		return LevelWarn
+	case "error":
+		return LevelError
+	}
+	return LevelInfo
+}
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return "INFO"
+}
+
+// Entry — одна запись журнала.
+type Entry struct {
+	ID      uint64 // растёт от записи к записи
+	Time    time.Time
+	Level   Level
+	Message string
+	Notice  bool // показана пользователю уведомлением
+}
+
+// String форматирует запись так же, как она пишется в файл.
+func (e Entry) String() string {
+	return fmt.Sprintf("%s %-5s %s", e.Time.Format("2006-01-02 15:04:05.000"), e.Level, e.Message)
+}
+
+// Options — настройки журнала (секции logging и performance конфига).
+type Options struct {
+	Level      Level
+	FilePath   string // пусто — только буфер в памяти
+	MaxSize    int64  // размер файла, после которого он сдвигается в .1; 0 — без ротации
+	MaxEntries int    // записей в буфере
+}
+
+// Logger — журнал. Безопасен для одновременного использования из
+// нескольких горутин (команды Bubble Tea, запуски surge).
+type Logger struct {
+	mu   sync.Mutex
+	opts Options
+
+	file    *os.File
+	size    int64
+	fileErr error // последняя ошибка открытия или записи файла
+
+	entries []Entry // кольцо: самая старая запись — entries[start]
+	start   int
+	seq     uint64 // растёт с каждой записью и очисткой
+}
+
+const defaultMaxEntries = 1000
+
+// New создаёт журнал; файл открывается при первой записи.
+func New(opts Options) *Logger {
+	l := &Logger{}
+	l.Configure(opts)
+	return l
+}
+
+// Configure применяет новые настройки. Записи в буфере сохраняются (старые
+// отбрасываются, если буфер стал меньше); файл переоткрывается, если сменился путь.
+func (l *Logger) Configure(opts Options) {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultMaxEntries
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if opts.FilePath != l.opts.FilePath {
+		l.closeFile()
+		l.fileErr = nil
+	}
+	entries := l.snapshot()
+	if extra := len(entries) - opts.MaxEntries; extra > 0 {
+		entries = entries[extra:]
+	}
+	l.opts = opts
+	l.entries, l.start = entries, 0
+	l.seq++
+}
+
+// Logf добавляет запись, если её уровень не ниже заданного.
+func (l *Logger) Logf(level Level, format string, args ...any) {
+	l.add(Entry{Level: level, Message: fmt.Sprintf(format, args...)})
+}
+
+// Notice записывает показанное пользователю уведомление. Уведомления
+// попадают в журнал при любом заданном уровне: это их история.
+func (l *Logger) Notice(level Level, text string) {
+	l.add(Entry{Level: level, Message: text, Notice: true})
+}
+
+func (l *Logger) add(entry Entry) {
+	if l == nil {
+		return
+	}
+	entry.Time = time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if entry.Level < l.opts.Level && !entry.Notice {
+		return
+	}
+	l.seq++
+	entry.ID = l.seq
+	if len(l.entries) < l.opts.MaxEntries {
+		l.entries = append(l.entries, entry)
+	} else {
+		l.entries[l.start] = entry
+		l.start = (l.start + 1) % len(l.entries)
+	}
+	l.writeFile(entry)
+}
+
+func (l *Logger) Debugf(format string, args ...any) { l.Logf(LevelDebug, format, args...) }
+func (l *Logger) Infof(format string, args ...any)  { l.Logf(LevelInfo, format, args...) }
+func (l *Logger) Warnf(format string, args ...any)  { l.Logf(LevelWarn, format, args...) }
+func (l *Logger) Errorf(format string, args ...any) { l.Logf(LevelError, format, args...) }
+
+// Entries возвращает копию буфера от старых записей к новым.
+func (l *Logger) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.snapshot()
+}
+
+// Seq меняется при каждой записи и очистке: по нему экран узнаёт, что
+// буфер пора перечитать.
+func (l *Logger) Seq() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.seq
+}
+
+// Clear очищает буфер; файл журнала не трогается.
+func (l *Logger) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries, l.start = nil, 0
+	l.seq++
+}
+
+// FilePath возвращает путь файла журнала и последнюю ошибку работы с ним.
+func (l *Logger) FilePath() (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.opts.FilePath, l.fileErr
+}
+
+// Close закрывает файл журнала.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closeFile()
+}
+
+func (l *Logger) snapshot() []Entry {
+	out := make([]Entry, 0, len(l.entries))
+	out = append(out, l.entries[l.start:]...)
+	return append(out, l.entries[:l.start]...)
+}
+
+// writeFile дописывает запись в файл, сдвигая его в .1 при превышении
+// размера. Ошибка файла не теряет запись: она остаётся в буфере.
+func (l *Logger) writeFile(entry Entry) {
+	if l.opts.FilePath == "" {
+		return
+	}
+	line := entry.String() + "\n"
+	if l.file != nil && l.opts.MaxSize > 0 && l.size+int64(len(line)) > l.opts.MaxSize {
+		l.rotate()
+	}
+	if l.file == nil && !l.openFile() {
+		return
+	}
+	n, err := l.file.WriteString(line)
+	l.size += int64(n)
+	if err != nil {
+		l.fileErr = err
+		l.closeFile()
+	}
+}
+
+func (l *Logger) openFile() bool {
+	if l.fileErr != nil {
+		return false // не пытаемся открывать на каждой записи
+	}
+	path := l.opts.FilePath
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		l.fileErr = err
+		return false
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		l.fileErr = err
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		l.fileErr = err
+		return false
+	}
+	l.file, l.size = file, info.Size()
+	if l.opts.MaxSize > 0 && l.size >= l.opts.MaxSize {
+		l.rotate()
+		return l.file != nil || l.openFile()
+	}
+	return true
+}
+
+// rotate сдвигает текущий файл в path.1 (прежний .1 удаляется); новый
+// файл откроется при следующей записи.
+func (l *Logger) rotate() {
+	l.closeFile()
+	path := l.opts.FilePath
+	if err := os.Rename(path, path+".1"); err != nil && !os.IsNotExist(err) {
+		l.fileErr = err
+	}
+}
+
+func (l *Logger) closeFile() error {
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file, l.size = nil, 0
+	return err
+}
+
+// std — журнал приложения; до настройки из конфига пишет только в память.
+var std = New(Options{Level: LevelInfo})
+
+// Default возвращает журнал приложения.
+func Default() *Logger { return std }
+
+func Debugf(format string, args ...any) { std.Logf(LevelDebug, format, args...) }
+func Infof(format string, args ...any)  { std.Logf(LevelInfo, format, args...) }
+func Warnf(format string, args ...any)  { std.Logf(LevelWarn, format, args...) }
+func Errorf(format string, args ...any) { std.Logf(LevelError, format, args...) }
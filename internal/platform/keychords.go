@@ -0,0 +1,272 @@
+package platform
+
+import (
+	"os"
+	"strings"
+)
+
+// Terminal describes the terminal surge-tui runs in, as far as the environment tells.
+type Terminal struct {
+	Term      string // $TERM
+	Program   string // $TERM_PROGRAM
+	ColorTerm string // $COLORTERM
+	Tmux      bool   // inside tmux ($TMUX is set)
+}
+
+// DetectTerminal reads TERM, TERM_PROGRAM, COLORTERM and TMUX.
+func DetectTerminal() Terminal {
+	return Terminal{
+		Term:      os.Getenv("TERM"),
+		Program:   os.Getenv("TERM_PROGRAM"),
+		ColorTerm: os.Getenv("COLORTERM"),
+		Tmux:      os.Getenv("TMUX") != "",
+	}
+}
+
+// trueColorPrograms are TERM_PROGRAM values of terminals known to render
+// 24-bit color even when COLORTERM is not exported (e.g. over ssh).
+var trueColorPrograms = map[string]bool{
+	"iTerm.app":    true,
+	"WezTerm":      true,
+	"vscode":       true,
+	"ghostty":      true,
+	"Hyper":        true,
+	"WarpTerminal": true,
+	"rio":          true,
+}
+
+// TrueColor reports whether the terminal advertises 24-bit color. Themes
+// use #RRGGBB colors; without truecolor they are rounded to the 256-color
+// palette and may look off.
+func (t Terminal) TrueColor() bool {
+	switch strings.ToLower(t.ColorTerm) {
+	case "truecolor", "24bit":
+		return true
+	}
+	return strings.HasSuffix(t.Term, "-direct") || trueColorPrograms[t.Program]
+}
+
+// Name returns a short terminal name for messages.
+func (t Terminal) Name() string {
+	switch {
+	case t.Tmux:
+		return "tmux"
+	case t.Program != "":
+		return t.Program
+	case t.Term != "":
+		return t.Term
+	default:
+		return "this terminal"
+	}
+}
+
+func (t Terminal) isTmux() bool {
+	return t.Tmux || t.Program == "tmux" || strings.HasPrefix(t.Term, "tmux")
+}
+
+func (t Terminal) isScreen() bool {
+	return !t.isTmux() && strings.HasPrefix(t.Term, "screen")
+}
+
+// optionNeedsSetup: macOS terminals send Option as Meta only when configured to.
+func (t Terminal) optionNeedsSetup() bool {
+	return t.Program == "Apple_Terminal" || t.Program == "iTerm.app"
+}
+
+// ChordWarning explains why a key chord will most likely never reach the application.
+type ChordWarning struct {
+	Key        string // canonical chord
+	Reason     string
+	Suggestion string // replacement chord, may be empty
+}
+
+type chord struct {
+	ctrl, alt, shift, super bool
+	main                    string
+}
+
+func parseChord(key string) chord {
+	mods, main := splitKey(CanonicalKeyForLookup(key))
+	var c chord
+	for _, mod := range mods {
+		switch mod {
+		case "ctrl":
+			c.ctrl = true
+		case "alt":
+			c.alt = true
+		case "shift":
+			c.shift = true
+		case "super":
+			c.super = true
+		}
+	}
+	c.main = strings.Join(main, "+")
+	return c
+}
+
+func (c chord) letter() bool {
+	return len(c.main) == 1 && c.main[0] >= 'a' && c.main[0] <= 'z'
+}
+
+func (c chord) navigation() bool {
+	switch c.main {
+	case "up", "down", "left", "right", "home", "end", "pgup", "pgdown", "insert", "delete":
+		return true
+	}
+	return false
+}
+
+// ctrlSymbols lists keys that have no control code when combined with Ctrl.
+// Ctrl+/ is absent: terminals send it as Ctrl+_ (0x1F), which lookup folds
+// back into ctrl+/.
+var ctrlSymbols = map[string]bool{
+	",": true, "comma": true, ".": true, "period": true, ";": true, "'": true,
+	"=": true, "-": true, "`": true, "0": true, "1": true, "2": true,
+	"3": true, "4": true, "5": true, "6": true, "7": true, "8": true, "9": true,
+}
+
+// ctrlAliases maps Ctrl chords to the key that sends the very same byte.
+var ctrlAliases = map[string]string{
+	"i": "Tab",
+	"m": "Enter",
+	"[": "Esc",
+}
+
+type chordRule struct {
+	when    func(Terminal) bool // nil applies to every terminal
+	match   func(chord) bool
+	reason  func(chord, Terminal) string
+	suggest func(chord) string
+}
+
+// chordRules is the knowledge table of unreliable chords. Bubble Tea decodes legacy
+// escape sequences only, so extended keyboard protocols don't help here.
+var chordRules = []chordRule{
+	{
+		match: func(c chord) bool { return c.ctrl && c.shift && c.letter() },
+		reason: func(c chord, _ Terminal) string {
+			return "terminals send Ctrl+Shift+" + strings.ToUpper(c.main) + " as Ctrl+" + strings.ToUpper(c.main)
+		},
+		suggest: func(c chord) string { return "alt+" + c.main },
+	},
+	{
+		match: func(c chord) bool { return c.alt && c.shift && c.letter() && !c.ctrl },
+		reason: func(c chord, _ Terminal) string {
+			return "arrives as Alt+" + strings.ToUpper(c.main) + " and cannot be told apart from Alt+" + c.main
+		},
+		suggest: func(c chord) string { return "alt+" + c.main },
+	},
+	{
+		match: func(c chord) bool { return c.ctrl && !c.alt && ctrlSymbols[c.main] },
+		reason: func(c chord, _ Terminal) string {
+			key := DisplayKey(c.main)
+			return "terminals have no control code for Ctrl+" + key + "; it arrives as plain " + key + " or not at all"
+		},
+		suggest: func(c chord) string { return "alt+" + c.main },
+	},
+	{
+		match: func(c chord) bool { return c.ctrl && !c.alt && !c.shift && ctrlAliases[c.main] != "" },
+		reason: func(c chord, _ Terminal) string {
+			return "Ctrl+" + strings.ToUpper(c.main) + " is the same byte as " + ctrlAliases[c.main]
+		},
+		suggest: func(c chord) string { return "alt+" + c.main },
+	},
+	{
+		match: func(c chord) bool {
+			return (c.main == "tab" && c.ctrl) || (c.main == "enter" && (c.ctrl || c.shift))
+		},
+		reason: func(c chord, _ Terminal) string {
+			return "modified " + DisplayKey(c.main) + " arrives as plain " + DisplayKey(c.main)
+		},
+		suggest: func(c chord) string { return "alt+" + c.main },
+	},
+	{
+		match: func(c chord) bool { return c.super },
+		reason: func(chord, Terminal) string {
+			return "terminals do not forward the Super/Windows key"
+		},
+	},
+	{
+		when:  Terminal.optionNeedsSetup,
+		match: func(c chord) bool { return c.alt },
+		reason: func(_ chord, t Terminal) string {
+			return t.Program + " sends Option as Meta only when \"Use Option as Meta key\" (Esc+ in iTerm2) is enabled"
+		},
+	},
+	{
+		when:  func(t Terminal) bool { return t.Term == "linux" },
+		match: func(c chord) bool { return (c.ctrl || c.alt || c.shift) && c.navigation() },
+		reason: func(chord, Terminal) string {
+			return "the Linux console does not report modifiers on navigation keys"
+		},
+	},
+	{
+		when:  Terminal.isTmux,
+		match: func(c chord) bool { return (c.ctrl || c.shift) && c.navigation() },
+		reason: func(chord, Terminal) string {
+			return "tmux passes modified navigation keys only with `set -g xterm-keys on`"
+		},
+	},
+	{
+		when:  Terminal.isTmux,
+		match: func(c chord) bool { return c.ctrl && !c.alt && !c.shift && c.main == "b" },
+		reason: func(chord, Terminal) string {
+			return "Ctrl+B is the default tmux prefix key"
+		},
+		suggest: func(chord) string { return "f5" },
+	},
+	{
+		when:  Terminal.isScreen,
+		match: func(c chord) bool { return c.ctrl && !c.alt && !c.shift && c.main == "a" },
+		reason: func(chord, Terminal) string {
+			return "Ctrl+A is the GNU screen command key"
+		},
+	},
+	{
+		when: func(t Terminal) bool { return t.Program == "vscode" },
+		match: func(c chord) bool {
+			return c.ctrl && !c.alt && !c.shift && (c.main == "p" || c.main == "k")
+		},
+		reason: func(c chord, _ Terminal) string {
+			return "the VS Code terminal keeps Ctrl+" + strings.ToUpper(c.main) + " for the editor by default"
+		},
+	},
+}
+
+// CheckChord reports whether key is known to be unreliable in the given terminal.
+// Only the first matching rule is reported.
+func CheckChord(key string, term Terminal) (ChordWarning, bool) {
+	if strings.TrimSpace(key) == "" {
+		return ChordWarning{}, false
+	}
+	c := parseChord(key)
+	for _, rule := range chordRules {
+		if rule.when != nil && !rule.when(term) {
+			continue
+		}
+		if !rule.match(c) {
+			continue
+		}
+		warning := ChordWarning{Key: CanonicalKeyForLookup(key), Reason: rule.reason(c, term)}
+		if rule.suggest != nil {
+			if alt := rule.suggest(c); alt != "" {
+				if !matchesAnyRule(alt, term) {
+					warning.Suggestion = alt
+				}
+			}
+		}
+		return warning, true
+	}
+	return ChordWarning{}, false
+}
+
+// matchesAnyRule keeps suggestions from pointing at another unreliable chord.
+func matchesAnyRule(key string, term Terminal) bool {
+	c := parseChord(key)
+	for _, rule := range chordRules {
+		if (rule.when == nil || rule.when(term)) && rule.match(c) {
+			return true
+		}
+	}
+	return false
+}
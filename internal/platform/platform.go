@@ -1,6 +1,7 @@
 package platform
 
 import (
+	"fmt"
 	"runtime"
 	"sort"
 	"strings"
@@ -53,8 +54,31 @@ var modifierOrder = map[string]int{
 
 // CanonicalKeyForLookup normalizes key descriptions so different aliases resolve consistently.
 // It sorts modifiers in a stable order and maps cmd→ctrl so that mac bindings work on terminals
-// which don't forward the command key.
+// which don't forward the command key. A lone letter keeps its case: "N" and "shift+n" both
+// resolve to "N", distinct from "n".
 func CanonicalKeyForLookup(key string) string {
+	canonical := canonicalizeKey(key, nil)
+	return canonical
+}
+
+// CanonicalKeyTrace works like CanonicalKeyForLookup but also reports every mapping
+// decision taken along the way (alias rewrites, dropped duplicates, reordering).
+// It is meant for debugging keybindings that don't resolve as expected.
+func CanonicalKeyTrace(key string) (string, []string) {
+	var steps []string
+	canonical := canonicalizeKey(key, func(step string) {
+		steps = append(steps, step)
+	})
+	if len(steps) == 0 && canonical != key {
+		steps = append(steps, fmt.Sprintf("normalized %q → %q", key, canonical))
+	}
+	return canonical, steps
+}
+
+func canonicalizeKey(key string, note func(string)) string {
+	if note == nil {
+		note = func(string) {}
+	}
 	key = strings.TrimSpace(key)
 	if key == "" {
 		return ""
@@ -78,9 +102,17 @@ func CanonicalKeyForLookup(key string) string {
 		default:
 			mods[i] = mod
 		}
+		if mods[i] != mod {
+			note(fmt.Sprintf("modifier %s → %s", mod, mods[i]))
+		}
 	}
 
+	before := len(mods)
 	mods = uniqueStrings(mods)
+	if len(mods) != before {
+		note("dropped duplicate modifiers")
+	}
+	order := strings.Join(mods, "+")
 	sort.SliceStable(mods, func(i, j int) bool {
 		ai := modifierOrderValue(mods[i])
 		aj := modifierOrderValue(mods[j])
@@ -89,21 +121,52 @@ func CanonicalKeyForLookup(key string) string {
 		}
 		return ai < aj
 	})
+	if sorted := strings.Join(mods, "+"); sorted != order {
+		note(fmt.Sprintf("reordered modifiers %s → %s", order, sorted))
+	}
 
 	if len(main) == 0 {
 		return strings.Join(mods, "+")
 	}
 
-	main = normalizeMainParts(main)
-	return strings.Join(append(mods, strings.Join(main, "+")), "+")
+	// Ctrl+/ and Ctrl+_ are the same byte (0x1F); Bubble Tea reports it as
+	// ctrl+_, while bindings are written as ctrl+/.
+	if len(mods) == 1 && mods[0] == "ctrl" && len(main) == 1 && main[0] == "_" {
+		note("ctrl+_ → ctrl+/ (same byte)")
+		main = []string{"/"}
+	}
+
+	// Terminals send Shift+N as "N", so a lone letter keeps its case and
+	// shift+n folds into "N"; other chords with letters stay lower-case.
+	if letter, ok := singleLetter(main); ok {
+		switch {
+		case len(mods) == 0:
+			return singleLetterCase(key, letter)
+		case len(mods) == 1 && mods[0] == "shift":
+			note(fmt.Sprintf("shift+%s → %s", letter, strings.ToUpper(letter)))
+			return strings.ToUpper(letter)
+		}
+	}
+
+	normalized := normalizeMainParts(main)
+	for i := range main {
+		if normalized[i] != main[i] {
+			note(fmt.Sprintf("key %s → %s", main[i], normalized[i]))
+		}
+	}
+	return strings.Join(append(mods, strings.Join(normalized, "+")), "+")
 }
 
 // DisplayKey formats a key binding for UI hints with platform-friendly modifier names.
+// A lone letter is shown as written, since "n" and "N" are different keys.
 func DisplayKey(key string) string {
 	key = strings.TrimSpace(key)
 	if key == "" {
 		return ""
 	}
+	if r := []rune(key); len(r) == 1 && unicode.IsLetter(r[0]) {
+		return key
+	}
 	parts := strings.Split(key, "+")
 	display := make([]string, 0, len(parts))
 	for _, part := range parts {
@@ -183,6 +246,25 @@ func splitKey(key string) (mods []string, main []string) {
 	return mods, main
 }
 
+func singleLetter(main []string) (string, bool) {
+	if len(main) != 1 {
+		return "", false
+	}
+	r := []rune(main[0])
+	if len(r) != 1 || !unicode.IsLetter(r[0]) {
+		return "", false
+	}
+	return main[0], true
+}
+
+// singleLetterCase returns letter in the case it was written in key.
+func singleLetterCase(key, letter string) string {
+	if trimmed := strings.TrimSpace(key); strings.EqualFold(trimmed, letter) {
+		return trimmed
+	}
+	return letter
+}
+
 func normalizeMainParts(parts []string) []string {
 	if len(parts) == 0 {
 		return parts
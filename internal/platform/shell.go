@@ -0,0 +1,26 @@
+package platform
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ShellCommand runs a command line through the system shell: sh -c on Unix,
+// cmd /C on Windows.
+func ShellCommand(ctx context.Context, line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", line)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", line)
+}
+
+// ShellQuote quotes a single argument so the system shell passes it through
+// unchanged, spaces and quotes included.
+func ShellQuote(arg string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(arg, `"`, `""`) + `"`
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
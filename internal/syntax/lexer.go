@@ -0,0 +1,244 @@
+package syntax
+
+import (
+	"unicode"
+)
+
+// TokenKind classifies a highlighted span of a line.
+type TokenKind int
+
+const (
+	TokenText TokenKind = iota
+	TokenKeyword
+	TokenType
+	TokenLiteral
+	TokenString
+	TokenNumber
+	TokenComment
+	TokenAttribute
+	TokenOperator
+	TokenPunctuation
+)
+
+// State is the lexer state carried from the end of one line to the next.
+// Only constructs that may span several lines need a dedicated state.
+type State int
+
+const (
+	StateNormal State = iota
+	StateBlockComment
+)
+
+// Token is a highlighted span of a line. Start and End are rune offsets.
+type Token struct {
+	Kind  TokenKind
+	Start int
+	End   int
+}
+
+// Line holds tokens of one source line and the lexer state after it.
+type Line struct {
+	Tokens   []Token
+	EndState State
+}
+
+var keywords = map[string]bool{
+	"fn": true, "let": true, "mut": true, "const": true, "pub": true,
+	"if": true, "else": true, "while": true, "for": true, "in": true,
+	"return": true, "break": true, "continue": true, "import": true,
+	"extern": true, "type": true, "tag": true, "contract": true,
+	"compare": true, "finally": true, "signal": true, "spawn": true,
+	"async": true, "await": true, "parallel": true, "macro": true,
+	"pragma": true, "is": true, "as": true, "own": true, "struct": true,
+	"enum": true, "match": true, "use": true,
+}
+
+var builtinTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float": true, "float16": true, "float32": true, "float64": true,
+	"bool": true, "string": true, "char": true, "byte": true,
+}
+
+var literals = map[string]bool{
+	"true": true, "false": true, "nothing": true, "self": true,
+}
+
+// HighlightDocument tokenizes all lines, threading lexer state between them
+// so multi-line constructs such as block comments are classified correctly.
+func HighlightDocument(lines []string) []Line {
+	out := make([]Line, len(lines))
+	state := StateNormal
+	for i, line := range lines {
+		out[i] = HighlightLine(line, state)
+		state = out[i].EndState
+	}
+	return out
+}
+
+// HighlightLine tokenizes a single line starting in the given state.
+func HighlightLine(line string, state State) Line {
+	runes := []rune(line)
+	var tokens []Token
+	emit := func(kind TokenKind, start, end int) {
+		if end <= start {
+			return
+		}
+		if n := len(tokens); n > 0 && tokens[n-1].Kind == kind && tokens[n-1].End == start {
+			tokens[n-1].End = end
+			return
+		}
+		tokens = append(tokens, Token{Kind: kind, Start: start, End: end})
+	}
+
+	i := 0
+	if state == StateBlockComment {
+		end, closed := scanBlockComment(runes, 0)
+		emit(TokenComment, 0, end)
+		i = end
+		if !closed {
+			return Line{Tokens: tokens, EndState: StateBlockComment}
+		}
+	}
+
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			emit(TokenComment, i, len(runes))
+			return Line{Tokens: tokens, EndState: StateNormal}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end, closed := scanBlockComment(runes, i+2)
+			emit(TokenComment, i, end)
+			i = end
+			if !closed {
+				return Line{Tokens: tokens, EndState: StateBlockComment}
+			}
+		case r == '"' || r == '\'':
+			end := scanQuoted(runes, i)
+			emit(TokenString, i, end)
+			i = end
+		case unicode.IsDigit(r):
+			end := scanNumber(runes, i)
+			emit(TokenNumber, i, end)
+			i = end
+		case r == '@' && i+1 < len(runes) && isIdentStart(runes[i+1]):
+			end := scanIdent(runes, i+1)
+			emit(TokenAttribute, i, end)
+			i = end
+		case isIdentStart(r):
+			end := scanIdent(runes, i)
+			emit(classifyWord(string(runes[i:end])), i, end)
+			i = end
+		case unicode.IsSpace(r):
+			start := i
+			for i < len(runes) && unicode.IsSpace(runes[i]) {
+				i++
+			}
+			emit(TokenText, start, i)
+		case isPunctuation(r):
+			emit(TokenPunctuation, i, i+1)
+			i++
+		case isOperator(r):
+			start := i
+			for i < len(runes) && isOperator(runes[i]) {
+				i++
+			}
+			emit(TokenOperator, start, i)
+		default:
+			emit(TokenText, i, i+1)
+			i++
+		}
+	}
+
+	return Line{Tokens: tokens, EndState: StateNormal}
+}
+
+func classifyWord(word string) TokenKind {
+	switch {
+	case keywords[word]:
+		return TokenKeyword
+	case literals[word]:
+		return TokenLiteral
+	case builtinTypes[word]:
+		return TokenType
+	default:
+		return TokenText
+	}
+}
+
+// scanBlockComment returns the offset right after the closing "*/" or the
+// end of the line when the comment continues on the next line.
+func scanBlockComment(runes []rune, from int) (int, bool) {
+	for i := from; i+1 < len(runes); i++ {
+		if runes[i] == '*' && runes[i+1] == '/' {
+			return i + 2, true
+		}
+	}
+	return len(runes), false
+}
+
+func scanQuoted(runes []rune, start int) int {
+	quote := runes[start]
+	i := start + 1
+	for i < len(runes) {
+		switch runes[i] {
+		case '\\':
+			i += 2
+			continue
+		case quote:
+			return i + 1
+		}
+		i++
+	}
+	return len(runes)
+}
+
+func scanNumber(runes []rune, start int) int {
+	i := start
+	for i < len(runes) {
+		r := runes[i]
+		if unicode.IsDigit(r) || unicode.IsLetter(r) || r == '_' {
+			i++
+			continue
+		}
+		if r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]) {
+			i++
+			continue
+		}
+		break
+	}
+	return i
+}
+
+func scanIdent(runes []rune, start int) int {
+	i := start
+	for i < len(runes) && isIdentPart(runes[i]) {
+		i++
+	}
+	return i
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isPunctuation(r rune) bool {
+	switch r {
+	case '(', ')', '[', ']', '{', '}', ',', ';', '.':
+		return true
+	}
+	return false
+}
+
+func isOperator(r rune) bool {
+	switch r {
+	case '+', '-', '*', '/', '%', '=', '!', '<', '>', '&', '|', '^', '~', '?', ':':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package syntax
+
+// SymbolKind classifies a declaration found by Outline.
+type SymbolKind int
+
+const (
+	SymbolFunction SymbolKind = iota
+	SymbolType
+)
+
+func (k SymbolKind) String() string {
+	if k == SymbolFunction {
+		return "fn"
+	}
+	return "type"
+}
+
+// Symbol is a named declaration. Line and Col are 0-based, Col is the rune
+// offset of the declaring keyword.
+type Symbol struct {
+	Kind SymbolKind
+	Name string
+	Line int
+	Col  int
+}
+
+var declKeywords = map[string]SymbolKind{
+	"fn":       SymbolFunction,
+	"type":     SymbolType,
+	"struct":   SymbolType,
+	"enum":     SymbolType,
+	"tag":      SymbolType,
+	"contract": SymbolType,
+}
+
+// Outline lists function and type declarations in source order. A
+// declaration is a declaring keyword followed by a name on the same line;
+// keywords inside comments and strings are skipped by the lexer, and
+// anonymous forms such as fn(int) -> int are not declarations.
+func Outline(lines []string) []Symbol {
+	var symbols []Symbol
+	for i, hl := range HighlightDocument(lines) {
+		runes := []rune(lines[i])
+		tokens := hl.Tokens
+		for j, tok := range tokens {
+			if tok.Kind != TokenKeyword {
+				continue
+			}
+			kind, ok := declKeywords[string(runes[tok.Start:tok.End])]
+			if !ok {
+				continue
+			}
+			if name, ok := declName(runes, tokens[j+1:]); ok {
+				symbols = append(symbols, Symbol{Kind: kind, Name: name, Line: i, Col: tok.Start})
+			}
+		}
+	}
+	return symbols
+}
+
+// declName returns the identifier that follows a declaring keyword. The
+// lexer merges adjacent plain text, so blanks and the name share a token.
+func declName(runes []rune, rest []Token) (string, bool) {
+	for _, tok := range rest {
+		if tok.Kind != TokenText {
+			return "", false
+		}
+		start := tok.Start
+		for start < tok.End && (runes[start] == ' ' || runes[start] == '\t') {
+			start++
+		}
+		if start == tok.End {
+			continue
+		}
+		if !isIdentStart(runes[start]) {
+			return "", false
+		}
+		end := min(scanIdent(runes, start), tok.End)
+		return string(runes[start:end]), true
+	}
+	return "", false
+}
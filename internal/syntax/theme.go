@@ -0,0 +1,144 @@
+package syntax
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HighlightTheme maps token kinds to lipgloss styles.
+type HighlightTheme struct {
+	styles map[TokenKind]lipgloss.Style
+}
+
+// HighlightPalette lists colors for each token kind.
+type HighlightPalette struct {
+	Text        string
+	Keyword     string
+	Type        string
+	Literal     string
+	String      string
+	Number      string
+	Comment     string
+	Attribute   string
+	Operator    string
+	Punctuation string
+}
+
+var (
+	DarkPalette = HighlightPalette{
+		Text:        "#E2E8F0",
+		Keyword:     "#C084FC",
+		Type:        "#38BDF8",
+		Literal:     "#F472B6",
+		String:      "#86EFAC",
+		Number:      "#FDBA74",
+		Comment:     "#64748B",
+		Attribute:   "#FACC15",
+		Operator:    "#94A3B8",
+		Punctuation: "#CBD5E1",
+	}
+
+	LightPalette = HighlightPalette{
+		Text:        "#0F172A",
+		Keyword:     "#7C3AED",
+		Type:        "#0369A1",
+		Literal:     "#BE185D",
+		String:      "#15803D",
+		Number:      "#C2410C",
+		Comment:     "#64748B",
+		Attribute:   "#A16207",
+		Operator:    "#475569",
+		Punctuation: "#334155",
+	}
+)
+
+// NewHighlightTheme builds a highlight theme matching the application theme name.
+func NewHighlightTheme(themeName string) *HighlightTheme {
+	return NewHighlightThemeFromPalette(BuiltinPalette(themeName))
+}
+
+// BuiltinPalette returns the palette of a built-in theme; unknown names get the dark one.
+func BuiltinPalette(themeName string) HighlightPalette {
+	if themeName == "light" {
+		return LightPalette
+	}
+	return DarkPalette
+}
+
+// WithColors returns a copy of the palette with colors replaced by token kind
+// name (keyword, string, ...). Unknown names and invalid colors are skipped,
+// so the palette falls back to its own color for them.
+func (p HighlightPalette) WithColors(colors map[string]string) HighlightPalette {
+	fields := map[string]*string{
+		"text":        &p.Text,
+		"keyword":     &p.Keyword,
+		"type":        &p.Type,
+		"literal":     &p.Literal,
+		"string":      &p.String,
+		"number":      &p.Number,
+		"comment":     &p.Comment,
+		"attribute":   &p.Attribute,
+		"operator":    &p.Operator,
+		"punctuation": &p.Punctuation,
+	}
+	for name, color := range colors {
+		if field, ok := fields[strings.ToLower(name)]; ok && ValidColor(color) {
+			*field = color
+		}
+	}
+	return p
+}
+
+// ValidColor reports whether c is a color lipgloss understands: #RGB,
+// #RRGGBB or an ANSI color number 0–255.
+func ValidColor(c string) bool {
+	if hex, ok := strings.CutPrefix(c, "#"); ok {
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// NewHighlightThemeFromPalette builds a highlight theme from explicit colors.
+func NewHighlightThemeFromPalette(p HighlightPalette) *HighlightTheme {
+	color := func(c string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(c))
+	}
+	return &HighlightTheme{
+		styles: map[TokenKind]lipgloss.Style{
+			TokenText:        color(p.Text),
+			TokenKeyword:     color(p.Keyword).Bold(true),
+			TokenType:        color(p.Type),
+			TokenLiteral:     color(p.Literal),
+			TokenString:      color(p.String),
+			TokenNumber:      color(p.Number),
+			TokenComment:     color(p.Comment).Italic(true),
+			TokenAttribute:   color(p.Attribute),
+			TokenOperator:    color(p.Operator),
+			TokenPunctuation: color(p.Punctuation),
+		},
+	}
+}
+
+// Style returns the style for a token kind.
+func (t *HighlightTheme) Style(kind TokenKind) lipgloss.Style {
+	if t == nil {
+		return lipgloss.NewStyle()
+	}
+	if style, ok := t.styles[kind]; ok {
+		return style
+	}
+	return t.styles[TokenText]
+}
+
+// SupportsFile reports whether the lexer understands the given file name.
+func SupportsFile(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".sg")
+}
@@ -0,0 +1,144 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ChoiceCanceled возвращается в канал результата, если диалог закрыт без выбора.
+const ChoiceCanceled = -1
+
+// ChoiceDialog предоставляет окно с несколькими вариантами действия.
+type ChoiceDialog struct {
+	Title       string
+	Description string
+	Options     []string
+
+	Visible  bool
+	selected int
+	result   chan int
+	mu       sync.Mutex
+}
+
+// NewChoiceDialog создает диалог с перечисленными вариантами.
+func NewChoiceDialog(title, description string, options ...string) *ChoiceDialog {
+	return &ChoiceDialog{
+		Title:       title,
+		Description: description,
+		Options:     options,
+	}
+}
+
+// Show делает диалог видимым и возвращает канал с индексом выбранного варианта.
+func (d *ChoiceDialog) Show() <-chan int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.Visible && d.result != nil {
+		return d.result
+	}
+
+	d.result = make(chan int, 1)
+	d.selected = 0
+	d.Visible = true
+	return d.result
+}
+
+// Hide скрывает диалог без выбора.
+func (d *ChoiceDialog) Hide() {
+	d.respond(ChoiceCanceled)
+}
+
+// Update обрабатывает нажатия.
+func (d *ChoiceDialog) Update(msg tea.Msg) tea.Cmd {
+	d.mu.Lock()
+	visible := d.Visible
+	count := len(d.Options)
+	d.mu.Unlock()
+
+	if !visible || count == 0 {
+		return nil
+	}
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	switch key.String() {
+	case "left", "h", "shift+tab":
+		d.selected = (d.selected - 1 + count) % count
+	case "right", "l", "tab":
+		d.selected = (d.selected + 1) % count
+	case "enter":
+		d.respond(d.selected)
+	case "esc", "escape":
+		d.respond(ChoiceCanceled)
+	default:
+		// Быстрый выбор цифрой
+		if n, err := strconv.Atoi(key.String()); err == nil && n >= 1 && n <= count {
+			d.respond(n - 1)
+		}
+	}
+	return nil
+}
+
+// View отрисовывает диалог поверх остальных компонентов.
+func (d *ChoiceDialog) View() string {
+	d.mu.Lock()
+	visible := d.Visible
+	title := d.Title
+	desc := d.Description
+	options := append([]string(nil), d.Options...)
+	selected := d.selected
+	d.mu.Unlock()
+
+	if !visible {
+		return ""
+	}
+
+	border := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2)
+	titleView := lipgloss.NewStyle().Bold(true).Render(title)
+	descView := lipgloss.NewStyle().Render(desc)
+
+	activeStyle := lipgloss.NewStyle().Background(lipgloss.Color(accentColor)).Foreground(lipgloss.Color(onAccentColor)).Padding(0, 1)
+	inactiveStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(hintColor)).Padding(0, 1)
+
+	buttons := make([]string, 0, len(options))
+	for i, option := range options {
+		label := fmt.Sprintf("%d %s", i+1, option)
+		if i == selected {
+			buttons = append(buttons, activeStyle.Render(label))
+		} else {
+			buttons = append(buttons, inactiveStyle.Render(label))
+		}
+	}
+
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color(hintColor)).
+		Render("←→: Select • 1-9: Quick • Enter: Confirm • Esc: Cancel")
+
+	return border.Render(fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s", titleView, descView, strings.Join(buttons, "  "), hint))
+}
+
+func (d *ChoiceDialog) respond(value int) {
+	d.mu.Lock()
+	if !d.Visible && d.result == nil {
+		d.mu.Unlock()
+		return
+	}
+	ch := d.result
+	d.Visible = false
+	d.result = nil
+	d.mu.Unlock()
+
+	if ch != nil {
+		select {
+		case ch <- value:
+		default:
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MoreIndicator отмечает, что ниже видимой части панели есть ещё текст.
+const MoreIndicator = "▼ more"
+
+// WrapText переносит текст по словам под ширину панели; свои переводы строк сохраняются.
+// Слова длиннее ширины режутся принудительно.
+func WrapText(text string, width int) []string {
+	if width <= 0 {
+		return []string{text}
+	}
+	wrapped := lipgloss.NewStyle().Width(width).Render(text)
+	lines := strings.Split(wrapped, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines
+}
+
+// ScrollWindow возвращает видимые строки панели высотой height и скорректированный offset.
+// Если ниже остаётся текст, последняя строка отдаётся под индикатор MoreIndicator.
+func ScrollWindow(lines []string, offset, height int) ([]string, int) {
+	if height <= 0 {
+		return nil, 0
+	}
+	if len(lines) <= height {
+		return lines, 0
+	}
+	maxOffset := len(lines) - height
+	offset = min(max(offset, 0), maxOffset)
+	if offset == maxOffset {
+		return lines[offset:], offset
+	}
+	visible := append([]string{}, lines[offset:offset+height-1]...)
+	more := lipgloss.NewStyle().Foreground(lipgloss.Color(scrollbarThumbColor)).Render(MoreIndicator)
+	return append(visible, more), offset
+}
@@ -98,8 +98,8 @@ func (d *ConfirmDialog) View() string {
 	descView := lipgloss.NewStyle().Render(desc)
 
 	// Стили для кнопок
-	activeStyle := lipgloss.NewStyle().Background(lipgloss.Color("#7C3AED")).Foreground(lipgloss.Color("#FFFFFF")).Padding(0, 1)
-	inactiveStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#94A3B8")).Padding(0, 1)
+	activeStyle := lipgloss.NewStyle().Background(lipgloss.Color(accentColor)).Foreground(lipgloss.Color(onAccentColor)).Padding(0, 1)
+	inactiveStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(hintColor)).Padding(0, 1)
 
 	// Кнопки
 	var cancelBtn, confirmBtn string
@@ -113,7 +113,7 @@ func (d *ConfirmDialog) View() string {
 
 	buttons := lipgloss.JoinHorizontal(lipgloss.Center, cancelBtn, "  ", confirmBtn)
 
-	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("#94A3B8")).
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color(hintColor)).
 		Render("←→: Select • Y/N: Quick • Enter: Confirm • Esc: Cancel")
 
 	return border.Render(fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s", titleView, descView, buttons, hint))
@@ -225,10 +225,10 @@ func (d *InputDialog) Update(msg tea.Msg) tea.Cmd {
 		case "shift+tab":
 			d.selected = (d.selected + 1) % 2
 			return nil
-		case "left", "h":
+		case "left":
 			d.selected = 0 // Cancel
 			return nil
-		case "right", "l":
+		case "right":
 			d.selected = 1 // Confirm
 			return nil
 		case "escape":
@@ -270,8 +270,8 @@ func (d *InputDialog) View() string {
 	inputView := d.input.View()
 
 	// Стили для кнопок
-	activeStyle := lipgloss.NewStyle().Background(lipgloss.Color("#7C3AED")).Foreground(lipgloss.Color("#FFFFFF")).Padding(0, 1)
-	inactiveStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#94A3B8")).Padding(0, 1)
+	activeStyle := lipgloss.NewStyle().Background(lipgloss.Color(accentColor)).Foreground(lipgloss.Color(onAccentColor)).Padding(0, 1)
+	inactiveStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(hintColor)).Padding(0, 1)
 
 	// Кнопки
 	var cancelBtn, confirmBtn string
@@ -285,7 +285,7 @@ func (d *InputDialog) View() string {
 
 	buttons := lipgloss.JoinHorizontal(lipgloss.Center, cancelBtn, "  ", confirmBtn)
 
-	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("#94A3B8")).
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color(hintColor)).
 		Render("Tab/←→: Select • Enter: Confirm • Esc: Cancel")
 
 	return border.Render(fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s", titleView, inputView, buttons, hint))
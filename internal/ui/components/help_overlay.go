@@ -0,0 +1,100 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HelpOverlay показывает полную справку экрана поверх интерфейса.
+// Пока оверлей открыт, он перехватывает ввод: ↑↓ и колесо прокручивают, Esc закрывает.
+type HelpOverlay struct {
+	Visible bool
+
+	title  string
+	lines  []string
+	offset int
+	height int // строк справки на экране
+}
+
+// NewHelpOverlay создает скрытый оверлей справки.
+func NewHelpOverlay() *HelpOverlay {
+	return &HelpOverlay{}
+}
+
+// Show открывает справку; height — сколько строк помещается на экран.
+func (o *HelpOverlay) Show(title string, lines []string, height int) {
+	o.title = title
+	o.lines = lines
+	o.offset = 0
+	o.height = max(height, 3)
+	o.Visible = true
+}
+
+// Hide закрывает оверлей.
+func (o *HelpOverlay) Hide() {
+	o.Visible = false
+}
+
+// Update обрабатывает клавиши и мышь. Возвращает true, если сообщение поглощено.
+func (o *HelpOverlay) Update(msg tea.Msg) bool {
+	if !o.Visible {
+		return false
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			o.scroll(-1)
+		case "down", "j":
+			o.scroll(1)
+		case "pgup":
+			o.scroll(-o.height)
+		case "pgdown", "space":
+			o.scroll(o.height)
+		case "esc", "q", "enter", "f1", "?":
+			o.Hide()
+		}
+		return true
+	case tea.MouseMsg:
+		switch {
+		case msg.Button == tea.MouseButtonWheelUp:
+			o.scroll(-3)
+		case msg.Button == tea.MouseButtonWheelDown:
+			o.scroll(3)
+		case msg.Action == tea.MouseActionPress:
+			o.Hide()
+		}
+		return true
+	}
+	return false
+}
+
+func (o *HelpOverlay) scroll(delta int) {
+	o.offset = max(min(o.offset+delta, len(o.lines)-o.height), 0)
+}
+
+// View отрисовывает оверлей.
+func (o *HelpOverlay) View() string {
+	if !o.Visible {
+		return ""
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Render(o.title)
+	hintText := "Esc close"
+	if len(o.lines) > o.height {
+		hintText = fmt.Sprintf("↑↓ scroll (%d-%d of %d) • Esc close",
+			o.offset+1, min(o.offset+o.height, len(o.lines)), len(o.lines))
+	}
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color(hintColor)).Render(hintText)
+
+	end := min(o.offset+o.height, len(o.lines))
+	body := append([]string{title, hint, ""}, o.lines[o.offset:end]...)
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(accentColor)).
+		Padding(0, 1).
+		Render(strings.Join(body, "\n"))
+}
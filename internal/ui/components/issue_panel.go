@@ -0,0 +1,131 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Issue — строка панели проблем: что не так и как это исправить.
+type Issue struct {
+	Title  string
+	Detail string
+	Remedy string
+	Action string // куда ведёт Enter, например "Settings → Surge Binary Path"; пусто — некуда
+}
+
+// IssuePanel показывает список проблем поверх интерфейса. Пока панель
+// открыта, она перехватывает ввод: ↑↓ выбирают проблему, Enter переходит
+// к её исправлению, Esc закрывает.
+type IssuePanel struct {
+	Visible bool
+
+	title    string
+	issues   []Issue
+	selected int
+	width    int // ширина окна
+	height   int // строк списка на экране
+}
+
+// NewIssuePanel создает скрытую панель.
+func NewIssuePanel() *IssuePanel {
+	return &IssuePanel{}
+}
+
+// Show открывает панель шириной не больше width; height — сколько строк
+// списка помещается на экран.
+func (p *IssuePanel) Show(title string, issues []Issue, width, height int) {
+	p.title = title
+	p.issues = issues
+	p.selected = 0
+	p.width = width
+	p.height = max(height, 4)
+	p.Visible = true
+}
+
+// Hide закрывает панель.
+func (p *IssuePanel) Hide() {
+	p.Visible = false
+}
+
+// Update обрабатывает клавиши и мышь. Возвращает, поглощено ли сообщение, и
+// индекс проблемы, к исправлению которой надо перейти (-1 — никуда).
+func (p *IssuePanel) Update(msg tea.Msg) (bool, int) {
+	if !p.Visible {
+		return false, -1
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			p.selected = max(p.selected-1, 0)
+		case "down", "j":
+			p.selected = min(p.selected+1, len(p.issues)-1)
+		case "enter":
+			if p.selected < len(p.issues) && p.issues[p.selected].Action != "" {
+				p.Hide()
+				return true, p.selected
+			}
+		case "esc", "q":
+			p.Hide()
+		}
+		return true, -1
+	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			p.Hide()
+		}
+		return true, -1
+	}
+	return false, -1
+}
+
+// View отрисовывает панель.
+func (p *IssuePanel) View() string {
+	if !p.Visible {
+		return ""
+	}
+	title := lipgloss.NewStyle().Bold(true).Render(p.title)
+	hintText := "↑↓ select • Esc dismiss"
+	if p.selected < len(p.issues) && p.issues[p.selected].Action != "" {
+		hintText = fmt.Sprintf("↑↓ select • Enter: %s • Esc dismiss", p.issues[p.selected].Action)
+	}
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color(hintColor)).Render(hintText)
+
+	marker := lipgloss.NewStyle().Foreground(lipgloss.Color(errorColor)).Bold(true)
+	selected := lipgloss.NewStyle().Background(lipgloss.Color(accentColor)).Foreground(lipgloss.Color(onAccentColor))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(hintColor))
+
+	var lines []string
+	first := 0
+	for i, issue := range p.issues {
+		if i == p.selected {
+			first = len(lines)
+		}
+		head := issue.Title
+		if issue.Detail != "" {
+			head += ": " + issue.Detail
+		}
+		if i == p.selected {
+			head = selected.Render(head)
+		}
+		lines = append(lines, marker.Render("✖ ")+head)
+		if issue.Remedy != "" {
+			lines = append(lines, dim.Render("  → "+issue.Remedy))
+		}
+	}
+	// выбранная проблема остаётся на экране, даже если список длинный
+	offset := max(min(first, len(lines)-p.height), 0)
+	end := min(offset+p.height, len(lines))
+	body := strings.Join(append([]string{title, hint, ""}, lines[offset:end]...), "\n")
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(errorColor)).
+		Padding(0, 1)
+	// длинные строки переносятся, чтобы рамка не вылезла за окно
+	if inner := p.width - 4; inner > 0 && lipgloss.Width(body) > inner {
+		style = style.Width(inner)
+	}
+	return style.Render(body)
+}
@@ -0,0 +1,213 @@
+package components
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/platform"
+)
+
+// KeyDebugEntry описывает одно полученное событие клавиатуры.
+type KeyDebugEntry struct {
+	Type      string
+	Runes     string
+	Hex       string
+	Alt       bool
+	Paste     bool
+	Key       string
+	Canonical string
+	Mapping   []string
+	Unknown   bool
+}
+
+// KeyDebugCopiedMsg сообщает о результате копирования дампа в буфер обмена.
+type KeyDebugCopiedMsg struct {
+	Err error
+}
+
+// KeyDebugOverlay показывает последние полученные клавиши в сыром виде.
+// Пока оверлей открыт, он перехватывает весь ввод: Esc закрывает, Ctrl+Y копирует дамп.
+type KeyDebugOverlay struct {
+	Visible bool
+	Limit   int
+
+	entries []KeyDebugEntry
+	status  string
+}
+
+// NewKeyDebugOverlay создает оверлей, хранящий не более limit событий.
+func NewKeyDebugOverlay(limit int) *KeyDebugOverlay {
+	if limit <= 0 {
+		limit = 12
+	}
+	return &KeyDebugOverlay{Limit: limit}
+}
+
+// Show открывает оверлей с пустой историей.
+func (o *KeyDebugOverlay) Show() {
+	o.entries = nil
+	o.status = ""
+	o.Visible = true
+}
+
+// Hide закрывает оверлей.
+func (o *KeyDebugOverlay) Hide() {
+	o.Visible = false
+}
+
+// Update записывает событие и обрабатывает служебные клавиши.
+// Возвращает true, если сообщение поглощено оверлеем.
+func (o *KeyDebugOverlay) Update(msg tea.Msg) (bool, tea.Cmd) {
+	if copied, ok := msg.(KeyDebugCopiedMsg); ok {
+		if copied.Err != nil {
+			o.status = "Copy failed: " + copied.Err.Error()
+		} else {
+			o.status = "Dump copied to clipboard"
+		}
+		return true, nil
+	}
+	if !o.Visible {
+		return false, nil
+	}
+
+	if key, ok := msg.(tea.KeyMsg); ok {
+		o.record(describeKey(key))
+		switch key.String() {
+		case "esc":
+			o.Hide()
+		case "ctrl+y":
+			dump := o.Dump()
+			return true, func() tea.Msg {
+				return KeyDebugCopiedMsg{Err: clipboard.WriteAll(dump)}
+			}
+		}
+		return true, nil
+	}
+	if entry, ok := describeUnknownInput(msg); ok {
+		o.record(entry)
+		return true, nil
+	}
+	return false, nil
+}
+
+// Entries возвращает записанные события, от старых к новым.
+func (o *KeyDebugOverlay) Entries() []KeyDebugEntry {
+	return o.entries
+}
+
+// Dump формирует текстовый отчёт для баг-репортов.
+func (o *KeyDebugOverlay) Dump() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "surge-tui key dump (%d events)\n", len(o.entries))
+	for i, e := range o.entries {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, formatKeyEntry(e))
+		for _, step := range e.Mapping {
+			fmt.Fprintf(&b, "     %s\n", step)
+		}
+	}
+	return b.String()
+}
+
+// View отрисовывает оверлей.
+func (o *KeyDebugOverlay) View() string {
+	if !o.Visible {
+		return ""
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Render("Key Debug")
+	hint := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(hintColor)).
+		Render(platform.ReplacePrimaryModifier("Press keys to capture • Ctrl+Y copy dump • Esc close"))
+
+	lines := []string{title, hint, ""}
+	if len(o.entries) == 0 {
+		lines = append(lines, "Waiting for input...")
+	}
+	for _, e := range o.entries {
+		line := formatKeyEntry(e)
+		if e.Unknown {
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color(warnColor)).Render(line)
+		}
+		lines = append(lines, line)
+		for _, step := range e.Mapping {
+			lines = append(lines, "    ↳ "+step)
+		}
+	}
+	if o.status != "" {
+		lines = append(lines, "", o.status)
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(accentColor)).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+func (o *KeyDebugOverlay) record(entry KeyDebugEntry) {
+	o.entries = append(o.entries, entry)
+	if len(o.entries) > o.Limit {
+		o.entries = o.entries[len(o.entries)-o.Limit:]
+	}
+}
+
+func describeKey(key tea.KeyMsg) KeyDebugEntry {
+	canonical, mapping := platform.CanonicalKeyTrace(key.String())
+	return KeyDebugEntry{
+		Type:      fmt.Sprintf("%s(%d)", key.Type.String(), int(key.Type)),
+		Runes:     fmt.Sprintf("%q", string(key.Runes)),
+		Hex:       hexBytes([]byte(string(key.Runes))),
+		Alt:       key.Alt,
+		Paste:     key.Paste,
+		Key:       key.String(),
+		Canonical: canonical,
+		Mapping:   mapping,
+	}
+}
+
+// describeUnknownInput распознаёт неразобранные bubbletea последовательности
+// (неизвестные CSI и невалидные байты) и достает из них сырые байты.
+func describeUnknownInput(msg tea.Msg) (KeyDebugEntry, bool) {
+	if msg == nil {
+		return KeyDebugEntry{}, false
+	}
+	t := reflect.TypeOf(msg)
+	if t.PkgPath() != reflect.TypeOf(tea.KeyMsg{}).PkgPath() || !strings.HasPrefix(t.Name(), "unknown") {
+		return KeyDebugEntry{}, false
+	}
+
+	var raw []byte
+	v := reflect.ValueOf(msg)
+	switch {
+	case v.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		raw = v.Bytes()
+	case v.Kind() == reflect.Uint8:
+		raw = []byte{byte(v.Uint())}
+	}
+	return KeyDebugEntry{
+		Type:    t.Name(),
+		Hex:     hexBytes(raw),
+		Key:     fmt.Sprint(msg),
+		Unknown: true,
+	}, true
+}
+
+func formatKeyEntry(e KeyDebugEntry) string {
+	if e.Unknown {
+		return fmt.Sprintf("%s hex=[%s] %s", e.Type, e.Hex, e.Key)
+	}
+	return fmt.Sprintf("type=%s runes=%s hex=[%s] alt=%t paste=%t str=%q canonical=%q",
+		e.Type, e.Runes, e.Hex, e.Alt, e.Paste, e.Key, e.Canonical)
+}
+
+func hexBytes(data []byte) string {
+	parts := make([]string, len(data))
+	for i, b := range data {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, " ")
+}
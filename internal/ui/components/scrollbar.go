@@ -0,0 +1,93 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	scrollbarTrack = "│"
+	scrollbarThumb = "┃"
+)
+
+// ScrollbarThumb вычисляет начало и высоту ползунка для полосы высотой height.
+// total — число строк контента, offset — первая видимая строка, viewport — видимых строк.
+// Ползунок всегда занимает хотя бы одну ячейку, даже в очень маленьком окне.
+func ScrollbarThumb(total, offset, viewport, height int) (int, int) {
+	if height <= 0 {
+		return 0, 0
+	}
+	if total <= viewport || viewport <= 0 {
+		return 0, height
+	}
+
+	size := height * viewport / total
+	size = min(max(size, 1), height)
+
+	maxOffset := total - viewport
+	offset = min(max(offset, 0), maxOffset)
+	track := height - size
+	start := (offset*track + maxOffset/2) / maxOffset
+	return min(start, track), size
+}
+
+// ScrollbarVisible сообщает, нужна ли полоса прокрутки: короткие списки её не показывают.
+func ScrollbarVisible(total, viewport int) bool {
+	return viewport > 0 && total > viewport
+}
+
+// VisibleWindow возвращает полуоткрытый диапазон [start, end) видимых строк списка.
+func VisibleWindow(offset, viewport, total int) (int, int) {
+	if total <= 0 || viewport <= 0 {
+		return 0, 0
+	}
+	start := min(max(offset, 0), max(total-1, 0))
+	return start, min(start+viewport, total)
+}
+
+// ListCounter форматирует позицию выбранного элемента, например "42/317".
+func ListCounter(selected, total int) string {
+	if total <= 0 {
+		return "0/0"
+	}
+	return fmt.Sprintf("%d/%d", min(max(selected, 0), total-1)+1, total)
+}
+
+// RenderScrollbar возвращает колонку полосы прокрутки по одной ячейке на строку.
+// Если весь контент помещается, возвращает пустые ячейки.
+func RenderScrollbar(total, offset, viewport, height int) []string {
+	cells := make([]string, max(height, 0))
+	if total <= viewport {
+		for i := range cells {
+			cells[i] = " "
+		}
+		return cells
+	}
+
+	trackStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(scrollbarTrackColor))
+	thumbStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(scrollbarThumbColor))
+	start, size := ScrollbarThumb(total, offset, viewport, height)
+	for i := range cells {
+		if i >= start && i < start+size {
+			cells[i] = thumbStyle.Render(scrollbarThumb)
+		} else {
+			cells[i] = trackStyle.Render(scrollbarTrack)
+		}
+	}
+	return cells
+}
+
+// ScrollIndicator форматирует положение прокрутки, например "45% 120/3400".
+func ScrollIndicator(offset, viewport, total int) string {
+	if total <= 0 {
+		return "0/0"
+	}
+	if total <= viewport {
+		return fmt.Sprintf("All %d/%d", total, total)
+	}
+	maxOffset := total - viewport
+	offset = min(max(offset, 0), maxOffset)
+	percent := offset * 100 / maxOffset
+	return fmt.Sprintf("%d%% %d/%d", percent, offset+1, total)
+}
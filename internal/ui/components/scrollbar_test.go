@@ -0,0 +1,76 @@
+package components
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScrollbarThumb(t *testing.T) {
+	tests := []struct {
+		name                            string
+		total, offset, viewport, height int
+		wantStart, wantSize             int
+	}{
+		{"высота 1, начало", 100, 0, 10, 1, 0, 1},
+		{"высота 1, конец", 100, 90, 10, 1, 0, 1},
+		{"высота 2, начало", 100, 0, 10, 2, 0, 1},
+		{"высота 2, конец", 100, 90, 10, 2, 1, 1},
+		{"огромный контент, ползунок не меньше ячейки", 10000, 0, 20, 20, 0, 1},
+		{"огромный контент, конец прижат к низу", 10000, 9980, 20, 20, 19, 1},
+		{"смещение за концом обрезается", 10000, 50000, 20, 20, 19, 1},
+		{"середина", 100, 45, 10, 10, 5, 1},
+		{"пропорциональный размер у конца", 40, 20, 20, 10, 5, 5},
+		{"контент помещается", 10, 0, 10, 5, 0, 5},
+		{"контента меньше окна", 3, 0, 10, 5, 0, 5},
+		{"нулевая высота", 100, 0, 10, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, size := ScrollbarThumb(tt.total, tt.offset, tt.viewport, tt.height)
+			if start != tt.wantStart || size != tt.wantSize {
+				t.Fatalf("ScrollbarThumb = (%d, %d), want (%d, %d)", start, size, tt.wantStart, tt.wantSize)
+			}
+			if tt.height > 0 && start+size > tt.height {
+				t.Fatalf("ползунок [%d, %d) выходит за полосу высотой %d", start, start+size, tt.height)
+			}
+		})
+	}
+}
+
+func TestRenderScrollbar(t *testing.T) {
+	tests := []struct {
+		name                            string
+		total, offset, viewport, height int
+		want                            string // t — ползунок, | — дорожка, _ — пустая ячейка
+	}{
+		{"высота 1", 100, 50, 10, 1, "t"},
+		{"высота 2, начало", 100, 0, 10, 2, "t|"},
+		{"высота 2, конец", 100, 90, 10, 2, "|t"},
+		{"огромный контент, конец", 10000, 9995, 5, 5, "||||t"},
+		{"пропорциональный ползунок у конца", 40, 20, 20, 10, "|||||ttttt"},
+		{"контент помещается", 10, 0, 10, 3, "___"},
+		{"контента меньше окна", 2, 0, 10, 3, "___"},
+		{"нулевая высота", 100, 0, 10, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := RenderScrollbar(tt.total, tt.offset, tt.viewport, tt.height)
+			var got strings.Builder
+			for _, cell := range cells {
+				switch {
+				case strings.Contains(cell, scrollbarThumb):
+					got.WriteByte('t')
+				case strings.Contains(cell, scrollbarTrack):
+					got.WriteByte('|')
+				case cell == " ":
+					got.WriteByte('_')
+				default:
+					t.Fatalf("неожиданная ячейка %q", cell)
+				}
+			}
+			if got.String() != tt.want {
+				t.Errorf("RenderScrollbar = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
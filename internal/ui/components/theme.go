@@ -0,0 +1,34 @@
+package components
+
+import "surge-tui/internal/ui/styles"
+
+// Цвета диалогов, оверлеев и полос прокрутки. Они берутся из палитры темы
+// приложения; UsePalette меняет их при смене темы, до неё действует тёмная.
+var (
+	accentColor         string // выбранная кнопка, рамка оверлея
+	onAccentColor       string // текст выбранной кнопки
+	hintColor           string // подсказки и невыбранные кнопки
+	warnColor           string // непонятые нажатия в Key Debug
+	infoColor           string // фон уведомлений по уровням
+	warningColor        string
+	errorColor          string
+	scrollbarTrackColor string
+	scrollbarThumbColor string
+)
+
+func init() {
+	UsePalette(styles.DarkPalette)
+}
+
+// UsePalette переключает компоненты на палитру темы.
+func UsePalette(p styles.Palette) {
+	accentColor = p.Primary
+	onAccentColor = p.OnPrimary
+	hintColor = p.TextDim
+	warnColor = p.Accent
+	infoColor = p.Info
+	warningColor = p.Warning
+	errorColor = p.Error
+	scrollbarTrackColor = p.Border
+	scrollbarThumbColor = p.TextDim
+}
@@ -0,0 +1,143 @@
+package components
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/logging"
+)
+
+// Сколько уведомление висит на экране: ошибки дольше, чтобы их успели прочитать.
+const (
+	toastInfoTTL  = 3 * time.Second
+	toastWarnTTL  = 5 * time.Second
+	toastErrorTTL = 8 * time.Second
+)
+
+// ToastExpiredMsg приходит, когда истекло время уведомления ID.
+type ToastExpiredMsg struct {
+	ID int
+}
+
+type toast struct {
+	id    int
+	level logging.Level
+	text  string
+}
+
+// Toasts — стопка временных уведомлений над статус-баром. Новое уведомление
+// встаёт снизу; сверх Limit вытесняются самые старые.
+type Toasts struct {
+	Limit int
+
+	items  []toast
+	nextID int
+}
+
+// NewToasts создаёт стопку, показывающую не более limit уведомлений.
+func NewToasts(limit int) *Toasts {
+	if limit <= 0 {
+		limit = 3
+	}
+	return &Toasts{Limit: limit}
+}
+
+// Push показывает уведомление и возвращает таймер его закрытия.
+func (t *Toasts) Push(level logging.Level, text string) tea.Cmd {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	// повтор последнего уведомления не множит стопку, а продлевает его
+	if n := len(t.items); n > 0 && t.items[n-1].text == text && t.items[n-1].level == level {
+		t.items = t.items[:n-1]
+	}
+	t.nextID++
+	t.items = append(t.items, toast{id: t.nextID, level: level, text: text})
+	if extra := len(t.items) - t.Limit; extra > 0 {
+		t.items = t.items[extra:]
+	}
+	id := t.nextID
+	return tea.Tick(toastTTL(level), func(time.Time) tea.Msg { return ToastExpiredMsg{ID: id} })
+}
+
+// Dismiss убирает уведомление id, если оно ещё показано.
+func (t *Toasts) Dismiss(id int) {
+	for i, item := range t.items {
+		if item.id == id {
+			t.items = append(t.items[:i], t.items[i+1:]...)
+			return
+		}
+	}
+}
+
+// Clear убирает все уведомления.
+func (t *Toasts) Clear() {
+	t.items = nil
+}
+
+// Len — сколько уведомлений сейчас показано.
+func (t *Toasts) Len() int {
+	return len(t.items)
+}
+
+// Lines возвращает строки уведомлений шириной width, от старых к новым.
+func (t *Toasts) Lines(width int) []string {
+	lines := make([]string, 0, len(t.items))
+	for _, item := range t.items {
+		style := lipgloss.NewStyle().
+			Width(width).
+			Padding(0, 1).
+			Bold(item.level >= logging.LevelError).
+			Foreground(lipgloss.Color(onAccentColor)).
+			Background(lipgloss.Color(toastColor(item.level)))
+		text := toastIcon(item.level) + " " + item.text
+		lines = append(lines, style.Render(truncateToast(text, max(width-2, 1))))
+	}
+	return lines
+}
+
+func toastTTL(level logging.Level) time.Duration {
+	switch {
+	case level >= logging.LevelError:
+		return toastErrorTTL
+	case level == logging.LevelWarn:
+		return toastWarnTTL
+	}
+	return toastInfoTTL
+}
+
+func toastColor(level logging.Level) string {
+	switch {
+	case level >= logging.LevelError:
+		return errorColor
+	case level == logging.LevelWarn:
+		return warningColor
+	}
+	return infoColor
+}
+
+func toastIcon(level logging.Level) string {
+	switch {
+	case level >= logging.LevelError:
+		return "✖"
+	case level == logging.LevelWarn:
+		return "!"
+	}
+	return "ℹ"
+}
+
+// truncateToast укорачивает текст до width колонок с многоточием.
+func truncateToast(text string, width int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if lipgloss.Width(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
@@ -0,0 +1,124 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	core "surge-tui/internal/core/surge"
+	"surge-tui/internal/ui/components"
+)
+
+const buildHeaderHeight = 4
+
+func (bs *BuildScreen) View() string {
+	if bs.Width() == 0 {
+		return "Initializing build view..."
+	}
+	width := bs.Width()
+	sections := []string{bs.renderHeader(width), bs.renderLog(width), bs.renderFooter(width)}
+	return strings.Join(sections, "\n")
+}
+
+// logHeight — строк лога между шапкой и подвалом.
+func (bs *BuildScreen) logHeight() int {
+	return max(bs.Height()-buildHeaderHeight-3, 1)
+}
+
+func (bs *BuildScreen) renderHeader(width int) string {
+	secondary := lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor))
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(diagHeaderColor)).Render("Build")
+
+	projectPath := bs.projectPath
+	if projectPath == "" {
+		projectPath = "(project not set)"
+	} else if w := width - 20; w > 0 {
+		projectPath = truncatePath(projectPath, w)
+	}
+
+	status := bs.status
+	statusStyle := secondary
+	switch {
+	case bs.running && bs.cancel != nil:
+		status = fmt.Sprintf("Building… %s", bs.elapsed().Round(time.Second))
+	case bs.err != nil || (!bs.finished.IsZero() && bs.exitCode > 0):
+		statusStyle = statusStyle.Foreground(lipgloss.Color(diagErrorColor))
+	}
+
+	counts := fmt.Sprintf("%d lines", len(bs.log))
+	if bs.errors+bs.warnings > 0 {
+		counts += fmt.Sprintf("  •  %s, %s", plural(bs.errors, "error"), plural(bs.warnings, "warning"))
+	}
+	if len(bs.log) > 0 {
+		counts += "  •  " + components.ScrollIndicator(bs.scroll, bs.logHeight(), len(bs.log))
+	}
+
+	lines := []string{
+		title,
+		secondary.Render("Project: " + projectPath),
+		statusStyle.Render(truncateString(status, width)),
+		secondary.Render(counts),
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (bs *BuildScreen) renderLog(width int) string {
+	height := bs.logHeight()
+	if len(bs.log) == 0 {
+		msg := "No output yet."
+		if bs.running {
+			msg = "Waiting for surge build output…"
+		}
+		return lipgloss.NewStyle().Width(width).Height(height).
+			Foreground(lipgloss.Color(diagSecondaryColor)).Render(msg)
+	}
+
+	showBar := components.ScrollbarVisible(len(bs.log), height)
+	lineWidth := width
+	if showBar {
+		lineWidth = max(width-1, 1)
+	}
+	start, end := components.VisibleWindow(bs.scroll, height, len(bs.log))
+	rows := make([]string, 0, height)
+	for i := start; i < end; i++ {
+		style := bs.lineStyle(bs.log[i]).Width(lineWidth)
+		if i == bs.selected {
+			style = style.Background(lipgloss.Color(diagSelectedBg)).Foreground(lipgloss.Color(diagSelectedFg))
+		}
+		rows = append(rows, style.Render(truncateString(bs.log[i].text, lineWidth)))
+	}
+	for len(rows) < height {
+		rows = append(rows, "")
+	}
+	logView := lipgloss.NewStyle().Width(lineWidth).Render(strings.Join(rows, "\n"))
+	if showBar {
+		bar := components.RenderScrollbar(len(bs.log), bs.scroll, height, height)
+		logView = lipgloss.JoinHorizontal(lipgloss.Top, logView, strings.Join(bar, "\n"))
+	}
+	return logView
+}
+
+func (bs *BuildScreen) lineStyle(line buildLogLine) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	switch {
+	case line.diag != nil && line.diag.IsError():
+		return style.Foreground(lipgloss.Color(diagErrorColor))
+	case line.diag != nil && line.diag.IsWarning():
+		return style.Foreground(lipgloss.Color(diagWarningColor))
+	case line.diag != nil:
+		return style.Foreground(lipgloss.Color(diagInfoColor))
+	case line.stream == core.BuildStderr:
+		return style.Foreground(lipgloss.Color(diagSecondaryColor))
+	}
+	return style
+}
+
+func (bs *BuildScreen) renderFooter(width int) string {
+	hint := "F5 rebuild • n/N diagnostics • Enter open"
+	if bs.running {
+		hint = "Esc cancel build"
+	}
+	return "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor)).Render(truncateString(hint, width))
+}
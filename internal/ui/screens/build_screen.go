@@ -0,0 +1,395 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	core "surge-tui/internal/core/surge"
+	"surge-tui/internal/platform"
+)
+
+const (
+	buildChunkSize   = 256   // строк вывода в одном сообщении
+	maxBuildLogLines = 10000 // старые строки лога отбрасываются
+)
+
+// BuildScreen запускает `surge build` и показывает вывод по мере поступления.
+type BuildScreen struct {
+	BaseScreen
+
+	projectPath string
+	client      core.SurgeRunner
+
+	runID    int // номер текущего запуска; сообщения старых запусков отбрасываются
+	running  bool
+	cancel   context.CancelFunc
+	started  time.Time
+	finished time.Time
+	exitCode int
+	err      error
+	status   string
+
+	log      []buildLogLine
+	errors   int
+	warnings int
+	selected int
+	scroll   int
+}
+
+type buildLogLine struct {
+	stream core.BuildStream
+	text   string
+	diag   *core.Diagnostic
+	path   string // абсолютный путь диагностики
+}
+
+// BuildEvent — сообщения запуска сборки. App доставляет их экрану сборки,
+// даже если он не активен: иначе вывод теряется, а процесс встаёт на записи в pipe.
+type BuildEvent interface {
+	buildEvent()
+}
+
+// StartBuildMsg просит экран сборки запустить `surge build`.
+type StartBuildMsg struct{}
+
+type buildStartedMsg struct {
+	run   int
+	build *core.BuildRun
+}
+
+type buildOutputMsg struct {
+	run    int
+	build  *core.BuildRun
+	lines  []core.BuildLine
+	closed bool
+}
+
+type buildDoneMsg struct {
+	run      int
+	exitCode int
+	err      error
+}
+
+type buildTickMsg struct {
+	run int
+}
+
+func (StartBuildMsg) buildEvent()   {}
+func (buildStartedMsg) buildEvent() {}
+func (buildOutputMsg) buildEvent()  {}
+func (buildDoneMsg) buildEvent()    {}
+func (buildTickMsg) buildEvent()    {}
+
+// NewBuildScreen создаёт экран сборки; сборка запускается по StartBuildMsg или F5.
+func NewBuildScreen(projectPath string, client core.SurgeRunner) *BuildScreen {
+	return &BuildScreen{
+		BaseScreen:  NewBaseScreen("Build"),
+		projectPath: projectPath,
+		client:      client,
+		status:      "Press F5 to run surge build",
+	}
+}
+
+func (bs *BuildScreen) Init() tea.Cmd {
+	return nil
+}
+
+func (bs *BuildScreen) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	switch m := msg.(type) {
+	case tea.WindowSizeMsg:
+		bs.SetSize(m.Width, m.Height-1)
+		bs.ensureSelectionVisible()
+	case tea.KeyMsg:
+		return bs, bs.handleKey(m)
+	case StartBuildMsg:
+		return bs, bs.startBuild()
+	case buildStartedMsg:
+		if m.run != bs.runID {
+			return bs, drainBuild(m.build)
+		}
+		return bs, readBuildChunk(m.run, m.build)
+	case buildOutputMsg:
+		return bs, bs.handleOutput(m)
+	case buildDoneMsg:
+		bs.handleDone(m)
+	case buildTickMsg:
+		if m.run == bs.runID && bs.running {
+			return bs, bs.tick()
+		}
+	}
+	return bs, nil
+}
+
+func (bs *BuildScreen) startBuild() tea.Cmd {
+	if bs.client == nil {
+		bs.err = errors.New("surge client not configured")
+		bs.status = "Surge client unavailable"
+		return nil
+	}
+	if bs.cancel != nil {
+		bs.cancel()
+	}
+	bs.runID++
+	bs.running = true
+	bs.started = time.Now()
+	bs.finished = time.Time{}
+	bs.exitCode, bs.err = 0, nil
+	bs.log = nil
+	bs.errors, bs.warnings = 0, 0
+	bs.selected, bs.scroll = 0, 0
+	bs.status = "Building…"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bs.cancel = cancel
+	client, projectPath, run := bs.client, bs.projectPath, bs.runID
+	start := func() tea.Msg {
+		build, err := client.StartBuild(ctx, projectPath)
+		if err != nil {
+			return buildDoneMsg{run: run, exitCode: -1, err: err}
+		}
+		return buildStartedMsg{run: run, build: build}
+	}
+	return tea.Batch(start, bs.tick())
+}
+
+// readBuildChunk ждёт первую строку и забирает то, что уже накопилось, не больше buildChunkSize.
+func readBuildChunk(run int, build *core.BuildRun) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-build.Lines
+		if !ok {
+			return buildOutputMsg{run: run, build: build, closed: true}
+		}
+		chunk := []core.BuildLine{line}
+		for len(chunk) < buildChunkSize {
+			select {
+			case next, ok := <-build.Lines:
+				if !ok {
+					return buildOutputMsg{run: run, build: build, lines: chunk, closed: true}
+				}
+				chunk = append(chunk, next)
+			default:
+				return buildOutputMsg{run: run, build: build, lines: chunk}
+			}
+		}
+		return buildOutputMsg{run: run, build: build, lines: chunk}
+	}
+}
+
+// drainBuild дочитывает вывод отменённого запуска, чтобы его горутины завершились.
+func drainBuild(build *core.BuildRun) tea.Cmd {
+	return func() tea.Msg {
+		for range build.Lines {
+		}
+		return nil
+	}
+}
+
+func (bs *BuildScreen) handleOutput(msg buildOutputMsg) tea.Cmd {
+	if msg.run != bs.runID {
+		if !msg.closed {
+			return drainBuild(msg.build)
+		}
+		return nil
+	}
+	follow := len(bs.log) == 0 || bs.selected == len(bs.log)-1
+	for _, line := range msg.lines {
+		bs.appendLine(line)
+	}
+	bs.trimLog()
+	if follow {
+		bs.setSelection(len(bs.log) - 1)
+	}
+	if msg.closed {
+		build, run := msg.build, msg.run
+		return func() tea.Msg {
+			exitCode, err := build.Wait()
+			return buildDoneMsg{run: run, exitCode: exitCode, err: err}
+		}
+	}
+	return readBuildChunk(msg.run, msg.build)
+}
+
+func (bs *BuildScreen) appendLine(line core.BuildLine) {
+	entry := buildLogLine{stream: line.Stream, text: line.Text, diag: line.Diagnostic}
+	if d := line.Diagnostic; d != nil {
+		entry.text = d.String()
+		if d.File != "" {
+			entry.path = d.File
+			if !filepath.IsAbs(entry.path) && bs.projectPath != "" {
+				entry.path = filepath.Join(bs.projectPath, entry.path)
+			}
+		}
+		switch {
+		case d.IsError():
+			bs.errors++
+		case d.IsWarning():
+			bs.warnings++
+		}
+	}
+	bs.log = append(bs.log, entry)
+}
+
+func (bs *BuildScreen) trimLog() {
+	extra := len(bs.log) - maxBuildLogLines
+	if extra <= 0 {
+		return
+	}
+	bs.log = append(bs.log[:0], bs.log[extra:]...)
+	bs.selected = max(bs.selected-extra, 0)
+	bs.scroll = max(bs.scroll-extra, 0)
+}
+
+func (bs *BuildScreen) handleDone(msg buildDoneMsg) {
+	if msg.run != bs.runID {
+		return
+	}
+	bs.running = false
+	bs.cancel = nil
+	bs.finished = time.Now()
+	bs.exitCode = msg.exitCode
+	elapsed := bs.elapsed().Round(100 * time.Millisecond)
+	switch {
+	case errors.Is(msg.err, context.Canceled):
+		bs.status = fmt.Sprintf("Build cancelled after %s", elapsed)
+	case msg.err != nil:
+		bs.err = msg.err
+		bs.status = fmt.Sprintf("Build error: %v", msg.err)
+	case msg.exitCode == 0:
+		bs.status = fmt.Sprintf("Build succeeded in %s (exit 0)", elapsed)
+	default:
+		bs.status = fmt.Sprintf("Build failed in %s (exit %d)", elapsed, msg.exitCode)
+	}
+}
+
+// tick обновляет время сборки на экране, пока она идёт.
+func (bs *BuildScreen) tick() tea.Cmd {
+	run := bs.runID
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return buildTickMsg{run: run}
+	})
+}
+
+func (bs *BuildScreen) elapsed() time.Duration {
+	if bs.started.IsZero() {
+		return 0
+	}
+	if bs.finished.IsZero() {
+		return time.Since(bs.started)
+	}
+	return bs.finished.Sub(bs.started)
+}
+
+func (bs *BuildScreen) cancelBuild() {
+	if bs.running && bs.cancel != nil {
+		bs.cancel()
+		bs.cancel = nil
+		bs.status = "Cancelling build…"
+	}
+}
+
+// Stop отменяет идущую сборку, например при смене проекта.
+func (bs *BuildScreen) Stop() {
+	bs.cancelBuild()
+}
+
+// HandleGlobalEsc отменяет идущую сборку вместо выхода с экрана.
+func (bs *BuildScreen) HandleGlobalEsc() (bool, tea.Cmd) {
+	if !bs.running {
+		return false, nil
+	}
+	bs.cancelBuild()
+	return true, nil
+}
+
+func (bs *BuildScreen) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch platform.CanonicalKeyForLookup(msg.String()) {
+	case "f5", "ctrl+r", "r":
+		return bs.startBuild()
+	case "up", "k":
+		bs.setSelection(bs.selected - 1)
+	case "down", "j":
+		bs.setSelection(bs.selected + 1)
+	case "pgup", "ctrl+u":
+		bs.setSelection(bs.selected - bs.logHeight())
+	case "pgdown", "ctrl+d":
+		bs.setSelection(bs.selected + bs.logHeight())
+	case "home", "g":
+		bs.setSelection(0)
+	case "end", "G":
+		bs.setSelection(len(bs.log) - 1)
+	case "n":
+		bs.jumpToDiagnostic(1)
+	case "N":
+		bs.jumpToDiagnostic(-1)
+	case "enter":
+		return bs.openSelected()
+	}
+	return nil
+}
+
+func (bs *BuildScreen) setSelection(index int) {
+	if len(bs.log) == 0 {
+		bs.selected, bs.scroll = 0, 0
+		return
+	}
+	bs.selected = clampInt(index, 0, len(bs.log)-1)
+	bs.ensureSelectionVisible()
+}
+
+func (bs *BuildScreen) ensureSelectionVisible() {
+	height := bs.logHeight()
+	if height <= 0 {
+		return
+	}
+	if bs.selected < bs.scroll {
+		bs.scroll = bs.selected
+	} else if bs.selected >= bs.scroll+height {
+		bs.scroll = bs.selected - height + 1
+	}
+	bs.scroll = clampInt(bs.scroll, 0, max(len(bs.log)-height, 0))
+}
+
+// jumpToDiagnostic переводит курсор к следующей (delta > 0) или предыдущей диагностике.
+func (bs *BuildScreen) jumpToDiagnostic(delta int) {
+	for i := bs.selected + delta; i >= 0 && i < len(bs.log); i += delta {
+		if bs.log[i].diag != nil {
+			bs.setSelection(i)
+			return
+		}
+	}
+}
+
+func (bs *BuildScreen) openSelected() tea.Cmd {
+	if bs.selected >= len(bs.log) {
+		return nil
+	}
+	entry := bs.log[bs.selected]
+	if entry.diag == nil || entry.path == "" {
+		return nil
+	}
+	location := OpenLocationMsg{FilePath: entry.path, Line: max(entry.diag.Line, 1), Column: max(entry.diag.Column, 1)}
+	return func() tea.Msg { return location }
+}
+
+func (bs *BuildScreen) ShortHelp() string {
+	return "F5 Build • Esc Cancel • ↑↓ Scroll • n/N Next/prev diagnostic • Enter Open"
+}
+
+func (bs *BuildScreen) FullHelp() []string {
+	help := bs.BaseScreen.FullHelp()
+	help = append(help, []string{
+		"",
+		"Build Screen:",
+		platform.ReplacePrimaryModifier("  F5 / Ctrl+R / r - Run surge build"),
+		"  Esc - Cancel running build / back",
+		"  ↑/↓ or j/k, PgUp/PgDn, g/G - Move through the log",
+		"  n / N - Next / previous diagnostic",
+		"  Enter - Open diagnostic location in workspace",
+	}...)
+	return help
+}
@@ -0,0 +1,148 @@
+package screens
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Защита от одновременных операций. Экран ведёт свою долгую операцию через
+// opGuard: каждая новая получает следующий токен, сообщение о завершении
+// несёт токен своей операции, и результаты сменённых или отменённых операций
+// отбрасываются. Операции, меняющие файлы проекта (применение фиксов,
+// форматирование проекта), дополнительно занимают общий для экранов
+// ProjectOps: пока одна идёт, другие экраны не начинают свою поверх неё.
+
+// opTokens выдаёт токены операций, сквозные для всех экранов: результат
+// операции экрана, пересозданного при смене проекта, не совпадёт с токеном
+// операции нового.
+var opTokens atomic.Int64
+
+// opGuard — идущая долгая операция экрана.
+type opGuard struct {
+	token   int
+	label   string // пусто — ничего не идёт; иначе "applying fixes" и т.п.
+	cancel  context.CancelFunc
+	mutates bool // операция меняет файлы проекта
+	project int  // токен в ProjectOps
+}
+
+// begin начинает операцию label вместо текущей (та отменяется, её результат
+// будет отброшен) и возвращает её контекст и токен.
+func (g *opGuard) begin(ops *ProjectOps, label string) (context.Context, int) {
+	g.drop(ops)
+	ctx, cancel := context.WithCancel(context.Background())
+	g.token = int(opTokens.Add(1))
+	g.label, g.cancel = label, cancel
+	return ctx, g.token
+}
+
+// beginProject начинает операцию, меняющую файлы проекта, и занимает под неё
+// ops с описанием label+where. Если реестр занят, ничего не начинается:
+// other — описание идущей операции.
+func (g *opGuard) beginProject(ops *ProjectOps, label, where string) (ctx context.Context, token int, other string) {
+	project, other := ops.Begin(label + where)
+	if other != "" {
+		return nil, 0, other
+	}
+	ctx, token = g.begin(ops, label)
+	g.mutates, g.project = true, project
+	return ctx, token, ""
+}
+
+// busy сообщает, что операция идёт.
+func (g *opGuard) busy() bool {
+	return g.label != ""
+}
+
+// mutating сообщает, что идёт операция, меняющая файлы проекта.
+func (g *opGuard) mutating() bool {
+	return g.busy() && g.mutates
+}
+
+// finish завершает операцию token; false — сообщение от сменённой или уже
+// завершённой операции, его нужно отбросить.
+func (g *opGuard) finish(ops *ProjectOps, token int) bool {
+	if !g.busy() || token != g.token {
+		return false
+	}
+	g.interrupt() // освобождает контекст завершившейся операции
+	ops.End(g.project)
+	g.label, g.cancel, g.mutates, g.project = "", nil, false, 0
+	return true
+}
+
+// interrupt отменяет контекст операции, оставляя её текущей: сообщение
+// о завершении (с context.Canceled) ещё будет принято.
+func (g *opGuard) interrupt() {
+	if g.cancel != nil {
+		g.cancel()
+	}
+}
+
+// drop отменяет операцию и забывает её: её сообщение будет отброшено.
+func (g *opGuard) drop(ops *ProjectOps) {
+	g.interrupt()
+	g.finish(ops, g.token)
+}
+
+// busyStatus — строка статуса для отклонённой попытки начать ещё одну операцию.
+func (g *opGuard) busyStatus(cancellable bool) string {
+	if cancellable {
+		return "Busy: " + g.label + " (Esc to cancel)"
+	}
+	return "Busy: " + g.label
+}
+
+// ProjectOps — общий реестр операций, меняющих файлы проекта. Одновременно
+// идёт не больше одной; App создаёт реестр и раздаёт экранам. Методы
+// безопасны для nil: без реестра проверки ничего не запрещают.
+type ProjectOps struct {
+	mu    sync.Mutex
+	next  int
+	token int
+	label string
+}
+
+// NewProjectOps создаёт пустой реестр.
+func NewProjectOps() *ProjectOps {
+	return &ProjectOps{}
+}
+
+// Begin занимает реестр операцией label ("applying fixes in Fix Mode") и
+// возвращает её токен. Если идёт другая операция, возвращает 0 и её описание.
+func (o *ProjectOps) Begin(label string) (int, string) {
+	if o == nil {
+		return 0, ""
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.label != "" {
+		return 0, o.label
+	}
+	o.next++
+	o.token, o.label = o.next, label
+	return o.token, ""
+}
+
+// End освобождает реестр, если token — идущая операция.
+func (o *ProjectOps) End(token int) {
+	if o == nil || token == 0 {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if token == o.token {
+		o.token, o.label = 0, ""
+	}
+}
+
+// Busy возвращает описание идущей операции или пустую строку.
+func (o *ProjectOps) Busy() string {
+	if o == nil {
+		return ""
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.label
+}
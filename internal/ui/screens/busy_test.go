@@ -0,0 +1,199 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"surge-tui/internal/core/surge/testsupport"
+)
+
+// Результат сменённой операции отбрасывается и не завершает текущую.
+func TestOpGuardDropsStaleToken(t *testing.T) {
+	ops := NewProjectOps()
+	var g opGuard
+	firstCtx, first := g.begin(ops, "loading")
+	secondCtx, second := g.begin(ops, "reloading")
+	if first == second {
+		t.Fatalf("both operations got token %d", first)
+	}
+	if firstCtx.Err() == nil {
+		t.Error("replaced operation was not cancelled")
+	}
+	if g.finish(ops, first) {
+		t.Error("stale token finished the current operation")
+	}
+	if !g.busy() || g.label != "reloading" || secondCtx.Err() != nil {
+		t.Errorf("current operation disturbed: busy=%v label=%q err=%v", g.busy(), g.label, secondCtx.Err())
+	}
+	if !g.finish(ops, second) {
+		t.Error("current token was not accepted")
+	}
+	if g.finish(ops, second) {
+		t.Error("token accepted twice")
+	}
+	if g.busy() {
+		t.Error("guard still busy after finish")
+	}
+}
+
+// Прерванная операция остаётся текущей: её сообщение ещё принимается.
+func TestOpGuardInterruptKeepsToken(t *testing.T) {
+	var g opGuard
+	ctx, token := g.begin(nil, "applying fixes")
+	g.interrupt()
+	if ctx.Err() == nil {
+		t.Error("interrupt did not cancel the context")
+	}
+	if !g.finish(nil, token) {
+		t.Error("interrupted operation's result was dropped")
+	}
+}
+
+// Две операции, меняющие файлы, не идут одновременно; сообщение сменённой
+// операции не освобождает реестр, занятый новой.
+func TestOpGuardProjectOpsOverlap(t *testing.T) {
+	ops := NewProjectOps()
+	var fixes, format opGuard
+
+	_, fixToken, other := fixes.beginProject(ops, "applying fixes", " in Fix Mode")
+	if other != "" {
+		t.Fatalf("first operation refused: %q", other)
+	}
+	if !fixes.mutating() {
+		t.Error("project operation is not marked as mutating")
+	}
+	if _, _, other := format.beginProject(ops, "formatting the project", ""); other != "applying fixes in Fix Mode" {
+		t.Fatalf("overlapping operation: other = %q", other)
+	}
+	if format.busy() {
+		t.Error("refused operation left the guard busy")
+	}
+
+	// новая операция экрана сменяет прежнюю и освобождает реестр
+	_, reload := fixes.begin(ops, "loading")
+	if ops.Busy() != "" {
+		t.Errorf("replaced project operation still holds ops: %q", ops.Busy())
+	}
+	if fixes.mutating() {
+		t.Error("plain operation inherited the mutating flag")
+	}
+	if _, formatToken, other := format.beginProject(ops, "formatting the project", ""); other != "" {
+		t.Fatalf("ops not released: %q", other)
+	} else {
+		// опоздавший результат фиксов ничего не освобождает
+		if fixes.finish(ops, fixToken) {
+			t.Error("stale fix result accepted")
+		}
+		if ops.Busy() != "formatting the project" {
+			t.Errorf("stale result released ops: %q", ops.Busy())
+		}
+		if !format.finish(ops, formatToken) || ops.Busy() != "" {
+			t.Errorf("format finish did not release ops: %q", ops.Busy())
+		}
+	}
+	if !fixes.finish(ops, reload) {
+		t.Error("reload result dropped")
+	}
+}
+
+func TestProjectOpsNilAndStaleEnd(t *testing.T) {
+	var none *ProjectOps
+	if token, other := none.Begin("x"); token != 0 || other != "" {
+		t.Errorf("nil Begin = %d, %q", token, other)
+	}
+	none.End(1)
+	if none.Busy() != "" {
+		t.Error("nil ops busy")
+	}
+
+	ops := NewProjectOps()
+	first, _ := ops.Begin("first")
+	ops.End(first)
+	second, _ := ops.Begin("second")
+	ops.End(first) // повторное завершение прежней операции
+	if ops.Busy() != "second" {
+		t.Errorf("stale End released ops: %q", ops.Busy())
+	}
+	ops.End(second)
+	if ops.Busy() != "" {
+		t.Errorf("ops busy after End: %q", ops.Busy())
+	}
+}
+
+func TestProjectOpsSingleWinner(t *testing.T) {
+	ops := NewProjectOps()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	winners := 0
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, other := ops.Begin("op"); other == "" {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if winners != 1 {
+		t.Errorf("%d operations started at once", winners)
+	}
+}
+
+// Пока Fix Mode применяет фикс, форматирование проекта не начинается, и
+// наоборот.
+func TestFixModeAndFormatDoNotOverlap(t *testing.T) {
+	runner := testsupport.NewFakeRunner(nil)
+	fs, path := newFixModeFlow(t, runner)
+	dir := filepath.Dir(path)
+	if err := os.WriteFile(filepath.Join(dir, "surge.toml"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ops := NewProjectOps()
+	fs.SetProjectOps(ops)
+	ps := NewProjectScreenReal(dir)
+	ps.loading = false
+	ps.SetSurgeClient(runner)
+	ps.SetProjectOps(ops)
+
+	_, apply := fs.Update(keyMsg("a"))
+	if apply == nil || ops.Busy() == "" {
+		t.Fatalf("applying did not take ops (busy=%q)", ops.Busy())
+	}
+	if cmd := ps.FormatProject(); cmd != nil {
+		t.Fatal("format started while fixes were being applied")
+	}
+	if !statusShown(&ps.status, "Busy: "+ops.Busy()) {
+		t.Errorf("status history %q lacks the busy message", ps.status.recent())
+	}
+
+	_, reload := fs.Update(awaitMsg[fixAppliedMsg](t, apply))
+	fs.Update(awaitMsg[fixesLoadedMsg](t, reload))
+	if ops.Busy() != "" {
+		t.Fatalf("ops still busy after the fix: %q", ops.Busy())
+	}
+
+	format := ps.FormatProject()
+	if format == nil || ops.Busy() != "formatting the project" {
+		t.Fatalf("format did not start (busy=%q)", ops.Busy())
+	}
+	if _, cmd := fs.Update(keyMsg("a")); cmd != nil {
+		t.Error("fix applied while the project was being formatted")
+	}
+	if !statusShown(&fs.status, "Busy: formatting the project") {
+		t.Errorf("status history %q lacks the busy message", fs.status.recent())
+	}
+
+	ps.Update(awaitMsg[FormatDoneMsg](t, format))
+	if ops.Busy() != "" {
+		t.Errorf("ops still busy after formatting: %q", ops.Busy())
+	}
+	if got := runner.Formatted(); len(got) != 1 || !strings.HasSuffix(got[0], filepath.Base(dir)) {
+		t.Errorf("formatted = %v, want the project", got)
+	}
+}
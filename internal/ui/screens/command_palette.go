@@ -1,6 +1,7 @@
 package screens
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -16,14 +17,22 @@ type CommandEntry struct {
 	Context string
 	Enabled bool
 	RawKey  string
+
+	// Group — группа динамического пункта («tab», «mark», «recent»); пустая
+	// у команд реестра. Run выполняет динамический пункт вместо команды по ID.
+	Group string
+	Run   func() tea.Cmd
 }
 
-// CommandFetcher возвращает доступные команды.
+// CommandFetcher возвращает команды реестра и динамические пункты экранов,
+// собранные в момент открытия палитры.
 type CommandFetcher func() []CommandEntry
 
 // CommandExecuteMsg сообщает приложению, какую команду нужно выполнить.
+// Run задан у динамических пунктов, их нет в реестре.
 type CommandExecuteMsg struct {
-	ID string
+	ID  string
+	Run func() tea.Cmd
 }
 
 // CommandPaletteClosedMsg сигнал закрытия палитры без выбора.
@@ -42,7 +51,7 @@ type CommandPaletteScreen struct {
 
 func NewCommandPaletteScreen(fetch CommandFetcher) *CommandPaletteScreen {
 	ti := textinput.New()
-	ti.Placeholder = "Filter commands"
+	ti.Placeholder = "Filter commands, tabs, bookmarks, recent files"
 	ti.Focus()
 
 	return &CommandPaletteScreen{
@@ -90,7 +99,7 @@ func (ps *CommandPaletteScreen) Update(msg tea.Msg) (Screen, tea.Cmd) {
 			if ps.selected >= 0 && ps.selected < len(ps.filtered) {
 				entry := ps.filtered[ps.selected]
 				if entry.Enabled {
-					return ps, func() tea.Msg { return CommandExecuteMsg{ID: entry.ID} }
+					return ps, func() tea.Msg { return CommandExecuteMsg{ID: entry.ID, Run: entry.Run} }
 				}
 			}
 			return ps, nil
@@ -123,7 +132,9 @@ func (ps *CommandPaletteScreen) View() string {
 	if len(ps.filtered) == 0 {
 		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color(unselectedColor)).Render("No commands match filter"))
 	}
-	for i, entry := range ps.filtered {
+	start, end := ps.visibleRange()
+	for i := start; i < end; i++ {
+		entry := ps.filtered[i]
 		prefix := "  "
 		style := lipgloss.NewStyle().Foreground(lipgloss.Color(unselectedColor))
 		if !entry.Enabled {
@@ -151,6 +162,12 @@ func (ps *CommandPaletteScreen) View() string {
 	return lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Width(width).Render(content)
 }
 
+// Refresh перечитывает команды, не сбрасывая фильтр и выбор: например,
+// когда появился surge и зависящие от него команды стали доступны.
+func (ps *CommandPaletteScreen) Refresh() {
+	ps.refresh()
+}
+
 func (ps *CommandPaletteScreen) refresh() {
 	if ps.fetch == nil {
 		ps.entries = nil
@@ -161,15 +178,34 @@ func (ps *CommandPaletteScreen) refresh() {
 	ps.applyFilter()
 }
 
+// applyFilter оставляет команды реестра, в которых есть подстрока фильтра,
+// и пункты экранов, нечётко совпавшие по заголовку. Команды всегда идут
+// первыми, чтобы поток вкладок и закладок их не заслонял; пункты экранов с
+// фильтром упорядочены по качеству совпадения.
 func (ps *CommandPaletteScreen) applyFilter() {
-	filter := strings.ToLower(strings.TrimSpace(ps.filter.Value()))
+	query := strings.TrimSpace(ps.filter.Value())
+	filter := strings.ToLower(query)
 	filtered := make([]CommandEntry, 0, len(ps.entries))
+	var dynamic []CommandEntry
+	scores := make(map[string]int)
 	for _, entry := range ps.entries {
+		if entry.Group != "" {
+			if score, ok := fuzzyScore(query, entry.Title); ok {
+				dynamic = append(dynamic, entry)
+				scores[entry.ID] = score
+			}
+			continue
+		}
 		if filter == "" || strings.Contains(strings.ToLower(entry.Title), filter) || strings.Contains(strings.ToLower(entry.Key), filter) || strings.Contains(strings.ToLower(entry.Context), filter) || strings.Contains(strings.ToLower(entry.RawKey), filter) {
 			filtered = append(filtered, entry)
 		}
 	}
-	ps.filtered = filtered
+	if query != "" {
+		sort.SliceStable(dynamic, func(i, j int) bool {
+			return scores[dynamic[i].ID] > scores[dynamic[j].ID]
+		})
+	}
+	ps.filtered = append(filtered, dynamic...)
 	if len(ps.filtered) == 0 {
 		ps.selected = -1
 	} else if ps.selected >= len(ps.filtered) {
@@ -178,3 +214,16 @@ func (ps *CommandPaletteScreen) applyFilter() {
 		ps.selected = 0
 	}
 }
+
+// visibleRange — окно списка, в котором виден выбранный пункт.
+func (ps *CommandPaletteScreen) visibleRange() (int, int) {
+	if ps.Height() <= 0 {
+		return 0, len(ps.filtered)
+	}
+	height := max(ps.Height()-8, 5)
+	start := 0
+	if ps.selected >= height {
+		start = ps.selected - height + 1
+	}
+	return start, min(start+height, len(ps.filtered))
+}
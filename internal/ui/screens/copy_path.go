@@ -0,0 +1,91 @@
+package screens
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CopyPathMsg просит приложение скопировать путь в буфер обмена
+// (Y в дереве или в списке диагностик).
+type CopyPathMsg struct {
+	Path     string
+	Relative bool
+}
+
+// PathCopiedMsg — результат копирования; Err != nil, если буфер обмена недоступен.
+type PathCopiedMsg struct {
+	Text string
+	Err  error
+}
+
+// CopyPath копирует path — абсолютный или относительно root — в буфер обмена.
+// Все команды копирования пути проходят через эту функцию.
+func CopyPath(path, root string, relative bool) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	text := path
+	if abs, err := filepath.Abs(path); err == nil {
+		text = abs
+	}
+	if relative && root != "" {
+		if rel, err := filepath.Rel(root, text); err == nil && !strings.HasPrefix(rel, "..") {
+			text = rel
+		}
+	}
+	return func() tea.Msg {
+		return PathCopiedMsg{Text: text, Err: clipboard.WriteAll(text)}
+	}
+}
+
+// PathCopiedStatus формирует строку статуса; без буфера обмена путь
+// показывается целиком, чтобы его можно было скопировать вручную.
+func PathCopiedStatus(msg PathCopiedMsg) string {
+	if msg.Err != nil {
+		return "Clipboard unavailable, copy manually: " + msg.Text
+	}
+	return "Copied " + msg.Text
+}
+
+// SelectedPath возвращает путь под курсором: выбранный узел дерева или файл
+// активной вкладки, смотря где фокус. У scratch-буфера пути нет.
+func (ps *ProjectScreenReal) SelectedPath() string {
+	if ps.focusedPanel == EditorPanel {
+		if tab := ps.activeEditorTab(); tab != nil && !tab.scratch {
+			return tab.path
+		}
+		return ""
+	}
+	if ps.fileTree != nil {
+		if node := ps.fileTree.GetSelected(); node != nil {
+			return node.Path
+		}
+	}
+	return ""
+}
+
+// SelectedPath возвращает файл выбранной диагностики.
+func (ds *DiagnosticsScreen) SelectedPath() string {
+	if entry, ok := ds.selectedEntry(); ok {
+		return entry.AbsPath
+	}
+	return ""
+}
+
+// Notify показывает сообщение в строке статуса экрана.
+func (ds *DiagnosticsScreen) Notify(msg string) {
+	ds.setStatus(msg)
+}
+
+// ActiveFile возвращает файл активной вкладки и строку курсора (с 1)
+// независимо от фокуса; у scratch-буфера и без вкладок — пустой путь.
+func (ps *ProjectScreenReal) ActiveFile() (string, int) {
+	tab := ps.activeEditorTab()
+	if tab == nil || tab.scratch {
+		return "", 0
+	}
+	return tab.path, tab.cursor.Line + 1
+}
@@ -0,0 +1,88 @@
+package screens
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"surge-tui/internal/fs"
+)
+
+// Экран диагностики и Fix Mode помнят, когда и при каком состоянии файлов
+// получен их последний успешный результат. При возврате на экран результат
+// показывается сразу, а surge запускается заново, только если результат
+// старше surge.cache_seconds или .sg файлы и surge.toml с тех пор менялись.
+// F5 / Ctrl+R запускают surge в любом случае.
+
+// projectStamp — отпечаток файлов, от которых зависит результат surge diag.
+type projectStamp = fs.ProjectStamp
+
+// diagCache — время и отпечаток файлов последнего успешного запуска.
+type diagCache struct {
+	maxAge time.Duration // 0 — результат не переиспользуется
+	at     time.Time     // начало запуска; нулевое — результата нет
+	stamp  projectStamp  // отпечаток на начало запуска
+}
+
+// fresh сообщает, что результат есть и ещё не устарел по времени.
+func (c *diagCache) fresh() bool {
+	return c.maxAge > 0 && !c.at.IsZero() && time.Since(c.at) < c.maxAge
+}
+
+func (c *diagCache) store(at time.Time, stamp projectStamp) {
+	c.at, c.stamp = at, stamp
+}
+
+func (c *diagCache) reset() {
+	c.at, c.stamp = time.Time{}, projectStamp{}
+}
+
+// stampProject снимает отпечаток .sg файлов и surge.toml проекта.
+func stampProject(projectPath string) projectStamp {
+	return fs.StampProject(projectPath, isDiagWatchTarget)
+}
+
+// diagFreshnessMsg — отпечаток файлов, снятый при возврате на экран.
+type diagFreshnessMsg struct {
+	run   int
+	stamp projectStamp
+}
+
+// SetCacheMaxAge задаёт, сколько прежний результат показывается без перезапуска.
+func (ds *DiagnosticsScreen) SetCacheMaxAge(age time.Duration) {
+	ds.cache.maxAge = age
+}
+
+// refreshIfStale оставляет на экране свежий результат и в фоне проверяет,
+// не менялись ли файлы; устаревший или неудачный запуск повторяется сразу.
+func (ds *DiagnosticsScreen) refreshIfStale() tea.Cmd {
+	if ds.err != nil || !ds.cache.fresh() {
+		return ds.runDiagnostics()
+	}
+	run, projectPath := ds.runID, ds.projectPath
+	return func() tea.Msg {
+		return diagFreshnessMsg{run: run, stamp: stampProject(projectPath)}
+	}
+}
+
+func (ds *DiagnosticsScreen) handleFreshness(msg diagFreshnessMsg) tea.Cmd {
+	if msg.run != ds.runID || ds.running {
+		return nil // за это время запуск уже начался
+	}
+	if !msg.stamp.Equal(ds.cache.stamp) {
+		if !ds.adoptStamp {
+			return ds.runDiagnostics()
+		}
+		// файлы поменяли фиксы Fix Mode, их диагностики уже помечены исправленными
+		ds.cache.stamp = msg.stamp
+	}
+	ds.adoptStamp = false
+	ds.pendingIssue = IssueRef{}
+	shown := fmt.Sprintf("Showing results from %s", ds.cache.at.Format("15:04:05"))
+	if n := len(ds.resolved); n > 0 {
+		shown += fmt.Sprintf(", %s fixed since", plural(n, "diagnostic"))
+	}
+	ds.setStatus(shown + " (F5 to re-run)")
+	return nil
+}
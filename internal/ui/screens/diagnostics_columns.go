@@ -0,0 +1,120 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"surge-tui/internal/ui/components"
+)
+
+// Ширины колонок таблицы диагностик подбираются по данным: код и место берут
+// 95-й перцентиль длины по текущему набору результатов (одна длинная
+// строка не раздувает колонку для всех), сообщение — остаток ширины.
+// `<` / `>` сужают и расширяют сообщение за счёт колонки места; сдвиг
+// действует до конца сессии и переживает перезапуски и смену размера окна.
+
+const (
+	diagSeverityWidth   = 8
+	diagColumnGaps      = 6 // по два пробела между четырьмя колонками
+	diagCodeMinWidth    = 4 // "CODE"
+	diagCodeMaxWidth    = 24
+	diagLocationMin     = 12
+	diagMessageMin      = 16
+	diagColumnStep      = 4 // шаг ручной подстройки
+	diagWidthPercentile = 0.95
+)
+
+// diagColumns — ширины колонок для текущей ширины таблицы.
+type diagColumns struct {
+	code     int
+	message  int
+	location int
+}
+
+// measureColumns заново подбирает ширины кода и места по ds.all.
+func (ds *DiagnosticsScreen) measureColumns() {
+	codes := make([]int, 0, len(ds.all))
+	locations := make([]int, 0, len(ds.all))
+	for _, entry := range ds.all {
+		codes = append(codes, max(utf8.RuneCountInString(entry.Code), 1))
+		locations = append(locations, utf8.RuneCountInString(diagLocation(entry)))
+	}
+	ds.codeWidth = percentile(codes, diagWidthPercentile)
+	ds.locationWidth = percentile(locations, diagWidthPercentile)
+}
+
+// percentile возвращает значение p-го перцентиля; values сортируется.
+func percentile(values []int, p float64) int {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Ints(values)
+	index := int(float64(len(values))*p+0.5) - 1
+	return values[clampInt(index, 0, len(values)-1)]
+}
+
+func diagLocation(entry DiagnosticEntry) string {
+	return fmt.Sprintf("%s:%d:%d", entry.File, entry.Line, entry.Column)
+}
+
+// columns раскладывает ширину width по колонкам с учётом ручного сдвига.
+func (ds *DiagnosticsScreen) columns(width int) diagColumns {
+	cols := diagColumns{
+		code:     clampInt(ds.codeWidth, diagCodeMinWidth, diagCodeMaxWidth),
+		location: max(ds.locationWidth, diagLocationMin),
+	}
+	rest := width - diagSeverityWidth - diagColumnGaps - cols.code
+	cols.message = rest - cols.location
+	if cols.message < diagMessageMin {
+		// узкое окно: место обрезается раньше сообщения
+		cols.location = max(rest-diagMessageMin, diagLocationMin)
+		cols.message = max(rest-cols.location, diagMessageMin)
+	}
+	shift := clampInt(ds.messageShift, diagMessageMin-cols.message, cols.location-diagLocationMin)
+	cols.message += shift
+	cols.location -= shift
+	return cols
+}
+
+// resizeMessageColumn расширяет (delta > 0) или сужает колонку сообщения.
+func (ds *DiagnosticsScreen) resizeMessageColumn(delta int) {
+	width := ds.tableWidth()
+	before := ds.columns(width).message
+	// сдвиг сверх возможного при текущей ширине не копится
+	ds.messageShift = ds.effectiveShift(width) + delta
+	after := ds.columns(width).message
+	if after == before {
+		ds.messageShift -= delta
+		ds.setStatus("Message column is at its limit")
+		return
+	}
+	ds.setStatus(fmt.Sprintf("Message column: %d", after))
+}
+
+// tableWidth — ширина строк таблицы без полосы прокрутки.
+func (ds *DiagnosticsScreen) tableWidth() int {
+	width := ds.Width()
+	if width <= 0 {
+		width = 80
+	}
+	if components.ScrollbarVisible(len(ds.rows), max(ds.listHeight(), 5)) {
+		width = max(width-1, 1)
+	}
+	return width
+}
+
+// effectiveShift — часть ручного сдвига, которая действует при ширине width.
+func (ds *DiagnosticsScreen) effectiveShift(width int) int {
+	shift := ds.messageShift
+	ds.messageShift = 0
+	auto := ds.columns(width).message
+	ds.messageShift = shift
+	return ds.columns(width).message - auto
+}
+
+// resetColumns возвращает автоматические ширины колонок.
+func (ds *DiagnosticsScreen) resetColumns() {
+	ds.messageShift = 0
+	ds.setStatus("Column widths fitted to results")
+}
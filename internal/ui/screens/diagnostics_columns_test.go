@@ -0,0 +1,151 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		want   int
+	}{
+		{"пусто", nil, 0},
+		{"одно значение", []int{7}, 7},
+		{"выброс не раздувает колонку", []int{5, 6, 5, 7, 6, 5, 6, 7, 5, 6, 5, 6, 7, 5, 6, 5, 6, 7, 5, 6, 90}, 7},
+		{"несортированный ввод", []int{9, 1, 5, 3}, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.values, diagWidthPercentile); got != tt.want {
+				t.Errorf("percentile = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticColumns(t *testing.T) {
+	tests := []struct {
+		name          string
+		code          int // измеренная ширина кода
+		location      int // измеренная ширина места
+		shift         int
+		width         int
+		want          diagColumns
+		fillsRowWidth bool
+	}{
+		{"по данным", 6, 20, 0, 100, diagColumns{code: 6, message: 60, location: 20}, true},
+		{"код не уже заголовка", 1, 20, 0, 100, diagColumns{code: diagCodeMinWidth, message: 62, location: 20}, true},
+		{"код не шире предела", 40, 20, 0, 100, diagColumns{code: diagCodeMaxWidth, message: 42, location: 20}, true},
+		{"место не уже минимума", 6, 3, 0, 100, diagColumns{code: 6, message: 68, location: diagLocationMin}, true},
+		{"узкое окно сужает место", 6, 40, 0, 60, diagColumns{code: 6, message: diagMessageMin, location: 24}, true},
+		{"очень узкое окно", 6, 40, 0, 30, diagColumns{code: 6, message: diagMessageMin, location: diagLocationMin}, false},
+		{"сообщение шире за счёт места", 6, 20, 4, 100, diagColumns{code: 6, message: 64, location: 16}, true},
+		{"сдвиг упирается в минимум места", 6, 20, 40, 100, diagColumns{code: 6, message: 68, location: diagLocationMin}, true},
+		{"сдвиг упирается в минимум сообщения", 6, 20, -100, 100, diagColumns{code: 6, message: diagMessageMin, location: 64}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := NewDiagnosticsScreen(t.TempDir(), nil)
+			ds.codeWidth, ds.locationWidth, ds.messageShift = tt.code, tt.location, tt.shift
+			got := ds.columns(tt.width)
+			if got != tt.want {
+				t.Errorf("columns(%d) = %+v, want %+v", tt.width, got, tt.want)
+			}
+			total := diagSeverityWidth + diagColumnGaps + got.code + got.message + got.location
+			if tt.fillsRowWidth && total != tt.width {
+				t.Errorf("columns take %d of %d", total, tt.width)
+			}
+		})
+	}
+}
+
+// Ручной сдвиг сверх возможного не копится: обратный шаг сразу сужает колонку.
+func TestResizeMessageColumnStopsAtLimit(t *testing.T) {
+	ds := NewDiagnosticsScreen(t.TempDir(), nil)
+	ds.SetSize(100, 30)
+	ds.codeWidth, ds.locationWidth = 6, 20
+	width := ds.tableWidth()
+	before := ds.columns(width).message
+	for i := 0; i < 10; i++ {
+		ds.resizeMessageColumn(diagColumnStep)
+	}
+	widest := ds.columns(width).message
+	if widest <= before || ds.columns(width).location != diagLocationMin {
+		t.Fatalf("message %d → %d, location %d", before, widest, ds.columns(width).location)
+	}
+	ds.resizeMessageColumn(-diagColumnStep)
+	if got := ds.columns(width).message; got != widest-diagColumnStep {
+		t.Errorf("narrowing after the limit: message %d, want %d", got, widest-diagColumnStep)
+	}
+	ds.resetColumns()
+	if got := ds.columns(width).message; got != before {
+		t.Errorf("reset: message %d, want %d", got, before)
+	}
+}
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"truncated text", 8, "truncat…"},
+		{"ünïcödé text", 6, "ünïcö…"},
+		{"漢字のメッセージ", 7, "漢字の…"},
+		{"漢字", 3, "漢…"},
+		{"漢字", 2, "…"},
+		{"abc", 1, "…"},
+		{"abc", 0, ""},
+		{"abc", -3, ""},
+	}
+	for _, tt := range tests {
+		got := truncateString(tt.text, tt.width)
+		if got != tt.want {
+			t.Errorf("truncateString(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+		if w := lipgloss.Width(got); w > max(tt.width, 0) {
+			t.Errorf("truncateString(%q, %d) is %d columns wide", tt.text, tt.width, w)
+		}
+	}
+}
+
+// Строки таблицы не выходят за ширину экрана и не переносятся, в том числе
+// с широкими символами в сообщении и длинными путями.
+func TestDiagnosticsTableFitsWidth(t *testing.T) {
+	entries := []DiagnosticEntry{
+		{Severity: "error", Code: "E0001", Message: "undefined variable x", File: "main.sg", Line: 1, Column: 1},
+		{Severity: "warning", Code: "W-VERY-LONG-DIAGNOSTIC-CODE", Message: "未使用の変数があります。削除してください", File: "src/deeply/nested/module/file.sg", Line: 120, Column: 14},
+		{Severity: "info", Message: strings.Repeat("long message ", 20), File: "ünïcödé/päth.sg", Line: 3, Column: 9},
+	}
+	for _, width := range []int{50, 80, 120, 200} {
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			ds := NewDiagnosticsScreen(t.TempDir(), nil)
+			ds.SetSize(width, 30)
+			ds.setEntries(entries)
+			lines := strings.Split(ds.renderTableSection(), "\n")
+			for i, line := range lines {
+				if w := lipgloss.Width(line); w > width {
+					t.Errorf("line %d is %d columns wide (limit %d): %q", i, w, width, line)
+				}
+			}
+			for _, text := range []string{"undefined", "未使", "long mes"} {
+				count := 0
+				for _, line := range lines {
+					if strings.Contains(line, text) {
+						count++
+					}
+				}
+				if count != 1 {
+					t.Errorf("row with %q on %d lines", text, count)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/ui/components"
+)
+
+// handleDetailKey прокручивает панель деталей, пока она в фокусе. Если у
+// записи есть заметки с местом, ↑/↓ выбирают их, а Enter открывает место.
+func (ds *DiagnosticsScreen) handleDetailKey(key string) (bool, tea.Cmd) {
+	page := max(ds.detailHeight()-3, 1)
+	switch key {
+	case "up", "k", "K":
+		if !ds.moveDetailNote(-1) {
+			ds.scrollDetail(-1)
+		}
+	case "down", "j", "J":
+		if !ds.moveDetailNote(1) {
+			ds.scrollDetail(1)
+		}
+	case "pgup", "ctrl+u":
+		ds.scrollDetail(-page)
+	case "pgdown", "ctrl+d":
+		ds.scrollDetail(page)
+	case "home", "g":
+		ds.detailScroll = 0
+	case "enter":
+		note, ok := ds.selectedNote()
+		if !ok {
+			return false, nil
+		}
+		return true, func() tea.Msg {
+			return OpenLocationMsg{FilePath: note.AbsPath, Line: note.Line, Column: note.Column}
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// moveDetailNote переводит выбор на соседнюю заметку с местом. false —
+// у записи таких заметок нет (или они скрыты), и клавиша прокручивает панель.
+func (ds *DiagnosticsScreen) moveDetailNote(delta int) bool {
+	entry, ok := ds.selectedEntry()
+	if !ok || !ds.includeNotes {
+		return false
+	}
+	for i := ds.detailNote + delta; ; i += delta {
+		if i < 0 || i >= len(entry.Notes) {
+			break
+		}
+		if entry.Notes[i].HasLocation() {
+			ds.detailNote = i
+			return true
+		}
+	}
+	// на крайней заметке клавиша поглощается, чтобы выбор не пропадал
+	return slices.ContainsFunc(entry.Notes, DiagnosticNote.HasLocation)
+}
+
+func (ds *DiagnosticsScreen) selectedNote() (DiagnosticNote, bool) {
+	entry, ok := ds.selectedEntry()
+	if !ok || !ds.includeNotes || ds.detailNote < 0 || ds.detailNote >= len(entry.Notes) {
+		return DiagnosticNote{}, false
+	}
+	note := entry.Notes[ds.detailNote]
+	return note, note.HasLocation() // после перезапуска индекс может указать на другую заметку
+}
+
+// detailFooter рисует подвал панели деталей: маркер фокуса и подсказку.
+func (ds *DiagnosticsScreen) detailFooter(hint string, innerWidth int) string {
+	return ds.focus.Marker(ds.detailFocused) + " " + lipgloss.NewStyle().
+		Foreground(lipgloss.Color(diagSecondaryColor)).
+		Render(truncateString(hint, max(innerWidth-2, 1)))
+}
+
+// scrollDetail сдвигает панель деталей; верхняя граница проверяется при отрисовке.
+func (ds *DiagnosticsScreen) scrollDetail(delta int) {
+	ds.detailScroll = max(ds.detailScroll+delta, 0)
+}
+
+func (ds *DiagnosticsScreen) renderDetailSection() string {
+	width := ds.Width()
+	if width <= 0 {
+		width = 80
+	}
+
+	// рамка добавляет по колонке с каждой стороны сверх Width
+	style := ds.focus.Panel(ds.detailFocused).
+		Width(max(width-2, 1)).
+		Height(ds.detailHeight()).
+		Padding(0, 1)
+
+	maxContentLines := max(ds.detailHeight()-2, 1)
+	row, ok := ds.selectedRow()
+	innerWidth := max(width-4, 8)
+	// подвал с маркером фокуса есть во всех вариантах панели
+	tabHint := "Tab: focus details"
+	if ds.detailFocused {
+		tabHint = "Tab: back to list"
+	}
+	if !ok {
+		content := padLines([]string{"Select a diagnostic to see details."}, max(maxContentLines-1, 0))
+		content = append(content, ds.detailFooter(tabHint, innerWidth))
+		return style.Render(strings.Join(content, "\n"))
+	}
+	if row.isHeader() {
+		content := []string{
+			lipgloss.NewStyle().Bold(true).Render(truncateString(row.group, innerWidth)),
+			fmt.Sprintf("%s in this group (%d errors, %d warnings)", plural(row.count, "diagnostic"), row.errors, row.warnings),
+			"",
+			"Enter / ←→: expand or collapse • m: change grouping",
+		}
+		content = append(padLines(content, max(maxContentLines-1, 0)), ds.detailFooter(tabHint, innerWidth))
+		return style.Render(strings.Join(content, "\n"))
+	}
+
+	entry := ds.diagnostics[row.entry]
+
+	header := fmt.Sprintf("%s — %s:%d:%d",
+		strings.ToUpper(entry.Severity),
+		entry.File,
+		entry.Line,
+		entry.Column,
+	)
+	if entry.Code != "" {
+		header = fmt.Sprintf("%s [%s]", header, entry.Code)
+	}
+
+	bold := lipgloss.NewStyle().Bold(true)
+	var content []string
+	for _, line := range components.WrapText(header, innerWidth) {
+		content = append(content, bold.Render(line))
+	}
+	content = append(content, components.WrapText(entry.Message, innerWidth)...)
+
+	if entry.HasFixes {
+		content = append(content, lipgloss.NewStyle().
+			Foreground(lipgloss.Color(diagInfoColor)).
+			Render("🔧 Fixes available (open Fix Mode to apply)."))
+	}
+
+	selectedLine := -1
+	if ds.includeNotes && len(entry.Notes) > 0 {
+		content = append(content, bold.Render("Notes:"))
+		locStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor))
+		selStyle := lipgloss.NewStyle().
+			Background(lipgloss.Color(diagSelectedBg)).
+			Foreground(lipgloss.Color(diagSelectedFg))
+		for n, note := range entry.Notes {
+			// продолжение заметки выравнивается под текст после маркера
+			for i, line := range components.WrapText(note.Message, max(innerWidth-4, 4)) {
+				prefix := "    "
+				if i == 0 {
+					prefix = "  • "
+				}
+				content = append(content, prefix+line)
+			}
+			if !note.HasLocation() {
+				continue
+			}
+			loc := truncateString(fmt.Sprintf("→ %s:%d:%d", note.File, note.Line, note.Column), max(innerWidth-4, 4))
+			if ds.detailFocused && n == ds.detailNote {
+				selectedLine = len(content)
+				content = append(content, "    "+selStyle.Render(loc))
+			} else {
+				content = append(content, "    "+locStyle.Render(loc))
+			}
+		}
+	}
+
+	hint := "Enter: open in editor • F5: rerun diagnostics • n: toggle notes • Tab: focus details"
+	if ds.detailFocused {
+		hint = "↑/↓ PgUp/PgDn: scroll details • Tab: back to list"
+		if _, ok := ds.selectedNote(); ok {
+			hint = "↑/↓: select note • Enter: open note location • Tab: back to list"
+		}
+	}
+	footer := ds.detailFooter(hint, innerWidth)
+
+	// подвал фиксирован, прокручивается только содержимое
+	if selectedLine >= 0 {
+		height := max(maxContentLines-1, 1)
+		if selectedLine < ds.detailScroll {
+			ds.detailScroll = selectedLine
+		} else if selectedLine >= ds.detailScroll+height {
+			ds.detailScroll = selectedLine - height + 1
+		}
+	}
+	var visible []string
+	visible, ds.detailScroll = components.ScrollWindow(content, ds.detailScroll, max(maxContentLines-1, 0))
+	visible = padLines(visible, max(maxContentLines-1, 0))
+	visible = append(visible, footer)
+
+	return style.Render(strings.Join(visible, "\n"))
+}
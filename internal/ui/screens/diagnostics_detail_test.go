@@ -0,0 +1,95 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// noteDetailScreen показывает в панели деталей диагностику с заметкой,
+// у которой есть место; фокус в панели, заметка выбрана.
+func noteDetailScreen(t *testing.T, width int) *DiagnosticsScreen {
+	t.Helper()
+	ds := NewDiagnosticsScreen(t.TempDir(), nil)
+	ds.SetSize(width, 30)
+	ds.setEntries([]DiagnosticEntry{{
+		Severity: "error", Message: "x", File: "main.sg", Line: 4, Column: 2,
+		Notes: []DiagnosticNote{{
+			Message: "defined here",
+			File:    "src/very/long/path/to/module/definitions.sg",
+			AbsPath: "/project/src/very/long/path/to/module/definitions.sg",
+			Line:    120, Column: 7,
+		}},
+	}})
+	for i, row := range ds.rows {
+		if !row.isHeader() {
+			ds.selected = i
+			break
+		}
+	}
+	ds.detailFocused = true
+	ds.detailNote = 0
+	return ds
+}
+
+// column возвращает экранную колонку text в строке line или -1.
+func column(line, text string) int {
+	at := strings.Index(line, text)
+	if at < 0 {
+		return -1
+	}
+	return lipgloss.Width(line[:at])
+}
+
+func TestDetailNoteSubRows(t *testing.T) {
+	for _, width := range []int{30, 50, 120} {
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			ds := noteDetailScreen(t, width)
+			lines := strings.Split(ds.renderDetailSection(), "\n")
+
+			bullet, loc := -1, -1
+			for i, line := range lines {
+				if w := lipgloss.Width(line); w > width {
+					t.Errorf("line %d is %d columns wide (limit %d): %q", i, w, width, line)
+				}
+				if strings.Contains(line, "• defined here") {
+					bullet = i
+				}
+				if strings.Contains(line, "→ ") {
+					loc = i
+				}
+			}
+			if bullet < 0 || loc != bullet+1 {
+				t.Fatalf("note rows not found under each other (bullet %d, location %d):\n%s", bullet, loc, strings.Join(lines, "\n"))
+			}
+
+			// «Notes:» — на уровне текста диагностики, маркер заметки сдвинут
+			// на два символа, место — на четыре, под текстом заметки
+			text := -1
+			for _, line := range lines {
+				if c := column(line, "Notes:"); c >= 0 {
+					text = c
+				}
+			}
+			if text < 0 {
+				t.Fatalf("Notes: header not visible:\n%s", strings.Join(lines, "\n"))
+			}
+			if c := column(lines[bullet], "•"); c != text+2 {
+				t.Errorf("note bullet at column %d, want %d", c, text+2)
+			}
+			if c := column(lines[loc], "→"); c != text+4 {
+				t.Errorf("note location at column %d, want %d", c, text+4)
+			}
+
+			full := "→ src/very/long/path/to/module/definitions.sg:120:7"
+			if fits := text+4+lipgloss.Width(full) <= width-2; fits != strings.Contains(lines[loc], full) {
+				t.Errorf("location row %q: want it whole only when it fits", lines[loc])
+			}
+			if !strings.Contains(lines[loc], full) && !strings.Contains(lines[loc], "…") {
+				t.Errorf("truncated location row has no ellipsis: %q", lines[loc])
+			}
+		})
+	}
+}
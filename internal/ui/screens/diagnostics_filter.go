@@ -0,0 +1,215 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Фильтр экрана диагностики сужает ds.diagnostics, полный список хранится в ds.all.
+// Счётчики в шапке считаются по полному списку, выделение — по отфильтрованному.
+
+type diagFilter struct {
+	query       string // подстрока сообщения, кода или пути файла
+	hideError   bool
+	hideWarning bool
+	hideInfo    bool // info и note
+}
+
+func (f diagFilter) active() bool {
+	return f.query != "" || f.hideError || f.hideWarning || f.hideInfo
+}
+
+func (f diagFilter) matches(entry DiagnosticEntry) bool {
+	switch severityClass(entry.Severity) {
+	case "error":
+		if f.hideError {
+			return false
+		}
+	case "warning":
+		if f.hideWarning {
+			return false
+		}
+	default:
+		if f.hideInfo {
+			return false
+		}
+	}
+	if f.query == "" {
+		return true
+	}
+	query := strings.ToLower(f.query)
+	return strings.Contains(strings.ToLower(entry.Message), query) ||
+		strings.Contains(strings.ToLower(entry.Code), query) ||
+		strings.Contains(strings.ToLower(entry.File), query)
+}
+
+// describe перечисляет скрытые уровни и текст фильтра для шапки.
+func (f diagFilter) describe() string {
+	var hidden []string
+	if f.hideError {
+		hidden = append(hidden, "errors")
+	}
+	if f.hideWarning {
+		hidden = append(hidden, "warnings")
+	}
+	if f.hideInfo {
+		hidden = append(hidden, "info")
+	}
+	var parts []string
+	if len(hidden) > 0 {
+		parts = append(parts, "hidden: "+strings.Join(hidden, ", "))
+	}
+	if f.query != "" {
+		parts = append(parts, fmt.Sprintf("text: %q", f.query))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// severityClass сводит уровень к одной из групп фильтра: error, warning или info.
+func severityClass(severity string) string {
+	switch strings.ToLower(severity) {
+	case "error":
+		return "error"
+	case "warning":
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+func diagKey(entry DiagnosticEntry) string {
+	return fmt.Sprintf("%s:%d:%d:%s:%s", entry.AbsPath, entry.Line, entry.Column, entry.Code, entry.Message)
+}
+
+func newDiagFilterInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.Placeholder = "message, code or file"
+	return ti
+}
+
+// setEntries заменяет полный список, оставляя выделенной ту же диагностику, если она видна.
+func (ds *DiagnosticsScreen) setEntries(entries []DiagnosticEntry) {
+	keep := ds.currentKey()
+	ds.all = entries
+	ds.recountSeverities()
+	ds.measureColumns()
+	ds.refilter(keep)
+}
+
+func (ds *DiagnosticsScreen) currentKey() string {
+	row, ok := ds.selectedRow()
+	if !ok {
+		return ""
+	}
+	return ds.rowKey(row)
+}
+
+func (ds *DiagnosticsScreen) setFilter(next diagFilter) {
+	keep := ds.currentKey()
+	ds.filter = next
+	ds.refilter(keep)
+}
+
+// refilter пересобирает видимый список и ставит курсор на keep, если он виден.
+func (ds *DiagnosticsScreen) refilter(keep string) {
+	if !ds.filter.active() {
+		ds.diagnostics = ds.all
+	} else {
+		ds.diagnostics = make([]DiagnosticEntry, 0, len(ds.all))
+		for _, entry := range ds.all {
+			if ds.filter.matches(entry) {
+				ds.diagnostics = append(ds.diagnostics, entry)
+			}
+		}
+	}
+	ds.rebuildRows()
+	if !ds.selectRowByKey(keep) {
+		ds.setSelection(ds.selected)
+	}
+}
+
+// toggleSeverity скрывает или возвращает уровень: error, warning или info.
+func (ds *DiagnosticsScreen) toggleSeverity(class string) {
+	next := ds.filter
+	switch class {
+	case "error":
+		next.hideError = !next.hideError
+	case "warning":
+		next.hideWarning = !next.hideWarning
+	default:
+		next.hideInfo = !next.hideInfo
+	}
+	ds.setFilter(next)
+}
+
+func (ds *DiagnosticsScreen) startFilterInput() tea.Cmd {
+	ds.filtering = true
+	ds.filterInput.SetValue(ds.filter.query)
+	ds.filterInput.CursorEnd()
+	return ds.filterInput.Focus()
+}
+
+// handleFilterKey обрабатывает ввод в строке фильтра: таблица сужается по мере набора.
+func (ds *DiagnosticsScreen) handleFilterKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		ds.filtering = false
+		ds.filterInput.Blur()
+		return ds, nil
+	case tea.KeyEsc:
+		ds.cancelFilterInput()
+		return ds, nil
+	case tea.KeyUp:
+		ds.moveSelection(-1)
+		return ds, nil
+	case tea.KeyDown:
+		ds.moveSelection(1)
+		return ds, nil
+	}
+	var cmd tea.Cmd
+	ds.filterInput, cmd = ds.filterInput.Update(msg)
+	if value := strings.TrimSpace(ds.filterInput.Value()); value != ds.filter.query {
+		next := ds.filter
+		next.query = value
+		ds.setFilter(next)
+	}
+	return ds, cmd
+}
+
+func (ds *DiagnosticsScreen) cancelFilterInput() {
+	ds.filtering = false
+	ds.filterInput.Blur()
+	next := ds.filter
+	next.query = ""
+	ds.setFilter(next)
+}
+
+// HandleGlobalEsc закрывает строку фильтра или сбрасывает фильтр
+// вместо возврата на экран проекта.
+func (ds *DiagnosticsScreen) HandleGlobalEsc() (bool, tea.Cmd) {
+	if ds.running {
+		ds.cancelRunning()
+		return true, nil
+	}
+	if ds.filtering {
+		ds.cancelFilterInput()
+		return true, nil
+	}
+	if ds.filter.active() {
+		ds.setFilter(diagFilter{})
+		return true, nil
+	}
+	return false, nil
+}
+
+// filterNote возвращает "filtered: X/Y" с описанием фильтра или пустую строку.
+func (ds *DiagnosticsScreen) filterNote() string {
+	if !ds.filter.active() {
+		return ""
+	}
+	return fmt.Sprintf("filtered: %d/%d (%s)", len(ds.diagnostics), len(ds.all), ds.filter.describe())
+}
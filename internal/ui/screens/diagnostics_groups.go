@@ -0,0 +1,248 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Таблица диагностики выводится группами по файлу или коду: строка-заголовок
+// со счётчиками, под ней записи группы в исходном порядке (уровень, строка).
+// Выделение и прокрутка относятся к строкам ds.rows, а не к ds.diagnostics.
+
+type diagGroupMode int
+
+const (
+	diagGroupByFile diagGroupMode = iota
+	diagGroupByCode
+	diagGroupNone
+)
+
+func (m diagGroupMode) label() string {
+	switch m {
+	case diagGroupByFile:
+		return "file"
+	case diagGroupByCode:
+		return "code"
+	default:
+		return "none"
+	}
+}
+
+const diagNoCode = "(no code)"
+
+type diagRow struct {
+	group    string
+	entry    int // индекс в ds.diagnostics, -1 для заголовка группы
+	count    int // для заголовка: записей в группе
+	errors   int
+	warnings int
+}
+
+func (r diagRow) isHeader() bool {
+	return r.entry < 0
+}
+
+func (ds *DiagnosticsScreen) groupOf(entry DiagnosticEntry) string {
+	switch ds.groupMode {
+	case diagGroupByCode:
+		if entry.Code == "" {
+			return diagNoCode
+		}
+		return entry.Code
+	case diagGroupByFile:
+		return entry.File
+	default:
+		return ""
+	}
+}
+
+func (ds *DiagnosticsScreen) collapseKey(group string) string {
+	return ds.groupMode.label() + ":" + group
+}
+
+// rebuildRows пересобирает строки таблицы из отфильтрованного списка.
+func (ds *DiagnosticsScreen) rebuildRows() {
+	ds.rows = ds.rows[:0]
+	if ds.groupMode == diagGroupNone {
+		for i := range ds.diagnostics {
+			ds.rows = append(ds.rows, diagRow{entry: i})
+		}
+		return
+	}
+
+	members := make(map[string][]int)
+	var groups []string
+	for i, entry := range ds.diagnostics {
+		group := ds.groupOf(entry)
+		if _, ok := members[group]; !ok {
+			groups = append(groups, group)
+		}
+		members[group] = append(members[group], i)
+	}
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		header := diagRow{group: group, entry: -1, count: len(members[group])}
+		for _, idx := range members[group] {
+			switch severityClass(ds.diagnostics[idx].Severity) {
+			case "error":
+				header.errors++
+			case "warning":
+				header.warnings++
+			}
+		}
+		ds.rows = append(ds.rows, header)
+		if ds.collapsed[ds.collapseKey(group)] {
+			continue
+		}
+		for _, idx := range members[group] {
+			ds.rows = append(ds.rows, diagRow{group: group, entry: idx})
+		}
+	}
+}
+
+func (ds *DiagnosticsScreen) selectedRow() (diagRow, bool) {
+	if ds.selected < 0 || ds.selected >= len(ds.rows) {
+		return diagRow{}, false
+	}
+	return ds.rows[ds.selected], true
+}
+
+// selectedEntry возвращает диагностику под курсором; на заголовке группы — false.
+func (ds *DiagnosticsScreen) selectedEntry() (DiagnosticEntry, bool) {
+	row, ok := ds.selectedRow()
+	if !ok || row.isHeader() {
+		return DiagnosticEntry{}, false
+	}
+	return ds.diagnostics[row.entry], true
+}
+
+// rowKey — устойчивый ключ строки для восстановления позиции после перестроения.
+func (ds *DiagnosticsScreen) rowKey(row diagRow) string {
+	if row.isHeader() {
+		return "group::" + ds.collapseKey(row.group)
+	}
+	return diagKey(ds.diagnostics[row.entry])
+}
+
+// selectRowByKey ставит курсор на строку с ключом key.
+func (ds *DiagnosticsScreen) selectRowByKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, row := range ds.rows {
+		if ds.rowKey(row) == key {
+			ds.setSelection(i)
+			return true
+		}
+	}
+	return false
+}
+
+// setGroupCollapsed сворачивает или разворачивает группу, оставляя курсор на её заголовке.
+func (ds *DiagnosticsScreen) setGroupCollapsed(group string, collapsed bool) {
+	key := ds.collapseKey(group)
+	if collapsed {
+		ds.collapsed[key] = true
+	} else {
+		delete(ds.collapsed, key)
+	}
+	ds.rebuildRows()
+	ds.selectRowByKey(ds.rowKey(diagRow{group: group, entry: -1}))
+}
+
+// handleGroupKey обрабатывает Enter на заголовке и ←/→ на строках групп.
+func (ds *DiagnosticsScreen) handleGroupKey(key string) bool {
+	row, ok := ds.selectedRow()
+	if !ok || ds.groupMode == diagGroupNone {
+		return false
+	}
+	collapsed := ds.collapsed[ds.collapseKey(row.group)]
+	switch key {
+	case "enter":
+		if !row.isHeader() {
+			return false
+		}
+		ds.setGroupCollapsed(row.group, !collapsed)
+	case "left":
+		if !row.isHeader() || !collapsed {
+			ds.setGroupCollapsed(row.group, true)
+		}
+	case "right":
+		if collapsed {
+			ds.setGroupCollapsed(row.group, false)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// cycleGroupMode переключает группировку: файл → код → без групп.
+// Режим хранится в экране и живёт до конца сессии.
+func (ds *DiagnosticsScreen) cycleGroupMode() {
+	var keep string
+	if entry, ok := ds.selectedEntry(); ok {
+		keep = diagKey(entry)
+	}
+	ds.groupMode = (ds.groupMode + 1) % (diagGroupNone + 1)
+	ds.rebuildRows()
+	if !ds.selectRowByKey(keep) {
+		ds.setSelection(0)
+	}
+	ds.setStatus("Grouping: " + ds.groupMode.label())
+}
+
+// groupHeaderText форматирует заголовок группы: "▾ src/main.sg (5) — 2 errors, 1 warning".
+func (ds *DiagnosticsScreen) groupHeaderText(row diagRow) string {
+	arrow := "▾ "
+	if ds.collapsed[ds.collapseKey(row.group)] {
+		arrow = "▸ "
+	}
+	text := fmt.Sprintf("%s%s (%d)", arrow, row.group, row.count)
+	var parts []string
+	if row.errors > 0 {
+		parts = append(parts, plural(row.errors, "error"))
+	}
+	if row.warnings > 0 {
+		parts = append(parts, plural(row.warnings, "warning"))
+	}
+	if len(parts) > 0 {
+		text += " — " + strings.Join(parts, ", ")
+	}
+	return text
+}
+
+func (ds *DiagnosticsScreen) moveSelection(delta int) {
+	ds.setSelection(ds.selected + delta)
+}
+
+func (ds *DiagnosticsScreen) setSelection(index int) {
+	if len(ds.rows) == 0 {
+		ds.selected = 0
+		ds.scroll = 0
+		return
+	}
+	index = clampInt(index, 0, len(ds.rows)-1)
+	if index != ds.selected {
+		ds.detailScroll = 0
+		ds.detailNote = -1
+	}
+	ds.selected = index
+	ds.ensureSelectionVisible()
+}
+
+func (ds *DiagnosticsScreen) ensureSelectionVisible() {
+	visible := ds.listHeight()
+	if visible <= 0 {
+		ds.scroll = 0
+		return
+	}
+	if ds.selected < ds.scroll {
+		ds.scroll = ds.selected
+	} else if ds.selected >= ds.scroll+visible {
+		ds.scroll = ds.selected - visible + 1
+	}
+	ds.scroll = clampInt(ds.scroll, 0, max(len(ds.rows)-visible, 0))
+}
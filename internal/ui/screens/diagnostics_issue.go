@@ -0,0 +1,94 @@
+package screens
+
+import "fmt"
+
+// CurrentIssue возвращает проблему записи под курсором.
+func (ds *DiagnosticsScreen) CurrentIssue() (IssueRef, bool) {
+	entry, ok := ds.selectedEntry()
+	if !ok {
+		return IssueRef{}, false
+	}
+	return issueOfDiagnostic(entry), true
+}
+
+// FocusIssue выбирает запись проблемы ref. Если при входе на экран
+// диагностика запустится заново, запись выбирается ещё раз, когда придут
+// результаты.
+func (ds *DiagnosticsScreen) FocusIssue(ref IssueRef) {
+	if ref.IsZero() {
+		return
+	}
+	ds.pendingIssue = ref
+	if current, ok := ds.CurrentIssue(); ok && current.Matches(ref) {
+		return
+	}
+	ds.focusIssue(ref)
+}
+
+// focusIssue ставит курсор на запись проблемы ref или, если её нет, на
+// первую запись с тем же кодом в том же файле; свёрнутая группа
+// разворачивается.
+func (ds *DiagnosticsScreen) focusIssue(ref IssueRef) bool {
+	exact, near := -1, -1
+	for i, entry := range ds.diagnostics {
+		issue := issueOfDiagnostic(entry)
+		if issue.Matches(ref) {
+			exact = i
+			break
+		}
+		if near < 0 && issue.sameCode(ref) {
+			near = i
+		}
+	}
+	index := exact
+	if index < 0 {
+		index = near
+	}
+	if index < 0 {
+		return false
+	}
+	if ds.groupMode != diagGroupNone {
+		delete(ds.collapsed, ds.collapseKey(ds.groupOf(ds.diagnostics[index])))
+		ds.rebuildRows()
+	}
+	for i, row := range ds.rows {
+		if row.entry == index {
+			ds.setSelection(i)
+			return true
+		}
+	}
+	return false
+}
+
+// MarkResolved помечает диагностики, исправленные в Fix Mode, до следующего
+// запуска. Пока прежний результат свежий, изменения файлов этими фиксами не
+// заставляют перезапускать диагностику при возврате на экран.
+func (ds *DiagnosticsScreen) MarkResolved(issues []IssueRef) {
+	marked := 0
+	for _, issue := range issues {
+		for _, entry := range ds.all {
+			ref := issueOfDiagnostic(entry)
+			if !ref.Matches(issue) || ds.resolved[ref.key()] {
+				continue
+			}
+			if ds.resolved == nil {
+				ds.resolved = make(map[string]bool)
+			}
+			ds.resolved[ref.key()] = true
+			marked++
+			break
+		}
+	}
+	if marked == 0 {
+		return
+	}
+	if ds.cache.fresh() && !ds.running {
+		ds.adoptStamp = true
+	}
+	ds.setStatus(fmt.Sprintf("%s fixed in Fix Mode (F5 to re-run)", plural(marked, "diagnostic")))
+}
+
+// isResolved сообщает, что диагностика исправлена в Fix Mode после запуска.
+func (ds *DiagnosticsScreen) isResolved(entry DiagnosticEntry) bool {
+	return ds.resolved[issueOfDiagnostic(entry).key()]
+}
@@ -0,0 +1,59 @@
+package screens
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// diagnosticPathResolver кэширует нормализацию путей: один файл обычно даёт
+// много диагностик, а filepath.Join/Clean/Rel на каждую заметно тормозят
+// на больших ответах.
+type diagnosticPathResolver struct {
+	projectPath string
+	rootPrefix  string // очищенный корень проекта с разделителем на конце
+	cache       map[string]resolvedDiagnosticPath
+}
+
+type resolvedDiagnosticPath struct {
+	abs     string
+	display string
+}
+
+func newDiagnosticPathResolver(projectPath string) *diagnosticPathResolver {
+	r := &diagnosticPathResolver{
+		projectPath: projectPath,
+		cache:       make(map[string]resolvedDiagnosticPath),
+	}
+	if projectPath != "" {
+		r.rootPrefix = filepath.Clean(projectPath) + string(filepath.Separator)
+	}
+	return r
+}
+
+// resolve возвращает абсолютный путь и путь для отображения (относительно проекта).
+func (r *diagnosticPathResolver) resolve(filePath string) (string, string) {
+	if cached, ok := r.cache[filePath]; ok {
+		return cached.abs, cached.display
+	}
+
+	abs := filePath
+	if !filepath.IsAbs(abs) && r.projectPath != "" {
+		abs = filepath.Join(r.projectPath, filePath)
+	}
+	abs = filepath.Clean(abs)
+
+	display := filePath
+	if filepath.IsAbs(display) && r.projectPath != "" {
+		if r.rootPrefix != "" && strings.HasPrefix(abs, r.rootPrefix) {
+			// быстрый путь: файл внутри проекта, Rel не нужен
+			display = abs[len(r.rootPrefix):]
+		} else if rel, err := filepath.Rel(r.projectPath, abs); err == nil {
+			display = rel
+		} else {
+			display = filepath.Base(abs)
+		}
+	}
+
+	r.cache[filePath] = resolvedDiagnosticPath{abs: abs, display: display}
+	return abs, display
+}
@@ -0,0 +1,117 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	core "surge-tui/internal/core/surge"
+)
+
+// resolveUncached — нормализация пути без кэша, как до diagnosticPathResolver.
+func resolveUncached(projectPath, filePath string) (string, string) {
+	abs := filePath
+	if !filepath.IsAbs(abs) && projectPath != "" {
+		abs = filepath.Join(projectPath, filePath)
+	}
+	abs = filepath.Clean(abs)
+	display := filePath
+	if filepath.IsAbs(display) && projectPath != "" {
+		if rel, err := filepath.Rel(projectPath, abs); err == nil {
+			display = rel
+		} else {
+			display = filepath.Base(abs)
+		}
+	}
+	return abs, display
+}
+
+func TestDiagnosticPathResolverMatchesUncached(t *testing.T) {
+	projects := []string{"/work/proj", "/work/proj/", "/work/./proj", ""}
+	files := []string{
+		"main.sg",
+		"./src/../main.sg",
+		"/work/proj/src/a.sg",
+		"/work/proj/src/../b.sg",
+		"/work/proj",
+		"/work/proj-other/c.sg",
+		"/elsewhere/d.sg",
+		"../outside.sg",
+	}
+	for _, project := range projects {
+		r := newDiagnosticPathResolver(project)
+		for range 2 { // второй проход — из кэша
+			for _, file := range files {
+				abs, display := r.resolve(file)
+				wantAbs, wantDisplay := resolveUncached(project, file)
+				if abs != wantAbs || display != wantDisplay {
+					t.Errorf("project %q file %q: got (%q, %q), want (%q, %q)", project, file, abs, display, wantAbs, wantDisplay)
+				}
+			}
+		}
+	}
+}
+
+func TestNormalizeDiagnosticsPaths(t *testing.T) {
+	resp := syntheticDiagResponse("/work/proj", 3, 2)
+	for _, entry := range normalizeDiagnostics(resp, "/work/proj", false) {
+		if entry.AbsPath == "" || filepath.Join("/work/proj", entry.File) != entry.AbsPath {
+			t.Errorf("entry paths disagree: File %q AbsPath %q", entry.File, entry.AbsPath)
+		}
+	}
+}
+
+// syntheticDiagResponse строит пакетный ответ: files файлов по perFile диагностик,
+// половина путей абсолютные.
+func syntheticDiagResponse(projectPath string, files, perFile int) *core.DiagResponse {
+	resp := &core.DiagResponse{Batch: make(map[string]core.DiagnosticsOutput, files)}
+	for f := range files {
+		name := fmt.Sprintf("pkg%d/file%d.sg", f%50, f)
+		file := name
+		if f%2 == 0 {
+			file = filepath.Join(projectPath, name)
+		}
+		out := core.DiagnosticsOutput{Diagnostics: make([]core.DiagnosticJSON, perFile)}
+		for d := range perFile {
+			out.Diagnostics[d] = core.DiagnosticJSON{
+				Severity: "error",
+				Code:     "E001",
+				Message:  "synthetic",
+				Location: core.LocationJSON{File: file, StartLine: uint32(d + 1), StartCol: 1},
+			}
+		}
+		resp.Batch[name] = out
+	}
+	return resp
+}
+
+// 50 000 диагностик в 500 файлах: кэш против нормализации каждой диагностики.
+func BenchmarkResolveDiagnosticPaths(b *testing.B) {
+	resp := syntheticDiagResponse("/work/proj", 500, 100)
+	b.Run("cached", func(b *testing.B) {
+		for b.Loop() {
+			r := newDiagnosticPathResolver("/work/proj")
+			for _, out := range resp.Batch {
+				for _, diag := range out.Diagnostics {
+					r.resolve(diag.Location.File)
+				}
+			}
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		for b.Loop() {
+			for _, out := range resp.Batch {
+				for _, diag := range out.Diagnostics {
+					resolveUncached("/work/proj", diag.Location.File)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkNormalizeDiagnostics50k(b *testing.B) {
+	resp := syntheticDiagResponse("/work/proj", 500, 100)
+	for b.Loop() {
+		normalizeDiagnostics(resp, "/work/proj", false)
+	}
+}
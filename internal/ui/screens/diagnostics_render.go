@@ -7,16 +7,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
-)
-
-const (
-	diagHeaderColor    = "#38BDF8"
-	diagErrorColor     = "#F87171"
-	diagWarningColor   = "#FBBF24"
-	diagInfoColor      = "#A5B4FC"
-	diagSelectedBg     = "#312E81"
-	diagSelectedFg     = "#F8FAFC"
-	diagSecondaryColor = "#94A3B8"
+	"surge-tui/internal/ui/components"
 )
 
 func (ds *DiagnosticsScreen) render() string {
@@ -46,20 +37,38 @@ func (ds *DiagnosticsScreen) renderHeaderSection() string {
 			projectPath = truncatePath(projectPath, w)
 		}
 	}
+	location := "Project: " + projectPath
+	if ds.scope != "" {
+		location = "Directory: " + scopeLabel(ds.projectPath, ds.scope) + "  (P: whole project)"
+	}
 	project := lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor)).
-		Render("Project: " + projectPath)
+		Render(location)
 
-	status := ds.status
+	status := ds.status.line()
 	if ds.running {
-		status = "Running diagnostics…"
+		status = ds.runningStatus()
+	}
+	if note := ds.watchNote(); note != "" {
+		status += "  •  " + note
 	}
 	statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor))
 	if ds.err != nil {
 		statusStyle = statusStyle.Foreground(lipgloss.Color(diagErrorColor))
+	} else if ds.running && ds.stallNote != "" {
+		statusStyle = statusStyle.Foreground(lipgloss.Color(diagWarningColor))
 	}
 	statusLine := statusStyle.Render(status)
+	if ds.filtering {
+		statusLine = ds.filterInput.View()
+	}
 
 	counts := ds.summaryCounts()
+	if note := ds.filterNote(); note != "" {
+		counts += "  •  " + note
+	}
+	if len(ds.rows) > 0 {
+		counts += "  •  " + components.ScrollIndicator(ds.scroll, ds.listHeight(), len(ds.rows))
+	}
 	countLine := lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor)).
 		Render(counts)
 
@@ -97,61 +106,62 @@ func (ds *DiagnosticsScreen) renderTableSection() string {
 
 	if len(ds.diagnostics) == 0 {
 		msg := "No diagnostics to display."
+		if len(ds.all) > 0 {
+			msg = "No diagnostics match the filter (Esc to clear)."
+		}
 		if ds.err != nil {
-			msg = fmt.Sprintf("Diagnostics failed: %v", ds.err)
+			msg = diagFailure(ds.err)
 		} else if ds.running {
 			msg = "Collecting diagnostics…"
 		}
 		return lipgloss.NewStyle().
 			Width(width).
-			Height(height).
+			Height(height + 1).
 			Foreground(lipgloss.Color(diagSecondaryColor)).
 			Render(msg)
 	}
 
-	severityWidth := 8
-	codeWidth := 12
-	locationMinWidth := 24
-	available := width - severityWidth - codeWidth - locationMinWidth - 6
-	if available < 16 {
-		available = 16
-	}
-	messageWidth := available
-	locationWidth := width - severityWidth - codeWidth - messageWidth - 6
-	if locationWidth < locationMinWidth {
-		locationWidth = locationMinWidth
-		messageWidth = width - severityWidth - codeWidth - locationWidth - 6
-		if messageWidth < 16 {
-			messageWidth = 16
-		}
+	// последняя колонка отведена под полосу прокрутки, если список не помещается
+	showBar := components.ScrollbarVisible(len(ds.rows), height)
+	if showBar {
+		width = max(width-1, 1)
 	}
 
-	start := ds.scroll
-	end := min(ds.scroll+height, len(ds.diagnostics))
+	cols := ds.columns(width)
+
+	start, end := components.VisibleWindow(ds.scroll, height, len(ds.rows))
 
 	var rows []string
 	for idx := start; idx < end; idx++ {
-		entry := ds.diagnostics[idx]
+		rowStyle := lipgloss.NewStyle().Width(width)
+		if idx == ds.selected {
+			rowStyle = rowStyle.Background(lipgloss.Color(diagSelectedBg)).Foreground(lipgloss.Color(diagSelectedFg))
+		}
+		if ds.rows[idx].isHeader() {
+			rows = append(rows, rowStyle.Bold(true).Render(truncateString(ds.groupHeaderText(ds.rows[idx]), width)))
+			continue
+		}
+		entry := ds.diagnostics[ds.rows[idx].entry]
 		severity := ds.renderSeverity(entry.Severity)
 		code := entry.Code
 		if code == "" {
 			code = "—"
 		}
-		message := truncateString(entry.Message, messageWidth)
-		location := fmt.Sprintf("%s:%d:%d", entry.File, entry.Line, entry.Column)
-		location = truncateString(location, locationWidth)
+		message := truncateString(entry.Message, cols.message)
+		location := truncateString(diagLocation(entry), cols.location)
+		if ds.isResolved(entry) {
+			// исправлена в Fix Mode; строка остаётся до следующего запуска
+			severity = lipgloss.NewStyle().Foreground(lipgloss.Color(validColor)).Render("FIXED")
+			message = lipgloss.NewStyle().Strikethrough(true).Render(padRight(message, cols.message))
+		}
 
-		row := fmt.Sprintf("%-*s  %-*s  %-*s  %s",
-			severityWidth, severity,
-			codeWidth, code,
-			messageWidth, message,
+		// отступы по колонкам экрана: стили и широкие символы не сбивают таблицу
+		row := strings.Join([]string{
+			padRight(severity, diagSeverityWidth),
+			padRight(truncateString(code, cols.code), cols.code),
+			padRight(message, cols.message),
 			location,
-		)
-
-		rowStyle := lipgloss.NewStyle().Width(width)
-		if idx == ds.selected {
-			rowStyle = rowStyle.Background(lipgloss.Color(diagSelectedBg)).Foreground(lipgloss.Color(diagSelectedFg))
-		}
+		}, "  ")
 		rows = append(rows, rowStyle.Render(row))
 	}
 
@@ -160,85 +170,36 @@ func (ds *DiagnosticsScreen) renderTableSection() string {
 		rows = append(rows, "")
 	}
 
-	return strings.Join(rows, "\n")
-}
-
-func (ds *DiagnosticsScreen) renderDetailSection() string {
-	width := ds.Width()
-	if width <= 0 {
-		width = 80
-	}
-
-	style := lipgloss.NewStyle().
-		Width(width).
-		Height(ds.detailHeight()).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(diagSecondaryColor)).
-		Padding(0, 1)
-
-	if len(ds.diagnostics) == 0 || ds.selected < 0 || ds.selected >= len(ds.diagnostics) {
-		content := padLines([]string{"Select a diagnostic to see details."}, max(ds.detailHeight()-2, 1))
-		return style.Render(strings.Join(content, "\n"))
-	}
-
-	entry := ds.diagnostics[ds.selected]
-
-	header := fmt.Sprintf("%s — %s:%d:%d",
-		strings.ToUpper(entry.Severity),
-		entry.File,
-		entry.Line,
-		entry.Column,
+	columns := fmt.Sprintf("%-*s  %-*s  %-*s  %s",
+		diagSeverityWidth, "SEVERITY",
+		cols.code, "CODE",
+		cols.message, "MESSAGE",
+		"LOCATION",
 	)
-	if entry.Code != "" {
-		header = fmt.Sprintf("%s [%s]", header, entry.Code)
+	counter := focusCounter(ds.focus, components.ListCounter(ds.selected, len(ds.rows)), !ds.detailFocused)
+	header := listHeaderRow(columns, counter, width)
+	if showBar {
+		header += " "
 	}
 
-	var content []string
-	content = append(content, lipgloss.NewStyle().Bold(true).Render(header))
-	content = append(content, entry.Message)
-
-	if entry.HasFixes {
-		content = append(content, lipgloss.NewStyle().
-			Foreground(lipgloss.Color(diagInfoColor)).
-			Render("🔧 Fixes available (open Fix Mode to apply)."))
-	}
-
-	if ds.includeNotes && len(entry.Notes) > 0 {
-		content = append(content, lipgloss.NewStyle().Bold(true).Render("Notes:"))
-		for _, note := range entry.Notes {
-			content = append(content, "  • "+note)
-		}
-	}
-
-	footer := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(diagSecondaryColor)).
-		Render("Enter: open in editor • F5: rerun diagnostics • n: toggle notes")
-
-	maxContentLines := max(ds.detailHeight()-2, 1)
-	baseSlots := maxContentLines
-	if footer != "" {
-		baseSlots--
-	}
-	if baseSlots < 0 {
-		baseSlots = 0
-	}
-
-	var limited []string
-	if baseSlots > 0 {
-		limited = clampLines(content, baseSlots, true)
-	} else if footer == "" {
-		limited = clampLines(content, maxContentLines, true)
-	} else {
-		limited = []string{}
+	table := lipgloss.NewStyle().Width(width).Render(strings.Join(rows, "\n"))
+	if showBar {
+		bar := components.RenderScrollbar(len(ds.rows), ds.scroll, height, height)
+		table = lipgloss.JoinHorizontal(lipgloss.Top, table, strings.Join(bar, "\n"))
 	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, table)
+}
 
-	if footer != "" {
-		limited = append(limited, footer)
+// listHeaderRow рисует строку заголовка списка со счётчиком позиции в правом углу.
+func listHeaderRow(title, counter string, width int) string {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor)).Bold(true)
+	gap := width - lipgloss.Width(counter) - 1
+	if gap < 1 {
+		return style.Render(truncateString(counter, width))
 	}
-
-	limited = padLines(limited, maxContentLines)
-
-	return style.Render(strings.Join(limited, "\n"))
+	title = truncateString(title, gap)
+	padding := strings.Repeat(" ", max(width-lipgloss.Width(title)-lipgloss.Width(counter), 0))
+	return style.Render(title + padding + counter)
 }
 
 func (ds *DiagnosticsScreen) renderSeverity(severity string) string {
@@ -261,14 +222,20 @@ func truncateString(text string, width int) string {
 	if lipgloss.Width(text) <= width {
 		return text
 	}
-	if width <= 1 {
-		return text[:width]
-	}
+	// ширина считается в колонках: широкие руны занимают две
 	runes := []rune(text)
-	if len(runes) <= width {
-		return string(runes[:width])
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// padRight дополняет text пробелами до width колонок экрана.
+func padRight(text string, width int) string {
+	if gap := width - lipgloss.Width(text); gap > 0 {
+		return text + strings.Repeat(" ", gap)
 	}
-	return string(runes[:width-1]) + "…"
+	return text
 }
 
 func truncatePath(path string, width int) string {
@@ -2,17 +2,18 @@ package screens
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
 	core "surge-tui/internal/core/surge"
+	"surge-tui/internal/fs"
 	"surge-tui/internal/platform"
+	"surge-tui/internal/ui/styles"
 )
 
 // DiagnosticsScreen отображает результаты `surge diag` и позволяет прыгать к ошибкам.
@@ -20,15 +21,31 @@ type DiagnosticsScreen struct {
 	BaseScreen
 
 	projectPath string
-	client      *core.Client
+	scope       string // каталог, которым ограничен запуск; пусто — весь проект
+	client      core.SurgeRunner
+	ops         *ProjectOps // пока другой экран меняет файлы, запуск откладывается
 
 	running     bool
 	err         error
-	status      string
+	status      statusQueue       // последнее сообщение остаётся до следующего
+	all         []DiagnosticEntry // полный список; diagnostics — его отфильтрованная часть
 	diagnostics []DiagnosticEntry
-	selected    int
+	rows        []diagRow // строки таблицы: заголовки групп и записи
+	selected    int       // индекс в rows
 	scroll      int
 
+	groupMode diagGroupMode
+	collapsed map[string]bool // свёрнутые группы по collapseKey
+
+	detailFocused bool // Tab переводит стрелки на прокрутку панели деталей
+	detailScroll  int
+	focus         styles.FocusStyle
+	detailNote    int // выбранная заметка с местом в панели деталей; -1 — нет
+
+	codeWidth     int // 95-й перцентиль ширины кода в текущих результатах
+	locationWidth int // то же для места file:line:col
+	messageShift  int // ручной сдвиг ширины сообщения (< / >), на всю сессию
+
 	lastRun      time.Time
 	runDuration  time.Duration
 	exitCode     int
@@ -39,42 +56,82 @@ type DiagnosticsScreen struct {
 	includeNotes bool
 	includeFixes bool
 
-	cancel context.CancelFunc
+	filter      diagFilter
+	filterInput textinput.Model
+	filtering   bool
+
+	watching      bool // режим наблюдения: перезапуск при изменении файлов
+	watcher       *fs.ProjectWatcher
+	watchGen      int
+	watchInterval time.Duration
+
+	cancel   context.CancelFunc
+	runID    int       // номер текущего запуска; сообщения старых запусков отбрасываются
+	started  time.Time // начало текущего запуска
+	received int       // диагностик получено в текущем запуске
+
+	stall     *stallWatch // сторож текущего запуска
+	stallNote string      // предупреждение сторожа: surge давно молчит
+
+	rerunFile string // файл, перезапущенный клавишей r; его результат попадёт в статус
+
+	cache    diagCache
+	runStamp projectStamp // отпечаток файлов на начало текущего запуска
+
+	pendingIssue IssueRef        // проблема из Fix Mode, которую выбрать, когда придут результаты
+	resolved     map[string]bool // диагностики, исправленные в Fix Mode, по IssueRef.key
+	adoptStamp   bool            // файлы менялись фиксами Fix Mode: прежний результат ещё годен
 }
 
 // DiagnosticEntry представляет одну диагностику с нормализованными полями.
 type DiagnosticEntry struct {
-	Severity string
-	Code     string
-	Message  string
-	File     string // отображаемый путь (относительный)
-	AbsPath  string // абсолютный путь
-	Line     int
-	Column   int
-	Notes    []string
-	HasFixes bool
-	FixIDs   []string
+	Severity  string
+	Code      string
+	Message   string
+	File      string // отображаемый путь (относительный)
+	AbsPath   string // абсолютный путь
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+	Notes     []DiagnosticNote
+	HasFixes  bool
+	Fixes     []core.FixJSON // срез ответа surge, не копия: ID, заголовок, применимость и правки
+}
+
+// DiagnosticNote — заметка диагностики и место, на которое она указывает.
+// Пути берутся из кэша diagnosticPathResolver и делятся со всеми записями файла.
+type DiagnosticNote struct {
+	Message string
+	File    string // отображаемый путь; пусто — у заметки нет места
+	AbsPath string
+	Line    int
+	Column  int
 }
 
-type diagnosticsResultMsg struct {
-	entries  []DiagnosticEntry
-	duration time.Duration
-	exitCode int
-	err      error
+// HasLocation сообщает, можно ли перейти к месту заметки.
+func (n DiagnosticNote) HasLocation() bool {
+	return n.AbsPath != "" && n.Line > 0
 }
 
 // NewDiagnosticsScreen создаёт экран диагностики.
-func NewDiagnosticsScreen(projectPath string, client *core.Client) *DiagnosticsScreen {
-	return &DiagnosticsScreen{
+func NewDiagnosticsScreen(projectPath string, client core.SurgeRunner) *DiagnosticsScreen {
+	ds := &DiagnosticsScreen{
 		BaseScreen:   NewBaseScreen("Diagnostics"),
 		projectPath:  projectPath,
 		client:       client,
-		status:       "Diagnostics will run shortly…",
+		status:       statusQueue{sticky: true},
 		selected:     0,
 		scroll:       0,
+		detailNote:   -1,
 		includeNotes: true,
 		includeFixes: true,
+		filterInput:  newDiagFilterInput(),
+		collapsed:    make(map[string]bool),
+		focus:        styles.DefaultFocus(),
 	}
+	ds.setStatus("Diagnostics will run shortly…")
+	return ds
 }
 
 // Init запускает первоначальную диагностику.
@@ -82,56 +139,62 @@ func (ds *DiagnosticsScreen) Init() tea.Cmd {
 	return ds.runDiagnostics()
 }
 
-// OnEnter повторно запускает диагностику при первом входе или по заверщению первого запуска.
+// OnEnter показывает прежний результат, если он свежий, иначе повторно
+// запускает диагностику, и возобновляет наблюдение, если оно было включено.
 func (ds *DiagnosticsScreen) OnEnter() tea.Cmd {
+	var watch tea.Cmd
+	if ds.watching {
+		watch = ds.startWatcher()
+	}
 	if ds.running {
-		return nil
+		return watch
 	}
-	return ds.runDiagnostics()
+	return tea.Batch(ds.refreshIfStale(), watch)
 }
 
 // Update обрабатывает сообщения.
 func (ds *DiagnosticsScreen) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	screen, cmd := ds.update(msg)
+	return screen, tea.Batch(cmd, ds.status.schedule())
+}
+
+func (ds *DiagnosticsScreen) setStatus(msg string) {
+	ds.status.push(msg, statusSeverityOf(msg))
+}
+
+// StatusHistory возвращает последние сообщения строки статуса, новые первыми.
+func (ds *DiagnosticsScreen) StatusHistory() []string {
+	return ds.status.recent()
+}
+
+func (ds *DiagnosticsScreen) update(msg tea.Msg) (Screen, tea.Cmd) {
 	switch m := msg.(type) {
 	case tea.WindowSizeMsg:
 		ds.SetSize(m.Width, m.Height-1)
 		return ds, nil
 	case tea.KeyMsg:
 		return ds.handleKey(m)
+	case diagWatchMsg:
+		return ds, ds.handleWatchMsg(m)
+	case diagStartedMsg:
+		return ds, ds.handleDiagStarted(m)
+	case diagChunkMsg:
+		return ds, ds.handleDiagChunk(m)
 	case diagnosticsResultMsg:
-		ds.running = false
-		if ds.cancel != nil {
-			ds.cancel = nil
-		}
-
-		if m.err != nil {
-			if errors.Is(m.err, context.Canceled) {
-				// Игнорируем отменённый запуск.
-				return ds, nil
-			}
-			ds.err = m.err
-			ds.status = fmt.Sprintf("Diagnostics failed: %v", m.err)
-			ds.diagnostics = nil
-			ds.errorCount, ds.warningCount, ds.infoCount = 0, 0, 0
-			return ds, nil
-		}
-
-		ds.err = nil
-		ds.diagnostics = m.entries
-		ds.exitCode = m.exitCode
-		ds.runDuration = m.duration
-		ds.lastRun = time.Now()
-		ds.status = ds.successStatus()
-		ds.selected = 0
-		ds.scroll = 0
-		ds.recountSeverities()
-		return ds, nil
+		return ds, ds.handleDiagResult(m)
+	case stallMsg:
+		return ds, ds.handleStall(m)
+	case diagFreshnessMsg:
+		return ds, ds.handleFreshness(m)
 	}
 
 	return ds, nil
 }
 
 func (ds *DiagnosticsScreen) handleKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
+	if ds.filtering {
+		return ds.handleFilterKey(msg)
+	}
 	key := platform.CanonicalKeyForLookup(msg.String())
 	if ds.running {
 		switch key {
@@ -143,6 +206,16 @@ func (ds *DiagnosticsScreen) handleKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
 		}
 	}
 
+	if key == "tab" {
+		ds.detailFocused = !ds.detailFocused
+		return ds, nil
+	}
+	if ds.detailFocused {
+		if handled, cmd := ds.handleDetailKey(key); handled {
+			return ds, cmd
+		}
+	}
+
 	switch key {
 	case "f5", "ctrl+r":
 		return ds, ds.runDiagnostics()
@@ -157,27 +230,60 @@ func (ds *DiagnosticsScreen) handleKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
 	case "home", "g":
 		ds.setSelection(0)
 	case "end", "G":
-		ds.setSelection(len(ds.diagnostics) - 1)
+		ds.setSelection(len(ds.rows) - 1)
+	case "J":
+		ds.scrollDetail(1)
+	case "K":
+		ds.scrollDetail(-1)
 	case "enter":
-		return ds, ds.openSelectedLocation()
-	case "f":
-		if len(ds.diagnostics) == 0 {
+		if ds.handleGroupKey(key) {
 			return ds, nil
 		}
-		entry := ds.diagnostics[ds.selected]
-		if !entry.HasFixes {
+		return ds, ds.openSelectedLocation()
+	case "left", "right":
+		ds.handleGroupKey(key)
+	case "m":
+		ds.cycleGroupMode()
+	case ">":
+		ds.resizeMessageColumn(diagColumnStep)
+	case "<":
+		ds.resizeMessageColumn(-diagColumnStep)
+	case "=":
+		ds.resetColumns()
+	case "P":
+		return ds, ds.widenScope()
+	case "r":
+		return ds, ds.rerunSelectedFile()
+	case "f":
+		entry, ok := ds.selectedEntry()
+		if !ok || !entry.HasFixes {
 			return ds, nil
 		}
 		fixID := ""
-		if len(entry.FixIDs) > 0 {
-			fixID = entry.FixIDs[0]
+		if len(entry.Fixes) > 0 {
+			fixID = entry.Fixes[0].ID
 		}
 		return ds, func() tea.Msg {
 			return OpenFixModeMsg{FilePath: entry.AbsPath, FixID: fixID}
 		}
+	case "Y":
+		if path := ds.SelectedPath(); path != "" {
+			return ds, func() tea.Msg { return CopyPathMsg{Path: path, Relative: true} }
+		}
+		return ds, nil
+	case "/":
+		return ds, ds.startFilterInput()
+	case "E":
+		ds.toggleSeverity("error")
+	case "W":
+		ds.toggleSeverity("warning")
+	case "I":
+		ds.toggleSeverity("info")
+	case "w":
+		return ds, ds.toggleWatch()
 	case "n":
 		ds.includeNotes = !ds.includeNotes
-		ds.status = fmt.Sprintf("Notes %s", ternary(ds.includeNotes, "enabled", "hidden"))
+		ds.setStatus(fmt.Sprintf("Notes %s", ternary(ds.includeNotes, "enabled", "hidden")))
 	default:
 		return ds, nil
 	}
@@ -193,7 +299,7 @@ func (ds *DiagnosticsScreen) View() string {
 }
 
 func (ds *DiagnosticsScreen) ShortHelp() string {
-	return "F5 Run diag • ↑↓ Select • Enter Open • f Fix mode • n Notes"
+	return "F5 Run diag • r Re-run file • ↑↓ Select • Enter Open/Toggle • m Group • / Filter • E/W/I Severities • w Watch • f Fix mode • n Notes"
 }
 
 func (ds *DiagnosticsScreen) FullHelp() []string {
@@ -202,200 +308,130 @@ func (ds *DiagnosticsScreen) FullHelp() []string {
 		"",
 		"Diagnostics Screen:",
 		platform.ReplacePrimaryModifier("  F5 / Ctrl+R - Run diagnostics"),
+		"  r - Re-run diagnostics for the selected file only",
 		"  ↑/↓ or j/k - Move selection",
 		"  PgUp/PgDn - Scroll page",
-		"  Enter - Open location in workspace",
+		"  Tab - Focus details pane (↑/↓, PgUp/PgDn scroll it)",
+		"  ↑/↓ + Enter in details - Select a note location and open it",
+		"  J/K - Scroll details",
+		"  Enter - Open location in workspace / expand or collapse group",
+		"  ←/→ - Collapse/expand group",
+		"  m - Group by file / by code / no grouping",
+		"  < / > - Shrink / widen the message column, = - fit columns to results",
 		"  f - Open Fix Mode",
+		"  P - Widen a directory run (c in the tree) to the whole project",
+		"  Y - Copy project-relative path of the file",
+		"  / - Filter by message, code or file path",
+		"  E / W / I - Show or hide errors / warnings / info",
+		"  w - Watch mode: rerun on .sg / surge.toml changes",
 		"  n - Toggle notes visibility",
-		"  Esc - Cancel running diagnostics / back",
+		"  Esc - Clear filter / cancel running diagnostics / back",
 	}...)
 	return help
 }
 
-func (ds *DiagnosticsScreen) runDiagnostics() tea.Cmd {
-	if ds.client == nil {
-		ds.err = errors.New("surge client not configured")
-		ds.status = "Surge client unavailable"
-		return nil
-	}
-
-	if ds.cancel != nil {
-		ds.cancel()
-		ds.cancel = nil
-	}
-
-	ds.running = true
-	ds.err = nil
-	ds.status = "Running diagnostics…"
-
-	projectPath := ds.projectPath
-	includeNotes := ds.includeNotes
-	includeFixes := ds.includeFixes
-	client := ds.client
-
-	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
-	ds.cancel = cancel
-	start := time.Now()
-
-	return func() tea.Msg {
-		defer cancel()
-		resp, err := client.Diagnose(ctx, projectPath, includeNotes, includeFixes)
-		duration := time.Since(start)
-		var entries []DiagnosticEntry
-		exitCode := 0
-		if resp != nil {
-			entries = ds.normalizeResponse(resp)
-			exitCode = resp.ExitCode
-		}
-		return diagnosticsResultMsg{
-			entries:  entries,
-			duration: duration,
-			exitCode: exitCode,
-			err:      err,
-		}
-	}
-}
-
-func (ds *DiagnosticsScreen) normalizeResponse(resp *core.DiagResponse) []DiagnosticEntry {
+// normalizeDiagnostics приводит ответ `surge diag` к плоскому отсортированному списку.
+func normalizeDiagnostics(resp *core.DiagResponse, projectPath string, includeNotes bool) []DiagnosticEntry {
 	var entries []DiagnosticEntry
 	if resp == nil {
 		return entries
 	}
-
-	appendEntry := func(displayPath string, out core.DiagnosticsOutput) {
-		for _, diag := range out.Diagnostics {
-			entry := DiagnosticEntry{
-				Severity: strings.ToLower(diag.Severity),
-				Code:     diag.Code,
-				Message:  diag.Message,
-				Notes:    nil,
-				HasFixes: len(diag.Fixes) > 0,
-			}
-
-			filePath := diag.Location.File
-			if filePath == "" {
-				filePath = displayPath
-			}
-			if filePath == "" {
-				filePath = "unknown"
-			}
-
-			abs := filePath
-			if !filepath.IsAbs(abs) && ds.projectPath != "" {
-				abs = filepath.Join(ds.projectPath, filePath)
-			}
-			abs = filepath.Clean(abs)
-
-			entry.AbsPath = abs
-
-			// Build display path relative to project.
-			display := filePath
-			if filepath.IsAbs(display) && ds.projectPath != "" {
-				if rel, err := filepath.Rel(ds.projectPath, abs); err == nil {
-					display = rel
-				} else {
-					display = filepath.Base(abs)
-				}
-			}
-			entry.File = display
-
-			entry.Line = clampInt(int(diag.Location.StartLine), 1, 1<<31-1)
-			entry.Column = clampInt(int(diag.Location.StartCol), 1, 1<<31-1)
-			if entry.Line == 0 {
-				entry.Line = clampInt(int(diag.Location.EndLine), 1, 1<<31-1)
-			}
-			if entry.Column == 0 {
-				entry.Column = clampInt(int(diag.Location.EndCol), 1, 1<<31-1)
-			}
-
-			if len(diag.Notes) > 0 && ds.includeNotes {
-				for _, note := range diag.Notes {
-					if note.Message != "" {
-						entry.Notes = append(entry.Notes, note.Message)
-					}
-				}
-			}
-
-			if len(diag.Fixes) > 0 {
-				entry.FixIDs = make([]string, 0, len(diag.Fixes))
-				for _, fix := range diag.Fixes {
-					entry.FixIDs = append(entry.FixIDs, fix.ID)
-				}
-			}
-
-			entries = append(entries, entry)
+	if len(resp.Batch) > 0 {
+		total := 0
+		for _, out := range resp.Batch {
+			total += len(out.Diagnostics)
 		}
+		entries = make([]DiagnosticEntry, 0, total)
+	} else if resp.Single != nil {
+		entries = make([]DiagnosticEntry, 0, len(resp.Single.Diagnostics))
 	}
 
+	paths := newDiagnosticPathResolver(projectPath)
 	if len(resp.Batch) > 0 {
 		for path, out := range resp.Batch {
-			appendEntry(path, out)
+			entries = appendDiagEntries(entries, paths, path, out, includeNotes)
 		}
 	} else if resp.Single != nil {
-		appendEntry("", *resp.Single)
+		entries = appendDiagEntries(entries, paths, "", *resp.Single, includeNotes)
 	}
 
 	sortDiagnostics(entries)
 	return entries
 }
 
-func (ds *DiagnosticsScreen) moveSelection(delta int) {
-	if len(ds.diagnostics) == 0 {
-		ds.selected = 0
-		ds.scroll = 0
-		return
-	}
-	ds.selected = clampInt(ds.selected+delta, 0, len(ds.diagnostics)-1)
-	ds.ensureSelectionVisible()
-}
+// appendDiagEntries добавляет к entries диагностики одного файла ответа.
+func appendDiagEntries(entries []DiagnosticEntry, paths *diagnosticPathResolver, displayPath string, out core.DiagnosticsOutput, includeNotes bool) []DiagnosticEntry {
+	for _, diag := range out.Diagnostics {
+		entry := DiagnosticEntry{
+			Severity: strings.ToLower(diag.Severity),
+			Code:     diag.Code,
+			Message:  diag.Message,
+			Notes:    nil,
+			HasFixes: len(diag.Fixes) > 0,
+		}
 
-func (ds *DiagnosticsScreen) setSelection(index int) {
-	if len(ds.diagnostics) == 0 {
-		ds.selected = 0
-		ds.scroll = 0
-		return
-	}
-	ds.selected = clampInt(index, 0, len(ds.diagnostics)-1)
-	ds.ensureSelectionVisible()
-}
+		filePath := diag.Location.File
+		if filePath == "" {
+			filePath = displayPath
+		}
+		if filePath == "" {
+			filePath = "unknown"
+		}
 
-func (ds *DiagnosticsScreen) ensureSelectionVisible() {
-	visible := ds.listHeight()
-	if visible <= 0 {
-		ds.scroll = 0
-		return
-	}
-	if ds.selected < ds.scroll {
-		ds.scroll = ds.selected
-	} else if ds.selected >= ds.scroll+visible {
-		ds.scroll = ds.selected - visible + 1
-	}
-	if ds.scroll < 0 {
-		ds.scroll = 0
-	}
-	maxScroll := len(ds.diagnostics) - visible
-	if maxScroll < 0 {
-		maxScroll = 0
-	}
-	if ds.scroll > maxScroll {
-		ds.scroll = maxScroll
+		entry.AbsPath, entry.File = paths.resolve(filePath)
+
+		entry.Line = clampInt(int(diag.Location.StartLine), 1, 1<<31-1)
+		entry.Column = clampInt(int(diag.Location.StartCol), 1, 1<<31-1)
+		if entry.Line == 0 {
+			entry.Line = clampInt(int(diag.Location.EndLine), 1, 1<<31-1)
+		}
+		if entry.Column == 0 {
+			entry.Column = clampInt(int(diag.Location.EndCol), 1, 1<<31-1)
+		}
+		entry.EndLine = max(int(diag.Location.EndLine), entry.Line)
+		entry.EndColumn = int(diag.Location.EndCol)
+
+		if len(diag.Notes) > 0 && includeNotes {
+			entry.Notes = make([]DiagnosticNote, 0, len(diag.Notes))
+			for _, note := range diag.Notes {
+				if note.Message != "" {
+					entry.Notes = append(entry.Notes, diagnosticNote(paths, note, filePath))
+				}
+			}
+		}
+
+		if len(diag.Fixes) > 0 {
+			entry.Fixes = diag.Fixes
+		}
+
+		entries = append(entries, entry)
 	}
+	return entries
 }
 
-func (ds *DiagnosticsScreen) cancelRunning() {
-	if ds.cancel != nil {
-		ds.cancel()
-		ds.cancel = nil
-		ds.status = "Diagnostics cancelled"
-		ds.running = false
-	}
+// diagnosticNote переводит заметку ответа в запись с разрешённым путём.
+// Заметка без файла, но со строкой указывает в файл своей диагностики.
+func diagnosticNote(paths *diagnosticPathResolver, note core.NoteJSON, diagFile string) DiagnosticNote {
+	out := DiagnosticNote{Message: note.Message}
+	loc := note.Location
+	if loc.StartLine == 0 {
+		return out
+	}
+	file := loc.File
+	if file == "" {
+		file = diagFile
+	}
+	out.AbsPath, out.File = paths.resolve(file)
+	out.Line = int(loc.StartLine)
+	out.Column = max(int(loc.StartCol), 1)
+	return out
 }
 
 func (ds *DiagnosticsScreen) openSelectedLocation() tea.Cmd {
-	if len(ds.diagnostics) == 0 || ds.selected < 0 || ds.selected >= len(ds.diagnostics) {
+	entry, ok := ds.selectedEntry()
+	if !ok {
 		return nil
 	}
-	entry := ds.diagnostics[ds.selected]
 	abs := entry.AbsPath
 	if abs == "" {
 		return nil
@@ -430,20 +466,25 @@ func (ds *DiagnosticsScreen) listHeight() int {
 	if h <= 0 {
 		return 0
 	}
-	height := max(h-ds.headerHeight()-ds.detailHeight()-2, 3)
+	// строка заголовков таблицы не входит в прокручиваемую область
+	height := max(h-ds.headerHeight()-ds.detailHeight()-3, 3)
 	return height
 }
 
 func (ds *DiagnosticsScreen) successStatus() string {
-	if len(ds.diagnostics) == 0 {
+	if len(ds.all) == 0 {
+		if ds.exitCode != 0 {
+			// JSON разобран, но surge завершился с ошибкой и ничего не сообщил
+			return fmt.Sprintf("No diagnostics reported, but surge diag exited with code %d (see Logs)", ds.exitCode)
+		}
 		return "No diagnostics reported"
 	}
-	return fmt.Sprintf("Diagnostics completed: %d issues (errors:%d warnings:%d)", len(ds.diagnostics), ds.errorCount, ds.warningCount)
+	return fmt.Sprintf("Diagnostics completed: %d issues (errors:%d warnings:%d)", len(ds.all), ds.errorCount, ds.warningCount)
 }
 
 func (ds *DiagnosticsScreen) recountSeverities() {
 	var errorsCount, warningsCount, infosCount int
-	for _, diag := range ds.diagnostics {
+	for _, diag := range ds.all {
 		switch strings.ToLower(diag.Severity) {
 		case "error":
 			errorsCount++
@@ -485,7 +526,14 @@ func sortDiagnostics(entries []DiagnosticEntry) {
 		if a.Column != b.Column {
 			return a.Column < b.Column
 		}
-		return a.Message < b.Message
+		if a.Message != b.Message {
+			return a.Message < b.Message
+		}
+		// одинаковые сообщения в одном месте различаются кодом и заметками
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return len(a.Notes) < len(b.Notes)
 	})
 }
 
@@ -506,6 +554,33 @@ func (ds *DiagnosticsScreen) SetProjectPath(path string) {
 	ds.projectPath = path
 }
 
+// SetProjectOps подключает общий реестр операций над файлами проекта.
+func (ds *DiagnosticsScreen) SetProjectOps(ops *ProjectOps) {
+	ds.ops = ops
+}
+
+// SetScope ограничивает запуски каталогом dir и сразу перезапускает
+// диагностику, если она уже идёт; иначе запуск начнётся при входе на экран.
+func (ds *DiagnosticsScreen) SetScope(dir string) tea.Cmd {
+	ds.scope = normalizeScope(ds.projectPath, dir)
+	ds.cache.reset()
+	if !ds.running {
+		return nil
+	}
+	return ds.runDiagnostics()
+}
+
+// widenScope снимает ограничение каталогом и запускает диагностику проекта.
+func (ds *DiagnosticsScreen) widenScope() tea.Cmd {
+	if ds.scope == "" {
+		ds.setStatus("Already showing the whole project")
+		return nil
+	}
+	ds.scope = ""
+	ds.setStatus("Widened to project")
+	return ds.runDiagnostics()
+}
+
 // TriggerDiagnostics запускает диагностику вручную.
 func (ds *DiagnosticsScreen) TriggerDiagnostics() tea.Cmd {
 	if ds.running {
@@ -0,0 +1,323 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	core "surge-tui/internal/core/surge"
+)
+
+// Результаты `surge diag` приходят по файлам: пачки добавляются в список
+// по мере разбора, счётчики в заголовке обновляются сразу, а общая
+// сортировка применяется, когда запуск закончился.
+
+const (
+	diagChunkEntries = 2000                  // диагностик в одном сообщении
+	diagChunkLatency = 50 * time.Millisecond // дольше пачку не копим
+)
+
+type diagStartedMsg struct {
+	run   int
+	diag  *core.DiagRun
+	stamp projectStamp
+}
+
+type diagChunkMsg struct {
+	run     int
+	diag    *core.DiagRun
+	paths   *diagnosticPathResolver // кэш путей запуска; трогает только чтение
+	entries []DiagnosticEntry
+	closed  bool
+}
+
+type diagnosticsResultMsg struct {
+	run      int
+	duration time.Duration
+	exitCode int
+	err      error
+}
+
+func (ds *DiagnosticsScreen) runDiagnostics() tea.Cmd {
+	if ds.client == nil {
+		ds.err = errors.New("surge client not configured")
+		ds.setStatus("Surge client unavailable")
+		return nil
+	}
+	// диагностика посреди применения фиксов увидела бы полуизменённые файлы
+	if other := ds.ops.Busy(); other != "" {
+		ds.setStatus("Busy: " + other + " (F5 to retry)")
+		return nil
+	}
+
+	if ds.cancel != nil {
+		ds.cancel()
+		ds.cancel = nil
+	}
+
+	ds.runID++
+	ds.running = true
+	ds.err = nil
+	ds.setStatus("Running diagnostics…")
+	ds.started = time.Now()
+	ds.received = 0
+
+	run := ds.runID
+	projectPath := ds.projectPath
+	stamped := ds.cache.maxAge > 0
+	target := scopeTarget(ds.projectPath, ds.scope)
+	includeNotes := ds.includeNotes
+	includeFixes := ds.includeFixes
+	client := ds.client
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ds.cancel = cancel
+	ds.stopStall()
+	watch, ctx, waitStall := watchStalls(ctx)
+	ds.stall = watch
+
+	start := func() tea.Msg {
+		// отпечаток снимается до запуска: правки во время запуска делают результат устаревшим
+		var stamp projectStamp
+		if stamped {
+			stamp = stampProject(projectPath)
+		}
+		diag, err := client.StartDiagnose(ctx, target, includeNotes, includeFixes)
+		if err != nil {
+			return diagnosticsResultMsg{run: run, exitCode: -1, err: err}
+		}
+		return diagStartedMsg{run: run, diag: diag, stamp: stamp}
+	}
+	return tea.Batch(start, waitStall)
+}
+
+func (ds *DiagnosticsScreen) handleDiagStarted(msg diagStartedMsg) tea.Cmd {
+	if msg.run != ds.runID {
+		return drainDiag(msg.diag)
+	}
+	ds.runStamp = msg.stamp
+	// Старый список уступает место новому, как только пошли результаты
+	ds.all = nil
+	ds.resolved, ds.adoptStamp = nil, false
+	ds.errorCount, ds.warningCount, ds.infoCount = 0, 0, 0
+	ds.refilter("")
+	ds.setSelection(0)
+	return readDiagChunk(msg.run, msg.diag, newDiagnosticPathResolver(ds.projectPath), ds.includeNotes)
+}
+
+func (ds *DiagnosticsScreen) handleDiagChunk(msg diagChunkMsg) tea.Cmd {
+	if msg.run != ds.runID {
+		if !msg.closed {
+			return drainDiag(msg.diag)
+		}
+		return nil
+	}
+	if len(msg.entries) > 0 {
+		ds.appendStreamed(msg.entries)
+	}
+	if msg.closed {
+		return waitDiag(msg.run, msg.diag, ds.started)
+	}
+	return readDiagChunk(msg.run, msg.diag, msg.paths, ds.includeNotes)
+}
+
+// appendStreamed добавляет пачку в конец списка. Курсор, не сдвинутый
+// пользователем, остаётся на первой строке.
+func (ds *DiagnosticsScreen) appendStreamed(entries []DiagnosticEntry) {
+	keep := ""
+	if ds.selected > 0 {
+		keep = ds.currentKey()
+	}
+	first := len(ds.all) == 0
+	ds.all = append(ds.all, entries...)
+	ds.received += len(entries)
+	if first {
+		// пока идёт поток, ширины берутся по первой пачке; по всем — в конце
+		ds.measureColumns()
+	}
+	for _, entry := range entries {
+		switch severityClass(entry.Severity) {
+		case "error":
+			ds.errorCount++
+		case "warning":
+			ds.warningCount++
+		default:
+			ds.infoCount++
+		}
+	}
+	ds.refilter(keep)
+}
+
+// finishStream сортирует полученное, сохраняя выбранную пользователем строку.
+func (ds *DiagnosticsScreen) finishStream() {
+	keep := ""
+	if ds.selected > 0 {
+		keep = ds.currentKey()
+	}
+	sortDiagnostics(ds.all)
+	ds.recountSeverities()
+	ds.measureColumns()
+	ds.refilter(keep)
+	if keep == "" {
+		ds.selected, ds.scroll = 0, 0
+		if !ds.pendingIssue.IsZero() {
+			ds.focusIssue(ds.pendingIssue)
+		}
+	}
+	ds.pendingIssue = IssueRef{}
+}
+
+func (ds *DiagnosticsScreen) handleDiagResult(msg diagnosticsResultMsg) tea.Cmd {
+	if msg.run != ds.runID {
+		return nil
+	}
+	ds.running = false
+	ds.stopStall()
+	if ds.cancel != nil {
+		ds.cancel()
+		ds.cancel = nil
+	}
+
+	if msg.err != nil {
+		ds.cache.reset()
+		if errors.Is(msg.err, context.Canceled) {
+			// Отменённый запуск оставляет то, что успело прийти.
+			ds.finishStream()
+			return nil
+		}
+		ds.err = msg.err
+		ds.pendingIssue = IssueRef{}
+		ds.setStatus(diagFailure(msg.err))
+		ds.all, ds.diagnostics, ds.rows = nil, nil, nil
+		ds.errorCount, ds.warningCount, ds.infoCount = 0, 0, 0
+		return nil
+	}
+
+	ds.err = nil
+	ds.exitCode = msg.exitCode
+	ds.runDuration = msg.duration
+	ds.lastRun = time.Now()
+	ds.cache.store(ds.started, ds.runStamp)
+	ds.finishStream()
+	ds.setStatus(ds.successStatus())
+	return publishDiagnostics(ds.scope, ds.all)
+}
+
+// diagFailure описывает сбой запуска: surge не найден, не уложился в
+// таймаут, вывод не разобрался как JSON или запуск не удался по другой причине.
+func diagFailure(err error) string {
+	var cliErr *core.CLIError
+	switch {
+	case errors.Is(err, core.ErrNotFound):
+		return fmt.Sprintf("Diagnostics failed: %v (check surge_binary in Settings)", err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Sprintf("Diagnostics failed: %v (raise Surge Timeout in Settings)", err)
+	case errors.Is(err, core.ErrInvalidOutput) && errors.As(err, &cliErr):
+		msg := fmt.Sprintf("Diagnostics failed: surge diag printed invalid JSON (exit code %d)", cliErr.ExitCode)
+		if line := cliErr.FirstLine(); line != "" {
+			msg += ": " + line
+		}
+		return msg
+	}
+	return fmt.Sprintf("Diagnostics failed: %v", err)
+}
+
+func (ds *DiagnosticsScreen) cancelRunning() {
+	if ds.cancel != nil {
+		ds.cancel()
+		ds.cancel = nil
+		status := "Diagnostics cancelled"
+		if ds.received > 0 {
+			status += fmt.Sprintf(" (%s received)", groupDigits(ds.received))
+		}
+		ds.setStatus(status)
+		ds.running = false
+	}
+	ds.stopStall()
+}
+
+// handleStall показывает предупреждение сторожа, пока запуск идёт.
+func (ds *DiagnosticsScreen) handleStall(msg stallMsg) tea.Cmd {
+	if msg.watch != ds.stall {
+		return nil
+	}
+	ds.stallNote = stallWarning(msg.stall)
+	return msg.watch.wait()
+}
+
+func (ds *DiagnosticsScreen) stopStall() {
+	ds.stall.stop()
+	ds.stall, ds.stallNote = nil, ""
+}
+
+// runningStatus — строка статуса, пока идёт запуск.
+func (ds *DiagnosticsScreen) runningStatus() string {
+	if ds.stallNote != "" {
+		return ds.stallNote
+	}
+	if ds.received == 0 {
+		return "Running diagnostics…"
+	}
+	return fmt.Sprintf("Still receiving… %s so far", groupDigits(ds.received))
+}
+
+// readDiagChunk ждёт первый файл, затем копит следующие, пока пачка
+// не наберётся или не выйдет время. Нормализация идёт здесь, вне UI.
+func readDiagChunk(run int, diag *core.DiagRun, paths *diagnosticPathResolver, includeNotes bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := diagChunkMsg{run: run, diag: diag, paths: paths}
+		file, ok := <-diag.Files
+		if !ok {
+			msg.closed = true
+			return msg
+		}
+		msg.entries = appendDiagEntries(nil, paths, file.Path, file.Output, includeNotes)
+		deadline := time.After(diagChunkLatency)
+		for len(msg.entries) < diagChunkEntries {
+			select {
+			case file, ok := <-diag.Files:
+				if !ok {
+					msg.closed = true
+					return msg
+				}
+				msg.entries = appendDiagEntries(msg.entries, paths, file.Path, file.Output, includeNotes)
+			case <-deadline:
+				return msg
+			}
+		}
+		return msg
+	}
+}
+
+func waitDiag(run int, diag *core.DiagRun, started time.Time) tea.Cmd {
+	return func() tea.Msg {
+		exitCode, err := diag.Wait()
+		return diagnosticsResultMsg{run: run, duration: time.Since(started), exitCode: exitCode, err: err}
+	}
+}
+
+// drainDiag дочитывает вывод устаревшего запуска, чтобы процесс мог завершиться.
+func drainDiag(diag *core.DiagRun) tea.Cmd {
+	return func() tea.Msg {
+		for range diag.Files {
+		}
+		_, _ = diag.Wait()
+		return nil
+	}
+}
+
+// groupDigits форматирует число с разделителями разрядов: 12431 → "12,431".
+func groupDigits(n int) string {
+	if n < 0 {
+		return "-" + groupDigits(-n)
+	}
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,114 @@
+package screens
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"surge-tui/internal/fs"
+)
+
+// Режим наблюдения перезапускает диагностику, когда меняются .sg файлы или surge.toml.
+// Флаг watching живёт в экране и переживает уход с него; сам наблюдатель на это время
+// останавливается, а OnEnter перезапускает диагностику, если файлы за это время менялись.
+
+// diagWatchDebounce — сколько ждать тишины после изменения перед запуском.
+const diagWatchDebounce = 500 * time.Millisecond
+
+// diagWatchMsg приходит после пачки изменений; gen отсекает сигналы старого наблюдателя.
+type diagWatchMsg struct {
+	gen int
+}
+
+func isDiagWatchTarget(path string) bool {
+	return strings.HasSuffix(path, ".sg") || filepath.Base(path) == "surge.toml"
+}
+
+// SetWatchInterval задаёт интервал опроса, если fsnotify недоступен.
+func (ds *DiagnosticsScreen) SetWatchInterval(interval time.Duration) {
+	ds.watchInterval = interval
+}
+
+func (ds *DiagnosticsScreen) toggleWatch() tea.Cmd {
+	if ds.watching {
+		ds.watching = false
+		ds.stopWatcher()
+		ds.setStatus("Watch mode off")
+		return nil
+	}
+	ds.watching = true
+	cmd := ds.startWatcher()
+	status := "Watching .sg files and surge.toml"
+	if ds.watcher != nil && ds.watcher.Polling() {
+		status += " (polling)"
+	}
+	ds.setStatus(status)
+	return cmd
+}
+
+func (ds *DiagnosticsScreen) startWatcher() tea.Cmd {
+	ds.stopWatcher()
+	if ds.projectPath == "" {
+		return nil
+	}
+	ds.watchGen++
+	ds.watcher = fs.WatchProject(ds.projectPath, isDiagWatchTarget, ds.watchInterval)
+	return ds.waitForChange()
+}
+
+func (ds *DiagnosticsScreen) stopWatcher() {
+	if ds.watcher != nil {
+		ds.watcher.Close()
+		ds.watcher = nil
+	}
+}
+
+func (ds *DiagnosticsScreen) waitForChange() tea.Cmd {
+	watcher, gen := ds.watcher, ds.watchGen
+	return func() tea.Msg {
+		if !watcher.Next(diagWatchDebounce) {
+			return nil
+		}
+		return diagWatchMsg{gen: gen}
+	}
+}
+
+// handleWatchMsg перезапускает диагностику (runDiagnostics отменяет текущий запуск)
+// и снова ждёт изменений.
+func (ds *DiagnosticsScreen) handleWatchMsg(msg diagWatchMsg) tea.Cmd {
+	if !ds.watching || ds.watcher == nil || msg.gen != ds.watchGen {
+		return nil
+	}
+	return tea.Batch(ds.runDiagnostics(), ds.waitForChange())
+}
+
+// OnExit останавливает наблюдатель и текущий запуск: пока экран не активен,
+// его сообщения сюда не доходят. OnEnter запустит всё заново.
+func (ds *DiagnosticsScreen) OnExit() tea.Cmd {
+	ds.stopWatcher()
+	if ds.running {
+		ds.cancelRunning()
+	}
+	return nil
+}
+
+// watchNote возвращает "watching (last run 12:03:45)" для строки статуса.
+func (ds *DiagnosticsScreen) watchNote() string {
+	if !ds.watching {
+		return ""
+	}
+	note := "watching"
+	var details []string
+	if ds.watcher != nil && ds.watcher.Polling() {
+		details = append(details, "polling")
+	}
+	if !ds.lastRun.IsZero() {
+		details = append(details, "last run "+ds.lastRun.Format("15:04:05"))
+	}
+	if len(details) > 0 {
+		note += " (" + strings.Join(details, ", ") + ")"
+	}
+	return note
+}
@@ -1,6 +1,7 @@
 package screens
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"surge-tui/internal/platform"
+	"surge-tui/internal/syntax"
 )
 
 // EditorScreen предоставляет просмотр и (в будущем) редактирование файла.
@@ -27,6 +29,14 @@ type EditorScreen struct {
 	statusAt time.Time
 
 	softWrap bool
+
+	restoreScroll int // прокрутка после перезагрузки файла; -1 — начать сверху
+
+	highlight      []syntax.Line
+	highlightTheme *syntax.HighlightTheme
+
+	diagnostics    []EditorDiagnostic
+	allDiagnostics map[string][]EditorDiagnostic
 }
 
 type editorStats struct {
@@ -45,6 +55,8 @@ func NewEditorScreen() *EditorScreen {
 		loading:    false,
 		err:        nil,
 		softWrap:   false,
+
+		restoreScroll: -1,
 	}
 }
 
@@ -66,20 +78,43 @@ func (es *EditorScreen) Update(msg tea.Msg) (Screen, tea.Cmd) {
 		return es, nil
 	case tea.KeyMsg:
 		return es.handleKey(m)
+	case tea.MouseMsg:
+		if es.loading {
+			return es, nil
+		}
+		switch m.Button {
+		case tea.MouseButtonWheelUp:
+			es.scrollUp(3)
+		case tea.MouseButtonWheelDown:
+			es.scrollDown(3)
+		}
+		return es, nil
 	case editorFileLoadedMsg:
+		previous := es.lines
 		es.loading = false
 		es.err = nil
 		es.filePath = m.Path
 		es.lines = m.Lines
+		es.highlight = m.Highlight
+		es.diagnostics = diagnosticsForPath(es.allDiagnostics, m.Path)
 		es.stats = m.Stats
 		es.scroll = 0
 		es.setStatus("Loaded")
+		if es.restoreScroll >= 0 {
+			top, _ := remapLine(previous, es.lines, es.restoreScroll)
+			es.scroll = clampInt(top, 0, es.maxScroll())
+			es.restoreScroll = -1
+			es.setStatus("Reverted to the file on disk")
+		}
 		return es, nil
 	case editorFileErrorMsg:
 		es.loading = false
 		es.err = m.Err
 		es.filePath = m.Path
 		es.lines = nil
+		es.highlight = nil
+		es.diagnostics = nil
+		es.restoreScroll = -1
 		es.setStatus("")
 		return es, nil
 	}
@@ -103,7 +138,24 @@ func (es *EditorScreen) View() string {
 }
 
 func (es *EditorScreen) ShortHelp() string {
-	return platform.ReplacePrimaryModifier("↑↓ Scroll • PgUp/PgDn • Ctrl+R Reload • g/G Top/Bottom")
+	return platform.ReplacePrimaryModifier("↑↓ Scroll • PgUp/PgDn • Alt+↑↓ Diagnostics • Ctrl+R Reload • g/G Top/Bottom")
+}
+
+// SetHighlightTheme задаёт тему подсветки синтаксиса (nil отключает подсветку).
+func (es *EditorScreen) SetHighlightTheme(theme *syntax.HighlightTheme) {
+	es.highlightTheme = theme
+}
+
+// SetDiagnostics принимает результаты surge diag, сгруппированные по файлам.
+func (es *EditorScreen) SetDiagnostics(diags map[string][]EditorDiagnostic) {
+	es.allDiagnostics = diags
+	if es.filePath == "" {
+		return
+	}
+	next := diags[filepath.Clean(es.filePath)]
+	if len(changedDiagnosticLines(es.diagnostics, next)) > 0 {
+		es.diagnostics = append([]EditorDiagnostic(nil), next...)
+	}
 }
 
 func (es *EditorScreen) FullHelp() []string {
@@ -115,6 +167,7 @@ func (es *EditorScreen) FullHelp() []string {
 		"  PgUp/PgDn - Scroll by half page",
 		"  g - Go to top",
 		"  G - Go to bottom",
+		"  Alt+↑/Alt+↓ - Previous/next diagnostic",
 		platform.ReplacePrimaryModifier("  Ctrl+R - Reload current file"),
 	}...)
 	return help
@@ -152,7 +205,11 @@ func (es *EditorScreen) OpenFile(path string) tea.Cmd {
 			runeCount: utf8.RuneCountInString(text),
 			modTime:   info.ModTime(),
 		}
-		return editorFileLoadedMsg{Path: abs, Lines: lines, Stats: stats}
+		var highlight []syntax.Line
+		if syntax.SupportsFile(abs) {
+			highlight = syntax.HighlightDocument(lines)
+		}
+		return editorFileLoadedMsg{Path: abs, Lines: lines, Highlight: highlight, Stats: stats}
 	}
 }
 
@@ -160,6 +217,10 @@ func (es *EditorScreen) handleKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
 	if es.loading {
 		return es, nil
 	}
+	if msg.Paste {
+		es.setStatus("Read-only view: paste ignored")
+		return es, nil
+	}
 	key := platform.CanonicalKeyForLookup(msg.String())
 	switch key {
 	case "up", "k":
@@ -180,6 +241,12 @@ func (es *EditorScreen) handleKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
 	case "G":
 		es.scrollBottom()
 		return es, nil
+	case "alt+up":
+		es.jumpToDiagnostic(-1)
+		return es, nil
+	case "alt+down":
+		es.jumpToDiagnostic(1)
+		return es, nil
 	case "ctrl+r":
 		if es.filePath != "" {
 			return es, es.OpenFile(es.filePath)
@@ -189,6 +256,43 @@ func (es *EditorScreen) handleKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
 	return es, nil
 }
 
+// CanToggleComment сообщает, что открыт файл: экран только просматривает
+// его, поэтому ToggleComment лишь объясняет, где править.
+func (es *EditorScreen) CanToggleComment() bool {
+	return es.filePath != "" && !es.loading
+}
+
+// ToggleComment не меняет файл: экран только для чтения.
+func (es *EditorScreen) ToggleComment() tea.Cmd {
+	es.setStatus("Read-only view: open the file in the workspace to comment lines")
+	return nil
+}
+
+// RevertFile перечитывает файл с диска, сохраняя позицию прокрутки.
+// Экран только просматривает файл, поэтому подтверждение не требуется.
+func (es *EditorScreen) RevertFile() tea.Cmd {
+	if es.filePath == "" || es.loading {
+		es.setStatus("No file to revert")
+		return nil
+	}
+	es.restoreScroll = es.scroll
+	return es.OpenFile(es.filePath)
+}
+
+// jumpToDiagnostic прокручивает к следующей (dir > 0) или предыдущей диагностике
+// относительно верхней видимой строки.
+func (es *EditorScreen) jumpToDiagnostic(dir int) {
+	line, ok := adjacentDiagnosticLine(es.diagnostics, es.scroll, dir)
+	if !ok {
+		es.setStatus("No more diagnostics")
+		return
+	}
+	es.scroll = clampInt(line, 0, es.maxScroll())
+	if diag, found := diagnosticsOnLine(es.diagnostics, line); found {
+		es.setStatus(fmt.Sprintf("L%d: %s", line+1, formatEditorDiagnostic(diag)))
+	}
+}
+
 func (es *EditorScreen) scrollUp(n int) {
 	if n < 1 {
 		n = 1
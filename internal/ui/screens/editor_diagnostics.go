@@ -0,0 +1,331 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	core "surge-tui/internal/core/surge"
+)
+
+// EditorDiagnostic — диагностика, привязанная к позиции в буфере (0-based).
+type EditorDiagnostic struct {
+	Line     int
+	Col      int
+	EndLine  int
+	EndCol   int
+	Severity string
+	Code     string
+	Message  string
+	Fixes    []core.FixJSON
+}
+
+// DiagnosticsPublishedMsg рассылается после успешного запуска диагностики,
+// чтобы App раздал результаты открытым редакторам. Если Path задан,
+// Entries относятся только к этому файлу и заменяют его прежние диагностики;
+// если задан Dir — заменяют диагностики файлов внутри этого каталога.
+type DiagnosticsPublishedMsg struct {
+	Path    string
+	Dir     string
+	Entries []DiagnosticEntry
+	Err     error
+}
+
+// publishDiagnostics рассылает результаты запуска по каталогу dir
+// (пусто — по всему проекту).
+func publishDiagnostics(dir string, entries []DiagnosticEntry) tea.Cmd {
+	return func() tea.Msg {
+		return DiagnosticsPublishedMsg{Dir: dir, Entries: entries}
+	}
+}
+
+// CollectDiagnostics запускает `surge diag` в фоне, не открывая экран диагностики.
+func CollectDiagnostics(client core.SurgeRunner, projectPath string) tea.Cmd {
+	if client == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return DiagnoseFile(context.Background(), client, projectPath, "")
+	}
+}
+
+// DiagnoseFile синхронно запускает `surge diag` для одного файла (или всего
+// проекта, если path пуст). Вызывается из tea.Cmd.
+func DiagnoseFile(ctx context.Context, client core.SurgeRunner, projectPath, path string) DiagnosticsPublishedMsg {
+	var resp *core.DiagResponse
+	var err error
+	if path != "" {
+		path = filepath.Clean(path)
+		resp, err = client.DiagnoseFiles(ctx, []string{path}, true, true)
+	} else {
+		resp, err = client.Diagnose(ctx, projectPath, true, true)
+	}
+	if err != nil {
+		return DiagnosticsPublishedMsg{Path: path, Err: fmt.Errorf("surge diag: %w", err)}
+	}
+	entries := normalizeDiagnostics(resp, projectPath, true)
+	if path != "" {
+		entries = entriesForPath(entries, path)
+	}
+	return DiagnosticsPublishedMsg{Path: path, Entries: entries}
+}
+
+func entriesForPath(entries []DiagnosticEntry, path string) []DiagnosticEntry {
+	var out []DiagnosticEntry
+	for _, entry := range entries {
+		if filepath.Clean(entry.AbsPath) == path {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// DiagnosticsSummary форматирует итог для строки статуса, например "diagnostics: 3 errors".
+func DiagnosticsSummary(entries []DiagnosticEntry) string {
+	var errorsCount, warningsCount int
+	for _, entry := range entries {
+		switch entry.Severity {
+		case "error":
+			errorsCount++
+		case "warning":
+			warningsCount++
+		}
+	}
+	switch {
+	case errorsCount == 0 && warningsCount == 0:
+		return "diagnostics: clean"
+	case warningsCount == 0:
+		return fmt.Sprintf("diagnostics: %s", plural(errorsCount, "error"))
+	case errorsCount == 0:
+		return fmt.Sprintf("diagnostics: %s", plural(warningsCount, "warning"))
+	default:
+		return fmt.Sprintf("diagnostics: %s, %s", plural(errorsCount, "error"), plural(warningsCount, "warning"))
+	}
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// rerunSelectedFile перезапускает `surge diag` только для файла выбранной
+// записи; результат приходит через DiagnosticsPublishedMsg с Path.
+func (ds *DiagnosticsScreen) rerunSelectedFile() tea.Cmd {
+	path := ds.SelectedPath()
+	if path == "" {
+		return nil
+	}
+	ds.rerunFile = filepath.Clean(path)
+	ds.setStatus(fmt.Sprintf("Re-running diagnostics for %s…", projectRel(ds.projectPath, path)))
+	return func() tea.Msg { return RunFileDiagnosticsMsg{Path: path} }
+}
+
+// ReplaceFileDiagnostics заменяет записи одного файла, не перезапуская диагностику проекта.
+func (ds *DiagnosticsScreen) ReplaceFileDiagnostics(path string, entries []DiagnosticEntry) {
+	path = filepath.Clean(path)
+	if path == ds.rerunFile {
+		ds.rerunFile = ""
+		ds.setStatus(fmt.Sprintf("%s: %s", projectRel(ds.projectPath, path), DiagnosticsSummary(entries)))
+	}
+	merged := make([]DiagnosticEntry, 0, len(ds.all)+len(entries))
+	for _, entry := range ds.all {
+		if filepath.Clean(entry.AbsPath) != path {
+			merged = append(merged, entry)
+		} else {
+			delete(ds.resolved, issueOfDiagnostic(entry).key()) // файл проверен заново
+		}
+	}
+	merged = append(merged, entries...)
+	sortDiagnostics(merged)
+
+	ds.setEntries(merged)
+}
+
+// GroupEditorDiagnostics раскладывает диагностики по абсолютным путям файлов,
+// переводя 1-based строки и колонки в 0-based позиции буфера.
+func GroupEditorDiagnostics(entries []DiagnosticEntry) map[string][]EditorDiagnostic {
+	byFile := make(map[string][]EditorDiagnostic)
+	for _, entry := range entries {
+		if entry.AbsPath == "" {
+			continue
+		}
+		diag := EditorDiagnostic{
+			Line:     max(entry.Line-1, 0),
+			Col:      max(entry.Column-1, 0),
+			EndLine:  max(entry.EndLine-1, 0),
+			EndCol:   max(entry.EndColumn-1, 0),
+			Severity: entry.Severity,
+			Code:     entry.Code,
+			Message:  entry.Message,
+			Fixes:    entry.Fixes,
+		}
+		if diag.EndLine < diag.Line {
+			diag.EndLine = diag.Line
+		}
+		path := filepath.Clean(entry.AbsPath)
+		byFile[path] = append(byFile[path], diag)
+	}
+	for path := range byFile {
+		sortEditorDiagnostics(byFile[path])
+	}
+	return byFile
+}
+
+// sortEditorDiagnostics задаёт полный порядок, чтобы одинаковые наборы
+// всегда сравнивались поэлементно.
+func sortEditorDiagnostics(diags []EditorDiagnostic) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i], diags[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Col != b.Col {
+			return a.Col < b.Col
+		}
+		if severityRank(a.Severity) != severityRank(b.Severity) {
+			return severityRank(a.Severity) < severityRank(b.Severity)
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return a.Message < b.Message
+	})
+}
+
+func sameEditorDiagnostic(a, b EditorDiagnostic) bool {
+	if a.Line != b.Line || a.Col != b.Col || a.EndLine != b.EndLine || a.EndCol != b.EndCol ||
+		a.Severity != b.Severity || a.Code != b.Code || a.Message != b.Message || len(a.Fixes) != len(b.Fixes) {
+		return false
+	}
+	for i := range a.Fixes {
+		if a.Fixes[i].ID != b.Fixes[i].ID || a.Fixes[i].Title != b.Fixes[i].Title {
+			return false
+		}
+	}
+	return true
+}
+
+// changedDiagnosticLines сравнивает два отсортированных набора и возвращает
+// отсортированные строки, чьи метки изменились. Пустой результат — наборы совпадают.
+func changedDiagnosticLines(old, next []EditorDiagnostic) []int {
+	byLine := func(diags []EditorDiagnostic) map[int][]EditorDiagnostic {
+		m := make(map[int][]EditorDiagnostic)
+		for _, d := range diags {
+			for line := d.Line; line <= d.EndLine; line++ {
+				m[line] = append(m[line], d)
+			}
+		}
+		return m
+	}
+	before, after := byLine(old), byLine(next)
+
+	var lines []int
+	for line, diags := range before {
+		if !sameDiagnosticSet(diags, after[line]) {
+			lines = append(lines, line)
+		}
+	}
+	for line := range after {
+		if _, ok := before[line]; !ok {
+			lines = append(lines, line)
+		}
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// SameEditorDiagnostics сообщает, совпадают ли два отсортированных набора диагностик файла.
+func SameEditorDiagnostics(a, b []EditorDiagnostic) bool {
+	return sameDiagnosticSet(a, b)
+}
+
+func sameDiagnosticSet(a, b []EditorDiagnostic) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !sameEditorDiagnostic(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// diagnosticsForPath возвращает копию диагностик файла, чтобы правки буфера
+// не портили общий кэш.
+func diagnosticsForPath(all map[string][]EditorDiagnostic, path string) []EditorDiagnostic {
+	diags := all[filepath.Clean(path)]
+	if len(diags) == 0 {
+		return nil
+	}
+	return append([]EditorDiagnostic(nil), diags...)
+}
+
+// diagnosticsOnLine возвращает самую серьёзную диагностику строки.
+func diagnosticsOnLine(diags []EditorDiagnostic, line int) (EditorDiagnostic, bool) {
+	var best EditorDiagnostic
+	found := false
+	for _, d := range diags {
+		if line < d.Line || line > d.EndLine {
+			continue
+		}
+		if !found || severityRank(d.Severity) < severityRank(best.Severity) {
+			best = d
+			found = true
+		}
+	}
+	return best, found
+}
+
+// adjacentDiagnosticLine ищет строку следующей (dir > 0) или предыдущей диагностики.
+func adjacentDiagnosticLine(diags []EditorDiagnostic, line, dir int) (int, bool) {
+	target, found := -1, false
+	for _, d := range diags {
+		switch {
+		case dir > 0 && d.Line > line && (!found || d.Line < target):
+			target, found = d.Line, true
+		case dir < 0 && d.Line < line && (!found || d.Line > target):
+			target, found = d.Line, true
+		}
+	}
+	return target, found
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case "error":
+		return 0
+	case "warning":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// renderDiagnosticMarker рисует символ гуттера для строки (или пробел).
+func renderDiagnosticMarker(diags []EditorDiagnostic, line int) string {
+	diag, ok := diagnosticsOnLine(diags, line)
+	if !ok {
+		return " "
+	}
+	return renderSeverityMarker(severityRank(diag.Severity))
+}
+
+// renderSeverityMarker рисует символ гуттера для ранга severityRank (-1 — пробел).
+func renderSeverityMarker(rank int) string {
+	color := diagInfoColor
+	switch rank {
+	case -1:
+		return " "
+	case 0:
+		color = diagErrorColor
+	case 1:
+		color = diagWarningColor
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render("●")
+}
@@ -1,11 +1,16 @@
 package screens
 
-import "time"
+import (
+	"time"
+
+	"surge-tui/internal/syntax"
+)
 
 type editorFileLoadedMsg struct {
-	Path  string
-	Lines []string
-	Stats editorStats
+	Path      string
+	Lines     []string
+	Highlight []syntax.Line
+	Stats     editorStats
 }
 
 type editorFileErrorMsg struct {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"surge-tui/internal/platform"
+	"surge-tui/internal/ui/components"
 )
 
 func (es *EditorScreen) renderLoading() string {
@@ -53,8 +54,8 @@ func (es *EditorScreen) renderHeader() string {
 	return lipgloss.NewStyle().
 		Width(es.Width()).
 		Bold(true).
-		Background(lipgloss.Color("#1E293B")).
-		Foreground(lipgloss.Color("#F8FAFC")).
+		Background(lipgloss.Color(barColor)).
+		Foreground(lipgloss.Color(textColor)).
 		Padding(0, 1).
 		Render(info)
 }
@@ -65,32 +66,44 @@ func (es *EditorScreen) renderBody() string {
 	start := es.scroll
 	end := min(start+height, len(es.lines))
 	for idx := start; idx < end; idx++ {
-		lineNumber := fmt.Sprintf("%6d ", idx+1)
-		content := es.lines[idx]
+		marker := renderDiagnosticMarker(es.diagnostics, idx)
+		lineNumber := fmt.Sprintf("%5d ", idx+1)
+		runes := []rune(es.lines[idx])
+		truncated := false
 		if !es.softWrap {
-			maxWidth := es.Width() - 8
-			if maxWidth > 0 && len([]rune(content)) > maxWidth {
-				content = string([]rune(content)[:maxWidth]) + "…"
+			maxWidth := es.Width() - 11 // padding, номер строки, полоса прокрутки и "…"
+			if maxWidth > 0 && len(runes) > maxWidth {
+				runes = runes[:maxWidth]
+				truncated = true
 			}
 		}
-		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#94A3B8")).Render(lineNumber)+content)
+		content := string(runes)
+		if es.highlightTheme != nil && idx < len(es.highlight) {
+			content = renderHighlightedLine(runes, es.highlight[idx].Tokens, es.highlightTheme, noDecor())
+		}
+		if truncated {
+			content += "…"
+		}
+		lines = append(lines, marker+lipgloss.NewStyle().Foreground(lipgloss.Color(DimTextColor)).Render(lineNumber)+content)
 	}
-	return lipgloss.NewStyle().
-		Width(es.Width()).
+	text := lipgloss.NewStyle().
+		Width(max(es.Width()-1, 1)).
 		Height(height).
 		Padding(0, 1).
 		Render(strings.Join(lines, "\n"))
+	bar := components.RenderScrollbar(len(es.lines), es.scroll, height, height)
+	return lipgloss.JoinHorizontal(lipgloss.Top, text, strings.Join(bar, "\n"))
 }
 
 func (es *EditorScreen) renderFooter() string {
 	status := es.statusLine()
 	if status == "" {
-		status = fmt.Sprintf("%s | %d/%d lines", es.filePath, es.scroll+1, es.stats.lineCount)
+		status = fmt.Sprintf("%s | %s", es.filePath, components.ScrollIndicator(es.scroll, es.contentHeight(), len(es.lines)))
 	}
 	return lipgloss.NewStyle().
 		Width(es.Width()).
-		Background(lipgloss.Color("#1E293B")).
-		Foreground(lipgloss.Color("#CBD5F5")).
+		Background(lipgloss.Color(barColor)).
+		Foreground(lipgloss.Color(barTextColor)).
 		Padding(0, 1).
 		Render(status)
 }
@@ -0,0 +1,386 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"surge-tui/internal/core/surge"
+	"surge-tui/internal/logging"
+)
+
+// Пакетное применение: пользователь отмечает фиксы (space / v), затем
+// применяет отмеченные одной командой. Фиксы применяются по файлам
+// последовательно, список перезагружается один раз в конце. Фикс без ID
+// применяется `surge fix --once`, если он единственный в своём файле,
+// иначе пропускается.
+
+type fixBatchResult struct {
+	title   string
+	file    string
+	issue   IssueRef
+	err     error
+	skipped bool // фикс без ID в файле с несколькими фиксами: неясно, какой применит --once
+}
+
+func (fs *FixModeScreen) isMarked(entry fixEntry) bool {
+	return fs.marked[fs.previewKey(entry)]
+}
+
+// toggleMarked отмечает или снимает отметку с текущего фикса.
+func (fs *FixModeScreen) toggleMarked() {
+	entry, ok := fs.selectedEntry()
+	if !ok {
+		return
+	}
+	key := fs.previewKey(entry)
+	if fs.marked[key] {
+		delete(fs.marked, key)
+	} else {
+		fs.marked[key] = true
+	}
+	fs.moveSelection(1)
+}
+
+// toggleVisibleMarked отмечает все видимые фиксы, а если они уже отмечены — снимает отметки.
+func (fs *FixModeScreen) toggleVisibleMarked() {
+	var visible []fixEntry
+	start, end := fs.visibleRange()
+	for _, row := range fs.rows[start:end] {
+		if !row.isHeader() {
+			visible = append(visible, fs.entries[row.entry])
+		}
+	}
+	allMarked := len(visible) > 0
+	for _, entry := range visible {
+		if !fs.isMarked(entry) {
+			allMarked = false
+			break
+		}
+	}
+	for _, entry := range visible {
+		key := fs.previewKey(entry)
+		if allMarked {
+			delete(fs.marked, key)
+		} else {
+			fs.marked[key] = true
+		}
+	}
+}
+
+func (fs *FixModeScreen) visibleRange() (int, int) {
+	start := min(max(fs.scroll, 0), len(fs.rows))
+	return start, min(start+max(fs.listHeight(), 1), len(fs.rows))
+}
+
+// markedEntries возвращает отмеченные фиксы, сгруппированные по файлам в порядке списка.
+func (fs *FixModeScreen) markedEntries() []fixEntry {
+	var files []string
+	byFile := make(map[string][]fixEntry)
+	// отмеченные, но скрытые фильтром фиксы тоже применяются
+	for _, entry := range fs.all {
+		if !fs.isMarked(entry) {
+			continue
+		}
+		file := filepath.Clean(entry.FilePath)
+		if _, ok := byFile[file]; !ok {
+			files = append(files, file)
+		}
+		byFile[file] = append(byFile[file], entry)
+	}
+	var out []fixEntry
+	for _, file := range files {
+		out = append(out, byFile[file]...)
+	}
+	return out
+}
+
+// applyMarked применяет отмеченные фиксы через `surge fix --id` по одному.
+func (fs *FixModeScreen) applyMarked() tea.Cmd {
+	entries := fs.markedEntries()
+	if len(entries) == 0 {
+		fs.setStatus("No fixes selected (space to select)")
+		return nil
+	}
+	return fs.applyEntries(entries, fmt.Sprintf("Applying %d selected fixes...", len(entries)))
+}
+
+type fixCodeApplyMsg struct {
+	code      string
+	entries   []fixEntry
+	confirmed bool
+}
+
+// confirmApplyCode собирает в текущем списке фиксы диагностик с тем же кодом,
+// что и у фикса под курсором, и спрашивает подтверждение со списком файлов.
+func (fs *FixModeScreen) confirmApplyCode() tea.Cmd {
+	entry, ok := fs.selectedEntry()
+	if !ok {
+		return nil
+	}
+	code := entry.Diagnostic.Code
+	if code == "" {
+		fs.setStatus("Diagnostic has no code")
+		return nil
+	}
+	var entries []fixEntry
+	var files []string
+	seen := make(map[string]bool)
+	for _, e := range fs.entries {
+		if e.Diagnostic.Code != code {
+			continue
+		}
+		entries = append(entries, e)
+		if file := filepath.Clean(e.FilePath); !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	if fs.confirm == nil {
+		return fs.applyEntries(entries, fmt.Sprintf("Applying %d %s fixes...", len(entries), code))
+	}
+	var lines []string
+	for i, file := range files {
+		if i == markedListLimit {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(files)-i))
+			break
+		}
+		lines = append(lines, "  "+file)
+	}
+	fs.confirm.Title = "Apply " + code + " Fixes"
+	fs.confirm.Description = fmt.Sprintf("Apply %d %s fixes in %d files?\n%s", len(entries), code, len(files), strings.Join(lines, "\n"))
+	ch := fs.confirm.Show()
+	return func() tea.Msg {
+		return fixCodeApplyMsg{code: code, entries: entries, confirmed: <-ch}
+	}
+}
+
+func (fs *FixModeScreen) handleCodeApply(msg fixCodeApplyMsg) tea.Cmd {
+	if !msg.confirmed {
+		fs.setStatus("Cancelled")
+		return nil
+	}
+	return fs.applyEntries(msg.entries, fmt.Sprintf("Applying %d %s fixes...", len(msg.entries), msg.code))
+}
+
+// fixBatch — идущее пакетное применение. Каждый фикс применяется отдельной
+// командой Bubble Tea, чтобы строка статуса показывала ход.
+type fixBatch struct {
+	ctx     context.Context
+	token   int // операция в opGuard экрана
+	label   string
+	entries []fixEntry
+	once    map[string]bool // файлы, где фикс без ID применяется `surge fix --once`
+	results []fixBatchResult
+}
+
+type fixBatchStepMsg struct {
+	batch  *fixBatch
+	result fixBatchResult
+}
+
+// applyEntries применяет фиксы последовательно и сообщает итог по каждому.
+func (fs *FixModeScreen) applyEntries(entries []fixEntry, label string) tea.Cmd {
+	if fs.client == nil || len(entries) == 0 {
+		return nil
+	}
+	retry := func() tea.Cmd { return fs.applyEntries(entries, label) }
+	if cmd, held := fs.holdForDirty(fixFiles(entries), retry); held {
+		return cmd
+	}
+	ctx, token, ok := fs.beginApply("applying fixes")
+	if !ok {
+		return nil
+	}
+	ctx, waitStall := fs.watchStalls(ctx)
+	fs.batch = &fixBatch{ctx: ctx, token: token, label: label, entries: entries, once: fs.onceFiles()}
+	return tea.Batch(fs.batch.step(fs.client), waitStall)
+}
+
+// onceFiles возвращает файлы с единственным фиксом в списке: только для них
+// `surge fix --once` заведомо применит именно этот фикс.
+func (fs *FixModeScreen) onceFiles() map[string]bool {
+	counts := make(map[string]int)
+	for _, entry := range fs.all {
+		counts[filepath.Clean(entry.FilePath)]++
+	}
+	once := make(map[string]bool)
+	for file, n := range counts {
+		if n == 1 {
+			once[file] = true
+		}
+	}
+	return once
+}
+
+// step применяет следующий фикс пакета.
+func (b *fixBatch) step(client surge.SurgeRunner) tea.Cmd {
+	entry := b.entries[len(b.results)]
+	ctx, once := b.ctx, b.once[filepath.Clean(entry.FilePath)]
+	return func() tea.Msg {
+		result := fixBatchResult{title: entry.Fix.Title, file: entry.FilePath, issue: issueOfFix(entry)}
+		path := entry.FilePath
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		switch {
+		case ctx.Err() != nil:
+			result.err = ctx.Err()
+		case entry.Fix.ID != "":
+			result.err = client.ApplyFixByID(ctx, path, entry.Fix.ID)
+		case once:
+			result.err = client.ApplyOneFix(ctx, path)
+		default:
+			result.skipped = true
+		}
+		return fixBatchStepMsg{batch: b, result: result}
+	}
+}
+
+func (b *fixBatch) done() bool {
+	return len(b.results) >= len(b.entries)
+}
+
+// progress — строка статуса во время применения, например "Applying 5 fixes... 2/5 (main.sg)".
+func (b *fixBatch) progress() string {
+	if b.done() {
+		return b.label
+	}
+	return fmt.Sprintf("%s %d/%d (%s)", b.label, len(b.results), len(b.entries), filepath.Base(b.entries[len(b.results)].FilePath))
+}
+
+// handleBatchStep учитывает результат очередного фикса и запускает следующий;
+// после последнего показывает итог и перезагружает список.
+func (fs *FixModeScreen) handleBatchStep(msg fixBatchStepMsg) tea.Cmd {
+	batch := msg.batch
+	if batch != fs.batch {
+		return nil
+	}
+	// отменённый Esc фикс не считается: пакет заканчивается на предыдущем
+	cancelled := errors.Is(msg.result.err, context.Canceled)
+	if !cancelled {
+		batch.results = append(batch.results, msg.result)
+	}
+	if !cancelled && !batch.done() {
+		return batch.step(fs.client)
+	}
+	fs.op.finish(fs.ops, batch.token)
+	fs.batch = nil
+	fs.stopStall()
+	for _, r := range batch.results {
+		switch {
+		case r.skipped:
+			logging.Warnf("fix skipped: %s in %s (no fix ID, file has several fixes)", r.title, r.file)
+		case r.err != nil:
+			logging.Warnf("fix failed: %s in %s: %v", r.title, r.file, r.err)
+		}
+	}
+	summary := batchSummary(batch.results)
+	if cancelled {
+		summary = "Cancelled — " + summary
+	}
+	fs.setStatus(summary)
+	fs.cache.reset()
+	var issues []IssueRef
+	var files []string
+	for _, r := range batch.results {
+		if r.err == nil && !r.skipped {
+			issues = append(issues, r.issue)
+			if !containsPath(files, r.file) {
+				files = append(files, r.file)
+			}
+		}
+	}
+	fs.reloadBuffers(files)
+	resolved := issuesResolved(issues)
+	queued := fs.queuedLoad
+	fs.queuedLoad = false
+	if file := batchFile(batch.results); file != "" && !queued {
+		return tea.Batch(resolved, fs.loadFileFixes(file))
+	}
+	return tea.Batch(resolved, fs.loadFixes())
+}
+
+// batchFile возвращает файл, если все фиксы пакета были в одном файле.
+func batchFile(results []fixBatchResult) string {
+	if len(results) == 0 {
+		return ""
+	}
+	file := results[0].file
+	for _, r := range results[1:] {
+		if !samePath(r.file, file) {
+			return ""
+		}
+	}
+	return file
+}
+
+// batchSummary формирует итог, например
+// "Applied 9/10 fixes; failed: Remove import (exit status 1)". Если фиксы
+// затронули несколько файлов, итог дополняется разбивкой по файлам.
+func batchSummary(results []fixBatchResult) string {
+	applied := 0
+	var failed, skipped []string
+	for _, r := range results {
+		title := r.title
+		if title == "" {
+			title = filepath.Base(r.file)
+		}
+		switch {
+		case r.skipped:
+			skipped = append(skipped, title)
+		case r.err != nil:
+			failed = append(failed, fmt.Sprintf("%s (%v)", title, r.err))
+		default:
+			applied++
+		}
+	}
+	summary := fmt.Sprintf("Applied %d/%d fixes", applied, len(results))
+	if files := fileSummary(results); files != "" {
+		summary += " (" + files + ")"
+	}
+	if len(skipped) > 0 {
+		summary += "; skipped without fix ID: " + strings.Join(skipped, ", ")
+	}
+	if len(failed) > 0 {
+		summary += "; failed: " + strings.Join(failed, ", ")
+	}
+	return summary
+}
+
+// fileSummary — итог по файлам вида "main.sg 2/2, util.sg 1/3"; пусто для одного файла.
+func fileSummary(results []fixBatchResult) string {
+	var files []string
+	total := make(map[string]int)
+	applied := make(map[string]int)
+	for _, r := range results {
+		file := filepath.Clean(r.file)
+		if _, ok := total[file]; !ok {
+			files = append(files, file)
+		}
+		total[file]++
+		if r.err == nil && !r.skipped {
+			applied[file]++
+		}
+	}
+	if len(files) < 2 {
+		return ""
+	}
+	parts := make([]string, 0, len(files))
+	for _, file := range files {
+		parts = append(parts, fmt.Sprintf("%s %d/%d", filepath.Base(file), applied[file], total[file]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// selectionLabel возвращает счётчик отмеченных фиксов для строки статуса.
+func (fs *FixModeScreen) selectionLabel() string {
+	if len(fs.marked) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d selected", len(fs.marked))
+}
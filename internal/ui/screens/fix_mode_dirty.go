@@ -0,0 +1,160 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/ui/components"
+)
+
+// surge fix пишет файлы на диск мимо открытых вкладок. Перед фиксом в файле
+// с несохранёнными правками Fix Mode спрашивает, сохранить вкладку или
+// отбросить её правки; после фикса вкладки затронутых файлов перечитываются
+// с диска, курсор остаётся на той же строке текста.
+
+// OpenBuffers — открытые вкладки редактора с точки зрения Fix Mode.
+type OpenBuffers interface {
+	UnsavedFiles() []string
+	SaveFile(path string) (tea.Cmd, error)
+	ReloadFile(path string) error // отбрасывает правки вкладки; неоткрытый файл пропускается
+}
+
+const (
+	fixDirtySave = iota
+	fixDirtyDiscard
+)
+
+// fixDirtyChoiceMsg — ответ на вопрос о несохранённых вкладках; retry
+// повторяет применение фиксов.
+type fixDirtyChoiceMsg struct {
+	choice int
+	files  []string
+	retry  func() tea.Cmd
+}
+
+func newFixDirtyDialog() *components.ChoiceDialog {
+	return components.NewChoiceDialog("Unsaved Changes", "", "Save & Apply", "Discard & Apply", "Cancel")
+}
+
+// SetBuffers подключает вкладки редактора.
+func (fs *FixModeScreen) SetBuffers(buffers OpenBuffers) {
+	fs.buffers = buffers
+}
+
+// CapturingKey отдаёт экрану все нажатия, пока открыт диалог: иначе Esc
+// ушёл бы в реестр команд и сменил экран.
+func (fs *FixModeScreen) CapturingKey() bool {
+	return (fs.confirm != nil && fs.confirm.Visible) || fs.dirtyDialog.Visible
+}
+
+// dirtyFiles — файлы фиксов, открытые во вкладках с несохранёнными правками.
+func (fs *FixModeScreen) dirtyFiles(files []string) []string {
+	if fs.buffers == nil {
+		return nil
+	}
+	unsaved := fs.buffers.UnsavedFiles()
+	var dirty []string
+	for _, file := range files {
+		path := absFixPath(file)
+		for _, open := range unsaved {
+			if samePath(open, path) && !containsPath(dirty, path) {
+				dirty = append(dirty, path)
+			}
+		}
+	}
+	return dirty
+}
+
+// holdForDirty спрашивает, что сделать с несохранёнными вкладками файлов
+// files, и возвращает true, если применение ждёт ответа; retry вызывается
+// после сохранения или отката вкладок.
+func (fs *FixModeScreen) holdForDirty(files []string, retry func() tea.Cmd) (tea.Cmd, bool) {
+	dirty := fs.dirtyFiles(files)
+	if len(dirty) == 0 {
+		return nil, false
+	}
+	names := make([]string, 0, len(dirty))
+	for i, file := range dirty {
+		if i == markedListLimit {
+			names = append(names, fmt.Sprintf("…and %d more", len(dirty)-i))
+			break
+		}
+		names = append(names, "  "+projectRel(fs.projectPath, file))
+	}
+	fs.dirtyDialog.Description = "Fixes write to disk, but these tabs have unsaved changes:\n" + strings.Join(names, "\n")
+	ch := fs.dirtyDialog.Show()
+	return func() tea.Msg {
+		return fixDirtyChoiceMsg{choice: <-ch, files: dirty, retry: retry}
+	}, true
+}
+
+func (fs *FixModeScreen) handleDirtyChoice(msg fixDirtyChoiceMsg) tea.Cmd {
+	if fs.buffers == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	switch msg.choice {
+	case fixDirtySave:
+		for _, file := range msg.files {
+			cmd, err := fs.buffers.SaveFile(file)
+			if err != nil {
+				fs.setStatus(fmt.Sprintf("Fix not applied: cannot save %s: %v", filepath.Base(file), err))
+				return tea.Batch(cmds...)
+			}
+			cmds = append(cmds, cmd)
+		}
+	case fixDirtyDiscard:
+		for _, file := range msg.files {
+			if err := fs.buffers.ReloadFile(file); err != nil {
+				fs.setStatus(fmt.Sprintf("Fix not applied: cannot reload %v", err))
+				return nil
+			}
+		}
+	default:
+		fs.setStatus("Cancelled")
+		return nil
+	}
+	return tea.Batch(append(cmds, msg.retry())...)
+}
+
+// reloadBuffers перечитывает вкладки файлов, изменённых фиксами.
+func (fs *FixModeScreen) reloadBuffers(files []string) {
+	if fs.buffers == nil {
+		return
+	}
+	for _, file := range files {
+		if err := fs.buffers.ReloadFile(absFixPath(file)); err != nil {
+			fs.setStatus(fmt.Sprintf("Fix applied, but the tab failed to reload: %v", err))
+		}
+	}
+}
+
+// fixFiles — файлы фиксов без повторов в порядке списка.
+func fixFiles(entries []fixEntry) []string {
+	var files []string
+	for _, entry := range entries {
+		if !containsPath(files, entry.FilePath) {
+			files = append(files, entry.FilePath)
+		}
+	}
+	return files
+}
+
+func containsPath(paths []string, path string) bool {
+	for _, p := range paths {
+		if samePath(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// absFixPath — абсолютный путь файла фикса, как его получает surge fix.
+func absFixPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,255 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Фильтр Fix Mode сужает fs.entries, полный список хранится в fs.all.
+// Выделение и прокрутка всегда относятся к отфильтрованному списку.
+
+type fixFilter struct {
+	query         string // подстрока пути файла или заголовка фикса
+	severity      string // "", "error" или "warning"
+	kind          string
+	applicability string
+}
+
+func (f fixFilter) active() bool {
+	return f.query != "" || f.severity != "" || f.kind != "" || f.applicability != ""
+}
+
+func (f fixFilter) matches(entry fixEntry) bool {
+	if f.severity != "" && !strings.EqualFold(entry.Diagnostic.Severity, f.severity) {
+		return false
+	}
+	if f.kind != "" && !strings.EqualFold(entry.Fix.Kind, f.kind) {
+		return false
+	}
+	if f.applicability != "" && !strings.EqualFold(entry.Fix.Applicability, f.applicability) {
+		return false
+	}
+	if f.query == "" {
+		return true
+	}
+	query := strings.ToLower(f.query)
+	return strings.Contains(strings.ToLower(entry.FilePath), query) ||
+		strings.Contains(strings.ToLower(entry.Fix.Title), query)
+}
+
+// describe возвращает краткое описание фильтра для строки статуса.
+func (f fixFilter) describe() string {
+	var parts []string
+	if f.query != "" {
+		parts = append(parts, fmt.Sprintf("%q", f.query))
+	}
+	if f.severity != "" {
+		parts = append(parts, "severity: "+f.severity)
+	}
+	if f.kind != "" {
+		parts = append(parts, "kind: "+f.kind)
+	}
+	if f.applicability != "" {
+		parts = append(parts, "applicability: "+f.applicability)
+	}
+	return "Filter: " + strings.Join(parts, ", ") + " (Esc to clear)"
+}
+
+func newFixFilterInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.Placeholder = "file or fix title"
+	return ti
+}
+
+// setEntries заменяет полный список фиксов, сохраняя позицию по возможности.
+func (fs *FixModeScreen) setEntries(entries []fixEntry) {
+	keep := fs.currentKey()
+	fs.all = entries
+	fs.refilter(keep)
+}
+
+func (fs *FixModeScreen) currentKey() string {
+	row, ok := fs.selectedRow()
+	if !ok {
+		return ""
+	}
+	return fs.rowKey(row)
+}
+
+// setFilter применяет новый фильтр. При включении запоминается текущая
+// позиция, а при полном сбросе фильтра курсор возвращается к ней.
+func (fs *FixModeScreen) setFilter(next fixFilter) {
+	keep := fs.currentKey()
+	switch {
+	case next.active() && !fs.filter.active():
+		fs.restoreKey = keep
+	case !next.active() && fs.filter.active():
+		if fs.restoreKey != "" {
+			keep = fs.restoreKey
+		}
+		fs.restoreKey = ""
+	}
+	fs.filter = next
+	fs.refilter(keep)
+}
+
+// refilter пересобирает видимый список и ставит курсор на keep, если он виден.
+func (fs *FixModeScreen) refilter(keep string) {
+	if !fs.filter.active() {
+		fs.entries = fs.all
+	} else {
+		fs.entries = make([]fixEntry, 0, len(fs.all))
+		for _, entry := range fs.all {
+			if fs.filter.matches(entry) {
+				fs.entries = append(fs.entries, entry)
+			}
+		}
+	}
+	fs.rebuildRows()
+	if !fs.selectRowByKey(keep) {
+		fs.setSelection(fs.selected)
+	}
+}
+
+func (fs *FixModeScreen) startFilterInput() tea.Cmd {
+	fs.filtering = true
+	fs.filterInput.SetValue(fs.filter.query)
+	fs.filterInput.CursorEnd()
+	return fs.filterInput.Focus()
+}
+
+// handleFilterKey обрабатывает ввод в строке фильтра: список сужается по мере набора.
+func (fs *FixModeScreen) handleFilterKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		fs.filtering = false
+		fs.filterInput.Blur()
+		return fs, nil
+	case tea.KeyEsc:
+		fs.cancelFilterInput()
+		return fs, nil
+	case tea.KeyUp:
+		fs.moveSelection(-1)
+		return fs, nil
+	case tea.KeyDown:
+		fs.moveSelection(1)
+		return fs, nil
+	}
+	var cmd tea.Cmd
+	fs.filterInput, cmd = fs.filterInput.Update(msg)
+	if value := strings.TrimSpace(fs.filterInput.Value()); value != fs.filter.query {
+		next := fs.filter
+		next.query = value
+		fs.setFilter(next)
+	}
+	return fs, cmd
+}
+
+// cancelFilterInput закрывает строку фильтра и сбрасывает введённую подстроку.
+func (fs *FixModeScreen) cancelFilterInput() {
+	fs.filtering = false
+	fs.filterInput.Blur()
+	next := fs.filter
+	next.query = ""
+	fs.setFilter(next)
+}
+
+// HandleGlobalEsc отменяет применение фиксов или замолчавшую операцию
+// surge, закрывает строку фильтра или сбрасывает активный фильтр вместо
+// возврата на экран проекта.
+func (fs *FixModeScreen) HandleGlobalEsc() (bool, tea.Cmd) {
+	if fs.confirm != nil && fs.confirm.Visible {
+		return false, nil
+	}
+	if fs.stallNote != "" || fs.op.mutating() {
+		fs.cancelOperation()
+		return true, nil
+	}
+	if fs.filtering {
+		fs.cancelFilterInput()
+		return true, nil
+	}
+	if fs.filter.active() {
+		fs.clearFilter()
+		return true, nil
+	}
+	return false, nil
+}
+
+// cycleSeverityFilter переключает фильтр: все → только ошибки → только предупреждения.
+func (fs *FixModeScreen) cycleSeverityFilter() {
+	next := fs.filter
+	switch next.severity {
+	case "":
+		next.severity = "error"
+	case "error":
+		next.severity = "warning"
+	default:
+		next.severity = ""
+	}
+	fs.setFilter(next)
+}
+
+func (fs *FixModeScreen) cycleKindFilter() {
+	next := fs.filter
+	next.kind = nextFilterValue(fs.distinctValues(func(e fixEntry) string { return e.Fix.Kind }), next.kind)
+	fs.setFilter(next)
+}
+
+func (fs *FixModeScreen) cycleApplicabilityFilter() {
+	next := fs.filter
+	next.applicability = nextFilterValue(fs.distinctValues(func(e fixEntry) string { return e.Fix.Applicability }), next.applicability)
+	fs.setFilter(next)
+}
+
+func (fs *FixModeScreen) clearFilter() {
+	fs.setFilter(fixFilter{})
+}
+
+// distinctValues собирает непустые значения поля по полному списку.
+func (fs *FixModeScreen) distinctValues(field func(fixEntry) string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, entry := range fs.all {
+		value := strings.ToLower(field(entry))
+		if value != "" && !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// nextFilterValue возвращает следующее значение по кругу, "" означает «все».
+func nextFilterValue(values []string, current string) string {
+	if current == "" {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+	for i, value := range values {
+		if value == current && i+1 < len(values) {
+			return values[i+1]
+		}
+	}
+	return ""
+}
+
+// listTitle возвращает заголовок списка: при активном фильтре — "Showing 12 of 240".
+func (fs *FixModeScreen) listTitle() string {
+	in := ""
+	if fs.scope != "" {
+		in = " in " + scopeLabel(fs.projectPath, fs.scope)
+	}
+	if !fs.filter.active() {
+		return "Fixes" + in
+	}
+	return fmt.Sprintf("Showing %d of %d%s", len(fs.entries), len(fs.all), in)
+}
@@ -0,0 +1,112 @@
+package screens
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"surge-tui/internal/core/surge"
+	"surge-tui/internal/core/surge/testsupport"
+)
+
+// fixResponse — ответ surge diag с одной диагностикой в path и фиксами fixIDs.
+func fixResponse(path string, fixIDs ...string) *surge.DiagResponse {
+	diag := surge.DiagnosticJSON{
+		Severity: "warning",
+		Code:     "W100",
+		Message:  "unused variable",
+		Location: surge.LocationJSON{File: path, StartLine: 1, StartCol: 5, EndLine: 1, EndCol: 6},
+	}
+	for _, id := range fixIDs {
+		diag.Fixes = append(diag.Fixes, surge.FixJSON{ID: id, Title: "remove " + id, Applicability: "safe"})
+	}
+	return &surge.DiagResponse{Single: &surge.DiagnosticsOutput{Diagnostics: []surge.DiagnosticJSON{diag}, Count: 1}}
+}
+
+// newFixModeFlow открывает Fix Mode над проектом с одним файлом и ждёт первой загрузки.
+func newFixModeFlow(t *testing.T, runner *testsupport.FakeRunner) (*FixModeScreen, string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.sg")
+	if err := os.WriteFile(path, []byte("let x = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runner.Default = fixResponse(path, "fix-1")
+
+	fs := NewFixModeScreen(dir, runner)
+	fs.SetSize(120, 30)
+	fs.Update(awaitMsg[fixesLoadedMsg](t, fs.Init()))
+	if fs.loading || fs.err != nil {
+		t.Fatalf("load finished with loading=%v err=%v", fs.loading, fs.err)
+	}
+	if len(fs.entries) != 1 || fs.entries[0].Fix.ID != "fix-1" {
+		t.Fatalf("entries = %+v, want one fix-1", fs.entries)
+	}
+	fs.focusEntry(0)
+	return fs, path
+}
+
+// statusShown сообщает, попало ли сообщение text в историю строки статуса.
+func statusShown(q *statusQueue, text string) bool {
+	for _, line := range q.recent() {
+		if strings.HasSuffix(line, "  "+text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFixModeLoadAndApply(t *testing.T) {
+	runner := testsupport.NewFakeRunner(nil)
+	fs, path := newFixModeFlow(t, runner)
+	if got := runner.Diagnosed(); len(got) != 1 {
+		t.Fatalf("Diagnose calls = %v, want one", got)
+	}
+
+	// после фикса surge больше не предлагает исправлений для файла
+	runner.Default = &surge.DiagResponse{Single: &surge.DiagnosticsOutput{}}
+	_, cmd := fs.Update(keyMsg("a"))
+	_, cmd = fs.Update(awaitMsg[fixAppliedMsg](t, cmd))
+	if got := runner.AppliedFixIDs(); !reflect.DeepEqual(got, []string{"fix-1"}) {
+		t.Errorf("applied fixes = %v, want [fix-1]", got)
+	}
+	if !statusShown(&fs.status, "Fix applied") {
+		t.Errorf("status history %q lacks %q", fs.status.recent(), "Fix applied")
+	}
+
+	// список файла перезагружается, и применённый фикс пропадает
+	if fs.reloadingFile == "" {
+		t.Fatalf("applying did not reload the file's fixes")
+	}
+	fs.Update(awaitMsg[fixesLoadedMsg](t, cmd))
+	if len(fs.entries) != 0 {
+		t.Errorf("entries after apply = %+v, want none", fs.entries)
+	}
+	if got := runner.Diagnosed(); len(got) != 2 || got[1] != path {
+		t.Errorf("Diagnose calls = %v, want a reload of %s", got, path)
+	}
+}
+
+func TestFixModeApplyFailureKeepsList(t *testing.T) {
+	runner := testsupport.NewFakeRunner(nil)
+	fs, _ := newFixModeFlow(t, runner)
+
+	runner.FixErr = errors.New("edit conflict")
+	_, cmd := fs.Update(keyMsg("a"))
+	fs.Update(awaitMsg[fixAppliedMsg](t, cmd))
+	if fs.loading || fs.reloadingFile != "" {
+		t.Errorf("failed apply reloaded the list")
+	}
+	if !statusShown(&fs.status, "Failed to apply fix: edit conflict") {
+		t.Errorf("status history %q lacks the failure", fs.status.recent())
+	}
+	if len(fs.entries) != 1 {
+		t.Errorf("entries = %+v, want the fix kept", fs.entries)
+	}
+	if fs.op.busy() {
+		t.Errorf("operation still busy after the failure")
+	}
+}
@@ -0,0 +1,153 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Список Fix Mode выводится группами по файлам: строка-заголовок с числом
+// фиксов, под ней фиксы файла. Свёрнутая группа показывает только заголовок,
+// поэтому навигация по строкам её пропускает.
+
+type fixRow struct {
+	file  string
+	entry int // индекс в fs.entries, -1 для заголовка группы
+	count int // число фиксов группы (для заголовка)
+}
+
+func (r fixRow) isHeader() bool {
+	return r.entry < 0
+}
+
+// rebuildRows пересобирает строки списка; fs.entries отсортирован по файлу,
+// поэтому фиксы одного файла идут подряд.
+func (fs *FixModeScreen) rebuildRows() {
+	fs.rows = fs.rows[:0]
+	for i := 0; i < len(fs.entries); {
+		file := fs.entries[i].FilePath
+		j := i
+		for j < len(fs.entries) && fs.entries[j].FilePath == file {
+			j++
+		}
+		fs.rows = append(fs.rows, fixRow{file: file, entry: -1, count: j - i})
+		if !fs.collapsed[file] {
+			for k := i; k < j; k++ {
+				fs.rows = append(fs.rows, fixRow{file: file, entry: k})
+			}
+		}
+		i = j
+	}
+}
+
+func (fs *FixModeScreen) selectedRow() (fixRow, bool) {
+	if fs.selected < 0 || fs.selected >= len(fs.rows) {
+		return fixRow{}, false
+	}
+	return fs.rows[fs.selected], true
+}
+
+// selectedEntry возвращает фикс под курсором; на заголовке группы — false.
+func (fs *FixModeScreen) selectedEntry() (fixEntry, bool) {
+	row, ok := fs.selectedRow()
+	if !ok || row.isHeader() {
+		return fixEntry{}, false
+	}
+	return fs.entries[row.entry], true
+}
+
+// rowKey — устойчивый ключ строки для восстановления позиции после перестроения.
+func (fs *FixModeScreen) rowKey(row fixRow) string {
+	if row.isHeader() {
+		return "group::" + filepath.Clean(row.file)
+	}
+	return fs.previewKey(fs.entries[row.entry])
+}
+
+// selectRowByKey ставит курсор на строку с ключом key.
+func (fs *FixModeScreen) selectRowByKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, row := range fs.rows {
+		if fs.rowKey(row) == key {
+			fs.setSelection(i)
+			return true
+		}
+	}
+	return false
+}
+
+// setGroupCollapsed сворачивает или разворачивает группу файла, оставляя курсор на её заголовке.
+func (fs *FixModeScreen) setGroupCollapsed(file string, collapsed bool) {
+	if collapsed {
+		fs.collapsed[file] = true
+	} else {
+		delete(fs.collapsed, file)
+	}
+	fs.rebuildRows()
+	fs.selectRowByKey(fs.rowKey(fixRow{file: file, entry: -1}))
+}
+
+// handleGroupKey обрабатывает space/←/→ на строках списка.
+// Space на фиксе переключает отметку, на заголовке — сворачивает группу.
+func (fs *FixModeScreen) handleGroupKey(key string) {
+	row, ok := fs.selectedRow()
+	if !ok {
+		return
+	}
+	switch key {
+	case "space":
+		if row.isHeader() {
+			fs.setGroupCollapsed(row.file, !fs.collapsed[row.file])
+			return
+		}
+		fs.toggleMarked()
+	case "left", "h":
+		if row.isHeader() && fs.collapsed[row.file] {
+			return
+		}
+		fs.setGroupCollapsed(row.file, true)
+	case "right", "l":
+		if fs.collapsed[row.file] {
+			fs.setGroupCollapsed(row.file, false)
+		}
+	}
+}
+
+// groupEntries возвращает видимые фиксы файла.
+func (fs *FixModeScreen) groupEntries(file string) []fixEntry {
+	var out []fixEntry
+	for _, entry := range fs.entries {
+		if entry.FilePath == file {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// applyGroup применяет все видимые фиксы файла под курсором.
+func (fs *FixModeScreen) applyGroup() tea.Cmd {
+	row, ok := fs.selectedRow()
+	if !ok {
+		return nil
+	}
+	entries := fs.groupEntries(row.file)
+	return fs.applyEntries(entries, fmt.Sprintf("Applying %d fixes in %s...", len(entries), filepath.Base(row.file)))
+}
+
+// focusEntry ставит курсор на фикс с индексом index в fs.entries, разворачивая его группу.
+func (fs *FixModeScreen) focusEntry(index int) {
+	file := fs.entries[index].FilePath
+	if fs.collapsed[file] {
+		delete(fs.collapsed, file)
+		fs.rebuildRows()
+	}
+	for i, row := range fs.rows {
+		if row.entry == index {
+			fs.setSelection(i)
+			return
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package screens
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Навигация по списку фиксов и фокусировка на фиксе, выбранном на других экранах.
+
+func (fs *FixModeScreen) FocusFix(filePath, fixID string) {
+	if filePath == "" && fixID == "" {
+		return
+	}
+	cleanFile := filePath
+	if cleanFile != "" {
+		cleanFile = filepath.Clean(cleanFile)
+	}
+	req := fixFocusRequest{
+		File:  cleanFile,
+		FixID: fixID,
+	}
+	if fs.applyFocus(req) {
+		fs.pendingFocus = nil
+		return
+	}
+	fs.pendingFocus = &req
+}
+
+// applyPendingFocus выбирает отложенную цель после загрузки списка. Фикс
+// ждёт, пока не появится; проблема с экрана диагностики ищется один раз.
+func (fs *FixModeScreen) applyPendingFocus() {
+	if fs.pendingFocus == nil {
+		return
+	}
+	if fs.applyFocus(*fs.pendingFocus) || !fs.pendingFocus.Issue.IsZero() {
+		fs.pendingFocus = nil
+	}
+}
+
+// CurrentIssue возвращает проблему фикса под курсором.
+func (fs *FixModeScreen) CurrentIssue() (IssueRef, bool) {
+	entry, ok := fs.selectedEntry()
+	if !ok {
+		return IssueRef{}, false
+	}
+	return issueOfFix(entry), true
+}
+
+// FocusIssue выбирает фикс проблемы ref, если он есть в списке. Отложенный
+// запрос FocusFix точнее и остаётся в силе.
+func (fs *FixModeScreen) FocusIssue(ref IssueRef) {
+	if ref.IsZero() || fs.pendingFocus != nil {
+		return
+	}
+	if current, ok := fs.CurrentIssue(); ok && current.Matches(ref) {
+		return
+	}
+	req := fixFocusRequest{Issue: ref}
+	if !fs.applyFocus(req) {
+		fs.pendingFocus = &req // список ещё загружается или придёт из OnEnter
+	}
+}
+
+// focusIssue ставит курсор на фикс проблемы ref или, если его нет, на
+// первый фикс с тем же кодом в том же файле.
+func (fs *FixModeScreen) focusIssue(ref IssueRef) bool {
+	exact, near := -1, -1
+	for i, entry := range fs.entries {
+		issue := issueOfFix(entry)
+		if issue.Matches(ref) {
+			exact = i
+			break
+		}
+		if near < 0 && issue.sameCode(ref) {
+			near = i
+		}
+	}
+	index := exact
+	if index < 0 {
+		index = near
+	}
+	if index < 0 {
+		if fs.filter.active() && fs.hasIssue(ref) {
+			fs.clearFilter()
+			return fs.focusIssue(ref)
+		}
+		return false
+	}
+	fs.focusEntry(index)
+	fs.ensureSelectionVisible()
+	return true
+}
+
+// hasIssue сообщает, что у проблемы есть фикс, пусть и скрытый фильтром.
+func (fs *FixModeScreen) hasIssue(ref IssueRef) bool {
+	for _, entry := range fs.all {
+		if issueOfFix(entry).sameCode(ref) {
+			return true
+		}
+	}
+	return false
+}
+
+func (fs *FixModeScreen) applyFocus(req fixFocusRequest) bool {
+	if len(fs.all) == 0 {
+		return false
+	}
+	if !req.Issue.IsZero() {
+		return fs.focusIssue(req.Issue)
+	}
+	cleanFile := req.File
+	if cleanFile != "" {
+		cleanFile = filepath.Clean(cleanFile)
+	}
+	index := -1
+	if req.FixID != "" {
+		for i, entry := range fs.entries {
+			if strings.EqualFold(entry.Fix.ID, req.FixID) {
+				if cleanFile == "" || samePath(entry.FilePath, cleanFile) {
+					index = i
+					break
+				}
+			}
+		}
+	}
+	if index == -1 && cleanFile != "" {
+		for i, entry := range fs.entries {
+			if samePath(entry.FilePath, cleanFile) {
+				index = i
+				break
+			}
+		}
+	}
+	if index == -1 {
+		if fs.filter.active() {
+			// цель скрыта фильтром: сбрасываем его и ищем снова
+			fs.clearFilter()
+			return fs.applyFocus(req)
+		}
+		return false
+	}
+	// свёрнутая группа цели разворачивается
+	fs.focusEntry(index)
+	fs.ensureSelectionVisible()
+	return true
+}
+
+func (fs *FixModeScreen) moveSelection(delta int) {
+	if len(fs.rows) == 0 {
+		fs.selected = 0
+		fs.scroll = 0
+		return
+	}
+	fs.selected += delta
+	fs.detailScroll = 0
+	if fs.selected < 0 {
+		fs.selected = 0
+	}
+	if fs.selected >= len(fs.rows) {
+		fs.selected = len(fs.rows) - 1
+	}
+	fs.ensureSelectionVisible()
+}
+
+func (fs *FixModeScreen) setSelection(index int) {
+	if len(fs.rows) == 0 {
+		fs.selected = 0
+		fs.scroll = 0
+		return
+	}
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(fs.rows) {
+		index = len(fs.rows) - 1
+	}
+	if index != fs.selected {
+		fs.detailScroll = 0
+	}
+	fs.selected = index
+	fs.ensureSelectionVisible()
+}
+
+func (fs *FixModeScreen) pageSize() int {
+	h := fs.listHeight()
+	if h <= 1 {
+		return 1
+	}
+	return h - 1
+}
+
+func (fs *FixModeScreen) ensureSelectionVisible() {
+	visible := fs.listHeight()
+	if visible <= 0 {
+		fs.scroll = 0
+		return
+	}
+	if fs.selected < fs.scroll {
+		fs.scroll = fs.selected
+	} else if fs.selected >= fs.scroll+visible {
+		fs.scroll = fs.selected - visible + 1
+	}
+	if fs.scroll < 0 {
+		fs.scroll = 0
+	}
+	maxScroll := len(fs.rows) - visible
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if fs.scroll > maxScroll {
+		fs.scroll = maxScroll
+	}
+}
+
+// handleDetailKey прокручивает панель деталей, пока она в фокусе.
+func (fs *FixModeScreen) handleDetailKey(key string) bool {
+	switch key {
+	case "up", "k":
+		fs.scrollDetail(-1)
+	case "down", "j":
+		fs.scrollDetail(1)
+	case "pgup", "ctrl+u":
+		fs.scrollDetail(-fs.pageSize())
+	case "pgdown", "ctrl+d":
+		fs.scrollDetail(fs.pageSize())
+	case "home", "g":
+		fs.detailScroll = 0
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,291 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"surge-tui/internal/core/surge"
+)
+
+func (fs *FixModeScreen) previewKey(entry fixEntry) string {
+	id := entry.Fix.ID
+	if id == "" {
+		id = fmt.Sprintf("%s:%d", entry.Diagnostic.Code, entry.Diagnostic.Location.StartLine)
+	}
+	return filepath.Clean(entry.FilePath) + "::" + id
+}
+
+// getPreview возвращает предпросмотр из кэша; запись устаревает при
+// изменении mtime файла, а не только при перезагрузке списка.
+func (fs *FixModeScreen) getPreview(entry fixEntry) *diffPreview {
+	if fs.previewCache == nil {
+		fs.previewCache = make(map[string]*diffPreview)
+	}
+	key := fs.previewKey(entry)
+	var modTime time.Time
+	if info, err := os.Stat(entry.FilePath); err == nil {
+		modTime = info.ModTime()
+	}
+	if cached, ok := fs.previewCache[key]; ok && cached.modTime.Equal(modTime) {
+		return cached
+	}
+	preview := buildPreview(entry, fs.diffContext)
+	preview.modTime = modTime
+	fs.previewCache[key] = preview
+	return preview
+}
+
+// buildPreview строит unified diff по правкам фикса с contextLines строками
+// контекста. Номера строк в заголовках hunk'ов соответствуют файлу.
+func buildPreview(entry fixEntry, contextLines int) *diffPreview {
+	if len(entry.Fix.Edits) == 0 {
+		return &diffPreview{Lines: []diffLine{{kind: diffNote, text: "(no edits provided)"}}}
+	}
+	data, err := os.ReadFile(entry.FilePath)
+	if err != nil {
+		preview := buildRawPreview(entry)
+		preview.Err = err
+		return preview
+	}
+	lines := strings.Split(string(data), "\n")
+
+	edits, overlapErr := orderedEdits(data, entry.Fix.Edits)
+
+	preview := &diffPreview{}
+	offset := 0 // сдвиг номеров строк нового файла после предыдущих правок
+	for _, edit := range edits {
+		r, ok := lineRange(lines, edit.Location)
+		if !ok {
+			preview.Lines = append(preview.Lines, diffLine{kind: diffNote, text: fmt.Sprintf("(edit at line %d is outside the file)", edit.Location.StartLine)})
+			continue
+		}
+		startRunes := []rune(lines[r.startLine])
+		endRunes := []rune(lines[r.endLine])
+		if edit.OldText != "" && extractSegment(lines, edit.Location) != edit.OldText {
+			preview.Err = fmt.Errorf("file changed since diagnostics, preview may be stale")
+		}
+
+		oldLines := lines[r.startLine : r.endLine+1]
+		replaced := string(startRunes[:r.startCol]) + edit.NewText + string(endRunes[r.endCol:])
+		newLines := strings.Split(replaced, "\n")
+
+		from := max(r.startLine-contextLines, 0)
+		to := min(r.endLine+1+contextLines, len(lines))
+		before := lines[from:r.startLine]
+		after := lines[r.endLine+1 : to]
+
+		oldCount := len(before) + len(oldLines) + len(after)
+		newCount := len(before) + len(newLines) + len(after)
+		preview.Lines = append(preview.Lines, diffLine{
+			kind: diffHunk,
+			text: fmt.Sprintf("@@ -%d,%d +%d,%d @@", from+1, oldCount, from+1+offset, newCount),
+		})
+		for _, line := range before {
+			preview.Lines = append(preview.Lines, diffLine{kind: diffContext, text: line})
+		}
+		preview.Lines = append(preview.Lines, changedLines(oldLines, newLines)...)
+		for _, line := range after {
+			preview.Lines = append(preview.Lines, diffLine{kind: diffContext, text: line})
+		}
+		offset += len(newLines) - len(oldLines)
+	}
+	if overlapErr != nil {
+		preview.Err = fmt.Errorf("%v; the fix cannot be applied locally", overlapErr)
+	}
+	return preview
+}
+
+// orderedEdits сортирует правки по позиции в файле (байтовые позиции дополняются
+// строками) и сообщает о пересечениях. Если позицию не удалось разобрать,
+// правки сортируются по строкам, а ошибку покажет сам предпросмотр.
+func orderedEdits(data []byte, edits []surge.FixEditJSON) ([]surge.FixEditJSON, error) {
+	resolved, overlaps, err := surge.NormalizeEdits(data, edits)
+	if err != nil {
+		sorted := append([]surge.FixEditJSON(nil), edits...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			a, b := sorted[i].Location, sorted[j].Location
+			if a.StartLine != b.StartLine {
+				return a.StartLine < b.StartLine
+			}
+			return a.StartCol < b.StartCol
+		})
+		return sorted, nil
+	}
+	ordered := make([]surge.FixEditJSON, len(resolved))
+	for i, r := range resolved {
+		ordered[i] = r.Edit
+	}
+	return ordered, surge.OverlapError(overlaps)
+}
+
+// changedLines выводит удалённые и добавленные строки. Если число строк
+// совпадает, строки идут парами, и в каждой паре подсвечивается изменившаяся часть.
+func changedLines(oldLines, newLines []string) []diffLine {
+	out := make([]diffLine, 0, len(oldLines)+len(newLines))
+	if len(oldLines) != len(newLines) {
+		for _, line := range oldLines {
+			out = append(out, diffLine{kind: diffRemoved, text: line})
+		}
+		for _, line := range newLines {
+			out = append(out, diffLine{kind: diffAdded, text: line})
+		}
+		return out
+	}
+	for i := range oldLines {
+		if oldLines[i] == newLines[i] {
+			out = append(out, diffLine{kind: diffContext, text: oldLines[i]})
+			continue
+		}
+		oldFrom, oldTo, newFrom, newTo := changedSpan(oldLines[i], newLines[i])
+		out = append(out,
+			diffLine{kind: diffRemoved, text: oldLines[i], hiFrom: oldFrom, hiTo: oldTo},
+			diffLine{kind: diffAdded, text: newLines[i], hiFrom: newFrom, hiTo: newTo},
+		)
+	}
+	return out
+}
+
+// changedSpan находит отличающуюся часть строк по общему префиксу и суффиксу (в рунах).
+func changedSpan(oldLine, newLine string) (int, int, int, int) {
+	a, b := []rune(oldLine), []rune(newLine)
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return prefix, len(a) - suffix, prefix, len(b) - suffix
+}
+
+// lineRange переводит позицию правки в 0-based строки и колонки файла.
+func lineRange(lines []string, loc surge.LocationJSON) (bufferRange, bool) {
+	startLine, endLine := int(loc.StartLine), int(loc.EndLine)
+	if startLine == 0 {
+		startLine = endLine
+	}
+	if endLine == 0 {
+		endLine = startLine
+	}
+	if startLine < 1 || startLine > len(lines) || endLine < startLine {
+		return bufferRange{}, false
+	}
+	endLine = min(endLine, len(lines))
+	r := bufferRange{startLine: startLine - 1, endLine: endLine - 1}
+	r.startCol = clamp(int(loc.StartCol)-1, 0, len([]rune(lines[r.startLine])))
+	endRunes := len([]rune(lines[r.endLine]))
+	r.endCol = endRunes
+	if loc.EndCol > 0 {
+		r.endCol = clamp(int(loc.EndCol)-1, 0, endRunes)
+	}
+	if r.startLine == r.endLine && r.endCol < r.startCol {
+		r.endCol = r.startCol
+	}
+	return r, true
+}
+
+// buildRawPreview выводит правки без контекста, когда файл прочитать не удалось.
+func buildRawPreview(entry fixEntry) *diffPreview {
+	preview := &diffPreview{}
+	for _, edit := range entry.Fix.Edits {
+		loc := edit.Location
+		preview.Lines = append(preview.Lines, diffLine{
+			kind: diffHunk,
+			text: fmt.Sprintf("@@ %d:%d-%d:%d @@", loc.StartLine, loc.StartCol, loc.EndLine, loc.EndCol),
+		})
+		oldText := edit.OldText
+		if oldText == "" {
+			oldText = "(no original text)"
+		}
+		for _, line := range strings.Split(oldText, "\n") {
+			preview.Lines = append(preview.Lines, diffLine{kind: diffRemoved, text: line})
+		}
+		newText := edit.NewText
+		if newText == "" {
+			newText = "(delete)"
+		}
+		for _, line := range strings.Split(newText, "\n") {
+			preview.Lines = append(preview.Lines, diffLine{kind: diffAdded, text: line})
+		}
+	}
+	return preview
+}
+
+func extractSegment(lines []string, loc surge.LocationJSON) string {
+	if len(lines) == 0 {
+		return ""
+	}
+
+	startLine := int(loc.StartLine)
+	endLine := int(loc.EndLine)
+	if startLine == 0 {
+		startLine = endLine
+	}
+	if startLine == 0 {
+		startLine = 1
+	}
+	if endLine == 0 {
+		endLine = startLine
+	}
+	if startLine > len(lines) {
+		return ""
+	}
+	if endLine > len(lines) {
+		endLine = len(lines)
+	}
+
+	startIdx := startLine - 1
+	endIdx := endLine - 1
+	if endIdx < startIdx {
+		endIdx = startIdx
+	}
+
+	startCol := int(loc.StartCol)
+	if startCol <= 0 {
+		startCol = 1
+	}
+	endCol := int(loc.EndCol)
+	if endCol <= 0 {
+		endCol = len([]rune(lines[endIdx])) + 1
+	}
+
+	var parts []string
+	for i := startIdx; i <= endIdx; i++ {
+		lineRunes := []rune(lines[i])
+		lineLen := len(lineRunes)
+		var segment string
+
+		switch {
+		case startIdx == endIdx:
+			sc := clamp(startCol-1, 0, lineLen)
+			ec := clamp(endCol-1, sc, lineLen)
+			segment = string(lineRunes[sc:ec])
+		case i == startIdx:
+			sc := clamp(startCol-1, 0, lineLen)
+			segment = string(lineRunes[sc:])
+		case i == endIdx:
+			ec := clamp(endCol-1, 0, lineLen)
+			segment = string(lineRunes[:ec])
+		default:
+			segment = string(lineRunes)
+		}
+		parts = append(parts, segment)
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,83 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// После фикса в одном файле Fix Mode перезагружает только этот файл:
+// `surge diag` для него быстрее прогона всего проекта, а фиксы остальных
+// файлов от правки не меняются. Пока файл перезагружается, применять фиксы
+// нельзя — их ID в этом файле могли сдвинуться.
+
+// loadFileFixes заново загружает фиксы файла file, оставляя список на экране.
+func (fs *FixModeScreen) loadFileFixes(file string) tea.Cmd {
+	if fs.client == nil {
+		return nil
+	}
+	fs.reloadingFile = file
+
+	ctx, token := fs.op.begin(fs.ops, "reloading fixes for "+filepath.Base(file))
+	ctx, waitStall := fs.watchStalls(ctx)
+	projectPath := fs.projectPath
+	scope := fs.scope
+	includeSuggested := fs.includeSuggested
+	client := fs.client
+	target := file
+	if abs, err := filepath.Abs(file); err == nil {
+		target = abs
+	}
+
+	reload := func() tea.Msg {
+		resp, err := client.DiagnoseFiles(ctx, []string{target}, true, true)
+		if err != nil {
+			return fixesLoadedMsg{token: token, scope: scope, file: file, err: err}
+		}
+		entries := buildFixEntries(resp, includeSuggested)
+		diagnostics := entriesForPath(normalizeDiagnostics(resp, projectPath, true), target)
+		return fixesLoadedMsg{token: token, scope: scope, file: file, entries: entries, diagnostics: diagnostics}
+	}
+	return tea.Batch(reload, waitStall)
+}
+
+// handleFileFixesLoaded заменяет фиксы перезагруженного файла. Если файл
+// не удалось проверить, список загружается целиком.
+func (fs *FixModeScreen) handleFileFixesLoaded(m fixesLoadedMsg) tea.Cmd {
+	fs.reloadingFile = ""
+	if m.err != nil {
+		fs.setStatus(fmt.Sprintf("Failed to reload %s: %v", filepath.Base(m.file), m.err))
+		return fs.loadFixes()
+	}
+
+	merged := make([]fixEntry, 0, len(fs.all)+len(m.entries))
+	for _, entry := range fs.all {
+		if !samePath(entry.FilePath, m.file) {
+			merged = append(merged, entry)
+		}
+	}
+	merged = append(merged, m.entries...)
+	sortFixEntries(merged)
+
+	// отметки и предпросмотры файла относятся к прежним ID фиксов
+	prefix := filepath.Clean(m.file) + "::"
+	for key := range fs.marked {
+		if strings.HasPrefix(key, prefix) {
+			delete(fs.marked, key)
+		}
+	}
+	for key := range fs.previewCache {
+		if strings.HasPrefix(key, prefix) {
+			delete(fs.previewCache, key)
+		}
+	}
+	fs.setEntries(merged)
+	fs.applyPendingFocus()
+	path := m.file
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return func() tea.Msg { return DiagnosticsPublishedMsg{Path: path, Entries: m.diagnostics} }
+}
@@ -0,0 +1,273 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/ui/components"
+)
+
+// Rendering helpers -------------------------------------------------------
+
+func (fs *FixModeScreen) renderLoading() string {
+	text := "🔄 Loading fixes..."
+	if fs.stallNote != "" {
+		text += "\n\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(diagWarningColor)).Render(fs.stallNote)
+	}
+	return lipgloss.NewStyle().
+		Width(fs.Width()).
+		Height(fs.Height()).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(text)
+}
+
+func (fs *FixModeScreen) renderError() string {
+	return lipgloss.NewStyle().
+		Width(fs.Width()).
+		Height(fs.Height()).
+		Align(lipgloss.Center, lipgloss.Center).
+		Foreground(lipgloss.Color(diagErrorColor)).
+		Render(fmt.Sprintf("❌ Failed to load fixes\n\n%v", fs.err))
+}
+
+func (fs *FixModeScreen) renderEmpty() string {
+	message := "No fixes available. Run diagnostics with suggestions to populate this list."
+	if fs.scope != "" {
+		message = fmt.Sprintf("No fixes in %s. Press P to look in the whole project.", scopeLabel(fs.projectPath, fs.scope))
+	}
+	return lipgloss.NewStyle().
+		Width(fs.Width()).
+		Height(fs.Height()).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(message)
+}
+
+func (fs *FixModeScreen) renderContent() string {
+	listWidth := fs.Width() / 2
+	if listWidth < 32 {
+		listWidth = 32
+	}
+	detailWidth := fs.Width() - listWidth - 1
+	if detailWidth < 32 {
+		detailWidth = 32
+	}
+
+	list := fs.renderList(listWidth)
+	detail := fs.renderDetail(detailWidth)
+
+	base := lipgloss.JoinHorizontal(lipgloss.Top, list, detail)
+
+	status := fs.statusLine()
+	if fs.filtering {
+		status = fs.filterInput.View()
+	} else if status == "" && fs.filter.active() {
+		status = fs.filter.describe()
+	} else if status == "" {
+		status = components.ScrollIndicator(fs.scroll, fs.listHeight(), len(fs.rows))
+		if label := fs.selectionLabel(); label != "" {
+			status += "  •  " + label
+		}
+	}
+	statusBar := lipgloss.NewStyle().
+		Width(fs.Width()).
+		Foreground(lipgloss.Color(diagSecondaryColor)).
+		Render(status)
+	base = lipgloss.JoinVertical(lipgloss.Left, base, statusBar)
+
+	return base
+}
+
+func (fs *FixModeScreen) renderList(width int) string {
+	height := fs.listHeight()
+	if height <= 0 {
+		height = 3
+	}
+
+	style := fs.focus.Panel(!fs.detailFocused).
+		Width(width).
+		Height(height+3).
+		Padding(0, 1)
+
+	innerWidth := max(width-2, 1)
+	showBar := components.ScrollbarVisible(len(fs.rows), height)
+	if showBar {
+		innerWidth = max(innerWidth-1, 1)
+	}
+
+	var rows []string
+	start, end := components.VisibleWindow(fs.scroll, height, len(fs.rows))
+
+	for i := start; i < end; i++ {
+		line := fs.renderRow(fs.rows[i], innerWidth)
+		if i == fs.selected {
+			line = lipgloss.NewStyle().
+				Background(lipgloss.Color(diagSelectedBg)).
+				Foreground(lipgloss.Color(diagSelectedFg)).
+				Render(line)
+		}
+		rows = append(rows, line)
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor)).Render("No fixes match the filter"))
+	}
+
+	counter := focusCounter(fs.focus, components.ListCounter(fs.selected, len(fs.rows)), !fs.detailFocused)
+	header := listHeaderRow(fs.listTitle(), counter, max(width-2, 1))
+
+	// Полоса прокрутки занимает последнюю колонку внутри рамки, если список не помещается
+	list := lipgloss.NewStyle().Width(innerWidth).Height(height).Render(strings.Join(rows, "\n"))
+	if showBar {
+		bar := components.RenderScrollbar(len(fs.rows), fs.scroll, height, height)
+		list = lipgloss.JoinHorizontal(lipgloss.Top, list, strings.Join(bar, "\n"))
+	}
+	return style.Render(lipgloss.JoinVertical(lipgloss.Left, header, list))
+}
+
+// renderRow выводит заголовок группы ("▾ file (3)") или фикс с отметкой.
+func (fs *FixModeScreen) renderRow(row fixRow, width int) string {
+	if row.isHeader() {
+		arrow := "▾ "
+		if fs.collapsed[row.file] {
+			arrow = "▸ "
+		}
+		count := fmt.Sprintf(" (%d)", row.count)
+		name := truncateText(row.file, width-lipgloss.Width(arrow+count))
+		return lipgloss.NewStyle().Bold(true).Render(arrow + name + count)
+	}
+	entry := fs.entries[row.entry]
+	title := entry.Fix.Title
+	if title == "" {
+		title = "(unnamed fix)"
+	}
+	mark := "  [ ] "
+	if fs.isMarked(entry) {
+		mark = "  [x] "
+	}
+	return mark + truncateText(title, width-lipgloss.Width(mark))
+}
+
+func (fs *FixModeScreen) renderDetail(width int) string {
+	row, ok := fs.selectedRow()
+	if !ok {
+		return ""
+	}
+
+	innerWidth := max(width-4, 8)
+	var content []string
+	if row.isHeader() {
+		content = append(components.WrapText(row.file, innerWidth),
+			"",
+			fmt.Sprintf("%d fixes in this file", row.count),
+			"",
+			"Space/←/→ — collapse or expand",
+			"f — apply all fixes in this file",
+		)
+	} else {
+		entry := fs.entries[row.entry]
+		content = append(content, components.WrapText(entry.Fix.Title, innerWidth)...)
+		content = append(content, components.WrapText(entry.Diagnostic.Message, innerWidth)...)
+		content = append(content, "")
+		content = append(content, components.WrapText("File: "+entry.FilePath, innerWidth)...)
+		content = append(content,
+			fmt.Sprintf("Severity: %s  Code: %s", strings.ToUpper(entry.Diagnostic.Severity), entry.Diagnostic.Code),
+			"",
+			"Diff:",
+		)
+		// строки diff не переносятся, чтобы не ломать выравнивание кода
+		content = append(content, fs.renderDiff(fs.getPreview(entry), innerWidth)...)
+	}
+
+	// заголовок с маркером фокуса фиксирован, прокручивается только содержимое
+	height := fs.listHeight() + 2
+	var visible []string
+	visible, fs.detailScroll = components.ScrollWindow(content, fs.detailScroll, max(height-1, 1))
+	visible = append([]string{fs.focus.RenderTitle("Details", fs.detailFocused)}, visible...)
+
+	style := fs.focus.Panel(fs.detailFocused).
+		Width(width).
+		Height(height).
+		Padding(0, 1)
+
+	return style.Render(strings.Join(visible, "\n"))
+}
+
+// scrollDetail прокручивает панель деталей; верхняя граница проверяется при отрисовке.
+func (fs *FixModeScreen) scrollDetail(delta int) {
+	fs.detailScroll = max(fs.detailScroll+delta, 0)
+}
+
+// renderDiff возвращает строки предпросмотра, обрезанные по ширине.
+func (fs *FixModeScreen) renderDiff(preview *diffPreview, width int) []string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor))
+	if preview == nil || len(preview.Lines) == 0 {
+		return []string{dim.Render("(no diff)")}
+	}
+	styled := make([]string, 0, len(preview.Lines)+1)
+	for _, line := range preview.Lines {
+		styled = append(styled, renderDiffLine(line, width))
+	}
+	if preview.Err != nil {
+		warning := lipgloss.NewStyle().Foreground(lipgloss.Color(fixDiffWarnColor)).Render(truncateText("⚠ "+preview.Err.Error(), width))
+		styled = append(styled, warning)
+	}
+	return styled
+}
+
+// renderDiffLine окрашивает строку diff; изменившаяся часть строки выделяется фоном.
+func renderDiffLine(line diffLine, width int) string {
+	var prefix string
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor))
+	switch line.kind {
+	case diffAdded:
+		prefix = "+ "
+		style = lipgloss.NewStyle().Foreground(lipgloss.Color(fixDiffAddColor))
+	case diffRemoved:
+		prefix = "- "
+		style = lipgloss.NewStyle().Foreground(lipgloss.Color(fixDiffDelColor))
+	case diffHunk:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(fixDiffMetaColor)).Bold(true).Render(truncateText(line.text, width))
+	case diffNote:
+		return style.Render(truncateText(line.text, width))
+	default:
+		prefix = "  "
+	}
+
+	runes := []rune(line.text)
+	limit := max(width-len(prefix), 1)
+	truncated := len(runes) > limit
+	if truncated {
+		runes = runes[:limit-1]
+	}
+	from := clamp(line.hiFrom, 0, len(runes))
+	to := clamp(line.hiTo, from, len(runes))
+	out := style.Render(prefix + string(runes[:from]))
+	if to > from {
+		out += style.Reverse(true).Render(string(runes[from:to]))
+	}
+	out += style.Render(string(runes[to:]))
+	if truncated {
+		out += style.Render("…")
+	}
+	return out
+}
+
+// Utility -----------------------------------------------------------------
+
+func truncateText(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if lipgloss.Width(text) <= width {
+		return text
+	}
+	if width <= 3 {
+		return text[:width]
+	}
+	runes := []rune(text)
+	if len(runes) <= width {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -4,18 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 
+	"surge-tui/internal/config"
 	"surge-tui/internal/core/surge"
 	"surge-tui/internal/platform"
 	"surge-tui/internal/ui/components"
+	"surge-tui/internal/ui/styles"
 )
 
 // FixModeScreen отображает доступные авто-фиксы и позволяет их применять.
@@ -23,27 +24,61 @@ type FixModeScreen struct {
 	BaseScreen
 
 	projectPath string
-	client      *surge.Client
+	scope       string // каталог, которым ограничены загрузка и Apply All; пусто — весь проект
+	client      surge.SurgeRunner
 
-	loading bool
-	err     error
+	loading     bool
+	applyOnLoad bool // после загрузки спросить о применении всех фиксов области
+	err         error
 
+	all      []fixEntry // полный список; entries — его отфильтрованная часть
 	entries  []fixEntry
-	selected int
+	rows     []fixRow // строки списка: заголовки групп и фиксы
+	selected int      // индекс в rows
 	scroll   int
 
 	includeSuggested bool
 
-	statusMsg string
-	statusAt  time.Time
+	filter      fixFilter
+	filterInput textinput.Model
+	filtering   bool
+	restoreKey  string // позиция до включения фильтра
 
-	confirm *components.ConfirmDialog
+	collapsed map[string]bool // свёрнутые группы по FilePath
 
-	previewCache map[string]*diffPreview
+	status statusQueue
+
+	confirm     *components.ConfirmDialog
+	dirtyDialog *components.ChoiceDialog // несохранённые вкладки перед фиксом
+	buffers     OpenBuffers
+
+	previewCache  map[string]*diffPreview
+	diffContext   int  // строк контекста в предпросмотре
+	detailScroll  int  // прокрутка панели деталей
+	detailFocused bool // Tab переводит стрелки на прокрутку панели деталей
+	focus         styles.FocusStyle
+
+	// отмеченные для пакетного применения фиксы (ключ previewKey)
+	marked map[string]bool
+	batch  *fixBatch // идущее пакетное применение
 
 	pendingFocus *fixFocusRequest
 
-	cancel context.CancelFunc
+	reloadingFile string // файл, фиксы которого перезагружаются после применения
+
+	// последний ответ surge diag: по нему список пересобирается без запуска
+	cache      diagCache
+	cacheResp  *surge.DiagResponse
+	cacheScope string
+
+	// идущая загрузка или применение; ops — общий реестр операций над
+	// файлами проекта, queuedLoad — загрузка, отложенная до конца применения
+	op         opGuard
+	ops        *ProjectOps
+	queuedLoad bool
+
+	stall     *stallWatch // сторож загрузки или применения
+	stallNote string      // предупреждение сторожа: surge давно молчит
 }
 
 type fixEntry struct {
@@ -52,39 +87,76 @@ type fixEntry struct {
 	Fix        surge.FixJSON
 }
 
+// FixModeEvent — сообщения загрузки и применения фиксов. App доставляет их
+// экрану Fix Mode, даже если он не активен: иначе он навсегда остался бы
+// занят операцией, результат которой потерялся.
+type FixModeEvent interface {
+	fixModeEvent()
+}
+
 type fixesLoadedMsg struct {
-	entries []fixEntry
-	err     error
+	token       int
+	scope       string
+	file        string // непусто — перезагружен только этот файл
+	entries     []fixEntry
+	diagnostics []DiagnosticEntry
+	err         error
+
+	resp   *surge.DiagResponse
+	at     time.Time    // начало загрузки
+	stamp  projectStamp // отпечаток файлов на начало загрузки
+	cached bool         // список собран из прежнего ответа, surge не запускался
 }
 
 type fixAppliedMsg struct {
-	err   error
-	count int // 1 для одиночного, >=0 для количества, -1 неизвестно
+	token  int
+	err    error
+	count  int        // 1 для одиночного, >=0 для количества, -1 неизвестно
+	file   string     // файл одиночного фикса: перезагружается только он
+	issues []IssueRef // исправленные диагностики; пусто, если неизвестно какие
+	files  []string   // файлы, которые могли измениться: их вкладки перечитываются
 }
 
 type fixApplyAllMsg struct {
 	confirmed bool
 }
 
+func (fixesLoadedMsg) fixModeEvent()  {}
+func (fixAppliedMsg) fixModeEvent()   {}
+func (fixBatchStepMsg) fixModeEvent() {}
+
+type diffLineKind int
+
+const (
+	diffContext diffLineKind = iota
+	diffRemoved
+	diffAdded
+	diffHunk
+	diffNote
+)
+
+// diffLine — строка предпросмотра; [hiFrom, hiTo) в рунах — изменившаяся часть.
+type diffLine struct {
+	kind   diffLineKind
+	text   string
+	hiFrom int
+	hiTo   int
+}
+
 type diffPreview struct {
-	Diff string
-	Err  error
+	Lines   []diffLine
+	Err     error
+	modTime time.Time
 }
 
 type fixFocusRequest struct {
 	File  string
 	FixID string
+	Issue IssueRef // проблема, выбранная на экране диагностики; ищется без File и FixID
 }
 
-const (
-	fixDiffAddColor  = "#22C55E"
-	fixDiffDelColor  = "#F87171"
-	fixDiffMetaColor = "#94A3B8"
-	fixDiffWarnColor = "#FBBF24"
-)
-
 // NewFixModeScreen создаёт новый экран Fix Mode.
-func NewFixModeScreen(projectPath string, client *surge.Client) *FixModeScreen {
+func NewFixModeScreen(projectPath string, client surge.SurgeRunner) *FixModeScreen {
 	dialog := components.NewConfirmDialog("Apply All Fixes", "Apply all available fixes? This cannot be undone.")
 	dialog.ConfirmText = "Apply"
 	dialog.CancelText = "Cancel"
@@ -97,7 +169,13 @@ func NewFixModeScreen(projectPath string, client *surge.Client) *FixModeScreen {
 		selected:         0,
 		scroll:           0,
 		confirm:          dialog,
+		dirtyDialog:      newFixDirtyDialog(),
 		previewCache:     make(map[string]*diffPreview),
+		diffContext:      config.DefaultConfig().FixMode.DiffContext,
+		marked:           make(map[string]bool),
+		collapsed:        make(map[string]bool),
+		filterInput:      newFixFilterInput(),
+		focus:            styles.DefaultFocus(),
 	}
 }
 
@@ -106,19 +184,39 @@ func (fs *FixModeScreen) Init() tea.Cmd {
 	return fs.loadFixes()
 }
 
-// OnEnter перезагружает фиксы при возврате на экран.
+// OnEnter перезагружает фиксы при возврате на экран; свежий прежний ответ
+// surge используется повторно.
 func (fs *FixModeScreen) OnEnter() tea.Cmd {
 	return fs.loadFixes()
 }
 
+// RetryAfterSurge перезагружает фиксы, если прошлая загрузка не удалась:
+// приложение вызывает его, когда surge стал доступен.
+func (fs *FixModeScreen) RetryAfterSurge() tea.Cmd {
+	if fs.err == nil || fs.loading {
+		return nil
+	}
+	return fs.loadFixes()
+}
+
 // Update обрабатывает сообщения.
 func (fs *FixModeScreen) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	screen, cmd := fs.update(msg)
+	return screen, tea.Batch(cmd, fs.status.schedule())
+}
+
+func (fs *FixModeScreen) update(msg tea.Msg) (Screen, tea.Cmd) {
 	if fs.confirm != nil && fs.confirm.Visible {
 		if cmd := fs.confirm.Update(msg); cmd != nil {
 			return fs, cmd
 		}
 		return fs, nil
 	}
+	if fs.dirtyDialog.Visible {
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return fs, fs.dirtyDialog.Update(msg)
+		}
+	}
 
 	switch m := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -127,41 +225,72 @@ func (fs *FixModeScreen) Update(msg tea.Msg) (Screen, tea.Cmd) {
 	case tea.KeyMsg:
 		return fs.handleKey(m)
 	case fixesLoadedMsg:
-		fs.loading = false
-		if fs.cancel != nil {
-			fs.cancel = nil
+		if !fs.op.finish(fs.ops, m.token) || errors.Is(m.err, context.Canceled) {
+			return fs, nil // эту загрузку сменила более новая или отменил Esc
+		}
+		fs.stopStall()
+		if m.file != "" {
+			return fs, fs.handleFileFixesLoaded(m)
 		}
+		fs.loading = false
+		applyAll := fs.applyOnLoad
+		fs.applyOnLoad = false
 		if m.err != nil {
+			fs.cache.reset()
+			fs.cacheResp = nil
 			fs.err = m.err
+			fs.all = nil
 			fs.entries = nil
+			fs.rows = nil
 			fs.selected = 0
 			fs.scroll = 0
 			fs.previewCache = make(map[string]*diffPreview)
 		} else {
 			fs.err = nil
-			fs.entries = m.entries
 			fs.previewCache = make(map[string]*diffPreview)
-			if fs.selected >= len(fs.entries) {
-				fs.selected = len(fs.entries) - 1
+			fs.marked = make(map[string]bool) // ID фиксов могли измениться
+			fs.setEntries(m.entries)
+			var publish tea.Cmd
+			if m.cached {
+				fs.setStatus(fmt.Sprintf("Fix list from %s (Ctrl+R to reload)", fs.cache.at.Format("15:04:05")))
+			} else {
+				fs.cache.store(m.at, m.stamp)
+				fs.cacheResp, fs.cacheScope = m.resp, m.scope
+				fs.setStatus("Fix list updated")
+				publish = publishDiagnostics(m.scope, m.diagnostics)
 			}
-			if fs.selected < 0 {
-				fs.selected = 0
+			fs.applyPendingFocus()
+			if applyAll && len(fs.all) > 0 {
+				return fs, tea.Batch(publish, fs.confirmApplyAll())
 			}
-			fs.ensureSelectionVisible()
-			fs.setStatus("Fix list updated")
-			if fs.pendingFocus != nil && fs.applyFocus(*fs.pendingFocus) {
-				fs.pendingFocus = nil
+			if applyAll {
+				fs.setStatus("No fixes in " + scopeLabel(fs.projectPath, fs.scope))
 			}
+			return fs, publish
 		}
 		return fs, nil
 	case fixAppliedMsg:
-		if fs.cancel != nil {
-			fs.cancel = nil
+		if !fs.op.finish(fs.ops, m.token) {
+			return fs, nil
+		}
+		fs.stopStall()
+		queued := fs.queuedLoad
+		fs.queuedLoad = false
+		if errors.Is(m.err, context.Canceled) {
+			// surge мог успеть переписать файлы
+			fs.setStatus("Fix cancelled; reloading the list")
+			fs.cache.reset()
+			fs.reloadBuffers(m.files)
+			return fs, fs.loadFixes()
 		}
 		if m.err != nil {
 			fs.setStatus(fmt.Sprintf("Failed to apply fix: %v", m.err))
+			if queued {
+				return fs, fs.loadFixes()
+			}
 			return fs, nil
 		}
+		fs.cache.reset() // фиксы поменяли файлы
 		if m.count < 0 {
 			fs.setStatus("Applied all fixes")
 		} else if m.count <= 1 {
@@ -169,7 +298,20 @@ func (fs *FixModeScreen) Update(msg tea.Msg) (Screen, tea.Cmd) {
 		} else {
 			fs.setStatus(fmt.Sprintf("Applied %d fixes", m.count))
 		}
-		return fs, fs.loadFixes()
+		fs.reloadBuffers(m.files)
+		resolved := issuesResolved(m.issues)
+		if m.file != "" && !queued {
+			return fs, tea.Batch(resolved, fs.loadFileFixes(m.file))
+		}
+		return fs, tea.Batch(resolved, fs.loadFixes())
+	case fixDirtyChoiceMsg:
+		return fs, fs.handleDirtyChoice(m)
+	case fixBatchStepMsg:
+		return fs, fs.handleBatchStep(m)
+	case stallMsg:
+		return fs, fs.handleStall(m)
+	case fixCodeApplyMsg:
+		return fs, fs.handleCodeApply(m)
 	case fixApplyAllMsg:
 		if !m.confirmed {
 			fs.setStatus("Cancelled")
@@ -192,14 +334,21 @@ func (fs *FixModeScreen) View() string {
 	if fs.err != nil {
 		return fs.renderError()
 	}
-	if len(fs.entries) == 0 {
+	if len(fs.all) == 0 {
 		return fs.renderEmpty()
 	}
-	return fs.renderContent()
+	view := fs.renderContent()
+	if fs.confirm != nil && fs.confirm.Visible {
+		view = joinOverlay(view, fs.confirm.View())
+	}
+	if fs.dirtyDialog.Visible {
+		view = joinOverlay(view, fs.dirtyDialog.View())
+	}
+	return view
 }
 
 func (fs *FixModeScreen) ShortHelp() string {
-	return platform.ReplacePrimaryModifier("↑↓ Navigate • Enter Preview • a Apply • A Apply All • Ctrl+R Refresh")
+	return platform.ReplacePrimaryModifier("↑↓ Navigate • / Filter • Space Select • s Apply Selected • c Apply Same Code • a Apply • A Apply All • o Open • Ctrl+R Refresh")
 }
 
 func (fs *FixModeScreen) FullHelp() []string {
@@ -210,27 +359,72 @@ func (fs *FixModeScreen) FullHelp() []string {
 		"  ↑/↓ or j/k - Navigate fixes",
 		"  PgUp/PgDn - Page",
 		"  Enter - Preview details",
-		"  a - Apply selected fix",
-		"  A - Apply all fixes",
+		"  o or Alt+Enter - Open the diagnostic in the editor",
+		"  / - Filter by file or fix title",
+		"  e - Cycle severity filter (errors/warnings)",
+		"  t - Cycle fix kind filter",
+		"  J/K or Ctrl+J/Ctrl+K - Scroll details",
+		"  Tab - Focus details pane (↑/↓, PgUp/PgDn scroll it)",
+		"  y - Cycle applicability filter",
+		"  Esc - Clear filters",
+		"  a - Apply fix under cursor",
+		"  Space - Select/deselect fix, collapse/expand file group",
+		"  ←/→ or h/l - Collapse/expand file group",
+		"  f - Apply all fixes in the file under cursor",
+		"  v - Select/deselect all visible fixes",
+		"  s - Apply selected fixes",
+		"  c - Apply all listed fixes with the same diagnostic code",
+		"  A - Apply all fixes (in the directory, if Fix Mode was opened with F from the tree)",
+		"  P - Widen a directory to the whole project",
 		platform.ReplacePrimaryModifier("  Ctrl+R - Reload"),
-		"  Tab - Toggle suggested fixes",
+		"  i - Toggle suggested fixes",
 	}...)
 	return help
 }
 
 func (fs *FixModeScreen) handleKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
 	key := platform.CanonicalKeyForLookup(msg.String())
+	if msg.Type == tea.KeySpace {
+		key = "space" // CanonicalKeyForLookup обрезает пробел до пустой строки
+	}
 	if fs.loading {
 		switch key {
 		case "ctrl+r":
-			return fs, fs.loadFixes()
+			return fs, fs.reloadFixes()
 		}
 		return fs, nil
 	}
+	if fs.filtering {
+		return fs.handleFilterKey(msg)
+	}
 
+	if fs.detailFocused && fs.handleDetailKey(key) {
+		return fs, nil
+	}
 	switch key {
+	case "a", "f", "s", "c", "A":
+		if fs.reloadingFile != "" {
+			// ID фиксов перезагружаемого файла могли сдвинуться
+			fs.setStatus(fmt.Sprintf("Reloading fixes for %s…", filepath.Base(fs.reloadingFile)))
+			return fs, nil
+		}
+	}
+
+	switch key {
+	case "/":
+		return fs, fs.startFilterInput()
+	case "e":
+		fs.cycleSeverityFilter()
+	case "t":
+		fs.cycleKindFilter()
+	case "J", "ctrl+j":
+		fs.scrollDetail(1)
+	case "K", "ctrl+k":
+		fs.scrollDetail(-1)
+	case "y":
+		fs.cycleApplicabilityFilter()
 	case "ctrl+r":
-		return fs, fs.loadFixes()
+		return fs, fs.reloadFixes()
 	case "up", "k":
 		fs.moveSelection(-1)
 	case "down", "j":
@@ -242,23 +436,31 @@ func (fs *FixModeScreen) handleKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
 	case "home", "g":
 		fs.setSelection(0)
 	case "end", "G":
-		fs.setSelection(len(fs.entries) - 1)
+		fs.setSelection(len(fs.rows) - 1)
 	case "enter":
 		// Preview is generated on render, so nothing extra for now.
 		return fs, nil
+	case "o", "alt+enter":
+		return fs, fs.openSelectedLocation()
 	case "a":
 		return fs, fs.applySelected()
+	case "space", "left", "h", "right", "l":
+		fs.handleGroupKey(key)
+	case "f":
+		return fs, fs.applyGroup()
+	case "v":
+		fs.toggleVisibleMarked()
+	case "s":
+		return fs, fs.applyMarked()
+	case "c":
+		return fs, fs.confirmApplyCode()
 	case "A":
-		if fs.confirm != nil {
-			fs.confirm.Description = "Apply all available fixes in project?"
-			ch := fs.confirm.Show()
-			return fs, func() tea.Msg {
-				confirmed := <-ch
-				return fixApplyAllMsg{confirmed: confirmed}
-			}
-		}
-		return fs, fs.applyAll()
+		return fs, fs.confirmApplyAll()
+	case "P":
+		return fs, fs.widenScope()
 	case "tab":
+		fs.detailFocused = !fs.detailFocused
+	case "i":
 		fs.includeSuggested = !fs.includeSuggested
 		if fs.includeSuggested {
 			fs.setStatus("Showing suggested fixes")
@@ -271,42 +473,99 @@ func (fs *FixModeScreen) handleKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
 	return fs, nil
 }
 
+// openSelectedLocation открывает место диагностики фикса под курсором в
+// редакторе; экран проекта предложит вернуться в Fix Mode к тому же фиксу.
+func (fs *FixModeScreen) openSelectedLocation() tea.Cmd {
+	entry, ok := fs.selectedEntry()
+	if !ok || entry.FilePath == "" {
+		return nil
+	}
+	path := entry.FilePath
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	loc := entry.Diagnostic.Location
+	location := OpenLocationMsg{
+		FilePath: path,
+		Line:     max(int(loc.StartLine), 1),
+		Column:   max(int(loc.StartCol), 1),
+		From:     "Fix Mode",
+	}
+	return func() tea.Msg { return location }
+}
+
 func (fs *FixModeScreen) loadFixes() tea.Cmd {
 	if fs.client == nil {
 		fs.err = errors.New("surge client not configured")
 		return nil
 	}
-	if fs.cancel != nil {
-		fs.cancel()
-		fs.cancel = nil
+	if fs.op.mutating() {
+		// применение само перезагрузит список, когда закончится
+		fs.queuedLoad = true
+		fs.setStatus(fs.op.busyStatus(true) + "; the list reloads when it finishes")
+		return nil
 	}
 	fs.loading = true
+	fs.reloadingFile = ""
 	fs.err = nil
 
-	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
-	fs.cancel = cancel
+	ctx, token := fs.op.begin(fs.ops, "loading fixes")
+	ctx, waitStall := fs.watchStalls(ctx)
 	projectPath := fs.projectPath
+	scope := fs.scope
 	includeSuggested := fs.includeSuggested
 	client := fs.client
+	stamped := fs.cache.maxAge > 0
+	cache, cached := fs.cache, fs.cacheResp
+	if fs.cacheScope != scope || !fs.cache.fresh() {
+		cached = nil
+	}
+	at := time.Now()
 
-	return func() tea.Msg {
-		defer cancel()
-		resp, err := client.Diagnose(ctx, projectPath, true, true)
+	load := func() tea.Msg {
+		var stamp projectStamp
+		if stamped {
+			stamp = stampProject(projectPath)
+		}
+		if cached != nil && stamp.Equal(cache.stamp) {
+			entries := buildFixEntries(cached, includeSuggested)
+			sortFixEntries(entries)
+			return fixesLoadedMsg{token: token, scope: scope, entries: entries, cached: true}
+		}
+		resp, err := client.Diagnose(ctx, scopeTarget(projectPath, scope), true, true)
 		if err != nil {
-			return fixesLoadedMsg{err: err}
+			return fixesLoadedMsg{token: token, scope: scope, err: err}
 		}
 		entries := buildFixEntries(resp, includeSuggested)
-		sort.Slice(entries, func(i, j int) bool {
-			if entries[i].FilePath != entries[j].FilePath {
-				return entries[i].FilePath < entries[j].FilePath
-			}
-			if entries[i].Diagnostic.Code != entries[j].Diagnostic.Code {
-				return entries[i].Diagnostic.Code < entries[j].Diagnostic.Code
-			}
-			return entries[i].Fix.Title < entries[j].Fix.Title
-		})
-		return fixesLoadedMsg{entries: entries}
+		sortFixEntries(entries)
+		diagnostics := normalizeDiagnostics(resp, projectPath, true)
+		return fixesLoadedMsg{token: token, scope: scope, entries: entries, diagnostics: diagnostics, resp: resp, at: at, stamp: stamp}
 	}
+	return tea.Batch(load, waitStall)
+}
+
+// reloadFixes загружает фиксы, не глядя на прежний ответ surge.
+func (fs *FixModeScreen) reloadFixes() tea.Cmd {
+	fs.cache.reset()
+	return fs.loadFixes()
+}
+
+// SetCacheMaxAge задаёт, сколько прежний ответ surge используется без перезапуска.
+func (fs *FixModeScreen) SetCacheMaxAge(age time.Duration) {
+	fs.cache.maxAge = age
+}
+
+// sortFixEntries упорядочивает фиксы по файлу, коду диагностики и заголовку.
+func sortFixEntries(entries []fixEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].FilePath != entries[j].FilePath {
+			return entries[i].FilePath < entries[j].FilePath
+		}
+		if entries[i].Diagnostic.Code != entries[j].Diagnostic.Code {
+			return entries[i].Diagnostic.Code < entries[j].Diagnostic.Code
+		}
+		return entries[i].Fix.Title < entries[j].Fix.Title
+	})
 }
 
 func buildFixEntries(resp *surge.DiagResponse, includeSuggested bool) []fixEntry {
@@ -356,23 +615,76 @@ func choosePath(display, reported string) string {
 	return filepath.Clean(path)
 }
 
+// SetDiffContext задаёт число строк контекста в предпросмотре фикса.
+func (fs *FixModeScreen) SetDiffContext(lines int) {
+	if lines != fs.diffContext {
+		fs.diffContext = lines
+		fs.previewCache = make(map[string]*diffPreview)
+	}
+}
+
 // SetProjectPath обновляет путь проекта, используемый экраном.
 func (fs *FixModeScreen) SetProjectPath(path string) {
 	fs.projectPath = path
 }
 
+// SetScope ограничивает список фиксов каталогом dir. applyAll — после
+// загрузки спросить, применить ли все фиксы каталога. Список загружается
+// заново при входе на экран.
+func (fs *FixModeScreen) SetScope(dir string, applyAll bool) {
+	fs.scope = normalizeScope(fs.projectPath, dir)
+	fs.applyOnLoad = applyAll
+}
+
+// widenScope снимает ограничение каталогом и загружает фиксы проекта.
+func (fs *FixModeScreen) widenScope() tea.Cmd {
+	if fs.scope == "" {
+		fs.setStatus("Already showing the whole project")
+		return nil
+	}
+	fs.scope = ""
+	fs.setStatus("Widened to project")
+	return fs.loadFixes()
+}
+
+// confirmApplyAll спрашивает, применить ли все фиксы области.
+func (fs *FixModeScreen) confirmApplyAll() tea.Cmd {
+	if fs.confirm == nil {
+		return fs.applyAll()
+	}
+	where := "project"
+	if fs.scope != "" {
+		where = scopeLabel(fs.projectPath, fs.scope)
+	}
+	fs.confirm.Title = "Apply All Fixes"
+	fs.confirm.Description = fmt.Sprintf("Apply all available fixes in %s?", where)
+	ch := fs.confirm.Show()
+	return func() tea.Msg {
+		confirmed := <-ch
+		return fixApplyAllMsg{confirmed: confirmed}
+	}
+}
+
 func (fs *FixModeScreen) applySelected() tea.Cmd {
-	if fs.client == nil || len(fs.entries) == 0 {
+	if fs.client == nil {
+		return nil
+	}
+	entry, ok := fs.selectedEntry()
+	if !ok {
 		return nil
 	}
-	entry := fs.entries[fs.selected]
 	if entry.Fix.ID == "" {
 		fs.setStatus("Fix has no ID")
 		return nil
 	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-	fs.cancel = cancel
+	if cmd, held := fs.holdForDirty([]string{entry.FilePath}, fs.applySelected); held {
+		return cmd
+	}
+	ctx, token, ok := fs.beginApply("applying fix")
+	if !ok {
+		return nil
+	}
+	ctx, waitStall := fs.watchStalls(ctx)
 	client := fs.client
 	filePath := entry.FilePath
 	if !filepath.IsAbs(filePath) {
@@ -381,166 +693,92 @@ func (fs *FixModeScreen) applySelected() tea.Cmd {
 		}
 	}
 	fixID := entry.Fix.ID
+	issue := issueOfFix(entry)
 
 	fs.setStatus("Applying fix...")
-	return func() tea.Msg {
-		defer cancel()
+	apply := func() tea.Msg {
 		err := client.ApplyFixByID(ctx, filePath, fixID)
-		return fixAppliedMsg{err: err, count: 1}
+		return fixAppliedMsg{token: token, err: err, count: 1, file: entry.FilePath, issues: []IssueRef{issue}, files: []string{entry.FilePath}}
 	}
+	return tea.Batch(apply, waitStall)
 }
 
 func (fs *FixModeScreen) applyAll() tea.Cmd {
 	if fs.client == nil {
 		return nil
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
-	fs.cancel = cancel
-	client := fs.client
-	projectPath := fs.projectPath
-	if projectPath == "" && len(fs.entries) > 0 {
-		projectPath = filepath.Dir(fs.entries[0].FilePath)
-	}
-
-	fs.setStatus("Applying all fixes...")
-	return func() tea.Msg {
-		defer cancel()
-		err := client.ApplyAllFixes(ctx, projectPath)
-		return fixAppliedMsg{err: err, count: -1}
-	}
-}
-
-func (fs *FixModeScreen) FocusFix(filePath, fixID string) {
-	if filePath == "" && fixID == "" {
-		return
+	files := fixFiles(fs.all)
+	if cmd, held := fs.holdForDirty(files, fs.applyAll); held {
+		return cmd
 	}
-	cleanFile := filePath
-	if cleanFile != "" {
-		cleanFile = filepath.Clean(cleanFile)
-	}
-	req := fixFocusRequest{
-		File:  cleanFile,
-		FixID: fixID,
-	}
-	if fs.applyFocus(req) {
-		fs.pendingFocus = nil
-		return
-	}
-	fs.pendingFocus = &req
-}
-
-func (fs *FixModeScreen) applyFocus(req fixFocusRequest) bool {
-	if len(fs.entries) == 0 {
-		return false
-	}
-	cleanFile := req.File
-	if cleanFile != "" {
-		cleanFile = filepath.Clean(cleanFile)
-	}
-	index := -1
-	if req.FixID != "" {
-		for i, entry := range fs.entries {
-			if strings.EqualFold(entry.Fix.ID, req.FixID) {
-				if cleanFile == "" || samePath(entry.FilePath, cleanFile) {
-					index = i
-					break
-				}
-			}
-		}
-	}
-	if index == -1 && cleanFile != "" {
-		for i, entry := range fs.entries {
-			if samePath(entry.FilePath, cleanFile) {
-				index = i
-				break
-			}
-		}
+	ctx, token, ok := fs.beginApply("applying all fixes")
+	if !ok {
+		return nil
 	}
-	if index == -1 {
-		return false
+	ctx, waitStall := fs.watchStalls(ctx)
+	client := fs.client
+	target := scopeTarget(fs.projectPath, fs.scope)
+	if target == "" && len(fs.entries) > 0 {
+		target = filepath.Dir(fs.entries[0].FilePath)
 	}
-	fs.setSelection(index)
-	fs.ensureSelectionVisible()
-	return true
-}
 
-func (fs *FixModeScreen) moveSelection(delta int) {
-	if len(fs.entries) == 0 {
-		fs.selected = 0
-		fs.scroll = 0
-		return
-	}
-	fs.selected += delta
-	if fs.selected < 0 {
-		fs.selected = 0
-	}
-	if fs.selected >= len(fs.entries) {
-		fs.selected = len(fs.entries) - 1
+	fs.setStatus("Applying all fixes...")
+	apply := func() tea.Msg {
+		err := client.ApplyAllFixes(ctx, target)
+		return fixAppliedMsg{token: token, err: err, count: -1, files: files}
 	}
-	fs.ensureSelectionVisible()
+	return tea.Batch(apply, waitStall)
 }
 
-func (fs *FixModeScreen) setSelection(index int) {
-	if len(fs.entries) == 0 {
-		fs.selected = 0
-		fs.scroll = 0
-		return
+// beginApply начинает применение фиксов, если ему ничто не мешает: ни
+// идущее применение на этом экране, ни операция над файлами проекта на другом.
+func (fs *FixModeScreen) beginApply(label string) (context.Context, int, bool) {
+	if fs.op.mutating() {
+		fs.setStatus(fs.op.busyStatus(true))
+		return nil, 0, false
 	}
-	if index < 0 {
-		index = 0
+	ctx, token, other := fs.op.beginProject(fs.ops, label, " in Fix Mode")
+	if other != "" {
+		fs.setStatus("Busy: " + other)
+		return nil, 0, false
 	}
-	if index >= len(fs.entries) {
-		index = len(fs.entries) - 1
+	if fs.loading {
+		// загрузка списка сменилась применением: список загрузится после него
+		fs.loading, fs.queuedLoad = false, true
 	}
-	fs.selected = index
-	fs.ensureSelectionVisible()
+	return ctx, token, true
 }
 
-func (fs *FixModeScreen) pageSize() int {
-	h := fs.listHeight()
-	if h <= 1 {
-		return 1
-	}
-	return h - 1
+// SetProjectOps подключает общий реестр операций над файлами проекта.
+func (fs *FixModeScreen) SetProjectOps(ops *ProjectOps) {
+	fs.ops = ops
 }
 
-func (fs *FixModeScreen) ensureSelectionVisible() {
-	visible := fs.listHeight()
-	if visible <= 0 {
-		fs.scroll = 0
-		return
-	}
-	if fs.selected < fs.scroll {
-		fs.scroll = fs.selected
-	} else if fs.selected >= fs.scroll+visible {
-		fs.scroll = fs.selected - visible + 1
-	}
-	if fs.scroll < 0 {
-		fs.scroll = 0
-	}
-	maxScroll := len(fs.entries) - visible
-	if maxScroll < 0 {
-		maxScroll = 0
-	}
-	if fs.scroll > maxScroll {
-		fs.scroll = maxScroll
+// issuesResolved сообщает приложению о диагностиках, исправленных фиксами.
+func issuesResolved(issues []IssueRef) tea.Cmd {
+	if len(issues) == 0 {
+		return nil
 	}
+	return func() tea.Msg { return IssuesResolvedMsg{Issues: issues} }
 }
 
 func (fs *FixModeScreen) setStatus(msg string) {
-	fs.statusMsg = msg
-	fs.statusAt = time.Now()
+	fs.status.push(msg, statusSeverityOf(msg))
 }
 
 func (fs *FixModeScreen) statusLine() string {
-	if fs.statusMsg == "" {
-		return ""
+	if fs.stallNote != "" {
+		return fs.stallNote
 	}
-	if time.Since(fs.statusAt) > 4*time.Second {
-		fs.statusMsg = ""
-		return ""
+	if fs.batch != nil {
+		return fs.batch.progress()
 	}
-	return fs.statusMsg
+	return fs.status.line()
+}
+
+// StatusHistory возвращает последние сообщения строки статуса, новые первыми.
+func (fs *FixModeScreen) StatusHistory() []string {
+	return fs.status.recent()
 }
 
 func (fs *FixModeScreen) listHeight() int {
@@ -548,336 +786,14 @@ func (fs *FixModeScreen) listHeight() int {
 	if h <= 0 {
 		return 0
 	}
-	// Reserve space for borders and preview
-	height := h - 8
+	// Reserve space for borders, list header and preview
+	height := h - 9
 	if height < 3 {
 		height = 3
 	}
 	return height
 }
 
-// Rendering helpers -------------------------------------------------------
-
-func (fs *FixModeScreen) renderLoading() string {
-	return lipgloss.NewStyle().
-		Width(fs.Width()).
-		Height(fs.Height()).
-		Align(lipgloss.Center, lipgloss.Center).
-		Render("🔄 Loading fixes...")
-}
-
-func (fs *FixModeScreen) renderError() string {
-	return lipgloss.NewStyle().
-		Width(fs.Width()).
-		Height(fs.Height()).
-		Align(lipgloss.Center, lipgloss.Center).
-		Foreground(lipgloss.Color(diagErrorColor)).
-		Render(fmt.Sprintf("❌ Failed to load fixes\n\n%v", fs.err))
-}
-
-func (fs *FixModeScreen) renderEmpty() string {
-	message := "No fixes available. Run diagnostics with suggestions to populate this list."
-	return lipgloss.NewStyle().
-		Width(fs.Width()).
-		Height(fs.Height()).
-		Align(lipgloss.Center, lipgloss.Center).
-		Render(message)
-}
-
-func (fs *FixModeScreen) renderContent() string {
-	listWidth := fs.Width() / 2
-	if listWidth < 32 {
-		listWidth = 32
-	}
-	detailWidth := fs.Width() - listWidth - 1
-	if detailWidth < 32 {
-		detailWidth = 32
-	}
-
-	list := fs.renderList(listWidth)
-	detail := fs.renderDetail(detailWidth)
-
-	base := lipgloss.JoinHorizontal(lipgloss.Top, list, detail)
-
-	if status := fs.statusLine(); status != "" {
-		statusBar := lipgloss.NewStyle().
-			Width(fs.Width()).
-			Foreground(lipgloss.Color(diagSecondaryColor)).
-			Render(status)
-		base = lipgloss.JoinVertical(lipgloss.Left, base, statusBar)
-	}
-
-	return base
-}
-
-func (fs *FixModeScreen) renderList(width int) string {
-	height := fs.listHeight()
-	if height <= 0 {
-		height = 3
-	}
-
-	style := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(diagSecondaryColor)).
-		Width(width).
-		Height(height+2).
-		Padding(0, 1)
-
-	var rows []string
-	start := fs.scroll
-	end := fs.scroll + height
-	if end > len(fs.entries) {
-		end = len(fs.entries)
-	}
-
-	for i := start; i < end; i++ {
-		entry := fs.entries[i]
-		title := entry.Fix.Title
-		if title == "" {
-			title = "(unnamed fix)"
-		}
-		line := fmt.Sprintf("%s — %s", truncateText(entry.FilePath, width-4), title)
-		if i == fs.selected {
-			line = lipgloss.NewStyle().
-				Background(lipgloss.Color(diagSelectedBg)).
-				Foreground(lipgloss.Color(diagSelectedFg)).
-				Render(line)
-		}
-		rows = append(rows, line)
-	}
-
-	return style.Render(strings.Join(rows, "\n"))
-}
-
-func (fs *FixModeScreen) renderDetail(width int) string {
-	if len(fs.entries) == 0 {
-		return ""
-	}
-	entry := fs.entries[fs.selected]
-
-	header := fmt.Sprintf("%s\n%s", entry.Fix.Title, entry.Diagnostic.Message)
-	meta := fmt.Sprintf("File: %s\nSeverity: %s\nCode: %s", entry.FilePath, strings.ToUpper(entry.Diagnostic.Severity), entry.Diagnostic.Code)
-	preview := fs.getPreview(entry)
-	diffBlock := fs.renderDiff(preview)
-
-	body := strings.Join([]string{header, "", meta, "", "Diff:", diffBlock}, "\n")
-
-	style := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(diagSecondaryColor)).
-		Width(width).
-		Height(fs.listHeight()+2).
-		Padding(0, 1)
-
-	return style.Render(body)
-}
-
-// Utility -----------------------------------------------------------------
-
-func truncateText(text string, width int) string {
-	if width <= 0 {
-		return ""
-	}
-	if lipgloss.Width(text) <= width {
-		return text
-	}
-	if width <= 3 {
-		return text[:width]
-	}
-	runes := []rune(text)
-	if len(runes) <= width {
-		return string(runes[:width])
-	}
-	return string(runes[:width-1]) + "…"
-}
-
-func (fs *FixModeScreen) previewKey(entry fixEntry) string {
-	id := entry.Fix.ID
-	if id == "" {
-		id = fmt.Sprintf("%s:%d", entry.Diagnostic.Code, entry.Diagnostic.Location.StartLine)
-	}
-	return filepath.Clean(entry.FilePath) + "::" + id
-}
-
-func (fs *FixModeScreen) getPreview(entry fixEntry) *diffPreview {
-	if fs.previewCache == nil {
-		fs.previewCache = make(map[string]*diffPreview)
-	}
-	key := fs.previewKey(entry)
-	if cached, ok := fs.previewCache[key]; ok {
-		return cached
-	}
-	preview := fs.buildPreview(entry)
-	fs.previewCache[key] = preview
-	return preview
-}
-
-func (fs *FixModeScreen) buildPreview(entry fixEntry) *diffPreview {
-	if len(entry.Fix.Edits) == 0 {
-		return &diffPreview{Diff: "(no edits provided)"}
-	}
-
-	var sb strings.Builder
-	var fileLines []string
-	var fileErr error
-	fileLoaded := false
-
-	loadFile := func() {
-		if fileLoaded {
-			return
-		}
-		data, err := os.ReadFile(entry.FilePath)
-		if err != nil {
-			fileErr = err
-		} else {
-			fileLines = strings.Split(string(data), "\n")
-		}
-		fileLoaded = true
-	}
-
-	for idx, edit := range entry.Fix.Edits {
-		loc := edit.Location
-		sb.WriteString(fmt.Sprintf("@@ %d:%d-%d:%d @@\n", loc.StartLine, loc.StartCol, loc.EndLine, loc.EndCol))
-
-		oldText := edit.OldText
-		if oldText == "" {
-			loadFile()
-			if fileErr == nil {
-				oldText = extractSegment(fileLines, loc)
-			}
-		}
-		if oldText == "" {
-			oldText = "(no original text)"
-		}
-		for _, line := range strings.Split(oldText, "\n") {
-			sb.WriteString("- " + line + "\n")
-		}
-
-		newText := edit.NewText
-		if newText == "" {
-			newText = "(delete)"
-		}
-		for _, line := range strings.Split(newText, "\n") {
-			sb.WriteString("+ " + line + "\n")
-		}
-
-		if idx < len(entry.Fix.Edits)-1 {
-			sb.WriteString("\n")
-		}
-	}
-
-	diff := strings.TrimSpace(sb.String())
-	if diff == "" {
-		diff = "(no diff)"
-	}
-	return &diffPreview{Diff: diff, Err: fileErr}
-}
-
-func extractSegment(lines []string, loc surge.LocationJSON) string {
-	if len(lines) == 0 {
-		return ""
-	}
-
-	startLine := int(loc.StartLine)
-	endLine := int(loc.EndLine)
-	if startLine == 0 {
-		startLine = endLine
-	}
-	if startLine == 0 {
-		startLine = 1
-	}
-	if endLine == 0 {
-		endLine = startLine
-	}
-	if startLine > len(lines) {
-		return ""
-	}
-	if endLine > len(lines) {
-		endLine = len(lines)
-	}
-
-	startIdx := startLine - 1
-	endIdx := endLine - 1
-	if endIdx < startIdx {
-		endIdx = startIdx
-	}
-
-	startCol := int(loc.StartCol)
-	if startCol <= 0 {
-		startCol = 1
-	}
-	endCol := int(loc.EndCol)
-	if endCol <= 0 {
-		endCol = len([]rune(lines[endIdx])) + 1
-	}
-
-	var parts []string
-	for i := startIdx; i <= endIdx; i++ {
-		lineRunes := []rune(lines[i])
-		lineLen := len(lineRunes)
-		var segment string
-
-		switch {
-		case startIdx == endIdx:
-			sc := clamp(startCol-1, 0, lineLen)
-			ec := clamp(endCol-1, sc, lineLen)
-			segment = string(lineRunes[sc:ec])
-		case i == startIdx:
-			sc := clamp(startCol-1, 0, lineLen)
-			segment = string(lineRunes[sc:])
-		case i == endIdx:
-			ec := clamp(endCol-1, 0, lineLen)
-			segment = string(lineRunes[:ec])
-		default:
-			segment = string(lineRunes)
-		}
-		parts = append(parts, segment)
-	}
-
-	return strings.Join(parts, "\n")
-}
-
-func clamp(value, min, max int) int {
-	if value < min {
-		return min
-	}
-	if value > max {
-		return max
-	}
-	return value
-}
-
-func (fs *FixModeScreen) renderDiff(preview *diffPreview) string {
-	if preview == nil {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor)).Render("(no diff)")
-	}
-	diff := preview.Diff
-	if diff == "" {
-		diff = "(no diff)"
-	}
-	lines := strings.Split(diff, "\n")
-	styled := make([]string, 0, len(lines)+1)
-	for _, line := range lines {
-		var style lipgloss.Style
-		switch {
-		case strings.HasPrefix(line, "+"):
-			style = lipgloss.NewStyle().Foreground(lipgloss.Color(fixDiffAddColor))
-		case strings.HasPrefix(line, "-"):
-			style = lipgloss.NewStyle().Foreground(lipgloss.Color(fixDiffDelColor))
-		case strings.HasPrefix(line, "@@"):
-			style = lipgloss.NewStyle().Foreground(lipgloss.Color(fixDiffMetaColor)).Bold(true)
-		default:
-			style = lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor))
-		}
-		styled = append(styled, style.Render(line))
-	}
-	if preview.Err != nil {
-		warning := lipgloss.NewStyle().Foreground(lipgloss.Color(fixDiffWarnColor)).Render("⚠ " + preview.Err.Error())
-		styled = append(styled, warning)
-	}
-	return strings.Join(styled, "\n")
-}
-
 func samePath(a, b string) bool {
 	if a == "" || b == "" {
 		return false
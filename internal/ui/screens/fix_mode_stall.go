@@ -0,0 +1,68 @@
+package screens
+
+import (
+	"context"
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Загрузка и применение фиксов идут под сторожем: если surge долго молчит,
+// строка статуса предупреждает об этом, и Esc отменяет операцию. Применение
+// Esc отменяет и без предупреждения; загрузку без него — нет, Esc работает
+// как обычно.
+
+// watchStalls подписывает операцию с контекстом ctx на сторожа вместо
+// прежней и возвращает её контекст с командой ожидания сообщений.
+func (fs *FixModeScreen) watchStalls(ctx context.Context) (context.Context, tea.Cmd) {
+	fs.stopStall()
+	watch, ctx, wait := watchStalls(ctx)
+	fs.stall = watch
+	return ctx, wait
+}
+
+func (fs *FixModeScreen) stopStall() {
+	fs.stall.stop()
+	fs.stall, fs.stallNote = nil, ""
+}
+
+func (fs *FixModeScreen) handleStall(msg stallMsg) tea.Cmd {
+	if msg.watch != fs.stall {
+		return nil
+	}
+	fs.stallNote = stallWarning(msg.stall)
+	return msg.watch.wait()
+}
+
+// Stop отменяет идущую операцию и забывает её, например при смене проекта.
+func (fs *FixModeScreen) Stop() {
+	fs.op.drop(fs.ops)
+	fs.stopStall()
+	fs.batch = nil
+	fs.loading = false
+	fs.reloadingFile = ""
+}
+
+// cancelOperation отменяет применение фиксов или загрузку, о молчании
+// которой предупредил сторож. Прерванная загрузка оставляет прежний список;
+// результат отменённого применения придёт сообщением и перезагрузит список.
+func (fs *FixModeScreen) cancelOperation() {
+	if fs.op.mutating() {
+		fs.op.interrupt()
+	} else {
+		fs.op.drop(fs.ops)
+	}
+	fs.stopStall()
+	fs.reloadingFile = ""
+	if fs.loading {
+		fs.loading = false
+		if len(fs.all) == 0 {
+			fs.err = errors.New("loading cancelled; press Ctrl+R to retry")
+		}
+		fs.setStatus("Loading cancelled (Ctrl+R to retry)")
+		return
+	}
+	if fs.batch == nil {
+		fs.setStatus("Cancelling…")
+	}
+}
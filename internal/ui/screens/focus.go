@@ -0,0 +1,26 @@
+package screens
+
+import "surge-tui/internal/ui/styles"
+
+// У экранов диагностики и Fix Mode фокус переключается Tab между списком и
+// панелью деталей. Оформление то же, что у панелей экрана проекта: цвет рамки
+// и маркер из темы — маркер виден и без цвета, в светлой теме и на
+// терминалах с урезанной палитрой.
+
+// SetTheme переключает общие цвета и оформление фокуса.
+func (ds *DiagnosticsScreen) SetTheme(theme *styles.Theme) {
+	ds.BaseScreen.SetTheme(theme)
+	ds.focus = theme.Focus()
+}
+
+// SetTheme переключает общие цвета и оформление фокуса.
+func (fs *FixModeScreen) SetTheme(theme *styles.Theme) {
+	fs.BaseScreen.SetTheme(theme)
+	fs.focus = theme.Focus()
+}
+
+// focusCounter дополняет счётчик позиции в заголовке списка маркером фокуса;
+// без фокуса на его месте пробел, чтобы заголовок не сдвигался.
+func focusCounter(focus styles.FocusStyle, counter string, focused bool) string {
+	return counter + " " + focus.Marker(focused)
+}
@@ -0,0 +1,85 @@
+package screens
+
+import (
+	"strings"
+	"testing"
+
+	"surge-tui/internal/core/surge"
+	"surge-tui/internal/ui/styles"
+)
+
+// focusCase рисует экран с фокусом на первой (focused=false) или второй панели.
+type focusCase struct {
+	name   string
+	render func(t *testing.T, theme *styles.Theme, second bool) string
+}
+
+var focusCases = []focusCase{
+	{"project", func(t *testing.T, theme *styles.Theme, second bool) string {
+		ps := newTestProject(t, newTestTab(t, "main.sg", "fn main() {}"))
+		ps.SetTheme(theme)
+		ps.SetSize(120, 30)
+		ps.recalculateLayout()
+		ps.focusedPanel = FileTreePanel
+		if second {
+			ps.focusedPanel = EditorPanel
+		}
+		return ps.View()
+	}},
+	{"diagnostics", func(t *testing.T, theme *styles.Theme, second bool) string {
+		ds := NewDiagnosticsScreen(t.TempDir(), nil)
+		ds.SetTheme(theme)
+		ds.SetSize(120, 30)
+		ds.setEntries([]DiagnosticEntry{
+			{Severity: "error", Code: "E1", Message: "undefined x", File: "main.sg", Line: 1, Column: 1},
+		})
+		ds.detailFocused = second
+		return ds.View()
+	}},
+	{"fix mode", func(t *testing.T, theme *styles.Theme, second bool) string {
+		fs := NewFixModeScreen(t.TempDir(), nil)
+		fs.SetTheme(theme)
+		fs.SetSize(120, 30)
+		fs.loading = false
+		fs.setEntries([]fixEntry{
+			{FilePath: "main.sg", Diagnostic: surge.DiagnosticJSON{Severity: "error", Message: "unused"}, Fix: surge.FixJSON{ID: "f1", Title: "remove"}},
+		})
+		fs.detailFocused = second
+		return fs.View()
+	}},
+}
+
+// Панель с фокусом отмечена маркером: он виден и без цвета, поэтому
+// варианты различаются даже в выводе без ANSI и в светлой теме.
+func TestFocusedPanelsRenderDifferently(t *testing.T) {
+	for _, themeName := range []string{"dark", "light"} {
+		theme := styles.NewTheme(themeName)
+		for _, tc := range focusCases {
+			t.Run(themeName+"/"+tc.name, func(t *testing.T) {
+				first := tc.render(t, theme, false)
+				second := tc.render(t, theme, true)
+				if first == second {
+					t.Fatalf("focus change did not change the rendering:\n%s", first)
+				}
+				for _, view := range []string{first, second} {
+					if !strings.Contains(view, styles.FocusMarker) {
+						t.Errorf("focus marker missing:\n%s", view)
+					}
+				}
+			})
+		}
+	}
+}
+
+// В светлой теме рамка с фокусом отличается от обычной, как и в тёмной.
+func TestFocusStyleDistinctInThemes(t *testing.T) {
+	for _, name := range []string{"dark", "light"} {
+		focus := styles.NewTheme(name).Focus()
+		if focus.BorderColor(true) == focus.BorderColor(false) {
+			t.Errorf("%s theme: focused border color equals the unfocused one", name)
+		}
+		if focus.RenderTitle("Files", true) == focus.RenderTitle("Files", false) {
+			t.Errorf("%s theme: focused title equals the unfocused one", name)
+		}
+	}
+}
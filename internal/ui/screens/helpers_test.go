@@ -0,0 +1,109 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+)
+
+// newTestTab открывает вкладку на временном файле name с содержимым lines.
+func newTestTab(t *testing.T, name string, lines ...string) *editorTab {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tab, err := newEditorTab(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tab
+}
+
+// moveTo ставит курсор на 0-based строку и колонку (setCursorPosition — 1-based).
+func moveTo(tab *editorTab, line, col int) {
+	tab.cursor = cursorPosition{Line: line, Col: col}
+	tab.clampCursor()
+}
+
+// newTestProject открывает экран проекта в каталоге вкладки tab: вкладка
+// активна, фокус в редакторе, отступ — 4 пробела.
+func newTestProject(t *testing.T, tab *editorTab) *ProjectScreenReal {
+	t.Helper()
+	ps := NewProjectScreenReal(filepath.Dir(tab.path))
+	cfg := config.DefaultConfig().Editor
+	cfg.TabSize, cfg.UseSpaces = 4, true
+	ps.SetEditorConfig(cfg)
+	ps.loading = false
+	ps.tabs = []*editorTab{tab}
+	ps.activeTab = 0
+	ps.focusedPanel = EditorPanel
+	return ps
+}
+
+// keyMsg собирает нажатие по имени клавиши Bubble Tea ("tab", "shift+tab")
+// или по набранным символам.
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "shift+tab":
+		return tea.KeyMsg{Type: tea.KeyShiftTab}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "ctrl+d":
+		return tea.KeyMsg{Type: tea.KeyCtrlD}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// press отправляет экрану нажатия по очереди.
+func press(s Screen, keys ...string) Screen {
+	for _, key := range keys {
+		s, _ = s.Update(keyMsg(key))
+	}
+	return s
+}
+
+// awaitMsg выполняет команду cmd вместе с вложенными в tea.Batch и возвращает
+// первое сообщение типа T. Команды, которые ждут дольше (сторож зависаний),
+// не мешают: каждая выполняется в своей горутине.
+func awaitMsg[T tea.Msg](t *testing.T, cmd tea.Cmd) T {
+	t.Helper()
+	found := make(chan T, 1)
+	var run func(cmd tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		go func() {
+			switch msg := cmd().(type) {
+			case tea.BatchMsg:
+				for _, sub := range msg {
+					run(sub)
+				}
+			case T:
+				select {
+				case found <- msg:
+				default:
+				}
+			}
+		}()
+	}
+	run(cmd)
+	select {
+	case msg := <-found:
+		return msg
+	case <-time.After(5 * time.Second):
+		var zero T
+		t.Fatalf("no %T message", zero)
+		return zero
+	}
+}
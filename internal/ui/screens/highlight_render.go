@@ -0,0 +1,89 @@
+package screens
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/syntax"
+)
+
+// lineDecor описывает курсор и выделение поверх подсвеченной строки.
+type lineDecor struct {
+	cursorCol   int // -1 — курсора на строке нет
+	cursorStyle lipgloss.Style
+	selFrom     int // выделение [selFrom, selTo); пустое, если selFrom >= selTo
+	selTo       int
+	selStyle    lipgloss.Style
+	brackets    map[int]lipgloss.Style // скобка у курсора и её пара по колонкам
+}
+
+func noDecor() lineDecor {
+	return lineDecor{cursorCol: -1}
+}
+
+// renderHighlightedLine рисует строку с подсветкой токенов, курсором и выделением.
+// tokens может быть nil — тогда строка выводится без подсветки.
+func renderHighlightedLine(runes []rune, tokens []syntax.Token, theme *syntax.HighlightTheme, decor lineDecor) string {
+	var b strings.Builder
+	selected := func(i int) bool { return i >= decor.selFrom && i < decor.selTo }
+
+	write := func(kind syntax.TokenKind, start, end int) {
+		if end <= start {
+			return
+		}
+		cuts := []int{start, end}
+		points := []int{decor.cursorCol, decor.cursorCol + 1, decor.selFrom, decor.selTo}
+		for col := range decor.brackets {
+			points = append(points, col, col+1)
+		}
+		for _, p := range points {
+			if p > start && p < end {
+				cuts = append(cuts, p)
+			}
+		}
+		sort.Ints(cuts)
+		for i := 0; i+1 < len(cuts); i++ {
+			from, to := cuts[i], cuts[i+1]
+			if from == to {
+				continue
+			}
+			style := theme.Style(kind)
+			bracket, isBracket := decor.brackets[from]
+			switch {
+			case from == decor.cursorCol:
+				style = decor.cursorStyle
+			case isBracket:
+				style = bracket.Inherit(style)
+			case selected(from):
+				style = decor.selStyle.Inherit(style)
+			}
+			b.WriteString(style.Render(string(runes[from:to])))
+		}
+	}
+
+	pos := 0
+	for _, tok := range tokens {
+		start := min(max(tok.Start, pos), len(runes))
+		end := min(tok.End, len(runes))
+		if start > pos {
+			write(syntax.TokenText, pos, start)
+		}
+		write(tok.Kind, start, end)
+		if end > pos {
+			pos = end
+		}
+	}
+	if pos < len(runes) {
+		write(syntax.TokenText, pos, len(runes))
+	}
+
+	switch {
+	case decor.cursorCol >= len(runes):
+		b.WriteString(decor.cursorStyle.Render(" "))
+	case selected(len(runes)):
+		// выделение захватывает конец строки
+		b.WriteString(decor.selStyle.Render(" "))
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Экран диагностики и Fix Mode показывают одни и те же проблемы. App
+// запоминает «текущую проблему» экрана, с которого уходят, и передаёт её
+// экрану, на который пришли: тот выбирает совпадающую запись, если она есть.
+// Применённый фикс помечает свою диагностику исправленной до следующего
+// запуска surge diag.
+
+// IssueRef — «текущая проблема»: файл, код и начало диагностики.
+type IssueRef struct {
+	File   string // абсолютный путь
+	Code   string
+	Line   int
+	Column int
+}
+
+// IsZero сообщает, что проблема не задана.
+func (r IssueRef) IsZero() bool {
+	return r.File == ""
+}
+
+// Matches сообщает, что r и other — одна и та же диагностика.
+func (r IssueRef) Matches(other IssueRef) bool {
+	return r.sameCode(other) && r.Line == other.Line && r.Column == other.Column
+}
+
+// sameCode — тот же файл и код; место могло сдвинуться после правок.
+func (r IssueRef) sameCode(other IssueRef) bool {
+	return samePath(r.File, other.File) && strings.EqualFold(r.Code, other.Code)
+}
+
+// key — ключ для множества исправленных диагностик.
+func (r IssueRef) key() string {
+	return fmt.Sprintf("%s:%s:%d:%d", strings.ToLower(filepath.Clean(r.File)), strings.ToLower(r.Code), r.Line, r.Column)
+}
+
+// IssuesResolvedMsg — Fix Mode применил фиксы этих диагностик.
+type IssuesResolvedMsg struct {
+	Issues []IssueRef
+}
+
+// issueOfDiagnostic — проблема записи экрана диагностики.
+func issueOfDiagnostic(entry DiagnosticEntry) IssueRef {
+	return IssueRef{File: entry.AbsPath, Code: entry.Code, Line: entry.Line, Column: max(entry.Column, 1)}
+}
+
+// issueOfFix — проблема, которую исправляет фикс; позиция считается так же,
+// как в записях экрана диагностики.
+func issueOfFix(entry fixEntry) IssueRef {
+	path := entry.FilePath
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	loc := entry.Diagnostic.Location
+	return IssueRef{File: path, Code: entry.Diagnostic.Code, Line: int(loc.StartLine), Column: max(int(loc.StartCol), 1)}
+}
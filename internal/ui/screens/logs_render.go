@@ -0,0 +1,130 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"surge-tui/internal/logging"
+	"surge-tui/internal/ui/components"
+)
+
+const logsHeaderHeight = 4
+
+func (ls *LogsScreen) View() string {
+	if ls.Width() == 0 {
+		return "Initializing logs view..."
+	}
+	width := ls.Width()
+	sections := []string{ls.renderHeader(width), ls.renderLog(width), ls.renderFooter(width)}
+	return strings.Join(sections, "\n")
+}
+
+// logHeight — строк журнала между шапкой и подвалом.
+func (ls *LogsScreen) logHeight() int {
+	return max(ls.Height()-logsHeaderHeight-3, 1)
+}
+
+func (ls *LogsScreen) renderHeader(width int) string {
+	secondary := lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor))
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(diagHeaderColor)).Render("Logs")
+
+	file := "File: (memory only)"
+	fileStyle := secondary
+	if ls.logger != nil {
+		path, err := ls.logger.FilePath()
+		switch {
+		case err != nil:
+			file = fmt.Sprintf("File: %s — %v", path, err)
+			fileStyle = fileStyle.Foreground(lipgloss.Color(diagErrorColor))
+		case path != "":
+			file = "File: " + truncatePath(path, max(width-6, 10))
+		}
+	}
+
+	follow := "off"
+	if ls.follow {
+		follow = "on"
+	}
+	counts := fmt.Sprintf("%d of %d entries  •  level ≥ %s  •  follow %s",
+		len(ls.visible), len(ls.entries), ls.minLevel, follow)
+	if ls.notices {
+		counts += "  •  notifications only"
+	}
+	if len(ls.visible) > 0 {
+		counts += "  •  " + components.ScrollIndicator(ls.scroll, ls.logHeight(), len(ls.visible))
+	}
+
+	lines := []string{
+		title,
+		fileStyle.Render(truncateString(file, width)),
+		secondary.Render(truncateString(counts, width)),
+		"",
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (ls *LogsScreen) renderLog(width int) string {
+	height := ls.logHeight()
+	if len(ls.visible) == 0 {
+		msg := "No log entries."
+		switch {
+		case len(ls.entries) > 0 && ls.notices:
+			msg = fmt.Sprintf("No notifications at level %s or above.", ls.minLevel)
+		case len(ls.entries) > 0:
+			msg = fmt.Sprintf("No entries at level %s or above.", ls.minLevel)
+		}
+		return lipgloss.NewStyle().Width(width).Height(height).
+			Foreground(lipgloss.Color(diagSecondaryColor)).Render(msg)
+	}
+
+	showBar := components.ScrollbarVisible(len(ls.visible), height)
+	lineWidth := width
+	if showBar {
+		lineWidth = max(width-1, 1)
+	}
+	start, end := components.VisibleWindow(ls.scroll, height, len(ls.visible))
+	rows := make([]string, 0, height)
+	for i := start; i < end; i++ {
+		entry := ls.entries[ls.visible[i]]
+		style := logLevelStyle(entry.Level).Width(lineWidth)
+		if i == ls.selected {
+			style = style.Background(lipgloss.Color(diagSelectedBg)).Foreground(lipgloss.Color(diagSelectedFg))
+		}
+		// уведомления, показанные тостом, помечены ◆
+		mark := " "
+		if entry.Notice {
+			mark = "◆"
+		}
+		text := fmt.Sprintf("%s %-5s %s %s", entry.Time.Format("15:04:05.000"), entry.Level, mark, entry.Message)
+		rows = append(rows, style.Render(truncateString(text, lineWidth)))
+	}
+	for len(rows) < height {
+		rows = append(rows, "")
+	}
+	logView := lipgloss.NewStyle().Width(lineWidth).Render(strings.Join(rows, "\n"))
+	if showBar {
+		bar := components.RenderScrollbar(len(ls.visible), ls.scroll, height, height)
+		logView = lipgloss.JoinHorizontal(lipgloss.Top, logView, strings.Join(bar, "\n"))
+	}
+	return logView
+}
+
+func logLevelStyle(level logging.Level) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	switch level {
+	case logging.LevelError:
+		return style.Foreground(lipgloss.Color(diagErrorColor))
+	case logging.LevelWarn:
+		return style.Foreground(lipgloss.Color(diagWarningColor))
+	case logging.LevelDebug:
+		return style.Foreground(lipgloss.Color(diagSecondaryColor))
+	}
+	return style
+}
+
+func (ls *LogsScreen) renderFooter(width int) string {
+	hint := "f follow • l level • n notifications • c clear • ↑↓ scroll"
+	return "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(diagSecondaryColor)).Render(truncateString(hint, width))
+}
@@ -0,0 +1,210 @@
+package screens
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"surge-tui/internal/logging"
+	"surge-tui/internal/platform"
+)
+
+// logsRefresh — опрос журнала, пока экран открыт
+const logsRefresh = 500 * time.Millisecond
+
+// LogsScreen показывает журнал приложения: запуски surge, ошибки сохранения
+// и диагностики. В режиме слежения выделение держится на последней записи.
+type LogsScreen struct {
+	BaseScreen
+
+	logger   *logging.Logger
+	seq      uint64 // Seq журнала на момент последнего чтения
+	entries  []logging.Entry
+	visible  []int // индексы entries, прошедшие фильтр уровня
+	minLevel logging.Level
+	notices  bool // только уведомления — история тостов приложения
+	follow   bool
+
+	selected int
+	scroll   int
+	tickGen  int // тики прошлых входов на экран отбрасываются
+}
+
+type logsTickMsg struct {
+	gen int
+}
+
+// NewLogsScreen создаёт экран журнала logger.
+func NewLogsScreen(logger *logging.Logger) *LogsScreen {
+	ls := &LogsScreen{
+		BaseScreen: NewBaseScreen("Logs"),
+		logger:     logger,
+		minLevel:   logging.LevelDebug,
+		follow:     true,
+	}
+	ls.reload()
+	return ls
+}
+
+func (ls *LogsScreen) Init() tea.Cmd {
+	return nil
+}
+
+// OnEnter перечитывает журнал и запускает опрос новых записей.
+func (ls *LogsScreen) OnEnter() tea.Cmd {
+	ls.reload()
+	ls.tickGen++
+	return ls.tick()
+}
+
+func (ls *LogsScreen) tick() tea.Cmd {
+	gen := ls.tickGen
+	return tea.Tick(logsRefresh, func(time.Time) tea.Msg { return logsTickMsg{gen: gen} })
+}
+
+func (ls *LogsScreen) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	switch m := msg.(type) {
+	case tea.WindowSizeMsg:
+		ls.SetSize(m.Width, m.Height-1)
+		ls.ensureSelectionVisible()
+	case tea.KeyMsg:
+		ls.handleKey(m)
+	case logsTickMsg:
+		// тик приходит, только пока экран активен: на других экранах цепочка обрывается
+		if m.gen != ls.tickGen {
+			return ls, nil
+		}
+		if ls.logger.Seq() != ls.seq {
+			ls.reload()
+		}
+		return ls, ls.tick()
+	}
+	return ls, nil
+}
+
+// reload перечитывает буфер журнала и применяет фильтр уровня.
+func (ls *LogsScreen) reload() {
+	if ls.logger == nil {
+		return
+	}
+	current, ok := ls.selectedEntry()
+	ls.seq = ls.logger.Seq()
+	ls.entries = ls.logger.Entries()
+	ls.applyFilter(current, ok)
+}
+
+func (ls *LogsScreen) selectedEntry() (logging.Entry, bool) {
+	if ls.selected >= len(ls.visible) {
+		return logging.Entry{}, false
+	}
+	return ls.entries[ls.visible[ls.selected]], true
+}
+
+// applyFilter отбирает записи по уровню. Без слежения выделение остаётся на
+// записи current или ближайшей более ранней: индексы сдвигаются, когда
+// переполненный буфер вытесняет старые записи.
+func (ls *LogsScreen) applyFilter(current logging.Entry, ok bool) {
+	ls.visible = ls.visible[:0]
+	for i, entry := range ls.entries {
+		if entry.Level >= ls.minLevel && (!ls.notices || entry.Notice) {
+			ls.visible = append(ls.visible, i)
+		}
+	}
+	if ls.follow || !ok {
+		ls.setSelection(len(ls.visible) - 1)
+		return
+	}
+	index := 0
+	for i, entryIndex := range ls.visible {
+		if ls.entries[entryIndex].ID <= current.ID {
+			index = i
+		}
+	}
+	ls.setSelection(index)
+}
+
+func (ls *LogsScreen) handleKey(msg tea.KeyMsg) {
+	switch platform.CanonicalKeyForLookup(msg.String()) {
+	case "up", "k":
+		ls.moveSelection(-1)
+	case "down", "j":
+		ls.moveSelection(1)
+	case "pgup", "ctrl+u":
+		ls.moveSelection(-ls.logHeight())
+	case "pgdown", "ctrl+d":
+		ls.moveSelection(ls.logHeight())
+	case "home", "g":
+		ls.follow = false
+		ls.setSelection(0)
+	case "end", "G":
+		ls.follow = true
+		ls.setSelection(len(ls.visible) - 1)
+	case "f":
+		ls.follow = !ls.follow
+		if ls.follow {
+			ls.setSelection(len(ls.visible) - 1)
+		}
+	case "l":
+		current, ok := ls.selectedEntry()
+		ls.minLevel = (ls.minLevel + 1) % (logging.LevelError + 1)
+		ls.applyFilter(current, ok)
+	case "n":
+		current, ok := ls.selectedEntry()
+		ls.notices = !ls.notices
+		ls.applyFilter(current, ok)
+	case "c":
+		if ls.logger != nil {
+			ls.logger.Clear()
+		}
+		ls.follow = true
+		ls.reload()
+	}
+}
+
+// moveSelection двигает выделение; уход с последней записи выключает
+// слежение, возврат на неё — включает.
+func (ls *LogsScreen) moveSelection(delta int) {
+	ls.setSelection(ls.selected + delta)
+	ls.follow = ls.selected == len(ls.visible)-1
+}
+
+func (ls *LogsScreen) setSelection(index int) {
+	if len(ls.visible) == 0 {
+		ls.selected, ls.scroll = 0, 0
+		return
+	}
+	ls.selected = clampInt(index, 0, len(ls.visible)-1)
+	ls.ensureSelectionVisible()
+}
+
+func (ls *LogsScreen) ensureSelectionVisible() {
+	height := ls.logHeight()
+	if height <= 0 {
+		return
+	}
+	if ls.selected < ls.scroll {
+		ls.scroll = ls.selected
+	} else if ls.selected >= ls.scroll+height {
+		ls.scroll = ls.selected - height + 1
+	}
+	ls.scroll = clampInt(ls.scroll, 0, max(len(ls.visible)-height, 0))
+}
+
+func (ls *LogsScreen) ShortHelp() string {
+	return "f Follow • l Level filter • n Notifications • c Clear • ↑↓ Scroll • g/G Top/bottom"
+}
+
+func (ls *LogsScreen) FullHelp() []string {
+	help := ls.BaseScreen.FullHelp()
+	help = append(help, []string{
+		"",
+		"Logs Screen:",
+		"  ↑/↓ or j/k, PgUp/PgDn - Move through the log",
+		"  g / G - First entry / last entry and follow new ones",
+		"  f - Toggle follow mode (stay on the newest entry)",
+		"  l - Cycle the minimum level: DEBUG, INFO, WARN, ERROR",
+		"  n - Show only notifications (history of toasts above the status bar)",
+		"  c - Clear the in-memory log (the log file is kept)",
+	}...)
+	return help
+}
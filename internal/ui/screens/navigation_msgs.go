@@ -5,6 +5,22 @@ type OpenLocationMsg struct {
 	FilePath string
 	Line     int
 	Column   int
+	From     string // screen to offer "go back" to, e.g. "Fix Mode"; empty — no breadcrumb
+}
+
+// ProjectLoadedMsg сообщает, что экран проекта загрузил дерево файлов.
+type ProjectLoadedMsg struct {
+	Path string
+}
+
+// FileSavedMsg сообщает, что файл записан на диск из редактора.
+type FileSavedMsg struct {
+	Path string
+}
+
+// RunFileDiagnosticsMsg просит приложение перезапустить диагностику одного файла.
+type RunFileDiagnosticsMsg struct {
+	Path string
 }
 
 // OpenFixModeMsg просит приложение открыть экран фиксов, опционально сфокусировав фикс.
@@ -0,0 +1,20 @@
+package screens
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"surge-tui/internal/logging"
+)
+
+// NotifyMsg просит приложение показать уведомление над статус-баром. В
+// отличие от строки статуса экрана, оно видно на любом экране и остаётся в
+// истории уведомлений на экране логов.
+type NotifyMsg struct {
+	Level logging.Level
+	Text  string
+}
+
+// Notify возвращает команду, отправляющую уведомление.
+func Notify(level logging.Level, text string) tea.Cmd {
+	return func() tea.Msg { return NotifyMsg{Level: level, Text: text} }
+}
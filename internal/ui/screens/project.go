@@ -1,7 +1,6 @@
 package screens
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"time"
@@ -9,9 +8,13 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/config"
+	core "surge-tui/internal/core/surge"
 	"surge-tui/internal/fs"
 	"surge-tui/internal/platform"
+	"surge-tui/internal/syntax"
 	"surge-tui/internal/ui/components"
+	"surge-tui/internal/ui/styles"
 )
 
 const (
@@ -23,13 +26,6 @@ const (
 	// Display constants
 	MaxDisplayLines = 6 // Резерв строк для заголовков и рамок
 	ScrollOffset    = 2 // Отступ при прокрутке
-
-	// Colors
-	ActiveBorderColor   = "#7C3AED"
-	InactiveBorderColor = "#334155"
-	LoadingColor        = "#7C3AED"
-	ErrorColor          = "#EF4444"
-	DimTextColor        = "#94A3B8"
 )
 
 // PanelType тип панели на экране
@@ -51,15 +47,33 @@ type ProjectScreenReal struct {
 	err         error
 
 	// UI состояние
-	focusedPanel  PanelType
-	statusInfo    ProjectStatus
-	statusMsg     string
-	statusAt      time.Time
-	confirm       *components.ConfirmDialog
-	closeDialog   *components.ConfirmDialog
-	newFileDialog *components.InputDialog
-	newDirDialog  *components.InputDialog
-	renameDialog  *components.InputDialog
+	focusedPanel   PanelType
+	statusInfo     ProjectStatus
+	status         statusQueue
+	confirm        *components.ConfirmDialog
+	closeDialog    *components.ConfirmDialog
+	newFileDialog  *components.InputDialog
+	newDirDialog   *components.InputDialog
+	renameDialog   *components.InputDialog
+	patternDialog  *components.InputDialog // переименование по шаблону
+	renamePreview  *components.ConfirmDialog
+	lossyDialog    *components.ChoiceDialog
+	fixDialog      *components.ChoiceDialog
+	saveAsDialog   *components.InputDialog
+	saveAsConfirm  *components.ConfirmDialog // :saveas поверх существующего файла
+	moveDialog     *components.InputDialog
+	copyToDialog   *components.InputDialog // Copy to New File и Extract Selection to New File
+	revertDialog   *components.ChoiceDialog
+	conflictDialog *components.ChoiceDialog
+	pasteDialog    *components.ChoiceDialog
+	pasteConfirm   *components.ConfirmDialog
+	saveWarnDialog *components.ConfirmDialog // маркеры конфликта или ошибки перед сохранением
+	recoverDialog  *components.ChoiceDialog
+	rollbackDialog *components.ChoiceDialog
+	rollbackDiff   *components.ConfirmDialog // diff выбранного состояния с буфером
+	quickOpen      *quickOpenDialog
+	treeLegend     *treeDiagLegend
+	problems       problemsDrawer // панель проблем внизу экрана
 
 	// Размеры панелей
 	treeWidth int
@@ -71,9 +85,55 @@ type ProjectScreenReal struct {
 	yankBuffer     string
 	tabActiveStyle lipgloss.Style
 	tabNormalStyle lipgloss.Style
+	highlight      *syntax.HighlightTheme
+	focus          styles.FocusStyle
+	icons          styles.IconSet    // значки файлов в дереве и вкладках
+	hintsEnabled   bool              // подсказки клавиш в подвале панели с фокусом
+	commandKeys    map[string]string // клавиши команд реестра по ID
+	editorCfg      config.EditorConfig
+	diagnostics    map[string][]EditorDiagnostic
+	treeSeverity   map[string]int // ранг самой серьёзной диагностики файла или каталога
+	treeIgnore     []string
+	treeWatcher    *fs.TreeWatcher
+	treeWatchGen   int
+	treeClip       *treeClip    // y/x в дереве, вставка по p
+	paste          *pasteBatch  // идущая вставка из буфера дерева
+	deferredCmd    tea.Cmd      // команда, созданная вне Update (ожидание диалога)
+	recoverQueue   []*editorTab // вкладки, ждущие вопроса о восстановлении копии
+	client         core.SurgeRunner
+	ops            *ProjectOps // общий реестр операций над файлами проекта
+	surgeAvailable bool        // surge найден: пустой каталог предлагает surge init
+	backTo         string      // экран, откуда пришли по OpenLocation ("Fix Mode"); показывается в строке редактора
+
+	sessionEnabled   bool
+	sessionScreen    string // экран из прошлой сессии или на момент выхода
+	restoringSession bool
+
+	// ошибка доступа к корню проекта; nil — проект доступен
+	unavailable  error
+	rootCheckSeq int
 
 	// Командная строка редактора
 	editorCommand textinput.Model
+
+	// Мышь: двойной клик в дереве и редакторе, выделение перетаскиванием
+	lastClickAt     time.Time
+	lastClickIndex  int
+	lastEditorClick time.Time
+	lastEditorPos   cursorPosition
+	dragging        bool
+	dragAnchor      cursorPosition
+
+	// Граница между деревом и редактором, перетаскиваемая мышью
+	splitDragging    bool
+	splitWidth       int // ширина дерева, заданная перетаскиванием; 0 — по фокусу
+	lastSplitClickAt time.Time
+
+	// Следование дерева за активной вкладкой
+	follow followState
+
+	// Переключение между файлом и его тестом
+	tests testToggle
 }
 
 // ProjectStatus информация о статусе проекта
@@ -84,6 +144,7 @@ type ProjectStatus struct {
 	WarningCount int
 	FileCount    int
 	DirCount     int
+	MarkedCount  int // отмеченные в дереве элементы
 }
 
 // NewProjectScreenReal создает новый экран проекта
@@ -100,7 +161,7 @@ func NewProjectScreenReal(projectPath string) *ProjectScreenReal {
 	cmdInput.Width = 40
 	cmdInput.Blur()
 
-	return &ProjectScreenReal{
+	ps := &ProjectScreenReal{
 		BaseScreen:     NewBaseScreen("Project"),
 		projectPath:    projectPath,
 		focusedPanel:   FileTreePanel,
@@ -110,58 +171,80 @@ func NewProjectScreenReal(projectPath string) *ProjectScreenReal {
 		newFileDialog:  components.NewInputDialog("New File", "Enter file name"),
 		newDirDialog:   components.NewInputDialog("New Directory", "Enter directory name"),
 		renameDialog:   components.NewInputDialog("Rename", "Enter new name"),
+		patternDialog:  components.NewInputDialog("Rename with Pattern", "s/old/new/, pre_* or *_v2"),
+		renamePreview:  components.NewConfirmDialog("Rename Preview", ""),
+		lossyDialog:    components.NewChoiceDialog("Lossy Save", ""),
+		fixDialog:      components.NewChoiceDialog("Apply Fix", ""),
+		saveAsDialog:   components.NewInputDialog("Save As", "Enter file path (relative to project)"),
+		saveAsConfirm:  components.NewConfirmDialog("File Exists", ""),
+		moveDialog:     components.NewInputDialog("Move File", "Enter new path (relative to project)"),
+		copyToDialog:   components.NewInputDialog("New File", "Enter path of the new file (relative to project)"),
+		revertDialog:   components.NewChoiceDialog("Revert File", ""),
+		conflictDialog: components.NewChoiceDialog("Save Conflict", ""),
+		pasteDialog:    components.NewChoiceDialog("Paste Conflict", ""),
+		pasteConfirm:   components.NewConfirmDialog("Large Paste", ""),
+		saveWarnDialog: components.NewConfirmDialog("Save Anyway?", ""),
+		recoverDialog:  components.NewChoiceDialog("Recover Autosave", ""),
+		rollbackDialog: components.NewChoiceDialog("Rollback Buffer", ""),
+		rollbackDiff:   components.NewConfirmDialog("Rollback Preview", ""),
+		treeLegend:     newTreeDiagLegend(),
+		treeIgnore:     config.DefaultConfig().UI.TreeIgnore,
 		editorCommand:  cmdInput,
+		editorCfg:      config.DefaultConfig().Editor,
+		focus:          styles.DefaultFocus(),
+		icons:          styles.DefaultIcons(),
+		hintsEnabled:   true,
 		activeTab:      -1,
-		tabActiveStyle: lipgloss.NewStyle().Background(lipgloss.Color("#7C3AED")).Foreground(lipgloss.Color("#FFFFFF")).Padding(0, 1).Bold(true),
-		tabNormalStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("#CBD5F5")).Padding(0, 1),
+		tests:          newTestToggle(config.DefaultConfig().Tests),
 	}
+	ps.tabActiveStyle, ps.tabNormalStyle = tabStyles()
+	return ps
+}
+
+// tabStyles строит стили вкладок из текущих цветов темы.
+func tabStyles() (active, normal lipgloss.Style) {
+	active = lipgloss.NewStyle().Background(lipgloss.Color(selectedColor)).Foreground(lipgloss.Color(onSelectedColor)).Padding(0, 1).Bold(true)
+	normal = lipgloss.NewStyle().Foreground(lipgloss.Color(barTextColor)).Padding(0, 1)
+	return active, normal
+}
+
+// SetTheme переключает цвета экрана и пересобирает стили вкладок и рамок.
+func (ps *ProjectScreenReal) SetTheme(theme *styles.Theme) {
+	ps.BaseScreen.SetTheme(theme)
+	ps.tabActiveStyle, ps.tabNormalStyle = tabStyles()
+	ps.SetFocusStyle(theme.Focus())
 }
 
 // Init инициализирует экран
 func (ps *ProjectScreenReal) Init() tea.Cmd {
-	return ps.loadFileTree()
+	return tea.Batch(ps.loadFileTree(), ps.startRootChecks())
 }
 
-// Update обрабатывает сообщения
-func (ps *ProjectScreenReal) Update(msg tea.Msg) (Screen, tea.Cmd) {
-	if ps.closeDialog != nil && ps.closeDialog.Visible {
-		if cmd := ps.closeDialog.Update(msg); cmd != nil {
-			return ps, cmd
-		}
-		if _, ok := msg.(tea.KeyMsg); ok {
-			return ps, nil
-		}
-	}
-
-	if ps.confirm != nil && ps.confirm.Visible {
-		if cmd := ps.confirm.Update(msg); cmd != nil {
-			return ps, cmd
-		}
-		if _, ok := msg.(tea.KeyMsg); ok {
-			return ps, nil
-		}
-	}
+// OnEnter перезапускает фоновую проверку корня проекта и наблюдение за деревом.
+func (ps *ProjectScreenReal) OnEnter() tea.Cmd {
+	ps.flushAutosaves()
+	ps.refreshActiveChanges()
+	return tea.Batch(ps.startRootChecks(), ps.resumeTreeWatch())
+}
 
-	if ps.newFileDialog != nil && ps.newFileDialog.Visible {
-		if cmd := ps.newFileDialog.Update(msg); cmd != nil {
-			return ps, cmd
-		}
-		if _, ok := msg.(tea.KeyMsg); ok {
-			return ps, nil
-		}
-	}
+// OnExit дописывает копии автосохранения: отсчёт до экрана уже не дойдёт.
+func (ps *ProjectScreenReal) OnExit() tea.Cmd {
+	ps.flushAutosaves()
+	return nil
+}
 
-	if ps.newDirDialog != nil && ps.newDirDialog.Visible {
-		if cmd := ps.newDirDialog.Update(msg); cmd != nil {
-			return ps, cmd
-		}
-		if _, ok := msg.(tea.KeyMsg); ok {
-			return ps, nil
-		}
-	}
+// Update обрабатывает сообщения
+func (ps *ProjectScreenReal) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	screen, cmd := ps.update(msg)
+	deferred := ps.deferredCmd
+	ps.deferredCmd = nil
+	ps.trackFollow()
+	return screen, tea.Batch(cmd, deferred, ps.trackAutosave(), ps.trackChanges(), ps.trackCheckpoints(), ps.status.schedule())
+}
 
-	if ps.renameDialog != nil && ps.renameDialog.Visible {
-		if cmd := ps.renameDialog.Update(msg); cmd != nil {
+func (ps *ProjectScreenReal) update(msg tea.Msg) (Screen, tea.Cmd) {
+	if dialog := ps.activeDialog(); dialog != nil {
+		if cmd := dialog.Update(msg); cmd != nil {
 			return ps, cmd
 		}
 		if _, ok := msg.(tea.KeyMsg); ok {
@@ -171,38 +254,123 @@ func (ps *ProjectScreenReal) Update(msg tea.Msg) (Screen, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if ps.problemsActive() {
+			return ps.handleProblemsKey(msg)
+		}
 		if ps.focusedPanel == EditorPanel && len(ps.tabs) > 0 {
 			return ps.handleEditorKey(msg)
 		}
 		return ps.handleKeyPress(msg)
+	case tea.MouseMsg:
+		return ps.handleMouse(msg)
 	case tea.WindowSizeMsg:
 		ps.handleResize(msg)
 		return ps, nil
 	case fileTreeLoadedMsg:
 		ps.loading = false
 		ps.fileTree = msg.tree
+		ps.applyTreeFilter()
 		ps.updateStats()
 		ps.recalculateLayout()
-		return ps, nil
+		path := ps.projectPath
+		return ps, tea.Batch(ps.indexQuickOpen(), ps.startTreeWatcher(), func() tea.Msg { return ProjectLoadedMsg{Path: path} })
 	case fileTreeErrorMsg:
 		ps.loading = false
+		if msg.rootErr != nil {
+			ps.markUnavailable(msg.rootErr)
+			return ps, nil
+		}
 		ps.err = msg.err
 		return ps, nil
+	case rootCheckTickMsg:
+		return ps, ps.handleRootCheckTick(msg)
+	case rootCheckedMsg:
+		return ps, ps.handleRootChecked(msg)
+	case revertChoiceMsg:
+		return ps, ps.handleRevertChoice(msg)
+	case treeChildrenLoadedMsg:
+		ps.handleTreeChildrenLoaded(msg)
+		return ps, nil
+	case treeChangedMsg:
+		return ps, ps.handleTreeChanged(msg)
+	case treeDirsReadMsg:
+		return ps, ps.handleTreeDirsRead(msg)
+	case treeDiagFilterChangedMsg:
+		ps.handleTreeDiagFilterChanged()
+		return ps, nil
+	case autosaveTickMsg:
+		ps.handleAutosaveTick(msg)
+		return ps, nil
+	case changesTickMsg:
+		ps.handleChangesTick(msg)
+		return ps, nil
+	case checkpointTickMsg:
+		ps.handleCheckpointTick(msg)
+		return ps, nil
+	case rollbackChoiceMsg:
+		return ps, ps.handleRollbackChoice(msg)
+	case rollbackConfirmMsg:
+		ps.handleRollbackConfirm(msg)
+		return ps, nil
+	case autosaveRecoverMsg:
+		ps.handleAutosaveRecover(msg)
+		return ps, nil
+	case testFileCreateMsg:
+		return ps, ps.handleTestFileCreate(msg)
+	case editorPasteConfirmedMsg:
+		ps.handleEditorPasteConfirmed(msg)
+		return ps, nil
+	case pasteChoiceMsg:
+		return ps, ps.handlePasteChoice(msg)
+	case pasteProgressMsg:
+		return ps, ps.handlePasteProgress(msg)
+	case pasteDoneMsg:
+		return ps, ps.handlePasteDone(msg)
+	case saveConflictChoiceMsg:
+		return ps, ps.handleSaveConflictChoice(msg)
+	case saveWarningMsg:
+		return ps, ps.handleSaveWarning(msg)
+	case lossySaveChoiceMsg:
+		return ps, ps.handleLossySaveChoice(msg)
+	case RestoreSessionMsg:
+		ps.restoreSession(msg.Session)
+		ps.saveSession()
+		return ps, nil
+	case saveAsConfirmedMsg:
+		return ps, ps.handleSaveAs(msg)
+	case saveAsOverwriteMsg:
+		return ps, ps.handleSaveAsOverwrite(msg)
+	case renamePatternMsg:
+		return ps, ps.handleRenamePattern(msg)
+	case renamePreviewMsg:
+		return ps, ps.handleRenamePreview(msg)
+	case ExternalEditorClosedMsg:
+		return ps, ps.handleExternalEditorClosed(msg)
+	case moveFileConfirmedMsg:
+		return ps, ps.handleMoveFileConfirmed(msg)
+	case copyToConfirmedMsg:
+		return ps, ps.handleCopyToConfirmed(msg)
+	case inlineFixChoiceMsg:
+		return ps, ps.handleInlineFixChoice(msg)
+	case inlineFixAppliedMsg:
+		return ps, ps.handleInlineFixApplied(msg)
+	case FormatDoneMsg:
+		return ps, ps.handleFormatDone(msg)
+	case quickOpenIndexMsg:
+		ps.handleQuickOpenIndex(msg)
+		return ps, nil
+	case quickOpenChosenMsg:
+		ps.openFileTab(msg.path)
+		return ps, nil
+	case ProjectInitializedMsg:
+		return ps, ps.handleProjectInitialized(msg)
 	case closeTabConfirmedMsg:
 		if msg.confirmed {
 			ps.forceCloseTab(msg.index)
 		}
 		return ps, nil
 	case deleteConfirmedMsg:
-		if msg.confirmed {
-			if err := ps.performDelete(msg.path); err != nil {
-				ps.setStatus(err.Error())
-			} else {
-				ps.setStatus("Deleted")
-			}
-			return ps, ps.loadFileTree()
-		}
-		return ps, nil
+		return ps, ps.handleDeleteConfirmed(msg)
 	case newFileConfirmedMsg:
 		if msg.value != nil && *msg.value != "" {
 			if err := ps.createEntry(*msg.value, false); err != nil {
@@ -259,33 +427,12 @@ func (ps *ProjectScreenReal) View() string {
 		rightPanel := ps.renderWorkspacePanel()
 		base = lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
 	}
-
-	if ps.confirm != nil {
-		if view := ps.confirm.View(); view != "" {
-			return joinOverlay(base, view)
-		}
-	}
-
-	if ps.closeDialog != nil {
-		if view := ps.closeDialog.View(); view != "" {
-			return joinOverlay(base, view)
-		}
-	}
-
-	if ps.newFileDialog != nil {
-		if view := ps.newFileDialog.View(); view != "" {
-			return joinOverlay(base, view)
-		}
-	}
-
-	if ps.newDirDialog != nil {
-		if view := ps.newDirDialog.View(); view != "" {
-			return joinOverlay(base, view)
-		}
+	if drawer := ps.renderProblemsDrawer(); drawer != "" {
+		base = lipgloss.JoinVertical(lipgloss.Left, base, drawer)
 	}
 
-	if ps.renameDialog != nil {
-		if view := ps.renameDialog.View(); view != "" {
+	if dialog := ps.activeDialog(); dialog != nil {
+		if view := dialog.View(); view != "" {
 			return joinOverlay(base, view)
 		}
 	}
@@ -293,194 +440,17 @@ func (ps *ProjectScreenReal) View() string {
 	return base
 }
 
-// handleKeyPress обрабатывает нажатия клавиш
-func (ps *ProjectScreenReal) handleKeyPress(msg tea.KeyMsg) (Screen, tea.Cmd) {
-	if ps.loading || ps.err != nil {
-		// В состоянии загрузки только разрешаем выход
-		return ps, nil
-	}
-
-	key := platform.CanonicalKeyForLookup(msg.String())
-
-	switch key {
-	case "ctrl+left":
-		ps.focusedPanel = FileTreePanel
-		ps.recalculateLayout()
-		return ps, nil
-	case "ctrl+right":
-		if len(ps.tabs) > 0 {
-			ps.focusedPanel = EditorPanel
-			ps.recalculateLayout()
-		}
-		return ps, nil
-	case "left", "right":
-		ps.switchPanel()
-		return ps, nil
-	case "ctrl+r":
-		return ps, ps.loadFileTree()
-	case "h":
-		if ps.fileTree != nil {
-			ps.fileTree.SetShowHidden(!ps.fileTree.ShowHidden)
-			ps.updateStats()
-			if ps.fileTree.ShowHidden {
-				ps.setStatus("Hidden entries visible")
-			} else {
-				ps.setStatus("Hidden entries hidden")
-			}
-		}
-		return ps, nil
-	case "s":
-		if ps.fileTree != nil {
-			ps.fileTree.SetFilterSurge(!ps.fileTree.FilterSurge)
-			ps.updateStats()
-			if ps.fileTree.FilterSurge {
-				ps.setStatus("Filter: .sg only")
-			} else {
-				ps.setStatus("Filter: all files")
-			}
-		}
-		return ps, nil
-	case "n":
-		if ps.newFileDialog != nil {
-			ch := ps.newFileDialog.Show()
-			return ps, func() tea.Msg {
-				value := <-ch
-				return newFileConfirmedMsg{value: value}
-			}
-		}
-		return ps, nil
-	case "N":
-		if ps.newDirDialog != nil {
-			ch := ps.newDirDialog.Show()
-			return ps, func() tea.Msg {
-				value := <-ch
-				return newDirConfirmedMsg{value: value}
-			}
-		}
-		return ps, nil
-	case "r":
-		if node := ps.fileTree.GetSelected(); node != nil && ps.renameDialog != nil {
-			ch := ps.renameDialog.ShowWithValue(node.Name)
-			return ps, func() tea.Msg {
-				value := <-ch
-				return renameConfirmedMsg{value: value}
-			}
-		}
-		return ps, nil
-	case "delete", "ctrl+d":
-		if node := ps.fileTree.GetSelected(); node != nil && ps.confirm != nil {
-			ps.confirm.Description = fmt.Sprintf("Delete %s?", node.Name)
-			ch := ps.confirm.Show()
-			path := node.Path
-			return ps, func() tea.Msg {
-				confirmed := <-ch
-				return deleteConfirmedMsg{confirmed: confirmed, path: path}
-			}
-		}
-		return ps, nil
-	case "alt+enter":
-		return ps, ps.openSelectedInEditor()
-	}
-
-	// Навигация в дереве файлов
-	if ps.focusedPanel == FileTreePanel && ps.fileTree != nil {
-		switch key {
-		case "up", "k":
-			ps.fileTree.SetSelected(ps.fileTree.Selected - 1)
-			return ps, nil
-		case "down", "j":
-			ps.fileTree.SetSelected(ps.fileTree.Selected + 1)
-			return ps, nil
-		case "space":
-			ps.fileTree.ToggleExpanded(ps.fileTree.Selected)
-			ps.updateStats()
-			return ps, nil
-		case "enter":
-			return ps, ps.openSelectedEntry()
-		}
-	}
-
-	return ps, nil
-}
-
-// handleResize обрабатывает изменение размера
-func (ps *ProjectScreenReal) handleResize(msg tea.WindowSizeMsg) {
-	ps.SetSize(msg.Width, msg.Height-1) // -1 для статус-бара приложения
-	ps.recalculateLayout()
-}
-
-// switchPanel переключает фокус между панелями
-func (ps *ProjectScreenReal) switchPanel() {
-	if ps.focusedPanel == FileTreePanel {
-		if len(ps.tabs) > 0 {
-			ps.focusedPanel = EditorPanel
-		}
-	} else {
-		ps.focusedPanel = FileTreePanel
-	}
-	ps.recalculateLayout()
-}
-
-func (ps *ProjectScreenReal) recalculateLayout() {
-	width := ps.Width()
-	if width <= 0 {
-		ps.treeWidth = 0
-		ps.mainWidth = 0
-		return
-	}
-
-	if len(ps.tabs) == 0 {
-		ps.treeWidth = width
-		ps.mainWidth = 0
-		return
-	}
-
-	expanded := int(float64(width) * TreeExpandedRatio)
-	if expanded < TreeMinWidth {
-		expanded = TreeMinWidth
-	}
-	if expanded > width-TreeMinWidth {
-		expanded = width - TreeMinWidth
-	}
-
-	collapsed := TreeCollapsedWidth
-	if collapsed < TreeMinWidth {
-		collapsed = TreeMinWidth
-	}
-	if collapsed > width-TreeMinWidth {
-		collapsed = max(width-TreeMinWidth, TreeMinWidth)
-	}
-
-	treeWidth := collapsed
-	if ps.focusedPanel == FileTreePanel {
-		treeWidth = expanded
-	}
-	if treeWidth < TreeMinWidth {
-		treeWidth = TreeMinWidth
-	}
-	if treeWidth > width-TreeMinWidth {
-		treeWidth = width - TreeMinWidth
-		if treeWidth < TreeMinWidth {
-			treeWidth = max(TreeMinWidth, width/2)
-		}
-	}
-
-	ps.treeWidth = treeWidth
-	ps.mainWidth = width - treeWidth
-	if ps.mainWidth < 0 {
-		ps.mainWidth = 0
-	}
-}
-
 // loadFileTree загружает дерево файлов асинхронно
 func (ps *ProjectScreenReal) loadFileTree() tea.Cmd {
 	ps.loading = true
 	ps.err = nil
 	ps.fileTree = nil
+	ps.stopTreeWatcher()
+	ignore := ps.treeIgnore
 	return func() tea.Msg {
-		tree, err := fs.NewFileTree(ps.projectPath)
+		tree, err := fs.NewLazyFileTree(ps.projectPath, ignore)
 		if err != nil {
-			return fileTreeErrorMsg{err: err}
+			return fileTreeErrorMsg{err: err, rootErr: checkProjectRoot(ps.projectPath)}
 		}
 		return fileTreeLoadedMsg{tree: tree}
 	}
@@ -494,9 +464,7 @@ func (ps *ProjectScreenReal) openSelectedEntry() tea.Cmd {
 	}
 
 	if selected.IsDir {
-		ps.fileTree.ToggleExpanded(ps.fileTree.Selected)
-		ps.updateStats()
-		return nil
+		return ps.toggleTreeEntry(ps.fileTree.Selected)
 	}
 
 	ps.openFileTab(selected.Path)
@@ -520,8 +488,10 @@ func (ps *ProjectScreenReal) updateStats() {
 
 	ps.statusInfo.FileCount = 0
 	ps.statusInfo.DirCount = 0
+	ps.statusInfo.MarkedCount = 0
 
 	ps.countNodes(ps.fileTree.Root)
+	ps.syncTreeWatch()
 }
 
 // countNodes рекурсивно считает файлы и директории
@@ -530,6 +500,9 @@ func (ps *ProjectScreenReal) countNodes(node *fs.FileNode) {
 		return
 	}
 
+	if node.Marked {
+		ps.statusInfo.MarkedCount++
+	}
 	if node.IsDir {
 		ps.statusInfo.DirCount++
 		for _, child := range node.Children {
@@ -540,6 +513,26 @@ func (ps *ProjectScreenReal) countNodes(node *fs.FileNode) {
 	}
 }
 
+// SetHighlightTheme задаёт тему подсветки синтаксиса (nil отключает подсветку).
+func (ps *ProjectScreenReal) SetHighlightTheme(theme *syntax.HighlightTheme) {
+	ps.highlight = theme
+}
+
+// SetFocusStyle задаёт оформление панели с фокусом по текущей теме.
+func (ps *ProjectScreenReal) SetFocusStyle(focus styles.FocusStyle) {
+	ps.focus = focus
+}
+
+// SetIcons задаёт значки файлов (ui.icons, ui.file_icons).
+func (ps *ProjectScreenReal) SetIcons(icons styles.IconSet) {
+	ps.icons = icons
+}
+
+// SetEditorConfig применяет настройки редактора (отступы, форматирование при сохранении).
+func (ps *ProjectScreenReal) SetEditorConfig(cfg config.EditorConfig) {
+	ps.editorCfg = cfg
+}
+
 // Title возвращает заголовок экрана
 func (ps *ProjectScreenReal) Title() string {
 	if ps.projectPath != "" {
@@ -566,14 +559,32 @@ func (ps *ProjectScreenReal) FullHelp() []string {
 		"  n - New file",
 		"  Shift+N - New directory",
 		"  r - Rename selected entry",
+		"  R - Rename marked entries with a pattern (s/old/new/, pre_*, *_v2)",
 		"  Delete - Delete with confirmation",
+		"  m - Mark entry • Shift+M - Clear marks (Delete / y / x act on all marked)",
+		"  y / x / p - Copy, cut, paste entry into selected directory • D - Duplicate",
+		"  Y - Copy project-relative path of selected entry",
 		"  h - Toggle hidden files display",
+		"  I - Toggle entries excluded by .gitignore",
 		"  s - Toggle .sg files only filter",
+		"  d - Diagnostics legend: badge threshold, only files with diagnostics",
 		platform.ReplacePrimaryModifier("  Ctrl+R - Refresh file tree"),
+		platform.ReplacePrimaryModifier("  Ctrl+T - Quick open file by name (recent files first)"),
 		"  Alt+←/→ - Switch editor tab • Alt+Shift+←/→ - Reorder tabs",
+		"  Alt+P - Problems drawer (↑/↓ select, Enter open, a file/project, Esc back)",
 		"  yy / dd / p - Copy, cut, paste current line",
-		"  :w save • :q quit tab • :q! force quit",
+		"  ]c / [c - Next/previous unsaved change • do - Revert change under cursor",
+		"  m - Toggle bookmark on line or selection • ]b / [b - Next/previous bookmark",
+		"  ]f / [f - Next/previous function or type declaration (.sg files)",
+		"  w / b / e - Next word / word start / word end (editor.word_chars joins words)",
+		"  Ctrl+D - Select word under cursor, then the next occurrence • double-click selects a word",
+		"  Sort Lines / Sort Lines (Unique) / Reverse Lines - Palette commands for the selected lines",
+		"  Alt+T - Toggle between a file and its test (creates a missing test)",
+		"  :w save • :q quit tab • :q! force quit • :e! revert to last save",
+		"  :saveas path - Save buffer to another file • :saveas path --move - Move the file",
+		"  :set wordchars=_- - Word characters for this session (:set wordchars? shows them)",
 		"  i / Esc - Enter/exit insert mode (Vim style)",
+		"  Tree keys are set in keybindings.project (Settings → Keybindings)",
 	}...)
 	return help
 }
@@ -585,7 +596,8 @@ type fileTreeLoadedMsg struct {
 }
 
 type fileTreeErrorMsg struct {
-	err error
+	err     error
+	rootErr error // корень проекта недоступен
 }
 
 type closeTabConfirmedMsg struct {
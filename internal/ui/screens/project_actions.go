@@ -6,7 +6,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -64,7 +63,11 @@ func (ps *ProjectScreenReal) renameEntry(name string) error {
 	if _, err := os.Stat(newPath); err == nil && !strings.EqualFold(newPath, node.Path) {
 		return fmt.Errorf("%s already exists", name)
 	}
-	return os.Rename(node.Path, newPath)
+	if err := os.Rename(node.Path, newPath); err != nil {
+		return err
+	}
+	ps.retargetTabs(node.Path, newPath)
+	return nil
 }
 
 func (ps *ProjectScreenReal) performDelete(path string) error {
@@ -72,19 +75,16 @@ func (ps *ProjectScreenReal) performDelete(path string) error {
 }
 
 func (ps *ProjectScreenReal) setStatus(msg string) {
-	ps.statusMsg = msg
-	ps.statusAt = time.Now()
+	ps.status.push(msg, statusSeverityOf(msg))
 }
 
 func (ps *ProjectScreenReal) statusLine() string {
-	if ps.statusMsg == "" {
-		return ""
-	}
-	if time.Since(ps.statusAt) > 3*time.Second {
-		ps.statusMsg = ""
-		return ""
-	}
-	return ps.statusMsg
+	return ps.status.line()
+}
+
+// StatusHistory возвращает последние сообщения строки статуса, новые первыми.
+func (ps *ProjectScreenReal) StatusHistory() []string {
+	return ps.status.recent()
 }
 
 func joinOverlay(base, modal string) string {
@@ -93,7 +93,7 @@ func joinOverlay(base, modal string) string {
 
 type deleteConfirmedMsg struct {
 	confirmed bool
-	path      string
+	paths     []string
 }
 
 func (ps *ProjectScreenReal) isProjectDirectory(path string) bool {
@@ -133,8 +133,7 @@ func (ps *ProjectScreenReal) InitProjectInSelectedDir() tea.Cmd {
 		ps.setStatus("Already a Surge project")
 		return nil
 	}
-	ps.setStatus("Init project action (TODO)")
-	return nil
+	return ps.runInit(node.Path)
 }
 
 func (ps *ProjectScreenReal) HandleGlobalEsc() (bool, tea.Cmd) {
@@ -158,6 +157,78 @@ func (ps *ProjectScreenReal) HandleGlobalEsc() (bool, tea.Cmd) {
 		ps.renameDialog.Hide()
 		return true, nil
 	}
+	if ps.patternDialog != nil && ps.patternDialog.Visible {
+		ps.patternDialog.Hide()
+		return true, nil
+	}
+	if ps.renamePreview != nil && ps.renamePreview.Visible {
+		ps.renamePreview.Hide()
+		return true, nil
+	}
+	if ps.saveAsDialog != nil && ps.saveAsDialog.Visible {
+		ps.saveAsDialog.Hide()
+		return true, nil
+	}
+	if ps.saveAsConfirm != nil && ps.saveAsConfirm.Visible {
+		ps.saveAsConfirm.Hide()
+		return true, nil
+	}
+	if ps.moveDialog != nil && ps.moveDialog.Visible {
+		ps.moveDialog.Hide()
+		return true, nil
+	}
+	if ps.copyToDialog != nil && ps.copyToDialog.Visible {
+		ps.copyToDialog.Hide()
+		return true, nil
+	}
+	if ps.revertDialog != nil && ps.revertDialog.Visible {
+		ps.revertDialog.Hide()
+		return true, nil
+	}
+	if ps.conflictDialog != nil && ps.conflictDialog.Visible {
+		ps.conflictDialog.Hide()
+		return true, nil
+	}
+	if ps.pasteDialog != nil && ps.pasteDialog.Visible {
+		ps.pasteDialog.Hide()
+		return true, nil
+	}
+	if ps.pasteConfirm != nil && ps.pasteConfirm.Visible {
+		ps.pasteConfirm.Hide()
+		return true, nil
+	}
+	if ps.recoverDialog != nil && ps.recoverDialog.Visible {
+		ps.recoverDialog.Hide()
+		return true, nil
+	}
+	if ps.rollbackDialog != nil && ps.rollbackDialog.Visible {
+		ps.rollbackDialog.Hide()
+		return true, nil
+	}
+	if ps.rollbackDiff != nil && ps.rollbackDiff.Visible {
+		ps.rollbackDiff.Hide()
+		return true, nil
+	}
+	if ps.tests.confirm != nil && ps.tests.confirm.Visible {
+		ps.tests.confirm.Hide()
+		return true, nil
+	}
+	if ps.saveWarnDialog != nil && ps.saveWarnDialog.Visible {
+		ps.saveWarnDialog.Hide()
+		return true, nil
+	}
+	if ps.quickOpen != nil && ps.quickOpen.Visible {
+		ps.quickOpen.Hide()
+		return true, nil
+	}
+	if ps.treeLegend != nil && ps.treeLegend.Visible {
+		ps.treeLegend.Hide()
+		return true, nil
+	}
+	if ps.problemsActive() {
+		ps.problems.focused = false
+		return true, nil
+	}
 	if ps.handleEditorEscape() {
 		return true, nil
 	}
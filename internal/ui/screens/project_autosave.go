@@ -0,0 +1,201 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+)
+
+// Автосохранение вкладок: через editor.auto_save_delay секунд после последней
+// правки буфер копируется в общий каталог копий (config.AutosaveDir), сам файл
+// не трогается. Копия удаляется, когда вкладка снова совпадает с диском
+// (сохранение, откат) или закрывается. При открытии файла, у которого
+// осталась копия, предлагается её восстановить.
+// Тики доходят только до активного экрана, поэтому при уходе с экрана и
+// возвращении несохранённые копии дописываются сразу.
+
+const (
+	autosaveRestoreOption = "Restore autosave"
+	autosaveDiscardOption = "Discard autosave"
+)
+
+// autosaveTickMsg — пауза после правки вкладки истекла; token отсекает
+// тики, после которых были новые правки.
+type autosaveTickMsg struct {
+	tab   *editorTab
+	token int
+}
+
+type autosaveRecoverMsg struct {
+	tab    *editorTab
+	saved  *config.Autosave
+	option string
+}
+
+func (ps *ProjectScreenReal) autosaveEnabled() bool {
+	return ps.editorCfg.AutoSave && ps.editorCfg.AutoSaveDelay > 0
+}
+
+// trackAutosave запускает отсчёт для вкладок с новыми правками и убирает
+// копии вкладок, которые снова совпадают с диском. Вызывается после
+// каждого сообщения экрана.
+func (ps *ProjectScreenReal) trackAutosave() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, tab := range ps.tabs {
+		if tab.scratch {
+			continue
+		}
+		if !tab.dirty {
+			if !tab.autosavedAt.IsZero() {
+				tab.autosavedAt = time.Time{}
+				_ = config.RemoveAutosave(tab.path)
+			}
+			tab.autosaveEdits, tab.autosavedEdits = tab.edits, tab.edits
+			continue
+		}
+		if tab.edits == tab.autosaveEdits || !ps.autosaveEnabled() {
+			continue
+		}
+		tab.autosaveEdits = tab.edits
+		tab.autosaveToken++
+		msg := autosaveTickMsg{tab: tab, token: tab.autosaveToken}
+		delay := time.Duration(ps.editorCfg.AutoSaveDelay) * time.Second
+		cmds = append(cmds, tea.Tick(delay, func(time.Time) tea.Msg { return msg }))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (ps *ProjectScreenReal) handleAutosaveTick(msg autosaveTickMsg) {
+	if msg.token != msg.tab.autosaveToken || !ps.autosaveEnabled() {
+		return
+	}
+	for _, tab := range ps.tabs {
+		if tab == msg.tab {
+			ps.writeAutosave(tab)
+			return
+		}
+	}
+}
+
+// flushAutosaves дописывает копии вкладок, чей отсчёт мог потеряться.
+func (ps *ProjectScreenReal) flushAutosaves() {
+	if !ps.autosaveEnabled() {
+		return
+	}
+	for _, tab := range ps.tabs {
+		if !tab.scratch && tab.dirty && tab.autosavedEdits != tab.edits {
+			ps.writeAutosave(tab)
+		}
+	}
+}
+
+// writeAutosave пишет копию сразу: буфер небольшой, а запись из команды
+// могла бы вернуться уже на другой экран.
+func (ps *ProjectScreenReal) writeAutosave(tab *editorTab) {
+	if !tab.dirty {
+		return
+	}
+	now := time.Now()
+	if err := config.WriteAutosave(tab.path, tab.text(), now); err != nil {
+		ps.setStatus(fmt.Sprintf("Autosave failed: %v", err))
+		return
+	}
+	tab.autosavedAt = now
+	tab.autosavedEdits = tab.edits
+}
+
+// discardAutosave удаляет копию закрываемой вкладки: правки отброшены намеренно.
+func (ps *ProjectScreenReal) discardAutosave(tab *editorTab) {
+	if tab.scratch {
+		return
+	}
+	_ = config.RemoveAutosave(tab.path)
+	tab.autosavedAt = time.Time{}
+}
+
+// checkAutosave ищет копию только что открытого файла. Копия, совпадающая
+// с диском, удаляется молча, иначе предлагается восстановить её. Пока открыт
+// вопрос о другой вкладке (восстановление сессии), вкладка ждёт в очереди.
+func (ps *ProjectScreenReal) checkAutosave(tab *editorTab) {
+	if ps.recoverDialog.Visible {
+		ps.recoverQueue = append(ps.recoverQueue, tab)
+		return
+	}
+	saved, err := config.LoadAutosave(tab.path)
+	if err != nil || saved == nil {
+		return
+	}
+	if saved.Content == tab.text() {
+		_ = config.RemoveAutosave(tab.path)
+		return
+	}
+
+	desc := fmt.Sprintf("%s has unsaved changes from %s that were kept by autosave.",
+		tab.name, saved.SavedAt.Format("2006-01-02 15:04:05"))
+	if info, err := os.Stat(tab.path); err == nil && info.ModTime().After(saved.SavedAt) {
+		desc += "\nThe file on disk was changed after that."
+	}
+	options := []string{autosaveRestoreOption, autosaveDiscardOption}
+	ps.recoverDialog.Description = desc
+	ps.recoverDialog.Options = options
+	ch := ps.recoverDialog.Show()
+	ps.deferredCmd = func() tea.Msg {
+		choice := <-ch
+		option := ""
+		if choice >= 0 && choice < len(options) {
+			option = options[choice]
+		}
+		return autosaveRecoverMsg{tab: tab, saved: saved, option: option}
+	}
+}
+
+func (ps *ProjectScreenReal) handleAutosaveRecover(msg autosaveRecoverMsg) {
+	tab := msg.tab
+	switch msg.option {
+	case autosaveRestoreOption:
+		if ps.findTabIndex(tab.path) < 0 {
+			break
+		}
+		lines, _ := decodeBuffer([]byte(msg.saved.Content))
+		tab.pushUndo()
+		tab.lines = lines
+		tab.clampCursor()
+		tab.markModified()
+		tab.autosavedAt = msg.saved.SavedAt
+		tab.autosaveEdits, tab.autosavedEdits = tab.edits, tab.edits
+		ps.ensureCursorVisible(tab)
+		ps.setStatus(fmt.Sprintf("Restored autosave of %s — save to keep it, u to undo", tab.name))
+	case autosaveDiscardOption:
+		_ = config.RemoveAutosave(tab.path)
+		ps.setStatus("Autosave discarded")
+	default:
+		ps.setStatus("Autosave kept; it will be offered again next time")
+	}
+
+	for len(ps.recoverQueue) > 0 && !ps.recoverDialog.Visible {
+		next := ps.recoverQueue[0]
+		ps.recoverQueue = ps.recoverQueue[1:]
+		if ps.findTabIndex(next.path) >= 0 {
+			ps.checkAutosave(next)
+		}
+	}
+}
+
+// autosaveNote возвращает "autosaved 12s ago" для строки статуса редактора.
+func (ps *ProjectScreenReal) autosaveNote(tab *editorTab) string {
+	if !tab.dirty || tab.autosavedAt.IsZero() {
+		return ""
+	}
+	ago := time.Since(tab.autosavedAt)
+	switch {
+	case ago < time.Minute:
+		return fmt.Sprintf("autosaved %ds ago", int(ago.Seconds()))
+	case ago < time.Hour:
+		return fmt.Sprintf("autosaved %dm ago", int(ago.Minutes()))
+	default:
+		return "autosaved " + tab.autosavedAt.Format("15:04")
+	}
+}
@@ -0,0 +1,154 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/platform"
+)
+
+// Корень проекта может пропасть (например, отвалился сетевой диск). Экран
+// периодически проверяет его в фоне: пока корня нет, дерево не показывается,
+// а сохранение файлов проекта блокируется. Когда путь возвращается, дерево
+// перезагружается автоматически.
+
+const rootCheckInterval = 3 * time.Second
+
+type rootCheckTickMsg struct {
+	seq int
+}
+
+type rootCheckedMsg struct {
+	seq   int
+	err   error
+	retry bool // ручная проверка по r / Ctrl+R, цикл не продолжает
+}
+
+// checkProjectRoot проверяет, что корень проекта существует и является каталогом.
+func checkProjectRoot(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+	return nil
+}
+
+// startRootChecks запускает новый цикл проверок; предыдущий цикл отбрасывается по seq.
+// Тики доходят только до активного экрана, поэтому цикл перезапускается в OnEnter.
+func (ps *ProjectScreenReal) startRootChecks() tea.Cmd {
+	ps.rootCheckSeq++
+	return ps.scheduleRootCheck()
+}
+
+func (ps *ProjectScreenReal) scheduleRootCheck() tea.Cmd {
+	seq := ps.rootCheckSeq
+	return tea.Tick(rootCheckInterval, func(time.Time) tea.Msg {
+		return rootCheckTickMsg{seq: seq}
+	})
+}
+
+// checkRootCmd выполняет stat вне цикла обновления: на отвалившемся
+// сетевом диске он может подвиснуть.
+func (ps *ProjectScreenReal) checkRootCmd(retry bool) tea.Cmd {
+	seq := ps.rootCheckSeq
+	path := ps.projectPath
+	return func() tea.Msg {
+		return rootCheckedMsg{seq: seq, err: checkProjectRoot(path), retry: retry}
+	}
+}
+
+func (ps *ProjectScreenReal) handleRootCheckTick(msg rootCheckTickMsg) tea.Cmd {
+	if msg.seq != ps.rootCheckSeq {
+		return nil
+	}
+	return ps.checkRootCmd(false)
+}
+
+func (ps *ProjectScreenReal) handleRootChecked(msg rootCheckedMsg) tea.Cmd {
+	var next tea.Cmd
+	if !msg.retry {
+		if msg.seq != ps.rootCheckSeq {
+			return nil
+		}
+		next = ps.scheduleRootCheck()
+	}
+
+	switch {
+	case msg.err != nil && ps.unavailable == nil:
+		ps.markUnavailable(msg.err)
+	case msg.err != nil && msg.retry:
+		ps.unavailable = msg.err
+		ps.setStatus("Project is still unavailable")
+	case msg.err == nil && ps.unavailable != nil:
+		ps.unavailable = nil
+		ps.setStatus("Project path is available again")
+		return tea.Batch(next, ps.loadFileTree())
+	}
+	return next
+}
+
+// retryProjectRoot немедленно перепроверяет корень проекта.
+func (ps *ProjectScreenReal) retryProjectRoot() tea.Cmd {
+	ps.setStatus("Checking project path...")
+	return ps.checkRootCmd(true)
+}
+
+func (ps *ProjectScreenReal) markUnavailable(err error) {
+	ps.unavailable = err
+	ps.setStatus("Project unavailable: saves are blocked until it returns")
+}
+
+// handleTreeError разбирает ошибку обновления дерева: пропавший корень
+// переводит экран в состояние «проект недоступен».
+func (ps *ProjectScreenReal) handleTreeError(err error) {
+	if rootErr := checkProjectRoot(ps.projectPath); rootErr != nil {
+		ps.markUnavailable(rootErr)
+		return
+	}
+	ps.setStatus(fmt.Sprintf("Refresh failed: %v", err))
+}
+
+// tabAtRisk сообщает, что файл вкладки лежит в недоступном проекте.
+func (ps *ProjectScreenReal) tabAtRisk(tab *editorTab) bool {
+	if ps.unavailable == nil || tab.path == "" {
+		return false
+	}
+	root := filepath.Clean(ps.projectPath) + string(filepath.Separator)
+	return strings.HasPrefix(filepath.Clean(tab.path), root)
+}
+
+// saveBlocked проверяет корень перед сохранением файла проекта.
+func (ps *ProjectScreenReal) saveBlocked(tab *editorTab) bool {
+	if ps.unavailable == nil {
+		if err := checkProjectRoot(ps.projectPath); err != nil {
+			ps.markUnavailable(err)
+		}
+	}
+	if !ps.tabAtRisk(tab) {
+		return false
+	}
+	ps.setStatus(fmt.Sprintf("Save blocked: project unavailable (%v). Press r in the file tree to retry", ps.unavailable))
+	return true
+}
+
+func (ps *ProjectScreenReal) renderUnavailable() string {
+	warning := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(ErrorColor)).Render("⚠ Project unavailable")
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(DimTextColor))
+	return strings.Join([]string{
+		warning,
+		"",
+		ps.projectPath,
+		dim.Render(ps.unavailable.Error()),
+		"",
+		dim.Render("Open tabs are kept; saving is blocked."),
+		dim.Render("Press r or " + platform.ReplacePrimaryModifier("Ctrl+R") + " to retry."),
+	}, "\n")
+}
@@ -0,0 +1,81 @@
+package screens
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// m в редакторе ставит или снимает закладку на строке курсора, m в
+// визуальном режиме — на выделенном блоке строк. ]b / [b переходят к
+// следующей/предыдущей закладке вкладки, а палитра команд показывает
+// закладки всех вкладок как «mark: файл:строка».
+
+// toggleLineBookmark ставит или снимает закладку на строке курсора.
+func (ps *ProjectScreenReal) toggleLineBookmark(tab *editorTab) {
+	line := tab.cursor.Line
+	ps.reportBookmark(tab, line, line, tab.toggleBookmark(line, line))
+}
+
+// toggleSelectionBookmark ставит или снимает закладку на выделенных строках
+// и выходит из визуального режима.
+func (ps *ProjectScreenReal) toggleSelectionBookmark(tab *editorTab) {
+	from, to := tab.selectedLineRange()
+	tab.stopVisual()
+	ps.reportBookmark(tab, from, to, tab.toggleBookmark(from, to))
+}
+
+func (ps *ProjectScreenReal) reportBookmark(tab *editorTab, from, to int, added bool) {
+	label := editorBookmark{Line: from, EndLine: to}.label()
+	if added {
+		ps.setStatus(fmt.Sprintf("Bookmark set: %s:%s", tab.name, label))
+	} else {
+		ps.setStatus(fmt.Sprintf("Bookmark removed: %s:%s", tab.name, label))
+	}
+}
+
+// jumpToBookmark переводит курсор к соседней закладке активной вкладки.
+func (ps *ProjectScreenReal) jumpToBookmark(tab *editorTab, dir int) {
+	b, ok := tab.bookmarkFrom(tab.cursor.Line, dir)
+	if !ok {
+		ps.setStatus("No bookmarks in " + tab.name)
+		return
+	}
+	ps.showBookmark(tab, b)
+}
+
+// showBookmark делает вкладку активной и ставит курсор на начало закладки.
+func (ps *ProjectScreenReal) showBookmark(tab *editorTab, b editorBookmark) {
+	index := ps.tabIndexOf(tab)
+	if index < 0 {
+		ps.setStatus("Tab is closed: " + tab.name)
+		return
+	}
+	ps.setActiveTab(index)
+	tab.mode = editorModeNormal
+	tab.clearPending()
+	tab.cursor = cursorPosition{Line: b.Line}
+	tab.clampCursor()
+	ps.ensureCursorVisible(tab)
+	ps.focusedPanel = EditorPanel
+	ps.recalculateLayout()
+	ps.setStatus(fmt.Sprintf("Bookmark %s:%s", tab.name, b.label()))
+}
+
+// tabIndexOf ищет вкладку по указателю: у scratch-буфера нет пути.
+func (ps *ProjectScreenReal) tabIndexOf(tab *editorTab) int {
+	for i, t := range ps.tabs {
+		if t == tab {
+			return i
+		}
+	}
+	return -1
+}
+
+// renderLineNumber рисует номер строки; строки закладок выделены цветом.
+func renderLineNumber(style lipgloss.Style, tab *editorTab, idx int) string {
+	if tab.bookmarked(idx) {
+		style = style.Foreground(lipgloss.Color(bookmarkColor)).Bold(true)
+	}
+	return style.Render(fmt.Sprintf("%5d", idx+1))
+}
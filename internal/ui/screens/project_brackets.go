@@ -0,0 +1,62 @@
+package screens
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CanJumpToBracket сообщает, что фокус во вкладке редактора.
+func (ps *ProjectScreenReal) CanJumpToBracket() bool {
+	return ps.focusedPanel == EditorPanel && ps.activeEditorTab() != nil
+}
+
+// JumpToBracket переводит курсор к скобке, парной скобке у курсора (% в
+// нормальном и визуальном режиме).
+func (ps *ProjectScreenReal) JumpToBracket() tea.Cmd {
+	if !ps.CanJumpToBracket() {
+		ps.setStatus("Focus an editor tab to jump between brackets")
+		return nil
+	}
+	ps.jumpToBracket(ps.activeEditorTab())
+	return nil
+}
+
+func (ps *ProjectScreenReal) jumpToBracket(tab *editorTab) {
+	match, ok := tab.matchingBracket()
+	switch {
+	case !ok:
+		ps.setStatus("No matching bracket at the cursor")
+		return
+	case !match.matched:
+		ps.setStatus("Unmatched " + string([]rune(tab.lines[match.at.Line])[match.at.Col]))
+		return
+	}
+	tab.cursor = match.pair
+	ps.ensureCursorVisible(tab)
+}
+
+// bracketDecor отмечает на строке line скобку у курсора и её пару.
+// Непарная скобка под курсором красится вместо курсора, чтобы ошибку было видно.
+func bracketDecor(decor *lineDecor, match bracketMatch, line int) {
+	if !match.matched {
+		if match.at.Line != line {
+			return
+		}
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color(onSelectedColor)).Background(lipgloss.Color(ErrorColor)).Bold(true)
+		if match.at.Col == decor.cursorCol {
+			decor.cursorStyle = style
+		}
+		decor.brackets = map[int]lipgloss.Style{match.at.Col: style}
+		return
+	}
+	style := lipgloss.NewStyle().Background(lipgloss.Color(bracketColor)).Bold(true)
+	for _, pos := range []cursorPosition{match.at, match.pair} {
+		if pos.Line != line {
+			continue
+		}
+		if decor.brackets == nil {
+			decor.brackets = make(map[int]lipgloss.Style, 2)
+		}
+		decor.brackets[pos.Col] = style
+	}
+}
@@ -0,0 +1,44 @@
+package screens
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// Операции экрана проекта, меняющие файлы через surge (форматирование,
+// фикс из редактора), занимают общий реестр ProjectOps, а правки дерева
+// (удаление, вставка) не начинаются, пока его занимает другой экран —
+// например, Fix Mode применяет фиксы.
+
+// SetProjectOps подключает общий реестр операций над файлами проекта.
+func (ps *ProjectScreenReal) SetProjectOps(ops *ProjectOps) {
+	ps.ops = ops
+}
+
+// beginProjectOp занимает реестр операцией label; если он занят, показывает
+// чем и возвращает false.
+func (ps *ProjectScreenReal) beginProjectOp(label string) (int, bool) {
+	token, other := ps.ops.Begin(label)
+	if other != "" {
+		ps.setStatus("Busy: " + other)
+		return 0, false
+	}
+	return token, true
+}
+
+// underProjectOp освобождает реестр, как только команда cmd отработала:
+// её сообщение может уйти неактивному экрану и не вернуться сюда.
+func (ps *ProjectScreenReal) underProjectOp(token int, cmd tea.Cmd) tea.Cmd {
+	ops := ps.ops
+	return func() tea.Msg {
+		defer ops.End(token)
+		return cmd()
+	}
+}
+
+// projectBusy сообщает и показывает в статусе, что файлы проекта сейчас
+// меняет другая операция.
+func (ps *ProjectScreenReal) projectBusy() bool {
+	if other := ps.ops.Busy(); other != "" {
+		ps.setStatus("Busy: " + other)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package screens
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Метки несохранённых изменений пересчитываются через changesDelay после
+// последней правки вкладки, чтобы набор текста не ждал diff.
+// ]c / [c переходят к следующему/предыдущему ханку, do (как в vimdiff)
+// возвращает ханк под курсором к сохранённой версии.
+
+const changesDelay = 150 * time.Millisecond
+
+// changesTickMsg — пауза после правки истекла; edits отсекает тики, после
+// которых вкладку снова правили.
+type changesTickMsg struct {
+	tab   *editorTab
+	edits int
+}
+
+// trackChanges запускает отсчёт до пересчёта меток для вкладок с новыми
+// правками. Вызывается после каждого сообщения экрана.
+func (ps *ProjectScreenReal) trackChanges() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, tab := range ps.tabs {
+		if !tab.changesStale || tab.changesEdits == tab.edits {
+			continue
+		}
+		tab.changesEdits = tab.edits
+		msg := changesTickMsg{tab: tab, edits: tab.edits}
+		cmds = append(cmds, tea.Tick(changesDelay, func(time.Time) tea.Msg { return msg }))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (ps *ProjectScreenReal) handleChangesTick(msg changesTickMsg) {
+	if msg.edits == msg.tab.edits {
+		msg.tab.refreshChanges()
+	}
+}
+
+// refreshActiveChanges пересчитывает метки активной вкладки сразу: тики
+// доходят только до активного экрана.
+func (ps *ProjectScreenReal) refreshActiveChanges() {
+	if tab := ps.activeEditorTab(); tab != nil {
+		tab.refreshChanges()
+	}
+}
+
+// CanNavigateChanges сообщает, есть ли вкладка, чьи правки можно обходить.
+func (ps *ProjectScreenReal) CanNavigateChanges() bool {
+	tab := ps.activeEditorTab()
+	return tab != nil && !tab.scratch
+}
+
+// JumpToChange переводит курсор к следующему (dir > 0) или предыдущему
+// несохранённому изменению.
+func (ps *ProjectScreenReal) JumpToChange(dir int) tea.Cmd {
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return nil
+	}
+	tab.refreshChanges()
+	if len(tab.changes) == 0 {
+		ps.setStatus("No unsaved changes in " + tab.name)
+		return nil
+	}
+	hunk, ok := tab.adjacentChange(tab.cursor.Line, dir)
+	if !ok {
+		if dir > 0 {
+			ps.setStatus("No more changes below")
+		} else {
+			ps.setStatus("No more changes above")
+		}
+		return nil
+	}
+	tab.cursor = cursorPosition{Line: hunk.markerLine()}
+	tab.clampCursor()
+	ps.ensureCursorVisible(tab)
+	ps.setStatus(describeHunk(hunk))
+	return nil
+}
+
+// RevertHunk возвращает ханк под курсором к последнему сохранению; u отменяет.
+func (ps *ProjectScreenReal) RevertHunk() tea.Cmd {
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return nil
+	}
+	tab.refreshChanges()
+	hunk, ok := tab.changeAt(tab.cursor.Line)
+	if !ok {
+		ps.setStatus("No change under cursor")
+		return nil
+	}
+	tab.pushUndo()
+	tab.revertHunk(hunk)
+	ps.ensureCursorVisible(tab)
+	ps.setStatus("Reverted: " + describeHunk(hunk))
+	return nil
+}
+
+// describeHunk — краткое описание ханка для статуса.
+func describeHunk(h changeHunk) string {
+	switch {
+	case h.count == 0:
+		return fmt.Sprintf("%s deleted", plural(h.savedCount, "line"))
+	case h.savedCount == 0:
+		return fmt.Sprintf("%s added", plural(h.count, "line"))
+	default:
+		return fmt.Sprintf("%s changed", plural(h.count, "line"))
+	}
+}
+
+// renderChangeMarker рисует метку изменения в гуттере (или пробел).
+func renderChangeMarker(change lineChange) string {
+	symbol, color := " ", ""
+	switch change {
+	case lineAdded:
+		symbol, color = "▎", changeAddedColor
+	case lineModified:
+		symbol, color = "▎", changeModifiedColor
+	case lineDeletedBelow:
+		symbol, color = "▁", changeDeletedColor
+	case lineDeletedAbove:
+		symbol, color = "▔", changeDeletedColor
+	default:
+		return symbol
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(symbol)
+}
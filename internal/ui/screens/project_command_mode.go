@@ -0,0 +1,92 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+)
+
+func (ps *ProjectScreenReal) handleCommandModeKey(tab *editorTab, msg tea.KeyMsg) (Screen, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		ps.handleEditorEscape()
+		return ps, nil
+	case tea.KeyEnter:
+		command := strings.TrimSpace(ps.editorCommand.Value())
+		ps.editorCommand.SetValue("")
+		return ps, ps.executeEditorCommand(tab, command)
+	}
+
+	var cmd tea.Cmd
+	ps.editorCommand, cmd = ps.editorCommand.Update(msg)
+	return ps, cmd
+}
+
+func (ps *ProjectScreenReal) executeEditorCommand(tab *editorTab, input string) tea.Cmd {
+	tab.mode = editorModeNormal
+	ps.editorCommand.Blur()
+
+	if input == "" {
+		ps.setStatus("-- NORMAL --")
+		return nil
+	}
+
+	name, args, _ := strings.Cut(input, " ")
+	switch name {
+	case "saveas", "saveas!":
+		return ps.saveAsCommand(tab, args, name == "saveas!")
+	case "set", "se":
+		ps.setEditorOption(strings.TrimSpace(args))
+		return nil
+	}
+
+	force := false
+	if strings.HasSuffix(input, "!") {
+		force = true
+		input = strings.TrimSuffix(input, "!")
+	}
+
+	switch input {
+	case "w", "write":
+		return ps.saveTab(tab, false)
+	case "q", "quit":
+		if tab.dirty && !force {
+			ps.setStatus("Unsaved changes (use :q!)")
+			return nil
+		}
+		ps.forceCloseTab(ps.activeTab)
+	case "wq", "x", "xit":
+		return ps.saveTab(tab, true)
+	case "fmt", "format":
+		return ps.FormatFile()
+	case "e", "edit":
+		if !force {
+			ps.setStatus("Use :e! to discard changes and revert")
+			return nil
+		}
+		return ps.requestRevert(tab)
+	default:
+		ps.setStatus("Unknown command: " + input)
+	}
+	return nil
+}
+
+// setEditorOption выполняет :set. Изменение действует до перезапуска или
+// следующего сохранения настроек; постоянное значение задаётся в Settings.
+func (ps *ProjectScreenReal) setEditorOption(arg string) {
+	name, value, assign := strings.Cut(arg, "=")
+	name = strings.TrimSuffix(strings.TrimSpace(name), "?")
+	switch name {
+	case "wordchars", "wc":
+		if assign {
+			ps.editorCfg.WordChars = config.NormalizeWordChars(value)
+		}
+		ps.setStatus(fmt.Sprintf("wordchars=%s", ps.editorCfg.WordChars))
+	case "":
+		ps.setStatus("Usage: :set wordchars=_-")
+	default:
+		ps.setStatus("Unknown option: " + name)
+	}
+}
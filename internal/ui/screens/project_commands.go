@@ -0,0 +1,142 @@
+package screens
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/ui/components"
+)
+
+// Команды дерева регистрируются приложением в реестре с клавишами из
+// keybindings.project.*; реестр вызывает их через RunScreenCommand.
+
+// CommandKeysActive сообщает, что клавиши команд дерева сейчас не нужны
+// редактору, диалогу или панели проблем.
+func (ps *ProjectScreenReal) CommandKeysActive() bool {
+	if ps.loading || ps.err != nil || ps.unavailable != nil || ps.fileTree == nil {
+		return false
+	}
+	if ps.activeDialog() != nil || ps.problemsActive() {
+		return false
+	}
+	return ps.focusedPanel == FileTreePanel || len(ps.tabs) == 0
+}
+
+// RunScreenCommand выполняет команду дерева по имени действия (new_file, rename…).
+func (ps *ProjectScreenReal) RunScreenCommand(action string) tea.Cmd {
+	if ps.fileTree == nil || ps.loading {
+		return nil
+	}
+	if treeEntryActions[action] {
+		if kind := ps.treeEmptyState(); kind != treeNotEmpty {
+			ps.setStatus(kind.emptyActionStatus())
+			return nil
+		}
+	}
+	switch action {
+	case "new_file":
+		return showInputDialog(ps.newFileDialog, "", func(value *string) tea.Msg {
+			return newFileConfirmedMsg{value: value}
+		})
+	case "new_dir":
+		return showInputDialog(ps.newDirDialog, "", func(value *string) tea.Msg {
+			return newDirConfirmedMsg{value: value}
+		})
+	case "rename":
+		node := ps.fileTree.GetSelected()
+		if node == nil {
+			return nil
+		}
+		return showInputDialog(ps.renameDialog, node.Name, func(value *string) tea.Msg {
+			return renameConfirmedMsg{value: value}
+		})
+	case "rename_pattern":
+		return ps.RenameWithPattern()
+	case "delete":
+		return ps.confirmDeleteEntries()
+	case "copy", "cut":
+		ps.yankTreeEntry(action == "cut")
+	case "paste":
+		return ps.pasteTreeEntry()
+	case "duplicate":
+		return ps.duplicateTreeEntry()
+	case "copy_relative_path":
+		if node := ps.fileTree.GetSelected(); node != nil {
+			path := node.Path
+			return func() tea.Msg { return CopyPathMsg{Path: path, Relative: true} }
+		}
+	case "toggle_hidden":
+		ps.toggleHiddenEntries()
+	case "toggle_ignored":
+		ps.toggleIgnoredEntries()
+	case "filter_surge":
+		ps.toggleSurgeFilter()
+	case "clear_filters":
+		ps.clearTreeFilters()
+	case "mark":
+		ps.toggleTreeMark()
+	case "clear_marks":
+		ps.clearTreeMarks()
+	case "format_project":
+		return ps.FormatProject()
+	case "init_project":
+		return ps.InitProjectInSelectedDir()
+	case "diag_here":
+		return ps.runInSelectedDir(false)
+	case "fix_here":
+		return ps.runInSelectedDir(true)
+	case "diag_legend":
+		ps.treeLegend.Show()
+	case "open_file":
+		return ps.openSelectedInEditor()
+	case "follow_tab":
+		ps.ToggleFollowActiveTab()
+	}
+	return nil
+}
+
+// showInputDialog открывает диалог ввода и превращает ответ в сообщение.
+func showInputDialog(dialog *components.InputDialog, value string, msg func(*string) tea.Msg) tea.Cmd {
+	if dialog == nil {
+		return nil
+	}
+	ch := dialog.ShowWithValue(value)
+	return func() tea.Msg { return msg(<-ch) }
+}
+
+func (ps *ProjectScreenReal) toggleHiddenEntries() {
+	if err := ps.fileTree.SetShowHidden(!ps.fileTree.ShowHidden); err != nil {
+		ps.handleTreeError(err)
+		return
+	}
+	ps.updateStats()
+	if ps.fileTree.ShowHidden {
+		ps.setStatus("Hidden entries visible")
+	} else {
+		ps.setStatus("Hidden entries hidden")
+	}
+}
+
+func (ps *ProjectScreenReal) toggleIgnoredEntries() {
+	if err := ps.fileTree.SetShowIgnored(!ps.fileTree.ShowIgnored); err != nil {
+		ps.handleTreeError(err)
+		return
+	}
+	ps.updateStats()
+	if ps.fileTree.ShowIgnored {
+		ps.setStatus("Gitignored entries visible")
+	} else {
+		ps.setStatus("Gitignored entries hidden")
+	}
+}
+
+func (ps *ProjectScreenReal) toggleSurgeFilter() {
+	if err := ps.fileTree.SetFilterSurge(!ps.fileTree.FilterSurge); err != nil {
+		ps.handleTreeError(err)
+		return
+	}
+	ps.updateStats()
+	if ps.fileTree.FilterSurge {
+		ps.setStatus("Filter: .sg only")
+	} else {
+		ps.setStatus("Filter: all files")
+	}
+}
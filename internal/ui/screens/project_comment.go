@@ -0,0 +1,39 @@
+package screens
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// CanToggleComment сообщает, что фокус во вкладке редактора, где можно
+// закомментировать строки.
+func (ps *ProjectScreenReal) CanToggleComment() bool {
+	return ps.focusedPanel == EditorPanel && ps.activeEditorTab() != nil
+}
+
+// ToggleComment комментирует или раскомментирует строку курсора либо все
+// строки выделения (см. toggleLineComments) одним шагом undo. Выделение
+// остаётся на тех же строках.
+func (ps *ProjectScreenReal) ToggleComment() tea.Cmd {
+	if !ps.CanToggleComment() {
+		ps.setStatus("Focus an editor tab to comment lines")
+		return nil
+	}
+	tab := ps.activeEditorTab()
+	from, to := tab.cursor.Line, tab.cursor.Line
+	if tab.hasSelection() {
+		from, to = tab.selectedLineRange()
+	}
+	before := tab.snapshot()
+	count, commented := tab.toggleLineComments(from, to)
+	if count == 0 {
+		ps.setStatus("Nothing to comment: the lines are empty")
+		return nil
+	}
+	tab.pushSnapshot(before)
+	tab.clampCursor()
+	ps.ensureCursorVisible(tab)
+	if commented {
+		ps.setStatus("Commented " + plural(count, "line"))
+	} else {
+		ps.setStatus("Uncommented " + plural(count, "line"))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package screens
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	conflictOverwriteOption = "Overwrite with mine"
+	conflictReloadOption    = "Reload theirs (back up mine)"
+	conflictDiffOption      = "Show diff"
+	conflictCancelOption    = "Cancel"
+)
+
+type saveConflictChoiceMsg struct {
+	path       string
+	option     string
+	closeAfter bool
+}
+
+// confirmConflictSave спрашивает, что делать, если файл изменили на диске
+// после последнего сохранения: сохранение иначе затёрло бы чужие правки.
+func (ps *ProjectScreenReal) confirmConflictSave(tab *editorTab, closeAfter bool) tea.Cmd {
+	desc := fmt.Sprintf("%s was changed on disk by another program since it was last saved or loaded.", tab.name)
+	if tab.dirty {
+		desc += "\nSaving now would overwrite those changes with your unsaved edits."
+	} else {
+		desc += "\nSaving now would overwrite those changes with the last loaded version."
+	}
+	return ps.showConflictDialog(tab, desc, closeAfter)
+}
+
+// showConflictDialog предлагает выбрать между буфером и файлом на диске.
+func (ps *ProjectScreenReal) showConflictDialog(tab *editorTab, desc string, closeAfter bool) tea.Cmd {
+	options := []string{conflictOverwriteOption, conflictReloadOption, conflictDiffOption, conflictCancelOption}
+	ps.conflictDialog.Description = desc
+	ps.conflictDialog.Options = options
+	ch := ps.conflictDialog.Show()
+	path := tab.path
+	return func() tea.Msg {
+		choice := <-ch
+		option := ""
+		if choice >= 0 && choice < len(options) {
+			option = options[choice]
+		}
+		return saveConflictChoiceMsg{path: path, option: option, closeAfter: closeAfter}
+	}
+}
+
+func (ps *ProjectScreenReal) handleSaveConflictChoice(msg saveConflictChoiceMsg) tea.Cmd {
+	index := ps.findTabIndex(msg.path)
+	if index < 0 {
+		return nil
+	}
+	tab := ps.tabs[index]
+
+	switch msg.option {
+	case conflictOverwriteOption:
+		return ps.writeTab(tab, msg.closeAfter)
+	case conflictReloadOption:
+		backup, err := tab.backupBuffer(time.Now())
+		if err != nil {
+			ps.setStatus(fmt.Sprintf("Backup failed, buffer kept: %v", err))
+			return nil
+		}
+		if err := tab.revertFromDisk(); err != nil {
+			ps.setStatus(fmt.Sprintf("Reload failed: %v (your version is in %s)", err, backup))
+			return ps.loadFileTree()
+		}
+		ps.ensureCursorVisible(tab)
+		ps.setStatus(fmt.Sprintf("Reloaded %s from disk; your version was saved to %s", tab.name, backup))
+		if msg.closeAfter {
+			ps.forceCloseTab(index)
+		}
+		return ps.loadFileTree()
+	case conflictDiffOption:
+		diff, err := tab.writeConflictDiff(time.Now())
+		if err != nil {
+			ps.setStatus(fmt.Sprintf("Diff failed: %v", err))
+			return nil
+		}
+		if ps.openFileTab(diff) != nil {
+			ps.setStatus(fmt.Sprintf("Diff of %s vs disk: merge into the buffer, then save and overwrite", tab.name))
+		}
+		return nil
+	default:
+		ps.setStatus("Save canceled")
+		return nil
+	}
+}
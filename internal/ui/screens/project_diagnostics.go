@@ -0,0 +1,75 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// SetDiagnostics принимает результаты surge diag, сгруппированные по файлам,
+// и обновляет метки в уже открытых вкладках. Вкладки с неизменившимся
+// набором не трогаются, а у остальных сбрасываются только метки изменившихся
+// строк; метки дерева пересчитываются, только если изменился набор файла.
+func (ps *ProjectScreenReal) SetDiagnostics(diags map[string][]EditorDiagnostic) {
+	treeChanged := !sameFileDiagnostics(ps.diagnostics, diags)
+	ps.diagnostics = diags
+	if treeChanged {
+		ps.refreshTreeDiagnostics()
+	}
+	for _, tab := range ps.tabs {
+		if tab.dirty || tab.scratch {
+			// позиции несохранённого буфера могут не совпадать с файлом на диске
+			continue
+		}
+		tab.setDiagnostics(diags[filepath.Clean(tab.path)])
+	}
+}
+
+// sameFileDiagnostics сообщает, совпадают ли наборы диагностик всех файлов.
+func sameFileDiagnostics(a, b map[string][]EditorDiagnostic) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, diags := range a {
+		if !SameEditorDiagnostics(diags, b[path]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Notify показывает сообщение в строке статуса экрана.
+func (ps *ProjectScreenReal) Notify(msg string) {
+	ps.setStatus(msg)
+}
+
+// jumpToDiagnostic переводит курсор к следующей (dir > 0) или предыдущей диагностике.
+func (ps *ProjectScreenReal) jumpToDiagnostic(dir int) {
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return
+	}
+	if len(tab.diagnostics) == 0 {
+		ps.setStatus("No diagnostics in " + tab.name)
+		return
+	}
+	line, ok := adjacentDiagnosticLine(tab.diagnostics, tab.cursor.Line, dir)
+	if !ok {
+		if dir > 0 {
+			ps.setStatus("No more diagnostics below")
+		} else {
+			ps.setStatus("No more diagnostics above")
+		}
+		return
+	}
+	diag, _ := diagnosticsOnLine(tab.diagnostics, line)
+	tab.cursor = cursorPosition{Line: line, Col: diag.Col}
+	tab.clampCursor()
+	ps.ensureCursorVisible(tab)
+}
+
+func formatEditorDiagnostic(diag EditorDiagnostic) string {
+	if diag.Code != "" {
+		return fmt.Sprintf("%s [%s] %s", diag.Severity, diag.Code, diag.Message)
+	}
+	return fmt.Sprintf("%s %s", diag.Severity, diag.Message)
+}
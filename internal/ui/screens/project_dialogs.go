@@ -0,0 +1,74 @@
+package screens
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// modalDialog — общий интерфейс модальных диалогов экрана проекта.
+type modalDialog interface {
+	Update(msg tea.Msg) tea.Cmd
+	View() string
+}
+
+// activeDialog возвращает открытый диалог; одновременно открыт не больше одного.
+func (ps *ProjectScreenReal) activeDialog() modalDialog {
+	switch {
+	case ps.closeDialog != nil && ps.closeDialog.Visible:
+		return ps.closeDialog
+	case ps.confirm != nil && ps.confirm.Visible:
+		return ps.confirm
+	case ps.newFileDialog != nil && ps.newFileDialog.Visible:
+		return ps.newFileDialog
+	case ps.newDirDialog != nil && ps.newDirDialog.Visible:
+		return ps.newDirDialog
+	case ps.renameDialog != nil && ps.renameDialog.Visible:
+		return ps.renameDialog
+	case ps.patternDialog != nil && ps.patternDialog.Visible:
+		return ps.patternDialog
+	case ps.renamePreview != nil && ps.renamePreview.Visible:
+		return ps.renamePreview
+	case ps.conflictDialog != nil && ps.conflictDialog.Visible:
+		return ps.conflictDialog
+	case ps.pasteDialog != nil && ps.pasteDialog.Visible:
+		return ps.pasteDialog
+	case ps.pasteConfirm != nil && ps.pasteConfirm.Visible:
+		return ps.pasteConfirm
+	case ps.recoverDialog != nil && ps.recoverDialog.Visible:
+		return ps.recoverDialog
+	case ps.rollbackDialog != nil && ps.rollbackDialog.Visible:
+		return ps.rollbackDialog
+	case ps.rollbackDiff != nil && ps.rollbackDiff.Visible:
+		return ps.rollbackDiff
+	case ps.tests.confirm != nil && ps.tests.confirm.Visible:
+		return ps.tests.confirm
+	case ps.saveWarnDialog != nil && ps.saveWarnDialog.Visible:
+		return ps.saveWarnDialog
+	case ps.lossyDialog != nil && ps.lossyDialog.Visible:
+		return ps.lossyDialog
+	case ps.fixDialog != nil && ps.fixDialog.Visible:
+		return ps.fixDialog
+	case ps.saveAsDialog != nil && ps.saveAsDialog.Visible:
+		return ps.saveAsDialog
+	case ps.saveAsConfirm != nil && ps.saveAsConfirm.Visible:
+		return ps.saveAsConfirm
+	case ps.moveDialog != nil && ps.moveDialog.Visible:
+		return ps.moveDialog
+	case ps.copyToDialog != nil && ps.copyToDialog.Visible:
+		return ps.copyToDialog
+	case ps.revertDialog != nil && ps.revertDialog.Visible:
+		return ps.revertDialog
+	case ps.quickOpen != nil && ps.quickOpen.Visible:
+		return ps.quickOpen
+	case ps.treeLegend != nil && ps.treeLegend.Visible:
+		return ps.treeLegend
+	}
+	return nil
+}
+
+// dialogVisible сообщает, открыт ли какой-либо модальный диалог экрана.
+func (ps *ProjectScreenReal) dialogVisible() bool {
+	return ps.activeDialog() != nil
+}
+
+// DialogVisible сообщает приложению, что экран ждёт ответа в диалоге.
+func (ps *ProjectScreenReal) DialogVisible() bool {
+	return ps.dialogVisible()
+}
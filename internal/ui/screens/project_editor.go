@@ -3,10 +3,9 @@ package screens
 import (
 	"fmt"
 	"path/filepath"
-	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"surge-tui/internal/platform"
+	"surge-tui/internal/logging"
 )
 
 func (ps *ProjectScreenReal) activeEditorTab() *editorTab {
@@ -16,6 +15,30 @@ func (ps *ProjectScreenReal) activeEditorTab() *editorTab {
 	return ps.tabs[ps.activeTab]
 }
 
+// EditorStatus — активная вкладка для статус-бара приложения.
+type EditorStatus struct {
+	Name    string
+	Dirty   bool
+	Line    int  // с 1
+	Column  int  // с 1
+	Focused bool // фокус на панели редактора
+}
+
+// EditorStatus возвращает состояние активной вкладки; false — вкладок нет.
+func (ps *ProjectScreenReal) EditorStatus() (EditorStatus, bool) {
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return EditorStatus{}, false
+	}
+	return EditorStatus{
+		Name:    tab.name,
+		Dirty:   tab.dirty,
+		Line:    tab.cursor.Line + 1,
+		Column:  tab.cursor.Col + 1,
+		Focused: ps.focusedPanel == EditorPanel,
+	}, true
+}
+
 func (ps *ProjectScreenReal) findTabIndex(path string) int {
 	if path == "" {
 		return -1
@@ -54,6 +77,7 @@ func (ps *ProjectScreenReal) openFileTab(path string) *editorTab {
 		ps.setActiveTab(idx)
 		ps.focusedPanel = EditorPanel
 		ps.recalculateLayout()
+		ps.rememberRecentFile(path)
 		return ps.activeEditorTab()
 	}
 
@@ -63,12 +87,16 @@ func (ps *ProjectScreenReal) openFileTab(path string) *editorTab {
 		return nil
 	}
 
+	tab.diagnostics = diagnosticsForPath(ps.diagnostics, tab.path)
 	ps.tabs = append(ps.tabs, tab)
 	ps.activeTab = len(ps.tabs) - 1
 	ps.focusedPanel = EditorPanel
 	ps.ensureCursorVisible(tab)
 	ps.recalculateLayout()
 	ps.setStatus("Opened " + tab.name)
+	ps.rememberRecentFile(tab.path)
+	ps.checkAutosave(tab)
+	ps.saveSession()
 	return tab
 }
 
@@ -97,9 +125,32 @@ func (ps *ProjectScreenReal) OpenLocation(path string, line, column int) {
 	ps.ensureCursorVisible(tab)
 	ps.focusedPanel = EditorPanel
 	ps.recalculateLayout()
+	if tab.dirty {
+		// место посчитано surge по файлу на диске, а не по буферу
+		ps.setStatus(fmt.Sprintf("Jumped to %s:%d:%d — warning: unsaved changes, the position is from the saved file", filepath.Base(tab.path), line, column))
+		return
+	}
 	ps.setStatus(fmt.Sprintf("Jumped to %s:%d:%d", filepath.Base(tab.path), line, column))
 }
 
+// SetBackTo задаёт крошку «назад» в строке редактора: экран, к которому
+// вернёт Go Back. Пустая строка убирает крошку.
+func (ps *ProjectScreenReal) SetBackTo(screen string) {
+	ps.backTo = screen
+}
+
+// backCrumb — крошка «← Fix Mode (Alt+B)» для строки редактора.
+func (ps *ProjectScreenReal) backCrumb() string {
+	if ps.backTo == "" {
+		return ""
+	}
+	crumb := "← " + ps.backTo
+	if key := ps.commandKeys["go_back"]; key != "" {
+		crumb += " (" + key + ")"
+	}
+	return crumb
+}
+
 func (ps *ProjectScreenReal) activateAdjacentTab(offset int) {
 	if len(ps.tabs) == 0 {
 		return
@@ -162,6 +213,8 @@ func (ps *ProjectScreenReal) forceCloseTab(index int) {
 
 	tab := ps.tabs[index]
 	ps.tabs = append(ps.tabs[:index], ps.tabs[index+1:]...)
+	ps.discardAutosave(tab)
+	defer ps.saveSession()
 
 	if len(ps.tabs) == 0 {
 		ps.activeTab = -1
@@ -180,16 +233,62 @@ func (ps *ProjectScreenReal) forceCloseTab(index int) {
 	ps.setStatus("Closed " + tab.name)
 }
 
-func (ps *ProjectScreenReal) saveActiveTab() {
+func (ps *ProjectScreenReal) saveActiveTab() tea.Cmd {
 	tab := ps.activeEditorTab()
 	if tab == nil {
-		return
+		return nil
+	}
+	return ps.saveTab(tab, false)
+}
+
+// saveTab сохраняет вкладку. Если буфер изменён при декодировании,
+// сначала запрашивает подтверждение.
+func (ps *ProjectScreenReal) saveTab(tab *editorTab, closeAfter bool) tea.Cmd {
+	if tab.scratch {
+		return ps.requestSaveAs(closeAfter)
+	}
+	if ps.saveBlocked(tab) {
+		return nil
+	}
+	if problems := ps.checkSave(tab); !problems.empty() {
+		return ps.confirmSaveWarning(tab, problems, closeAfter)
+	}
+	return ps.saveChecked(tab, closeAfter)
+}
+
+// saveChecked сохраняет вкладку, уже прошедшую проверку содержимого.
+func (ps *ProjectScreenReal) saveChecked(tab *editorTab, closeAfter bool) tea.Cmd {
+	if tab.diskChanged() {
+		return ps.confirmConflictSave(tab, closeAfter)
+	}
+	return ps.writeTab(tab, closeAfter)
+}
+
+// writeTab записывает вкладку на диск, предупреждая о потерях при декодировании.
+func (ps *ProjectScreenReal) writeTab(tab *editorTab, closeAfter bool) tea.Cmd {
+	if tab.isLossy() {
+		return ps.confirmLossySave(tab, closeAfter)
 	}
 	if err := tab.save(); err != nil {
-		ps.setStatus(fmt.Sprintf("Save failed: %v", err))
-		return
+		return ps.saveFailed(err)
 	}
 	ps.setStatus("Saved " + tab.name)
+	if closeAfter {
+		ps.forceCloseTab(ps.findTabIndex(tab.path))
+	}
+	return ps.afterSave(tab)
+}
+
+// saveFailed показывает ошибку записи в статусе и уведомлением: запись из
+// команды могла завершиться, когда рабочая область уже не на экране.
+func (ps *ProjectScreenReal) saveFailed(err error) tea.Cmd {
+	text := fmt.Sprintf("Save failed: %v", err)
+	ps.setStatus(text)
+	return Notify(logging.LevelError, text)
+}
+
+func fileSaved(path string) tea.Cmd {
+	return func() tea.Msg { return FileSavedMsg{Path: path} }
 }
 
 func (ps *ProjectScreenReal) ensureCursorVisible(tab *editorTab) {
@@ -211,40 +310,9 @@ func (ps *ProjectScreenReal) ensureCursorVisible(tab *editorTab) {
 	}
 }
 
-func (ps *ProjectScreenReal) handleEditorEscape() bool {
-	tab := ps.activeEditorTab()
-	if tab == nil {
-		return false
-	}
-
-	switch tab.mode {
-	case editorModeInsert:
-		if tab.cursor.Col > 0 {
-			tab.cursor.Col--
-		}
-		tab.mode = editorModeNormal
-		tab.clearPending()
-		ps.editorCommand.Blur()
-		ps.setStatus("-- NORMAL --")
-		return true
-	case editorModeCommand:
-		tab.mode = editorModeNormal
-		ps.editorCommand.Blur()
-		ps.setStatus("-- NORMAL --")
-		return true
-	default:
-		if tab.pending != "" {
-			tab.clearPending()
-			return true
-		}
-	}
-
-	return false
-}
-
 func (ps *ProjectScreenReal) editorContentHeight() int {
 	// Панель имеет рамку (2 строки) + строка табов + статус
-	content := ps.Height() - 5
+	content := ps.panelHeight() - 5
 	if tab := ps.activeEditorTab(); tab != nil && tab.mode == editorModeCommand {
 		content--
 	}
@@ -255,304 +323,9 @@ func (ps *ProjectScreenReal) editorContentHeight() int {
 }
 
 func (ps *ProjectScreenReal) editorContentWidth() int {
-	width := ps.mainWidth - 8 // учёт бордера, паддинга и ширины номера строки
+	width := ps.mainWidth - 9 // учёт бордера, паддинга, маркера диагностики и номера строки
 	if width < 8 {
 		width = 8
 	}
 	return width
 }
-
-func (ps *ProjectScreenReal) handleEditorKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
-	if len(ps.tabs) == 0 {
-		return ps, nil
-	}
-
-	key := platform.CanonicalKeyForLookup(msg.String())
-	switch key {
-	case "ctrl+left":
-		ps.focusedPanel = FileTreePanel
-		ps.recalculateLayout()
-		return ps, nil
-	case "ctrl+right":
-		ps.focusedPanel = EditorPanel
-		ps.recalculateLayout()
-		return ps, nil
-	case "alt+left", "alt+h", "ctrl+shift+tab":
-		ps.activateAdjacentTab(-1)
-		return ps, nil
-	case "alt+right", "alt+l", "ctrl+tab":
-		ps.activateAdjacentTab(1)
-		return ps, nil
-	case "alt+shift+left":
-		ps.reorderTabs(-1)
-		return ps, nil
-	case "alt+shift+right":
-		ps.reorderTabs(1)
-		return ps, nil
-	}
-
-	tab := ps.activeEditorTab()
-	if tab == nil {
-		return ps, nil
-	}
-
-	switch tab.mode {
-	case editorModeInsert:
-		return ps.handleInsertModeKey(tab, msg)
-	case editorModeCommand:
-		return ps.handleCommandModeKey(tab, msg)
-	default:
-		return ps.handleNormalModeKey(tab, msg)
-	}
-}
-
-func (ps *ProjectScreenReal) handleInsertModeKey(tab *editorTab, msg tea.KeyMsg) (Screen, tea.Cmd) {
-	switch msg.Type {
-	case tea.KeyEsc:
-		ps.handleEditorEscape()
-		return ps, nil
-	case tea.KeyEnter:
-		tab.insertNewLine()
-		ps.ensureCursorVisible(tab)
-		return ps, nil
-	case tea.KeyBackspace:
-		tab.deleteBackward()
-		ps.ensureCursorVisible(tab)
-		return ps, nil
-	case tea.KeyDelete:
-		tab.deleteForward()
-		ps.ensureCursorVisible(tab)
-		return ps, nil
-	case tea.KeySpace:
-		tab.insertString(" ")
-		ps.ensureCursorVisible(tab)
-		return ps, nil
-	case tea.KeyRunes:
-		if msg.Alt {
-			// Alt-modified runes are ignored in insert mode for now.
-			return ps, nil
-		}
-		tab.insertRunes(msg.Runes)
-		ps.ensureCursorVisible(tab)
-		return ps, nil
-	}
-
-	key := platform.CanonicalKeyForLookup(msg.String())
-	switch key {
-	case "ctrl+s":
-		ps.saveActiveTab()
-	case "tab":
-		tab.insertString("\t")
-		ps.ensureCursorVisible(tab)
-	case "space":
-		tab.insertString(" ")
-		ps.ensureCursorVisible(tab)
-	case "backspace", "ctrl+h":
-		tab.deleteBackward()
-		ps.ensureCursorVisible(tab)
-	case "left":
-		tab.moveCursor(0, -1)
-		ps.ensureCursorVisible(tab)
-	case "right":
-		tab.moveCursor(0, 1)
-		ps.ensureCursorVisible(tab)
-	case "up":
-		tab.moveCursor(-1, 0)
-		ps.ensureCursorVisible(tab)
-	case "down":
-		tab.moveCursor(1, 0)
-		ps.ensureCursorVisible(tab)
-	case "ctrl+w":
-		return ps, ps.requestCloseActiveTab(false)
-	case "home":
-		tab.moveToStartOfLine()
-	case "end":
-		tab.moveToEndOfLine()
-	default:
-		return ps, nil
-	}
-
-	return ps, nil
-}
-
-func (ps *ProjectScreenReal) handleCommandModeKey(tab *editorTab, msg tea.KeyMsg) (Screen, tea.Cmd) {
-	switch msg.Type {
-	case tea.KeyEsc:
-		ps.handleEditorEscape()
-		return ps, nil
-	case tea.KeyEnter:
-		command := strings.TrimSpace(ps.editorCommand.Value())
-		ps.editorCommand.SetValue("")
-		ps.executeEditorCommand(tab, command)
-		return ps, nil
-	}
-
-	var cmd tea.Cmd
-	ps.editorCommand, cmd = ps.editorCommand.Update(msg)
-	return ps, cmd
-}
-
-func (ps *ProjectScreenReal) handleNormalModeKey(tab *editorTab, msg tea.KeyMsg) (Screen, tea.Cmd) {
-	key := platform.CanonicalKeyForLookup(msg.String())
-
-	switch {
-	case tab.hasPending("y"):
-		tab.clearPending()
-		if key == "y" {
-			ps.copyLine()
-			return ps, nil
-		}
-	case tab.hasPending("d"):
-		tab.clearPending()
-		if key == "d" {
-			ps.cutLine()
-			return ps, nil
-		}
-	case tab.hasPending("g"):
-		tab.clearPending()
-		if key == "g" {
-			tab.cursor.Line = 0
-			tab.cursor.Col = 0
-			ps.ensureCursorVisible(tab)
-			return ps, nil
-		}
-	}
-
-	switch key {
-	case "i":
-		tab.mode = editorModeInsert
-		ps.setStatus("-- INSERT --")
-	case "a":
-		tab.moveCursor(0, 1)
-		tab.mode = editorModeInsert
-		ps.ensureCursorVisible(tab)
-		ps.setStatus("-- INSERT --")
-	case "o":
-		tab.moveToEndOfLine()
-		tab.insertNewLine()
-		tab.mode = editorModeInsert
-		ps.ensureCursorVisible(tab)
-		ps.setStatus("-- INSERT --")
-	case "O":
-		tab.cursor.Col = 0
-		tab.insertNewLine()
-		tab.cursor.Line--
-		if tab.cursor.Line < 0 {
-			tab.cursor.Line = 0
-		}
-		tab.mode = editorModeInsert
-		ps.ensureCursorVisible(tab)
-		ps.setStatus("-- INSERT --")
-	case ":":
-		tab.mode = editorModeCommand
-		ps.editorCommand.SetValue("")
-		ps.editorCommand.SetCursor(len(ps.editorCommand.Value()))
-		ps.editorCommand.Focus()
-		ps.setStatus("-- COMMAND --")
-	case "h", "left":
-		tab.moveCursor(0, -1)
-		ps.ensureCursorVisible(tab)
-	case "l", "right":
-		tab.moveCursor(0, 1)
-		ps.ensureCursorVisible(tab)
-	case "j", "down":
-		tab.moveCursor(1, 0)
-		ps.ensureCursorVisible(tab)
-	case "k", "up":
-		tab.moveCursor(-1, 0)
-		ps.ensureCursorVisible(tab)
-	case "0", "home":
-		tab.moveToStartOfLine()
-	case "$", "end":
-		tab.moveToEndOfLine()
-	case "G":
-		tab.cursor.Line = tab.lineCount() - 1
-		tab.moveToEndOfLine()
-		ps.ensureCursorVisible(tab)
-	case "g":
-		tab.setPending("g")
-	case "y":
-		tab.setPending("y")
-	case "d":
-		tab.setPending("d")
-	case "p":
-		ps.pasteLine()
-	case "x":
-		tab.deleteForward()
-		ps.ensureCursorVisible(tab)
-	case "ctrl+s":
-		ps.saveActiveTab()
-	case "ctrl+w":
-		return ps, ps.requestCloseActiveTab(false)
-	case "ctrl+q":
-		return ps, ps.requestCloseActiveTab(false)
-	default:
-		tab.clearPending()
-	}
-
-	return ps, nil
-}
-
-func (ps *ProjectScreenReal) executeEditorCommand(tab *editorTab, input string) {
-	tab.mode = editorModeNormal
-	ps.editorCommand.Blur()
-
-	if input == "" {
-		ps.setStatus("-- NORMAL --")
-		return
-	}
-
-	force := false
-	if strings.HasSuffix(input, "!") {
-		force = true
-		input = strings.TrimSuffix(input, "!")
-	}
-
-	switch input {
-	case "w", "write":
-		ps.saveActiveTab()
-	case "q", "quit":
-		if tab.dirty && !force {
-			ps.setStatus("Unsaved changes (use :q!)")
-			return
-		}
-		ps.forceCloseTab(ps.activeTab)
-	case "wq", "x", "xit":
-		if err := tab.save(); err != nil {
-			ps.setStatus(fmt.Sprintf("Save failed: %v", err))
-			return
-		}
-		ps.forceCloseTab(ps.activeTab)
-	default:
-		ps.setStatus("Unknown command: " + input)
-	}
-}
-
-func (ps *ProjectScreenReal) copyLine() {
-	tab := ps.activeEditorTab()
-	if tab == nil {
-		return
-	}
-	ps.yankBuffer = tab.copyLine()
-	ps.setStatus("Line yanked")
-}
-
-func (ps *ProjectScreenReal) cutLine() {
-	tab := ps.activeEditorTab()
-	if tab == nil {
-		return
-	}
-	ps.yankBuffer = tab.deleteLine()
-	ps.ensureCursorVisible(tab)
-	ps.setStatus("Line cut")
-}
-
-func (ps *ProjectScreenReal) pasteLine() {
-	tab := ps.activeEditorTab()
-	if tab == nil || ps.yankBuffer == "" {
-		return
-	}
-	tab.pasteLine(ps.yankBuffer)
-	ps.ensureCursorVisible(tab)
-	ps.setStatus("Line pasted")
-}
@@ -0,0 +1,356 @@
+package screens
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/platform"
+)
+
+// Клавиши редактора: режимы вставки и нормальный (в стиле Vim), Esc и
+// построчный буфер yy / dd / p.
+
+func (ps *ProjectScreenReal) handleEditorEscape() bool {
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return false
+	}
+
+	switch tab.mode {
+	case editorModeInsert:
+		if tab.cursor.Col > 0 {
+			tab.cursor.Col--
+		}
+		tab.dropUnchangedUndo()
+		tab.mode = editorModeNormal
+		tab.clearPending()
+		ps.editorCommand.Blur()
+		ps.setStatus("-- NORMAL --")
+		return true
+	case editorModeCommand:
+		tab.mode = editorModeNormal
+		ps.editorCommand.Blur()
+		ps.setStatus("-- NORMAL --")
+		return true
+	case editorModeVisual:
+		tab.stopVisual()
+		ps.setStatus("-- NORMAL --")
+		return true
+	default:
+		if tab.pending != "" {
+			tab.clearPending()
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ps *ProjectScreenReal) handleEditorKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
+	if len(ps.tabs) == 0 {
+		return ps, nil
+	}
+
+	key := platform.CanonicalKeyForLookup(msg.String())
+	switch key {
+	case "ctrl+left":
+		ps.focusedPanel = FileTreePanel
+		ps.recalculateLayout()
+		return ps, nil
+	case "ctrl+right":
+		ps.focusedPanel = EditorPanel
+		ps.recalculateLayout()
+		return ps, nil
+	case "alt+left", "alt+h", "ctrl+shift+tab":
+		ps.activateAdjacentTab(-1)
+		return ps, nil
+	case "alt+right", "alt+l", "ctrl+tab":
+		ps.activateAdjacentTab(1)
+		return ps, nil
+	case "alt+shift+left":
+		ps.reorderTabs(-1)
+		return ps, nil
+	case "alt+shift+right":
+		ps.reorderTabs(1)
+		return ps, nil
+	case "alt+up":
+		ps.jumpToDiagnostic(-1)
+		return ps, nil
+	case "alt+down":
+		ps.jumpToDiagnostic(1)
+		return ps, nil
+	}
+
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return ps, nil
+	}
+	if msg.Paste && tab.mode != editorModeCommand {
+		if tab.mode == editorModeVisual {
+			tab.stopVisual()
+		}
+		return ps, ps.handleEditorPaste(tab, msg)
+	}
+
+	switch tab.mode {
+	case editorModeInsert:
+		return ps.handleInsertModeKey(tab, msg)
+	case editorModeCommand:
+		return ps.handleCommandModeKey(tab, msg)
+	case editorModeVisual:
+		return ps.handleVisualModeKey(tab, msg)
+	default:
+		return ps.handleNormalModeKey(tab, msg)
+	}
+}
+
+func (ps *ProjectScreenReal) handleInsertModeKey(tab *editorTab, msg tea.KeyMsg) (Screen, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		ps.handleEditorEscape()
+		return ps, nil
+	case tea.KeyEnter:
+		ps.insertNewLine(tab)
+		ps.ensureCursorVisible(tab)
+		return ps, nil
+	case tea.KeyBackspace:
+		tab.deleteBackward()
+		ps.ensureCursorVisible(tab)
+		return ps, nil
+	case tea.KeyDelete:
+		tab.deleteForward()
+		ps.ensureCursorVisible(tab)
+		return ps, nil
+	case tea.KeySpace:
+		tab.insertString(" ")
+		ps.ensureCursorVisible(tab)
+		return ps, nil
+	case tea.KeyRunes:
+		if msg.Alt {
+			// Alt-modified runes are ignored in insert mode for now.
+			return ps, nil
+		}
+		tab.insertRunes(msg.Runes)
+		ps.ensureCursorVisible(tab)
+		return ps, nil
+	}
+
+	key := platform.CanonicalKeyForLookup(msg.String())
+	switch key {
+	case "ctrl+s":
+		return ps, ps.saveActiveTab()
+	case "tab":
+		tab.insertString(ps.indentUnit())
+		ps.ensureCursorVisible(tab)
+	case "space":
+		tab.insertString(" ")
+		ps.ensureCursorVisible(tab)
+	case "backspace", "ctrl+h":
+		tab.deleteBackward()
+		ps.ensureCursorVisible(tab)
+	case "left":
+		tab.moveCursor(0, -1)
+		ps.ensureCursorVisible(tab)
+	case "right":
+		tab.moveCursor(0, 1)
+		ps.ensureCursorVisible(tab)
+	case "up":
+		tab.moveCursor(-1, 0)
+		ps.ensureCursorVisible(tab)
+	case "down":
+		tab.moveCursor(1, 0)
+		ps.ensureCursorVisible(tab)
+	case "ctrl+w":
+		return ps, ps.requestCloseActiveTab(false)
+	case "home":
+		tab.moveToStartOfLine()
+	case "end":
+		tab.moveToEndOfLine()
+	default:
+		return ps, nil
+	}
+
+	return ps, nil
+}
+
+func (ps *ProjectScreenReal) handleNormalModeKey(tab *editorTab, msg tea.KeyMsg) (Screen, tea.Cmd) {
+	key := platform.CanonicalKeyForLookup(msg.String())
+
+	switch {
+	case tab.hasPending("y"):
+		tab.clearPending()
+		if key == "y" {
+			ps.copyLine()
+			return ps, nil
+		}
+	case tab.hasPending("d"):
+		tab.clearPending()
+		switch key {
+		case "d":
+			tab.pushUndo()
+			ps.cutLine()
+			return ps, nil
+		case "o":
+			return ps, ps.RevertHunk()
+		}
+	case tab.hasPending("]"), tab.hasPending("["):
+		dir := 1
+		if tab.hasPending("[") {
+			dir = -1
+		}
+		tab.clearPending()
+		switch key {
+		case "c":
+			return ps, ps.JumpToChange(dir)
+		case "b":
+			ps.jumpToBookmark(tab, dir)
+			return ps, nil
+		case "f":
+			return ps, ps.JumpToSymbol(dir)
+		}
+	case tab.hasPending("g"):
+		tab.clearPending()
+		if key == "g" {
+			tab.cursor.Line = 0
+			tab.cursor.Col = 0
+			ps.ensureCursorVisible(tab)
+			return ps, nil
+		}
+	}
+
+	switch key {
+	case "i":
+		tab.pushUndo()
+		tab.mode = editorModeInsert
+		ps.setStatus("-- INSERT --")
+	case "a":
+		tab.pushUndo()
+		tab.moveCursor(0, 1)
+		tab.mode = editorModeInsert
+		ps.ensureCursorVisible(tab)
+		ps.setStatus("-- INSERT --")
+	case "o":
+		tab.pushUndo()
+		tab.moveToEndOfLine()
+		ps.insertNewLine(tab)
+		tab.mode = editorModeInsert
+		ps.ensureCursorVisible(tab)
+		ps.setStatus("-- INSERT --")
+	case "O":
+		tab.pushUndo()
+		tab.cursor.Col = 0
+		tab.insertNewLine()
+		tab.cursor.Line--
+		if tab.cursor.Line < 0 {
+			tab.cursor.Line = 0
+		}
+		if ps.editorCfg.AutoIndent {
+			// новая строка выше получает отступ строки, над которой открыта
+			indent := lineIndent(tab.lines[tab.cursor.Line+1])
+			tab.lines[tab.cursor.Line] = indent
+			tab.cursor.Col = len(indent)
+		}
+		tab.mode = editorModeInsert
+		ps.ensureCursorVisible(tab)
+		ps.setStatus("-- INSERT --")
+	case ":":
+		tab.mode = editorModeCommand
+		ps.editorCommand.SetValue("")
+		ps.editorCommand.SetCursor(len(ps.editorCommand.Value()))
+		ps.editorCommand.Focus()
+		ps.setStatus("-- COMMAND --")
+	case "h", "left":
+		tab.moveCursor(0, -1)
+		ps.ensureCursorVisible(tab)
+	case "l", "right":
+		tab.moveCursor(0, 1)
+		ps.ensureCursorVisible(tab)
+	case "j", "down":
+		tab.moveCursor(1, 0)
+		ps.ensureCursorVisible(tab)
+	case "k", "up":
+		tab.moveCursor(-1, 0)
+		ps.ensureCursorVisible(tab)
+	case "0", "home":
+		tab.moveToStartOfLine()
+	case "$", "end":
+		tab.moveToEndOfLine()
+	case "w", "b", "e":
+		ps.moveByWord(tab, key)
+	case "%":
+		ps.jumpToBracket(tab)
+	case "ctrl+d":
+		ps.selectNextOccurrence(tab)
+	case "G":
+		tab.cursor.Line = tab.lineCount() - 1
+		tab.moveToEndOfLine()
+		ps.ensureCursorVisible(tab)
+	case "g":
+		tab.setPending("g")
+	case "y":
+		tab.setPending("y")
+	case "d":
+		tab.setPending("d")
+	case "]", "[":
+		tab.setPending(key)
+	case "p":
+		if ps.yankBuffer != "" {
+			tab.pushUndo()
+		}
+		ps.pasteLine()
+	case "x":
+		tab.pushUndo()
+		tab.deleteForward()
+		tab.dropUnchangedUndo()
+		ps.ensureCursorVisible(tab)
+	case "m":
+		ps.toggleLineBookmark(tab)
+	case "v":
+		ps.enterVisualMode(tab, false)
+	case "V":
+		ps.enterVisualMode(tab, true)
+	case "u":
+		ps.undoEdit(tab)
+	case "ctrl+r":
+		ps.redoEdit(tab)
+	case "ctrl+s":
+		return ps, ps.saveActiveTab()
+	case "ctrl+.", "alt+enter":
+		return ps, ps.requestInlineFix(tab)
+	case "ctrl+w":
+		return ps, ps.requestCloseActiveTab(false)
+	case "ctrl+q":
+		return ps, ps.requestCloseActiveTab(false)
+	default:
+		tab.clearPending()
+	}
+
+	return ps, nil
+}
+
+func (ps *ProjectScreenReal) copyLine() {
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return
+	}
+	ps.yankBuffer = tab.copyLine()
+	ps.setStatus("Line yanked")
+}
+
+func (ps *ProjectScreenReal) cutLine() {
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return
+	}
+	ps.yankBuffer = tab.deleteLine()
+	ps.ensureCursorVisible(tab)
+	ps.setStatus("Line cut")
+}
+
+func (ps *ProjectScreenReal) pasteLine() {
+	tab := ps.activeEditorTab()
+	if tab == nil || ps.yankBuffer == "" {
+		return
+	}
+	tab.pasteLine(ps.yankBuffer)
+	ps.ensureCursorVisible(tab)
+	ps.setStatus("Line pasted")
+}
@@ -0,0 +1,83 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Терминал с bracketed paste присылает вставку одним KeyMsg с Paste=true.
+// Она применяется одной правкой с одним снимком undo, а не посимвольно, и
+// в normal-режиме не разбирается как команды. Очень большие вставки
+// подтверждаются.
+
+const (
+	editorPasteConfirmLines = 10_000
+	editorPasteConfirmBytes = 4 << 20
+)
+
+type editorPasteConfirmedMsg struct {
+	tab       *editorTab
+	text      string
+	lines     int
+	confirmed bool
+}
+
+// handleEditorPaste вставляет текст из терминала в активную вкладку.
+func (ps *ProjectScreenReal) handleEditorPaste(tab *editorTab, msg tea.KeyMsg) tea.Cmd {
+	text := normalizeNewlines(string(msg.Runes))
+	if text == "" {
+		return nil
+	}
+	lines := pastedLines(text)
+	if lines <= editorPasteConfirmLines && len(text) <= editorPasteConfirmBytes {
+		ps.pasteIntoTab(tab, text)
+		return nil
+	}
+	ps.pasteConfirm.Description = fmt.Sprintf("Paste %s lines (%s KB) into %s?", groupDigits(lines), groupDigits(len(text)/1024), tab.name)
+	ch := ps.pasteConfirm.Show()
+	return func() tea.Msg {
+		return editorPasteConfirmedMsg{tab: tab, text: text, lines: lines, confirmed: <-ch}
+	}
+}
+
+func (ps *ProjectScreenReal) handleEditorPasteConfirmed(msg editorPasteConfirmedMsg) {
+	if !msg.confirmed {
+		ps.setStatus("Paste canceled")
+		return
+	}
+	for _, tab := range ps.tabs {
+		if tab == msg.tab {
+			ps.pasteIntoTab(tab, msg.text)
+			return
+		}
+	}
+	ps.setStatus("Paste canceled: tab was closed")
+}
+
+func (ps *ProjectScreenReal) pasteIntoTab(tab *editorTab, text string) {
+	tab.pushUndo()
+	tab.insertText(text)
+	if tab.mode == editorModeInsert {
+		// дальнейший ввод откатывается отдельно от вставки; без ввода
+		// лишний снимок уберёт выход из insert-режима
+		tab.pushUndo()
+	}
+	ps.ensureCursorVisible(tab)
+	if !strings.Contains(text, "\n") {
+		ps.setStatus("Pasted " + plural(len([]rune(text)), "character"))
+		return
+	}
+	ps.setStatus(fmt.Sprintf("Pasted %s lines", groupDigits(pastedLines(text))))
+}
+
+// pastedLines считает строки вставки; завершающий перевод строки не
+// добавляет пустую строку.
+func pastedLines(text string) int {
+	lines := strings.Count(text, "\n")
+	if !strings.HasSuffix(text, "\n") {
+		lines++
+	}
+	return lines
+}
@@ -0,0 +1,156 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Пустое дерево: в каталоге проекта ничего нет или все записи скрыли
+// фильтры. Вместо голой надписи под корнем выводится панель с тем, что
+// можно сделать дальше: создать файл или каталог, инициализировать проект,
+// показать скрытое.
+
+type treeEmptyKind int
+
+const (
+	treeNotEmpty    treeEmptyKind = iota
+	treeEmptyDir                  // в каталоге нет записей
+	treeAllFiltered               // записи есть, но фильтры скрыли все
+)
+
+// treeEntryActions — команды дерева, которым нужна выбранная запись.
+var treeEntryActions = map[string]bool{
+	"rename":             true,
+	"rename_pattern":     true,
+	"delete":             true,
+	"copy":               true,
+	"cut":                true,
+	"duplicate":          true,
+	"copy_relative_path": true,
+	"mark":               true,
+	"open_file":          true,
+}
+
+// SetSurgeAvailable сообщает экрану, найден ли surge: от этого зависит,
+// предлагает ли пустой каталог `surge init`.
+func (ps *ProjectScreenReal) SetSurgeAvailable(available bool) {
+	ps.surgeAvailable = available
+}
+
+// treeEmptyState определяет, пусто ли дерево и почему.
+func (ps *ProjectScreenReal) treeEmptyState() treeEmptyKind {
+	if ps.fileTree == nil || ps.fileTree.Root == nil {
+		return treeEmptyDir
+	}
+	root := ps.fileTree.Root
+	if len(ps.fileTree.FlatList) > 1 || !root.Expanded {
+		return treeNotEmpty
+	}
+	if len(root.Children) > 0 || ps.fileTree.CountFiltered(root.Path).Total() > 0 {
+		return treeAllFiltered
+	}
+	return treeEmptyDir
+}
+
+// emptyActionStatus объясняет, почему команде записи нечего делать.
+func (kind treeEmptyKind) emptyActionStatus() string {
+	if kind == treeAllFiltered {
+		return "No entries shown: all are hidden by filters"
+	}
+	return "No entries: the directory is empty"
+}
+
+// treeKey — клавиша команды дерева для подсказки; пусто, если не назначена.
+func (ps *ProjectScreenReal) treeKey(action string) string {
+	return ps.commandKeys["project."+action]
+}
+
+// renderTreeEmpty рисует панель пустого дерева.
+func (ps *ProjectScreenReal) renderTreeEmpty(kind treeEmptyKind) string {
+	key := lipgloss.NewStyle().Foreground(lipgloss.Color(diagHeaderColor)).Bold(true)
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(DimTextColor))
+	action := func(name, text string) string {
+		if k := ps.treeKey(name); k != "" {
+			return "  " + key.Render(k) + "  " + text
+		}
+		return "  " + text + dim.Render(" (palette)")
+	}
+
+	var lines []string
+	if kind == treeAllFiltered {
+		lines = append(lines, "All entries are hidden by filters:")
+		lines = append(lines, ps.activeTreeFilters(action)...)
+		lines = append(lines, action("clear_filters", "Clear all filters"), "")
+	} else {
+		lines = append(lines, "This directory is empty", "")
+	}
+	lines = append(lines, action("new_file", "New file"), action("new_dir", "New directory"))
+	if root := ps.fileTree; root != nil && root.Root != nil && ps.client != nil && ps.surgeAvailable && !ps.isProjectDirectory(root.Root.Path) {
+		lines = append(lines, action("init_project", "Init surge project"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// activeTreeFilters перечисляет фильтры, которые сейчас что-то скрывают.
+func (ps *ProjectScreenReal) activeTreeFilters(action func(name, text string) string) []string {
+	counts := ps.fileTree.CountFiltered(ps.fileTree.Root.Path)
+	var lines []string
+	if counts.Hidden > 0 {
+		lines = append(lines, action("toggle_hidden", fmt.Sprintf("Show hidden entries (%d)", counts.Hidden)))
+	}
+	if counts.Ignored > 0 {
+		lines = append(lines, action("toggle_ignored", fmt.Sprintf("Show gitignored entries (%d)", counts.Ignored)))
+	}
+	if counts.NonSurge > 0 {
+		lines = append(lines, action("filter_surge", fmt.Sprintf("Show files other than .sg (%d)", counts.NonSurge)))
+	}
+	if ps.treeLegend.filtering() {
+		lines = append(lines, action("diag_legend", "Only files with diagnostics are shown"))
+	}
+	return lines
+}
+
+// clearTreeFilters снимает фильтр .sg и фильтр диагностик, а также
+// показывает скрытые и исключённые .gitignore записи, если в корне они есть.
+func (ps *ProjectScreenReal) clearTreeFilters() {
+	tree := ps.fileTree
+	if tree.Root == nil {
+		return
+	}
+	counts := tree.CountFiltered(tree.Root.Path)
+	var cleared []string
+	if counts.Hidden > 0 {
+		if err := tree.SetShowHidden(true); err != nil {
+			ps.handleTreeError(err)
+			return
+		}
+		cleared = append(cleared, "hidden")
+	}
+	if counts.Ignored > 0 {
+		if err := tree.SetShowIgnored(true); err != nil {
+			ps.handleTreeError(err)
+			return
+		}
+		cleared = append(cleared, "gitignored")
+	}
+	if tree.FilterSurge {
+		if err := tree.SetFilterSurge(false); err != nil {
+			ps.handleTreeError(err)
+			return
+		}
+		cleared = append(cleared, ".sg only")
+	}
+	if ps.treeLegend.filtering() {
+		ps.treeLegend.onlyWithDiags = false
+		ps.applyTreeFilter()
+		cleared = append(cleared, "diagnostics")
+	}
+	ps.updateStats()
+	if len(cleared) == 0 {
+		ps.setStatus("No tree filters to clear")
+		return
+	}
+	ps.setStatus("Filters cleared: " + strings.Join(cleared, ", "))
+}
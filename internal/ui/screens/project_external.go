@@ -0,0 +1,83 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/platform"
+)
+
+// Открытие файла активной вкладки во внешнем редакторе (editor.external_editor).
+// Редактор получает терминал, экран ждёт его завершения. Перед запуском
+// буфер сохраняется, если для этого не нужны подтверждения; иначе правки
+// остаются в буфере. После выхода файл перечитывается только в чистую
+// вкладку: если в буфере есть несохранённые правки, а файл изменился,
+// показывается диалог конфликта, как при сохранении.
+
+// ExternalEditorClosedMsg — внешний редактор завершился.
+type ExternalEditorClosedMsg struct {
+	Path string
+	Err  error
+}
+
+// CanOpenExternalEditor сообщает, есть ли файл для внешнего редактора.
+func (ps *ProjectScreenReal) CanOpenExternalEditor() bool {
+	tab := ps.activeEditorTab()
+	return tab != nil && !tab.scratch
+}
+
+// OpenExternalEditor запускает внешний редактор для файла активной вкладки.
+func (ps *ProjectScreenReal) OpenExternalEditor() tea.Cmd {
+	tab := ps.activeEditorTab()
+	if tab == nil || tab.scratch {
+		ps.setStatus("No file to open in the external editor")
+		return nil
+	}
+	if ps.editorCfg.ExternalEditor == "" {
+		ps.setStatus("Set editor.external_editor in the settings first")
+		return nil
+	}
+	if tab.dirty && !ps.saveBlocked(tab) && !tab.diskChanged() && !tab.isLossy() {
+		if err := tab.save(); err != nil {
+			return ps.saveFailed(err)
+		}
+	}
+
+	line := ps.editorCfg.ExternalEditor + " " + platform.ShellQuote(tab.path)
+	path := tab.path
+	return tea.ExecProcess(platform.ShellCommand(context.Background(), line), func(err error) tea.Msg {
+		return ExternalEditorClosedMsg{Path: path, Err: err}
+	})
+}
+
+// handleExternalEditorClosed перечитывает файл, не затирая несохранённые правки.
+func (ps *ProjectScreenReal) handleExternalEditorClosed(msg ExternalEditorClosedMsg) tea.Cmd {
+	index := ps.findTabIndex(msg.Path)
+	if index < 0 {
+		return nil
+	}
+	tab := ps.tabs[index]
+	if msg.Err != nil {
+		ps.setStatus(fmt.Sprintf("External editor failed: %v", msg.Err))
+	}
+	switch {
+	case !tab.diskChanged():
+		if msg.Err == nil {
+			ps.setStatus(tab.name + " unchanged in the external editor")
+		}
+		return nil
+	case tab.dirty:
+		desc := fmt.Sprintf("%s was changed in the external editor, and this buffer has unsaved edits.", tab.name)
+		desc += "\nOverwriting drops the external changes; reloading keeps a backup of the edits here."
+		return ps.showConflictDialog(tab, desc, false)
+	}
+	if err := tab.revertFromDisk(); err != nil {
+		ps.setStatus(fmt.Sprintf("Reload failed: %v", err))
+		return nil
+	}
+	ps.ensureCursorVisible(tab)
+	ps.setStatus("Reloaded " + tab.name + " after the external editor")
+	// как после сохранения: diag_on_save учитывает внешние правки
+	return fileSaved(tab.path)
+}
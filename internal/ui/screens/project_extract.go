@@ -0,0 +1,218 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Два рефакторинга поверх Save As: «Copy to New File…» записывает весь буфер
+// под новым именем (заготовка по образцу), «Extract Selection to New File…»
+// переносит выделение в новый файл и оставляет на его месте
+// editor.extract_placeholder или ничего. Путь проверяется так же, как у
+// :saveas, новый файл открывается во вкладке, а вырезание выделения — один
+// шаг undo исходного буфера.
+
+// extraction — выделение, переносимое в новый файл.
+type extraction struct {
+	r        bufferRange // что убрать из буфера
+	text     string      // содержимое нового файла
+	linewise bool
+	toEnd    bool   // построчно до конца буфера: забран перевод строки перед выделением
+	indent   string // отступ первой строки для заглушки
+}
+
+// copyToConfirmedMsg — путь нового файла из диалога; extract — выделение,
+// которое переносится, nil — копия всего буфера.
+type copyToConfirmedMsg struct {
+	tab     *editorTab
+	value   *string
+	extract *extraction
+}
+
+// CanCopyToFile сообщает, есть ли вкладка, буфер которой можно скопировать.
+func (ps *ProjectScreenReal) CanCopyToFile() bool {
+	return ps.activeEditorTab() != nil
+}
+
+// CopyToFile запрашивает путь копии буфера активной вкладки.
+func (ps *ProjectScreenReal) CopyToFile() tea.Cmd {
+	tab := ps.activeEditorTab()
+	if tab == nil || ps.copyToDialog == nil {
+		ps.setStatus("No buffer to copy")
+		return nil
+	}
+	value := ""
+	if !tab.scratch {
+		value = ps.relativePath(tab.path)
+	}
+	ps.copyToDialog.Title = "Copy to New File"
+	return ps.askNewFile(tab, value, nil)
+}
+
+// CanExtractSelection сообщает, есть ли выделение, которое можно перенести.
+func (ps *ProjectScreenReal) CanExtractSelection() bool {
+	tab := ps.activeEditorTab()
+	return tab != nil && tab.hasSelection()
+}
+
+// ExtractSelection запрашивает путь файла для выделения активной вкладки.
+func (ps *ProjectScreenReal) ExtractSelection() tea.Cmd {
+	tab := ps.activeEditorTab()
+	if tab == nil || !tab.hasSelection() || ps.copyToDialog == nil {
+		ps.setStatus("Select text to extract first (v or V)")
+		return nil
+	}
+	extract := tab.selectionExtraction()
+	value := ""
+	if !tab.scratch {
+		if dir := ps.relativePath(filepath.Dir(tab.path)); dir != "." {
+			value = dir + string(filepath.Separator)
+		}
+	}
+	ps.copyToDialog.Title = "Extract to New File"
+	return ps.askNewFile(tab, value, &extract)
+}
+
+func (ps *ProjectScreenReal) askNewFile(tab *editorTab, value string, extract *extraction) tea.Cmd {
+	ch := ps.copyToDialog.ShowWithValue(value)
+	return func() tea.Msg {
+		return copyToConfirmedMsg{tab: tab, value: <-ch, extract: extract}
+	}
+}
+
+func (ps *ProjectScreenReal) handleCopyToConfirmed(msg copyToConfirmedMsg) tea.Cmd {
+	if msg.value == nil || strings.TrimSpace(*msg.value) == "" || !ps.hasTab(msg.tab) {
+		return nil
+	}
+	return ps.saveTabAs(saveAsRequest{tab: msg.tab, path: *msg.value, copy: msg.extract == nil, extract: msg.extract}, false)
+}
+
+// hasTab сообщает, что вкладка ещё открыта (у scratch-вкладки нет пути).
+func (ps *ProjectScreenReal) hasTab(tab *editorTab) bool {
+	for _, t := range ps.tabs {
+		if t == tab {
+			return true
+		}
+	}
+	return false
+}
+
+// writeNewFile записывает копию буфера или выделение в новый файл и
+// открывает его. Выделение убирается из буфера только после удачной записи.
+func (ps *ProjectScreenReal) writeNewFile(req saveAsRequest) tea.Cmd {
+	tab := req.tab
+	content := tab.text()
+	if req.extract != nil {
+		if !tab.validRange(req.extract.r) {
+			ps.setStatus("Selection changed; select the text again")
+			return nil
+		}
+		content = req.extract.text
+	}
+	if err := os.MkdirAll(filepath.Dir(req.path), 0o755); err != nil {
+		return ps.saveFailed(err)
+	}
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(tab.path); err == nil && !tab.scratch {
+		perm = info.Mode().Perm()
+	}
+	if err := os.WriteFile(req.path, []byte(content), perm); err != nil {
+		return ps.saveFailed(err)
+	}
+
+	rel := ps.relativePath(req.path)
+	status := "Copied to " + rel
+	if req.extract != nil {
+		before := tab.snapshot()
+		tab.stopVisual()
+		tab.replaceRange(req.extract.r, ps.extractPlaceholder(*req.extract, rel))
+		tab.pushSnapshot(before)
+		tab.clampCursor()
+		ps.ensureCursorVisible(tab)
+		lines := strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1
+		status = fmt.Sprintf("Extracted %s to %s", plural(lines, "line"), rel)
+	}
+
+	ps.openFileTab(req.path)
+	ps.setStatus(status)
+	cmds := []tea.Cmd{fileSaved(req.path)}
+	if ps.fileTree != nil {
+		cmds = append(cmds, ps.readTreeDirs([]string{filepath.Dir(req.path)}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// extractPlaceholder — текст на месте перенесённого выделения:
+// editor.extract_placeholder с {path} — путём нового файла от корня проекта.
+// Построчное выделение заменяется отдельной строкой с отступом первой строки.
+func (ps *ProjectScreenReal) extractPlaceholder(e extraction, rel string) string {
+	text := strings.ReplaceAll(ps.editorCfg.ExtractPlaceholder, "{path}", filepath.ToSlash(rel))
+	switch {
+	case text == "" || !e.linewise:
+		return text
+	case !e.toEnd:
+		return e.indent + text + "\n"
+	case e.r.startLine == 0 && e.r.startCol == 0:
+		return e.indent + text // выделен весь буфер
+	default:
+		return "\n" + e.indent + text
+	}
+}
+
+// selectionExtraction описывает выделение для переноса в новый файл.
+func (t *editorTab) selectionExtraction() extraction {
+	first, last := t.selectedLineRange()
+	line := t.lines[first]
+	e := extraction{r: t.selectionRange(), linewise: t.visualLine}
+	e.toEnd = e.linewise && last == len(t.lines)-1
+	e.indent = lineIndent(line)
+	e.text = t.textInRange(e.r)
+	if e.linewise {
+		e.text = strings.Join(t.lines[first:last+1], "\n")
+		if !e.toEnd || t.lines[last] != "" {
+			e.text += "\n"
+		}
+	}
+	return e
+}
+
+// selectionRange переводит выделение в диапазон буфера с исключённым концом.
+// Построчное выделение забирает строки целиком вместе с переводом строки.
+func (t *editorTab) selectionRange() bufferRange {
+	start, end := t.selectionBounds()
+	last := len(t.lines) - 1
+	if t.visualLine {
+		if end.Line < last {
+			return bufferRange{startLine: start.Line, endLine: end.Line + 1}
+		}
+		if start.Line > 0 {
+			// последняя строка файла: забираем перевод строки перед выделением
+			prev := len([]rune(t.lines[start.Line-1]))
+			return bufferRange{startLine: start.Line - 1, startCol: prev, endLine: last, endCol: len([]rune(t.lines[last]))}
+		}
+		return bufferRange{endLine: last, endCol: len([]rune(t.lines[last]))}
+	}
+	r := bufferRange{startLine: start.Line, startCol: start.Col, endLine: end.Line, endCol: end.Col + 1}
+	if length := len([]rune(t.lines[end.Line])); r.endCol > length {
+		// выделение захватило перевод строки
+		if end.Line < last {
+			r.endLine, r.endCol = end.Line+1, 0
+		} else {
+			r.endCol = length
+		}
+	}
+	r.startCol = min(r.startCol, len([]rune(t.lines[start.Line])))
+	return r
+}
+
+// validRange сообщает, что диапазон всё ещё внутри буфера.
+func (t *editorTab) validRange(r bufferRange) bool {
+	if r.startLine < 0 || r.endLine >= len(t.lines) || r.endLine < r.startLine {
+		return false
+	}
+	return r.startCol <= len([]rune(t.lines[r.startLine])) && r.endCol <= len([]rune(t.lines[r.endLine]))
+}
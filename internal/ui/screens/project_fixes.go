@@ -0,0 +1,168 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	core "surge-tui/internal/core/surge"
+	"surge-tui/internal/ui/components"
+)
+
+type inlineFixChoiceMsg struct {
+	path string
+	fix  core.FixJSON
+	ok   bool
+}
+
+type inlineFixAppliedMsg struct {
+	path  string
+	title string
+	err   error
+}
+
+// SetSurgeClient задаёт клиента surge для фиксов, у которых нет готовых правок.
+func (ps *ProjectScreenReal) SetSurgeClient(client core.SurgeRunner) {
+	ps.client = client
+}
+
+// fixesNearCursor собирает фиксы диагностик строки курсора,
+// а если их нет — соседних строк.
+func fixesNearCursor(tab *editorTab) (EditorDiagnostic, []core.FixJSON) {
+	line := tab.cursor.Line
+	for _, candidate := range []int{line, line - 1, line + 1} {
+		var fixes []core.FixJSON
+		var first EditorDiagnostic
+		for _, d := range tab.diagnostics {
+			if candidate < d.Line || candidate > d.EndLine || len(d.Fixes) == 0 {
+				continue
+			}
+			if len(fixes) == 0 {
+				first = d
+			}
+			fixes = append(fixes, d.Fixes...)
+		}
+		if len(fixes) > 0 {
+			return first, fixes
+		}
+	}
+	return EditorDiagnostic{}, nil
+}
+
+// requestInlineFix показывает список фиксов для диагностики под курсором.
+func (ps *ProjectScreenReal) requestInlineFix(tab *editorTab) tea.Cmd {
+	if tab.scratch {
+		ps.setStatus("Fixes are not available for " + scratchTabName)
+		return nil
+	}
+	diag, fixes := fixesNearCursor(tab)
+	if len(fixes) == 0 {
+		ps.setStatus("No fixes at cursor")
+		return nil
+	}
+
+	options := make([]string, len(fixes))
+	for i, fix := range fixes {
+		options[i] = fix.Title
+		if options[i] == "" {
+			options[i] = "(unnamed fix)"
+		}
+	}
+	ps.fixDialog.Description = formatEditorDiagnostic(diag)
+	ps.fixDialog.Options = options
+	ch := ps.fixDialog.Show()
+	path := tab.path
+	return func() tea.Msg {
+		choice := <-ch
+		if choice == components.ChoiceCanceled || choice >= len(fixes) {
+			return inlineFixChoiceMsg{path: path}
+		}
+		return inlineFixChoiceMsg{path: path, fix: fixes[choice], ok: true}
+	}
+}
+
+// handleInlineFixChoice применяет правки фикса к буферу (с undo), а если
+// правок нет — сохраняет файл и вызывает `surge fix --id`.
+func (ps *ProjectScreenReal) handleInlineFixChoice(msg inlineFixChoiceMsg) tea.Cmd {
+	if !msg.ok {
+		ps.setStatus("Fix canceled")
+		return nil
+	}
+	index := ps.findTabIndex(msg.path)
+	if index < 0 {
+		return nil
+	}
+	tab := ps.tabs[index]
+
+	if len(msg.fix.Edits) > 0 {
+		if err := tab.applyFixEdits(msg.fix.Edits); err != nil {
+			ps.setStatus(fmt.Sprintf("Fix not applied: %v", err))
+			return nil
+		}
+		ps.ensureCursorVisible(tab)
+		ps.setStatus("Applied fix: " + msg.fix.Title)
+		return tea.Sequence(ps.saveTab(tab, false), requestFileDiagnostics(tab.path))
+	}
+
+	if msg.fix.ID == "" || ps.client == nil {
+		ps.setStatus("Fix cannot be applied from the editor")
+		return nil
+	}
+	if tab.dirty {
+		if tab.isLossy() {
+			ps.setStatus("Save the file before applying this fix")
+			return nil
+		}
+		if err := tab.save(); err != nil {
+			return ps.saveFailed(err)
+		}
+	}
+
+	token, ok := ps.beginProjectOp("applying a fix to " + tab.name)
+	if !ok {
+		return nil
+	}
+	client := ps.client
+	path := tab.path
+	fix := msg.fix
+	ps.setStatus("Applying fix…")
+	return ps.underProjectOp(token, func() tea.Msg {
+		err := client.ApplyFixByID(context.Background(), path, fix.ID)
+		return inlineFixAppliedMsg{path: path, title: fix.Title, err: err}
+	})
+}
+
+func (ps *ProjectScreenReal) handleInlineFixApplied(msg inlineFixAppliedMsg) tea.Cmd {
+	if msg.err != nil {
+		ps.setStatus(fmt.Sprintf("Failed to apply fix: %v", msg.err))
+		return nil
+	}
+	if err := ps.ReloadFile(msg.path); err != nil {
+		ps.setStatus(fmt.Sprintf("Failed to reload %v", err))
+		return nil
+	}
+	ps.setStatus("Applied fix: " + msg.title)
+	return requestFileDiagnostics(msg.path)
+}
+
+// ReloadFile перечитывает открытую вкладку файла после правки на диске;
+// если файл не открыт, ничего не делает.
+func (ps *ProjectScreenReal) ReloadFile(path string) error {
+	index := ps.findTabIndex(path)
+	if index < 0 {
+		return nil
+	}
+	tab := ps.tabs[index]
+	if err := tab.reloadFromDisk(); err != nil {
+		return fmt.Errorf("%s: %w", tab.name, err)
+	}
+	ps.ensureCursorVisible(tab)
+	return nil
+}
+
+func requestFileDiagnostics(path string) tea.Cmd {
+	if path == "" {
+		return nil // буфер без файла
+	}
+	return func() tea.Msg { return RunFileDiagnosticsMsg{Path: path} }
+}
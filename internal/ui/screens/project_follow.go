@@ -0,0 +1,124 @@
+package screens
+
+import "fmt"
+
+// Следование дерева за вкладками (ui.follow_active_tab, L в дереве): при
+// смене активной вкладки дерево раскрывает каталоги до её файла и выделяет
+// его. Пока фокус в дереве, следование приостановлено, чтобы не перебивать
+// навигацию. Shift+↑/↓ в дереве двигают выделение и открывают файл на
+// просмотр, не забирая фокус; просматриваемая вкладка заменяется следующей,
+// пока в неё не перешли.
+
+// followState — следование дерева за активной вкладкой.
+type followState struct {
+	enabled bool
+	path    string     // файл вкладки, под который дерево уже подстроено
+	preview *editorTab // вкладка, открытая Shift+↑/↓ и ещё не закреплённая
+}
+
+// SetFollowActiveTab включает или выключает следование дерева за вкладкой.
+func (ps *ProjectScreenReal) SetFollowActiveTab(enabled bool) {
+	ps.follow.enabled = enabled
+	ps.follow.path = ""
+}
+
+// ToggleFollowActiveTab переключает следование; включённое сразу выделяет
+// файл активной вкладки, даже если фокус в дереве.
+func (ps *ProjectScreenReal) ToggleFollowActiveTab() {
+	ps.SetFollowActiveTab(!ps.follow.enabled)
+	if !ps.follow.enabled {
+		ps.setStatus("Follow active tab: off")
+		return
+	}
+	ps.setStatus("Follow active tab: on")
+	if tab := ps.activeEditorTab(); tab != nil && !tab.scratch {
+		ps.follow.path = tab.path
+		ps.revealInTree(tab.path)
+	}
+}
+
+// revealInTree раскрывает каталоги до path и выделяет его в дереве.
+func (ps *ProjectScreenReal) revealInTree(path string) bool {
+	if ps.fileTree == nil || !ps.fileTree.Reveal(path) {
+		return false
+	}
+	ps.updateStats() // раскрытие могло прочитать каталоги
+	return true
+}
+
+// trackFollow подстраивает дерево под активную вкладку после каждого
+// сообщения. Смена вкладки, пока фокус в дереве, только запоминается.
+func (ps *ProjectScreenReal) trackFollow() {
+	if ps.focusedPanel != FileTreePanel {
+		ps.follow.preview = nil // в просмотренную вкладку перешли — она остаётся
+	}
+	if !ps.follow.enabled || ps.fileTree == nil || ps.loading {
+		return
+	}
+	path := ""
+	if tab := ps.activeEditorTab(); tab != nil && !tab.scratch {
+		path = tab.path
+	}
+	if path == ps.follow.path {
+		return
+	}
+	ps.follow.path = path
+	if path != "" && ps.focusedPanel != FileTreePanel {
+		ps.revealInTree(path)
+	}
+}
+
+// previewTreeEntry сдвигает выделение дерева на delta и показывает файл под
+// ним во вкладке, оставляя фокус в дереве. Уже открытый файл просто
+// становится активным; новый открывается на месте прошлого просмотра.
+func (ps *ProjectScreenReal) previewTreeEntry(delta int) {
+	ps.fileTree.SetSelected(ps.fileTree.Selected + delta)
+	node := ps.fileTree.GetSelected()
+	if node == nil || node.IsDir {
+		return
+	}
+	if prev := ps.follow.preview; prev != nil && prev.path != node.Path {
+		ps.closePreview()
+	}
+	if idx := ps.findTabIndex(node.Path); idx >= 0 {
+		ps.setActiveTab(idx)
+	} else {
+		tab, err := newEditorTab(node.Path)
+		if err != nil {
+			ps.setStatus(fmt.Sprintf("Failed to open file: %v", err))
+			return
+		}
+		tab.diagnostics = diagnosticsForPath(ps.diagnostics, tab.path)
+		ps.tabs = append(ps.tabs, tab)
+		ps.activeTab = len(ps.tabs) - 1
+		ps.ensureCursorVisible(tab)
+		ps.checkAutosave(tab)
+		ps.saveSession()
+		ps.follow.preview = tab
+	}
+	ps.follow.path = ps.activeEditorTab().path
+	ps.recalculateLayout()
+}
+
+// closePreview закрывает вкладку прошлого просмотра, если её не меняли.
+func (ps *ProjectScreenReal) closePreview() {
+	prev := ps.follow.preview
+	ps.follow.preview = nil
+	if prev.dirty {
+		return
+	}
+	for i, tab := range ps.tabs {
+		if tab != prev {
+			continue
+		}
+		ps.tabs = append(ps.tabs[:i], ps.tabs[i+1:]...)
+		ps.discardAutosave(prev)
+		if ps.activeTab >= i {
+			ps.activeTab = max(ps.activeTab-1, 0)
+		}
+		if len(ps.tabs) == 0 {
+			ps.activeTab = -1
+		}
+		return
+	}
+}
@@ -0,0 +1,163 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/logging"
+	"surge-tui/internal/syntax"
+)
+
+// FormatDoneMsg — `surge fmt` завершился для файла или каталога проекта.
+// App доставляет его экрану проекта, даже если активен другой экран.
+type FormatDoneMsg struct {
+	path    string
+	project bool
+	onSave  bool // запуск после сохранения: FileSavedMsg отправляется после форматирования
+	err     error
+}
+
+// CanFormatFile сообщает, можно ли отформатировать файл активной вкладки.
+func (ps *ProjectScreenReal) CanFormatFile() bool {
+	tab := ps.activeEditorTab()
+	return ps.client != nil && tab != nil && !tab.scratch && syntax.SupportsFile(tab.path)
+}
+
+// FormatFile форматирует файл активной вкладки через `surge fmt`.
+// Несохранённые правки сначала записываются на диск.
+func (ps *ProjectScreenReal) FormatFile() tea.Cmd {
+	tab := ps.activeEditorTab()
+	switch {
+	case tab == nil:
+		ps.setStatus("No file to format")
+		return nil
+	case tab.scratch || !syntax.SupportsFile(tab.path):
+		ps.setStatus("Only .sg files can be formatted")
+		return nil
+	case ps.client == nil:
+		ps.setStatus("Surge client unavailable")
+		return nil
+	}
+	if tab.dirty {
+		if tab.isLossy() {
+			ps.setStatus("Save the file before formatting")
+			return nil
+		}
+		if err := tab.save(); err != nil {
+			return ps.saveFailed(err)
+		}
+	}
+	token, ok := ps.beginProjectOp("formatting " + tab.name)
+	if !ok {
+		return nil
+	}
+	return ps.underProjectOp(token, ps.runFormat(tab.path, false, false))
+}
+
+// FormatProject форматирует все исходники проекта. Открытые файлы
+// с несохранёнными правками не перезаписываются — форматирование не начинается.
+func (ps *ProjectScreenReal) FormatProject() tea.Cmd {
+	if ps.client == nil {
+		ps.setStatus("Surge client unavailable")
+		return nil
+	}
+	if !ps.isProjectDirectory(ps.projectPath) {
+		ps.setStatus("Not a surge project: surge.toml is missing")
+		return nil
+	}
+	if unsaved := ps.UnsavedTabs(); len(unsaved) > 0 {
+		ps.setStatus("Save before formatting: " + strings.Join(unsaved, ", "))
+		return nil
+	}
+	token, ok := ps.beginProjectOp("formatting the project")
+	if !ok {
+		return nil
+	}
+	return ps.underProjectOp(token, ps.runFormat(ps.projectPath, true, false))
+}
+
+// afterSave сообщает о сохранении файла; с editor.format_on_save сообщение
+// отправляется после `surge fmt`, чтобы диагностика видела отформатированный файл.
+func (ps *ProjectScreenReal) afterSave(tab *editorTab) tea.Cmd {
+	if !ps.editorCfg.FormatOnSave || ps.client == nil || !syntax.SupportsFile(tab.path) {
+		return fileSaved(tab.path)
+	}
+	return ps.runFormat(tab.path, false, true)
+}
+
+func (ps *ProjectScreenReal) runFormat(path string, project, onSave bool) tea.Cmd {
+	client := ps.client
+	if !onSave {
+		ps.setStatus("Formatting…")
+	}
+	return func() tea.Msg {
+		err := client.Format(context.Background(), path)
+		return FormatDoneMsg{path: path, project: project, onSave: onSave, err: err}
+	}
+}
+
+// handleFormatDone перечитывает затронутые вкладки. Вкладки, изменённые
+// во время форматирования, не трогаются, чтобы не потерять правки.
+func (ps *ProjectScreenReal) handleFormatDone(msg FormatDoneMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	if msg.onSave {
+		cmds = append(cmds, fileSaved(msg.path))
+	}
+	if msg.err != nil {
+		logging.Errorf("format %s: %v", msg.path, msg.err)
+		ps.setStatus(fmt.Sprintf("Format failed: %v", msg.err))
+		return tea.Batch(cmds...)
+	}
+
+	reloaded := 0
+	var skipped, failed []string
+	for _, tab := range ps.tabs {
+		if tab.scratch || !formatCovers(msg, tab.path) {
+			continue
+		}
+		if tab.dirty {
+			skipped = append(skipped, tab.name)
+			continue
+		}
+		changed, err := tab.reloadIfChanged()
+		if err != nil {
+			failed = append(failed, tab.name)
+			continue
+		}
+		if changed {
+			reloaded++
+			if !msg.project && !msg.onSave {
+				cmds = append(cmds, requestFileDiagnostics(tab.path))
+			}
+		}
+	}
+	if tab := ps.activeEditorTab(); tab != nil {
+		ps.ensureCursorVisible(tab)
+	}
+
+	switch {
+	case len(failed) > 0:
+		ps.setStatus("Formatted, but failed to reload " + strings.Join(failed, ", "))
+	case len(skipped) > 0:
+		ps.setStatus("Formatted; not reloaded (edited meanwhile): " + strings.Join(skipped, ", "))
+	case msg.onSave && reloaded == 0:
+		// остаётся статус сохранения
+	case msg.project:
+		ps.setStatus(fmt.Sprintf("Formatted project (%s reloaded)", plural(reloaded, "open file")))
+	case reloaded == 0:
+		ps.setStatus("Already formatted: " + filepath.Base(msg.path))
+	default:
+		ps.setStatus("Formatted " + filepath.Base(msg.path))
+	}
+	return tea.Batch(cmds...)
+}
+
+func formatCovers(msg FormatDoneMsg, path string) bool {
+	if !msg.project {
+		return path == msg.path
+	}
+	return strings.HasPrefix(path, msg.path+string(filepath.Separator))
+}
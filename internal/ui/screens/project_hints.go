@@ -0,0 +1,144 @@
+package screens
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/platform"
+)
+
+// maxPanelHints — сколько подсказок помещается в подвал панели в лучшем случае.
+const maxPanelHints = 6
+
+// panelHint — подсказка в подвале панели; порядок в таблице задаёт приоритет.
+type panelHint struct {
+	key     string // клавиша, которую обрабатывает сам экран
+	command string // или ID команды реестра: клавиша берётся из текущих привязок
+	label   string
+}
+
+var (
+	treeHints = []panelHint{
+		{key: "Enter", label: "open"},
+		{command: "project.new_file", label: "new file"},
+		{command: "project.new_dir", label: "new dir"},
+		{command: "project.rename", label: "rename"},
+		{command: "project.delete", label: "delete"},
+		{command: "project.copy", label: "copy"},
+		{command: "project.cut", label: "cut"},
+		{command: "project.paste", label: "paste"},
+		{command: "project.mark", label: "mark"},
+		{command: "project.toggle_hidden", label: "hidden"},
+		{command: "project.diag_legend", label: "diag filter"},
+		{command: "new_scratch", label: "scratch"},
+		{command: "toggle_problems", label: "problems"},
+		{command: "project.follow_tab", label: "follow tab"},
+	}
+	treeUnavailableHints = []panelHint{
+		{key: "r", label: "retry"},
+		{key: "→", label: "editor"},
+	}
+	editorNormalHints = []panelHint{
+		{key: "i", label: "insert"},
+		{key: "yy", label: "copy line"},
+		{key: "dd", label: "cut line"},
+		{key: "p", label: "paste"},
+		{key: "v", label: "visual"},
+		{key: "u", label: "undo"},
+		{key: ":", label: "command"},
+		{key: "]c", label: "next change"},
+		{command: "toggle_problems", label: "problems"},
+	}
+	editorInsertHints = []panelHint{
+		{key: "Esc", label: "normal"},
+		{key: "Ctrl+S", label: "save"},
+		{key: "Tab", label: "indent"},
+		{command: "new_scratch", label: "scratch"},
+	}
+	editorVisualHints = []panelHint{
+		{key: "Esc", label: "cancel"},
+		{key: ">/Tab", label: "indent"},
+		{key: "</Shift+Tab", label: "outdent"},
+		{key: "V", label: "line mode"},
+		{key: "m", label: "bookmark"},
+	}
+	editorCommandHints = []panelHint{
+		{key: "Enter", label: "run"},
+		{key: "Esc", label: "cancel"},
+		{key: ":w", label: "save"},
+		{key: ":q", label: "close tab"},
+	}
+)
+
+// SetKeyHints включает подсказки в подвалах панелей и передаёт клавиши команд
+// реестра (ID → отображаемая клавиша); вызывается заново при смене привязок.
+func (ps *ProjectScreenReal) SetKeyHints(enabled bool, commands map[string]string) {
+	ps.hintsEnabled = enabled
+	ps.commandKeys = commands
+}
+
+// treeHintsVisible сообщает, занимает ли подвал строку в панели дерева.
+func (ps *ProjectScreenReal) treeHintsVisible() bool {
+	return ps.hintsEnabled && ps.focusedPanel == FileTreePanel && !ps.problems.focused
+}
+
+// panelHints возвращает подсказки для панели с фокусом и текущего режима.
+func (ps *ProjectScreenReal) panelHints() []panelHint {
+	if ps.focusedPanel == FileTreePanel {
+		if ps.unavailable != nil {
+			return treeUnavailableHints
+		}
+		return treeHints
+	}
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return nil
+	}
+	switch tab.mode {
+	case editorModeInsert:
+		return editorInsertHints
+	case editorModeVisual:
+		return editorVisualHints
+	case editorModeCommand:
+		return editorCommandHints
+	default:
+		return editorNormalHints
+	}
+}
+
+// renderHintRow собирает строку подсказок под ширину панели; не поместившиеся
+// подсказки с низким приоритетом отбрасываются.
+func (ps *ProjectScreenReal) renderHintRow(width int) string {
+	if !ps.hintsEnabled || width <= 0 {
+		return ""
+	}
+	keyStyle := lipgloss.NewStyle().Bold(true).Foreground(ps.focus.TitleFocus)
+	labelStyle := lipgloss.NewStyle().Foreground(ps.focus.Title)
+	sep := labelStyle.Render(" • ")
+
+	var parts []string
+	used := 0
+	for _, hint := range ps.panelHints() {
+		if len(parts) == maxPanelHints {
+			break
+		}
+		key := platform.ReplacePrimaryModifier(hint.key)
+		if hint.command != "" {
+			key = ps.commandKeys[hint.command]
+			if key == "" {
+				continue // команда без привязки
+			}
+		}
+		part := keyStyle.Render(key) + " " + labelStyle.Render(hint.label)
+		extra := lipgloss.Width(part)
+		if len(parts) > 0 {
+			extra += lipgloss.Width(sep)
+		}
+		if used+extra > width {
+			break
+		}
+		parts = append(parts, part)
+		used += extra
+	}
+	return strings.Join(parts, sep)
+}
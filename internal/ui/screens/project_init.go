@@ -0,0 +1,48 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ProjectInitializedMsg — `surge init` завершился для каталога Path.
+// App перезагружает экран проекта, если это новый корень проекта.
+type ProjectInitializedMsg struct {
+	Path string
+	Err  error
+}
+
+func (ps *ProjectScreenReal) runInit(path string) tea.Cmd {
+	if ps.client == nil {
+		ps.setStatus("Surge client unavailable")
+		return nil
+	}
+	client := ps.client
+	ps.setStatus("Initializing " + filepath.Base(path) + "…")
+	return func() tea.Msg {
+		err := client.InitProject(context.Background(), path)
+		return ProjectInitializedMsg{Path: path, Err: err}
+	}
+}
+
+// handleProjectInitialized обновляет дерево, чтобы появился surge.toml:
+// выбор и раскрытые каталоги сохраняются, панель действий переключается
+// на format/build/diagnostic.
+func (ps *ProjectScreenReal) handleProjectInitialized(msg ProjectInitializedMsg) tea.Cmd {
+	if msg.Err != nil {
+		ps.setStatus(fmt.Sprintf("Init failed: %v", msg.Err))
+		return nil
+	}
+	ps.setStatus("Initialized surge project in " + filepath.Base(msg.Path))
+	if ps.fileTree == nil {
+		return ps.loadFileTree()
+	}
+	if err := ps.fileTree.Refresh(); err != nil {
+		return ps.loadFileTree()
+	}
+	ps.updateStats()
+	return ps.indexQuickOpen()
+}
@@ -0,0 +1,74 @@
+package screens
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/platform"
+)
+
+// handleKeyPress обрабатывает нажатия клавиш
+func (ps *ProjectScreenReal) handleKeyPress(msg tea.KeyMsg) (Screen, tea.Cmd) {
+	key := platform.CanonicalKeyForLookup(msg.String())
+
+	if ps.loading || ps.err != nil {
+		// В состоянии загрузки только разрешаем выход, после ошибки — повтор
+		if ps.err != nil && key == "ctrl+r" {
+			return ps, ps.loadFileTree()
+		}
+		return ps, nil
+	}
+
+	if ps.unavailable != nil {
+		// дерево устарело: доступны только повтор проверки и переключение панелей
+		switch key {
+		case "r", "ctrl+r":
+			return ps, ps.retryProjectRoot()
+		case "ctrl+right", "tab", "left", "right":
+		default:
+			return ps, nil
+		}
+	}
+
+	switch key {
+	case "ctrl+left":
+		ps.focusedPanel = FileTreePanel
+		ps.recalculateLayout()
+		return ps, nil
+	case "ctrl+right":
+		if len(ps.tabs) > 0 {
+			ps.focusedPanel = EditorPanel
+			ps.recalculateLayout()
+		}
+		return ps, nil
+	case "left", "right":
+		ps.switchPanel()
+		return ps, nil
+	case "ctrl+r":
+		return ps, ps.loadFileTree()
+	case "ctrl+d":
+		return ps, ps.confirmDeleteEntries()
+	}
+
+	// Навигация в дереве файлов
+	if ps.focusedPanel == FileTreePanel && ps.fileTree != nil {
+		switch key {
+		case "up", "k":
+			ps.fileTree.SetSelected(ps.fileTree.Selected - 1)
+			return ps, nil
+		case "down", "j":
+			ps.fileTree.SetSelected(ps.fileTree.Selected + 1)
+			return ps, nil
+		case "shift+up":
+			ps.previewTreeEntry(-1)
+			return ps, nil
+		case "shift+down":
+			ps.previewTreeEntry(1)
+			return ps, nil
+		case "space":
+			return ps, ps.toggleTreeEntry(ps.fileTree.Selected)
+		case "enter":
+			return ps, ps.openSelectedEntry()
+		}
+	}
+
+	return ps, nil
+}
@@ -0,0 +1,77 @@
+package screens
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// Раскладка экрана проекта: ширина дерева зависит от панели с фокусом и
+// может быть задана перетаскиванием границы.
+
+func (ps *ProjectScreenReal) handleResize(msg tea.WindowSizeMsg) {
+	ps.SetSize(msg.Width, msg.Height-1) // -1 для статус-бара приложения
+	ps.recalculateLayout()
+}
+
+// switchPanel переключает фокус между панелями
+func (ps *ProjectScreenReal) switchPanel() {
+	if ps.focusedPanel == FileTreePanel {
+		if len(ps.tabs) > 0 {
+			ps.focusedPanel = EditorPanel
+		}
+	} else {
+		ps.focusedPanel = FileTreePanel
+	}
+	ps.recalculateLayout()
+}
+
+func (ps *ProjectScreenReal) recalculateLayout() {
+	width := ps.Width()
+	if width <= 0 {
+		ps.treeWidth = 0
+		ps.mainWidth = 0
+		return
+	}
+
+	if len(ps.tabs) == 0 {
+		ps.treeWidth = width
+		ps.mainWidth = 0
+		return
+	}
+
+	expanded := int(float64(width) * TreeExpandedRatio)
+	if expanded < TreeMinWidth {
+		expanded = TreeMinWidth
+	}
+	if expanded > width-TreeMinWidth {
+		expanded = width - TreeMinWidth
+	}
+
+	collapsed := TreeCollapsedWidth
+	if collapsed < TreeMinWidth {
+		collapsed = TreeMinWidth
+	}
+	if collapsed > width-TreeMinWidth {
+		collapsed = max(width-TreeMinWidth, TreeMinWidth)
+	}
+
+	treeWidth := collapsed
+	if ps.focusedPanel == FileTreePanel {
+		treeWidth = expanded
+	}
+	if ps.splitWidth > 0 {
+		treeWidth = ps.splitWidth
+	}
+	if treeWidth < TreeMinWidth {
+		treeWidth = TreeMinWidth
+	}
+	if treeWidth > width-TreeMinWidth {
+		treeWidth = width - TreeMinWidth
+		if treeWidth < TreeMinWidth {
+			treeWidth = max(TreeMinWidth, width/2)
+		}
+	}
+
+	ps.treeWidth = treeWidth
+	ps.mainWidth = width - treeWidth
+	if ps.mainWidth < 0 {
+		ps.mainWidth = 0
+	}
+}
@@ -0,0 +1,73 @@
+package screens
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Сортировка, сортировка с удалением повторов и разворот выделенных строк.
+// Берутся строки целиком, даже если выделение начинается или кончается
+// посреди строки; вся операция — один шаг undo, а выделение остаётся на
+// преобразованных строках.
+
+// CanTransformLines сообщает, выделено ли в активной вкладке несколько строк.
+func (ps *ProjectScreenReal) CanTransformLines() bool {
+	tab := ps.activeEditorTab()
+	if tab == nil || !tab.hasSelection() {
+		return false
+	}
+	from, to := tab.selectedLineRange()
+	return to > from
+}
+
+// SortLines сортирует выделенные строки; unique убирает повторы.
+func (ps *ProjectScreenReal) SortLines(unique bool) tea.Cmd {
+	if unique {
+		return ps.transformSelectedLines(linesSortUnique)
+	}
+	return ps.transformSelectedLines(linesSort)
+}
+
+// ReverseLines разворачивает порядок выделенных строк.
+func (ps *ProjectScreenReal) ReverseLines() tea.Cmd {
+	return ps.transformSelectedLines(linesReverse)
+}
+
+func (ps *ProjectScreenReal) transformSelectedLines(tr lineTransform) tea.Cmd {
+	if !ps.CanTransformLines() {
+		ps.setStatus("Select several lines first (v or V)")
+		return nil
+	}
+	tab := ps.activeEditorTab()
+	from, to := tab.selectedLineRange()
+	before := tab.snapshot()
+	count, changed := tab.transformLines(from, to, tr)
+	if !changed {
+		ps.setStatus("Lines already in order")
+		return nil
+	}
+	tab.pushSnapshot(before)
+
+	// выделение остаётся на тех же строках, курсор — на своём краю
+	last := from + count - 1
+	top, bottom := cursorPosition{Line: from}, cursorPosition{Line: last, Col: max(len([]rune(tab.lines[last]))-1, 0)}
+	if tab.cursor.Line < tab.anchor.Line || (tab.cursor.Line == tab.anchor.Line && tab.cursor.Col < tab.anchor.Col) {
+		tab.anchor, tab.cursor = bottom, top
+	} else {
+		tab.anchor, tab.cursor = top, bottom
+	}
+	tab.visualLine = true
+	tab.clampCursor()
+	ps.ensureCursorVisible(tab)
+
+	switch removed := to - last; {
+	case removed > 0:
+		ps.setStatus(fmt.Sprintf("Sorted %d lines, removed %d duplicates", count, removed))
+	case tr == linesReverse:
+		ps.setStatus(fmt.Sprintf("Reversed %d lines", count))
+	default:
+		ps.setStatus(fmt.Sprintf("Sorted %d lines", count))
+	}
+	return nil
+}
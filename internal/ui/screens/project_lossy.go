@@ -0,0 +1,86 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	lossySaveOption         = "Save"
+	lossySaveKeepOption     = "Save + keep original"
+	lossySaveCancelOption   = "Cancel"
+	lossyWarningBadge       = "⚠"
+	lossyWarningDescription = "content was modified during decoding"
+)
+
+type lossySaveChoiceMsg struct {
+	path       string
+	option     string
+	closeAfter bool
+}
+
+// confirmLossySave объясняет, что будет потеряно при сохранении, и ждёт выбора.
+func (ps *ProjectScreenReal) confirmLossySave(tab *editorTab, closeAfter bool) tea.Cmd {
+	var desc strings.Builder
+	fmt.Fprintf(&desc, "Saving %s will not reproduce the original bytes:\n", tab.name)
+	for _, warning := range tab.decodeWarnings {
+		fmt.Fprintf(&desc, "  • %s\n", warning)
+	}
+	options := []string{lossySaveOption}
+	if tab.original != nil {
+		fmt.Fprintf(&desc, "\nThe original can be kept as %s%s.", tab.name, originalCopySuffix)
+		options = append(options, lossySaveKeepOption)
+	}
+	options = append(options, lossySaveCancelOption)
+
+	ps.lossyDialog.Description = strings.TrimRight(desc.String(), "\n")
+	ps.lossyDialog.Options = options
+	ch := ps.lossyDialog.Show()
+	path := tab.path
+	return func() tea.Msg {
+		choice := <-ch
+		option := ""
+		if choice >= 0 && choice < len(options) {
+			option = options[choice]
+		}
+		return lossySaveChoiceMsg{path: path, option: option, closeAfter: closeAfter}
+	}
+}
+
+func (ps *ProjectScreenReal) handleLossySaveChoice(msg lossySaveChoiceMsg) tea.Cmd {
+	index := ps.findTabIndex(msg.path)
+	if index < 0 {
+		return nil
+	}
+	tab := ps.tabs[index]
+
+	kept := ""
+	switch msg.option {
+	case lossySaveKeepOption:
+		path, err := tab.saveOriginalCopy()
+		if err != nil {
+			ps.setStatus(fmt.Sprintf("Failed to keep original: %v", err))
+			return nil
+		}
+		kept = path
+	case lossySaveOption:
+	default:
+		ps.setStatus("Save canceled")
+		return nil
+	}
+
+	if err := tab.save(); err != nil {
+		return ps.saveFailed(err)
+	}
+	if kept != "" {
+		ps.setStatus(fmt.Sprintf("Saved %s (original kept at %s)", tab.name, kept))
+	} else {
+		ps.setStatus("Saved " + tab.name)
+	}
+	if msg.closeAfter {
+		ps.forceCloseTab(index)
+	}
+	return ps.afterSave(tab)
+}
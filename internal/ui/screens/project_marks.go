@@ -0,0 +1,134 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/fs"
+)
+
+// Отметки в дереве: m отмечает выбранный элемент и переходит к следующему,
+// M снимает все отметки. Пока что-то отмечено, удаление и y/x работают со
+// всеми отмеченными элементами сразу, с одним подтверждением.
+
+// markedListLimit — сколько путей перечисляется в подтверждении удаления.
+const markedListLimit = 8
+
+// toggleTreeMark переключает отметку выбранного элемента.
+func (ps *ProjectScreenReal) toggleTreeMark() {
+	node := ps.fileTree.GetSelected()
+	if node == nil || node.Placeholder {
+		return
+	}
+	if node.Parent == nil {
+		ps.setStatus("Cannot mark the project root")
+		return
+	}
+	node.Marked = !node.Marked
+	ps.fileTree.SetSelected(ps.fileTree.Selected + 1)
+	ps.updateStats()
+}
+
+func (ps *ProjectScreenReal) clearTreeMarks() {
+	if ps.fileTree == nil {
+		return
+	}
+	if cleared := ps.fileTree.ClearMarks(); cleared > 0 {
+		ps.setStatus("Cleared " + plural(cleared, "mark"))
+	}
+	ps.updateStats()
+}
+
+// markedPaths возвращает пути отмеченных элементов без вложенных в другие
+// отмеченные каталоги: они и так уйдут вместе с каталогом.
+func (ps *ProjectScreenReal) markedPaths() []string {
+	if ps.fileTree == nil {
+		return nil
+	}
+	var paths []string
+	for _, node := range ps.fileTree.MarkedNodes() {
+		nested := false
+		for _, path := range paths {
+			if fs.IsWithin(node.Path, path) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			paths = append(paths, node.Path) // обход идёт от каталога к детям
+		}
+	}
+	return paths
+}
+
+// treeTargets — элементы для удаления и буфера: отмеченные или выбранный.
+func (ps *ProjectScreenReal) treeTargets() []string {
+	if paths := ps.markedPaths(); len(paths) > 0 {
+		return paths
+	}
+	if node := ps.fileTree.GetSelected(); node != nil && !node.Placeholder {
+		return []string{node.Path}
+	}
+	return nil
+}
+
+// confirmDeleteEntries спрашивает одно подтверждение на все удаляемые элементы.
+func (ps *ProjectScreenReal) confirmDeleteEntries() tea.Cmd {
+	paths := ps.treeTargets()
+	if len(paths) == 0 || ps.confirm == nil || ps.projectBusy() {
+		return nil
+	}
+	if len(paths) == 1 {
+		ps.confirm.Description = fmt.Sprintf("Delete %s?", filepath.Base(paths[0]))
+	} else {
+		ps.confirm.Description = fmt.Sprintf("Delete %d entries?\n%s", len(paths), ps.listPaths(paths))
+	}
+	ch := ps.confirm.Show()
+	return func() tea.Msg {
+		confirmed := <-ch
+		return deleteConfirmedMsg{confirmed: confirmed, paths: paths}
+	}
+}
+
+// listPaths перечисляет пути относительно проекта, не больше markedListLimit.
+func (ps *ProjectScreenReal) listPaths(paths []string) string {
+	var lines []string
+	for i, path := range paths {
+		if i == markedListLimit {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(paths)-i))
+			break
+		}
+		lines = append(lines, "  "+ps.relativePath(path))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (ps *ProjectScreenReal) handleDeleteConfirmed(msg deleteConfirmedMsg) tea.Cmd {
+	if !msg.confirmed {
+		return nil
+	}
+	deleted := 0
+	var firstErr error
+	for _, path := range msg.paths {
+		if err := ps.performDelete(path); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		deleted++
+	}
+	switch {
+	case firstErr != nil && len(msg.paths) == 1:
+		ps.setStatus(firstErr.Error())
+	case firstErr != nil:
+		ps.setStatus(fmt.Sprintf("Deleted %d of %d entries: %v", deleted, len(msg.paths), firstErr))
+	case deleted == 1:
+		ps.setStatus("Deleted")
+	default:
+		ps.setStatus(fmt.Sprintf("Deleted %d entries", deleted))
+	}
+	return ps.loadFileTree()
+}
@@ -0,0 +1,238 @@
+package screens
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	doubleClickInterval = 400 * time.Millisecond
+	wheelStep           = 3
+
+	// Геометрия панелей: рамка + отступ слева, строки заголовков сверху
+	panelInsetX       = 2
+	treeHeaderRows    = 4 // рамка, заголовок, фильтры, пустая строка
+	editorHeaderRows  = 2 // рамка и строка табов
+	editorGutterWidth = 7 // маркер диагностики, "%5d" и метка изменения
+)
+
+// handleMouse обрабатывает клики, перетаскивание и колесо мыши.
+func (ps *ProjectScreenReal) handleMouse(msg tea.MouseMsg) (Screen, tea.Cmd) {
+	if ps.loading || ps.err != nil || ps.fileTree == nil || ps.dialogVisible() {
+		return ps, nil
+	}
+
+	if ps.splitDragging {
+		return ps, ps.handleSplitMouse(msg)
+	}
+	// Перетаскивание продолжается, даже если курсор вышел за панель редактора
+	if ps.dragging {
+		return ps, ps.handleEditorMouse(msg)
+	}
+	if msg.Y >= ps.panelHeight() {
+		return ps, ps.handleProblemsMouse(msg)
+	}
+	if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+		ps.problems.focused = false
+	}
+	if ps.onSplitBorder(msg) {
+		return ps, ps.handleSplitMouse(msg)
+	}
+
+	treeOuter := ps.treeWidth + 2
+	if ps.mainWidth > 0 && msg.X >= treeOuter {
+		return ps, ps.handleEditorMouse(msg)
+	}
+	return ps, ps.handleTreeMouse(msg)
+}
+
+// onSplitBorder — нажатие на правую рамку дерева или левую рамку редактора.
+func (ps *ProjectScreenReal) onSplitBorder(msg tea.MouseMsg) bool {
+	if ps.mainWidth <= 0 || msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return false
+	}
+	border := ps.treeWidth + 1
+	return (msg.X == border || msg.X == border+1) && msg.Y < ps.panelHeight()
+}
+
+// handleSplitMouse меняет ширину дерева, пока граница перетаскивается.
+// Двойной клик по границе возвращает ширину по фокусу.
+func (ps *ProjectScreenReal) handleSplitMouse(msg tea.MouseMsg) tea.Cmd {
+	switch msg.Action {
+	case tea.MouseActionPress:
+		now := time.Now()
+		if now.Sub(ps.lastSplitClickAt) <= doubleClickInterval {
+			ps.lastSplitClickAt = time.Time{}
+			ps.splitWidth = 0
+			ps.recalculateLayout()
+			return nil
+		}
+		ps.lastSplitClickAt = now
+		ps.splitDragging = true
+	case tea.MouseActionMotion:
+		// рамка дерева идёт сразу за его шириной
+		ps.splitWidth = clampInt(msg.X-1, TreeMinWidth, max(ps.Width()-TreeMinWidth, TreeMinWidth))
+		ps.recalculateLayout()
+		if tab := ps.activeEditorTab(); tab != nil {
+			ps.ensureCursorVisible(tab)
+		}
+	case tea.MouseActionRelease:
+		ps.splitDragging = false
+	}
+	return nil
+}
+
+func (ps *ProjectScreenReal) handleTreeMouse(msg tea.MouseMsg) tea.Cmd {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		ps.fileTree.SetSelected(max(ps.fileTree.Selected-wheelStep, 0))
+		return nil
+	case tea.MouseButtonWheelDown:
+		ps.fileTree.SetSelected(min(ps.fileTree.Selected+wheelStep, len(ps.fileTree.FlatList)-1))
+		return nil
+	}
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return nil
+	}
+
+	if ps.focusedPanel != FileTreePanel {
+		ps.focusedPanel = FileTreePanel
+		ps.recalculateLayout()
+	}
+
+	start, end := ps.treeWindow()
+	index := start + msg.Y - treeHeaderRows
+	if msg.Y < treeHeaderRows || index >= end {
+		return nil
+	}
+
+	now := time.Now()
+	double := index == ps.lastClickIndex && now.Sub(ps.lastClickAt) <= doubleClickInterval
+	ps.lastClickIndex = index
+	ps.lastClickAt = now
+
+	ps.fileTree.SetSelected(index)
+	if double {
+		ps.lastClickAt = time.Time{}
+		return ps.openSelectedEntry()
+	}
+	return nil
+}
+
+func (ps *ProjectScreenReal) handleEditorMouse(msg tea.MouseMsg) tea.Cmd {
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		ps.scrollEditor(tab, -wheelStep)
+		return nil
+	case tea.MouseButtonWheelDown:
+		ps.scrollEditor(tab, wheelStep)
+		return nil
+	}
+
+	originX := ps.treeWidth + 2 + panelInsetX
+	switch msg.Action {
+	case tea.MouseActionPress:
+		if msg.Button != tea.MouseButtonLeft {
+			return nil
+		}
+		if ps.focusedPanel != EditorPanel {
+			ps.focusedPanel = EditorPanel
+			ps.recalculateLayout()
+			originX = ps.treeWidth + 2 + panelInsetX
+		}
+		if msg.Y == editorHeaderRows-1 {
+			ps.clickTabBar(msg.X - originX)
+			return nil
+		}
+		pos, ok := ps.editorPositionAt(tab, msg.X-originX, msg.Y)
+		if !ok {
+			return nil
+		}
+		if tab.mode == editorModeCommand || tab.mode == editorModeVisual {
+			ps.handleEditorEscape()
+		}
+		now := time.Now()
+		if pos == ps.lastEditorPos && now.Sub(ps.lastEditorClick) <= doubleClickInterval {
+			ps.lastEditorClick = time.Time{}
+			if tab.mode == editorModeInsert {
+				ps.handleEditorEscape()
+			}
+			if tab.selectWordAt(pos, ps.editorCfg.WordChars) {
+				ps.setStatus(visualModeLabel(tab))
+				return nil
+			}
+		}
+		ps.lastEditorClick, ps.lastEditorPos = now, pos
+		tab.cursor = pos
+		tab.clampCursor()
+		ps.dragging = true
+		ps.dragAnchor = tab.cursor
+	case tea.MouseActionMotion:
+		if !ps.dragging {
+			return nil
+		}
+		pos, _ := ps.editorPositionAt(tab, msg.X-originX, msg.Y)
+		if tab.mode != editorModeVisual {
+			if pos == ps.dragAnchor {
+				return nil
+			}
+			if tab.mode == editorModeInsert {
+				ps.handleEditorEscape()
+			}
+			tab.cursor = ps.dragAnchor
+			ps.enterVisualMode(tab, false)
+		}
+		tab.cursor = pos
+		tab.clampCursor()
+		ps.ensureCursorVisible(tab)
+	case tea.MouseActionRelease:
+		ps.dragging = false
+	}
+	return nil
+}
+
+// editorPositionAt переводит координаты внутри панели редактора в позицию буфера.
+// ok=false, если точка вне области текста; позиция всё равно ограничивается буфером.
+func (ps *ProjectScreenReal) editorPositionAt(tab *editorTab, x, y int) (cursorPosition, bool) {
+	row := y - editorHeaderRows
+	height := ps.editorContentHeight()
+	ok := row >= 0 && row < height && x >= 0
+
+	line := clampInt(tab.scroll+row, 0, tab.lineCount()-1)
+	col := max(x-editorGutterWidth, 0)
+	return cursorPosition{Line: line, Col: col}, ok
+}
+
+// clickTabBar активирует вкладку под курсором мыши. x отсчитывается от начала строки табов.
+func (ps *ProjectScreenReal) clickTabBar(x int) {
+	width := max(max(ps.mainWidth, 20)-2, 10)
+	offset := 0
+	for i, tab := range ps.tabs {
+		w := lipgloss.Width(ps.tabTitle(tab, width)) + 2 // горизонтальный padding
+		if x >= offset && x < offset+w {
+			ps.setActiveTab(i)
+			return
+		}
+		offset += w + 1 // разделитель
+	}
+}
+
+// scrollEditor прокручивает буфер, удерживая курсор в видимой области.
+func (ps *ProjectScreenReal) scrollEditor(tab *editorTab, delta int) {
+	height := ps.editorContentHeight()
+	maxScroll := max(tab.lineCount()-height, 0)
+	tab.scroll = clampInt(tab.scroll+delta, 0, maxScroll)
+	if tab.cursor.Line < tab.scroll {
+		tab.cursor.Line = tab.scroll
+	} else if tab.cursor.Line >= tab.scroll+height {
+		tab.cursor.Line = tab.scroll + height - 1
+	}
+	tab.clampCursor()
+}
@@ -0,0 +1,53 @@
+package screens
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/syntax"
+)
+
+// ]f / [f переходят к следующему/предыдущему объявлению функции или типа
+// в .sg-файле и ставят его строку в середину экрана. В остальных буферах
+// клавиши ничего не делают и только объясняют почему.
+
+// CanNavigateSymbols сообщает, есть ли активная вкладка для ]f / [f.
+func (ps *ProjectScreenReal) CanNavigateSymbols() bool {
+	return ps.activeEditorTab() != nil
+}
+
+// JumpToSymbol переводит курсор к следующему (dir > 0) или предыдущему
+// объявлению, переходя через конец файла по кругу.
+func (ps *ProjectScreenReal) JumpToSymbol(dir int) tea.Cmd {
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return nil
+	}
+	if !syntax.SupportsFile(tab.name) {
+		ps.setStatus("Function navigation is available in .sg files only")
+		return nil
+	}
+	sym, wrapped, ok := tab.symbolFrom(tab.cursor.Line, dir)
+	if !ok {
+		ps.setStatus("No functions or types in " + tab.name)
+		return nil
+	}
+	tab.cursor = cursorPosition{Line: sym.Line, Col: sym.Col}
+	tab.clampCursor()
+	ps.centerCursorLine(tab)
+	status := fmt.Sprintf("%s %s (line %d)", sym.Kind, sym.Name, sym.Line+1)
+	if wrapped && dir > 0 {
+		status = "Wrapped to top: " + status
+	} else if wrapped {
+		status = "Wrapped to bottom: " + status
+	}
+	ps.setStatus(status)
+	return nil
+}
+
+// centerCursorLine прокручивает вкладку так, чтобы строка курсора оказалась
+// посередине области текста.
+func (ps *ProjectScreenReal) centerCursorLine(tab *editorTab) {
+	height := ps.editorContentHeight()
+	tab.scroll = clampInt(tab.cursor.Line-height/2, 0, max(tab.lineCount()-height, 0))
+}
@@ -0,0 +1,113 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+)
+
+// Пункты перехода для палитры команд: открытые вкладки, закладки и недавние
+// файлы. Собираются заново при каждом открытии палитры.
+
+const paletteSnippetWidth = 60 // столько символов строки закладки видно в палитре
+
+// PaletteEntries возвращает пункты перехода экрана проекта по группам:
+// вкладки, закладки, недавние файлы, которые сейчас не открыты.
+func (ps *ProjectScreenReal) PaletteEntries() []CommandEntry {
+	var entries []CommandEntry
+	for _, tab := range ps.tabs {
+		entries = append(entries, CommandEntry{
+			ID:      "tab:" + tab.path,
+			Title:   "tab: " + tab.name,
+			Context: ps.paletteContext(tab.path),
+			Group:   "tab",
+			Enabled: true,
+			Run:     ps.showTabCmd(tab),
+		})
+	}
+	for _, tab := range ps.tabs {
+		for _, b := range tab.bookmarks {
+			entries = append(entries, CommandEntry{
+				ID:      fmt.Sprintf("mark:%s:%d", tab.path, b.Line),
+				Title:   fmt.Sprintf("mark: %s:%s", tab.name, b.label()),
+				Context: bookmarkSnippet(tab, b),
+				Group:   "mark",
+				Enabled: true,
+				Run:     ps.showBookmarkCmd(tab, b),
+			})
+		}
+	}
+	if ps.projectPath == "" {
+		return entries
+	}
+	recent, _ := config.LoadRecentFiles(ps.projectPath)
+	for _, path := range recent {
+		if ps.findTabIndex(path) >= 0 {
+			continue
+		}
+		entries = append(entries, CommandEntry{
+			ID:      "recent:" + path,
+			Title:   "recent: " + filepath.Base(path),
+			Context: ps.paletteContext(path),
+			Group:   "recent",
+			Enabled: true,
+			Run:     ps.openFileCmd(path),
+		})
+	}
+	return entries
+}
+
+// paletteContext — каталог файла относительно проекта для подписи пункта.
+func (ps *ProjectScreenReal) paletteContext(path string) string {
+	if path == "" {
+		return "unsaved buffer"
+	}
+	dir := filepath.Dir(ps.relativePath(path))
+	if dir == "." {
+		return "project root"
+	}
+	return dir
+}
+
+// bookmarkSnippet — начало первой строки закладки без отступа.
+func bookmarkSnippet(tab *editorTab, b editorBookmark) string {
+	if b.Line >= len(tab.lines) {
+		return ""
+	}
+	runes := []rune(strings.TrimSpace(tab.lines[b.Line]))
+	if len(runes) > paletteSnippetWidth {
+		return string(runes[:paletteSnippetWidth-1]) + "…"
+	}
+	return string(runes)
+}
+
+func (ps *ProjectScreenReal) showTabCmd(tab *editorTab) func() tea.Cmd {
+	return func() tea.Cmd {
+		index := ps.tabIndexOf(tab)
+		if index < 0 {
+			ps.setStatus("Tab is closed: " + tab.name)
+			return nil
+		}
+		ps.setActiveTab(index)
+		ps.focusedPanel = EditorPanel
+		ps.recalculateLayout()
+		return nil
+	}
+}
+
+func (ps *ProjectScreenReal) showBookmarkCmd(tab *editorTab, b editorBookmark) func() tea.Cmd {
+	return func() tea.Cmd {
+		ps.showBookmark(tab, b)
+		return nil
+	}
+}
+
+func (ps *ProjectScreenReal) openFileCmd(path string) func() tea.Cmd {
+	return func() tea.Cmd {
+		ps.openFileTab(path)
+		return nil
+	}
+}
@@ -0,0 +1,411 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+	"surge-tui/internal/fs"
+)
+
+// Буфер дерева: y копирует выбранный элемент (или все отмеченные), x
+// вырезает, p вставляет в выбранный каталог (или в каталог выбранного файла),
+// D дублирует рядом. Вставка идёт в фоне по одному элементу; для больших
+// каталогов в статусе виден ход копирования. Вкладки перенесённых файлов
+// переходят на новый путь.
+
+const (
+	pasteOverwriteOption = "Overwrite"
+	pasteRenameOption    = "Keep both"
+	pasteSkipOption      = "Skip"
+
+	pasteProgressMin      = 50 // с какого числа файлов показывать ход копирования
+	pasteProgressInterval = 100 * time.Millisecond
+)
+
+// treeClip — элементы в буфере дерева.
+type treeClip struct {
+	paths []string
+	cut   bool
+}
+
+// pasteJob — одна операция вставки: src копируется или переносится в dst.
+type pasteJob struct {
+	src       string
+	dst       string
+	cut       bool
+	overwrite bool // dst существует и заменяется
+	duplicate bool
+}
+
+// pasteBatch — вставка всех элементов буфера; элементы идут по очереди,
+// чтобы конфликт имён каждого решался отдельным вопросом.
+type pasteBatch struct {
+	queue   []pasteJob
+	total   int
+	done    int
+	dir     string
+	cut     bool
+	problem string // первая ошибка или пропуск
+}
+
+type pasteChoiceMsg struct {
+	job    pasteJob
+	option string
+}
+
+type pasteProgressMsg struct {
+	events <-chan tea.Msg
+	job    pasteJob
+	done   int
+	total  int
+}
+
+type pasteDoneMsg struct {
+	job pasteJob
+	err error
+}
+
+// yankTreeEntry кладёт в буфер отмеченные элементы или выбранный.
+// Отметки снимаются: дальше с элементами работает буфер.
+func (ps *ProjectScreenReal) yankTreeEntry(cut bool) {
+	paths := ps.treeTargets()
+	if len(paths) == 0 {
+		return
+	}
+	if paths[0] == ps.fileTree.Root.Path {
+		ps.setStatus("Cannot copy the project root")
+		return
+	}
+	ps.treeClip = &treeClip{paths: paths, cut: cut}
+	ps.fileTree.ClearMarks()
+	ps.updateStats()
+
+	what := filepath.Base(paths[0])
+	if len(paths) > 1 {
+		what = fmt.Sprintf("%d entries", len(paths))
+	}
+	if cut {
+		ps.setStatus(fmt.Sprintf("Cut %s — p to move into the selected directory", what))
+	} else {
+		ps.setStatus(fmt.Sprintf("Copied %s — p to paste into the selected directory", what))
+	}
+}
+
+// pasteTreeEntry вставляет элементы из буфера в выбранный каталог.
+func (ps *ProjectScreenReal) pasteTreeEntry() tea.Cmd {
+	if ps.treeClip == nil {
+		ps.setStatus("Nothing to paste: y copies, x cuts the selected entry")
+		return nil
+	}
+	if ps.paste != nil {
+		ps.setStatus("Another paste is still running")
+		return nil
+	}
+	if ps.projectBusy() {
+		return nil
+	}
+	dir := ps.selectedDirPath()
+	batch := &pasteBatch{total: len(ps.treeClip.paths), dir: dir, cut: ps.treeClip.cut}
+	for _, src := range ps.treeClip.paths {
+		job, problem := planPaste(src, dir, ps.treeClip.cut)
+		if problem != "" {
+			if batch.problem == "" {
+				batch.problem = problem
+			}
+			continue
+		}
+		batch.queue = append(batch.queue, job)
+	}
+	if len(batch.queue) == 0 {
+		if _, err := os.Lstat(ps.treeClip.paths[0]); err != nil && batch.total == 1 {
+			ps.treeClip = nil
+		}
+		ps.setStatus(batch.problem)
+		return nil
+	}
+	ps.paste = batch
+	return ps.nextPaste()
+}
+
+// planPaste строит операцию для одного элемента буфера или объясняет,
+// почему вставить его нельзя.
+func planPaste(src, dir string, cut bool) (pasteJob, string) {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return pasteJob{}, fmt.Sprintf("%s no longer exists", filepath.Base(src))
+	}
+	if info.IsDir() && fs.IsWithin(dir, src) {
+		if cut || dir != src {
+			return pasteJob{}, "Cannot paste a directory into itself"
+		}
+		dir = filepath.Dir(src) // копия каталога, выбранного самим собой, — рядом
+	}
+	job := pasteJob{src: src, dst: filepath.Join(dir, filepath.Base(src)), cut: cut}
+	if job.dst == src {
+		if cut {
+			return pasteJob{}, fmt.Sprintf("%s is already here", filepath.Base(src))
+		}
+		job.duplicate = true
+	}
+	return job, ""
+}
+
+// nextPaste запускает следующий элемент очереди; имя копии и конфликт
+// проверяются только сейчас — предыдущие элементы могли занять имя.
+func (ps *ProjectScreenReal) nextPaste() tea.Cmd {
+	batch := ps.paste
+	if batch == nil {
+		return nil
+	}
+	if len(batch.queue) == 0 {
+		ps.paste = nil
+		ps.finishPasteBatch(batch)
+		return nil
+	}
+	job := batch.queue[0]
+	batch.queue = batch.queue[1:]
+	if job.duplicate {
+		job.dst = ps.freePath(filepath.Dir(job.src), job.src)
+		return ps.startPaste(job)
+	}
+	if _, err := os.Lstat(job.dst); err == nil {
+		return ps.confirmPasteCollision(job)
+	}
+	return ps.startPaste(job)
+}
+
+// finishPasteBatch подводит итог вставки нескольких элементов; итог
+// одного элемента уже в статусе.
+func (ps *ProjectScreenReal) finishPasteBatch(batch *pasteBatch) {
+	if batch.total == 1 {
+		return
+	}
+	verb, prep := "Pasted", "into"
+	if batch.cut {
+		verb, prep = "Moved", "to"
+	}
+	summary := fmt.Sprintf("%s %d entries %s %s", verb, batch.done, prep, filepath.Base(batch.dir))
+	if batch.done < batch.total {
+		summary = fmt.Sprintf("%s %d of %d entries %s %s", verb, batch.done, batch.total, prep, filepath.Base(batch.dir))
+		if batch.problem != "" {
+			summary += ": " + batch.problem
+		}
+	}
+	ps.setStatus(summary)
+}
+
+// duplicateTreeEntry копирует выбранный элемент рядом под свободным именем.
+func (ps *ProjectScreenReal) duplicateTreeEntry() tea.Cmd {
+	node := ps.fileTree.GetSelected()
+	if node == nil || node.Parent == nil {
+		return nil
+	}
+	if ps.paste != nil {
+		ps.setStatus("Another paste is still running")
+		return nil
+	}
+	if ps.projectBusy() {
+		return nil
+	}
+	ps.paste = &pasteBatch{total: 1, dir: filepath.Dir(node.Path)}
+	dir := filepath.Dir(node.Path)
+	return ps.startPaste(pasteJob{src: node.Path, dst: ps.freePath(dir, node.Path), duplicate: true})
+}
+
+func (ps *ProjectScreenReal) freePath(dir, src string) string {
+	return filepath.Join(dir, fs.FreeName(dir, filepath.Base(src)))
+}
+
+// confirmPasteCollision спрашивает, что делать с существующим элементом.
+// Заменить каталог, внутри которого лежит сам источник, нельзя.
+func (ps *ProjectScreenReal) confirmPasteCollision(job pasteJob) tea.Cmd {
+	options := []string{pasteOverwriteOption, pasteRenameOption, pasteSkipOption}
+	if fs.IsWithin(job.src, job.dst) {
+		options = options[1:]
+	}
+	ps.pasteDialog.Description = fmt.Sprintf("%s already exists in %s.", filepath.Base(job.dst), filepath.Base(filepath.Dir(job.dst)))
+	ps.pasteDialog.Options = options
+	ch := ps.pasteDialog.Show()
+	return func() tea.Msg {
+		choice := <-ch
+		option := pasteSkipOption
+		if choice >= 0 && choice < len(options) {
+			option = options[choice]
+		}
+		return pasteChoiceMsg{job: job, option: option}
+	}
+}
+
+func (ps *ProjectScreenReal) handlePasteChoice(msg pasteChoiceMsg) tea.Cmd {
+	job := msg.job
+	switch msg.option {
+	case pasteOverwriteOption:
+		job.overwrite = true
+	case pasteRenameOption:
+		job.dst = ps.freePath(filepath.Dir(job.dst), job.src)
+	default:
+		ps.setStatus(fmt.Sprintf("Skipped %s", filepath.Base(job.src)))
+		if ps.paste != nil && ps.paste.problem == "" {
+			ps.paste.problem = fmt.Sprintf("skipped %s", filepath.Base(job.src))
+		}
+		return ps.nextPaste()
+	}
+	return ps.startPaste(job)
+}
+
+// startPaste выполняет операцию в фоне; события приходят через канал.
+func (ps *ProjectScreenReal) startPaste(job pasteJob) tea.Cmd {
+	events := make(chan tea.Msg, 1)
+	go func() {
+		var last time.Time
+		progress := func(done, total int) {
+			if total < pasteProgressMin || time.Since(last) < pasteProgressInterval {
+				return
+			}
+			last = time.Now()
+			select {
+			case events <- pasteProgressMsg{events: events, job: job, done: done, total: total}:
+			default: // UI ещё не забрал прошлое значение
+			}
+		}
+		events <- pasteDoneMsg{job: job, err: runPaste(job, progress)}
+	}()
+	return waitPaste(events)
+}
+
+func runPaste(job pasteJob, progress fs.CopyProgress) error {
+	if !job.overwrite {
+		return pasteEntry(job, job.dst, progress)
+	}
+	// замена собирается рядом с dst, а старый dst уходит только после удачи:
+	// прерванное копирование не оставляет пользователя без обеих версий
+	stage, err := os.MkdirTemp(filepath.Dir(job.dst), ".surge-tui-paste-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stage)
+	staged := filepath.Join(stage, "new")
+	if err := pasteEntry(job, staged, progress); err != nil {
+		return err
+	}
+	if err := swapEntry(staged, job.dst, filepath.Join(stage, "old")); err != nil {
+		if job.cut {
+			// вырезанный элемент возвращается на место, а не пропадает со stage
+			_ = fs.MoveEntry(staged, job.src, nil)
+		}
+		return err
+	}
+	return nil
+}
+
+func pasteEntry(job pasteJob, dst string, progress fs.CopyProgress) error {
+	if job.cut {
+		return fs.MoveEntry(job.src, dst, progress)
+	}
+	return fs.CopyEntry(job.src, dst, progress)
+}
+
+// swapEntry ставит staged на место dst. Прежний dst отодвигается в aside и
+// возвращается обратно, если подмена не удалась.
+func swapEntry(staged, dst, aside string) error {
+	if err := os.Rename(dst, aside); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Rename(staged, dst); err != nil {
+		_ = os.Rename(aside, dst)
+		return err
+	}
+	return nil
+}
+
+func waitPaste(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+func (ps *ProjectScreenReal) handlePasteProgress(msg pasteProgressMsg) tea.Cmd {
+	verb := "Copying"
+	if msg.job.cut {
+		verb = "Moving"
+	}
+	ps.setStatus(fmt.Sprintf("%s %s: %d/%d files", verb, filepath.Base(msg.job.src), msg.done, msg.total))
+	return waitPaste(msg.events)
+}
+
+func (ps *ProjectScreenReal) handlePasteDone(msg pasteDoneMsg) tea.Cmd {
+	job := msg.job
+	if msg.err != nil {
+		ps.setStatus(fmt.Sprintf("Paste failed: %v", msg.err))
+		if ps.paste != nil && ps.paste.problem == "" {
+			ps.paste.problem = msg.err.Error()
+		}
+	} else {
+		if ps.paste != nil {
+			ps.paste.done++
+		}
+		name, dir := filepath.Base(job.dst), filepath.Base(filepath.Dir(job.dst))
+		switch {
+		case job.cut:
+			ps.dropFromClip(job.src)
+			ps.retargetTabs(job.src, job.dst)
+			ps.setStatus(fmt.Sprintf("Moved %s to %s", name, dir))
+		case job.duplicate:
+			ps.setStatus("Duplicated as " + name)
+		default:
+			ps.setStatus(fmt.Sprintf("Pasted %s into %s", name, dir))
+		}
+	}
+	next := ps.nextPaste()
+	if ps.fileTree == nil {
+		return next
+	}
+	return tea.Batch(ps.readTreeDirs([]string{filepath.Dir(job.dst), filepath.Dir(job.src)}), next)
+}
+
+// dropFromClip убирает перенесённый элемент из буфера вырезания.
+func (ps *ProjectScreenReal) dropFromClip(path string) {
+	if ps.treeClip == nil {
+		return
+	}
+	paths := ps.treeClip.paths[:0]
+	for _, clipped := range ps.treeClip.paths {
+		if clipped != path {
+			paths = append(paths, clipped)
+		}
+	}
+	ps.treeClip.paths = paths
+	if len(paths) == 0 {
+		ps.treeClip = nil
+	}
+}
+
+// retargetTabs переводит вкладки файлов из oldPath (файла или каталога) на newPath.
+func (ps *ProjectScreenReal) retargetTabs(oldPath, newPath string) {
+	for _, tab := range ps.tabs {
+		if tab.scratch || !fs.IsWithin(tab.path, oldPath) {
+			continue
+		}
+		rel, err := filepath.Rel(oldPath, tab.path)
+		if err != nil {
+			continue
+		}
+		if !tab.autosavedAt.IsZero() {
+			// копия лежит под старым путём; следующий цикл запишет её заново
+			_ = config.RemoveAutosave(tab.path)
+			tab.autosavedAt = time.Time{}
+			tab.autosaveEdits--
+		}
+		tab.path = filepath.Join(newPath, rel)
+		tab.name = filepath.Base(tab.path)
+		tab.invalidateHighlight(0, len(tab.lines)-1) // подсветка выбирается по расширению
+		tab.setDiagnostics(diagnosticsForPath(ps.diagnostics, tab.path))
+		if info, err := os.Stat(tab.path); err == nil && !tab.savedAt.IsZero() {
+			tab.savedAt = info.ModTime() // содержимое на диске не менялось
+		}
+	}
+}
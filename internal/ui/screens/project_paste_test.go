@@ -0,0 +1,85 @@
+package screens
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// pasteDirs создаёт src и существующий dst с файлом keep.
+func pasteDirs(t *testing.T) (src, dst string) {
+	t.Helper()
+	base := t.TempDir()
+	src = filepath.Join(base, "from", "pkg")
+	dst = filepath.Join(base, "to", "pkg")
+	for _, dir := range []string{src, dst} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(src, "new.sg"), []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dst, "keep.sg"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return src, dst
+}
+
+// assertNoStage проверяет, что рядом с dst не осталось временных каталогов.
+func assertNoStage(t *testing.T, dst string) {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Dir(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".surge-tui-paste-") {
+			t.Errorf("stage %s left behind", e.Name())
+		}
+	}
+}
+
+func TestRunPasteOverwriteReplaces(t *testing.T) {
+	for _, cut := range []bool{false, true} {
+		src, dst := pasteDirs(t)
+		if err := runPaste(pasteJob{src: src, dst: dst, cut: cut, overwrite: true}, nil); err != nil {
+			t.Fatalf("cut=%v: %v", cut, err)
+		}
+		if _, err := os.Stat(filepath.Join(dst, "keep.sg")); !os.IsNotExist(err) {
+			t.Errorf("cut=%v: old content survived the overwrite", cut)
+		}
+		if data, _ := os.ReadFile(filepath.Join(dst, "new.sg")); string(data) != "new" {
+			t.Errorf("cut=%v: new.sg = %q", cut, data)
+		}
+		_, err := os.Stat(src)
+		if gone := os.IsNotExist(err); gone != cut {
+			t.Errorf("cut=%v: source removed = %v", cut, gone)
+		}
+		assertNoStage(t, dst)
+	}
+}
+
+// Прерванная замена оставляет прежний dst нетронутым.
+func TestRunPasteOverwriteFailureKeepsDestination(t *testing.T) {
+	src, dst := pasteDirs(t)
+	// сокет не открыть на чтение, и копирование обрывается на середине
+	l, err := net.Listen("unix", filepath.Join(src, "sock"))
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer l.Close()
+
+	if err := runPaste(pasteJob{src: src, dst: dst, overwrite: true}, nil); err == nil {
+		t.Fatal("runPaste succeeded on an unreadable entry")
+	}
+	if data, err := os.ReadFile(filepath.Join(dst, "keep.sg")); err != nil || string(data) != "old" {
+		t.Errorf("destination lost: keep.sg = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "new.sg")); !os.IsNotExist(err) {
+		t.Errorf("partial copy leaked into the destination")
+	}
+	assertNoStage(t, dst)
+}
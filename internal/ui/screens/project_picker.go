@@ -0,0 +1,364 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/config"
+	"surge-tui/internal/fs"
+	"surge-tui/internal/platform"
+	"surge-tui/internal/ui/styles"
+)
+
+// ProjectChosenMsg — в пикере выбран каталог проекта.
+type ProjectChosenMsg struct {
+	Path string
+}
+
+// ProjectPickerClosedMsg — пикер закрыт без выбора.
+type ProjectPickerClosedMsg struct{}
+
+// ProjectPickerScreen выбирает каталог проекта: сверху недавние проекты,
+// ниже дерево каталогов вокруг текущего проекта.
+type ProjectPickerScreen struct {
+	BaseScreen
+
+	projectPath string
+	recent      []string
+	tree        *fs.FileTree
+	showHidden  bool
+	selected    int // индекс в общем списке: сначала недавние, затем дерево
+	startRoot   string
+	visited     map[string]string // корень дерева → выбранный в нём каталог
+	status      statusQueue
+	icons       styles.IconSet
+}
+
+// NewProjectPickerScreen создает пикер для текущего проекта.
+func NewProjectPickerScreen(projectPath string) *ProjectPickerScreen {
+	return &ProjectPickerScreen{
+		BaseScreen:  NewBaseScreen("Open Project"),
+		projectPath: projectPath,
+		icons:       styles.DefaultIcons(),
+	}
+}
+
+// SetIcons задаёт значки каталогов (ui.icons, ui.file_icons).
+func (pp *ProjectPickerScreen) SetIcons(icons styles.IconSet) {
+	pp.icons = icons
+}
+
+// SetProjectPath задаёт текущий проект; он не попадает в недавние.
+func (pp *ProjectPickerScreen) SetProjectPath(path string) {
+	pp.projectPath = path
+}
+
+func (pp *ProjectPickerScreen) Init() tea.Cmd {
+	return nil
+}
+
+// OnEnter перечитывает недавние проекты и открывает дерево у родителя
+// текущего проекта.
+func (pp *ProjectPickerScreen) OnEnter() tea.Cmd {
+	pp.setStatus("", false)
+	pp.recent = pp.recent[:0]
+	recent, err := config.LoadRecentProjects()
+	if err != nil {
+		pp.setStatus("Recent projects: "+err.Error(), true)
+	}
+	for _, path := range recent {
+		if path != pp.projectPath {
+			pp.recent = append(pp.recent, path)
+		}
+	}
+
+	root := filepath.Dir(pp.projectPath)
+	if pp.projectPath == "" {
+		root, _ = os.UserHomeDir()
+	}
+	pp.tree = nil
+	pp.startRoot = root
+	pp.visited = make(map[string]string)
+	pp.browse(root, pp.projectPath)
+	if len(pp.recent) > 0 {
+		pp.selected = 0
+	}
+	return nil
+}
+
+// browse показывает дерево с корнем root и ставит курсор на focus.
+func (pp *ProjectPickerScreen) browse(root, focus string) {
+	tree, err := fs.NewDirTree(root)
+	if err != nil {
+		pp.setStatus(err.Error(), true)
+		return
+	}
+	if node := pp.selectedNode(); node != nil {
+		pp.visited[pp.tree.Root.Path] = node.Path
+	}
+	if pp.showHidden {
+		_ = tree.SetShowHidden(true)
+	}
+	pp.tree = tree
+	pp.selected = len(pp.recent)
+	for i, node := range tree.FlatList {
+		if node.Path == focus {
+			pp.selected = len(pp.recent) + i
+			break
+		}
+	}
+}
+
+func (pp *ProjectPickerScreen) rowCount() int {
+	count := len(pp.recent)
+	if pp.tree != nil {
+		count += len(pp.tree.FlatList)
+	}
+	return count
+}
+
+// selectedNode возвращает выбранный узел дерева или nil, если курсор на недавнем проекте.
+func (pp *ProjectPickerScreen) selectedNode() *fs.FileNode {
+	if pp.tree == nil || pp.selected < len(pp.recent) {
+		return nil
+	}
+	pp.tree.SetSelected(pp.selected - len(pp.recent))
+	return pp.tree.GetSelected()
+}
+
+func (pp *ProjectPickerScreen) selectedPath() string {
+	if pp.selected >= 0 && pp.selected < len(pp.recent) {
+		return pp.recent[pp.selected]
+	}
+	if node := pp.selectedNode(); node != nil {
+		return node.Path
+	}
+	return ""
+}
+
+func (pp *ProjectPickerScreen) setStatus(text string, isErr bool) {
+	severity := statusInfo
+	if isErr {
+		severity = statusError
+	}
+	pp.status.push(text, severity)
+}
+
+// StatusHistory возвращает последние сообщения строки статуса, новые первыми.
+func (pp *ProjectPickerScreen) StatusHistory() []string {
+	return pp.status.recent()
+}
+
+func (pp *ProjectPickerScreen) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	screen, cmd := pp.update(msg)
+	return screen, tea.Batch(cmd, pp.status.schedule())
+}
+
+func (pp *ProjectPickerScreen) update(msg tea.Msg) (Screen, tea.Cmd) {
+	switch m := msg.(type) {
+	case tea.WindowSizeMsg:
+		pp.SetSize(m.Width, m.Height-1)
+	case tea.KeyMsg:
+		return pp, pp.handleKey(m)
+	case tea.MouseMsg:
+		pp.handleMouse(m)
+	}
+	return pp, nil
+}
+
+func (pp *ProjectPickerScreen) handleKey(msg tea.KeyMsg) tea.Cmd {
+	pp.setStatus("", false)
+	count := pp.rowCount()
+	switch platform.CanonicalKeyForLookup(msg.String()) {
+	case "up", "k":
+		pp.selected = max(pp.selected-1, 0)
+	case "down", "j":
+		pp.selected = min(pp.selected+1, count-1)
+	case "pgup":
+		pp.selected = max(pp.selected-pp.listHeight(), 0)
+	case "pgdown":
+		pp.selected = min(pp.selected+pp.listHeight(), count-1)
+	case "home", "g":
+		pp.selected = 0
+	case "end", "G":
+		pp.selected = max(count-1, 0)
+	case "enter":
+		return pp.choose(pp.selectedPath())
+	case "right", "l", "space":
+		if node := pp.selectedNode(); node != nil && !node.Expanded {
+			pp.tree.ToggleExpanded(pp.tree.Selected)
+		}
+	case "left", "h":
+		pp.collapse()
+	case "backspace", "u":
+		if pp.tree != nil {
+			pp.browseTo(filepath.Dir(pp.tree.Root.Path))
+		}
+	case "~":
+		pp.jumpBack()
+	case ".":
+		pp.showHidden = !pp.showHidden
+		if pp.tree != nil {
+			focus := pp.selectedPath()
+			pp.browse(pp.tree.Root.Path, focus)
+		}
+	}
+	return nil
+}
+
+// collapse сворачивает каталог, иначе переходит к родителю; у корня дерева
+// поднимается на каталог выше.
+func (pp *ProjectPickerScreen) collapse() {
+	node := pp.selectedNode()
+	switch {
+	case node == nil:
+	case node.Expanded && node.Parent != nil:
+		pp.tree.ToggleExpanded(pp.tree.Selected)
+	case node.Parent != nil:
+		for i, n := range pp.tree.FlatList {
+			if n == node.Parent {
+				pp.selected = len(pp.recent) + i
+				break
+			}
+		}
+	default:
+		pp.browseTo(filepath.Dir(node.Path))
+	}
+}
+
+func (pp *ProjectPickerScreen) choose(path string) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		pp.setStatus(displayPath(path)+" is not a directory", true)
+		return nil
+	}
+	return func() tea.Msg { return ProjectChosenMsg{Path: path} }
+}
+
+// HandleGlobalEsc закрывает пикер без выбора.
+func (pp *ProjectPickerScreen) HandleGlobalEsc() (bool, tea.Cmd) {
+	return true, func() tea.Msg { return ProjectPickerClosedMsg{} }
+}
+
+func (pp *ProjectPickerScreen) listHeight() int {
+	return max(pp.Height()-13, 3)
+}
+
+func (pp *ProjectPickerScreen) View() string {
+	width := max(pp.Width(), 40)
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(unselectedColor))
+
+	var rows []string
+	for i, path := range pp.recent {
+		label := displayPath(path)
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			label += dim.Render(" (missing)")
+		} else if isSurgeDir(path) {
+			label += dim.Render(" ◆")
+		}
+		rows = append(rows, pp.renderRow(i, label))
+	}
+	if pp.tree != nil {
+		for i, node := range pp.tree.FlatList {
+			label := node.Indent() + pp.icons.ForDir(node.Expanded).Render() + " " + node.Name
+			if node.Path == pp.projectPath {
+				label += dim.Render(" (current)")
+			} else if isSurgeDir(node.Path) {
+				label += dim.Render(" ◆")
+			}
+			rows = append(rows, pp.renderRow(len(pp.recent)+i, label))
+		}
+	}
+
+	visible := pp.listHeight()
+	start := 0
+	if pp.selected >= visible {
+		start = pp.selected - visible + 1
+	}
+	end := min(start+visible, len(rows))
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render("Open Project"))
+	b.WriteString("\n")
+	b.WriteString(dim.Render("Enter open • →/Space expand • ← collapse • Backspace/u up • ~ back/home • . hidden • Esc cancel"))
+	b.WriteString("\n")
+	b.WriteString(pp.renderCrumbs(pp.crumbWidth()))
+	b.WriteString("\n")
+	for i := start; i < end; i++ {
+		switch {
+		case i == 0 && len(pp.recent) > 0:
+			b.WriteString("\nRecent\n")
+		case i == len(pp.recent) || (i == start && i > len(pp.recent)):
+			if pp.tree != nil {
+				b.WriteString("\nBrowse\n")
+			}
+		}
+		b.WriteString(rows[i])
+		b.WriteString("\n")
+	}
+	if status := pp.status.line(); status != "" {
+		color := unselectedColor
+		if pp.status.severity() == statusError {
+			color = invalidColor
+		}
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(status))
+	} else {
+		b.WriteString("\n")
+		b.WriteString(dim.Render("◆ surge project"))
+	}
+
+	content := lipgloss.NewStyle().Padding(1).Width(width - 2).Render(strings.TrimRight(b.String(), "\n"))
+	return lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Width(width).Render(content)
+}
+
+func (pp *ProjectPickerScreen) renderRow(index int, label string) string {
+	if index == pp.selected {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(selectedColor)).Bold(true).Render("→ " + label)
+	}
+	return "  " + label
+}
+
+func (pp *ProjectPickerScreen) ShortHelp() string {
+	return "Enter: Open • ←→: Tree • Backspace/u: Up • Esc: Cancel"
+}
+
+func (pp *ProjectPickerScreen) FullHelp() []string {
+	return []string{
+		"Open Project:",
+		"  ↑/↓ - Move through recent projects and directories",
+		"  Enter - Open the selected directory as the project",
+		"  →/Space - Expand directory, ← - Collapse or go to parent",
+		"  Backspace / u - Browse one directory up (the cursor returns where it was)",
+		"  ~ - Back to the starting directory; pressed there - Browse home",
+		"  Click a path segment above the tree - Browse that directory",
+		"  . - Show or hide hidden directories",
+		"  Esc - Cancel",
+	}
+}
+
+// isSurgeDir сообщает, есть ли в каталоге surge.toml.
+func isSurgeDir(path string) bool {
+	info, err := os.Stat(filepath.Join(path, "surge.toml"))
+	return err == nil && !info.IsDir()
+}
+
+// displayPath сокращает домашний каталог до ~.
+func displayPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if rel, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
@@ -0,0 +1,144 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Строка пути над деревом пикера: предки текущего корня, каждый можно
+// выбрать щелчком. Для каждого посещённого корня запоминается выбранный
+// каталог, чтобы при возврате курсор стоял там же.
+
+const (
+	crumbSeparator = " › "
+	crumbRow       = 4 // рамка, отступ, заголовок, подсказки
+	crumbInsetX    = 2 // рамка и отступ слева
+)
+
+// crumb — сегмент строки пути; path пуст у сокращения «…».
+type crumb struct {
+	label string
+	path  string
+}
+
+// crumbs возвращает сегменты от корня файловой системы (или ~) до root.
+func crumbs(root string) []crumb {
+	home, _ := os.UserHomeDir()
+	var segments []crumb
+	for path := root; ; path = filepath.Dir(path) {
+		label := filepath.Base(path)
+		if path == home {
+			label = "~"
+		}
+		segments = append(segments, crumb{label: label, path: path})
+		if path == home || path == filepath.Dir(path) {
+			break
+		}
+	}
+	for i, j := 0, len(segments)-1; i < j; i, j = i+1, j-1 {
+		segments[i], segments[j] = segments[j], segments[i]
+	}
+	return segments
+}
+
+// visibleCrumbs убирает первые сегменты, пока строка не влезет в width.
+func visibleCrumbs(root string, width int) []crumb {
+	segments := crumbs(root)
+	for len(segments) > 1 && crumbsWidth(segments) > width {
+		if segments[0].path == "" {
+			segments = append([]crumb{{label: "…"}}, segments[2:]...)
+		} else {
+			segments = append([]crumb{{label: "…"}}, segments[1:]...)
+		}
+	}
+	return segments
+}
+
+func crumbsWidth(segments []crumb) int {
+	width := 0
+	for i, segment := range segments {
+		if i > 0 {
+			width += len([]rune(crumbSeparator))
+		}
+		width += lipgloss.Width(segment.label)
+	}
+	return width
+}
+
+func (pp *ProjectPickerScreen) renderCrumbs(width int) string {
+	if pp.tree == nil {
+		return ""
+	}
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(unselectedColor))
+	segments := visibleCrumbs(pp.tree.Root.Path, width)
+	parts := make([]string, len(segments))
+	for i, segment := range segments {
+		if i == len(segments)-1 {
+			parts[i] = lipgloss.NewStyle().Bold(true).Render(segment.label)
+		} else {
+			parts[i] = dim.Render(segment.label)
+		}
+	}
+	return strings.Join(parts, dim.Render(crumbSeparator))
+}
+
+// crumbWidth — ширина строки пути, как в View.
+func (pp *ProjectPickerScreen) crumbWidth() int {
+	return max(pp.Width(), 40) - 4
+}
+
+// handleMouse переходит к каталогу, по сегменту которого щёлкнули.
+func (pp *ProjectPickerScreen) handleMouse(msg tea.MouseMsg) {
+	if pp.tree == nil || msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft || msg.Y != crumbRow {
+		return
+	}
+	x := msg.X - crumbInsetX
+	for _, segment := range visibleCrumbs(pp.tree.Root.Path, pp.crumbWidth()) {
+		width := lipgloss.Width(segment.label)
+		if x >= 0 && x < width {
+			if segment.path != "" {
+				pp.browseTo(segment.path)
+			}
+			return
+		}
+		x -= width + len([]rune(crumbSeparator))
+	}
+}
+
+// browseTo открывает root с курсором на запомненном каталоге; без памяти —
+// на каталоге, из которого пришли, или на текущем проекте.
+func (pp *ProjectPickerScreen) browseTo(root string) {
+	focus := pp.visited[root]
+	if focus == "" && pp.tree != nil {
+		focus = childToward(root, pp.tree.Root.Path)
+	}
+	if focus == "" {
+		focus = pp.projectPath
+	}
+	pp.browse(root, focus)
+}
+
+// childToward возвращает каталог внутри root на пути к path.
+func childToward(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	first, _, _ := strings.Cut(rel, string(filepath.Separator))
+	return filepath.Join(root, first)
+}
+
+// jumpBack возвращает к корню, с которого начался просмотр; оттуда — домой.
+func (pp *ProjectPickerScreen) jumpBack() {
+	if pp.tree != nil && pp.tree.Root.Path != pp.startRoot {
+		pp.browseTo(pp.startRoot)
+		return
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		pp.browseTo(home)
+	}
+}
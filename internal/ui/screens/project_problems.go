@@ -0,0 +1,323 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/platform"
+)
+
+// Панель проблем под деревом и редактором: диагностики последнего запуска
+// surge diag для файла активной вкладки или (по a) для всего проекта.
+// Пустая панель не занимает места, пока в ней не появятся записи.
+
+const (
+	defaultProblemsHeight = 3
+	problemsChromeRows    = 3 // рамка и заголовок
+)
+
+// problemsDrawer — состояние панели проблем.
+type problemsDrawer struct {
+	open     bool
+	focused  bool
+	project  bool // весь проект, а не файл активной вкладки
+	selected int
+	scroll   int
+	height   int // строк таблицы (ui.problems_height)
+}
+
+// problemItem — строка таблицы: файл и диагностика в координатах буфера.
+type problemItem struct {
+	path string
+	diag EditorDiagnostic
+}
+
+// SetProblemsHeight задаёт число строк таблицы панели проблем.
+func (ps *ProjectScreenReal) SetProblemsHeight(rows int) {
+	if rows < 1 {
+		rows = defaultProblemsHeight
+	}
+	ps.problems.height = rows
+	ps.recalculateLayout()
+}
+
+// ToggleProblems открывает панель проблем с фокусом; повторный вызов
+// закрывает её (или возвращает фокус, если он ушёл в другую панель).
+func (ps *ProjectScreenReal) ToggleProblems() tea.Cmd {
+	switch {
+	case !ps.problems.open:
+		ps.problems.open = true
+		ps.problems.selected, ps.problems.scroll = 0, 0
+		ps.problems.focused = ps.problemCount() > 0
+		if !ps.problems.focused {
+			ps.setStatus(ps.noProblemsStatus())
+		}
+	case ps.problems.focused || ps.problemCount() == 0:
+		ps.problems.open = false
+		ps.problems.focused = false
+	default:
+		ps.problems.focused = true
+	}
+	ps.recalculateLayout()
+	if tab := ps.activeEditorTab(); tab != nil {
+		ps.ensureCursorVisible(tab)
+	}
+	return nil
+}
+
+func (ps *ProjectScreenReal) noProblemsStatus() string {
+	if ps.problems.project {
+		return "No problems in the project"
+	}
+	if tab := ps.activeEditorTab(); tab != nil {
+		return "No problems in " + tab.name
+	}
+	return "No open file; press a in the problems drawer for the whole project"
+}
+
+// problemItems возвращает записи текущей области: для открытого файла —
+// диагностики вкладки (они сдвигаются вместе с правками).
+func (ps *ProjectScreenReal) problemItems() []problemItem {
+	var items []problemItem
+	if !ps.problems.project {
+		tab := ps.activeEditorTab()
+		if tab == nil || tab.scratch {
+			return nil
+		}
+		for _, diag := range tab.diagnostics {
+			items = append(items, problemItem{path: tab.path, diag: diag})
+		}
+		return items
+	}
+
+	paths := make([]string, 0, len(ps.diagnostics))
+	for path := range ps.diagnostics {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		diags := ps.diagnostics[path]
+		if index := ps.findTabIndex(path); index >= 0 && !ps.tabs[index].dirty {
+			diags = ps.tabs[index].diagnostics
+		}
+		for _, diag := range diags {
+			items = append(items, problemItem{path: path, diag: diag})
+		}
+	}
+	return items
+}
+
+// problemCount — число записей текущей области без сборки таблицы.
+func (ps *ProjectScreenReal) problemCount() int {
+	if !ps.problems.project {
+		if tab := ps.activeEditorTab(); tab != nil && !tab.scratch {
+			return len(tab.diagnostics)
+		}
+		return 0
+	}
+	count := 0
+	for path, diags := range ps.diagnostics {
+		if index := ps.findTabIndex(path); index >= 0 && !ps.tabs[index].dirty {
+			diags = ps.tabs[index].diagnostics
+		}
+		count += len(diags)
+	}
+	return count
+}
+
+// problemsHeight — высота панели проблем вместе с рамкой; 0 — панель скрыта.
+func (ps *ProjectScreenReal) problemsHeight() int {
+	if !ps.problems.open || ps.loading || ps.err != nil {
+		return 0
+	}
+	count := ps.problemCount()
+	if count == 0 {
+		return 0
+	}
+	rows := min(count, max(ps.problems.height, 1))
+	// панелям над ящиком остаётся хотя бы несколько строк
+	rows = min(rows, ps.Height()-problemsChromeRows-8)
+	if rows < 1 {
+		return 0
+	}
+	return rows + problemsChromeRows
+}
+
+// panelHeight — высота дерева и редактора над панелью проблем.
+func (ps *ProjectScreenReal) panelHeight() int {
+	return ps.Height() - ps.problemsHeight()
+}
+
+// problemsActive сообщает, что клавиши принадлежат панели проблем;
+// опустевшая панель отдаёт фокус обратно.
+func (ps *ProjectScreenReal) problemsActive() bool {
+	if ps.problems.focused && ps.problemsHeight() == 0 {
+		ps.problems.focused = false
+	}
+	return ps.problems.focused
+}
+
+func (ps *ProjectScreenReal) handleProblemsKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
+	items := ps.problemItems()
+	switch platform.CanonicalKeyForLookup(msg.String()) {
+	case "up", "k":
+		ps.selectProblem(ps.problems.selected-1, len(items))
+	case "down", "j":
+		ps.selectProblem(ps.problems.selected+1, len(items))
+	case "pgup":
+		ps.selectProblem(ps.problems.selected-ps.problems.height, len(items))
+	case "pgdown":
+		ps.selectProblem(ps.problems.selected+ps.problems.height, len(items))
+	case "g", "home":
+		ps.selectProblem(0, len(items))
+	case "G", "end":
+		ps.selectProblem(len(items)-1, len(items))
+	case "enter":
+		ps.openProblem(items)
+	case "a":
+		ps.problems.project = !ps.problems.project
+		ps.problems.selected, ps.problems.scroll = 0, 0
+		if ps.problemsHeight() == 0 {
+			ps.setStatus(ps.noProblemsStatus())
+		}
+		ps.recalculateLayout()
+	}
+	return ps, nil
+}
+
+func (ps *ProjectScreenReal) selectProblem(index, count int) {
+	if count == 0 {
+		return
+	}
+	ps.problems.selected = clampInt(index, 0, count-1)
+	rows := ps.problemsHeight() - problemsChromeRows
+	if ps.problems.selected < ps.problems.scroll {
+		ps.problems.scroll = ps.problems.selected
+	}
+	if rows > 0 && ps.problems.selected >= ps.problems.scroll+rows {
+		ps.problems.scroll = ps.problems.selected - rows + 1
+	}
+}
+
+// openProblem переходит к выбранной диагностике в редакторе этого же экрана.
+func (ps *ProjectScreenReal) openProblem(items []problemItem) {
+	if ps.problems.selected >= len(items) {
+		return
+	}
+	item := items[ps.problems.selected]
+	ps.problems.focused = false
+	ps.OpenLocation(item.path, item.diag.Line+1, item.diag.Col+1)
+	ps.setStatus(formatEditorDiagnostic(item.diag))
+}
+
+// handleProblemsMouse выбирает строку по щелчку и переходит к ней.
+func (ps *ProjectScreenReal) handleProblemsMouse(msg tea.MouseMsg) tea.Cmd {
+	items := ps.problemItems()
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		ps.selectProblem(ps.problems.selected-1, len(items))
+		return nil
+	case tea.MouseButtonWheelDown:
+		ps.selectProblem(ps.problems.selected+1, len(items))
+		return nil
+	}
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return nil
+	}
+	row := msg.Y - ps.panelHeight() - 2 // рамка и заголовок
+	if row < 0 || ps.problems.scroll+row >= len(items) {
+		ps.problems.focused = true
+		return nil
+	}
+	ps.problems.selected = ps.problems.scroll + row
+	ps.openProblem(items)
+	return nil
+}
+
+func (ps *ProjectScreenReal) renderProblemsDrawer() string {
+	height := ps.problemsHeight()
+	if height == 0 {
+		return ""
+	}
+	items := ps.problemItems()
+	rows := height - problemsChromeRows
+	// во всю ширину панелей над ней (вместе с их рамками)
+	width := ps.treeWidth
+	if ps.mainWidth > 0 {
+		width += ps.mainWidth + 2
+	}
+	width = max(width, 10)
+	inner := max(width-2, 8)
+	focused := ps.problems.focused
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(DimTextColor))
+
+	scope := "file"
+	if ps.problems.project {
+		scope = "project"
+	} else if tab := ps.activeEditorTab(); tab != nil {
+		scope = tab.name
+	}
+	info := fmt.Sprintf("%s • %s", scope, problemsCount(items))
+	if focused {
+		info += " • Enter open • a scope • Esc back"
+	}
+	title := ps.focus.RenderTitle("⚠ Problems", focused)
+	title += " " + dim.Render(truncateString(info, inner-lipgloss.Width(title)-1))
+
+	ps.problems.selected = clampInt(ps.problems.selected, 0, len(items)-1)
+	ps.problems.scroll = clampInt(ps.problems.scroll, 0, max(len(items)-rows, 0))
+	lines := []string{title}
+	for i := ps.problems.scroll; i < min(ps.problems.scroll+rows, len(items)); i++ {
+		line := ps.renderProblemRow(items[i], inner)
+		if i == ps.problems.selected {
+			background := borderColor
+			if focused {
+				background = selectedColor
+			}
+			line = lipgloss.NewStyle().Background(lipgloss.Color(background)).Width(inner).Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	style := ps.focus.Panel(focused).
+		Width(width).
+		Height(height-2).
+		Padding(0, 1)
+	return style.Render(strings.Join(lines, "\n"))
+}
+
+// renderProblemRow — строка таблицы: метка, место, код и сообщение.
+func (ps *ProjectScreenReal) renderProblemRow(item problemItem, width int) string {
+	location := fmt.Sprintf("%d:%d", item.diag.Line+1, item.diag.Col+1)
+	if ps.problems.project {
+		location = truncatePath(ps.relativePath(item.path), max(width/3, 12)) + ":" + location
+	}
+	marker := renderDiagnosticMarker([]EditorDiagnostic{item.diag}, item.diag.Line)
+	text := location
+	if item.diag.Code != "" {
+		text += " " + item.diag.Code
+	}
+	text += " " + strings.ReplaceAll(item.diag.Message, "\n", " ")
+	return marker + " " + truncateString(text, width-2)
+}
+
+// problemsCount — сводка по важности для заголовка панели.
+func problemsCount(items []problemItem) string {
+	var errorsCount, warningsCount int
+	for _, item := range items {
+		switch item.diag.Severity {
+		case "error":
+			errorsCount++
+		case "warning":
+			warningsCount++
+		}
+	}
+	parts := []string{plural(errorsCount, "error"), plural(warningsCount, "warning")}
+	if other := len(items) - errorsCount - warningsCount; other > 0 {
+		parts = append(parts, fmt.Sprintf("%d other", other))
+	}
+	return strings.Join(parts, ", ")
+}
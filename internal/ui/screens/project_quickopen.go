@@ -0,0 +1,43 @@
+package screens
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+)
+
+// OpenQuickOpen показывает быстрое открытие файла по имени.
+func (ps *ProjectScreenReal) OpenQuickOpen() tea.Cmd {
+	if ps.quickOpen == nil {
+		ps.quickOpen = newQuickOpenDialog()
+	}
+	recent, err := config.LoadRecentFiles(ps.projectPath)
+	if err != nil {
+		ps.setStatus("Recent files: " + err.Error())
+	}
+	ps.quickOpen.Show(ps.projectPath, recent, max(ps.Height()-12, 3))
+	return ps.indexQuickOpen()
+}
+
+// indexQuickOpen перестраивает индекс в фоне, если дерево было заменено.
+func (ps *ProjectScreenReal) indexQuickOpen() tea.Cmd {
+	if ps.quickOpen == nil || ps.fileTree == nil || !ps.quickOpen.needsIndex(ps.fileTree.Root) {
+		return nil
+	}
+	ps.quickOpen.pendingRoot = ps.fileTree.Root
+	return buildQuickOpenIndex(ps.fileTree)
+}
+
+func (ps *ProjectScreenReal) handleQuickOpenIndex(msg quickOpenIndexMsg) {
+	if ps.quickOpen != nil {
+		ps.quickOpen.setIndex(msg)
+	}
+}
+
+// rememberRecentFile поднимает файл в списке недавних проекта.
+// Ошибка записи кэша не мешает работе и не показывается.
+func (ps *ProjectScreenReal) rememberRecentFile(path string) {
+	if ps.projectPath == "" || path == "" {
+		return
+	}
+	_, _ = config.AddRecentFile(ps.projectPath, path)
+}
@@ -0,0 +1,266 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Переименование по шаблону (R в дереве или «Rename with Pattern…» в палитре)
+// для отмеченных элементов или выбранного. Шаблоны:
+//   s/old/new/   — замена первого вхождения текста (s/old/new/g — всех);
+//   pre_*, *_v2  — * заменяется именем без расширения, расширение остаётся.
+// Перед переименованием показывается таблица старых и новых имён; при
+// конфликтах подтверждение возвращает к вводу шаблона. Переименования идут
+// по порядку и останавливаются на первой ошибке.
+
+// renamePreviewLimit — сколько строк таблицы показывается в предпросмотре.
+const renamePreviewLimit = 12
+
+// renamePlanItem — одно переименование; reason непуст, если оно невозможно.
+type renamePlanItem struct {
+	from   string
+	to     string
+	reason string
+}
+
+type renamePatternMsg struct {
+	paths []string
+	value *string
+}
+
+type renamePreviewMsg struct {
+	paths     []string
+	pattern   string
+	plan      []renamePlanItem
+	confirmed bool
+}
+
+// CanRenameWithPattern сообщает, есть ли в дереве что переименовать.
+func (ps *ProjectScreenReal) CanRenameWithPattern() bool {
+	return ps.fileTree != nil && len(ps.renameTargets()) > 0
+}
+
+// RenameWithPattern запрашивает шаблон для отмеченных (или выбранного) элементов.
+func (ps *ProjectScreenReal) RenameWithPattern() tea.Cmd {
+	paths := ps.renameTargets()
+	if len(paths) == 0 {
+		ps.setStatus("Nothing to rename")
+		return nil
+	}
+	return ps.askRenamePattern(paths, "")
+}
+
+// renameTargets — цели из дерева без корня проекта.
+func (ps *ProjectScreenReal) renameTargets() []string {
+	if ps.fileTree == nil {
+		return nil
+	}
+	var paths []string
+	for _, path := range ps.treeTargets() {
+		if filepath.Clean(path) != filepath.Clean(ps.projectPath) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func (ps *ProjectScreenReal) askRenamePattern(paths []string, pattern string) tea.Cmd {
+	if ps.patternDialog == nil {
+		return nil
+	}
+	ps.patternDialog.Title = "Rename " + countEntries(len(paths))
+	if len(paths) == 1 {
+		ps.patternDialog.Title = "Rename " + filepath.Base(paths[0])
+	}
+	ch := ps.patternDialog.ShowWithValue(pattern)
+	return func() tea.Msg {
+		return renamePatternMsg{paths: paths, value: <-ch}
+	}
+}
+
+func (ps *ProjectScreenReal) handleRenamePattern(msg renamePatternMsg) tea.Cmd {
+	if msg.value == nil || strings.TrimSpace(*msg.value) == "" {
+		return nil
+	}
+	pattern := strings.TrimSpace(*msg.value)
+	rename, err := parseRenamePattern(pattern)
+	if err != nil {
+		ps.setStatus(err.Error())
+		return ps.askRenamePattern(msg.paths, pattern)
+	}
+	plan := planRenames(msg.paths, rename)
+	if len(plan) == 0 {
+		ps.setStatus("Pattern changes no names")
+		return nil
+	}
+	conflicts := 0
+	for _, item := range plan {
+		if item.reason != "" {
+			conflicts++
+		}
+	}
+	if ps.renamePreview == nil {
+		return nil
+	}
+	ps.renamePreview.Description = ps.renamePreviewTable(plan)
+	ps.renamePreview.ConfirmText = "Rename"
+	if conflicts > 0 {
+		ps.renamePreview.Description += "\n\n" + plural(conflicts, "conflict") + "; nothing will be renamed"
+		ps.renamePreview.ConfirmText = "Edit Pattern"
+	}
+	ch := ps.renamePreview.Show()
+	return func() tea.Msg {
+		return renamePreviewMsg{paths: msg.paths, pattern: pattern, plan: plan, confirmed: <-ch}
+	}
+}
+
+func (ps *ProjectScreenReal) handleRenamePreview(msg renamePreviewMsg) tea.Cmd {
+	if !msg.confirmed {
+		return nil
+	}
+	for _, item := range msg.plan {
+		if item.reason != "" {
+			return ps.askRenamePattern(msg.paths, msg.pattern)
+		}
+	}
+
+	done := 0
+	for _, item := range msg.plan {
+		if err := renamePlanned(item); err != nil {
+			ps.setStatus(fmt.Sprintf("Renamed %d of %d; %s: %v", done, len(msg.plan), filepath.Base(item.from), err))
+			return ps.loadFileTree()
+		}
+		ps.retargetTabs(item.from, item.to)
+		done++
+	}
+	ps.fileTree.ClearMarks()
+	ps.setStatus("Renamed " + countEntries(done))
+	return ps.loadFileTree()
+}
+
+// renamePlanned переименовывает, перепроверяя, что цель не появилась после
+// предпросмотра.
+func renamePlanned(item renamePlanItem) error {
+	if renameTargetTaken(item.from, item.to) {
+		return errors.New(filepath.Base(item.to) + " already exists")
+	}
+	return os.Rename(item.from, item.to)
+}
+
+// renameTargetTaken сообщает, что по новому пути лежит другой файл. Тот же
+// файл под другим регистром (нечувствительная к регистру ФС) не мешает.
+func renameTargetTaken(from, to string) bool {
+	target, err := os.Lstat(to)
+	if err != nil {
+		return false
+	}
+	source, err := os.Lstat(from)
+	return err != nil || !os.SameFile(source, target)
+}
+
+// parseRenamePattern разбирает шаблон в функцию имени: dir — каталог,
+// у которого расширение не отделяется.
+func parseRenamePattern(pattern string) (func(name string, dir bool) string, error) {
+	if strings.HasPrefix(pattern, "s/") {
+		parts := strings.Split(pattern[2:], "/")
+		if len(parts) != 3 || parts[0] == "" || (parts[2] != "" && parts[2] != "g") {
+			return nil, errors.New("pattern must look like s/old/new/ or s/old/new/g")
+		}
+		old, replacement, count := parts[0], parts[1], 1
+		if parts[2] == "g" {
+			count = -1
+		}
+		return func(name string, _ bool) string {
+			return strings.Replace(name, old, replacement, count)
+		}, nil
+	}
+	if strings.Count(pattern, "*") != 1 {
+		return nil, errors.New("template needs exactly one * for the current name, e.g. pre_* or *_v2")
+	}
+	return func(name string, dir bool) string {
+		ext := ""
+		if !dir {
+			ext = filepath.Ext(name)
+			if ext == name {
+				ext = "" // .gitignore и подобные
+			}
+		}
+		return strings.Replace(pattern, "*", strings.TrimSuffix(name, ext), 1) + ext
+	}, nil
+}
+
+// planRenames применяет шаблон и отмечает конфликты: пустое имя,
+// разделитель пути, существующий файл и совпадение двух новых имён.
+// Элементы, чьё имя не меняется, в план не попадают.
+func planRenames(paths []string, rename func(name string, dir bool) string) []renamePlanItem {
+	var plan []renamePlanItem
+	targets := make(map[string]int)
+	for _, path := range paths {
+		info, err := os.Lstat(path)
+		name := filepath.Base(path)
+		newName := rename(name, err == nil && info.IsDir())
+		if newName == name {
+			continue
+		}
+		item := renamePlanItem{from: path, to: filepath.Join(filepath.Dir(path), newName)}
+		switch {
+		case err != nil:
+			item.reason = "missing"
+		case newName == "" || newName == "." || newName == "..":
+			item.reason = "empty name"
+		case strings.ContainsAny(newName, "/\\"):
+			item.reason = "name contains a separator"
+		}
+		if item.reason == "" {
+			if renameTargetTaken(item.from, item.to) {
+				item.reason = "already exists"
+			}
+		}
+		if first, ok := targets[strings.ToLower(item.to)]; ok {
+			item.reason = "same name as " + filepath.Base(plan[first].from)
+			if plan[first].reason == "" {
+				plan[first].reason = "same name as " + name
+			}
+		} else {
+			targets[strings.ToLower(item.to)] = len(plan)
+		}
+		plan = append(plan, item)
+	}
+	return plan
+}
+
+// renamePreviewTable — строки «старое → новое», конфликты выделены цветом.
+func (ps *ProjectScreenReal) renamePreviewTable(plan []renamePlanItem) string {
+	width := 0
+	for _, item := range plan[:min(len(plan), renamePreviewLimit)] {
+		width = max(width, lipgloss.Width(ps.relativePath(item.from)))
+	}
+	conflict := lipgloss.NewStyle().Foreground(lipgloss.Color(ErrorColor))
+	lines := []string{countEntries(len(plan)) + " to rename:"}
+	for i, item := range plan {
+		if i == renamePreviewLimit {
+			lines = append(lines, fmt.Sprintf("  …and %d more", len(plan)-i))
+			break
+		}
+		from := ps.relativePath(item.from)
+		line := fmt.Sprintf("  %s%s → %s", from, strings.Repeat(" ", width-lipgloss.Width(from)), filepath.Base(item.to))
+		if item.reason != "" {
+			line = conflict.Render(line + "  ✗ " + item.reason)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func countEntries(n int) string {
+	if n == 1 {
+		return "1 entry"
+	}
+	return fmt.Sprintf("%d entries", n)
+}
@@ -5,7 +5,10 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/fs"
 	"surge-tui/internal/platform"
+	"surge-tui/internal/syntax"
+	"surge-tui/internal/ui/styles"
 )
 
 func (ps *ProjectScreenReal) renderLoading() string {
@@ -34,33 +37,35 @@ func (ps *ProjectScreenReal) renderFileTreePanel() string {
 	if width <= 0 {
 		width = ps.Width()
 	}
-	height := max(ps.Height()-2, 3)
+	height := max(ps.panelHeight()-2, 3)
 
-	borderColor := InactiveBorderColor
-	if ps.focusedPanel == FileTreePanel {
-		borderColor = ActiveBorderColor
-	}
-
-	title := "📁 Files"
-	if ps.focusedPanel == FileTreePanel {
-		title = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#F8FAFC")).Render(title + " (focused)")
-	} else {
-		title = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#E2E8F0")).Render(title)
-	}
+	focused := ps.focusedPanel == FileTreePanel && !ps.problems.focused
+	title := ps.focus.RenderTitle("📁 Files", focused)
 
 	filterInfo := lipgloss.NewStyle().Foreground(lipgloss.Color(DimTextColor)).Render(ps.getFilterInfo())
 	treeContent := ps.renderFileTree(width)
+	if ps.unavailable != nil {
+		treeContent = ps.renderUnavailable()
+	}
 
 	var builder []string
 	builder = append(builder, title, filterInfo, "", treeContent)
 
 	if status := ps.statusLine(); status != "" && (ps.focusedPanel == FileTreePanel || len(ps.tabs) == 0) {
-		builder = append(builder, "", lipgloss.NewStyle().Foreground(lipgloss.Color(DimTextColor)).Render(status))
+		builder = append(builder, "", lipgloss.NewStyle().Foreground(lipgloss.Color(ps.status.severity().color())).Render(status))
 	}
 
-	style := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(borderColor)).
+	if focused {
+		if hints := ps.renderHintRow(max(width-2, 1)); hints != "" {
+			// подсказки прижаты к нижней границе панели
+			for lines := len(strings.Split(strings.Join(builder, "\n"), "\n")); lines < height-1; lines++ {
+				builder = append(builder, "")
+			}
+			builder = append(builder, hints)
+		}
+	}
+
+	style := ps.focus.Panel(focused).
 		Width(width).
 		Height(height).
 		Padding(0, 1)
@@ -80,19 +85,13 @@ func (ps *ProjectScreenReal) renderWorkspacePanel() string {
 
 func (ps *ProjectScreenReal) renderWorkspaceEmpty() string {
 	width := ps.mainWidth
-	height := max(ps.Height()-2, 3)
-
-	borderColor := InactiveBorderColor
-	if ps.focusedPanel == EditorPanel {
-		borderColor = ActiveBorderColor
-	}
+	height := max(ps.panelHeight()-2, 3)
 
-	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#F8FAFC")).Render("🛠 Workspace")
+	focused := ps.focusedPanel == EditorPanel && !ps.problems.focused
+	title := ps.focus.RenderTitle("🛠 Workspace", focused)
 	content := ps.renderProjectInfo(width)
 
-	style := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(borderColor)).
+	style := ps.focus.Panel(focused).
 		Width(width).
 		Height(height).
 		Padding(0, 1)
@@ -102,13 +101,9 @@ func (ps *ProjectScreenReal) renderWorkspaceEmpty() string {
 
 func (ps *ProjectScreenReal) renderWorkspaceEditor() string {
 	width := max(ps.mainWidth, 20)
-	height := max(ps.Height()-2, 3)
-
-	borderColor := InactiveBorderColor
-	if ps.focusedPanel == EditorPanel {
-		borderColor = ActiveBorderColor
-	}
+	height := max(ps.panelHeight()-2, 3)
 
+	focused := ps.focusedPanel == EditorPanel && !ps.problems.focused
 	innerWidth := max(width-2, 10)
 	tabBar := ps.renderTabBar(innerWidth)
 	body := ps.renderEditorBody()
@@ -123,10 +118,13 @@ func (ps *ProjectScreenReal) renderWorkspaceEditor() string {
 	if tab := ps.activeEditorTab(); tab != nil && tab.mode == editorModeCommand {
 		parts = append(parts, ps.renderCommandLine())
 	}
+	if focused {
+		if hints := ps.renderHintRow(innerWidth); hints != "" {
+			parts = append(parts, hints)
+		}
+	}
 
-	style := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(borderColor)).
+	style := ps.focus.Panel(focused).
 		Width(width).
 		Height(height).
 		Padding(0, 1)
@@ -144,24 +142,41 @@ func (ps *ProjectScreenReal) renderTabBar(width int) string {
 
 	var rendered []string
 	for i, tab := range ps.tabs {
-		title := tab.name
-		if tab.dirty {
-			title = "*" + title
+		title := ps.tabTitle(tab, width)
+		style := ps.tabNormalStyle
+		if i == ps.activeTab {
+			style = ps.tabActiveStyle
 		}
-		if lipgloss.Width(title) > width/2 {
-			title = truncateMiddle(title, max(width/2, 8))
+		if tab.scratch {
+			style = style.Italic(true).Foreground(lipgloss.Color(scratchTabColor))
 		}
-		if i == ps.activeTab {
-			rendered = append(rendered, ps.tabActiveStyle.Render(title))
-		} else {
-			rendered = append(rendered, ps.tabNormalStyle.Render(title))
+		if ps.tabAtRisk(tab) {
+			title = "✗ " + title
+			style = style.Foreground(lipgloss.Color(ErrorColor))
 		}
+		rendered = append(rendered, style.Render(title))
 	}
 
 	line := strings.Join(rendered, " ")
 	return lipgloss.NewStyle().Width(width).Render(line)
 }
 
+// tabTitle возвращает подпись вкладки так, как она выводится в строке табов.
+// Значок не красится: цвет вкладки задаёт её стиль.
+func (ps *ProjectScreenReal) tabTitle(tab *editorTab, width int) string {
+	title := ps.icons.ForFile(tab.name).Glyph + " " + tab.name
+	if tab.dirty {
+		title = "*" + title
+	}
+	if tab.isLossy() {
+		title = lossyWarningBadge + " " + title
+	}
+	if lipgloss.Width(title) > width/2 {
+		title = truncateMiddle(title, max(width/2, 8))
+	}
+	return title
+}
+
 func (ps *ProjectScreenReal) renderEditorBody() string {
 	tab := ps.activeEditorTab()
 	if tab == nil {
@@ -171,50 +186,65 @@ func (ps *ProjectScreenReal) renderEditorBody() string {
 	contentWidth := ps.editorContentWidth()
 	contentHeight := ps.editorContentHeight()
 
-	lineNumberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#64748B"))
-	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#7C3AED"))
+	lineNumberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(lineNumberColor))
+	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(onSelectedColor)).Background(lipgloss.Color(selectedColor))
+	selectionStyle := lipgloss.NewStyle().Background(lipgloss.Color(selectionColor))
 
 	start := tab.scroll
 	end := min(start+contentHeight, tab.lineCount())
 
+	var highlighted []syntax.Line
+	if ps.highlight != nil && syntax.SupportsFile(tab.name) {
+		highlighted = tab.highlightLines()
+	}
+	bracket, hasBracket := tab.matchingBracket()
+
 	var rows []string
 	for idx := start; idx < end; idx++ {
 		line := tab.lines[idx]
 		runes := []rune(line)
 		col := min(tab.cursor.Col, len(runes))
 
-		display := line
+		decor := noDecor()
 		if idx == tab.cursor.Line {
-			before := string(runes[:col])
-			cursor := " "
-			after := ""
-			if col < len(runes) {
-				cursor = string(runes[col])
-				after = string(runes[col+1:])
-			}
-			display = before + cursorStyle.Render(cursor) + after
+			decor.cursorCol = col
+			decor.cursorStyle = cursorStyle
+		}
+		if from, to, ok := tab.lineSelection(idx); ok {
+			decor.selFrom, decor.selTo = from, to
+			decor.selStyle = selectionStyle
+		}
+		if hasBracket {
+			bracketDecor(&decor, bracket, idx)
 		}
+		var tokens []syntax.Token
+		if idx < len(highlighted) {
+			tokens = highlighted[idx].Tokens
+		}
+		display := renderHighlightedLine(runes, tokens, ps.highlight, decor)
 
 		contentStyle := lipgloss.NewStyle().Width(contentWidth)
 		if idx == tab.cursor.Line {
-			contentStyle = contentStyle.Background(lipgloss.Color("#1F2937"))
+			contentStyle = contentStyle.Background(lipgloss.Color(currentLineColor))
 		}
 
-		number := lineNumberStyle.Render(fmt.Sprintf("%5d ", idx+1))
-		row := lipgloss.JoinHorizontal(lipgloss.Left, number, contentStyle.Render(display))
+		marker := renderSeverityMarker(tab.diagnosticMark(idx))
+		number := renderLineNumber(lineNumberStyle, tab, idx)
+		change := renderChangeMarker(tab.lineChangeAt(idx))
+		row := lipgloss.JoinHorizontal(lipgloss.Left, marker, number, change, contentStyle.Render(display))
 		rows = append(rows, row)
 	}
 
 	if len(rows) == 0 {
 		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Left,
-			lineNumberStyle.Render(fmt.Sprintf("%5d ", 1)),
+			" "+lineNumberStyle.Render(fmt.Sprintf("%5d ", 1)),
 			lipgloss.NewStyle().Width(contentWidth).Render(""),
 		))
 	}
 
 	body := strings.Join(rows, "\n")
 	bodyStyle := lipgloss.NewStyle().
-		Width(contentWidth + 6).
+		Width(contentWidth + editorGutterWidth).
 		Height(contentHeight)
 
 	return bodyStyle.Render(body)
@@ -232,6 +262,8 @@ func (ps *ProjectScreenReal) renderEditorStatus() string {
 		mode = "-- INSERT --"
 	case editorModeCommand:
 		mode = "-- COMMAND --"
+	case editorModeVisual:
+		mode = visualModeLabel(tab)
 	default:
 		mode = "-- NORMAL --"
 	}
@@ -243,31 +275,52 @@ func (ps *ProjectScreenReal) renderEditorStatus() string {
 
 	position := fmt.Sprintf("L%d C%d", tab.cursor.Line+1, tab.cursor.Col+1)
 	info := fmt.Sprintf("%s %s %s | %s", mode, dirty, tab.name, position)
+	if crumb := ps.backCrumb(); crumb != "" {
+		info = crumb + " | " + info
+	}
+	if note := ps.autosaveNote(tab); note != "" {
+		info += " | " + note
+	}
+	if tab.isLossy() {
+		info += " | " + lossyWarningBadge + " " + lossyWarningDescription
+	}
+	if ps.tabAtRisk(tab) {
+		info += " | project unavailable — saves blocked"
+	}
+	if diag, ok := diagnosticsOnLine(tab.diagnostics, tab.cursor.Line); ok {
+		info += " | " + formatEditorDiagnostic(diag)
+	}
 
 	if status := ps.statusLine(); status != "" && ps.focusedPanel == EditorPanel {
 		info += "  —  " + status
 	}
 
+	// маркер без собственного стиля, чтобы не сбрасывать фон строки
+	marker := "  "
+	if ps.focusedPanel == EditorPanel {
+		marker = styles.FocusMarker + " "
+	}
 	return lipgloss.NewStyle().
 		Width(max(ps.mainWidth-2, 20)).
-		Background(lipgloss.Color("#1E293B")).
-		Foreground(lipgloss.Color("#CBD5F5")).
+		Background(lipgloss.Color(barColor)).
+		Foreground(lipgloss.Color(barTextColor)).
 		Padding(0, 1).
-		Render(strings.TrimSpace(info))
+		Render(marker + strings.TrimSpace(info))
 }
 
 func (ps *ProjectScreenReal) renderCommandLine() string {
 	return lipgloss.NewStyle().
 		Width(max(ps.mainWidth-2, 20)).
-		Background(lipgloss.Color("#111827")).
-		Foreground(lipgloss.Color("#F8FAFC")).
+		Background(lipgloss.Color(commandBarColor)).
+		Foreground(lipgloss.Color(textColor)).
 		Padding(0, 1).
 		Render(ps.editorCommand.View())
 }
 
 func (ps *ProjectScreenReal) renderFileTree(panelWidth int) string {
+	empty := ps.treeEmptyState()
 	if ps.fileTree == nil || len(ps.fileTree.FlatList) == 0 {
-		return "No files found"
+		return ps.renderTreeEmpty(empty)
 	}
 
 	if panelWidth <= 0 {
@@ -275,27 +328,21 @@ func (ps *ProjectScreenReal) renderFileTree(panelWidth int) string {
 	}
 
 	var lines []string
-	maxLines := max(ps.Height()-MaxDisplayLines, 1)
-
-	start := ps.fileTree.Selected
-	if maxLines > ScrollOffset && start > maxLines/ScrollOffset {
-		start = ps.fileTree.Selected - maxLines/ScrollOffset
-	}
-	if start < 0 {
-		start = 0
-	}
-
-	end := start + maxLines
-	if end > len(ps.fileTree.FlatList) {
-		end = len(ps.fileTree.FlatList)
-		start = max(end-maxLines, 0)
-	}
-
+	start, end := ps.treeWindow()
 	for i := start; i < end; i++ {
 		node := ps.fileTree.FlatList[i]
-		line := node.GetDisplayName()
+		icon := ps.treeIcon(node)
+		prefix := node.Indent() + icon.Glyph + " "
+		line := prefix + node.Name
+		badge := ps.treeBadge(node)
 
 		maxWidth := max(panelWidth-6, 1)
+		if badge != "" {
+			maxWidth = max(maxWidth-2, 1)
+		}
+		if ps.statusInfo.MarkedCount > 0 {
+			maxWidth = max(maxWidth-2, 1) // колонка отметок
+		}
 		if len(line) > maxWidth {
 			runes := []rune(line)
 			if len(runes) > maxWidth {
@@ -305,25 +352,82 @@ func (ps *ProjectScreenReal) renderFileTree(panelWidth int) string {
 			}
 		}
 
+		// у выбранной строки значок не красится, чтобы не рвать фон выделения
+		if i != ps.fileTree.Selected && strings.HasPrefix(line, prefix) {
+			line = node.Indent() + icon.Render() + " " + line[len(prefix):]
+		}
+
 		if i == ps.fileTree.Selected {
 			if ps.focusedPanel == FileTreePanel {
 				line = lipgloss.NewStyle().
-					Background(lipgloss.Color("#7C3AED")).
-					Foreground(lipgloss.Color("#FFFFFF")).
+					Background(lipgloss.Color(selectedColor)).
+					Foreground(lipgloss.Color(onSelectedColor)).
 					Render(line)
 			} else {
 				line = lipgloss.NewStyle().
-					Background(lipgloss.Color("#334155")).
+					Background(lipgloss.Color(borderColor)).
 					Render(line)
 			}
 		}
+		if badge != "" {
+			line += " " + badge
+		}
+		if ps.statusInfo.MarkedCount > 0 {
+			line = treeMarkGutter(node) + line
+		}
 
 		lines = append(lines, line)
 	}
+	if empty != treeNotEmpty {
+		lines = append(lines, "", ps.renderTreeEmpty(empty))
+	}
 
 	return strings.Join(lines, "\n")
 }
 
+// treeIcon выбирает значок узла дерева.
+func (ps *ProjectScreenReal) treeIcon(node *fs.FileNode) styles.Icon {
+	switch {
+	case node.Placeholder:
+		return ps.icons.Lookup(styles.IconLoading)
+	case node.IsDir:
+		return ps.icons.ForDir(node.Expanded)
+	}
+	return ps.icons.ForFile(node.Name)
+}
+
+// treeMarkGutter — колонка отметок слева от дерева, пока что-то отмечено.
+func treeMarkGutter(node *fs.FileNode) string {
+	if !node.Marked {
+		return "  "
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(modifiedColor)).Bold(true).Render("✚") + " "
+}
+
+// treeWindow возвращает диапазон видимых строк дерева [start, end).
+func (ps *ProjectScreenReal) treeWindow() (int, int) {
+	maxLines := ps.panelHeight() - MaxDisplayLines
+	if ps.treeHintsVisible() {
+		maxLines-- // строка подсказок
+	}
+	maxLines = max(maxLines, 1)
+
+	start := ps.fileTree.Selected
+	if maxLines > ScrollOffset && start > maxLines/ScrollOffset {
+		start = ps.fileTree.Selected - maxLines/ScrollOffset
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	end := start + maxLines
+	if end > len(ps.fileTree.FlatList) {
+		end = len(ps.fileTree.FlatList)
+		start = max(end-maxLines, 0)
+	}
+	return start, end
+}
+
 func (ps *ProjectScreenReal) getFilterInfo() string {
 	if ps.fileTree == nil {
 		return "Filters: none"
@@ -332,13 +436,26 @@ func (ps *ProjectScreenReal) getFilterInfo() string {
 	if ps.fileTree.ShowHidden {
 		filters = append(filters, "Hidden")
 	}
+	if ps.fileTree.GitIgnore != nil && !ps.fileTree.ShowIgnored {
+		filters = append(filters, ".gitignore")
+	}
 	if ps.fileTree.FilterSurge {
 		filters = append(filters, ".sg only")
 	}
-	if len(filters) == 0 {
-		return "Filters: none"
+	if ps.treeLegend.filtering() {
+		filters = append(filters, "with diagnostics")
+	}
+	info := "Filters: none"
+	if len(filters) > 0 {
+		info = "Filters: " + strings.Join(filters, ", ")
+	}
+	if marked := ps.statusInfo.MarkedCount; marked > 0 {
+		info += fmt.Sprintf(" • %d marked", marked)
+	}
+	if ps.follow.enabled {
+		info += " • following tab"
 	}
-	return "Filters: " + strings.Join(filters, ", ")
+	return info
 }
 
 func (ps *ProjectScreenReal) renderProjectInfo(width int) string {
@@ -355,6 +472,9 @@ func (ps *ProjectScreenReal) renderProjectInfo(width int) string {
 	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Statistics:"))
 	lines = append(lines, fmt.Sprintf("📁 Directories: %d", ps.statusInfo.DirCount))
 	lines = append(lines, fmt.Sprintf("📄 Files: %d", ps.statusInfo.FileCount))
+	if ps.statusInfo.MarkedCount > 0 {
+		lines = append(lines, fmt.Sprintf("✚ Marked: %d", ps.statusInfo.MarkedCount))
+	}
 	lines = append(lines, "")
 
 	if selected := ps.fileTree.GetSelected(); selected != nil {
@@ -372,11 +492,14 @@ func (ps *ProjectScreenReal) renderProjectInfo(width int) string {
 	lines = append(lines, "Space - Expand/collapse directory")
 	lines = append(lines, "n / Shift+N - New file / directory")
 	lines = append(lines, "r - Rename • Delete - Remove")
+	lines = append(lines, "m - Mark entry • M - Clear marks")
 	lines = append(lines, "h - Toggle hidden • s - Toggle .sg")
+	lines = append(lines, "f - Format project • i - Init project (surge init)")
+	lines = append(lines, "c - Diagnostics in dir • F - Fix all in dir")
 	lines = append(lines, platform.ReplacePrimaryModifier("Ctrl+R - Refresh tree listing"))
 	lines = append(lines, platform.ReplacePrimaryModifier("Ctrl+→ focus editor • Ctrl+← focus tree"))
 	lines = append(lines, "Alt+←/→ switch tab • Alt+Shift+←/→ reorder")
-	lines = append(lines, ":w save • :q quit tab • yy/dd/p line copy/cut/paste")
+	lines = append(lines, ":w save • :q quit tab • :fmt format • yy/dd/p line copy/cut/paste")
 
 	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Actions:"))
 	lines = append(lines, ps.renderFileActions())
@@ -395,19 +518,31 @@ func (ps *ProjectScreenReal) renderFileActions() string {
 
 	var entries []string
 	button := func(label, hint string) string {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Render(fmt.Sprintf("[ %s ]", strings.ToUpper(label))) + " " + hint
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(diagHeaderColor)).Render(fmt.Sprintf("[ %s ]", strings.ToUpper(label))) + " " + hint
 	}
 	buttonDisabled := func(label, hint string) string {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#475569")).Render(fmt.Sprintf("[ %s ]", strings.ToUpper(label))) + " " + hint
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(mutedColor)).Render(fmt.Sprintf("[ %s ]", strings.ToUpper(label))) + " " + hint
+	}
+
+	withKey := func(hint, action string) string {
+		if key := ps.commandKeys[action]; key != "" {
+			hint += " (" + key + ")"
+		}
+		return hint
 	}
 
 	if node.IsDir {
-		if ps.isProjectDirectory(node.Path) {
-			entries = append(entries, button("format", "Format project (TODO)"))
-			entries = append(entries, button("build", "Build project (TODO)"))
-			entries = append(entries, button("diagnostic", "Run diagnostics (TODO)"))
+		project := ps.isProjectDirectory(node.Path)
+		if project {
+			entries = append(entries, button("format", "Format project (f)"))
+			entries = append(entries, button("build", withKey("Build project", "run_build")))
+		} else {
+			entries = append(entries, button("init", "Initialize project (i)"))
+		}
+		if project || ps.inSurgeProject(node.Path) {
+			entries = append(entries, button("diagnostic", withKey("Run diagnostics here", "project.diag_here")))
+			entries = append(entries, button("fix", withKey("Fix all in this directory", "project.fix_here")))
 		} else {
-			entries = append(entries, button("init", "Initialize project"))
 			entries = append(entries, buttonDisabled("format", "Project not initialized"))
 			entries = append(entries, buttonDisabled("build", "Project not initialized"))
 			entries = append(entries, buttonDisabled("diagnostic", "Project not initialized"))
@@ -0,0 +1,90 @@
+package screens
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	revertDiskOption   = "Reload from disk"
+	revertSavedOption  = "Restore last save"
+	revertCancelOption = "Cancel"
+)
+
+type revertChoiceMsg struct {
+	path   string
+	option string
+}
+
+// RevertFile откатывает активную вкладку к последнему сохранению (команда палитры и :e!).
+func (ps *ProjectScreenReal) RevertFile() tea.Cmd {
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		ps.setStatus("No file to revert")
+		return nil
+	}
+	return ps.requestRevert(tab)
+}
+
+// requestRevert спрашивает подтверждение: откат сбрасывает правки и историю undo.
+// Если файл изменился на диске, можно выбрать между диском и последним сохранением.
+func (ps *ProjectScreenReal) requestRevert(tab *editorTab) tea.Cmd {
+	if tab.scratch || tab.path == "" {
+		ps.setStatus("Scratch buffer has no file to revert to")
+		return nil
+	}
+	if tab.created {
+		ps.setStatus(tab.name + " has not been saved yet")
+		return nil
+	}
+	changed := tab.diskChanged()
+	if !tab.dirty && !changed {
+		ps.setStatus(tab.name + " already matches the last save")
+		return nil
+	}
+
+	desc := fmt.Sprintf("Discard unsaved changes in %s?\nUndo history will be cleared.", tab.name)
+	options := []string{revertDiskOption, revertCancelOption}
+	if changed && tab.savedLines != nil {
+		desc = fmt.Sprintf("%s changed on disk since the last save.\nUnsaved changes and undo history will be discarded.", tab.name)
+		options = []string{revertDiskOption, revertSavedOption, revertCancelOption}
+	}
+	ps.revertDialog.Description = desc
+	ps.revertDialog.Options = options
+	ch := ps.revertDialog.Show()
+	path := tab.path
+	return func() tea.Msg {
+		choice := <-ch
+		option := ""
+		if choice >= 0 && choice < len(options) {
+			option = options[choice]
+		}
+		return revertChoiceMsg{path: path, option: option}
+	}
+}
+
+func (ps *ProjectScreenReal) handleRevertChoice(msg revertChoiceMsg) tea.Cmd {
+	index := ps.findTabIndex(msg.path)
+	if index < 0 {
+		return nil
+	}
+	tab := ps.tabs[index]
+
+	switch msg.option {
+	case revertDiskOption:
+		if err := tab.revertFromDisk(); err != nil {
+			ps.setStatus(fmt.Sprintf("Revert failed: %v", err))
+			return nil
+		}
+		ps.setStatus("Reverted " + tab.name + " to the file on disk")
+	case revertSavedOption:
+		tab.revertToLastSave()
+		ps.setStatus("Restored last save of " + tab.name + " (differs from disk, save to keep it)")
+	default:
+		ps.setStatus("Revert canceled")
+		return nil
+	}
+	ps.ensureCursorVisible(tab)
+	return nil
+}
@@ -0,0 +1,202 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Откат буфера по времени (Rollback Buffer to…): диалог предлагает
+// последнее сохранение, состояния 1/5/15 минут назад и момент открытия файла
+// из контрольных точек вкладки (workspace_checkpoints.go), показывает diff
+// выбранного состояния с текущим и подменяет буфер одной правкой, которую
+// можно отменить u. Пока буфер изменён, точки снимаются по таймеру.
+
+const (
+	rollbackPreviewLines = 20
+	rollbackPreviewWidth = 72
+)
+
+// rollbackAges — насколько назад предлагается откатиться.
+var rollbackAges = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+
+// checkpointTickMsg — отсчёт до следующей контрольной точки вкладки истёк.
+type checkpointTickMsg struct {
+	tab *editorTab
+}
+
+// rollbackTarget — состояние буфера, к которому можно откатиться.
+type rollbackTarget struct {
+	label string
+	lines []string
+}
+
+type rollbackChoiceMsg struct {
+	tab     *editorTab
+	targets []rollbackTarget
+	choice  int
+}
+
+type rollbackConfirmMsg struct {
+	tab       *editorTab
+	target    rollbackTarget
+	confirmed bool
+}
+
+// trackCheckpoints запускает отсчёт до контрольной точки для изменённых
+// вкладок с новыми правками. Тики доходят только до активного экрана, так что
+// просроченный отсчёт считается потерянным и запускается заново.
+func (ps *ProjectScreenReal) trackCheckpoints() tea.Cmd {
+	now := time.Now()
+	var cmds []tea.Cmd
+	for _, tab := range ps.tabs {
+		if !tab.dirty || tab.edits == tab.checkpointEdits {
+			continue
+		}
+		if !tab.checkpointDue.IsZero() && now.Before(tab.checkpointDue.Add(checkpointInterval)) {
+			continue
+		}
+		tab.checkpointDue = now.Add(checkpointInterval)
+		msg := checkpointTickMsg{tab: tab}
+		cmds = append(cmds, tea.Tick(checkpointInterval, func(time.Time) tea.Msg { return msg }))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (ps *ProjectScreenReal) handleCheckpointTick(msg checkpointTickMsg) {
+	tab := msg.tab
+	tab.checkpointDue = time.Time{}
+	if tab.dirty {
+		tab.captureCheckpoint(time.Now())
+	}
+}
+
+// CanRollbackBuffer сообщает, есть ли вкладка, которую можно откатить.
+func (ps *ProjectScreenReal) CanRollbackBuffer() bool {
+	return ps.activeEditorTab() != nil
+}
+
+// RollbackBuffer предлагает откатить активную вкладку к одному из прошлых
+// состояний.
+func (ps *ProjectScreenReal) RollbackBuffer() tea.Cmd {
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		ps.setStatus("No buffer to roll back")
+		return nil
+	}
+	targets := rollbackTargets(tab, time.Now())
+	if len(targets) == 0 {
+		ps.setStatus(tab.name + " has no earlier versions yet")
+		return nil
+	}
+	options := make([]string, 0, len(targets)+1)
+	for _, target := range targets {
+		options = append(options, target.label+"  "+diffSummary(tab.lines, target.lines))
+	}
+	options = append(options, "Cancel")
+	ps.rollbackDialog.Description = fmt.Sprintf("Roll back %s to an earlier version.\nThe rollback can be undone with u.", tab.name)
+	ps.rollbackDialog.Options = options
+	ch := ps.rollbackDialog.Show()
+	return func() tea.Msg {
+		return rollbackChoiceMsg{tab: tab, targets: targets, choice: <-ch}
+	}
+}
+
+// rollbackTargets собирает варианты отката от нового к старому, пропуская
+// совпадающие с текущим буфером и друг с другом.
+func rollbackTargets(tab *editorTab, now time.Time) []rollbackTarget {
+	var targets []rollbackTarget
+	add := func(label string, lines []string) {
+		if slices.Equal(lines, tab.lines) {
+			return
+		}
+		for _, t := range targets {
+			if slices.Equal(t.lines, lines) {
+				return
+			}
+		}
+		targets = append(targets, rollbackTarget{label: label, lines: lines})
+	}
+
+	if !tab.scratch && !tab.created && tab.savedLines != nil {
+		label := "Last save"
+		if !tab.savedAt.IsZero() {
+			label += " (" + tab.savedAt.Format("15:04:05") + ")"
+		}
+		add(label, tab.savedLines)
+	}
+	for _, age := range rollbackAges {
+		if cp, ok := tab.checkpointAt(now.Add(-age)); ok {
+			add(fmt.Sprintf("%s ago (%s)", plural(int(age/time.Minute), "minute"), cp.at.Format("15:04:05")), cp.lines)
+		}
+	}
+	if len(tab.checkpoints) > 0 {
+		first := tab.checkpoints[0]
+		add("File open ("+first.at.Format("15:04")+")", first.lines)
+	}
+	return targets
+}
+
+// diffSummary кратко описывает, сколько строк добавит и уберёт переход от a к b.
+func diffSummary(a, b []string) string {
+	added, removed := 0, 0
+	for _, h := range diffLines(a, b) {
+		added += h.count
+		removed += h.savedCount
+	}
+	return fmt.Sprintf("+%d −%d", added, removed)
+}
+
+func (ps *ProjectScreenReal) handleRollbackChoice(msg rollbackChoiceMsg) tea.Cmd {
+	if msg.choice < 0 || msg.choice >= len(msg.targets) || !ps.hasTab(msg.tab) {
+		return nil
+	}
+	target := msg.targets[msg.choice]
+	diff := unifiedDiff(msg.tab.lines, target.lines, 1)
+	preview := make([]string, 0, rollbackPreviewLines+1)
+	for _, line := range diff[:min(len(diff), rollbackPreviewLines)] {
+		preview = append(preview, truncateString(line, rollbackPreviewWidth))
+	}
+	if rest := len(diff) - rollbackPreviewLines; rest > 0 {
+		preview = append(preview, "… "+plural(rest, "more line"))
+	}
+	ps.rollbackDiff.Title = "Roll back to " + lowerFirst(target.label) + "?"
+	ps.rollbackDiff.Description = strings.Join(preview, "\n")
+	ps.rollbackDiff.ConfirmText = "Restore"
+	ps.rollbackDiff.CancelText = "Cancel"
+	ch := ps.rollbackDiff.Show()
+	tab := msg.tab
+	return func() tea.Msg {
+		return rollbackConfirmMsg{tab: tab, target: target, confirmed: <-ch}
+	}
+}
+
+func (ps *ProjectScreenReal) handleRollbackConfirm(msg rollbackConfirmMsg) {
+	if !msg.confirmed {
+		ps.setStatus("Rollback canceled")
+		return
+	}
+	if !ps.hasTab(msg.tab) {
+		return
+	}
+	tab := msg.tab
+	before := tab.snapshot()
+	tab.stopVisual()
+	tab.clearPending()
+	tab.lines = slices.Clone(msg.target.lines)
+	tab.markModified()
+	tab.remapCursor(before.lines)
+	tab.pushSnapshot(before)
+	if tab == ps.activeEditorTab() {
+		ps.ensureCursorVisible(tab)
+	}
+	ps.setStatus(fmt.Sprintf("Rolled back %s to %s (u to undo)", tab.name, lowerFirst(msg.target.label)))
+}
+
+// lowerFirst переводит первую букву подписи в нижний регистр для середины фразы.
+func lowerFirst(label string) string {
+	return strings.ToLower(label[:1]) + label[1:]
+}
@@ -0,0 +1,192 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// :saveas path записывает буфер вкладки в другой файл и переводит вкладку
+// на него; исходный файл остаётся как был на диске. С --move (или командой
+// палитры «Move File…») исходный файл после записи удаляется. Вкладка
+// остаётся той же: курсор, история undo и позиция не теряются.
+// Запись буфера вместо rename работает и между файловыми системами.
+// С --copy (или «Copy to New File…») пишется только копия, и вкладка
+// остаётся на своём файле; см. также project_extract.go.
+
+// saveAsRequest — запись вкладки под новым путём.
+type saveAsRequest struct {
+	tab     *editorTab
+	path    string
+	move    bool
+	copy    bool        // записать копию буфера; вкладка остаётся на своём файле
+	extract *extraction // записать только выделение и убрать его из буфера
+}
+
+type saveAsOverwriteMsg struct {
+	req       saveAsRequest
+	confirmed bool
+}
+
+type moveFileConfirmedMsg struct {
+	tab   *editorTab
+	value *string
+}
+
+// CanMoveFile сообщает, есть ли вкладка с файлом, который можно перенести.
+func (ps *ProjectScreenReal) CanMoveFile() bool {
+	tab := ps.activeEditorTab()
+	return tab != nil && !tab.scratch
+}
+
+// MoveFile запрашивает новый путь файла активной вкладки.
+func (ps *ProjectScreenReal) MoveFile() tea.Cmd {
+	tab := ps.activeEditorTab()
+	if tab == nil || tab.scratch || ps.moveDialog == nil {
+		ps.setStatus("No file to move")
+		return nil
+	}
+	ch := ps.moveDialog.ShowWithValue(ps.relativePath(tab.path))
+	return func() tea.Msg {
+		return moveFileConfirmedMsg{tab: tab, value: <-ch}
+	}
+}
+
+func (ps *ProjectScreenReal) handleMoveFileConfirmed(msg moveFileConfirmedMsg) tea.Cmd {
+	if msg.value == nil || strings.TrimSpace(*msg.value) == "" || ps.findTabIndex(msg.tab.path) < 0 {
+		return nil
+	}
+	return ps.saveTabAs(saveAsRequest{tab: msg.tab, path: *msg.value, move: true}, false)
+}
+
+// saveAsCommand разбирает аргументы :saveas — путь и необязательный --move.
+func (ps *ProjectScreenReal) saveAsCommand(tab *editorTab, args string, force bool) tea.Cmd {
+	req := saveAsRequest{tab: tab}
+	for _, field := range strings.Fields(args) {
+		switch {
+		case field == "--move":
+			req.move = true
+		case field == "--copy":
+			req.copy = true
+		case req.path == "":
+			req.path = field
+		default:
+			ps.setStatus("Usage: :saveas path [--move | --copy]")
+			return nil
+		}
+	}
+	if req.path == "" || (req.move && req.copy) {
+		ps.setStatus("Usage: :saveas path [--move | --copy]")
+		return nil
+	}
+	if tab.scratch && !req.copy {
+		// у scratch-буфера нечего переносить: обычный Save As
+		return ps.handleSaveAs(saveAsConfirmedMsg{value: &req.path})
+	}
+	return ps.saveTabAs(req, force)
+}
+
+// saveTabAs проверяет новый путь; существующий файл заменяется только
+// после подтверждения (или с :saveas!).
+func (ps *ProjectScreenReal) saveTabAs(req saveAsRequest, force bool) tea.Cmd {
+	path := strings.TrimSpace(req.path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(ps.projectPath, path)
+	}
+	req.path = filepath.Clean(path)
+
+	switch {
+	case req.path == req.tab.path && (req.copy || req.extract != nil):
+		ps.setStatus("Choose a path other than " + req.tab.name)
+		return nil
+	case req.path == req.tab.path:
+		ps.setStatus(fmt.Sprintf("%s is already at %s", req.tab.name, ps.relativePath(req.path)))
+		return nil
+	case ps.findTabIndex(req.path) >= 0:
+		ps.setStatus(fmt.Sprintf("%s is open in another tab", filepath.Base(req.path)))
+		return nil
+	case ps.saveBlocked(req.tab):
+		return nil
+	}
+	info, err := os.Stat(req.path)
+	if err == nil && info.IsDir() {
+		ps.setStatus(fmt.Sprintf("%s is a directory", ps.relativePath(req.path)))
+		return nil
+	}
+	if err == nil && !force && ps.saveAsConfirm != nil {
+		ps.saveAsConfirm.Description = fmt.Sprintf("%s already exists. Replace it?", ps.relativePath(req.path))
+		ch := ps.saveAsConfirm.Show()
+		return func() tea.Msg {
+			return saveAsOverwriteMsg{req: req, confirmed: <-ch}
+		}
+	}
+	return ps.writeTabAs(req)
+}
+
+func (ps *ProjectScreenReal) handleSaveAsOverwrite(msg saveAsOverwriteMsg) tea.Cmd {
+	if !msg.confirmed || ps.findTabIndex(msg.req.tab.path) < 0 {
+		return nil
+	}
+	return ps.writeTabAs(msg.req)
+}
+
+// writeTabAs записывает буфер под новым путём и переводит на него вкладку.
+// Если исходный файл удалить не удалось, копия остаётся, а в статусе
+// сообщается, что перенос выполнен не до конца.
+func (ps *ProjectScreenReal) writeTabAs(req saveAsRequest) tea.Cmd {
+	if req.copy || req.extract != nil {
+		return ps.writeNewFile(req)
+	}
+	tab, oldPath := req.tab, req.tab.path
+	if err := os.MkdirAll(filepath.Dir(req.path), 0o755); err != nil {
+		return ps.saveFailed(err)
+	}
+	var perm os.FileMode
+	if info, err := os.Stat(oldPath); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	ps.retargetTabs(oldPath, req.path)
+	if err := tab.save(); err != nil {
+		ps.retargetTabs(req.path, oldPath)
+		return ps.saveFailed(err)
+	}
+	if perm != 0 {
+		_ = os.Chmod(req.path, perm)
+	}
+
+	rel := ps.relativePath(req.path)
+	if !req.move {
+		ps.setStatus("Saved as " + rel)
+	} else if err := removeMoved(oldPath); err != nil {
+		ps.setStatus(fmt.Sprintf("Saved as %s, but %s could not be removed: %v", rel, ps.relativePath(oldPath), err))
+	} else {
+		ps.setStatus("Moved to " + rel)
+	}
+
+	cmds := []tea.Cmd{ps.afterSave(tab)}
+	if ps.fileTree != nil {
+		cmds = append(cmds, ps.readTreeDirs([]string{filepath.Dir(req.path), filepath.Dir(oldPath)}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// removeMoved удаляет исходный файл после переноса; уже удалённый — не ошибка.
+func removeMoved(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// relativePath возвращает путь относительно корня проекта, если он внутри него.
+func (ps *ProjectScreenReal) relativePath(path string) string {
+	rel, err := filepath.Rel(ps.projectPath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
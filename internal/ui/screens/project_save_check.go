@@ -0,0 +1,133 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Перед сохранением из редактора (Ctrl+S, :w, :wq) буфер проверяется на
+// маркеры конфликта слияния (editor.warn_conflict_markers) и, если включено
+// editor.warn_errors_on_save, на строки с ошибками последнего surge diag.
+// Найденное не сохраняется молча: диалог спрашивает, записать ли файл.
+// Автосохранение и записи по команде (fmt, внешний редактор) не проверяются.
+
+// saveWarningMsg — ответ диалога предупреждения перед сохранением.
+type saveWarningMsg struct {
+	path       string
+	closeAfter bool
+	confirmed  bool
+}
+
+// saveProblems — что нашлось в буфере перед сохранением; строки 0-based,
+// -1 — ничего.
+type saveProblems struct {
+	conflicts    int // открывающих маркеров <<<<<<<
+	conflictLine int
+	errors       int // строк с ошибками
+	errorLine    int
+}
+
+// checkSave ищет в буфере вкладки то, о чём стоит предупредить перед записью.
+func (ps *ProjectScreenReal) checkSave(tab *editorTab) saveProblems {
+	p := saveProblems{conflictLine: -1, errorLine: -1}
+	if ps.editorCfg.WarnConflictMarkers {
+		p.conflicts, p.conflictLine = conflictMarkers(tab.lines)
+	}
+	if ps.editorCfg.WarnErrorsOnSave {
+		lines := make(map[int]bool)
+		for _, d := range tab.diagnostics {
+			if d.Severity != "error" || d.Line >= len(tab.lines) {
+				continue
+			}
+			lines[d.Line] = true
+			if p.errorLine < 0 || d.Line < p.errorLine {
+				p.errorLine = d.Line
+			}
+		}
+		p.errors = len(lines)
+	}
+	return p
+}
+
+func (p saveProblems) empty() bool {
+	return p.conflicts == 0 && p.errors == 0
+}
+
+// firstLine — строка, к которой стоит перейти, чтобы разобраться.
+func (p saveProblems) firstLine() int {
+	if p.conflicts > 0 {
+		return p.conflictLine
+	}
+	return p.errorLine
+}
+
+// conflictMarkers считает конфликты слияния по открывающим маркерам
+// <<<<<<< и возвращает строку первого из них. Одиночное «=======» (например,
+// подчёркивание заголовка Markdown) конфликтом не считается.
+func conflictMarkers(lines []string) (count, first int) {
+	first = -1
+	for i, line := range lines {
+		if !isConflictMarker(line, "<<<<<<<") {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		count++
+	}
+	return count, first
+}
+
+func isConflictMarker(line, marker string) bool {
+	rest, ok := strings.CutPrefix(line, marker)
+	return ok && (rest == "" || rest[0] == ' ')
+}
+
+// confirmSaveWarning спрашивает, сохранить ли вкладку, несмотря на найденное.
+func (ps *ProjectScreenReal) confirmSaveWarning(tab *editorTab, p saveProblems, closeAfter bool) tea.Cmd {
+	var found []string
+	if p.conflicts > 0 {
+		found = append(found, fmt.Sprintf("%s (first at line %d)", plural(p.conflicts, "merge conflict"), p.conflictLine+1))
+	}
+	if p.errors > 0 {
+		found = append(found, fmt.Sprintf("%s from the last diagnostics run (first at line %d)",
+			plural(p.errors, "line")+" with errors", p.errorLine+1))
+	}
+	title := "File contains conflict markers — save anyway?"
+	if p.conflicts == 0 {
+		title = "File has errors — save anyway?"
+	}
+	ps.saveWarnDialog.Title = title
+	ps.saveWarnDialog.Description = fmt.Sprintf("%s contains:\n  • %s", tab.name, strings.Join(found, "\n  • "))
+	ps.saveWarnDialog.ConfirmText = "Save"
+	ps.saveWarnDialog.CancelText = "Cancel"
+	ch := ps.saveWarnDialog.Show()
+	path := tab.path
+	return func() tea.Msg {
+		return saveWarningMsg{path: path, closeAfter: closeAfter, confirmed: <-ch}
+	}
+}
+
+// handleSaveWarning сохраняет вкладку после подтверждения, а при отказе
+// переводит курсор к первому найденному месту.
+func (ps *ProjectScreenReal) handleSaveWarning(msg saveWarningMsg) tea.Cmd {
+	index := ps.findTabIndex(msg.path)
+	if index < 0 {
+		return nil
+	}
+	tab := ps.tabs[index]
+	if msg.confirmed {
+		return ps.saveChecked(tab, msg.closeAfter)
+	}
+	if line := ps.checkSave(tab).firstLine(); line >= 0 && tab == ps.activeEditorTab() {
+		tab.cursor = cursorPosition{Line: line}
+		tab.clampCursor()
+		ps.ensureCursorVisible(tab)
+		ps.setStatus(fmt.Sprintf("Save cancelled; see line %d", line+1))
+		return nil
+	}
+	ps.setStatus("Save cancelled")
+	return nil
+}
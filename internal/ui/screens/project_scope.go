@@ -0,0 +1,42 @@
+package screens
+
+import (
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/fs"
+)
+
+// inSurgeProject сообщает, лежит ли каталог dir в инициализированном
+// проекте: surge.toml есть в нём или в одном из родителей до корня
+// открытого проекта.
+func (ps *ProjectScreenReal) inSurgeProject(dir string) bool {
+	for d := filepath.Clean(dir); fs.IsWithin(d, ps.projectPath); d = filepath.Dir(d) {
+		if ps.isProjectDirectory(d) {
+			return true
+		}
+		if samePath(d, ps.projectPath) {
+			break
+		}
+	}
+	return false
+}
+
+// runInSelectedDir запускает диагностику (или, с fix, применение всех
+// фиксов) только для выбранного в дереве каталога.
+func (ps *ProjectScreenReal) runInSelectedDir(fix bool) tea.Cmd {
+	node := ps.selectedDirectoryNode()
+	if node == nil {
+		ps.setStatus("Select a directory first")
+		return nil
+	}
+	if !ps.inSurgeProject(node.Path) {
+		ps.setStatus("Not in a surge project: surge.toml is missing")
+		return nil
+	}
+	dir := node.Path
+	if fix {
+		return func() tea.Msg { return FixDirMsg{Dir: dir} }
+	}
+	return func() tea.Msg { return DiagnoseDirMsg{Dir: dir} }
+}
@@ -0,0 +1,190 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Scratch-буфер — вкладка без файла на диске. Живёт до конца сессии,
+// при сохранении запрашивает путь (Save As) и превращается в обычную вкладку.
+
+const scratchTabName = "*scratch*"
+
+type saveAsConfirmedMsg struct {
+	value      *string
+	closeAfter bool
+}
+
+func newScratchTab() *editorTab {
+	tab := &editorTab{
+		name:    scratchTabName,
+		lines:   []string{""},
+		mode:    editorModeNormal,
+		scratch: true,
+	}
+	tab.keepCheckpoint(time.Now(), tab.lines, true)
+	tab.highlightFrom = -1
+	return tab
+}
+
+// scratchTabIndex возвращает индекс scratch-вкладки или -1.
+func (ps *ProjectScreenReal) scratchTabIndex() int {
+	for i, tab := range ps.tabs {
+		if tab.scratch {
+			return i
+		}
+	}
+	return -1
+}
+
+// OpenScratchBuffer открывает scratch-буфер; если он уже есть, переключается на него.
+func (ps *ProjectScreenReal) OpenScratchBuffer() {
+	if idx := ps.scratchTabIndex(); idx >= 0 {
+		ps.setActiveTab(idx)
+		ps.focusedPanel = EditorPanel
+		ps.recalculateLayout()
+		return
+	}
+	tab := newScratchTab()
+	ps.tabs = append(ps.tabs, tab)
+	ps.activeTab = len(ps.tabs) - 1
+	ps.focusedPanel = EditorPanel
+	ps.recalculateLayout()
+	ps.setStatus("Opened " + scratchTabName)
+}
+
+// UnsavedTabs возвращает имена вкладок с несохранёнными изменениями.
+func (ps *ProjectScreenReal) UnsavedTabs() []string {
+	var names []string
+	for _, tab := range ps.tabs {
+		if tab.dirty {
+			names = append(names, tab.name)
+		}
+	}
+	return names
+}
+
+// UnsavedFiles возвращает пути файлов с несохранёнными изменениями;
+// для буферов без файла — имя вкладки.
+func (ps *ProjectScreenReal) UnsavedFiles() []string {
+	var paths []string
+	for _, tab := range ps.tabs {
+		if !tab.dirty {
+			continue
+		}
+		if tab.path == "" {
+			paths = append(paths, tab.name)
+		} else {
+			paths = append(paths, tab.path)
+		}
+	}
+	return paths
+}
+
+// SaveFile сохраняет вкладку файла path без диалогов; вкладке, которой нужно
+// подтверждение, возвращается ошибка. Неоткрытый файл пропускается.
+func (ps *ProjectScreenReal) SaveFile(path string) (tea.Cmd, error) {
+	index := ps.findTabIndex(path)
+	if index < 0 || !ps.tabs[index].dirty {
+		return nil, nil
+	}
+	tab := ps.tabs[index]
+	switch {
+	case ps.saveBlocked(tab):
+		return nil, errors.New("project unavailable")
+	case tab.diskChanged():
+		return nil, errors.New("changed on disk")
+	case tab.isLossy():
+		return nil, errors.New("would lose characters in its encoding")
+	}
+	if err := tab.save(); err != nil {
+		return nil, err
+	}
+	ps.setStatus("Saved " + tab.name)
+	return ps.afterSave(tab), nil
+}
+
+// SaveAllTabs сохраняет все изменённые вкладки без диалогов. Вкладки, которым
+// нужно подтверждение (буфер без файла, файл изменён на диске, потери при
+// перекодировании, недоступный проект), не записываются: ошибка называет
+// первую из них, и запись не начинается.
+func (ps *ProjectScreenReal) SaveAllTabs() error {
+	var dirty []*editorTab
+	for _, tab := range ps.tabs {
+		if !tab.dirty {
+			continue
+		}
+		switch {
+		case tab.scratch:
+			return fmt.Errorf("%s has no file; save it with :saveas", tab.name)
+		case ps.saveBlocked(tab):
+			return fmt.Errorf("%s: project unavailable", tab.name)
+		case tab.diskChanged():
+			return fmt.Errorf("%s changed on disk", tab.name)
+		case tab.isLossy():
+			return fmt.Errorf("%s would lose characters in its encoding", tab.name)
+		}
+		dirty = append(dirty, tab)
+	}
+	for _, tab := range dirty {
+		if err := tab.save(); err != nil {
+			return fmt.Errorf("%s: %w", tab.name, err)
+		}
+	}
+	if len(dirty) > 0 {
+		ps.setStatus("Saved " + plural(len(dirty), "file"))
+	}
+	return nil
+}
+
+// requestSaveAs запрашивает путь для вкладки без файла.
+func (ps *ProjectScreenReal) requestSaveAs(closeAfter bool) tea.Cmd {
+	if ps.saveAsDialog == nil {
+		return nil
+	}
+	ch := ps.saveAsDialog.Show()
+	return func() tea.Msg {
+		value := <-ch
+		return saveAsConfirmedMsg{value: value, closeAfter: closeAfter}
+	}
+}
+
+// handleSaveAs привязывает scratch-буфер к файлу и сохраняет его.
+// Относительный путь отсчитывается от корня проекта; существующие файлы не перезаписываются.
+func (ps *ProjectScreenReal) handleSaveAs(msg saveAsConfirmedMsg) tea.Cmd {
+	if msg.value == nil || strings.TrimSpace(*msg.value) == "" {
+		return nil
+	}
+	index := ps.scratchTabIndex()
+	if index < 0 {
+		return nil
+	}
+	tab := ps.tabs[index]
+
+	path := strings.TrimSpace(*msg.value)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(ps.projectPath, path)
+	}
+	path = filepath.Clean(path)
+	if _, err := os.Stat(path); err == nil {
+		ps.setStatus(fmt.Sprintf("%s already exists", filepath.Base(path)))
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return ps.saveFailed(err)
+	}
+
+	tab.path = path
+	tab.name = filepath.Base(path)
+	tab.scratch = false
+	tab.created = true
+	tab.markModified() // подсветка теперь выбирается по расширению
+	tab.setDiagnostics(diagnosticsForPath(ps.diagnostics, path))
+	return tea.Batch(ps.saveTab(tab, msg.closeAfter), ps.loadFileTree())
+}
@@ -0,0 +1,108 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+
+	"surge-tui/internal/config"
+)
+
+// Сессия проекта: открытые вкладки, позиции курсора и панель с фокусом.
+// Файл сессии переписывается при открытии и закрытии вкладок, а при выходе
+// приложение дописывает в него текущий экран (SaveSession).
+
+// RestoreSessionMsg — открыть вкладки сохранённой сессии.
+type RestoreSessionMsg struct {
+	Session *config.Session
+}
+
+// SetSessionEnabled включает запись сессии (startup.restore_session).
+func (ps *ProjectScreenReal) SetSessionEnabled(enabled bool) {
+	ps.sessionEnabled = enabled
+}
+
+// SaveSession записывает сессию вместе с экраном, на котором вышли.
+func (ps *ProjectScreenReal) SaveSession(screen string) {
+	ps.sessionScreen = screen
+	ps.saveSession()
+}
+
+func (ps *ProjectScreenReal) saveSession() {
+	if !ps.sessionEnabled || ps.restoringSession || ps.projectPath == "" {
+		return
+	}
+	session := &config.Session{
+		Project:   ps.projectPath,
+		ActiveTab: -1,
+		Focus:     "tree",
+		Screen:    ps.sessionScreen,
+	}
+	if ps.focusedPanel == EditorPanel {
+		session.Focus = "editor"
+	}
+	for i, tab := range ps.tabs {
+		if tab.scratch {
+			continue
+		}
+		if i == ps.activeTab {
+			session.ActiveTab = len(session.Tabs)
+		}
+		session.Tabs = append(session.Tabs, config.SessionTab{
+			Path:   tab.path,
+			Line:   tab.cursor.Line + 1,
+			Column: tab.cursor.Col + 1,
+			Scroll: tab.scroll,
+		})
+	}
+	_ = config.SaveSession(session)
+}
+
+// restoreSession открывает вкладки сессии; пропавшие файлы пропускаются.
+func (ps *ProjectScreenReal) restoreSession(session *config.Session) {
+	if session == nil {
+		return
+	}
+	ps.sessionScreen = session.Screen
+	ps.restoringSession = true
+	defer func() { ps.restoringSession = false }()
+
+	active, restored, missing := -1, 0, 0
+	for i, saved := range session.Tabs {
+		if info, err := os.Stat(saved.Path); err != nil || info.IsDir() {
+			missing++
+			continue
+		}
+		tab := ps.openFileTab(saved.Path)
+		if tab == nil {
+			missing++
+			continue
+		}
+		restored++
+		tab.setCursorPosition(max(saved.Line, 1), max(saved.Column, 1))
+		tab.scroll = clampInt(saved.Scroll, 0, max(len(tab.lines)-1, 0))
+		ps.ensureCursorVisible(tab)
+		if i == session.ActiveTab {
+			active = len(ps.tabs) - 1
+		}
+	}
+	if restored == 0 {
+		ps.focusedPanel = FileTreePanel
+	} else {
+		if active >= 0 {
+			ps.setActiveTab(active)
+		}
+		if session.Focus != "editor" {
+			ps.focusedPanel = FileTreePanel
+		}
+	}
+	ps.recalculateLayout()
+
+	switch {
+	case restored > 0 && missing > 0:
+		ps.setStatus(fmt.Sprintf("Restored %s; %d no longer exist", plural(restored, "tab"), missing))
+	case restored > 0:
+		ps.setStatus("Restored " + plural(restored, "tab"))
+	case missing > 0:
+		ps.setStatus(fmt.Sprintf("Session files no longer exist (%d)", missing))
+	}
+}
@@ -0,0 +1,190 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+	"surge-tui/internal/ui/components"
+)
+
+// Переключение между исходником и его тестом: foo.sg ↔ foo_test.sg.
+// Тест ищется сначала рядом с исходником, затем в зеркальном каталоге
+// tests/ от корня проекта (tests/pkg/foo_test.sg для pkg/foo.sg). Найденная
+// пара запоминается, и следующие переключения идут по ней без поиска.
+// Отсутствующий тест предлагается создать по шаблону.
+
+// testToggle — настройки и найденные пары переключения файл ↔ тест.
+type testToggle struct {
+	cfg     config.TestsConfig
+	pairs   map[string]string         // исходник ↔ тест, найденные переключением
+	confirm *components.ConfirmDialog // создать отсутствующий тест по шаблону
+}
+
+func newTestToggle(cfg config.TestsConfig) testToggle {
+	return testToggle{
+		cfg:     cfg,
+		pairs:   make(map[string]string),
+		confirm: components.NewConfirmDialog("Create Test", ""),
+	}
+}
+
+// remember запоминает пару в обе стороны.
+func (tt *testToggle) remember(a, b string) {
+	tt.pairs[a] = b
+	tt.pairs[b] = a
+}
+
+type testFileCreateMsg struct {
+	source    string
+	path      string
+	confirmed bool
+}
+
+// SetTestsConfig задаёт поиск парных тестовых файлов (секция tests).
+func (ps *ProjectScreenReal) SetTestsConfig(cfg config.TestsConfig) {
+	ps.tests.cfg = cfg
+	ps.tests.pairs = make(map[string]string)
+}
+
+// CanToggleTestFile сообщает, открыт ли в редакторе файл проекта.
+func (ps *ProjectScreenReal) CanToggleTestFile() bool {
+	tab := ps.activeEditorTab()
+	return tab != nil && !tab.scratch
+}
+
+// ToggleTestFile открывает тест активного файла или исходник активного теста.
+func (ps *ProjectScreenReal) ToggleTestFile() tea.Cmd {
+	if !ps.CanToggleTestFile() {
+		ps.setStatus("Open a file first")
+		return nil
+	}
+	current := ps.activeEditorTab().path
+	if pair, ok := ps.tests.pairs[current]; ok && ps.pathAvailable(pair) {
+		ps.openFileTab(pair)
+		return nil
+	}
+
+	candidates, isTest := ps.testFileCandidates(current)
+	for _, candidate := range candidates {
+		if ps.pathAvailable(candidate) {
+			ps.tests.remember(current, candidate)
+			ps.openFileTab(candidate)
+			return nil
+		}
+	}
+	if isTest || len(candidates) == 0 {
+		ps.setStatus("No source file for " + filepath.Base(current))
+		return nil
+	}
+
+	path := ps.newTestFilePath(candidates)
+	ps.tests.confirm.Description = fmt.Sprintf("No test for %s. Create %s?", filepath.Base(current), ps.relativePath(path))
+	ch := ps.tests.confirm.Show()
+	return func() tea.Msg {
+		return testFileCreateMsg{source: current, path: path, confirmed: <-ch}
+	}
+}
+
+// handleTestFileCreate создаёт тест по шаблону и открывает его.
+func (ps *ProjectScreenReal) handleTestFileCreate(msg testFileCreateMsg) tea.Cmd {
+	if !msg.confirmed {
+		return nil
+	}
+	if _, err := os.Stat(msg.path); err == nil {
+		// файл появился, пока был открыт диалог
+		ps.tests.remember(msg.source, msg.path)
+		ps.openFileTab(msg.path)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(msg.path), 0o755); err != nil {
+		ps.setStatus(fmt.Sprintf("Create failed: %v", err))
+		return nil
+	}
+	tab := ps.openFileTab(msg.path)
+	if tab == nil {
+		return nil
+	}
+	ps.tests.remember(msg.source, msg.path)
+	if !tab.created {
+		return nil
+	}
+	tab.lines = strings.Split(ps.testTemplate(msg.source), "\n")
+	tab.markModified()
+	tab.clampCursor()
+	return tea.Batch(ps.saveTab(tab, false), ps.loadFileTree())
+}
+
+// testFileCandidates возвращает пути парного файла в порядке поиска и
+// сообщает, является ли path тестом.
+func (ps *ProjectScreenReal) testFileCandidates(path string) ([]string, bool) {
+	suffix := ps.tests.cfg.Suffix
+	dir, base := filepath.Split(path)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	isTest := strings.HasSuffix(stem, suffix) && stem != suffix
+
+	name := stem + suffix + ext
+	if isTest {
+		name = strings.TrimSuffix(stem, suffix) + ext
+	}
+	candidates := []string{filepath.Join(dir, name)}
+	if mirrored, ok := ps.mirroredTestDir(filepath.Clean(dir), isTest); ok {
+		candidates = append(candidates, filepath.Join(mirrored, name))
+	}
+	return candidates, isTest
+}
+
+// mirroredTestDir переводит каталог исходника в каталог тестов (pkg →
+// tests/pkg) или обратно, если fromTests. ok == false, когда каталог вне
+// проекта, зеркальная раскладка выключена или тест лежит не в ней.
+func (ps *ProjectScreenReal) mirroredTestDir(dir string, fromTests bool) (string, bool) {
+	if ps.projectPath == "" || ps.tests.cfg.Dir == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(ps.projectPath, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	testsDir := filepath.FromSlash(ps.tests.cfg.Dir)
+	inTests := rel == testsDir || strings.HasPrefix(rel, testsDir+string(filepath.Separator))
+	switch {
+	case fromTests && inTests:
+		return filepath.Join(ps.projectPath, strings.TrimPrefix(rel, testsDir)), true
+	case !fromTests && !inTests:
+		return filepath.Join(ps.projectPath, testsDir, rel), true
+	}
+	return "", false
+}
+
+// newTestFilePath выбирает место нового теста: зеркальный каталог, если
+// в проекте уже есть каталог тестов, иначе рядом с исходником.
+func (ps *ProjectScreenReal) newTestFilePath(candidates []string) string {
+	if len(candidates) > 1 && ps.tests.cfg.Dir != "" {
+		if info, err := os.Stat(filepath.Join(ps.projectPath, filepath.FromSlash(ps.tests.cfg.Dir))); err == nil && info.IsDir() {
+			return candidates[1]
+		}
+	}
+	return candidates[0]
+}
+
+// testTemplate подставляет в шаблон теста {name} и {file} исходника.
+func (ps *ProjectScreenReal) testTemplate(source string) string {
+	name := strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	return strings.NewReplacer(
+		"{name}", name,
+		"{file}", filepath.ToSlash(ps.relativePath(source)),
+	).Replace(ps.tests.cfg.Template)
+}
+
+// pathAvailable сообщает, есть ли файл на диске или открытая вкладка с ним.
+func (ps *ProjectScreenReal) pathAvailable(path string) bool {
+	if ps.findTabIndex(path) >= 0 {
+		return true
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,219 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/fs"
+)
+
+// Метки диагностик в дереве: у файла — самая серьёзная его диагностика,
+// у каталога — самая серьёзная среди вложенных файлов. Легенда (d в дереве)
+// объясняет цвета, задаёт порог меток и фильтр «только файлы с диагностиками».
+// Состояние живёт до конца сессии и переживает перечитывание дерева.
+
+// Порог меток: показываются уровни с рангом severityRank не выше порога.
+const (
+	treeBadgesOff    = -1
+	treeBadgesErrors = 0
+	treeBadgesWarn   = 1
+	treeBadgesAll    = 2
+)
+
+var treeBadgeLevels = []struct {
+	level int
+	label string
+}{
+	{treeBadgesAll, "all"},
+	{treeBadgesWarn, "warnings and errors"},
+	{treeBadgesErrors, "errors only"},
+	{treeBadgesOff, "off"},
+}
+
+// treeDiagFilterChangedMsg — в легенде изменили порог или фильтр.
+type treeDiagFilterChangedMsg struct{}
+
+// treeDiagLegend — поповер легенды и фильтра меток дерева.
+type treeDiagLegend struct {
+	Visible bool
+
+	level         int  // порог меток
+	onlyWithDiags bool // в дереве только файлы с диагностиками (с учётом порога)
+	row           int  // 0 — порог, 1 — фильтр
+	files         [3]int
+}
+
+func newTreeDiagLegend() *treeDiagLegend {
+	return &treeDiagLegend{level: treeBadgesAll}
+}
+
+func (l *treeDiagLegend) Show() { l.Visible = true }
+func (l *treeDiagLegend) Hide() { l.Visible = false }
+
+func (l *treeDiagLegend) changed() tea.Cmd {
+	return func() tea.Msg { return treeDiagFilterChangedMsg{} }
+}
+
+// shows сообщает, проходит ли ранг severityRank через порог.
+func (l *treeDiagLegend) shows(rank int) bool {
+	return rank <= l.level
+}
+
+// filtering сообщает, что дерево сужено до файлов с диагностиками.
+func (l *treeDiagLegend) filtering() bool {
+	return l.onlyWithDiags && l.level != treeBadgesOff
+}
+
+func (l *treeDiagLegend) levelIndex() int {
+	for i, item := range treeBadgeLevels {
+		if item.level == l.level {
+			return i
+		}
+	}
+	return 0
+}
+
+func (l *treeDiagLegend) Update(msg tea.Msg) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || !l.Visible {
+		return nil
+	}
+	keyName := key.String()
+	switch keyName {
+	case "esc", "d", "q":
+		l.Hide()
+	case "up", "k", "down", "j", "tab":
+		l.row = 1 - l.row
+	case "left", "h", "right", "l", " ", "enter":
+		if l.row == 1 {
+			l.onlyWithDiags = !l.onlyWithDiags
+			return l.changed()
+		}
+		step := 1
+		if keyName == "left" || keyName == "h" {
+			step = -1
+		}
+		index := (l.levelIndex() + step + len(treeBadgeLevels)) % len(treeBadgeLevels)
+		l.level = treeBadgeLevels[index].level
+		return l.changed()
+	}
+	return nil
+}
+
+func (l *treeDiagLegend) View() string {
+	if !l.Visible {
+		return ""
+	}
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(DimTextColor))
+	badge := func(color string) string {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render("●")
+	}
+	row := func(index int, text string) string {
+		if index == l.row {
+			return lipgloss.NewStyle().Foreground(lipgloss.Color(selectedColor)).Bold(true).Render("→ " + text)
+		}
+		return "  " + text
+	}
+
+	check := "[ ]"
+	if l.onlyWithDiags {
+		check = "[x]"
+	}
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Render("Diagnostics in tree"),
+		"",
+		fmt.Sprintf("%s error    %s", badge(diagErrorColor), dim.Render(plural(l.files[0], "file"))),
+		fmt.Sprintf("%s warning  %s", badge(diagWarningColor), dim.Render(plural(l.files[1], "file"))),
+		fmt.Sprintf("%s info     %s", badge(diagInfoColor), dim.Render(plural(l.files[2], "file"))),
+		dim.Render("A directory shows the worst badge of the files inside it."),
+		"",
+		row(0, "Badges: ‹ "+treeBadgeLevels[l.levelIndex()].label+" ›"),
+		row(1, check+" Show only files with diagnostics"),
+		"",
+		dim.Render("↑↓ select • ←→/Space change • Esc close"),
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(selectedColor)).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// refreshTreeDiagnostics пересчитывает метки дерева по текущим диагностикам.
+func (ps *ProjectScreenReal) refreshTreeDiagnostics() {
+	ps.treeSeverity = make(map[string]int)
+	ps.treeLegend.files = [3]int{}
+	root := filepath.Clean(ps.projectPath)
+	for path, diags := range ps.diagnostics {
+		if len(diags) == 0 {
+			continue
+		}
+		rank := severityRank(diags[0].Severity)
+		for _, diag := range diags[1:] {
+			rank = min(rank, severityRank(diag.Severity))
+		}
+		ps.treeLegend.files[rank]++
+
+		// метка поднимается по каталогам до корня проекта
+		for p := filepath.Clean(path); ; p = filepath.Dir(p) {
+			if current, ok := ps.treeSeverity[p]; !ok || rank < current {
+				ps.treeSeverity[p] = rank
+			}
+			if p == root || filepath.Dir(p) == p {
+				break
+			}
+		}
+	}
+	ps.applyTreeFilter()
+}
+
+// applyTreeFilter передаёт фильтр «только с диагностиками» дереву.
+func (ps *ProjectScreenReal) applyTreeFilter() {
+	if ps.fileTree == nil {
+		return
+	}
+	if !ps.treeLegend.filtering() {
+		if ps.fileTree.Visible != nil {
+			ps.fileTree.SetVisible(nil)
+		}
+		return
+	}
+	ps.fileTree.SetVisible(func(node *fs.FileNode) bool {
+		rank, ok := ps.treeSeverity[filepath.Clean(node.Path)]
+		return ok && ps.treeLegend.shows(rank)
+	})
+}
+
+// treeBadge возвращает метку узла или пустую строку.
+func (ps *ProjectScreenReal) treeBadge(node *fs.FileNode) string {
+	rank, ok := ps.treeSeverity[filepath.Clean(node.Path)]
+	if !ok || !ps.treeLegend.shows(rank) {
+		return ""
+	}
+	color := diagInfoColor
+	switch rank {
+	case 0:
+		color = diagErrorColor
+	case 1:
+		color = diagWarningColor
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render("●")
+}
+
+func (ps *ProjectScreenReal) handleTreeDiagFilterChanged() {
+	if ps.fileTree == nil {
+		return
+	}
+	ps.applyTreeFilter()
+	switch {
+	case ps.treeLegend.filtering() && len(ps.fileTree.FlatList) <= 1:
+		ps.setStatus("Filter: no files with diagnostics")
+	case ps.treeLegend.filtering():
+		ps.setStatus("Filter: files with diagnostics")
+	default:
+		ps.setStatus("Diagnostic badges: " + treeBadgeLevels[ps.treeLegend.levelIndex()].label)
+	}
+}
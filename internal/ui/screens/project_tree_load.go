@@ -0,0 +1,62 @@
+package screens
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/fs"
+)
+
+// Дерево проекта ленивое: при загрузке читается только корень, а каталог —
+// при первом раскрытии, в фоне, со строкой «загрузка…» под ним.
+
+// treeChildrenLoadedMsg — содержимое каталога прочитано в фоне.
+type treeChildrenLoadedMsg struct {
+	tree     *fs.FileTree
+	node     *fs.FileNode
+	children []*fs.FileNode
+}
+
+// SetTreeIgnore задаёт имена, которые дерево пропускает; загруженное дерево
+// перечитывается с сохранением раскрытых каталогов.
+func (ps *ProjectScreenReal) SetTreeIgnore(names []string) {
+	if slices.Equal(ps.treeIgnore, names) {
+		return
+	}
+	ps.treeIgnore = slices.Clone(names)
+	if ps.fileTree == nil {
+		return
+	}
+	ps.fileTree.Ignore = make(map[string]bool, len(names))
+	for _, name := range names {
+		ps.fileTree.Ignore[name] = true
+	}
+	if err := ps.fileTree.Refresh(); err != nil {
+		ps.handleTreeError(err)
+		return
+	}
+	ps.updateStats()
+}
+
+// toggleTreeEntry раскрывает или сворачивает каталог; непрочитанный каталог
+// читается командой, чтобы большой каталог не блокировал UI.
+func (ps *ProjectScreenReal) toggleTreeEntry(index int) tea.Cmd {
+	if node, load := ps.fileTree.StartLoad(index); load != nil {
+		tree := ps.fileTree
+		return func() tea.Msg {
+			return treeChildrenLoadedMsg{tree: tree, node: node, children: load()}
+		}
+	}
+	ps.fileTree.ToggleExpanded(index)
+	ps.updateStats()
+	return nil
+}
+
+func (ps *ProjectScreenReal) handleTreeChildrenLoaded(msg treeChildrenLoadedMsg) {
+	if msg.tree != ps.fileTree {
+		return // дерево перезагружено, пока каталог читался
+	}
+	if ps.fileTree.FinishLoad(msg.node, msg.children) {
+		ps.updateStats()
+	}
+}
@@ -0,0 +1,118 @@
+package screens
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/fs"
+)
+
+// Дерево следит за прочитанными каталогами и подхватывает внешние изменения
+// на месте: раскрытые каталоги и выбор сохраняются. Сигналы доходят только
+// до активного экрана, поэтому OnEnter перечитывает каталоги и снова ждёт.
+
+const (
+	treeWatchDebounce = 150 * time.Millisecond
+	treeWatchPoll     = time.Second // интервал опроса без fsnotify
+)
+
+// treeChangedMsg — изменились каталоги дерева; gen отсекает старый наблюдатель.
+type treeChangedMsg struct {
+	gen  int
+	dirs []string
+}
+
+// treeDirsReadMsg — изменившиеся каталоги перечитаны в фоне.
+type treeDirsReadMsg struct {
+	tree *fs.FileTree
+	read map[string][]*fs.FileNode
+}
+
+// startTreeWatcher начинает наблюдение за свежезагруженным деревом.
+func (ps *ProjectScreenReal) startTreeWatcher() tea.Cmd {
+	ps.stopTreeWatcher()
+	if ps.fileTree == nil {
+		return nil
+	}
+	ps.treeWatchGen++
+	ps.treeWatcher = fs.WatchTree(ps.projectPath, treeWatchPoll)
+	ps.syncTreeWatch()
+	return ps.waitTreeChange()
+}
+
+func (ps *ProjectScreenReal) stopTreeWatcher() {
+	if ps.treeWatcher != nil {
+		ps.treeWatcher.Close()
+		ps.treeWatcher = nil
+	}
+}
+
+// Stop освобождает наблюдатель дерева и дописывает копии автосохранения,
+// когда экран проекта заменяют.
+func (ps *ProjectScreenReal) Stop() {
+	ps.stopTreeWatcher()
+	ps.flushAutosaves()
+}
+
+// syncTreeWatch приводит набор наблюдаемых каталогов к прочитанным.
+func (ps *ProjectScreenReal) syncTreeWatch() {
+	if ps.treeWatcher != nil && ps.fileTree != nil {
+		ps.treeWatcher.Sync(ps.fileTree.LoadedDirs())
+	}
+}
+
+func (ps *ProjectScreenReal) waitTreeChange() tea.Cmd {
+	watcher, gen := ps.treeWatcher, ps.treeWatchGen
+	return func() tea.Msg {
+		dirs, ok := watcher.Next(treeWatchDebounce)
+		if !ok {
+			return nil
+		}
+		return treeChangedMsg{gen: gen, dirs: dirs}
+	}
+}
+
+// resumeTreeWatch перечитывает все прочитанные каталоги (изменения, пришедшие
+// пока экран был неактивен, потеряны) и перезапускает ожидание.
+func (ps *ProjectScreenReal) resumeTreeWatch() tea.Cmd {
+	if ps.treeWatcher == nil || ps.fileTree == nil {
+		return nil
+	}
+	ps.treeWatchGen++
+	return tea.Batch(ps.readTreeDirs(ps.fileTree.LoadedDirs()), ps.waitTreeChange())
+}
+
+func (ps *ProjectScreenReal) readTreeDirs(dirs []string) tea.Cmd {
+	if len(dirs) == 0 {
+		return nil
+	}
+	tree, read := ps.fileTree, ps.fileTree.DirReader(dirs)
+	return func() tea.Msg {
+		return treeDirsReadMsg{tree: tree, read: read()}
+	}
+}
+
+func (ps *ProjectScreenReal) handleTreeChanged(msg treeChangedMsg) tea.Cmd {
+	if ps.treeWatcher == nil || msg.gen != ps.treeWatchGen || ps.fileTree == nil {
+		return nil
+	}
+	return tea.Batch(ps.readTreeDirs(msg.dirs), ps.waitTreeChange())
+}
+
+// handleTreeDirsRead вливает перечитанные каталоги в дерево. Выбор остаётся
+// на том же пути; если выбранный файл удалили — на соседней строке.
+func (ps *ProjectScreenReal) handleTreeDirsRead(msg treeDirsReadMsg) tea.Cmd {
+	if msg.tree != ps.fileTree || !ps.fileTree.MergeDirs(msg.read) {
+		return nil
+	}
+	ps.applyTreeFilter()
+	ps.updateStats()
+	if ps.quickOpen == nil {
+		return nil
+	}
+	ps.quickOpen.invalidate()
+	if ps.quickOpen.Visible {
+		return ps.indexQuickOpen()
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package screens
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/platform"
+)
+
+// indentUnit возвращает строку одного уровня отступа согласно настройкам редактора.
+func (ps *ProjectScreenReal) indentUnit() string {
+	if ps.editorCfg.UseSpaces {
+		return strings.Repeat(" ", max(ps.editorCfg.TabSize, 1))
+	}
+	return "\t"
+}
+
+// insertNewLine разбивает строку по Enter; при editor.auto_indent новая
+// строка получает отступ (см. insertIndentedNewLine).
+func (ps *ProjectScreenReal) insertNewLine(tab *editorTab) {
+	if ps.editorCfg.AutoIndent {
+		tab.insertIndentedNewLine(ps.indentUnit())
+		return
+	}
+	tab.insertNewLine()
+}
+
+func (ps *ProjectScreenReal) enterVisualMode(tab *editorTab, linewise bool) {
+	tab.clearPending()
+	tab.startVisual(linewise)
+	ps.setStatus(visualModeLabel(tab))
+}
+
+func (ps *ProjectScreenReal) handleVisualModeKey(tab *editorTab, msg tea.KeyMsg) (Screen, tea.Cmd) {
+	key := platform.CanonicalKeyForLookup(msg.String())
+
+	switch key {
+	case "esc":
+		tab.stopVisual()
+		ps.setStatus("-- NORMAL --")
+	case "v", "V":
+		linewise := key == "V"
+		if tab.visualLine == linewise {
+			tab.stopVisual()
+			ps.setStatus("-- NORMAL --")
+			return ps, nil
+		}
+		tab.visualLine = linewise
+		ps.setStatus(visualModeLabel(tab))
+	case "h", "left":
+		tab.moveCursor(0, -1)
+	case "l", "right":
+		tab.moveCursor(0, 1)
+	case "j", "down":
+		tab.moveCursor(1, 0)
+	case "k", "up":
+		tab.moveCursor(-1, 0)
+	case "0", "home":
+		tab.moveToStartOfLine()
+	case "$", "end":
+		tab.moveToEndOfLine()
+	case "w", "b", "e":
+		ps.moveByWord(tab, key)
+	case "%":
+		ps.jumpToBracket(tab)
+	case "ctrl+d":
+		ps.selectNextOccurrence(tab)
+	case "G":
+		tab.cursor.Line = tab.lineCount() - 1
+		tab.moveToEndOfLine()
+	case "tab", ">":
+		ps.indentSelection(tab)
+	case "shift+tab", "<":
+		ps.outdentSelection(tab)
+	case "m":
+		ps.toggleSelectionBookmark(tab)
+	case "ctrl+s":
+		return ps, ps.saveActiveTab()
+	}
+
+	ps.ensureCursorVisible(tab)
+	return ps, nil
+}
+
+// ClaimsKey забирает у глобальных привязок (смена экрана) Tab в режимах
+// вставки и выделения и Shift+Tab при выделении: это отступы. В normal-режиме
+// Tab по-прежнему переключает экраны.
+func (ps *ProjectScreenReal) ClaimsKey(key string) bool {
+	if key != "tab" && key != "shift+tab" {
+		return false
+	}
+	if ps.loading || ps.focusedPanel != EditorPanel || ps.activeDialog() != nil || ps.problemsActive() {
+		return false
+	}
+	tab := ps.activeEditorTab()
+	if tab == nil {
+		return false
+	}
+	return tab.mode == editorModeVisual || (tab.mode == editorModeInsert && key == "tab")
+}
+
+// indentSelection сдвигает выделенные строки вправо, сохраняя выделение.
+func (ps *ProjectScreenReal) indentSelection(tab *editorTab) {
+	from, to := tab.selectedLineRange()
+	before := tab.snapshot()
+	if tab.indentLines(from, to, ps.indentUnit()) {
+		tab.pushSnapshot(before)
+	}
+	tab.clampCursor()
+}
+
+// outdentSelection сдвигает выделенные строки влево, сохраняя выделение.
+func (ps *ProjectScreenReal) outdentSelection(tab *editorTab) {
+	from, to := tab.selectedLineRange()
+	before := tab.snapshot()
+	if tab.outdentLines(from, to, ps.indentUnit(), ps.editorCfg.TabSize) {
+		tab.pushSnapshot(before)
+	}
+	tab.clampCursor()
+}
+
+func (ps *ProjectScreenReal) undoEdit(tab *editorTab) {
+	if !tab.undo() {
+		ps.setStatus("Already at oldest change")
+		return
+	}
+	ps.ensureCursorVisible(tab)
+	ps.setStatus("Undo")
+}
+
+func (ps *ProjectScreenReal) redoEdit(tab *editorTab) {
+	if !tab.redo() {
+		ps.setStatus("Already at newest change")
+		return
+	}
+	ps.ensureCursorVisible(tab)
+	ps.setStatus("Redo")
+}
+
+// moveByWord выполняет движение w, b или e с границами слова из настроек.
+func (ps *ProjectScreenReal) moveByWord(tab *editorTab, key string) {
+	switch key {
+	case "w":
+		tab.wordForward(ps.editorCfg.WordChars)
+	case "b":
+		tab.wordBackward(ps.editorCfg.WordChars)
+	case "e":
+		tab.wordEnd(ps.editorCfg.WordChars)
+	}
+	ps.ensureCursorVisible(tab)
+}
+
+// selectNextOccurrence выделяет слово под курсором, а если выделение в
+// одной строке уже есть — следующее вхождение его текста.
+func (ps *ProjectScreenReal) selectNextOccurrence(tab *editorTab) {
+	extra := ps.editorCfg.WordChars
+	if !tab.hasSelection() || tab.visualLine {
+		tab.clearPending()
+		tab.stopVisual()
+		if !tab.selectWordAt(tab.cursor, extra) {
+			ps.setStatus("No word under cursor")
+			return
+		}
+		ps.setStatus(visualModeLabel(tab))
+		ps.ensureCursorVisible(tab)
+		return
+	}
+	start, end := tab.selectionBounds()
+	pos, wrapped, ok := tab.nextOccurrence(extra)
+	if !ok {
+		ps.setStatus("No other occurrence")
+		return
+	}
+	tab.cursor = pos
+	tab.anchor = pos
+	tab.cursor.Col += end.Col - start.Col
+	if wrapped {
+		ps.setStatus("Next occurrence (wrapped to top)")
+	} else {
+		ps.setStatus("Next occurrence")
+	}
+	ps.ensureCursorVisible(tab)
+}
+
+func visualModeLabel(tab *editorTab) string {
+	if tab.visualLine {
+		return "-- VISUAL LINE --"
+	}
+	return "-- VISUAL --"
+}
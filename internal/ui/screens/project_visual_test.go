@@ -0,0 +1,116 @@
+package screens
+
+import (
+	"slices"
+	"testing"
+)
+
+func visualTab(t *testing.T) (*ProjectScreenReal, *editorTab) {
+	t.Helper()
+	tab := newTestTab(t, "main.sg",
+		"fn main() {",
+		"let a = 1",
+		"",
+		"\tlet b = 2",
+		"  let c = 3",
+		"}",
+	)
+	ps := newTestProject(t, tab)
+	// посимвольное выделение от середины второй строки до середины пятой
+	moveTo(tab, 1, 4)
+	ps.enterVisualMode(tab, false)
+	moveTo(tab, 4, 6)
+	return ps, tab
+}
+
+func TestVisualTabIndentsPartiallySelectedLines(t *testing.T) {
+	ps, tab := visualTab(t)
+	press(ps, "tab")
+
+	want := []string{
+		"fn main() {",
+		"    let a = 1",
+		"",
+		"    \tlet b = 2",
+		"      let c = 3",
+		"}",
+	}
+	if !slices.Equal(tab.lines, want) {
+		t.Fatalf("lines = %q, want %q", tab.lines, want)
+	}
+	if tab.anchor != (cursorPosition{Line: 1, Col: 8}) || tab.cursor != (cursorPosition{Line: 4, Col: 10}) {
+		t.Errorf("selection moved off the text: anchor %v cursor %v", tab.anchor, tab.cursor)
+	}
+	if tab.mode != editorModeVisual {
+		t.Errorf("selection was dropped")
+	}
+
+	ps.undoEdit(tab)
+	if tab.lines[1] != "let a = 1" || tab.lines[4] != "  let c = 3" {
+		t.Errorf("one undo step must restore all lines, got %q", tab.lines)
+	}
+}
+
+func TestVisualShiftTabOutdentsPartiallySelectedLines(t *testing.T) {
+	ps, tab := visualTab(t)
+	press(ps, "shift+tab")
+
+	want := []string{
+		"fn main() {",
+		"let a = 1",
+		"",
+		"let b = 2",
+		"let c = 3",
+		"}",
+	}
+	if !slices.Equal(tab.lines, want) {
+		t.Fatalf("lines = %q, want %q", tab.lines, want)
+	}
+	if tab.anchor != (cursorPosition{Line: 1, Col: 4}) || tab.cursor != (cursorPosition{Line: 4, Col: 4}) {
+		t.Errorf("selection moved off the text: anchor %v cursor %v", tab.anchor, tab.cursor)
+	}
+
+	// повторное нажатие, когда отступать некуда, не добавляет шаг undo
+	undo := len(tab.undoStack)
+	press(ps, "shift+tab")
+	if len(tab.undoStack) != undo {
+		t.Errorf("no-op outdent pushed an undo step")
+	}
+}
+
+func TestVisualIndentRepeatsOnTheSameSelection(t *testing.T) {
+	ps, tab := visualTab(t)
+	press(ps, ">", ">", "<")
+	if tab.lines[1] != "    let a = 1" || tab.lines[4] != "      let c = 3" {
+		t.Fatalf("lines = %q", tab.lines)
+	}
+	if len(tab.undoStack) != 3 {
+		t.Errorf("undo steps = %d, want one per key", len(tab.undoStack))
+	}
+}
+
+func TestClaimsKeyTakesTabFromScreenSwitching(t *testing.T) {
+	tab := newTestTab(t, "main.sg", "let a = 1")
+	ps := newTestProject(t, tab)
+
+	cases := []struct {
+		name  string
+		setup func()
+		key   string
+		want  bool
+	}{
+		{"normal tab", func() { tab.stopVisual() }, "tab", false},
+		{"visual tab", func() { ps.enterVisualMode(tab, false) }, "tab", true},
+		{"visual shift+tab", func() { ps.enterVisualMode(tab, true) }, "shift+tab", true},
+		{"insert tab", func() { tab.stopVisual(); tab.mode = editorModeInsert }, "tab", true},
+		{"insert shift+tab", func() { tab.mode = editorModeInsert }, "shift+tab", false},
+		{"other key", func() { ps.enterVisualMode(tab, false) }, ">", false},
+		{"tree focus", func() { ps.focusedPanel = FileTreePanel }, "tab", false},
+	}
+	for _, c := range cases {
+		c.setup()
+		if got := ps.ClaimsKey(c.key); got != c.want {
+			t.Errorf("%s: ClaimsKey(%q) = %v, want %v", c.name, c.key, got, c.want)
+		}
+	}
+}
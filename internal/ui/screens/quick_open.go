@@ -0,0 +1,292 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/fs"
+)
+
+const quickOpenMaxResults = 200 // больше совпадений не сортируем и не рисуем
+
+// quickOpenIndexMsg — список файлов проекта, собранный в фоне.
+type quickOpenIndexMsg struct {
+	root  *fs.FileNode
+	files []string
+}
+
+// quickOpenChosenMsg — выбран файл для открытия.
+type quickOpenChosenMsg struct {
+	path string
+}
+
+type quickOpenMatch struct {
+	path   string
+	score  int
+	recent bool
+}
+
+// quickOpenDialog — оверлей быстрого открытия файла по нечёткому имени.
+// Недавние файлы идут первыми; индекс строится асинхронно по корню дерева
+// и перестраивается, когда дерево заменяется.
+type quickOpenDialog struct {
+	Visible bool
+
+	input       textinput.Model
+	projectPath string
+	recent      []string
+	files       []string // абсолютные пути всех файлов дерева
+	indexed     map[string]bool
+	indexedRoot *fs.FileNode // корень, по которому построен files
+	pendingRoot *fs.FileNode // корень, индекс которого сейчас строится
+	results     []quickOpenMatch
+	total       int // совпадений до обрезки
+	selected    int
+	height      int
+}
+
+func newQuickOpenDialog() *quickOpenDialog {
+	ti := textinput.New()
+	ti.Placeholder = "file name"
+	ti.Prompt = "› "
+	ti.CharLimit = 256
+	ti.Width = 50
+	return &quickOpenDialog{input: ti}
+}
+
+// Show открывает оверлей; height — сколько строк результатов помещается.
+func (q *quickOpenDialog) Show(projectPath string, recent []string, height int) {
+	if q.projectPath != projectPath {
+		q.files, q.indexed, q.indexedRoot, q.pendingRoot = nil, nil, nil, nil
+	}
+	q.projectPath = projectPath
+	q.recent = recent
+	q.height = max(height, 3)
+	q.selected = 0
+	q.input.SetValue("")
+	q.input.Focus()
+	q.Visible = true
+	q.refilter()
+}
+
+func (q *quickOpenDialog) Hide() {
+	q.Visible = false
+	q.input.Blur()
+}
+
+// needsIndex сообщает, нужно ли строить индекс для root.
+func (q *quickOpenDialog) needsIndex(root *fs.FileNode) bool {
+	return root != nil && root != q.indexedRoot && root != q.pendingRoot
+}
+
+// invalidate помечает индекс устаревшим: файлы в дереве добавились или пропали.
+// Старый список остаётся в выдаче, пока строится новый.
+func (q *quickOpenDialog) invalidate() {
+	q.indexedRoot, q.pendingRoot = nil, nil
+}
+
+func (q *quickOpenDialog) setIndex(msg quickOpenIndexMsg) {
+	if msg.root != q.pendingRoot {
+		return // индекс устаревшего дерева
+	}
+	q.files, q.indexedRoot, q.pendingRoot = msg.files, msg.root, nil
+	q.indexed = make(map[string]bool, len(msg.files))
+	for _, path := range msg.files {
+		q.indexed[path] = true
+	}
+	q.refilter()
+}
+
+// buildQuickOpenIndex собирает пути файлов вне UI. Дерево ленивое, поэтому
+// обходится диск — с фильтрами дерева на момент вызова.
+func buildQuickOpenIndex(tree *fs.FileTree) tea.Cmd {
+	snapshot := *tree
+	return func() tea.Msg {
+		return quickOpenIndexMsg{root: snapshot.Root, files: snapshot.ListFiles()}
+	}
+}
+
+func (q *quickOpenDialog) Update(msg tea.Msg) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || !q.Visible {
+		return nil
+	}
+	switch key.String() {
+	case "esc":
+		q.Hide()
+	case "up":
+		q.selected = max(q.selected-1, 0)
+	case "down":
+		q.selected = max(min(q.selected+1, len(q.results)-1), 0)
+	case "pgup":
+		q.selected = max(q.selected-q.height, 0)
+	case "pgdown":
+		q.selected = max(min(q.selected+q.height, len(q.results)-1), 0)
+	case "enter":
+		if q.selected < len(q.results) {
+			path := q.results[q.selected].path
+			q.Hide()
+			return func() tea.Msg { return quickOpenChosenMsg{path: path} }
+		}
+	default:
+		before := q.input.Value()
+		var cmd tea.Cmd
+		q.input, cmd = q.input.Update(key)
+		if q.input.Value() != before {
+			q.selected = 0
+			q.refilter()
+		}
+		return cmd
+	}
+	return nil
+}
+
+// refilter пересчитывает совпадения: недавние первыми, затем по качеству.
+func (q *quickOpenDialog) refilter() {
+	query := strings.TrimSpace(q.input.Value())
+	recentRank := make(map[string]int, len(q.recent))
+	for i, path := range q.recent {
+		recentRank[path] = i + 1
+	}
+
+	var matches []quickOpenMatch
+	consider := func(path string, recent bool) {
+		score, ok := fuzzyScore(query, q.relative(path))
+		if !ok {
+			return
+		}
+		if query == "" && recent {
+			score = -recentRank[path] // без запроса — в порядке недавности
+		}
+		matches = append(matches, quickOpenMatch{path: path, score: score, recent: recent})
+	}
+	for _, path := range q.recent {
+		// недавний файл, которого нет в готовом индексе, удалён или скрыт фильтром
+		if q.indexedRoot == nil || q.indexed[path] {
+			consider(path, true)
+		}
+	}
+	for _, path := range q.files {
+		if recentRank[path] == 0 {
+			consider(path, false)
+		}
+	}
+
+	q.total = len(matches)
+	if query != "" || len(matches) > quickOpenMaxResults {
+		sort.SliceStable(matches, func(i, j int) bool {
+			a, b := matches[i], matches[j]
+			if a.recent != b.recent {
+				return a.recent
+			}
+			if a.score != b.score {
+				return a.score > b.score
+			}
+			return len(a.path) < len(b.path)
+		})
+	}
+	if len(matches) > quickOpenMaxResults {
+		matches = matches[:quickOpenMaxResults]
+	}
+	q.results = matches
+	q.selected = clamp(q.selected, 0, max(len(matches)-1, 0))
+}
+
+func (q *quickOpenDialog) relative(path string) string {
+	if rel, err := filepath.Rel(q.projectPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// fuzzyScore ищет символы запроса в тексте по порядку. Подряд идущие
+// совпадения, начала слов и имя файла ценятся выше; длинные пути — ниже.
+func fuzzyScore(query, text string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	pattern := []rune(strings.ToLower(query))
+	runes := []rune(strings.ToLower(text))
+	base := 0
+	for i, r := range runes {
+		if r == '/' || r == filepath.Separator {
+			base = i + 1
+		}
+	}
+
+	score, pi, prev := 0, 0, -2
+	for i := 0; i < len(runes) && pi < len(pattern); i++ {
+		if runes[i] != pattern[pi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 5
+		}
+		if i == 0 || strings.ContainsRune("/\\_-. ", runes[i-1]) {
+			score += 8
+		}
+		if i >= base {
+			score += 2
+		}
+		prev = i
+		pi++
+	}
+	if pi < len(pattern) {
+		return 0, false
+	}
+	return score - len(runes)/10, true
+}
+
+func (q *quickOpenDialog) View() string {
+	if !q.Visible {
+		return ""
+	}
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(DimTextColor))
+
+	status := fmt.Sprintf("%d of %d files", q.total, len(q.files))
+	if q.pendingRoot != nil {
+		status = "indexing… " + status
+	}
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Render("Quick Open"),
+		q.input.View(),
+		dim.Render(status + " • ↑↓ select • Enter open • Esc close"),
+		"",
+	}
+	if len(q.results) == 0 {
+		lines = append(lines, dim.Render("No matching files"))
+	}
+
+	start := 0
+	if q.selected >= q.height {
+		start = q.selected - q.height + 1
+	}
+	end := min(start+q.height, len(q.results))
+	for i := start; i < end; i++ {
+		match := q.results[i]
+		rel := q.relative(match.path)
+		dir, name := filepath.Split(rel)
+		line := dim.Render(dir) + name
+		if match.recent {
+			line += dim.Render("  recent")
+		}
+		if i == q.selected {
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color(selectedColor)).Bold(true).Render("→ ") + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(selectedColor)).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
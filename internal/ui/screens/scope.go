@@ -0,0 +1,58 @@
+package screens
+
+import (
+	"path/filepath"
+
+	"surge-tui/internal/fs"
+)
+
+// Область запуска — каталог внутри проекта, которым ограничены surge diag
+// на экране диагностики и surge fix в Fix Mode. Задаётся из дерева проекта
+// и остаётся у экрана, пока её не расширят до проекта (P); пустая
+// область — весь проект.
+
+// DiagnoseDirMsg просит запустить диагностику только для каталога Dir.
+type DiagnoseDirMsg struct {
+	Dir string
+}
+
+// FixDirMsg просит открыть Fix Mode для каталога Dir и применить все его
+// фиксы после подтверждения.
+type FixDirMsg struct {
+	Dir string
+}
+
+// normalizeScope приводит каталог к области: корень проекта и пути вне
+// проекта означают весь проект.
+func normalizeScope(projectPath, dir string) string {
+	if dir == "" || projectPath == "" {
+		return ""
+	}
+	dir = filepath.Clean(dir)
+	if samePath(dir, projectPath) || !fs.IsWithin(dir, projectPath) {
+		return ""
+	}
+	return dir
+}
+
+// scopeTarget — путь, который получает surge: область или весь проект.
+func scopeTarget(projectPath, scope string) string {
+	if scope != "" {
+		return scope
+	}
+	return projectPath
+}
+
+// scopeLabel — область относительно проекта для заголовков ("pkg/lexer/").
+func scopeLabel(projectPath, scope string) string {
+	return projectRel(projectPath, scope) + "/"
+}
+
+// projectRel — путь относительно проекта для статуса; вне проекта — как есть.
+func projectRel(projectPath, path string) string {
+	rel, err := filepath.Rel(projectPath, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -3,6 +3,7 @@ package screens
 import (
 	tea "github.com/charmbracelet/bubbletea"
 	"surge-tui/internal/platform"
+	"surge-tui/internal/ui/styles"
 )
 
 // Screen интерфейс для всех экранов приложения
@@ -17,6 +18,10 @@ type Screen interface {
 	OnExit() tea.Cmd  // Вызывается при выходе с экрана
 	CanExit() bool    // Можно ли покинуть экран (например, есть несохраненные изменения)
 
+	// SetTheme применяет тему приложения; вызывается при создании экрана и
+	// при каждой смене темы (ThemeChangedMsg)
+	SetTheme(theme *styles.Theme)
+
 	// Метаданные экрана
 	Title() string      // Заголовок экрана для статус-бара
 	ShortHelp() string  // Краткая справка по горячим клавишам
@@ -63,6 +68,11 @@ func (bs *BaseScreen) CanExit() bool {
 	return true
 }
 
+// SetTheme базовая реализация - переключает общие цвета экранов
+func (bs *BaseScreen) SetTheme(theme *styles.Theme) {
+	usePalette(theme.Palette())
+}
+
 // OnEnter базовая реализация - ничего не делаем
 func (bs *BaseScreen) OnEnter() tea.Cmd {
 	return nil
@@ -88,6 +98,6 @@ func (bs *BaseScreen) FullHelp() []string {
 		"",
 		"Global:",
 		platform.ReplacePrimaryModifier("  Ctrl+Q - Quit application"),
-		platform.ReplacePrimaryModifier("  Ctrl+, - Settings"),
+		"  F2 - Settings",
 	}
 }
@@ -3,10 +3,4 @@ package screens
 const (
 	settingsMenuWidth = 25
 	settingsMinWidth  = 80
-
-	selectedColor   = "#7C3AED"
-	unselectedColor = "#94A3B8"
-	validColor      = "#10B981"
-	invalidColor    = "#EF4444"
-	modifiedColor   = "#F59E0B"
 )
@@ -0,0 +1,101 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/ui/components"
+)
+
+// Уход с экрана настроек с несохранёнными правками: приложение спрашивает
+// CanExit и вместо перехода показывает диалог Save / Discard / Cancel.
+// После Save или Discard переход повторяется, Cancel оставляет на экране.
+// При выходе из приложения правки попадают в общий диалог выхода.
+
+const (
+	settingsExitSave = iota
+	settingsExitDiscard
+)
+
+// settingsExitMsg — ответ диалога ухода с экрана.
+type settingsExitMsg struct {
+	choice int
+	resume tea.Cmd
+}
+
+// CanExit не отпускает с экрана, пока есть несохранённые правки.
+func (ss *SettingsScreen) CanExit() bool {
+	return !ss.state.hasChanges
+}
+
+// ConfirmExit спрашивает, что сделать с правками; resume повторяет переход.
+func (ss *SettingsScreen) ConfirmExit(resume tea.Cmd) tea.Cmd {
+	if ss.exitDialog.Visible {
+		return nil
+	}
+	ss.exitDialog.Description = "Unsaved changes: " + strings.Join(ss.changedFieldNames(), ", ")
+	ch := ss.exitDialog.Show()
+	return func() tea.Msg {
+		return settingsExitMsg{choice: <-ch, resume: resume}
+	}
+}
+
+func (ss *SettingsScreen) handleExitChoice(msg settingsExitMsg) tea.Cmd {
+	switch msg.choice {
+	case settingsExitSave:
+		ss.cancelEdit()
+		if err := ss.SaveAllTabs(); err != nil {
+			ss.setNotice(fmt.Sprintf("Save failed: %v", err), true)
+			return nil
+		}
+		clone := ss.config.Clone()
+		return tea.Sequence(func() tea.Msg { return ConfigChangedMsg{Config: clone} }, msg.resume)
+	case settingsExitDiscard:
+		ss.cancelEdit()
+		ss.state.captureMode = false
+		ss.state.bindingMode = false
+		ss.config = ss.original.Clone()
+		ss.recalcChangeState()
+		return tea.Batch(ss.validateAllFields(), msg.resume)
+	default:
+		ss.setNotice("Settings not saved yet: S saves, R reloads from disk", false)
+		return nil
+	}
+}
+
+// UnsavedTabs попадает в диалог выхода из приложения.
+func (ss *SettingsScreen) UnsavedTabs() []string {
+	if !ss.state.hasChanges {
+		return nil
+	}
+	return []string{"Settings (" + strings.Join(ss.changedFieldNames(), ", ") + ")"}
+}
+
+// SaveAllTabs записывает конфиг сразу, без команды: им пользуются выход из
+// приложения и диалог ухода с экрана.
+func (ss *SettingsScreen) SaveAllTabs() error {
+	if !ss.state.hasChanges {
+		return nil
+	}
+	if err := ss.config.SaveDefault(); err != nil {
+		return err
+	}
+	ss.original = *ss.config.Clone()
+	ss.recalcChangeState()
+	return nil
+}
+
+func (ss *SettingsScreen) changedFieldNames() []string {
+	var names []string
+	for _, field := range allSettingsFields() {
+		if ss.isFieldChanged(field) {
+			names = append(names, ss.fieldName(field))
+		}
+	}
+	return names
+}
+
+func newSettingsExitDialog() *components.ChoiceDialog {
+	return components.NewChoiceDialog("Leave Settings", "", "Save", "Discard", "Cancel")
+}
@@ -1,39 +1,65 @@
 package screens
 
 import (
+	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+
+	"surge-tui/internal/config"
 )
 
 func allSettingsFields() []SettingsField {
 	return []SettingsField{
 		ThemeField,
 		SurgeBinaryField,
+		SurgeTimeoutField,
+		SurgeStallField,
+		DiagCacheField,
 		DefaultProjectField,
 		TabSizeField,
 		UseSpacesField,
+		AutoIndentField,
 		AutoSaveField,
 		AutoSaveDelayField,
 		ExternalEditorField,
 		SyntaxHighlightField,
+		WordCharsField,
+		DiagOnSaveField,
+		FormatOnSaveField,
+		RestoreSessionField,
 		MaxFileSizeField,
 		RefreshRateField,
 		LogLevelField,
+		KeybindingsField,
 	}
 }
 
 func (ss *SettingsScreen) fieldName(field SettingsField) string {
+	return field.String()
+}
+
+// String возвращает название поля, как оно показано в настройках.
+func (field SettingsField) String() string {
 	switch field {
 	case ThemeField:
 		return "Theme"
 	case SurgeBinaryField:
 		return "Surge Binary Path"
+	case SurgeTimeoutField:
+		return "Surge Timeout (seconds)"
+	case SurgeStallField:
+		return "Stall Warning (seconds)"
+	case DiagCacheField:
+		return "Diagnostics Cache (seconds)"
 	case DefaultProjectField:
 		return "Default Project Directory"
 	case TabSizeField:
 		return "Tab Size"
 	case UseSpacesField:
 		return "Use Spaces Instead of Tabs"
+	case AutoIndentField:
+		return "Auto Indent"
 	case AutoSaveField:
 		return "Auto Save Files"
 	case AutoSaveDelayField:
@@ -42,12 +68,22 @@ func (ss *SettingsScreen) fieldName(field SettingsField) string {
 		return "External Editor Command"
 	case SyntaxHighlightField:
 		return "Syntax Highlighting"
+	case WordCharsField:
+		return "Word Characters"
+	case DiagOnSaveField:
+		return "Diagnostics on Save"
+	case FormatOnSaveField:
+		return "Format on Save"
+	case RestoreSessionField:
+		return "Restore Session"
 	case MaxFileSizeField:
 		return "Maximum File Size"
 	case RefreshRateField:
 		return "UI Refresh Rate"
 	case LogLevelField:
 		return "Log Level"
+	case KeybindingsField:
+		return "Keybindings"
 	default:
 		return "Unknown"
 	}
@@ -56,15 +92,23 @@ func (ss *SettingsScreen) fieldName(field SettingsField) string {
 func (ss *SettingsScreen) fieldDescription() string {
 	switch ss.state.selectedField {
 	case ThemeField:
-		return "Choose between 'dark' and 'light' theme. Press 'T' for quick toggle."
+		return "Built-in 'dark' and 'light' or a theme from the themes section of the config. Press 'T' to cycle."
 	case SurgeBinaryField:
 		return "Path to surge executable. Can be 'surge' (in PATH) or full path like '/path/to/surge'."
+	case SurgeTimeoutField:
+		return "Time limit for one surge run (diag, fix, fmt, init); 0 disables it. Raise it if diagnostics of a big project time out. Builds are not limited."
+	case SurgeStallField:
+		return "Warn when a surge run (diag, fix, fmt, init) has printed nothing for this long; the run keeps going and Esc cancels it. 0 disables the warning."
+	case DiagCacheField:
+		return "How long Diagnostics and Fix Mode show their last result when you come back instead of running surge again; changed files always trigger a new run and F5 / Ctrl+R forces one. 0 re-runs on every visit."
 	case DefaultProjectField:
 		return "Default directory to open when starting surge-tui without arguments."
 	case TabSizeField:
 		return "Number of spaces for tab indentation (1-16)."
 	case UseSpacesField:
 		return "Use spaces instead of tab characters for indentation."
+	case AutoIndentField:
+		return "Enter keeps the indentation of the line; after '{' or '(' the new line goes one level deeper, and Enter between '{' and '}' puts the brace on its own line."
 	case AutoSaveField:
 		return "Automatically save files after editing."
 	case AutoSaveDelayField:
@@ -73,12 +117,22 @@ func (ss *SettingsScreen) fieldDescription() string {
 		return "Command to launch external editor (e.g., 'code', 'vim')."
 	case SyntaxHighlightField:
 		return "Enable syntax highlighting for source files."
+	case WordCharsField:
+		return "Characters that belong to a word besides letters and digits: w/b/e motions, double-click and Ctrl+D selection. '_-' keeps foo-bar whole, '_.' keeps std.io whole."
+	case DiagOnSaveField:
+		return "Run 'surge diag' for a file after it is saved and update the gutter."
+	case FormatOnSaveField:
+		return "Run 'surge fmt' for a .sg file after it is saved and reload the buffer."
+	case RestoreSessionField:
+		return "Reopen tabs, cursor positions and the last screen of the project on start."
 	case MaxFileSizeField:
 		return "Maximum file size to open in editor (in megabytes)."
 	case RefreshRateField:
 		return "UI refresh rate in milliseconds (10-1000)."
 	case LogLevelField:
 		return "Logging level: debug, info, warn, error."
+	case KeybindingsField:
+		return "Keys for app commands; project.* keys act in the project tree. Bindings marked " + keybindingWarningBadge + " are ones " + ss.terminal.Name() + " most likely cannot send, " + keybindingConflictBadge + " share a key within their scope. Enter records a new key and saves it at once; a key taken in the same scope can be swapped."
 	default:
 		return ""
 	}
@@ -89,124 +143,177 @@ func (ss *SettingsScreen) getCurrentValue() string {
 }
 
 func (ss *SettingsScreen) valueFor(field SettingsField) string {
-	switch field {
-	case ThemeField:
-		return ss.config.Theme
-	case SurgeBinaryField:
-		return ss.config.SurgeBinary
-	case DefaultProjectField:
-		return ss.config.DefaultProject
-	case TabSizeField:
-		return strconv.Itoa(ss.config.Editor.TabSize)
-	case UseSpacesField:
-		if ss.config.Editor.UseSpaces {
-			return "true"
-		}
-		return "false"
-	case AutoSaveField:
-		if ss.config.Editor.AutoSave {
-			return "true"
-		}
-		return "false"
-	case AutoSaveDelayField:
-		return strconv.Itoa(ss.config.Editor.AutoSaveDelay)
-	case ExternalEditorField:
-		return ss.config.Editor.ExternalEditor
-	case SyntaxHighlightField:
-		if ss.config.Editor.SyntaxHighlight {
-			return "true"
-		}
-		return "false"
-	case MaxFileSizeField:
-		return strconv.FormatInt(ss.config.Performance.MaxFileSize/(1024*1024), 10) + "MB"
-	case RefreshRateField:
-		return strconv.Itoa(ss.config.Performance.RefreshRate) + "ms"
-	case LogLevelField:
-		return ss.config.Logging.Level
-	default:
-		return ""
-	}
+	return fieldValue(ss.config, field)
 }
 
-func (ss *SettingsScreen) setCurrentValue(value string) {
-	switch ss.state.selectedField {
+func (ss *SettingsScreen) originalValue(field SettingsField) string {
+	return fieldValue(&ss.original, field)
+}
+
+// setFieldValue записывает значение в поле конфига; недопустимое значение
+// не меняет конфиг и возвращается ошибкой для показа под полем.
+func (ss *SettingsScreen) setFieldValue(field SettingsField, value string) error {
+	if err := ss.checkFieldValue(field, value); err != nil {
+		return err
+	}
+	switch field {
 	case ThemeField:
-		if value == "dark" || value == "light" {
-			ss.config.Theme = value
-		}
+		ss.config.Theme = value
 	case SurgeBinaryField:
 		ss.config.SurgeBinary = strings.TrimSpace(value)
+	case SurgeTimeoutField:
+		ss.config.Surge.TimeoutSeconds, _ = parseNumber(value, "s")
+	case SurgeStallField:
+		ss.config.Surge.StallSeconds, _ = parseNumber(value, "s")
+	case DiagCacheField:
+		ss.config.Surge.CacheSeconds, _ = parseNumber(value, "s")
 	case DefaultProjectField:
 		ss.config.DefaultProject = strings.TrimSpace(value)
 	case TabSizeField:
-		if n, err := strconv.Atoi(value); err == nil && n >= 1 && n <= 16 {
-			ss.config.Editor.TabSize = n
-		}
+		ss.config.Editor.TabSize, _ = strconv.Atoi(strings.TrimSpace(value))
 	case UseSpacesField:
 		ss.config.Editor.UseSpaces = parseBool(value)
+	case AutoIndentField:
+		ss.config.Editor.AutoIndent = parseBool(value)
 	case AutoSaveField:
 		ss.config.Editor.AutoSave = parseBool(value)
 	case AutoSaveDelayField:
-		if n, err := strconv.Atoi(value); err == nil && n > 0 {
-			ss.config.Editor.AutoSaveDelay = n
-		}
+		ss.config.Editor.AutoSaveDelay, _ = strconv.Atoi(strings.TrimSpace(value))
 	case ExternalEditorField:
 		ss.config.Editor.ExternalEditor = strings.TrimSpace(value)
 	case SyntaxHighlightField:
 		ss.config.Editor.SyntaxHighlight = parseBool(value)
+	case WordCharsField:
+		ss.config.Editor.WordChars = config.NormalizeWordChars(value)
+	case DiagOnSaveField:
+		ss.config.Editor.DiagOnSave = parseBool(value)
+	case FormatOnSaveField:
+		ss.config.Editor.FormatOnSave = parseBool(value)
+	case RestoreSessionField:
+		ss.config.Startup.RestoreSession = parseBool(value)
 	case MaxFileSizeField:
-		v := strings.TrimSuffix(strings.ToLower(value), "mb")
-		if n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil && n > 0 {
-			ss.config.Performance.MaxFileSize = n * 1024 * 1024
-		}
+		n, _ := parseNumber(value, "mb")
+		ss.config.Performance.MaxFileSize = int64(n) * 1024 * 1024
 	case RefreshRateField:
-		v := strings.TrimSuffix(strings.ToLower(value), "ms")
-		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && n >= 10 && n <= 1000 {
-			ss.config.Performance.RefreshRate = n
-		}
+		ss.config.Performance.RefreshRate, _ = parseNumber(value, "ms")
 	case LogLevelField:
-		switch value {
-		case "debug", "info", "warn", "error":
-			ss.config.Logging.Level = value
-		}
+		ss.config.Logging.Level = value
 	}
+	return nil
 }
 
-func (ss *SettingsScreen) originalValue(field SettingsField) string {
+// checkFieldValue проверяет значение поля, не меняя конфиг.
+func (ss *SettingsScreen) checkFieldValue(field SettingsField, value string) error {
+	switch field {
+	case SurgeTimeoutField, SurgeStallField, DiagCacheField:
+		return checkRange(value, "s", 0, 0)
+	case TabSizeField:
+		return checkRange(value, "", 1, 16)
+	case AutoSaveDelayField:
+		return checkRange(value, "", 1, 0)
+	case MaxFileSizeField:
+		return checkRange(value, "mb", 1, 0)
+	case RefreshRateField:
+		return checkRange(value, "ms", 10, 1000)
+	}
+	if options := ss.fieldOptions(field); options != nil && !slices.Contains(options, value) {
+		return fmt.Errorf("Choose one of: %s", strings.Join(options, ", "))
+	}
+	return nil
+}
+
+// checkRange требует целое число в [low, high], high <= 0 — без верхней
+// границы; suffix (например "ms") можно не писать.
+func checkRange(value, suffix string, low, high int) error {
+	n, err := parseNumber(value, suffix)
+	switch {
+	case high <= 0 && (err != nil || n < low):
+		return fmt.Errorf("Enter a whole number of at least %d", low)
+	case high > 0 && (err != nil || n < low || n > high):
+		return fmt.Errorf("Enter a whole number from %d to %d", low, high)
+	}
+	return nil
+}
+
+func parseNumber(value, suffix string) (int, error) {
+	v := strings.TrimSpace(strings.ToLower(value))
+	if suffix != "" {
+		v = strings.TrimSpace(strings.TrimSuffix(v, suffix))
+	}
+	return strconv.Atoi(v)
+}
+
+// defaultValue возвращает значение поля из config.DefaultConfig.
+func defaultValue(field SettingsField) string {
+	return fieldValue(config.DefaultConfig(), field)
+}
+
+// fieldValue форматирует значение поля из произвольного конфига.
+func fieldValue(cfg *config.Config, field SettingsField) string {
 	switch field {
 	case ThemeField:
-		return ss.original.Theme
+		return cfg.Theme
 	case SurgeBinaryField:
-		return ss.original.SurgeBinary
+		return cfg.SurgeBinary
+	case SurgeTimeoutField:
+		return strconv.Itoa(cfg.Surge.TimeoutSeconds) + "s"
+	case SurgeStallField:
+		return strconv.Itoa(cfg.Surge.StallSeconds) + "s"
+	case DiagCacheField:
+		return strconv.Itoa(cfg.Surge.CacheSeconds) + "s"
 	case DefaultProjectField:
-		return ss.original.DefaultProject
+		return cfg.DefaultProject
 	case TabSizeField:
-		return strconv.Itoa(ss.original.Editor.TabSize)
+		return strconv.Itoa(cfg.Editor.TabSize)
 	case UseSpacesField:
-		if ss.original.Editor.UseSpaces {
+		if cfg.Editor.UseSpaces {
+			return "true"
+		}
+		return "false"
+	case AutoIndentField:
+		if cfg.Editor.AutoIndent {
 			return "true"
 		}
 		return "false"
 	case AutoSaveField:
-		if ss.original.Editor.AutoSave {
+		if cfg.Editor.AutoSave {
 			return "true"
 		}
 		return "false"
 	case AutoSaveDelayField:
-		return strconv.Itoa(ss.original.Editor.AutoSaveDelay)
+		return strconv.Itoa(cfg.Editor.AutoSaveDelay)
 	case ExternalEditorField:
-		return ss.original.Editor.ExternalEditor
+		return cfg.Editor.ExternalEditor
 	case SyntaxHighlightField:
-		if ss.original.Editor.SyntaxHighlight {
+		if cfg.Editor.SyntaxHighlight {
+			return "true"
+		}
+		return "false"
+	case WordCharsField:
+		return cfg.Editor.WordChars
+	case DiagOnSaveField:
+		if cfg.Editor.DiagOnSave {
+			return "true"
+		}
+		return "false"
+	case FormatOnSaveField:
+		if cfg.Editor.FormatOnSave {
+			return "true"
+		}
+		return "false"
+	case RestoreSessionField:
+		if cfg.Startup.RestoreSession {
 			return "true"
 		}
 		return "false"
 	case MaxFileSizeField:
-		return strconv.FormatInt(ss.original.Performance.MaxFileSize/(1024*1024), 10) + "MB"
+		return strconv.FormatInt(cfg.Performance.MaxFileSize/(1024*1024), 10) + "MB"
 	case RefreshRateField:
-		return strconv.Itoa(ss.original.Performance.RefreshRate) + "ms"
+		return strconv.Itoa(cfg.Performance.RefreshRate) + "ms"
 	case LogLevelField:
-		return ss.original.Logging.Level
+		return cfg.Logging.Level
+	case KeybindingsField:
+		return keybindingsSummary(cfg)
 	default:
 		return ""
 	}
@@ -2,15 +2,19 @@ package screens
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
+	"slices"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
 	"surge-tui/internal/platform"
 )
 
 func (ss *SettingsScreen) handleKeyPress(msg tea.KeyMsg) (Screen, tea.Cmd) {
-	key := platform.CanonicalKeyForLookup(msg.String())
+	key := settingsKey(msg)
+	ss.setNotice("", false)
 	switch key {
 	case "up", "k":
 		ss.selectPreviousField()
@@ -24,17 +28,30 @@ func (ss *SettingsScreen) handleKeyPress(msg tea.KeyMsg) (Screen, tea.Cmd) {
 		return ss, ss.saveSettings()
 	case "r", "ctrl+r":
 		return ss, ss.resetSettings()
+	case "d":
+		return ss, ss.resetFieldToDefault()
 	case "t":
 		ss.toggleTheme()
 		return ss, nil
 	case "esc":
-		// later integrate confirm dialog; for now noop
+		// уход с экрана проверяет приложение через CanExit
 		return ss, nil
 	}
 	return ss, nil
 }
 
+// settingsKey — каноничное имя нажатия; пробел канонизация теряет.
+func settingsKey(msg tea.KeyMsg) string {
+	if msg.Type == tea.KeySpace {
+		return "space"
+	}
+	return platform.CanonicalKeyForLookup(msg.String())
+}
+
 func (ss *SettingsScreen) handleEditMode(msg tea.KeyMsg) (Screen, tea.Cmd) {
+	if ss.state.selectedField != KeybindingsField && fieldKind(ss.state.selectedField) == settingEnum {
+		return ss.handleChoiceKey(msg)
+	}
 	switch msg.Type {
 	case tea.KeyEnter:
 		return ss.commitEdit()
@@ -42,10 +59,12 @@ func (ss *SettingsScreen) handleEditMode(msg tea.KeyMsg) (Screen, tea.Cmd) {
 		ss.cancelEdit()
 		return ss, nil
 	}
-
-	var cmd tea.Cmd
-	ss.input, cmd = ss.input.Update(msg)
-	return ss, cmd
+	if ss.state.selectedField == KeybindingsField {
+		var cmd tea.Cmd
+		ss.input, cmd = ss.input.Update(msg)
+		return ss, cmd
+	}
+	return ss.handleInputKey(msg)
 }
 
 func (ss *SettingsScreen) handleResize(msg tea.WindowSizeMsg) {
@@ -62,14 +81,14 @@ func (ss *SettingsScreen) handleResize(msg tea.WindowSizeMsg) {
 
 func (ss *SettingsScreen) selectPreviousField() {
 	if ss.state.selectedField == ThemeField {
-		ss.state.selectedField = LogLevelField
+		ss.state.selectedField = KeybindingsField
 		return
 	}
 	ss.state.selectedField--
 }
 
 func (ss *SettingsScreen) selectNextField() {
-	if ss.state.selectedField == LogLevelField {
+	if ss.state.selectedField == KeybindingsField {
 		ss.state.selectedField = ThemeField
 		return
 	}
@@ -77,7 +96,19 @@ func (ss *SettingsScreen) selectNextField() {
 }
 
 func (ss *SettingsScreen) enterEditMode() (Screen, tea.Cmd) {
+	if ss.state.selectedField == KeybindingsField {
+		ss.openBindings()
+		return ss, nil
+	}
+	switch fieldKind(ss.state.selectedField) {
+	case settingBool:
+		return ss, ss.toggleField(ss.state.selectedField)
+	case settingEnum:
+		ss.startChoice(ss.state.selectedField)
+		return ss, nil
+	}
 	ss.state.editMode = true
+	ss.state.editErr = ""
 	ss.input.SetValue(ss.getCurrentValue())
 	if ss.state.contentWidth > 4 {
 		ss.input.Width = ss.state.contentWidth - 4
@@ -88,25 +119,32 @@ func (ss *SettingsScreen) enterEditMode() (Screen, tea.Cmd) {
 }
 
 func (ss *SettingsScreen) commitEdit() (Screen, tea.Cmd) {
-	value := ss.input.Value()
-	ss.setCurrentValue(value)
-	ss.state.editMode = false
-	ss.input.Blur()
+	if ss.state.selectedField == KeybindingsField {
+		return ss.commitBindingEdit()
+	}
+	if err := ss.setFieldValue(ss.state.selectedField, ss.editedValue()); err != nil {
+		ss.state.editErr = err.Error()
+		return ss, nil
+	}
+	ss.cancelEdit()
 	ss.recalcChangeState()
 	return ss, ss.validateField(ss.state.selectedField)
 }
 
+// cancelEdit закрывает правку; конфиг меняется только в commitEdit, так что
+// поле сохраняет значение, которое было до правки.
 func (ss *SettingsScreen) cancelEdit() {
 	ss.state.editMode = false
+	ss.state.editErr = ""
 	ss.input.Blur()
+	ss.input.SetValue("")
 }
 
+// toggleTheme переходит к следующей теме: встроенные, затем пользовательские.
 func (ss *SettingsScreen) toggleTheme() {
-	if ss.config.Theme == "dark" {
-		ss.config.Theme = "light"
-	} else {
-		ss.config.Theme = "dark"
-	}
+	names := ss.config.ThemeNames()
+	next := (slices.Index(names, ss.config.Theme) + 1) % len(names)
+	ss.config.Theme = names[next]
 	ss.recalcChangeState()
 }
 
@@ -115,17 +153,52 @@ func (ss *SettingsScreen) saveSettings() tea.Cmd {
 		if err := ss.config.SaveDefault(); err != nil {
 			return settingsErrorMsg{Error: err}
 		}
-		ss.original = *ss.config
+		ss.original = *ss.config.Clone()
 		ss.recalcChangeState()
-		clone := ss.original
-		return ConfigChangedMsg{Config: &clone}
+		return ConfigChangedMsg{Config: ss.config.Clone()}
 	}
 }
 
+// resetSettings перечитывает конфиг с диска: файл мог измениться вне TUI.
 func (ss *SettingsScreen) resetSettings() tea.Cmd {
-	*ss.config = ss.original
+	return func() tea.Msg {
+		cfg, err := config.Load()
+		return settingsReloadedMsg{Config: cfg, Error: err}
+	}
+}
+
+func (ss *SettingsScreen) handleReloaded(msg settingsReloadedMsg) tea.Cmd {
+	if msg.Error != nil || msg.Config == nil {
+		ss.setNotice(fmt.Sprintf("Reload failed: %v", msg.Error), true)
+		return nil
+	}
+	ss.original = *msg.Config.Clone()
+	ss.config = msg.Config.Clone()
 	ss.recalcChangeState()
-	return ss.validateAllFields()
+	ss.setNotice("Reloaded settings from disk", false)
+	clone := msg.Config.Clone()
+	// приложение тоже переходит на конфиг с диска
+	return tea.Batch(ss.validateAllFields(), func() tea.Msg {
+		return ConfigChangedMsg{Config: clone}
+	})
+}
+
+// resetFieldToDefault подставляет в выбранное поле значение из DefaultConfig.
+func (ss *SettingsScreen) resetFieldToDefault() tea.Cmd {
+	field := ss.state.selectedField
+	if field == KeybindingsField {
+		ss.config.Keybindings = config.DefaultConfig().Keybindings
+	} else {
+		_ = ss.setFieldValue(field, defaultValue(field))
+	}
+	ss.recalcChangeState()
+	ss.setNotice(fmt.Sprintf("%s reset to default", ss.fieldName(field)), false)
+	return ss.validateField(field)
+}
+
+func (ss *SettingsScreen) setNotice(text string, isErr bool) {
+	ss.state.notice = text
+	ss.state.noticeErr = isErr
 }
 
 func (ss *SettingsScreen) recalcChangeState() {
@@ -178,3 +251,18 @@ func (ss *SettingsScreen) validateAllFields() tea.Cmd {
 	}
 	return tea.Batch(cmds...)
 }
+
+// HandleGlobalEsc отменяет правку или закрывает список привязок вместо выхода с экрана.
+func (ss *SettingsScreen) HandleGlobalEsc() (bool, tea.Cmd) {
+	switch {
+	case ss.state.editMode:
+		ss.cancelEdit()
+	case ss.state.captureMode:
+		ss.state.captureMode = false
+	case ss.state.bindingMode:
+		ss.state.bindingMode = false
+	default:
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,171 @@
+package screens
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Виджеты правки полей: строки и числа правятся в textinput, числа
+// проверяются на каждом нажатии, булевы поля переключаются Enter/Space
+// без режима правки, Theme и Log Level выбираются стрелками. Пока правка
+// не подтверждена, конфиг не меняется, поэтому Esc оставляет прежнее
+// значение как было.
+
+// settingKind определяет виджет правки поля.
+type settingKind int
+
+const (
+	settingText settingKind = iota
+	settingNumber
+	settingBool
+	settingEnum
+)
+
+func fieldKind(field SettingsField) settingKind {
+	switch field {
+	case SurgeTimeoutField, SurgeStallField, DiagCacheField, TabSizeField, AutoSaveDelayField, MaxFileSizeField, RefreshRateField:
+		return settingNumber
+	case UseSpacesField, AutoIndentField, AutoSaveField, SyntaxHighlightField, DiagOnSaveField,
+		FormatOnSaveField, RestoreSessionField:
+		return settingBool
+	case ThemeField, LogLevelField:
+		return settingEnum
+	default:
+		return settingText
+	}
+}
+
+// fieldOptions — допустимые значения поля-перечисления; темы — встроенные
+// и пользовательские из конфига.
+func (ss *SettingsScreen) fieldOptions(field SettingsField) []string {
+	switch field {
+	case ThemeField:
+		return ss.config.ThemeNames()
+	case LogLevelField:
+		return []string{"debug", "info", "warn", "error"}
+	default:
+		return nil
+	}
+}
+
+// toggleField переключает булево поле.
+func (ss *SettingsScreen) toggleField(field SettingsField) tea.Cmd {
+	value := "true"
+	if parseBool(ss.valueFor(field)) {
+		value = "false"
+	}
+	_ = ss.setFieldValue(field, value)
+	ss.recalcChangeState()
+	return ss.validateField(field)
+}
+
+// startChoice открывает выбор значения перечисления с текущего.
+func (ss *SettingsScreen) startChoice(field SettingsField) {
+	ss.state.editMode = true
+	ss.state.editChoice = 0
+	current := ss.valueFor(field)
+	for i, option := range ss.fieldOptions(field) {
+		if option == current {
+			ss.state.editChoice = i
+		}
+	}
+}
+
+func (ss *SettingsScreen) handleChoiceKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
+	options := ss.fieldOptions(ss.state.selectedField)
+	switch settingsKey(msg) {
+	case "left", "h":
+		ss.state.editChoice = (ss.state.editChoice + len(options) - 1) % len(options)
+	case "right", "l", "space", "tab":
+		ss.state.editChoice = (ss.state.editChoice + 1) % len(options)
+	case "enter":
+		return ss.commitEdit()
+	case "esc":
+		ss.cancelEdit()
+	}
+	return ss, nil
+}
+
+// handleInputKey передаёт нажатие в textinput и сразу проверяет число.
+func (ss *SettingsScreen) handleInputKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
+	var cmd tea.Cmd
+	ss.input, cmd = ss.input.Update(msg)
+	ss.state.editErr = ""
+	if fieldKind(ss.state.selectedField) == settingNumber {
+		if err := ss.checkFieldValue(ss.state.selectedField, ss.input.Value()); err != nil {
+			ss.state.editErr = err.Error()
+		}
+	}
+	return ss, cmd
+}
+
+// editedValue — значение, которое подтвердит Enter.
+func (ss *SettingsScreen) editedValue() string {
+	if fieldKind(ss.state.selectedField) == settingEnum {
+		options := ss.fieldOptions(ss.state.selectedField)
+		return options[ss.state.editChoice]
+	}
+	return ss.input.Value()
+}
+
+// renderFieldValue рисует значение поля вне режима правки.
+func (ss *SettingsScreen) renderFieldValue(field SettingsField, color string) string {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+	value := ss.valueFor(field)
+	switch fieldKind(field) {
+	case settingBool:
+		if parseBool(value) {
+			return style.Render("[x] enabled")
+		}
+		return style.Render("[ ] disabled")
+	case settingEnum:
+		return ss.renderOptions(field, value, color)
+	}
+	return style.Render(value)
+}
+
+// renderFieldEditor рисует виджет правки выбранного поля.
+func (ss *SettingsScreen) renderFieldEditor() string {
+	field := ss.state.selectedField
+	if fieldKind(field) == settingEnum {
+		return ss.renderOptions(field, ss.editedValue(), selectedColor)
+	}
+	view := ss.input.View()
+	if ss.state.editErr != "" {
+		view += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(invalidColor)).Render(ss.state.editErr)
+	}
+	return view
+}
+
+// renderOptions показывает все значения перечисления, выбранное — в скобках.
+func (ss *SettingsScreen) renderOptions(field SettingsField, selected, color string) string {
+	plain := lipgloss.NewStyle().Foreground(lipgloss.Color(unselectedColor))
+	active := lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true)
+	parts := make([]string, 0, len(ss.fieldOptions(field)))
+	for _, option := range ss.fieldOptions(field) {
+		if option == selected {
+			parts = append(parts, active.Render("["+option+"]"))
+		} else {
+			parts = append(parts, plain.Render(" "+option+" "))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// editHint — подсказка клавиш для выбранного поля.
+func (ss *SettingsScreen) editHint() string {
+	kind := fieldKind(ss.state.selectedField)
+	switch {
+	case ss.state.editMode && kind == settingEnum:
+		return "←→: Choose • Enter: Apply • Esc: Cancel"
+	case ss.state.editMode:
+		return "Enter: Save • Esc: Cancel"
+	case kind == settingBool:
+		return "Enter/Space: Toggle"
+	case kind == settingEnum:
+		return "Enter: Choose"
+	}
+	return "Enter: Edit • Space: Edit"
+}
@@ -0,0 +1,156 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"surge-tui/internal/config"
+	"surge-tui/internal/platform"
+)
+
+// Переназначение клавиш в редакторе привязок: Enter ждёт следующее нажатие
+// и делает его клавишей команды. Если клавиша занята в той же области,
+// диалог предлагает обменять клавиши или отменить. Каждая правка сразу
+// записывается в конфиг и применяется приложением.
+
+const (
+	bindingSwapOption   = "Swap"
+	bindingCancelOption = "Cancel"
+)
+
+// bindingSwapMsg — ответ диалога конфликта клавиш.
+type bindingSwapMsg struct {
+	action  string
+	key     string
+	clashes []string
+	swap    bool
+}
+
+// keybindingsSavedMsg — привязки записаны; config — сохранённый конфиг.
+type keybindingsSavedMsg struct {
+	config *config.Config
+}
+
+// CapturingKey сообщает приложению, что следующее нажатие нужно экрану
+// целиком: идёт запись клавиши или открыт диалог конфликта либо ухода.
+func (ss *SettingsScreen) CapturingKey() bool {
+	return ss.state.captureMode || ss.swapDialog.Visible || ss.exitDialog.Visible
+}
+
+func (ss *SettingsScreen) startCapture() {
+	if ss.selectedAction() == "" {
+		return
+	}
+	ss.state.captureMode = true
+}
+
+// handleCaptureKey делает нажатие клавишей выбранной команды; Esc отменяет.
+func (ss *SettingsScreen) handleCaptureKey(msg tea.KeyMsg) (Screen, tea.Cmd) {
+	if msg.Paste {
+		return ss, nil
+	}
+	ss.state.captureMode = false
+	key := platform.CanonicalKeyForLookup(msg.String())
+	if key == "" || key == "esc" {
+		ss.setNotice("Keybinding unchanged", false)
+		return ss, nil
+	}
+	action := ss.selectedAction()
+	return ss, ss.rebind(action, key, fmt.Sprintf("%s → %s", action, platform.DisplayKey(key)))
+}
+
+// commitBindingEdit применяет сочетание, набранное текстом; пустое значение
+// возвращает привязку по умолчанию.
+func (ss *SettingsScreen) commitBindingEdit() (Screen, tea.Cmd) {
+	ss.state.editMode = false
+	ss.input.Blur()
+	action := ss.selectedAction()
+	key := normalizeBinding(ss.input.Value())
+	if key == "" {
+		key = defaultBinding(action)
+	}
+	display := platform.DisplayKey(key)
+	if key == "" {
+		display = "no key"
+	}
+	return ss, ss.rebind(action, key, fmt.Sprintf("%s → %s", action, display))
+}
+
+// rebind назначает клавишу действию и сохраняет привязки; при конфликте
+// сначала спрашивает, обменять ли клавиши.
+func (ss *SettingsScreen) rebind(action, key, notice string) tea.Cmd {
+	if action == "" {
+		return nil
+	}
+	if key == ss.bindingKey(action) {
+		ss.setNotice("Keybinding unchanged", false)
+		return nil
+	}
+	if clashes := ss.bindingClashes(action, key); len(clashes) > 0 {
+		return ss.askSwap(action, key, clashes)
+	}
+	ss.assignKey(action, key)
+	ss.recalcChangeState()
+	ss.setNotice(notice, false)
+	return ss.saveBindings()
+}
+
+func (ss *SettingsScreen) askSwap(action, key string, clashes []string) tea.Cmd {
+	titles := make([]string, len(clashes))
+	for i, other := range clashes {
+		titles[i] = ss.bindingTitle(other)
+	}
+	previous := platform.DisplayKey(ss.bindingKey(action))
+	if strings.TrimSpace(previous) == "" {
+		previous = "no key"
+	}
+	ss.swapDialog.Description = fmt.Sprintf("%s is already bound to %s.\nSwap: %s gets %s and %s gets %s.",
+		platform.DisplayKey(key), strings.Join(titles, ", "),
+		ss.bindingTitle(action), platform.DisplayKey(key), strings.Join(titles, ", "), previous)
+	ss.swapDialog.Options = []string{bindingSwapOption, bindingCancelOption}
+	ch := ss.swapDialog.Show()
+	return func() tea.Msg {
+		choice := <-ch
+		return bindingSwapMsg{action: action, key: key, clashes: clashes, swap: choice == 0}
+	}
+}
+
+func (ss *SettingsScreen) handleBindingSwap(msg bindingSwapMsg) tea.Cmd {
+	if !msg.swap {
+		ss.setNotice("Keybinding unchanged", false)
+		return nil
+	}
+	previous := ss.bindingKey(msg.action)
+	for _, other := range msg.clashes {
+		ss.assignKey(other, previous)
+	}
+	ss.assignKey(msg.action, msg.key)
+	ss.recalcChangeState()
+	ss.setNotice(fmt.Sprintf("%s → %s, swapped with %s", msg.action, platform.DisplayKey(msg.key), strings.Join(msg.clashes, ", ")), false)
+	return ss.saveBindings()
+}
+
+// saveBindings записывает привязки поверх сохранённого конфига: остальные
+// несохранённые правки экрана в файл не попадают.
+func (ss *SettingsScreen) saveBindings() tea.Cmd {
+	saved := ss.original.Clone()
+	saved.Keybindings = ss.config.Clone().Keybindings
+	saved.Commands = ss.config.Clone().Commands
+	return func() tea.Msg {
+		if err := saved.SaveDefault(); err != nil {
+			return settingsErrorMsg{Error: err}
+		}
+		return keybindingsSavedMsg{config: saved}
+	}
+}
+
+// handleBindingsSaved запоминает сохранённые привязки и передаёт конфиг
+// приложению, которое перестраивает реестр команд.
+func (ss *SettingsScreen) handleBindingsSaved(msg keybindingsSavedMsg) tea.Cmd {
+	ss.original.Keybindings = msg.config.Clone().Keybindings
+	ss.original.Commands = msg.config.Clone().Commands
+	ss.recalcChangeState()
+	clone := msg.config.Clone()
+	return func() tea.Msg { return ConfigChangedMsg{Config: clone} }
+}
@@ -0,0 +1,512 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"surge-tui/internal/config"
+	"surge-tui/internal/platform"
+)
+
+const (
+	keybindingWarningBadge  = "⚠"
+	keybindingConflictBadge = "⇄"
+)
+
+// UserCommandsGroup — группа пользовательских команд (секция commands)
+// в редакторе привязок; их клавиши хранятся в самих командах.
+const UserCommandsGroup = "User Commands"
+
+// userBindingPrefix отличает пользовательские команды от действий keybindings.
+const userBindingPrefix = "user:"
+
+// bindingRow — строка редактора привязок: действие, название команды и группа.
+type bindingRow struct {
+	action string
+	title  string
+	group  string
+}
+
+// SetCommandCatalog задаёт источник названий и групп команд реестра:
+// ID пункта — имя привязки, Context — группа (Global или экран).
+func (ss *SettingsScreen) SetCommandCatalog(fetch CommandFetcher) {
+	ss.catalog = fetch
+	ss.refreshCatalog()
+}
+
+// refreshCatalog перечитывает реестр: после смены конфига команды
+// регистрируются заново.
+func (ss *SettingsScreen) refreshCatalog() {
+	ss.catalogEntries = make(map[string]CommandEntry)
+	if ss.catalog == nil {
+		return
+	}
+	for _, entry := range ss.catalog() {
+		ss.catalogEntries[entry.ID] = entry
+	}
+}
+
+// openBindings переводит экран в список привязок.
+func (ss *SettingsScreen) openBindings() {
+	ss.refreshCatalog()
+	ss.state.selectedField = KeybindingsField
+	ss.state.bindingMode = true
+}
+
+// FocusField выбирает поле field в списке настроек, закрывая редактор
+// привязок и правку значения.
+func (ss *SettingsScreen) FocusField(field SettingsField) {
+	if field == KeybindingsField {
+		ss.FocusKeybindings()
+		return
+	}
+	ss.cancelEdit()
+	ss.state.selectedField = field
+	ss.state.bindingMode = false
+	ss.state.captureMode = false
+}
+
+// FocusKeybindings открывает редактор привязок на первой ненадёжной или
+// конфликтующей привязке.
+func (ss *SettingsScreen) FocusKeybindings() {
+	ss.openBindings()
+	ss.state.bindingIndex = 0
+	warnings := ss.keybindingWarnings()
+	conflicts := ss.keybindingConflicts()
+	for i, action := range ss.bindingActions() {
+		_, risky := warnings[action]
+		_, clash := conflicts[action]
+		if risky || clash {
+			ss.state.bindingIndex = i
+			break
+		}
+	}
+}
+
+// bindingRows возвращает привязки по группам: Global, экраны по алфавиту,
+// затем пользовательские команды; внутри группы — по названию команды.
+func (ss *SettingsScreen) bindingRows() []bindingRow {
+	rows := make([]bindingRow, 0, len(ss.config.Keybindings)+len(ss.config.Commands))
+	for action := range ss.config.Keybindings {
+		row := bindingRow{action: action, title: action}
+		if entry, ok := ss.catalogEntries[action]; ok {
+			row.title, row.group = entry.Title, entry.Context
+		}
+		if row.group == "" {
+			scope, _ := config.KeybindingScope(action)
+			row.group = scopeGroup(scope)
+		}
+		rows = append(rows, row)
+	}
+	for _, command := range ss.config.Commands {
+		title := command.Title
+		if title == "" {
+			title = command.ID
+		}
+		rows = append(rows, bindingRow{action: userBindingPrefix + command.ID, title: title, group: UserCommandsGroup})
+	}
+	slices.SortFunc(rows, func(a, b bindingRow) int {
+		if ra, rb := groupRank(a.group), groupRank(b.group); ra != rb {
+			return ra - rb
+		}
+		if c := strings.Compare(a.group, b.group); c != 0 {
+			return c
+		}
+		if c := strings.Compare(strings.ToLower(a.title), strings.ToLower(b.title)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.action, b.action)
+	})
+	return rows
+}
+
+// scopeGroup — название группы для области привязок без команды в реестре.
+func scopeGroup(scope string) string {
+	if scope == config.GlobalKeyScope || scope == "" {
+		return "Global"
+	}
+	return strings.ToUpper(scope[:1]) + scope[1:]
+}
+
+func groupRank(group string) int {
+	switch group {
+	case "Global":
+		return 0
+	case UserCommandsGroup:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func (ss *SettingsScreen) bindingActions() []string {
+	rows := ss.bindingRows()
+	actions := make([]string, len(rows))
+	for i, row := range rows {
+		actions[i] = row.action
+	}
+	return actions
+}
+
+func (ss *SettingsScreen) selectedAction() string {
+	actions := ss.bindingActions()
+	if len(actions) == 0 {
+		return ""
+	}
+	ss.state.bindingIndex = clamp(ss.state.bindingIndex, 0, len(actions)-1)
+	return actions[ss.state.bindingIndex]
+}
+
+// bindingKey возвращает клавишу действия или пользовательской команды.
+func (ss *SettingsScreen) bindingKey(action string) string {
+	if id, ok := strings.CutPrefix(action, userBindingPrefix); ok {
+		for _, command := range ss.config.Commands {
+			if command.ID == id {
+				return command.Key
+			}
+		}
+		return ""
+	}
+	return ss.config.Keybindings[action]
+}
+
+// originalBindingKey — клавиша действия в сохранённом конфиге.
+func (ss *SettingsScreen) originalBindingKey(action string) string {
+	if id, ok := strings.CutPrefix(action, userBindingPrefix); ok {
+		for _, command := range ss.original.Commands {
+			if command.ID == id {
+				return command.Key
+			}
+		}
+		return ""
+	}
+	return ss.original.Keybindings[action]
+}
+
+// assignKey назначает клавишу действию или пользовательской команде.
+func (ss *SettingsScreen) assignKey(action, key string) {
+	if id, ok := strings.CutPrefix(action, userBindingPrefix); ok {
+		for i := range ss.config.Commands {
+			if ss.config.Commands[i].ID == id {
+				ss.config.Commands[i].Key = key
+			}
+		}
+		return
+	}
+	ss.config.Keybindings[action] = key
+}
+
+// bindingScope — область действия; пользовательские команды глобальны.
+func bindingScope(action string) string {
+	if strings.HasPrefix(action, userBindingPrefix) {
+		return config.GlobalKeyScope
+	}
+	scope, _ := config.KeybindingScope(action)
+	return scope
+}
+
+func (ss *SettingsScreen) keybindingWarnings() map[string]config.KeybindingWarning {
+	warnings := make(map[string]config.KeybindingWarning)
+	for _, w := range ss.config.KeybindingWarnings(ss.terminal) {
+		warnings[w.Action] = w
+	}
+	return warnings
+}
+
+// keybindingConflicts возвращает конфликт для каждого действия, чья клавиша
+// занята другим действием той же области.
+func (ss *SettingsScreen) keybindingConflicts() map[string]config.KeybindingConflict {
+	conflicts := make(map[string]config.KeybindingConflict)
+	for _, c := range ss.config.KeybindingConflicts() {
+		for _, action := range c.Actions {
+			conflicts[action] = c
+		}
+	}
+	return conflicts
+}
+
+// bindingClashes перечисляет другие действия области action, которым
+// назначена клавиша key.
+func (ss *SettingsScreen) bindingClashes(action, key string) []string {
+	canonical := platform.CanonicalKeyForLookup(key)
+	if canonical == "" {
+		return nil
+	}
+	scope := bindingScope(action)
+	var clashes []string
+	for _, other := range ss.bindingActions() {
+		if other == action || bindingScope(other) != scope {
+			continue
+		}
+		if platform.CanonicalKeyForLookup(ss.bindingKey(other)) == canonical {
+			clashes = append(clashes, other)
+		}
+	}
+	return clashes
+}
+
+// handleBindingKeys — навигация по списку привязок; Enter ждёт новое
+// сочетание, E — ввод сочетания текстом.
+func (ss *SettingsScreen) handleBindingKeys(msg tea.KeyMsg) (Screen, tea.Cmd) {
+	key := platform.CanonicalKeyForLookup(msg.String())
+	ss.setNotice("", false)
+	count := len(ss.bindingActions())
+	switch key {
+	case "up", "k":
+		ss.state.bindingIndex = max(ss.state.bindingIndex-1, 0)
+	case "down", "j":
+		ss.state.bindingIndex = min(ss.state.bindingIndex+1, count-1)
+	case "home":
+		ss.state.bindingIndex = 0
+	case "end":
+		ss.state.bindingIndex = max(count-1, 0)
+	case "enter", "space":
+		ss.startCapture()
+	case "e":
+		return ss.editBinding()
+	case "a":
+		return ss, ss.applySuggestion()
+	case "d":
+		return ss, ss.resetBinding()
+	case "R":
+		return ss, ss.resetAllBindings()
+	case "s", "ctrl+s":
+		return ss, ss.saveSettings()
+	case "esc":
+		ss.state.bindingMode = false
+	}
+	return ss, nil
+}
+
+func (ss *SettingsScreen) editBinding() (Screen, tea.Cmd) {
+	action := ss.selectedAction()
+	if action == "" {
+		return ss, nil
+	}
+	ss.state.editMode = true
+	ss.input.SetValue(ss.bindingKey(action))
+	ss.input.CursorEnd()
+	ss.input.Focus()
+	return ss, nil
+}
+
+// normalizeBinding приводит введённое сочетание к виду конфига. Регистр
+// сохраняется только у одиночной буквы: "N" — это Shift+N.
+func normalizeBinding(value string) string {
+	value = strings.TrimSpace(value)
+	if len([]rune(value)) > 1 {
+		value = strings.ToLower(value)
+	}
+	return value
+}
+
+// defaultBinding — клавиша действия по умолчанию; у пользовательских
+// команд умолчания нет.
+func defaultBinding(action string) string {
+	if strings.HasPrefix(action, userBindingPrefix) {
+		return ""
+	}
+	return config.DefaultConfig().Keybindings[action]
+}
+
+func (ss *SettingsScreen) resetBinding() tea.Cmd {
+	action := ss.selectedAction()
+	if action == "" {
+		return nil
+	}
+	if strings.HasPrefix(action, userBindingPrefix) {
+		ss.setNotice("User commands have no default key; Enter to rebind", true)
+		return nil
+	}
+	return ss.rebind(action, defaultBinding(action), action+" reset to default")
+}
+
+// resetAllBindings возвращает все привязки keybindings к умолчаниям;
+// клавиши пользовательских команд не трогаются.
+func (ss *SettingsScreen) resetAllBindings() tea.Cmd {
+	ss.config.Keybindings = config.DefaultConfig().Keybindings
+	ss.recalcChangeState()
+	ss.setNotice("All keybindings reset to defaults", false)
+	return ss.saveBindings()
+}
+
+func (ss *SettingsScreen) applySuggestion() tea.Cmd {
+	action := ss.selectedAction()
+	warning, ok := ss.keybindingWarnings()[action]
+	if !ok || warning.Suggestion == "" {
+		ss.setNotice("No suggestion for "+action, true)
+		return nil
+	}
+	return ss.rebind(action, warning.Suggestion, fmt.Sprintf("%s → %s", action, platform.DisplayKey(warning.Suggestion)))
+}
+
+// keybindingsSummary — значение поля для сравнения с сохранённым конфигом.
+func keybindingsSummary(cfg *config.Config) string {
+	actions := make([]string, 0, len(cfg.Keybindings)+len(cfg.Commands))
+	for action, key := range cfg.Keybindings {
+		actions = append(actions, action+"="+platform.CanonicalKeyForLookup(key))
+	}
+	for _, command := range cfg.Commands {
+		actions = append(actions, userBindingPrefix+command.ID+"="+platform.CanonicalKeyForLookup(command.Key))
+	}
+	slices.Sort(actions)
+	return strings.Join(actions, ", ")
+}
+
+// renderBindings рисует список привязок по группам; ненадёжные и
+// конфликтующие помечены, для выбранной выводится причина и замена.
+func (ss *SettingsScreen) renderBindings() string {
+	rows := ss.bindingRows()
+	warnings := ss.keybindingWarnings()
+	conflicts := ss.keybindingConflicts()
+	selected := ss.selectedAction()
+
+	var b strings.Builder
+	noticeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(modifiedColor))
+	if len(warnings) > 0 {
+		b.WriteString(noticeStyle.Render(fmt.Sprintf("%s %s may not reach the app in %s",
+			keybindingWarningBadge, plural(len(warnings), "binding"), ss.terminal.Name())))
+		b.WriteString("\n")
+	}
+	if len(conflicts) > 0 {
+		b.WriteString(noticeStyle.Render(fmt.Sprintf("%s %s share a key with another action in the same scope",
+			keybindingConflictBadge, plural(len(conflicts), "binding"))))
+		b.WriteString("\n")
+	}
+	if len(warnings) > 0 || len(conflicts) > 0 {
+		b.WriteString("\n")
+	}
+
+	// строки списка вместе с заголовками групп; row < 0 — заголовок
+	type line struct {
+		row   int
+		group string
+	}
+	var lines []line
+	selectedLine := 0
+	for i, row := range rows {
+		if i == 0 || rows[i-1].group != row.group {
+			lines = append(lines, line{row: -1, group: row.group})
+		}
+		if i == ss.state.bindingIndex {
+			selectedLine = len(lines)
+		}
+		lines = append(lines, line{row: i})
+	}
+
+	visible := max(ss.Height()-16, 5)
+	start := 0
+	if selectedLine >= visible {
+		start = selectedLine - visible + 1
+	}
+	end := min(start+visible, len(lines))
+
+	titleWidth, actionWidth := 0, 0
+	for _, row := range rows {
+		titleWidth = max(titleWidth, len([]rune(row.title)))
+		actionWidth = max(actionWidth, len(row.action))
+	}
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(unselectedColor))
+	for _, l := range lines[start:end] {
+		if l.row < 0 {
+			b.WriteString(lipgloss.NewStyle().Bold(true).Render(l.group))
+			b.WriteString("\n")
+			continue
+		}
+		row := rows[l.row]
+		key := ss.bindingKey(row.action)
+		display := platform.DisplayKey(key)
+		if strings.TrimSpace(key) == "" {
+			display = "—"
+		}
+		name := row.action
+		if row.title == row.action {
+			name = "" // действие без команды в реестре: имя уже в первой колонке
+		}
+		text := fmt.Sprintf("%-*s  %-*s  %s", titleWidth, row.title, actionWidth, name, display)
+		if _, risky := warnings[row.action]; risky {
+			text += " " + keybindingWarningBadge
+		}
+		if _, clash := conflicts[row.action]; clash {
+			text += " " + keybindingConflictBadge
+		}
+		style := dim
+		switch {
+		case ss.state.bindingMode && row.action == selected:
+			style = style.Foreground(lipgloss.Color(selectedColor)).Bold(true)
+			text = "> " + text
+		case key != ss.originalBindingKey(row.action):
+			style = style.Foreground(lipgloss.Color(modifiedColor))
+			text = "  " + text
+		default:
+			text = "  " + text
+		}
+		b.WriteString(style.Render(text))
+		b.WriteString("\n")
+	}
+	if end < len(lines) || start > 0 {
+		b.WriteString(dim.Render(fmt.Sprintf("  %d-%d of %d", start+1, end, len(lines))))
+		b.WriteString("\n")
+	}
+
+	if ss.state.captureMode {
+		b.WriteString("\n")
+		b.WriteString(noticeStyle.Render("Press the new key for " + ss.bindingTitle(selected) + " (Esc cancels)"))
+		b.WriteString("\n")
+	}
+	if warning, ok := warnings[selected]; ok && ss.state.bindingMode {
+		b.WriteString("\n")
+		b.WriteString(renderChordWarning(warning.ChordWarning, true))
+	}
+	if _, ok := conflicts[selected]; ok && ss.state.bindingMode {
+		b.WriteString("\n")
+		b.WriteString(renderBindingClash(ss.bindingClashes(selected, ss.bindingKey(selected))))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// bindingTitle — название команды действия для подсказок.
+func (ss *SettingsScreen) bindingTitle(action string) string {
+	for _, row := range ss.bindingRows() {
+		if row.action == action && row.title != action {
+			return fmt.Sprintf("%s (%s)", row.title, action)
+		}
+	}
+	return action
+}
+
+// renderBindingInput показывает поле ввода и сразу проверяет набранное сочетание.
+func (ss *SettingsScreen) renderBindingInput() string {
+	var b strings.Builder
+	b.WriteString(ss.selectedAction())
+	b.WriteString(": ")
+	b.WriteString(ss.input.View())
+	if warning, risky := platform.CheckChord(ss.input.Value(), ss.terminal); risky {
+		b.WriteString("\n\n")
+		b.WriteString(renderChordWarning(warning, false))
+	}
+	if clashes := ss.bindingClashes(ss.selectedAction(), ss.input.Value()); len(clashes) > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(renderBindingClash(clashes))
+	}
+	return b.String()
+}
+
+func renderBindingClash(clashes []string) string {
+	text := keybindingConflictBadge + " also bound to " + strings.Join(clashes, ", ")
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(invalidColor)).Render(text)
+}
+
+func renderChordWarning(warning platform.ChordWarning, applyHint bool) string {
+	text := keybindingWarningBadge + " " + warning.Reason
+	if warning.Suggestion != "" {
+		text += "; try " + platform.DisplayKey(warning.Suggestion)
+		if applyHint {
+			text += " (A to apply)"
+		}
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(invalidColor)).Render(text)
+}
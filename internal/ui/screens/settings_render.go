@@ -23,6 +23,11 @@ func (ss *SettingsScreen) renderMenu() string {
 		} else {
 			style = style.Foreground(lipgloss.Color(unselectedColor))
 		}
+		if field == KeybindingsField {
+			if n := len(ss.keybindingWarnings()); n > 0 {
+				name = fmt.Sprintf("%s %s%d", name, keybindingWarningBadge, n)
+			}
+		}
 		if ss.isFieldChanged(field) {
 			name = fmt.Sprintf("* %s", name)
 		}
@@ -43,7 +48,6 @@ func (ss *SettingsScreen) renderContent() string {
 		width = ss.Width() - ss.state.menuWidth
 	}
 
-	currentValue := ss.getCurrentValue()
 	description := ss.fieldDescription()
 
 	content := &strings.Builder{}
@@ -58,11 +62,15 @@ func (ss *SettingsScreen) renderContent() string {
 		valueColor = modifiedColor
 	}
 
-	if ss.state.editMode {
-		content.WriteString(ss.input.View())
-	} else {
-		valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(valueColor))
-		content.WriteString(valueStyle.Render(currentValue))
+	switch {
+	case ss.state.selectedField == KeybindingsField && ss.state.editMode:
+		content.WriteString(ss.renderBindingInput())
+	case ss.state.selectedField == KeybindingsField:
+		content.WriteString(ss.renderBindings())
+	case ss.state.editMode:
+		content.WriteString(ss.renderFieldEditor())
+	default:
+		content.WriteString(ss.renderFieldValue(ss.state.selectedField, valueColor))
 	}
 
 	content.WriteString("\n\n")
@@ -87,10 +95,24 @@ func (ss *SettingsScreen) renderContent() string {
 		content.WriteString(stamp)
 	}
 
+	if ss.state.notice != "" {
+		content.WriteString("\n\n")
+		color := validColor
+		if ss.state.noticeErr {
+			color = invalidColor
+		}
+		content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(ss.state.notice))
+	}
+
 	content.WriteString("\n\n")
-	hint := "Enter: Edit • Space: Edit"
-	if ss.state.editMode {
+	hint := ss.editHint()
+	switch {
+	case ss.state.selectedField == KeybindingsField && ss.state.editMode:
 		hint = "Enter: Save • Esc: Cancel"
+	case ss.state.captureMode:
+		hint = "Press the new key • Esc: Cancel"
+	case ss.state.bindingMode:
+		hint = "↑↓: Select • Enter: Press new key • E: Type key • A: Apply suggestion • D: Default • R: Reset all • Esc: Back"
 	}
 	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(unselectedColor))
 	content.WriteString(hintStyle.Render(hint))
@@ -1,11 +1,15 @@
 package screens
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"surge-tui/internal/config"
+	"surge-tui/internal/logging"
 	"surge-tui/internal/platform"
+	"surge-tui/internal/ui/components"
 )
 
 // SettingsScreen renders and edits application configuration.
@@ -17,11 +21,18 @@ type SettingsScreen struct {
 
 	state SettingsScreenState
 	input textinput.Model
+
+	terminal platform.Terminal // для предупреждений о привязках
+
+	// команды реестра для редактора привязок: названия и группы по имени привязки
+	catalog        CommandFetcher
+	catalogEntries map[string]CommandEntry
+	swapDialog     *components.ChoiceDialog
+	exitDialog     *components.ChoiceDialog // уход с несохранёнными правками
 }
 
 // NewSettingsScreen constructs settings UI with editable copy of config.
 func NewSettingsScreen(cfg *config.Config) *SettingsScreen {
-	editable := *cfg
 
 	ti := textinput.New()
 	ti.Prompt = ""
@@ -29,9 +40,12 @@ func NewSettingsScreen(cfg *config.Config) *SettingsScreen {
 
 	screen := &SettingsScreen{
 		BaseScreen: NewBaseScreen("Settings"),
-		config:     &editable,
-		original:   *cfg,
+		config:     cfg.Clone(),
+		original:   *cfg.Clone(),
 		input:      ti,
+		terminal:   platform.DetectTerminal(),
+		swapDialog: components.NewChoiceDialog("Keybinding Conflict", ""),
+		exitDialog: newSettingsExitDialog(),
 	}
 
 	screen.state.validation = make(map[SettingsField]ValidationResult)
@@ -49,9 +63,21 @@ func (ss *SettingsScreen) Init() tea.Cmd {
 func (ss *SettingsScreen) Update(msg tea.Msg) (Screen, tea.Cmd) {
 	switch m := msg.(type) {
 	case tea.KeyMsg:
+		if ss.exitDialog.Visible {
+			return ss, ss.exitDialog.Update(m)
+		}
+		if ss.swapDialog.Visible {
+			return ss, ss.swapDialog.Update(m)
+		}
+		if ss.state.captureMode {
+			return ss.handleCaptureKey(m)
+		}
 		if ss.state.editMode {
 			return ss.handleEditMode(m)
 		}
+		if ss.state.bindingMode {
+			return ss.handleBindingKeys(m)
+		}
 		return ss.handleKeyPress(m)
 	case tea.WindowSizeMsg:
 		ss.handleResize(m)
@@ -66,13 +92,21 @@ func (ss *SettingsScreen) Update(msg tea.Msg) (Screen, tea.Cmd) {
 		}
 		return ss, nil
 	case settingsErrorMsg:
-		// TODO: surface error via notification system
-		_ = m.Error
+		logging.Errorf("save config: %v", m.Error)
+		ss.setNotice(fmt.Sprintf("Save failed: %v", m.Error), true)
 		return ss, nil
+	case settingsReloadedMsg:
+		return ss, ss.handleReloaded(m)
+	case settingsExitMsg:
+		return ss, ss.handleExitChoice(m)
+	case bindingSwapMsg:
+		return ss, ss.handleBindingSwap(m)
+	case keybindingsSavedMsg:
+		return ss, ss.handleBindingsSaved(m)
 	case ConfigChangedMsg:
 		if m.Config != nil {
-			ss.original = *m.Config
-			*ss.config = *m.Config
+			ss.original = *m.Config.Clone()
+			ss.config = m.Config.Clone()
 		}
 		ss.recalcChangeState()
 		return ss, ss.validateAllFields()
@@ -87,7 +121,14 @@ func (ss *SettingsScreen) View() string {
 	}
 	left := ss.renderMenu()
 	right := ss.renderContent()
-	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+	view := lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+	if ss.exitDialog.Visible {
+		return joinOverlay(view, ss.exitDialog.View())
+	}
+	if ss.swapDialog.Visible {
+		return joinOverlay(view, ss.swapDialog.View())
+	}
+	return view
 }
 
 // Title reflects pending changes.
@@ -100,7 +141,7 @@ func (ss *SettingsScreen) Title() string {
 
 // ShortHelp returns quick help line.
 func (ss *SettingsScreen) ShortHelp() string {
-	return "↑↓: Navigate • Enter: Edit • S: Save • R: Reset • T: Toggle theme"
+	return "↑↓: Navigate • Enter: Edit • S: Save • R: Reload • D: Default • T: Toggle theme"
 }
 
 // FullHelp details controls.
@@ -110,17 +151,27 @@ func (ss *SettingsScreen) FullHelp() []string {
 		"",
 		"Settings Screen:",
 		"  ↑/↓ or j/k - Navigate between settings",
-		"  Enter or Space - Edit selected setting",
+		"  Enter or Space - Edit selected setting, toggle an on/off setting",
 		platform.ReplacePrimaryModifier("  S or Ctrl+S - Save settings to file"),
-		platform.ReplacePrimaryModifier("  R or Ctrl+R - Reset to original values"),
-		"  T - Quick toggle theme (dark/light)",
-		"  Escape - Cancel edit or exit",
+		platform.ReplacePrimaryModifier("  R or Ctrl+R - Reload settings from disk"),
+		"  D - Reset selected setting to default",
+		"  T - Cycle theme (dark, light, then themes from config)",
+		"  Escape - Cancel edit or exit; unsaved changes ask Save / Discard / Cancel",
+		"",
+		"Keybindings:",
+		"  ↑/↓ - Select command, Enter - Press its new key (Esc cancels)",
+		"  E - Type the key instead (for chords the terminal cannot send)",
+		"  A key taken in the same scope offers to swap the two bindings",
+		"  A - Apply suggested key for a " + keybindingWarningBadge + " binding",
+		"  D - Reset binding to default • Shift+R - Reset all bindings",
+		"  Changes are saved and applied at once",
+		"  Escape - Back to settings list",
 		"",
 		"Edit Mode:",
-		"  Enter - Confirm changes",
-		"  Escape - Cancel changes",
+		"  Enter - Confirm changes (invalid numbers are shown in red and not applied)",
+		"  Escape - Cancel changes, the previous value stays",
+		"  ←/→ - Choose Theme or Log Level, move cursor in text fields",
 		"  Backspace/Delete - Remove character",
-		"  Arrow keys - Move cursor",
 	}...)
 	return help
 }
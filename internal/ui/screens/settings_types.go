@@ -12,16 +12,25 @@ type SettingsField int
 const (
 	ThemeField SettingsField = iota
 	SurgeBinaryField
+	SurgeTimeoutField
+	SurgeStallField
+	DiagCacheField
 	DefaultProjectField
 	TabSizeField
 	UseSpacesField
+	AutoIndentField
 	AutoSaveField
 	AutoSaveDelayField
 	ExternalEditorField
 	SyntaxHighlightField
+	WordCharsField
+	DiagOnSaveField
+	FormatOnSaveField
+	RestoreSessionField
 	MaxFileSizeField
 	RefreshRateField
 	LogLevelField
+	KeybindingsField
 )
 
 // ValidationResult stores validation status for a field.
@@ -42,6 +51,12 @@ type settingsErrorMsg struct {
 	Error error
 }
 
+// settingsReloadedMsg carries config re-read from disk by Reset.
+type settingsReloadedMsg struct {
+	Config *config.Config
+	Error  error
+}
+
 // ConfigChangedMsg notifies app that settings updated.
 type ConfigChangedMsg struct {
 	Config *config.Config
@@ -51,9 +66,16 @@ type ConfigChangedMsg struct {
 type SettingsScreenState struct {
 	selectedField SettingsField
 	editMode      bool
+	bindingMode   bool // список привязок в KeybindingsField
+	bindingIndex  int
+	captureMode   bool   // ждём нажатие для выбранной привязки
+	editChoice    int    // выбранный вариант поля-перечисления
+	editErr       string // ошибка проверки вводимого значения
 	hasChanges    bool
 	validation    map[SettingsField]ValidationResult
 	surgeCheck    time.Time
 	menuWidth     int
 	contentWidth  int
+	notice        string
+	noticeErr     bool
 }
@@ -0,0 +1,72 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	core "surge-tui/internal/core/surge"
+)
+
+// Экран, запустивший surge, подписывается на сторожа клиента: если surge
+// долго молчит, в строке статуса появляется предупреждение, и Esc отменяет
+// запуск. Процесс при этом не убивается — можно просто подождать.
+
+// stallMsg — очередное сообщение сторожа запуска watch.
+type stallMsg struct {
+	watch *stallWatch
+	stall core.Stall
+}
+
+// stallWatch передаёт сообщения сторожа из горутины запуска в Update.
+// Хранится только последнее: важно, сколько surge молчит сейчас.
+type stallWatch struct {
+	ch   chan core.Stall
+	done chan struct{}
+	once sync.Once
+}
+
+// watchStalls подписывает запуски с контекстом ctx на сторожа и возвращает
+// контекст для них вместе с командой, ждущей первое сообщение.
+func watchStalls(ctx context.Context) (*stallWatch, context.Context, tea.Cmd) {
+	w := &stallWatch{ch: make(chan core.Stall, 1), done: make(chan struct{})}
+	ctx = core.WithStallWatch(ctx, func(stall core.Stall) {
+		select {
+		case <-w.ch:
+		default:
+		}
+		select {
+		case w.ch <- stall:
+		default:
+		}
+	})
+	return w, ctx, w.wait()
+}
+
+// wait ждёт следующее сообщение; после stop команда завершается без него.
+func (w *stallWatch) wait() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case stall := <-w.ch:
+			return stallMsg{watch: w, stall: stall}
+		case <-w.done:
+			return nil
+		}
+	}
+}
+
+func (w *stallWatch) stop() {
+	if w != nil {
+		w.once.Do(func() { close(w.done) })
+	}
+}
+
+// stallWarning — предупреждение для строки статуса; пусто, если вывод снова пошёл.
+func stallWarning(stall core.Stall) string {
+	if stall.Silent <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s has produced no output for %s — Esc to cancel, or keep waiting", stall.Command, stall.Silent)
+}
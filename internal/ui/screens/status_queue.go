@@ -0,0 +1,218 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Очередь сообщений строки статуса. Быстрые операции подряд (сохранение →
+// запуск диагностики → автосохранение) не затирают друг друга: каждое
+// сообщение показывается своё время, а если за ним ждут другие — хотя бы
+// треть этого времени. Ошибки держатся дольше. Одинаковые сообщения подряд
+// склеиваются в одно со счётчиком, последние сообщения можно вызвать снова
+// командой «Recent Messages».
+
+const (
+	statusQueueDepth   = 4  // сообщений в очереди вместе с показанным
+	statusHistoryDepth = 20 // сообщений в истории
+)
+
+// statusSeverity задаёт, сколько сообщение висит в строке статуса.
+type statusSeverity int
+
+const (
+	statusInfo statusSeverity = iota
+	statusWarning
+	statusError
+)
+
+func (s statusSeverity) duration() time.Duration {
+	switch s {
+	case statusError:
+		return 8 * time.Second
+	case statusWarning:
+		return 5 * time.Second
+	default:
+		return 3 * time.Second
+	}
+}
+
+// color — цвет сообщения в строке статуса дерева.
+func (s statusSeverity) color() string {
+	switch s {
+	case statusError:
+		return ErrorColor
+	case statusWarning:
+		return diagWarningColor
+	default:
+		return DimTextColor
+	}
+}
+
+// hold — сколько сообщение держится, если за ним в очереди ждут другие.
+func (s statusSeverity) hold() time.Duration {
+	return s.duration() / 3
+}
+
+// statusSeverityOf определяет важность сообщения по тексту: экраны
+// сообщают о результатах одной строкой, и ошибки в ней узнаются по словам.
+func statusSeverityOf(text string) statusSeverity {
+	lower := strings.ToLower(text)
+	for _, word := range []string{"fail", "error", "cannot", "can't", "unable", "not found", "denied", "invalid"} {
+		if strings.Contains(lower, word) {
+			return statusError
+		}
+	}
+	for _, word := range []string{"warning", "conflict", "aborted", "unavailable", "skipped"} {
+		if strings.Contains(lower, word) {
+			return statusWarning
+		}
+	}
+	return statusInfo
+}
+
+type statusEntry struct {
+	text     string
+	severity statusSeverity
+	count    int
+	at       time.Time
+}
+
+// display — текст для строки статуса: повторы отмечаются счётчиком.
+func (e statusEntry) display() string {
+	if e.count > 1 {
+		return fmt.Sprintf("%s (×%d)", e.text, e.count)
+	}
+	return e.text
+}
+
+// statusTickMsg будит экран, когда показанное сообщение пора сменить.
+type statusTickMsg struct{}
+
+// statusQueue — очередь сообщений одного экрана. sticky оставляет
+// последнее сообщение на месте, пока не придёт следующее.
+type statusQueue struct {
+	pending []statusEntry // pending[0] сейчас в строке статуса
+	shownAt time.Time     // когда pending[0] появилось в строке
+	history []statusEntry // последние сообщения, новые в конце
+	tickAt  time.Time     // на когда запланирован statusTickMsg
+	sticky  bool
+}
+
+// push ставит сообщение в очередь; пустой текст очищает строку статуса.
+func (q *statusQueue) push(text string, severity statusSeverity) {
+	now := time.Now()
+	if text == "" {
+		q.pending = nil
+		return
+	}
+	q.advance(now)
+	if n := len(q.pending); n > 0 && q.pending[n-1].text == text {
+		q.pending[n-1].count++
+		if severity > q.pending[n-1].severity {
+			q.pending[n-1].severity = severity
+		}
+		if n == 1 {
+			q.shownAt = now
+		}
+		if h := len(q.history); h > 0 && q.history[h-1].text == text {
+			q.history[h-1].count++
+			q.history[h-1].at = now
+		}
+		return
+	}
+	entry := statusEntry{text: text, severity: severity, count: 1, at: now}
+	if len(q.pending) == 0 || (q.sticky && len(q.pending) == 1 && q.expired(now)) {
+		q.pending = q.pending[:0]
+		q.shownAt = now
+	}
+	q.pending = append(q.pending, entry)
+	if len(q.pending) > statusQueueDepth {
+		// показанное остаётся, пропадает самое старое из ждущих
+		q.pending = append(q.pending[:1], q.pending[2:]...)
+	}
+	q.history = append(q.history, entry)
+	if len(q.history) > statusHistoryDepth {
+		q.history = q.history[len(q.history)-statusHistoryDepth:]
+	}
+}
+
+// limit — сколько ещё может висеть показанное сообщение.
+func (q *statusQueue) limit() time.Duration {
+	if len(q.pending) > 1 {
+		return q.pending[0].severity.hold()
+	}
+	return q.pending[0].severity.duration()
+}
+
+func (q *statusQueue) expired(now time.Time) bool {
+	return len(q.pending) > 0 && now.Sub(q.shownAt) >= q.limit()
+}
+
+// advance снимает сообщения, чьё время вышло.
+func (q *statusQueue) advance(now time.Time) {
+	for q.expired(now) {
+		if q.sticky && len(q.pending) == 1 {
+			return
+		}
+		q.shownAt = q.shownAt.Add(q.limit())
+		q.pending = q.pending[1:]
+	}
+	if len(q.pending) == 0 {
+		q.pending = nil
+	}
+}
+
+// line возвращает текст для строки статуса или пустую строку.
+func (q *statusQueue) line() string {
+	q.advance(time.Now())
+	if len(q.pending) == 0 {
+		return ""
+	}
+	return q.pending[0].display()
+}
+
+// severity — важность показанного сообщения.
+func (q *statusQueue) severity() statusSeverity {
+	if len(q.pending) == 0 {
+		return statusInfo
+	}
+	return q.pending[0].severity
+}
+
+// schedule планирует statusTickMsg на момент смены сообщения. Тик,
+// доставленный другому экрану, теряется; тогда следующий вызов после
+// его срока планирует новый.
+func (q *statusQueue) schedule() tea.Cmd {
+	now := time.Now()
+	q.advance(now)
+	if len(q.pending) == 0 || (q.sticky && len(q.pending) == 1) {
+		return nil
+	}
+	at := q.shownAt.Add(q.limit())
+	if q.tickAt.After(now) && !q.tickAt.After(at) {
+		return nil
+	}
+	q.tickAt = at
+	return tea.Tick(at.Sub(now), func(time.Time) tea.Msg { return statusTickMsg{} })
+}
+
+// recent возвращает последние сообщения, новые первыми.
+func (q *statusQueue) recent() []string {
+	lines := make([]string, 0, len(q.history))
+	for i := len(q.history) - 1; i >= 0; i-- {
+		e := q.history[i]
+		marker := " "
+		switch e.severity {
+		case statusError:
+			marker = "✗"
+		case statusWarning:
+			marker = "!"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s  %s", e.at.Format("15:04:05"), marker, e.display()))
+	}
+	return lines
+}
@@ -0,0 +1,102 @@
+package screens
+
+import "surge-tui/internal/ui/styles"
+
+// ThemeChangedMsg рассылается всем созданным экранам при смене темы или
+// подсветки: экраны перестраивают стили без перезапуска.
+type ThemeChangedMsg struct {
+	Theme *styles.Theme
+}
+
+// Цвета экранов. Все экраны показывают одну тему, поэтому цвета общие для
+// пакета: их задаёт палитра темы через SetTheme, до неё действует тёмная.
+var (
+	LoadingColor string
+	ErrorColor   string
+	DimTextColor string
+
+	textColor        string // текст на полосах состояния
+	barColor         string // фон полос состояния
+	barTextColor     string // текст полос и невыбранных вкладок
+	commandBarColor  string // фон строки команд редактора
+	lineNumberColor  string
+	currentLineColor string
+	selectionColor   string // выделение текста в редакторе
+	bracketColor     string // фон скобки у курсора и её пары
+	borderColor      string // фон выбранной строки без фокуса
+	mutedColor       string // недоступные действия
+
+	selectedColor   string
+	onSelectedColor string // текст поверх selectedColor
+	unselectedColor string
+	validColor      string
+	invalidColor    string
+	modifiedColor   string
+
+	scratchTabColor     string
+	bookmarkColor       string
+	changeAddedColor    string
+	changeModifiedColor string
+	changeDeletedColor  string
+
+	diagHeaderColor    string
+	diagErrorColor     string
+	diagWarningColor   string
+	diagInfoColor      string
+	diagSelectedBg     string
+	diagSelectedFg     string
+	diagSecondaryColor string
+
+	fixDiffAddColor  string
+	fixDiffDelColor  string
+	fixDiffMetaColor string
+	fixDiffWarnColor string
+)
+
+func init() {
+	usePalette(styles.DarkPalette)
+}
+
+// usePalette переключает цвета экранов на палитру темы.
+func usePalette(p styles.Palette) {
+	LoadingColor = p.Primary
+	ErrorColor = p.Error
+	DimTextColor = p.TextDim
+
+	textColor = p.Text
+	barColor = p.Surface
+	barTextColor = p.TextSoft
+	commandBarColor = p.SurfaceAlt
+	lineNumberColor = p.TextMuted
+	currentLineColor = p.CurrentLine
+	selectionColor = p.Selection
+	bracketColor = p.Selection
+	borderColor = p.Border
+	mutedColor = p.TextMuted
+
+	selectedColor = p.Primary
+	onSelectedColor = p.OnPrimary
+	unselectedColor = p.TextDim
+	validColor = p.Success
+	invalidColor = p.Error
+	modifiedColor = p.Accent
+
+	scratchTabColor = p.Warning
+	bookmarkColor = p.Accent
+	changeAddedColor = p.Added
+	changeModifiedColor = p.Changed
+	changeDeletedColor = p.Deleted
+
+	diagHeaderColor = p.Heading
+	diagErrorColor = p.ErrorSoft
+	diagWarningColor = p.Warning
+	diagInfoColor = p.Info
+	diagSelectedBg = p.ListSelection
+	diagSelectedFg = p.ListSelectionText
+	diagSecondaryColor = p.TextDim
+
+	fixDiffAddColor = p.Added
+	fixDiffDelColor = p.ErrorSoft
+	fixDiffMetaColor = p.TextDim
+	fixDiffWarnColor = p.Warning
+}
@@ -0,0 +1,138 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Закладки вкладки — строки или блоки строк, к которым можно вернуться из
+// палитры команд. Как и диагностики, они живут в координатах буфера и
+// сдвигаются при вставке и удалении строк.
+
+// editorBookmark — закладка на строках [Line, EndLine] (0-based, включительно).
+type editorBookmark struct {
+	Line    int
+	EndLine int
+}
+
+func (b editorBookmark) contains(line int) bool {
+	return line >= b.Line && line <= b.EndLine
+}
+
+// label — номер строки или диапазон для палитры и статуса (1-based).
+func (b editorBookmark) label() string {
+	if b.EndLine > b.Line {
+		return fmt.Sprintf("%d-%d", b.Line+1, b.EndLine+1)
+	}
+	return fmt.Sprintf("%d", b.Line+1)
+}
+
+// bookmarked сообщает, входит ли строка в какую-нибудь закладку.
+func (t *editorTab) bookmarked(line int) bool {
+	for _, b := range t.bookmarks {
+		if b.contains(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleBookmark ставит закладку на строки [from, to] или снимает её.
+// Для одной строки снимается любая закладка, в которую строка входит;
+// блок снимается, только если совпадает точно. Возвращает true, если
+// закладка поставлена.
+func (t *editorTab) toggleBookmark(from, to int) bool {
+	for i, b := range t.bookmarks {
+		if (b.Line == from && b.EndLine == to) || (from == to && b.contains(from)) {
+			t.bookmarks = slices.Delete(t.bookmarks, i, i+1)
+			return false
+		}
+	}
+	t.bookmarks = append(t.bookmarks, editorBookmark{Line: from, EndLine: to})
+	slices.SortFunc(t.bookmarks, func(a, b editorBookmark) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.EndLine - b.EndLine
+	})
+	return true
+}
+
+// shiftBookmarks сдвигает закладки после вставки count строк перед at.
+// Вставка внутрь блока его растягивает.
+func (t *editorTab) shiftBookmarks(at, count int) {
+	for i := range t.bookmarks {
+		b := &t.bookmarks[i]
+		if b.Line >= at {
+			b.Line += count
+		}
+		if b.EndLine >= at {
+			b.EndLine += count
+		}
+	}
+}
+
+// removeBookmarkLines убирает удалённые строки из закладок: блок сжимается,
+// закладка, от которой ничего не осталось, пропадает.
+func (t *editorTab) removeBookmarkLines(at, count int) {
+	if len(t.bookmarks) == 0 {
+		return
+	}
+	kept := t.bookmarks[:0]
+	for _, b := range t.bookmarks {
+		if b.Line >= at && b.EndLine < at+count {
+			continue
+		}
+		if b.Line >= at+count {
+			b.Line -= count
+		} else if b.Line >= at {
+			b.Line = at
+		}
+		if b.EndLine >= at+count {
+			b.EndLine -= count
+		} else if b.EndLine >= at {
+			b.EndLine = max(at-1, b.Line)
+		}
+		kept = append(kept, b)
+	}
+	t.bookmarks = kept
+}
+
+// bookmarkFrom возвращает первую закладку ниже строки (dir > 0) или выше
+// неё, переходя через конец буфера по кругу.
+func (t *editorTab) bookmarkFrom(line, dir int) (editorBookmark, bool) {
+	if len(t.bookmarks) == 0 {
+		return editorBookmark{}, false
+	}
+	if dir > 0 {
+		for _, b := range t.bookmarks {
+			if b.Line > line {
+				return b, true
+			}
+		}
+		return t.bookmarks[0], true
+	}
+	for i := len(t.bookmarks) - 1; i >= 0; i-- {
+		if t.bookmarks[i].Line < line {
+			return t.bookmarks[i], true
+		}
+	}
+	return t.bookmarks[len(t.bookmarks)-1], true
+}
+
+// clampBookmarks подгоняет закладки под длину буфера после замены содержимого.
+func (t *editorTab) clampBookmarks() {
+	if len(t.bookmarks) == 0 {
+		return
+	}
+	last := len(t.lines) - 1
+	kept := t.bookmarks[:0]
+	for _, b := range t.bookmarks {
+		if b.Line > last {
+			continue
+		}
+		b.EndLine = min(b.EndLine, last)
+		kept = append(kept, b)
+	}
+	t.bookmarks = kept
+}
@@ -0,0 +1,173 @@
+package screens
+
+import "surge-tui/internal/syntax"
+
+// Парные скобки. Скобка под курсором или сразу перед ним подсвечивается
+// вместе с парной, непарная — цветом ошибки. Скобки в строках и комментариях
+// не считаются: в файлах, которые разбирает лексер подсветки, скобкой кода
+// считается только его токен пунктуации, в остальных — любая. Пара ищется не
+// дальше bracketScanLines строк, а результат кэшируется до сдвига курсора или
+// правки, так что перерисовка не сканирует буфер заново.
+
+const bracketScanLines = 5000
+
+var bracketPairs = map[rune]rune{'(': ')', '[': ']', '{': '}', ')': '(', ']': '[', '}': '{'}
+
+// bracketMatch — скобка у курсора и её пара.
+type bracketMatch struct {
+	at      cursorPosition
+	pair    cursorPosition
+	matched bool // false — пары нет
+}
+
+// bracketCache — bracketMatch для состояния буфера key.
+type bracketCache struct {
+	key   bracketCacheKey
+	match bracketMatch
+	found bool
+	valid bool
+}
+
+type bracketCacheKey struct {
+	edits  int
+	cursor cursorPosition
+	name   string // переименование меняет, разбирает ли файл лексер
+}
+
+// matchingBracket возвращает скобку у курсора и её пару; false — курсор не у
+// скобки или пара дальше bracketScanLines строк.
+func (t *editorTab) matchingBracket() (bracketMatch, bool) {
+	key := bracketCacheKey{edits: t.edits, cursor: t.cursor, name: t.name}
+	if !t.brackets.valid || t.brackets.key != key {
+		match, found := t.findMatchingBracket()
+		t.brackets = bracketCache{key: key, match: match, found: found, valid: true}
+	}
+	return t.brackets.match, t.brackets.found
+}
+
+func (t *editorTab) findMatchingBracket() (bracketMatch, bool) {
+	if t.cursor.Line < 0 || t.cursor.Line >= len(t.lines) {
+		return bracketMatch{}, false
+	}
+	var highlight []syntax.Line
+	if syntax.SupportsFile(t.name) {
+		highlight = t.highlightLines()
+	}
+	for _, col := range []int{t.cursor.Col, t.cursor.Col - 1} {
+		at := cursorPosition{Line: t.cursor.Line, Col: col}
+		bracket, ok := t.codeBracketAt(at, highlight)
+		if !ok {
+			continue
+		}
+		pair, matched, ok := t.scanBracketPair(at, bracket, highlight)
+		if !ok {
+			return bracketMatch{}, false
+		}
+		return bracketMatch{at: at, pair: pair, matched: matched}, true
+	}
+	return bracketMatch{}, false
+}
+
+// codeBracketAt возвращает скобку кода в позиции at.
+func (t *editorTab) codeBracketAt(at cursorPosition, highlight []syntax.Line) (rune, bool) {
+	runes := []rune(t.lines[at.Line])
+	if at.Col < 0 || at.Col >= len(runes) {
+		return 0, false
+	}
+	r := runes[at.Col]
+	if _, ok := bracketPairs[r]; !ok {
+		return 0, false
+	}
+	if at.Line >= len(highlight) {
+		return r, true
+	}
+	for _, tok := range highlight[at.Line].Tokens {
+		if at.Col >= tok.Start && at.Col < tok.End {
+			return r, tok.Kind == syntax.TokenPunctuation
+		}
+	}
+	return r, true
+}
+
+// scanBracketPair ищет пару скобки bracket в позиции at с учётом вложенности:
+// вперёд для открывающей, назад для закрывающей. matched false — пары нет до
+// края буфера; ok false — поиск упёрся в bracketScanLines.
+func (t *editorTab) scanBracketPair(at cursorPosition, bracket rune, highlight []syntax.Line) (pair cursorPosition, matched, ok bool) {
+	want := bracketPairs[bracket]
+	step := 1
+	if bracket == ')' || bracket == ']' || bracket == '}' {
+		step = -1
+	}
+	depth := 0
+	for line, scanned := at.Line, 0; line >= 0 && line < len(t.lines); line, scanned = line+step, scanned+1 {
+		if scanned > bracketScanLines {
+			return cursorPosition{}, false, false
+		}
+		from := -1
+		if line == at.Line {
+			from = at.Col
+		}
+		found := t.eachCodeBracket(line, from, step, highlight, func(col int, r rune) bool {
+			switch r {
+			case bracket:
+				depth++
+			case want:
+				depth--
+				if depth == 0 {
+					pair = cursorPosition{Line: line, Col: col}
+					return false
+				}
+			}
+			return true
+		})
+		if found {
+			return pair, true, true
+		}
+	}
+	return cursorPosition{}, false, true
+}
+
+// eachCodeBracket обходит скобки кода строки line в направлении step, начиная
+// с колонки from (-1 — с края строки); visit возвращает false, чтобы
+// остановиться, и тогда eachCodeBracket возвращает true.
+func (t *editorTab) eachCodeBracket(line, from, step int, highlight []syntax.Line, visit func(col int, r rune) bool) bool {
+	runes := []rune(t.lines[line])
+	if from < 0 {
+		from = 0
+		if step < 0 {
+			from = len(runes) - 1
+		}
+	}
+	inRange := func(col int) bool {
+		return (step > 0 && col >= from) || (step < 0 && col <= from)
+	}
+	each := func(start, end int) bool {
+		if step < 0 {
+			start, end = end-1, start-1
+		}
+		for col := start; col != end; col += step {
+			if _, ok := bracketPairs[runes[col]]; ok && inRange(col) && !visit(col, runes[col]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if line >= len(highlight) {
+		return each(0, len(runes))
+	}
+	tokens := highlight[line].Tokens
+	for i := range tokens {
+		if step < 0 {
+			i = len(tokens) - 1 - i
+		}
+		tok := tokens[i]
+		if tok.Kind != syntax.TokenPunctuation {
+			continue
+		}
+		if each(min(tok.Start, len(runes)), min(tok.End, len(runes))) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+package screens
+
+// Метки несохранённых изменений в гуттере: буфер сравнивается с последним
+// сохранением (savedLines) построчно. Правки не пересчитывают diff сразу —
+// они сдвигают готовые ханки и расширяют окно изменённых строк; после паузы
+// в наборе diff строится заново только для этого окна вместе с задетыми
+// ханками, а строки вне окна считаются совпадающими с сохранённой версией.
+
+type lineChange int
+
+const (
+	lineUnchanged lineChange = iota
+	lineAdded
+	lineModified
+	lineDeletedBelow // под строкой удалены строки сохранённой версии
+	lineDeletedAbove // удалены строки в начале файла
+)
+
+// changeHunk — участок буфера [start, start+count), заменивший строки
+// сохранённой версии [savedStart, savedStart+savedCount). count == 0 —
+// удаление перед строкой start.
+type changeHunk struct {
+	start      int
+	count      int
+	savedStart int
+	savedCount int
+}
+
+// markerLine — строка, на которой рисуется метка ханка.
+func (h changeHunk) markerLine() int {
+	if h.count == 0 && h.start > 0 {
+		return h.start - 1
+	}
+	return h.start
+}
+
+// covers сообщает, относится ли строка буфера к ханку.
+func (h changeHunk) covers(line int) bool {
+	if h.count == 0 {
+		return line == h.markerLine()
+	}
+	return line >= h.start && line < h.start+h.count
+}
+
+// touchChanges расширяет окно пересчёта на строки [from, to).
+func (t *editorTab) touchChanges(from, to int) {
+	if !t.changesStale {
+		t.changesStale = true
+		t.changesFrom, t.changesTo = from, to
+		return
+	}
+	t.changesFrom = min(t.changesFrom, from)
+	t.changesTo = max(t.changesTo, to)
+}
+
+// invalidateChanges требует пересчитать весь буфер (замена содержимого, undo).
+func (t *editorTab) invalidateChanges() {
+	t.changes = nil
+	t.changesStale = true
+	t.changesFrom, t.changesTo = 0, len(t.lines)
+}
+
+// clearChanges убирает метки: буфер совпадает с сохранённым.
+func (t *editorTab) clearChanges() {
+	t.changes = nil
+	t.changesStale = false
+}
+
+// shiftChangesInserted сдвигает ханки и окно после вставки count строк перед at.
+func (t *editorTab) shiftChangesInserted(at, count int) {
+	for i := range t.changes {
+		h := &t.changes[i]
+		switch {
+		case h.start >= at:
+			h.start += count
+		case h.start+h.count > at:
+			h.count += count
+		}
+	}
+	if t.changesStale {
+		if t.changesFrom >= at {
+			t.changesFrom += count
+		}
+		if t.changesTo > at {
+			t.changesTo += count
+		}
+	}
+	t.touchChanges(at, at+count)
+}
+
+// shiftChangesRemoved сдвигает ханки и окно после удаления строк [at, at+count).
+func (t *editorTab) shiftChangesRemoved(at, count int) {
+	shift := func(line int) int {
+		switch {
+		case line >= at+count:
+			return line - count
+		case line > at:
+			return at
+		}
+		return line
+	}
+	for i := range t.changes {
+		h := &t.changes[i]
+		end := h.start + h.count
+		h.start = shift(h.start)
+		h.count = max(shift(end)-h.start, 0)
+	}
+	if t.changesStale {
+		t.changesFrom = shift(t.changesFrom)
+		t.changesTo = shift(t.changesTo)
+	}
+	t.touchChanges(at, at+1)
+}
+
+// refreshChanges пересчитывает ханки в окне изменённых строк.
+func (t *editorTab) refreshChanges() {
+	if !t.changesStale {
+		return
+	}
+	t.changesStale = false
+	if t.savedLines == nil || t.scratch {
+		t.changes = nil
+		return
+	}
+
+	from := clampInt(t.changesFrom, 0, len(t.lines))
+	to := clampInt(t.changesTo, from, len(t.lines))
+	// окно захватывает задетые и соседние ханки целиком
+	for grown := true; grown; {
+		grown = false
+		for _, h := range t.changes {
+			if h.start <= to && h.start+h.count >= from && (h.start < from || h.start+h.count > to) {
+				from, to = min(from, h.start), max(to, h.start+h.count)
+				grown = true
+			}
+		}
+	}
+
+	var before, after []changeHunk
+	delta := 0
+	for _, h := range t.changes {
+		switch {
+		case h.start+h.count < from:
+			before = append(before, h)
+			delta += h.count - h.savedCount
+		case h.start > to:
+			after = append(after, h)
+		}
+	}
+	savedFrom := from - delta
+	savedTo := len(t.savedLines) - (len(t.lines) - to)
+	if len(after) > 0 {
+		savedTo = after[0].savedStart - (after[0].start - to)
+	}
+	if savedFrom < 0 || savedTo < savedFrom || savedTo > len(t.savedLines) {
+		// сдвиги разошлись с буфером — сравниваем файл целиком
+		before, after = nil, nil
+		from, to, savedFrom, savedTo = 0, len(t.lines), 0, len(t.savedLines)
+	}
+
+	window := diffLines(t.savedLines[savedFrom:savedTo], t.lines[from:to])
+	changes := append(before, make([]changeHunk, 0, len(window)+len(after))...)
+	for _, h := range window {
+		h.start += from
+		h.savedStart += savedFrom
+		changes = append(changes, h)
+	}
+	t.changes = append(changes, after...)
+}
+
+// lineChangeAt возвращает метку гуттера для строки буфера.
+func (t *editorTab) lineChangeAt(line int) lineChange {
+	mark := lineUnchanged
+	for _, h := range t.changes {
+		switch {
+		case h.count == 0 && h.markerLine() == line:
+			if h.start == 0 {
+				mark = lineDeletedAbove
+			} else {
+				mark = lineDeletedBelow
+			}
+		case line >= h.start && line < h.start+h.count:
+			if h.savedCount == 0 {
+				return lineAdded
+			}
+			return lineModified
+		case h.start > line+1:
+			return mark
+		}
+	}
+	return mark
+}
+
+// adjacentChange возвращает ханк выше (dir < 0) или ниже курсора.
+func (t *editorTab) adjacentChange(line, dir int) (changeHunk, bool) {
+	if dir > 0 {
+		for _, h := range t.changes {
+			if h.markerLine() > line && !h.covers(line) {
+				return h, true
+			}
+		}
+		return changeHunk{}, false
+	}
+	for i := len(t.changes) - 1; i >= 0; i-- {
+		if h := t.changes[i]; h.markerLine() < line && !h.covers(line) {
+			return h, true
+		}
+	}
+	return changeHunk{}, false
+}
+
+// changeAt возвращает ханк под строкой курсора.
+func (t *editorTab) changeAt(line int) (changeHunk, bool) {
+	for _, h := range t.changes {
+		if h.covers(line) {
+			return h, true
+		}
+	}
+	return changeHunk{}, false
+}
+
+// revertHunk возвращает строки ханка к сохранённой версии.
+func (t *editorTab) revertHunk(h changeHunk) {
+	saved := t.savedLines[h.savedStart : h.savedStart+h.savedCount]
+	lines := make([]string, 0, len(t.lines)-h.count+len(saved))
+	lines = append(lines, t.lines[:h.start]...)
+	lines = append(lines, saved...)
+	t.lines = append(lines, t.lines[h.start+h.count:]...)
+	if len(t.lines) == 0 {
+		t.lines = []string{""}
+	}
+
+	common := min(h.count, len(saved))
+	for i := 0; i < common; i++ {
+		t.markLineChanged(h.start + i)
+	}
+	if len(saved) > h.count {
+		t.markLinesInserted(h.start+common, len(saved)-h.count)
+	} else if h.count > len(saved) {
+		t.markLinesRemoved(h.start+common, h.count-len(saved))
+	}
+	t.refreshChanges()
+	if len(t.changes) == 0 {
+		t.dirty = t.diskChanged()
+	}
+
+	t.cursor = cursorPosition{Line: h.start}
+	t.clampCursor()
+}
@@ -0,0 +1,81 @@
+package screens
+
+import (
+	"slices"
+	"time"
+)
+
+// Контрольные точки для отката по времени («Rollback Buffer to…»). История
+// undo ограничена maxUndoDepth правками и сбрасывается при перечитывании
+// файла, поэтому рядом хранится второе кольцо: снимки буфера не чаще раза в
+// checkpointInterval — перед правками и по таймеру, пока буфер изменён.
+// Когда кольцо заполнено, выбрасывается точка, соседи которой ближе всего
+// друг к другу, так что недавнее остаётся подробным, а давнее — редким.
+
+const (
+	checkpointInterval = 30 * time.Second
+	maxCheckpoints     = 32
+)
+
+// bufferCheckpoint — содержимое буфера на момент at.
+type bufferCheckpoint struct {
+	at    time.Time
+	lines []string
+}
+
+// keepCheckpoint запоминает lines на момент at, если последняя точка старше
+// checkpointInterval или force. lines копируются: правки меняют строки буфера
+// на месте.
+func (t *editorTab) keepCheckpoint(at time.Time, lines []string, force bool) bool {
+	if n := len(t.checkpoints); n > 0 {
+		last := t.checkpoints[n-1]
+		if !force && at.Sub(last.at) < checkpointInterval {
+			return false
+		}
+		if slices.Equal(last.lines, lines) {
+			return false
+		}
+	}
+	t.checkpoints = append(t.checkpoints, bufferCheckpoint{at: at, lines: slices.Clone(lines)})
+	t.thinCheckpoints()
+	return true
+}
+
+// captureCheckpoint снимает точку с текущего буфера по таймеру; false —
+// интервал с прошлой точки ещё не прошёл.
+func (t *editorTab) captureCheckpoint(now time.Time) bool {
+	if n := len(t.checkpoints); n > 0 && now.Sub(t.checkpoints[n-1].at) < checkpointInterval {
+		return false
+	}
+	t.keepCheckpoint(now, t.lines, true)
+	t.checkpointEdits = t.edits
+	return true
+}
+
+// thinCheckpoints удаляет лишние точки, сохраняя самую старую и самую новую.
+// Промежуток, который останется без точки, меряется относительно её
+// давности: так интервалы между точками растут примерно геометрически.
+func (t *editorTab) thinCheckpoints() {
+	for len(t.checkpoints) > maxCheckpoints {
+		newest := t.checkpoints[len(t.checkpoints)-1].at
+		drop, best := 1, -1.0
+		for i := 1; i < len(t.checkpoints)-1; i++ {
+			gap := t.checkpoints[i+1].at.Sub(t.checkpoints[i-1].at)
+			age := newest.Sub(t.checkpoints[i].at) + checkpointInterval
+			if score := float64(gap) / float64(age); best < 0 || score < best {
+				drop, best = i, score
+			}
+		}
+		t.checkpoints = slices.Delete(t.checkpoints, drop, drop+1)
+	}
+}
+
+// checkpointAt возвращает последнюю точку не позже at.
+func (t *editorTab) checkpointAt(at time.Time) (bufferCheckpoint, bool) {
+	for i := len(t.checkpoints) - 1; i >= 0; i-- {
+		if !t.checkpoints[i].at.After(at) {
+			return t.checkpoints[i], true
+		}
+	}
+	return bufferCheckpoint{}, false
+}
@@ -0,0 +1,66 @@
+package screens
+
+import "strings"
+
+// lineCommentPrefix — маркер строчного комментария Surge.
+const lineCommentPrefix = "//"
+
+// toggleLineComments комментирует строки [from, to] маркером "// " в колонке
+// наименьшего отступа среди них или, если все они уже закомментированы,
+// снимает маркеры. Пустые строки пропускаются. Возвращает число изменённых
+// строк и true, если строки закомментированы.
+func (t *editorTab) toggleLineComments(from, to int) (int, bool) {
+	indent := -1
+	uncomment := true
+	for i := from; i <= to; i++ {
+		line := t.lines[i]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		ws := len(lineIndent(line))
+		if indent < 0 || ws < indent {
+			indent = ws
+		}
+		if !strings.HasPrefix(line[ws:], lineCommentPrefix) {
+			uncomment = false
+		}
+	}
+	if indent < 0 {
+		return 0, false
+	}
+
+	count := 0
+	for i := from; i <= to; i++ {
+		line := t.lines[i]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		col, delta := indent, len(lineCommentPrefix)+1
+		if uncomment {
+			col = len(lineIndent(line))
+			rest := line[col+len(lineCommentPrefix):]
+			delta = len(lineCommentPrefix)
+			if strings.HasPrefix(rest, " ") {
+				delta++
+			}
+			t.lines[i] = line[:col] + line[col+delta:]
+			delta = -delta
+		} else {
+			t.lines[i] = line[:col] + lineCommentPrefix + " " + line[col:]
+		}
+		t.cursor = shiftColumn(t.cursor, i, col, delta)
+		t.anchor = shiftColumn(t.anchor, i, col, delta)
+		t.markLineChanged(i)
+		count++
+	}
+	return count, !uncomment
+}
+
+// shiftColumn сдвигает позицию pos на строке line, стоящую не левее col,
+// на delta колонок (но не левее col).
+func shiftColumn(pos cursorPosition, line, col, delta int) cursorPosition {
+	if pos.Line == line && pos.Col >= col {
+		pos.Col = max(pos.Col+delta, col)
+	}
+	return pos
+}
@@ -0,0 +1,58 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"surge-tui/internal/config"
+)
+
+// Конфликт сохранения: файл изменили на диске после последнего сохранения
+// или загрузки, а в буфере есть свои правки. Ни одна из версий не должна
+// пропасть молча — буфер перед заменой уходит в копию с меткой времени.
+
+const (
+	conflictBackupLayout = "20060102-150405"
+	conflictDiffContext  = 3
+)
+
+// backupBuffer сохраняет содержимое буфера в каталог резервных копий
+// (config.BackupDir), а не в проект, и возвращает путь копии.
+func (t *editorTab) backupBuffer(now time.Time) (string, error) {
+	return config.WriteBackup(t.path, []byte(t.text()), now)
+}
+
+// writeConflictDiff сохраняет unified diff буфера и файла на диске во
+// временный каталог и возвращает путь к нему.
+func (t *editorTab) writeConflictDiff(now time.Time) (string, error) {
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return "", err
+	}
+	theirs, _ := decodeBuffer(data)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s (unsaved buffer)\n", t.name)
+	fmt.Fprintf(&b, "+++ %s (on disk)\n", t.name)
+	hunks := unifiedDiff(t.lines, theirs, conflictDiffContext)
+	if len(hunks) == 0 {
+		b.WriteString("(buffer and file on disk are identical)\n")
+	}
+	for _, line := range hunks {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	dir := filepath.Join(os.TempDir(), "surge-tui")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.diff", t.name, now.Format(conflictBackupLayout)))
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,59 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"surge-tui/internal/config"
+)
+
+// Копия буфера при перечитывании файла уходит в каталог копий, а не в
+// проект, и совпадает с тем, что записал бы save.
+func TestBackupBufferWritesOutsideProject(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	tab := newTestTab(t, "main.sg", "on disk")
+	tab.lines = []string{"mine", "", "ünïcode"}
+	now := time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC)
+
+	first, err := tab.backupBuffer(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir := filepath.Dir(first); dir != config.BackupDir() {
+		t.Errorf("backup in %s, want %s", dir, config.BackupDir())
+	}
+	if !strings.HasSuffix(first, "main.sg.mine-20261016-123000") {
+		t.Errorf("backup name = %s", filepath.Base(first))
+	}
+	entries, err := os.ReadDir(filepath.Dir(tab.path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("project dir has extra files: %v", entries)
+	}
+
+	data, err := os.ReadFile(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != tab.text() {
+		t.Errorf("backup = %q, want %q", data, tab.text())
+	}
+
+	// вторая копия в ту же секунду не затирает первую
+	tab.lines = []string{"later"}
+	second, err := tab.backupBuffer(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second == first {
+		t.Fatalf("second backup reused %s", first)
+	}
+	if data, _ := os.ReadFile(first); string(data) != "mine\n\nünïcode" {
+		t.Errorf("first backup overwritten: %q", data)
+	}
+}
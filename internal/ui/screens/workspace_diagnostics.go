@@ -0,0 +1,100 @@
+package screens
+
+// Диагностики вкладки живут в координатах буфера: при вставке и удалении
+// строк они сдвигаются, а отредактированная строка теряет свои метки до
+// следующего запуска surge diag. Серьёзность метки гуттера кэшируется по
+// строкам: новый набор сбрасывает только строки, чьи метки изменились, а
+// сдвиг строк правкой — весь кэш.
+
+// setDiagnostics заменяет набор диагностик вкладки и возвращает строки,
+// чьи метки изменились: по ним кэши отрисовки могут сбросить только
+// затронутые строки. Пустой результат означает, что набор не изменился.
+func (t *editorTab) setDiagnostics(diags []EditorDiagnostic) []int {
+	changed := changedDiagnosticLines(t.diagnostics, diags)
+	if len(changed) == 0 {
+		return nil
+	}
+	t.diagnostics = append([]EditorDiagnostic(nil), diags...)
+	for _, line := range changed {
+		delete(t.diagMarks, line)
+	}
+	return changed
+}
+
+// diagnosticMark возвращает ранг самой серьёзной диагностики строки line
+// (см. severityRank; -1 — диагностик нет) и запоминает его до смены набора.
+func (t *editorTab) diagnosticMark(line int) int {
+	if rank, ok := t.diagMarks[line]; ok {
+		return rank
+	}
+	rank := -1
+	if diag, ok := diagnosticsOnLine(t.diagnostics, line); ok {
+		rank = severityRank(diag.Severity)
+	}
+	if t.diagMarks == nil {
+		t.diagMarks = make(map[int]int)
+	}
+	t.diagMarks[line] = rank
+	return rank
+}
+
+// clearDiagnosticMarks сбрасывает кэш меток после сдвига или удаления диагностик.
+func (t *editorTab) clearDiagnosticMarks() {
+	t.diagMarks = nil
+}
+
+// dropDiagnosticsOnLine убирает диагностики, начинающиеся на изменённой строке.
+func (t *editorTab) dropDiagnosticsOnLine(line int) {
+	if len(t.diagnostics) == 0 {
+		return
+	}
+	kept := t.diagnostics[:0]
+	for _, d := range t.diagnostics {
+		if d.Line != line {
+			kept = append(kept, d)
+			continue
+		}
+		for l := d.Line; l <= d.EndLine; l++ {
+			delete(t.diagMarks, l)
+		}
+	}
+	t.diagnostics = kept
+}
+
+// shiftDiagnostics сдвигает диагностики после вставки count строк перед at.
+func (t *editorTab) shiftDiagnostics(at, count int) {
+	t.clearDiagnosticMarks()
+	for i := range t.diagnostics {
+		d := &t.diagnostics[i]
+		if d.Line >= at {
+			d.Line += count
+		}
+		if d.EndLine >= at {
+			d.EndLine += count
+		}
+	}
+}
+
+// removeDiagnosticLines удаляет диагностики удалённых строк и сдвигает остальные.
+func (t *editorTab) removeDiagnosticLines(at, count int) {
+	if len(t.diagnostics) == 0 {
+		return
+	}
+	t.clearDiagnosticMarks()
+	kept := t.diagnostics[:0]
+	for _, d := range t.diagnostics {
+		if d.Line >= at && d.Line < at+count {
+			continue
+		}
+		if d.Line >= at+count {
+			d.Line -= count
+		}
+		if d.EndLine >= at+count {
+			d.EndLine -= count
+		} else if d.EndLine >= at {
+			d.EndLine = max(at-1, d.Line)
+		}
+		kept = append(kept, d)
+	}
+	t.diagnostics = kept
+}
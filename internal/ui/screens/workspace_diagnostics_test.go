@@ -0,0 +1,162 @@
+package screens
+
+import (
+	"reflect"
+	"testing"
+)
+
+func diagAt(line int, severity, message string) EditorDiagnostic {
+	return EditorDiagnostic{Line: line, EndLine: line, Severity: severity, Message: message}
+}
+
+func TestChangedDiagnosticLines(t *testing.T) {
+	base := []EditorDiagnostic{
+		diagAt(2, "error", "undefined x"),
+		diagAt(5, "warning", "unused y"),
+	}
+	tests := []struct {
+		name string
+		next []EditorDiagnostic
+		want []int
+	}{
+		{"без изменений", base, nil},
+		{"добавлена на новой строке", append(append([]EditorDiagnostic(nil), base...), diagAt(7, "info", "hint")), []int{7}},
+		{"добавлена на строке с меткой", append(append([]EditorDiagnostic(nil), base...), diagAt(5, "info", "hint")), []int{5}},
+		{"удалена", base[:1], []int{5}},
+		{"удалены все", nil, []int{2, 5}},
+		{"изменилась серьёзность", []EditorDiagnostic{diagAt(2, "warning", "undefined x"), base[1]}, []int{2}},
+		{"изменилось сообщение", []EditorDiagnostic{base[0], diagAt(5, "warning", "unused z")}, []int{5}},
+		{"перенесена на другую строку", []EditorDiagnostic{diagAt(3, "error", "undefined x"), base[1]}, []int{2, 3}},
+		{"многострочная", append(append([]EditorDiagnostic(nil), base...), EditorDiagnostic{Line: 8, EndLine: 10, Severity: "error"}), []int{8, 9, 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := changedDiagnosticLines(base, tt.next); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changedDiagnosticLines = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Порядок строк не зависит от обхода карт: кэши сбрасывают их по порядку.
+func TestChangedDiagnosticLinesSorted(t *testing.T) {
+	var old, next []EditorDiagnostic
+	for line := 0; line < 50; line++ {
+		old = append(old, diagAt(line, "warning", "old"))
+		next = append(next, diagAt(line*2, "error", "new"))
+	}
+	first := changedDiagnosticLines(old, next)
+	for i := 1; i < len(first); i++ {
+		if first[i-1] >= first[i] {
+			t.Fatalf("lines not strictly ascending at %d: %v", i, first)
+		}
+	}
+	for range 20 {
+		if got := changedDiagnosticLines(old, next); !reflect.DeepEqual(got, first) {
+			t.Fatalf("unstable order: %v, then %v", first, got)
+		}
+	}
+}
+
+func TestSameEditorDiagnostics(t *testing.T) {
+	a := []EditorDiagnostic{diagAt(1, "error", "x"), diagAt(4, "warning", "y")}
+	tests := []struct {
+		name string
+		b    []EditorDiagnostic
+		want bool
+	}{
+		{"те же", []EditorDiagnostic{diagAt(1, "error", "x"), diagAt(4, "warning", "y")}, true},
+		{"короче", a[:1], false},
+		{"другая колонка", []EditorDiagnostic{{Line: 1, EndLine: 1, Col: 3, Severity: "error", Message: "x"}, a[1]}, false},
+		{"другой код", []EditorDiagnostic{{Line: 1, EndLine: 1, Severity: "error", Message: "x", Code: "E1"}, a[1]}, false},
+		{"пустой", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameEditorDiagnostics(a, tt.b); got != tt.want {
+				t.Errorf("SameEditorDiagnostics = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !SameEditorDiagnostics(nil, nil) {
+		t.Error("empty sets differ")
+	}
+}
+
+// Новый набор сбрасывает в кэше меток только строки, чьи метки изменились.
+func TestSetDiagnosticsInvalidatesChangedLines(t *testing.T) {
+	tab := newTestTab(t, "main.sg", "a", "b", "c", "d", "e", "f")
+	tab.setDiagnostics([]EditorDiagnostic{diagAt(1, "error", "x"), diagAt(3, "warning", "y")})
+	for line := range tab.lines {
+		tab.diagnosticMark(line)
+	}
+
+	changed := tab.setDiagnostics([]EditorDiagnostic{diagAt(1, "error", "x"), diagAt(4, "info", "z")})
+	if want := []int{3, 4}; !reflect.DeepEqual(changed, want) {
+		t.Fatalf("changed = %v, want %v", changed, want)
+	}
+	for _, line := range []int{0, 1, 2, 5} {
+		if _, ok := tab.diagMarks[line]; !ok {
+			t.Errorf("mark of unchanged line %d was dropped", line)
+		}
+	}
+	for _, line := range changed {
+		if _, ok := tab.diagMarks[line]; ok {
+			t.Errorf("mark of changed line %d was kept", line)
+		}
+	}
+	for line, want := range []int{-1, 0, -1, -1, 2, -1} {
+		if got := tab.diagnosticMark(line); got != want {
+			t.Errorf("diagnosticMark(%d) = %d, want %d", line, got, want)
+		}
+	}
+
+	if changed := tab.setDiagnostics(append([]EditorDiagnostic(nil), tab.diagnostics...)); changed != nil {
+		t.Errorf("identical set reported changes %v", changed)
+	}
+}
+
+// Правка сдвигает метки вместе с диагностиками.
+func TestDiagnosticMarksFollowEdits(t *testing.T) {
+	tab := newTestTab(t, "main.sg", "a", "b", "c")
+	tab.setDiagnostics([]EditorDiagnostic{diagAt(1, "error", "x")})
+	if got := tab.diagnosticMark(1); got != 0 {
+		t.Fatalf("diagnosticMark(1) = %d, want 0", got)
+	}
+
+	tab.lines = append([]string{"new"}, tab.lines...)
+	tab.markLinesInserted(0, 1)
+	if got := tab.diagnosticMark(1); got != -1 {
+		t.Errorf("after insert diagnosticMark(1) = %d, want -1", got)
+	}
+	if got := tab.diagnosticMark(2); got != 0 {
+		t.Errorf("after insert diagnosticMark(2) = %d, want 0", got)
+	}
+
+	tab.lines[2] = "changed"
+	tab.markLineChanged(2)
+	if got := tab.diagnosticMark(2); got != -1 {
+		t.Errorf("after edit diagnosticMark(2) = %d, want -1", got)
+	}
+}
+
+// Вкладки с несохранёнными правками не получают новый набор.
+func TestProjectSetDiagnosticsSkipsDirtyTabs(t *testing.T) {
+	clean := newTestTab(t, "clean.sg", "a", "b")
+	dirty := newTestTab(t, "dirty.sg", "a", "b")
+	dirty.dirty = true
+	ps := newTestProject(t, clean)
+	ps.tabs = append(ps.tabs, dirty)
+
+	diags := map[string][]EditorDiagnostic{
+		clean.path: {diagAt(1, "error", "x")},
+		dirty.path: {diagAt(0, "error", "y")},
+	}
+	ps.SetDiagnostics(diags)
+	if got := clean.diagnosticMark(1); got != 0 {
+		t.Errorf("clean tab mark = %d, want 0", got)
+	}
+	if len(dirty.diagnostics) != 0 {
+		t.Errorf("dirty tab got diagnostics %v", dirty.diagnostics)
+	}
+}
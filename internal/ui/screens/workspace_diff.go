@@ -0,0 +1,205 @@
+package screens
+
+import "fmt"
+
+// Построчный diff для меток гуттера, отката и конфликта сохранения. Общие
+// начало и конец отрезаются, середина выравнивается алгоритмом Майерса;
+// если правок больше maxChangeEdits, середина считается заменённой целиком.
+
+// maxChangeEdits ограничивает число правок, которое ищет diff; при большем
+// расхождении вся середина становится одним изменённым ханком.
+const maxChangeEdits = 1000
+
+// unifiedDiff строит строки unified diff от a к b с context строками контекста.
+func unifiedDiff(a, b []string, context int) []string {
+	ops := diffOps(a, b)
+
+	var out []string
+	for start := 0; start < len(ops); {
+		// ищем следующее изменение
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		from := max(start-context, 0)
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end = min(end+context, len(ops))
+				break
+			}
+			end = run
+		}
+
+		aStart, bStart := ops[from].aLine, ops[from].bLine
+		aCount, bCount := 0, 0
+		body := make([]string, 0, end-from)
+		for _, op := range ops[from:end] {
+			switch op.kind {
+			case ' ':
+				aCount++
+				bCount++
+			case '-':
+				aCount++
+			case '+':
+				bCount++
+			}
+			body = append(body, string(op.kind)+op.text)
+		}
+		out = append(out, fmt.Sprintf("@@ -%d,%d +%d,%d @@", aStart+1, aCount, bStart+1, bCount))
+		out = append(out, body...)
+		start = end
+	}
+	return out
+}
+
+type diffOp struct {
+	kind  byte // ' ', '-' или '+'
+	text  string
+	aLine int // номер строки в a (с нуля), с которой начинается операция
+	bLine int
+}
+
+// diffOps разворачивает ханки diffLines в построчные операции.
+func diffOps(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	ai, bi := 0, 0
+	emit := func(kind byte, text string) {
+		ops = append(ops, diffOp{kind: kind, text: text, aLine: ai, bLine: bi})
+		switch kind {
+		case ' ':
+			ai++
+			bi++
+		case '-':
+			ai++
+		case '+':
+			bi++
+		}
+	}
+	for _, h := range diffLines(a, b) {
+		for ai < h.savedStart {
+			emit(' ', a[ai])
+		}
+		for _, line := range a[h.savedStart : h.savedStart+h.savedCount] {
+			emit('-', line)
+		}
+		for _, line := range b[h.start : h.start+h.count] {
+			emit('+', line)
+		}
+	}
+	for ai < len(a) {
+		emit(' ', a[ai])
+	}
+	return ops
+}
+
+// diffLines сравнивает строки a (старые, для ханка — сохранённые) и b (новые)
+// и возвращает ханки в координатах срезов.
+func diffLines(a, b []string) []changeHunk {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	hunks, ok := myersHunks(a, b, maxChangeEdits)
+	if !ok {
+		hunks = []changeHunk{{count: len(b), savedCount: len(a)}}
+	}
+	for i := range hunks {
+		hunks[i].start += prefix
+		hunks[i].savedStart += prefix
+	}
+	return hunks
+}
+
+// myersHunks — алгоритм Майерса с ограничением limit на число правок.
+// Для обратного прохода хранится только диапазон диагоналей каждого шага.
+func myersHunks(a, b []string, limit int) ([]changeHunk, bool) {
+	n, m := len(a), len(b)
+	maxD := min(n+m, limit)
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackHunks(trace, n, m, d), true
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+	return nil, false
+}
+
+// backtrackHunks восстанавливает путь от (n, m) к началу и склеивает
+// соседние вставки и удаления в ханки.
+func backtrackHunks(trace [][]int, n, m, d int) []changeHunk {
+	var hunks []changeHunk
+	x, y := n, m
+	for ; d > 0; d-- {
+		prev := trace[d-1]
+		at := func(k int) int { return prev[k+d-1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		inserted := prevK == k+1
+
+		// правка сдвигает точку на одну строку, дальше до (x, y) идёт диагональ
+		endX, endY := prevX+1, prevY
+		if inserted {
+			endX, endY = prevX, prevY+1
+		}
+		last := len(hunks) - 1
+		if last >= 0 && endX == x && endY == y && hunks[last].savedStart == x && hunks[last].start == y {
+			hunks[last].savedStart, hunks[last].start = prevX, prevY
+		} else {
+			hunks = append(hunks, changeHunk{start: prevY, savedStart: prevX})
+			last = len(hunks) - 1
+		}
+		if inserted {
+			hunks[last].count++
+		} else {
+			hunks[last].savedCount++
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(hunks)-1; i < j; i, j = i+1, j-1 {
+		hunks[i], hunks[j] = hunks[j], hunks[i]
+	}
+	return hunks
+}
@@ -0,0 +1,65 @@
+package screens
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want []changeHunk
+	}{
+		{"одинаковые", []string{"a", "b"}, []string{"a", "b"}, nil},
+		{"вставка", []string{"a", "c"}, []string{"a", "b", "c"}, []changeHunk{{start: 1, count: 1, savedStart: 1}}},
+		{"удаление", []string{"a", "b", "c"}, []string{"a", "c"}, []changeHunk{{start: 1, savedStart: 1, savedCount: 1}}},
+		{"замена", []string{"a", "b", "c"}, []string{"a", "B", "c"}, []changeHunk{{start: 1, count: 1, savedStart: 1, savedCount: 1}}},
+		{"два ханка", []string{"a", "b", "c", "d", "e"}, []string{"x", "b", "c", "d"}, []changeHunk{
+			{start: 0, count: 1, savedStart: 0, savedCount: 1},
+			{start: 4, savedStart: 4, savedCount: 1},
+		}},
+		{"из пустого", nil, []string{"a"}, []changeHunk{{count: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffLines = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// При расхождении больше maxChangeEdits середина становится одним ханком.
+func TestDiffLinesTooManyEdits(t *testing.T) {
+	a := make([]string, maxChangeEdits+1)
+	b := make([]string, maxChangeEdits+1)
+	for i := range a {
+		a[i], b[i] = fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)
+	}
+	a = append([]string{"head"}, append(a, "tail")...)
+	b = append([]string{"head"}, append(b, "tail")...)
+	want := []changeHunk{{start: 1, count: maxChangeEdits + 1, savedStart: 1, savedCount: maxChangeEdits + 1}}
+	if got := diffLines(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffLines = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}
+	b := []string{"1", "two", "3", "4", "5", "6", "7", "8", "9", "10"}
+	want := []string{
+		"@@ -1,3 +1,3 @@", " 1", "-2", "+two", " 3",
+		"@@ -9,1 +9,2 @@", " 9", "+10",
+	}
+	if got := unifiedDiff(a, b, 1); !reflect.DeepEqual(got, want) {
+		t.Errorf("unifiedDiff =\n%q\nwant\n%q", got, want)
+	}
+	if got := unifiedDiff(a, a, 3); got != nil {
+		t.Errorf("unifiedDiff of equal input = %q", got)
+	}
+	if got := diffSummary(a, b); got != "+2 −1" {
+		t.Errorf("diffSummary = %q", got)
+	}
+}
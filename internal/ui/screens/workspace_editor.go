@@ -4,7 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode/utf8"
+
+	"surge-tui/internal/logging"
+	"surge-tui/internal/syntax"
 )
 
 type editorMode int
@@ -13,6 +17,7 @@ const (
 	editorModeNormal editorMode = iota
 	editorModeInsert
 	editorModeCommand
+	editorModeVisual
 )
 
 type cursorPosition struct {
@@ -21,16 +26,73 @@ type cursorPosition struct {
 }
 
 type editorTab struct {
-	path      string
-	name      string
-	lines     []string
-	cursor    cursorPosition
-	scroll    int
-	mode      editorMode
-	pending   string
-	dirty     bool
-	created   bool
-	lastSaved int64
+	path    string
+	name    string
+	lines   []string
+	cursor  cursorPosition
+	scroll  int
+	mode    editorMode
+	pending string
+	dirty   bool
+	created bool
+	scratch bool // буфер без файла, path пуст до Save As
+
+	// содержимое и время изменения файла на момент последнего сохранения или загрузки
+	savedLines []string
+	savedAt    time.Time
+
+	// выделение в визуальном режиме: от anchor до cursor
+	anchor     cursorPosition
+	visualLine bool
+
+	undoStack []editorSnapshot
+	redoStack []editorSnapshot
+
+	// замены при декодировании/вставке и исходные байты файла для копии
+	decodeWarnings []string
+	original       []byte
+
+	// кэш подсветки и диапазон строк, требующих пересчёта (from < 0 — кэш актуален)
+	highlight     []syntax.Line
+	highlightFrom int
+	highlightTo   int
+
+	// скобка у курсора и её пара на момент последнего поиска
+	brackets bracketCache
+
+	// диагностики surge diag, сдвигаемые вместе с правками, и ранги меток
+	// гуттера по строкам
+	diagnostics []EditorDiagnostic
+	diagMarks   map[int]int
+
+	// закладки на строках и блоках, отсортированные по началу
+	bookmarks []editorBookmark
+
+	// объявления функций и типов на момент загрузки или сохранения
+	outline []syntax.Symbol
+
+	// ханки относительно savedLines; changesStale — строки [changesFrom,
+	// changesTo) правились после последнего пересчёта, changesEdits — правки
+	// на момент запуска отсчёта до пересчёта
+	changes      []changeHunk
+	changesStale bool
+	changesFrom  int
+	changesTo    int
+	changesEdits int
+
+	// автосохранение: edits растёт с каждой правкой, autosaveEdits — правки на
+	// момент запуска отсчёта, autosavedEdits — на момент записи копии
+	edits          int
+	autosaveEdits  int
+	autosavedEdits int
+	autosaveToken  int
+	autosavedAt    time.Time
+
+	// контрольные точки для отката по времени: checkpointEdits — правки на
+	// момент последней точки по таймеру, checkpointDue — срок запущенного отсчёта
+	checkpoints     []bufferCheckpoint
+	checkpointEdits int
+	checkpointDue   time.Time
 }
 
 func newEditorTab(path string) (*editorTab, error) {
@@ -41,12 +103,13 @@ func newEditorTab(path string) (*editorTab, error) {
 
 	lines := []string{""}
 	created := false
+	var warnings []string
+	var original []byte
 
 	if data, err := os.ReadFile(abs); err == nil {
-		text := strings.ReplaceAll(string(data), "\r\n", "\n")
-		lines = strings.Split(text, "\n")
-		if len(lines) == 0 {
-			lines = []string{""}
+		lines, warnings = decodeBuffer(data)
+		if len(warnings) > 0 {
+			original = data
 		}
 	} else {
 		if !os.IsNotExist(err) {
@@ -64,7 +127,15 @@ func newEditorTab(path string) (*editorTab, error) {
 		pending: "",
 		dirty:   created,
 		created: created,
+
+		decodeWarnings: warnings,
+		original:       original,
+	}
+	if !created {
+		tab.markSaved()
 	}
+	tab.keepCheckpoint(time.Now(), lines, true) // «File open»
+	tab.highlightFrom = -1
 	tab.clampCursor()
 	return tab, nil
 }
@@ -121,6 +192,7 @@ func (t *editorTab) insertRunes(rs []rune) {
 	if len(rs) == 0 {
 		return
 	}
+	t.noteLossyInput(rs)
 	lineRunes := []rune(t.lines[t.cursor.Line])
 	col := t.cursor.Col
 	if col > len(lineRunes) {
@@ -129,13 +201,48 @@ func (t *editorTab) insertRunes(rs []rune) {
 	newRunes := append(lineRunes[:col], append(rs, lineRunes[col:]...)...)
 	t.lines[t.cursor.Line] = string(newRunes)
 	t.cursor.Col += len(rs)
-	t.dirty = true
+	t.markLineChanged(t.cursor.Line)
 }
 
 func (t *editorTab) insertString(text string) {
 	t.insertRunes([]rune(text))
 }
 
+// insertText вставляет текст с переводами строк одной правкой: строки
+// разбиваются сразу, а не по символу.
+func (t *editorTab) insertText(text string) {
+	parts := strings.Split(normalizeNewlines(text), "\n")
+	if len(parts) == 1 {
+		t.insertString(parts[0])
+		return
+	}
+	t.noteLossyInput([]rune(text))
+
+	lineRunes := []rune(t.lines[t.cursor.Line])
+	col := min(t.cursor.Col, len(lineRunes))
+	right := string(lineRunes[col:])
+	last := len(parts) - 1
+
+	inserted := make([]string, 0, len(parts))
+	inserted = append(inserted, string(lineRunes[:col])+parts[0])
+	inserted = append(inserted, parts[1:last]...)
+	inserted = append(inserted, parts[last]+right)
+
+	at := t.cursor.Line
+	t.lines = append(t.lines[:at], append(inserted, t.lines[at+1:]...)...)
+	t.markLineChanged(at)
+	t.markLinesInserted(at+1, last)
+	t.cursor.Line = at + last
+	t.cursor.Col = utf8.RuneCountInString(parts[last])
+}
+
+// normalizeNewlines приводит \r\n и одиночный \r (так вставку шлют
+// многие терминалы) к \n.
+func normalizeNewlines(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\r", "\n")
+}
+
 func (t *editorTab) insertNewLine() {
 	lineRunes := []rune(t.lines[t.cursor.Line])
 	col := t.cursor.Col
@@ -154,9 +261,47 @@ func (t *editorTab) insertNewLine() {
 		t.lines = append(t.lines[:t.cursor.Line+1], append([]string{right}, t.lines[t.cursor.Line+1:]...)...)
 	}
 
+	t.markLineChanged(t.cursor.Line)
+	t.markLinesInserted(t.cursor.Line+1, 1)
 	t.cursor.Line++
 	t.cursor.Col = 0
-	t.dirty = true
+}
+
+// insertIndentedNewLine разбивает строку у курсора, как insertNewLine, но
+// новая строка повторяет отступ текущей, а после '{' или '(' — на unit
+// глубже. Enter между скобками ({|}) выносит закрывающую на свою строку с
+// прежним отступом, а курсор ставит на строку между ними.
+func (t *editorTab) insertIndentedNewLine(unit string) {
+	lineRunes := []rune(t.lines[t.cursor.Line])
+	col := min(t.cursor.Col, len(lineRunes))
+	left := string(lineRunes[:col])
+	right := strings.TrimLeft(string(lineRunes[col:]), " \t")
+
+	indent := lineIndent(left)
+	inner, closer := indent, ""
+	switch trimmed := strings.TrimRight(left, " \t"); {
+	case strings.HasSuffix(trimmed, "{"):
+		inner, closer = indent+unit, "}"
+	case strings.HasSuffix(trimmed, "("):
+		inner, closer = indent+unit, ")"
+	}
+	inserted := []string{inner + right}
+	if closer != "" && strings.HasPrefix(right, closer) {
+		inserted = []string{inner, indent + right}
+	}
+
+	at := t.cursor.Line
+	t.lines[at] = left
+	t.lines = append(t.lines[:at+1], append(inserted, t.lines[at+1:]...)...)
+	t.markLineChanged(at)
+	t.markLinesInserted(at+1, len(inserted))
+	t.cursor.Line = at + 1
+	t.cursor.Col = utf8.RuneCountInString(inner)
+}
+
+// lineIndent возвращает ведущие пробелы и табы строки.
+func lineIndent(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
 }
 
 func (t *editorTab) deleteBackward() {
@@ -169,7 +314,7 @@ func (t *editorTab) deleteBackward() {
 		newRunes := append(lineRunes[:col-1], lineRunes[col:]...)
 		t.lines[t.cursor.Line] = string(newRunes)
 		t.cursor.Col--
-		t.dirty = true
+		t.markLineChanged(t.cursor.Line)
 		return
 	}
 	if t.cursor.Line == 0 {
@@ -182,13 +327,9 @@ func (t *editorTab) deleteBackward() {
 	t.cursor.Col = len(prevLine)
 	t.lines[t.cursor.Line-1] = string(append(prevLine, []rune(current)...))
 	t.lines = append(t.lines[:t.cursor.Line], t.lines[t.cursor.Line+1:]...)
+	t.markLinesRemoved(t.cursor.Line, 1)
 	t.cursor.Line--
-	if len(t.lines) == 0 {
-		t.lines = []string{""}
-		t.cursor.Line = 0
-		t.cursor.Col = 0
-	}
-	t.dirty = true
+	t.markLineChanged(t.cursor.Line)
 }
 
 func (t *editorTab) deleteForward() {
@@ -197,7 +338,7 @@ func (t *editorTab) deleteForward() {
 	if col < len(lineRunes) {
 		newRunes := append(lineRunes[:col], lineRunes[col+1:]...)
 		t.lines[t.cursor.Line] = string(newRunes)
-		t.dirty = true
+		t.markLineChanged(t.cursor.Line)
 		return
 	}
 
@@ -208,12 +349,8 @@ func (t *editorTab) deleteForward() {
 	next := t.lines[t.cursor.Line+1]
 	t.lines[t.cursor.Line] = t.lines[t.cursor.Line] + next
 	t.lines = append(t.lines[:t.cursor.Line+1], t.lines[t.cursor.Line+2:]...)
-	if len(t.lines) == 0 {
-		t.lines = []string{""}
-		t.cursor.Line = 0
-		t.cursor.Col = 0
-	}
-	t.dirty = true
+	t.markLinesRemoved(t.cursor.Line+1, 1)
+	t.markLineChanged(t.cursor.Line)
 }
 
 func (t *editorTab) deleteLine() string {
@@ -228,14 +365,15 @@ func (t *editorTab) deleteLine() string {
 	if len(t.lines) == 1 {
 		t.lines[0] = ""
 		t.cursor.Col = 0
+		t.markLineChanged(0)
 	} else {
 		t.lines = append(t.lines[:t.cursor.Line], t.lines[t.cursor.Line+1:]...)
+		t.markLinesRemoved(t.cursor.Line, 1)
 		if t.cursor.Line >= len(t.lines) {
 			t.cursor.Line = len(t.lines) - 1
 		}
 	}
 	t.clampCursor()
-	t.dirty = true
 	return line
 }
 
@@ -255,8 +393,8 @@ func (t *editorTab) pasteLine(content string) {
 		t.lines = append(t.lines[:insertIndex], append([]string{content}, t.lines[insertIndex:]...)...)
 		t.cursor.Line = insertIndex
 	}
+	t.markLinesInserted(insertIndex, 1)
 	t.cursor.Col = 0
-	t.dirty = true
 }
 
 func (t *editorTab) setCursorPosition(line, column int) {
@@ -283,16 +421,26 @@ func (t *editorTab) setCursorPosition(line, column int) {
 	t.clampCursor()
 }
 
+// text возвращает содержимое буфера в том виде, в каком его записывает save:
+// копии буфера (автосохранение, резервная копия) должны совпадать с файлом.
+func (t *editorTab) text() string {
+	return strings.Join(t.lines, "\n")
+}
+
 func (t *editorTab) save() error {
 	perm := os.FileMode(0o644)
 	if info, err := os.Stat(t.path); err == nil {
 		perm = info.Mode()
 	}
-	content := strings.Join(t.lines, "\n")
+	content := t.text()
 	if err := os.WriteFile(t.path, []byte(content), perm); err != nil {
+		logging.Errorf("save %s: %v", t.path, err)
 		return err
 	}
+	logging.Debugf("saved %s (%d bytes)", t.path, len(content))
 	t.dirty = false
+	t.clearLossy()
+	t.markSaved()
 	return nil
 }
 
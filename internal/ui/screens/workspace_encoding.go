@@ -0,0 +1,87 @@
+package screens
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Буфер хранит текст в UTF-8. Всё, что не удалось представить как есть
+// (невалидные байты, CRLF), заменяется при загрузке или вставке. Такие
+// замены запоминаются, чтобы сохранение не теряло данные молча.
+
+const originalCopySuffix = ".orig"
+
+// decodeBuffer разбивает содержимое файла на строки и сообщает о заменах.
+func decodeBuffer(data []byte) ([]string, []string) {
+	var warnings []string
+
+	if n := countInvalidUTF8(data); n > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d invalid UTF-8 sequence(s) replaced with U+FFFD", n))
+	}
+	text := strings.ToValidUTF8(string(data), string(utf8.RuneError))
+
+	if crlf := bytes.Count(data, []byte("\r\n")); crlf > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d CRLF line ending(s) converted to LF", crlf))
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+	}
+
+	lines := strings.Split(text, "\n")
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+	return lines, warnings
+}
+
+func countInvalidUTF8(data []byte) int {
+	count := 0
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size <= 1 {
+			count++
+		}
+		data = data[size:]
+	}
+	return count
+}
+
+// isLossy сообщает, отличается ли буфер от исходных байт не только правками пользователя.
+func (t *editorTab) isLossy() bool {
+	return len(t.decodeWarnings) > 0
+}
+
+// noteLossyInput запоминает, что вставленный текст содержал непредставимые символы.
+func (t *editorTab) noteLossyInput(rs []rune) {
+	for _, r := range rs {
+		if r == utf8.RuneError {
+			const warning = "inserted text contained invalid bytes (replaced with U+FFFD)"
+			for _, w := range t.decodeWarnings {
+				if w == warning {
+					return
+				}
+			}
+			t.decodeWarnings = append(t.decodeWarnings, warning)
+			return
+		}
+	}
+}
+
+// saveOriginalCopy записывает исходные байты файла рядом с ним.
+func (t *editorTab) saveOriginalCopy() (string, error) {
+	if t.original == nil {
+		return "", fmt.Errorf("original content is not available")
+	}
+	path := t.path + originalCopySuffix
+	if err := os.WriteFile(path, t.original, 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// clearLossy сбрасывает предупреждения после сохранения: файл на диске теперь совпадает с буфером.
+func (t *editorTab) clearLossy() {
+	t.decodeWarnings = nil
+	t.original = nil
+}
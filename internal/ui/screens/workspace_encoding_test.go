@@ -0,0 +1,110 @@
+package screens
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDecodeBufferReportsLossyInput(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		lines    []string
+		warnings []string
+	}{
+		{"clean", "a\nb", []string{"a", "b"}, nil},
+		{"crlf", "a\r\nb\r\n", []string{"a", "b", ""}, []string{"2 CRLF line ending(s) converted to LF"}},
+		{"invalid utf-8", "a\xffb\n\xfe", []string{"a�b", "�"}, []string{"2 invalid UTF-8 sequence(s) replaced with U+FFFD"}},
+		{"both", "\xff\r\n", []string{"�", ""}, []string{
+			"1 invalid UTF-8 sequence(s) replaced with U+FFFD",
+			"1 CRLF line ending(s) converted to LF",
+		}},
+		{"lone cr stays", "a\rb", []string{"a\rb"}, nil},
+	}
+	for _, c := range cases {
+		lines, warnings := decodeBuffer([]byte(c.data))
+		if !slices.Equal(lines, c.lines) || !slices.Equal(warnings, c.warnings) {
+			t.Errorf("%s: got %q %q, want %q %q", c.name, lines, warnings, c.lines, c.warnings)
+		}
+	}
+}
+
+func TestLossyInputIsTrackedUntilSave(t *testing.T) {
+	tab := newTestTab(t, "clean.sg", "let a = 1")
+	if tab.isLossy() {
+		t.Fatal("clean file reported as lossy")
+	}
+	tab.insertRunes([]rune{'x', utf8.RuneError})
+	tab.insertRunes([]rune{utf8.RuneError})
+	if len(tab.decodeWarnings) != 1 {
+		t.Fatalf("warnings = %q, want one for inserted text", tab.decodeWarnings)
+	}
+	if err := tab.save(); err != nil {
+		t.Fatal(err)
+	}
+	if tab.isLossy() {
+		t.Error("warnings survived a save")
+	}
+}
+
+func lossyProject(t *testing.T) (*ProjectScreenReal, *editorTab, []byte) {
+	t.Helper()
+	original := []byte("let a = 1\r\nlet b = \xff\r\n")
+	tab := newTestTab(t, "lossy.sg")
+	if err := os.WriteFile(tab.path, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tab.reloadFromDisk(); err != nil {
+		t.Fatal(err)
+	}
+	if !tab.isLossy() || tab.original == nil {
+		t.Fatalf("reloaded tab is not lossy: %q", tab.decodeWarnings)
+	}
+	return newTestProject(t, tab), tab, original
+}
+
+func TestLossySaveAsksBeforeWriting(t *testing.T) {
+	ps, tab, original := lossyProject(t)
+	ps.saveActiveTab()
+	if !ps.lossyDialog.Visible {
+		t.Fatal("no confirmation for a lossy save")
+	}
+	for _, warning := range tab.decodeWarnings {
+		if !strings.Contains(ps.lossyDialog.Description, warning) {
+			t.Errorf("dialog does not mention %q", warning)
+		}
+	}
+	if !slices.Contains(ps.lossyDialog.Options, lossySaveKeepOption) {
+		t.Errorf("options %q lack keeping the original", ps.lossyDialog.Options)
+	}
+	if data, _ := os.ReadFile(tab.path); string(data) != string(original) {
+		t.Errorf("file written before confirmation: %q", data)
+	}
+
+	ps.lossyDialog.Hide()
+	ps.handleLossySaveChoice(lossySaveChoiceMsg{path: tab.path, option: lossySaveCancelOption})
+	if data, _ := os.ReadFile(tab.path); string(data) != string(original) {
+		t.Errorf("canceled save wrote the file: %q", data)
+	}
+	if !tab.isLossy() {
+		t.Error("canceled save cleared the warnings")
+	}
+}
+
+func TestLossySaveKeepsOriginalCopy(t *testing.T) {
+	ps, tab, original := lossyProject(t)
+	ps.handleLossySaveChoice(lossySaveChoiceMsg{path: tab.path, option: lossySaveKeepOption})
+
+	if data, err := os.ReadFile(tab.path + originalCopySuffix); err != nil || string(data) != string(original) {
+		t.Errorf("original copy = %q, %v", data, err)
+	}
+	if data, _ := os.ReadFile(tab.path); string(data) != "let a = 1\nlet b = �\n" {
+		t.Errorf("saved content = %q", data)
+	}
+	if tab.isLossy() || tab.dirty {
+		t.Error("tab still lossy or dirty after save")
+	}
+}
@@ -0,0 +1,164 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	core "surge-tui/internal/core/surge"
+)
+
+// Правки фиксов приходят в координатах surge: строки и колонки 1-based,
+// колонки считаются в рунах, конец диапазона не включается.
+
+type bufferRange struct {
+	startLine, startCol int
+	endLine, endCol     int
+}
+
+// fixEditRange переводит позицию правки в 0-based диапазон буфера.
+func (t *editorTab) fixEditRange(loc core.LocationJSON) (bufferRange, error) {
+	startLine := int(loc.StartLine)
+	endLine := int(loc.EndLine)
+	if startLine == 0 {
+		startLine = endLine
+	}
+	if startLine == 0 {
+		return bufferRange{}, fmt.Errorf("edit has no line information")
+	}
+	if endLine == 0 {
+		endLine = startLine
+	}
+	if startLine > len(t.lines) || endLine < startLine {
+		return bufferRange{}, fmt.Errorf("edit range %d-%d is outside the buffer", startLine, endLine)
+	}
+	endLine = min(endLine, len(t.lines))
+
+	r := bufferRange{startLine: startLine - 1, endLine: endLine - 1}
+	r.startCol = clampInt(int(loc.StartCol)-1, 0, len([]rune(t.lines[r.startLine])))
+	endRunes := len([]rune(t.lines[r.endLine]))
+	if loc.EndCol == 0 {
+		r.endCol = endRunes
+	} else {
+		r.endCol = clampInt(int(loc.EndCol)-1, 0, endRunes)
+	}
+	if r.startLine == r.endLine && r.endCol < r.startCol {
+		r.endCol = r.startCol
+	}
+	return r, nil
+}
+
+// textInRange возвращает текст диапазона буфера.
+func (t *editorTab) textInRange(r bufferRange) string {
+	if r.startLine == r.endLine {
+		runes := []rune(t.lines[r.startLine])
+		return string(runes[r.startCol:r.endCol])
+	}
+	parts := []string{string([]rune(t.lines[r.startLine])[r.startCol:])}
+	parts = append(parts, t.lines[r.startLine+1:r.endLine]...)
+	parts = append(parts, string([]rune(t.lines[r.endLine])[:r.endCol]))
+	return strings.Join(parts, "\n")
+}
+
+// replaceRange заменяет диапазон текстом, сообщая подсветке и диагностикам о сдвиге строк.
+func (t *editorTab) replaceRange(r bufferRange, text string) {
+	head := string([]rune(t.lines[r.startLine])[:r.startCol])
+	tail := string([]rune(t.lines[r.endLine])[r.endCol:])
+	inserted := strings.Split(head+text+tail, "\n")
+
+	removed := r.endLine - r.startLine + 1
+	lines := make([]string, 0, len(t.lines)-removed+len(inserted))
+	lines = append(lines, t.lines[:r.startLine]...)
+	lines = append(lines, inserted...)
+	lines = append(lines, t.lines[r.endLine+1:]...)
+	t.lines = lines
+
+	if removed > 1 {
+		t.markLinesRemoved(r.startLine+1, removed-1)
+	}
+	if len(inserted) > 1 {
+		t.markLinesInserted(r.startLine+1, len(inserted)-1)
+	}
+	t.markLineChanged(r.startLine)
+
+	last := inserted[len(inserted)-1]
+	t.cursor = cursorPosition{
+		Line: r.startLine + len(inserted) - 1,
+		Col:  len([]rune(last)) - len([]rune(tail)),
+	}
+}
+
+// applyFixEdits применяет правки фикса к буферу одним шагом undo.
+// Если OldText не совпадает с буфером или правки пересекаются, ничего не меняется.
+func (t *editorTab) applyFixEdits(edits []core.FixEditJSON) error {
+	if len(edits) == 0 {
+		return fmt.Errorf("fix has no edits")
+	}
+	content := []byte(t.text())
+	ordered, overlaps, err := core.NormalizeEdits(content, edits)
+	if err != nil {
+		return err
+	}
+	if err := core.OverlapError(overlaps); err != nil {
+		return err
+	}
+
+	type resolved struct {
+		r    bufferRange
+		text string
+	}
+	plan := make([]resolved, 0, len(ordered))
+	for _, edit := range ordered {
+		r, err := t.fixEditRange(edit.Edit.Location)
+		if err != nil {
+			return err
+		}
+		if edit.Edit.OldText != "" && t.textInRange(r) != edit.Edit.OldText {
+			return fmt.Errorf("buffer differs from the fix at line %d", r.startLine+1)
+		}
+		plan = append(plan, resolved{r: r, text: edit.Edit.NewText})
+	}
+
+	// применяем с конца, чтобы ранние позиции оставались верными
+	t.pushUndo()
+	for i := len(plan) - 1; i >= 0; i-- {
+		t.replaceRange(plan[i].r, plan[i].text)
+	}
+	t.clampCursor()
+	return nil
+}
+
+// reloadFromDisk перечитывает файл после внешней правки, сохраняя undo.
+func (t *editorTab) reloadFromDisk() error {
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return err
+	}
+	lines, warnings := decodeBuffer(data)
+	t.pushUndo()
+	old := t.lines
+	t.lines = lines
+	t.markModified()
+	t.dirty = false
+	t.decodeWarnings = warnings
+	t.original = nil
+	if len(warnings) > 0 {
+		t.original = data
+	}
+	t.markSaved()
+	t.remapCursor(old)
+	return nil
+}
+
+// reloadIfChanged перечитывает файл, только если на диске не то, что в буфере.
+func (t *editorTab) reloadIfChanged() (bool, error) {
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return false, err
+	}
+	if lines, _ := decodeBuffer(data); slices.Equal(lines, t.lines) {
+		return false, nil
+	}
+	return true, t.reloadFromDisk()
+}
@@ -0,0 +1,125 @@
+package screens
+
+import "surge-tui/internal/syntax"
+
+// Инкрементальная подсветка: правки сообщают затронутые строки, а
+// highlightLines пересчитывает только их и дальше, пока состояние лексера
+// на конце строки не совпадёт с закэшированным.
+
+// markModified помечает весь буфер изменённым.
+func (t *editorTab) markModified() {
+	t.dirty = true
+	t.edits++
+	t.diagnostics = nil
+	t.clearDiagnosticMarks()
+	t.clampBookmarks()
+	t.clampOutline()
+	t.invalidateHighlight(0, len(t.lines)-1)
+	t.invalidateChanges()
+}
+
+// markLineChanged помечает одну строку изменённой.
+func (t *editorTab) markLineChanged(line int) {
+	t.dirty = true
+	t.edits++
+	t.dropDiagnosticsOnLine(line)
+	t.invalidateHighlight(line, line)
+	t.touchChanges(line, line+1)
+}
+
+// markLinesInserted сдвигает кэш после вставки count строк перед индексом at.
+func (t *editorTab) markLinesInserted(at, count int) {
+	t.dirty = true
+	t.edits++
+	if count <= 0 {
+		return
+	}
+	t.shiftDiagnostics(at, count)
+	t.shiftBookmarks(at, count)
+	t.shiftOutline(at, count)
+	t.shiftChangesInserted(at, count)
+	if at >= 0 && at <= len(t.highlight) {
+		placeholder := make([]syntax.Line, count)
+		t.highlight = append(t.highlight[:at], append(placeholder, t.highlight[at:]...)...)
+	}
+	if t.highlightFrom >= 0 {
+		if t.highlightFrom >= at {
+			t.highlightFrom += count
+		}
+		if t.highlightTo >= at {
+			t.highlightTo += count
+		}
+	}
+	// у вставленных строк нет прежнего состояния для сравнения, поэтому
+	// пересчёт захватывает и строку после них: её вход мог измениться
+	t.invalidateHighlight(at, at+count)
+}
+
+// markLinesRemoved сдвигает кэш после удаления count строк начиная с at.
+func (t *editorTab) markLinesRemoved(at, count int) {
+	t.dirty = true
+	t.edits++
+	if count <= 0 {
+		return
+	}
+	t.removeDiagnosticLines(at, count)
+	t.removeBookmarkLines(at, count)
+	t.removeOutlineLines(at, count)
+	t.shiftChangesRemoved(at, count)
+	if at >= 0 && at+count <= len(t.highlight) {
+		t.highlight = append(t.highlight[:at], t.highlight[at+count:]...)
+	}
+	if t.highlightFrom >= 0 {
+		if t.highlightFrom >= at+count {
+			t.highlightFrom -= count
+		} else if t.highlightFrom > at {
+			t.highlightFrom = at
+		}
+		if t.highlightTo >= at+count {
+			t.highlightTo -= count
+		} else if t.highlightTo >= at {
+			t.highlightTo = at
+		}
+	}
+	// строка, занявшая место удалённых, получает новое входное состояние
+	t.invalidateHighlight(at, at)
+}
+
+func (t *editorTab) invalidateHighlight(from, to int) {
+	last := len(t.lines) - 1
+	from = clampInt(from, 0, max(last, 0))
+	to = clampInt(to, from, max(last, 0))
+	if t.highlightFrom < 0 {
+		t.highlightFrom, t.highlightTo = from, to
+		return
+	}
+	t.highlightFrom = min(t.highlightFrom, from)
+	t.highlightTo = max(t.highlightTo, to)
+}
+
+// highlightLines возвращает токены подсветки, пересчитывая только изменённые строки.
+func (t *editorTab) highlightLines() []syntax.Line {
+	if len(t.highlight) != len(t.lines) {
+		t.highlight = syntax.HighlightDocument(t.lines)
+		t.highlightFrom = -1
+		return t.highlight
+	}
+	if t.highlightFrom < 0 {
+		return t.highlight
+	}
+
+	state := syntax.StateNormal
+	if t.highlightFrom > 0 {
+		state = t.highlight[t.highlightFrom-1].EndState
+	}
+	for i := t.highlightFrom; i < len(t.lines); i++ {
+		prev := t.highlight[i].EndState
+		t.highlight[i] = syntax.HighlightLine(t.lines[i], state)
+		state = t.highlight[i].EndState
+		if i >= t.highlightTo && state == prev {
+			break
+		}
+	}
+	t.highlightFrom = -1
+	return t.highlight
+}
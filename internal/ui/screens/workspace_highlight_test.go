@@ -0,0 +1,197 @@
+package screens
+
+import (
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"surge-tui/internal/syntax"
+)
+
+func TestIncrementalHighlightMatchesFullPass(t *testing.T) {
+	tab := newTestTab(t, "main.sg",
+		`fn main() {`,
+		`  let s = "/* not a comment";`,
+		`  let n = 1 // tail`,
+		`  call(n)`,
+		`}`,
+	)
+	steps := []struct {
+		name string
+		edit func(tab *editorTab)
+	}{
+		{"open a block comment", func(tab *editorTab) {
+			moveTo(tab, 1, 0)
+			tab.insertString("/* ")
+		}},
+		{"close it two lines below", func(tab *editorTab) {
+			moveTo(tab, 3, len([]rune(tab.lines[3])))
+			tab.insertString(" */")
+		}},
+		{"insert lines inside the comment", func(tab *editorTab) {
+			moveTo(tab, 2, 2)
+			tab.insertText("a\nb /* nested\nc")
+		}},
+		{"split a line", func(tab *editorTab) {
+			moveTo(tab, 0, 3)
+			tab.insertNewLine()
+		}},
+		{"remove the opener", func(tab *editorTab) {
+			moveTo(tab, 2, 0)
+			for range 3 {
+				tab.deleteForward()
+			}
+		}},
+		{"join lines", func(tab *editorTab) {
+			moveTo(tab, 1, 0)
+			tab.deleteBackward()
+		}},
+		{"open a comment inside a string", func(tab *editorTab) {
+			moveTo(tab, 0, 0)
+			tab.insertString(`"/*" `)
+		}},
+		{"replace a multi-line range", func(tab *editorTab) {
+			tab.replaceRange(bufferRange{startLine: 1, startCol: 2, endLine: 3, endCol: 1}, "/*\nx\n")
+		}},
+		{"delete lines", func(tab *editorTab) {
+			moveTo(tab, 2, 0)
+			tab.deleteLine()
+			tab.deleteLine()
+		}},
+		{"paste a line", func(tab *editorTab) {
+			moveTo(tab, 0, 0)
+			tab.pasteLine("*/ after")
+		}},
+	}
+
+	tab.highlightLines()
+	for _, step := range steps {
+		step.edit(tab)
+		got := tab.highlightLines()
+		want := syntax.HighlightDocument(tab.lines)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: incremental highlight differs from a full pass\nlines: %q\ngot:  %+v\nwant: %+v", step.name, tab.lines, got, want)
+		}
+	}
+}
+
+func TestIncrementalHighlightRandomEdits(t *testing.T) {
+	fragments := []string{"/*", "*/", `"`, `"/*"`, "//", "\n", "x", " ", "\n/* a\nb */\n"}
+	rng := rand.New(rand.NewPCG(1, 2))
+	tab := newTestTab(t, "main.sg", "fn main() {", "  /* a", "  b */ c", `  d = "e"`, "}")
+	tab.highlightLines()
+	for i := range 500 {
+		line := rng.IntN(len(tab.lines))
+		moveTo(tab, line, rng.IntN(len([]rune(tab.lines[line]))+1))
+		switch rng.IntN(5) {
+		case 0:
+			tab.deleteBackward()
+		case 1:
+			tab.deleteForward()
+		case 2:
+			if len(tab.lines) > 1 {
+				tab.deleteLine()
+			}
+		case 3:
+			tab.pasteLine(fragments[rng.IntN(5)]) // без переводов строк
+		default:
+			tab.insertText(fragments[rng.IntN(len(fragments))])
+		}
+		if got, want := tab.highlightLines(), syntax.HighlightDocument(tab.lines); !reflect.DeepEqual(got, want) {
+			t.Fatalf("edit %d: incremental highlight differs from a full pass\nlines: %q", i, tab.lines)
+		}
+	}
+}
+
+// bigHighlightTab открывает буфер из 10k одинаковых непустых строк с уже
+// посчитанной подсветкой.
+func bigHighlightTab(tb testing.TB) *editorTab {
+	lines := make([]string, 10000)
+	for i := range lines {
+		lines[i] = `  let x = call(1, "s") // n`
+	}
+	path := filepath.Join(tb.TempDir(), "big.sg")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		tb.Fatal(err)
+	}
+	tab, err := newEditorTab(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tab.highlightLines()
+	return tab
+}
+
+// rehighlighted считает строки, чьи токены пересчитаны: HighlightLine
+// каждый раз выделяет новый срез, а нетронутые строки сохраняют прежний.
+func rehighlighted(before, after []syntax.Line) int {
+	count := 0
+	for i := range after {
+		if &before[i].Tokens[0] != &after[i].Tokens[0] {
+			count++
+		}
+	}
+	return count
+}
+
+func TestIncrementalHighlightTouchesFewLines(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(tab *editorTab)
+		max  int
+	}{
+		{"edit inside a line", func(tab *editorTab) {
+			moveTo(tab, 5000, 2)
+			tab.insertString("y")
+		}, 1},
+		{"comment out to a closer three lines below", func(tab *editorTab) {
+			moveTo(tab, 5003, 0)
+			tab.insertString("*/")
+			tab.highlightLines()
+			moveTo(tab, 5000, 0)
+			tab.insertString("/*")
+		}, 5},
+		{"delete a character", func(tab *editorTab) {
+			moveTo(tab, 9999, 0)
+			tab.deleteForward()
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab := bigHighlightTab(t)
+			tt.edit(tab)
+			before := slices.Clone(tab.highlight)
+			got := tab.highlightLines()
+			if n := rehighlighted(before, got); n == 0 || n > tt.max {
+				t.Errorf("re-highlighted %d of %d lines, want 1..%d", n, len(got), tt.max)
+			}
+			if want := syntax.HighlightDocument(tab.lines); !reflect.DeepEqual(got, want) {
+				t.Errorf("incremental highlight differs from a full pass")
+			}
+		})
+	}
+}
+
+func BenchmarkIncrementalHighlight(b *testing.B) {
+	tab := bigHighlightTab(b)
+	b.Run("single-line edit", func(b *testing.B) {
+		for i := range b.N {
+			moveTo(tab, 5000, 2)
+			if i%2 == 0 {
+				tab.insertString("y")
+			} else {
+				tab.deleteForward()
+			}
+			tab.highlightLines()
+		}
+	})
+	b.Run("full pass", func(b *testing.B) {
+		for range b.N {
+			syntax.HighlightDocument(tab.lines)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package screens
+
+import (
+	"slices"
+	"strings"
+)
+
+// lineTransform — преобразование строк выделения целиком.
+type lineTransform int
+
+const (
+	linesSort lineTransform = iota
+	linesSortUnique
+	linesReverse
+)
+
+// apply возвращает строки block после преобразования; block не меняется.
+// Сортировка побайтовая, без учёта локали.
+func (tr lineTransform) apply(block []string) []string {
+	result := slices.Clone(block)
+	switch tr {
+	case linesSort:
+		slices.SortStableFunc(result, strings.Compare)
+	case linesSortUnique:
+		slices.SortStableFunc(result, strings.Compare)
+		result = slices.Compact(result)
+	case linesReverse:
+		slices.Reverse(result)
+	}
+	return result
+}
+
+// transformLines заменяет строки [from, to] результатом tr. Возвращает
+// число строк после преобразования и false, если текст не изменился.
+func (t *editorTab) transformLines(from, to int, tr lineTransform) (int, bool) {
+	block := t.lines[from : to+1]
+	result := tr.apply(block)
+	if slices.Equal(result, block) {
+		return len(block), false
+	}
+
+	lines := make([]string, 0, len(t.lines)-len(block)+len(result))
+	lines = append(lines, t.lines[:from]...)
+	lines = append(lines, result...)
+	lines = append(lines, t.lines[to+1:]...)
+	old := slices.Clone(block)
+	t.lines = lines
+
+	if removed := len(old) - len(result); removed > 0 {
+		t.markLinesRemoved(from+len(result), removed)
+	}
+	for i, line := range result {
+		if line != old[i] {
+			t.markLineChanged(from + i)
+		}
+	}
+	return len(result), true
+}
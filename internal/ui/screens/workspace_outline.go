@@ -0,0 +1,75 @@
+package screens
+
+import "surge-tui/internal/syntax"
+
+// Оглавление вкладки — объявления функций и типов для ]f / [f. Оно
+// строится при загрузке и сохранении .sg-файла, а между сохранениями
+// сдвигается вместе со строками, как закладки.
+
+// refreshOutline перестраивает оглавление по текущему буферу.
+func (t *editorTab) refreshOutline() {
+	t.outline = nil
+	if syntax.SupportsFile(t.name) {
+		t.outline = syntax.Outline(t.lines)
+	}
+}
+
+// shiftOutline сдвигает объявления после вставки count строк перед at.
+func (t *editorTab) shiftOutline(at, count int) {
+	for i := range t.outline {
+		if t.outline[i].Line >= at {
+			t.outline[i].Line += count
+		}
+	}
+}
+
+// removeOutlineLines забывает объявления на удалённых строках.
+func (t *editorTab) removeOutlineLines(at, count int) {
+	if len(t.outline) == 0 {
+		return
+	}
+	kept := t.outline[:0]
+	for _, sym := range t.outline {
+		switch {
+		case sym.Line >= at+count:
+			sym.Line -= count
+		case sym.Line >= at:
+			continue
+		}
+		kept = append(kept, sym)
+	}
+	t.outline = kept
+}
+
+// clampOutline отбрасывает объявления за концом буфера после замены содержимого.
+func (t *editorTab) clampOutline() {
+	kept := t.outline[:0]
+	for _, sym := range t.outline {
+		if sym.Line < len(t.lines) {
+			kept = append(kept, sym)
+		}
+	}
+	t.outline = kept
+}
+
+// symbolFrom возвращает первое объявление ниже строки (dir > 0) или выше
+// неё; wrapped — поиск перешёл через конец буфера.
+func (t *editorTab) symbolFrom(line, dir int) (sym syntax.Symbol, wrapped, ok bool) {
+	if len(t.outline) == 0 {
+		return syntax.Symbol{}, false, false
+	}
+	if dir > 0 {
+		for _, s := range t.outline {
+			if s.Line > line {
+				return s, false, true
+			}
+		}
+		return t.outline[0], true, true
+	}
+	for i := len(t.outline) - 1; i >= 0; i-- {
+		if t.outline[i].Line < line {
+			return t.outline[i], false, true
+		}
+	}
+	return t.outline[len(t.outline)-1], true, true
+}
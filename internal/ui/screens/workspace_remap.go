@@ -0,0 +1,163 @@
+package screens
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Когда содержимое буфера подменяется целиком (перезагрузка после фикса, откат),
+// курсор переносится через вставки и удаления строк, а не просто обрезается:
+// общие начало и конец файла сопоставляются сразу, изменившаяся середина — по LCS.
+
+// maxRemapCells ограничивает таблицу LCS для изменившейся середины; для больших
+// правок строка курсора ищется по тексту рядом с ожидаемым местом.
+const maxRemapCells = 4_000_000
+
+// remapLine переводит номер строки line старого содержимого в новое.
+// same сообщает, что строка сохранилась без изменений (колонку можно оставить).
+func remapLine(oldLines, newLines []string, line int) (int, bool) {
+	if len(newLines) == 0 {
+		return 0, false
+	}
+	if line < 0 || line >= len(oldLines) {
+		return clampInt(line, 0, len(newLines)-1), false
+	}
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	if line < prefix {
+		return line, true
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+	if line >= len(oldLines)-suffix {
+		return line + len(newLines) - len(oldLines), true
+	}
+
+	oldMid := oldLines[prefix : len(oldLines)-suffix]
+	newMid := newLines[prefix : len(newLines)-suffix]
+	rel := line - prefix
+	if len(newMid) == 0 {
+		// середину удалили: курсор встаёт на первую строку после неё
+		return min(prefix, len(newLines)-1), false
+	}
+	var mapped int
+	var same bool
+	if len(oldMid)*len(newMid) <= maxRemapCells {
+		mapped, same = remapByLCS(oldMid, newMid, rel)
+	} else {
+		mapped, same = remapByText(oldMid, newMid, rel)
+	}
+	return prefix + clampInt(mapped, 0, len(newMid)-1), same
+}
+
+// remapByLCS сопоставляет строки по наибольшей общей подпоследовательности.
+// Изменённая строка курсора ставится с тем же отступом от ближайшей
+// сохранившейся строки выше.
+func remapByLCS(oldLines, newLines []string, line int) (int, bool) {
+	n, m := len(oldLines), len(newLines)
+	// lcs[i][j] — длина LCS суффиксов oldLines[i:] и newLines[j:]
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lastOld, lastNew := -1, -1 // последняя сопоставленная пара выше курсора
+	for i, j := 0, 0; i < n && j < m && i <= line; {
+		switch {
+		case oldLines[i] == newLines[j]:
+			if i == line {
+				return j, true
+			}
+			lastOld, lastNew = i, j
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return lastNew + (line - lastOld), false
+}
+
+// remapByText ищет ту же строку в новом тексте ближе всего к пропорциональной позиции.
+func remapByText(oldLines, newLines []string, line int) (int, bool) {
+	guess := line * len(newLines) / len(oldLines)
+	for delta := 0; delta < len(newLines); delta++ {
+		for _, j := range []int{guess - delta, guess + delta} {
+			if j >= 0 && j < len(newLines) && newLines[j] == oldLines[line] {
+				return j, true
+			}
+		}
+	}
+	return guess, false
+}
+
+// remapCursor переносит курсор и прокрутку со старого содержимого на текущее:
+// экран сдвигается вместе со строкой курсора. Строки сравниваются без пробелов,
+// чтобы переформатирование не сбивало курсор; колонка сохраняет число
+// непробельных символов перед курсором.
+func (t *editorTab) remapCursor(oldLines []string) {
+	line, same := remapLine(stripSpaceLines(oldLines), stripSpaceLines(t.lines), t.cursor.Line)
+	if same && t.cursor.Line < len(oldLines) && line < len(t.lines) {
+		t.cursor.Col = remapColumn(oldLines[t.cursor.Line], t.lines[line], t.cursor.Col)
+	}
+	t.scroll = max(t.scroll+line-t.cursor.Line, 0)
+	t.cursor.Line = line
+	t.clampCursor()
+}
+
+func stripSpaceLines(lines []string) []string {
+	stripped := make([]string, len(lines))
+	for i, line := range lines {
+		stripped[i] = strings.Join(strings.Fields(line), "")
+	}
+	return stripped
+}
+
+// remapColumn находит в newLine позицию с тем же числом непробельных символов
+// слева, что и у col в oldLine (строки отличаются только пробелами).
+func remapColumn(oldLine, newLine string, col int) int {
+	oldRunes := []rune(oldLine)
+	col = clampInt(col, 0, len(oldRunes))
+	if oldLine == newLine {
+		return col
+	}
+	visible := 0
+	for _, r := range oldRunes[:col] {
+		if !unicode.IsSpace(r) {
+			visible++
+		}
+	}
+	newRunes := []rune(newLine)
+	pos := 0
+	for ; pos < len(newRunes) && visible > 0; pos++ {
+		if !unicode.IsSpace(newRunes[pos]) {
+			visible--
+		}
+	}
+	if col < len(oldRunes) && !unicode.IsSpace(oldRunes[col]) {
+		// курсор стоял на символе: пропускаем пробелы перед ним
+		for pos < len(newRunes) && unicode.IsSpace(newRunes[pos]) {
+			pos++
+		}
+	}
+	return pos
+}
@@ -0,0 +1,141 @@
+package screens
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRemapLine(t *testing.T) {
+	old := []string{"a", "b", "c", "d", "e"}
+	tests := []struct {
+		name     string
+		newLines []string
+		line     int
+		want     int
+		wantSame bool
+	}{
+		{"без изменений", old, 2, 2, true},
+		{"вставка выше курсора", []string{"new", "a", "b", "c", "d", "e"}, 2, 3, true},
+		{"удаление выше курсора", []string{"a", "c", "d", "e"}, 2, 1, true},
+		{"вставка ниже курсора", []string{"a", "b", "c", "new", "d", "e"}, 2, 2, true},
+		{"удаление ниже курсора", []string{"a", "b", "c", "e"}, 2, 2, true},
+		{"изменена строка курсора", []string{"a", "b", "C", "d", "e"}, 2, 2, false},
+		{"удалена строка курсора", []string{"a", "b", "d", "e"}, 2, 2, false},
+		{"удалена середина с курсором", []string{"a", "e"}, 2, 1, false},
+		{"правки выше и ниже", []string{"x", "a", "y", "b", "c", "z", "d", "e"}, 2, 4, true},
+		// LCS сохраняет a и b, перенесённая строка курсора считается изменённой
+		{"строка курсора перенесена вверх", []string{"c", "a", "b", "d", "e"}, 2, 2, false},
+		{"курсор за концом", []string{"a"}, 9, 0, false},
+		{"пустое новое содержимое", nil, 2, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, same := remapLine(old, tt.newLines, tt.line)
+			if got != tt.want || same != tt.wantSame {
+				t.Errorf("remapLine = %d, %v; want %d, %v", got, same, tt.want, tt.wantSame)
+			}
+		})
+	}
+}
+
+// На больших правках вместо LCS строка ищется по тексту рядом с
+// пропорциональной позицией — результат тот же для сохранившейся строки.
+func TestRemapLineLargeMiddle(t *testing.T) {
+	const n = 2100 // n*n > maxRemapCells
+	old := make([]string, n)
+	next := make([]string, 0, n+n/2)
+	for i := range old {
+		old[i] = fmt.Sprintf("old %d", i)
+		next = append(next, old[i])
+		if i%2 == 0 {
+			next = append(next, fmt.Sprintf("inserted %d", i))
+		}
+	}
+	// первая и последняя строки меняются, чтобы общей рамки не было
+	old[0], old[n-1] = "first", "last"
+	if got, same := remapLine(old, next, 1000); !same || next[got] != old[1000] {
+		t.Errorf("remapLine = %d (%q), %v; want the line %q", got, next[got], same, old[1000])
+	}
+}
+
+func TestRemapCursor(t *testing.T) {
+	tests := []struct {
+		name      string
+		old       []string
+		next      []string
+		line, col int
+		wantLine  int
+		wantCol   int
+	}{
+		{
+			name:     "строки вставлены выше",
+			old:      []string{"fn a() {}", "fn b() {", "    body()", "}"},
+			next:     []string{"// doc", "// more", "fn a() {}", "fn b() {", "    body()", "}"},
+			line:     2,
+			col:      6,
+			wantLine: 4,
+			wantCol:  6,
+		},
+		{
+			name:     "строки удалены ниже",
+			old:      []string{"one", "two", "three", "four"},
+			next:     []string{"one", "two"},
+			line:     1,
+			col:      2,
+			wantLine: 1,
+			wantCol:  2,
+		},
+		{
+			name:     "строку курсора переформатировали",
+			old:      []string{"x", "let  y=1", "z"},
+			next:     []string{"x", "let y = 1", "z"},
+			line:     1,
+			col:      6, // на «=»
+			wantLine: 1,
+			wantCol:  6,
+		},
+		{
+			name:     "отступ строки курсора изменился",
+			old:      []string{"if a {", "b()", "}"},
+			next:     []string{"if a {", "    b()", "}"},
+			line:     1,
+			col:      1,
+			wantLine: 1,
+			wantCol:  5,
+		},
+		{
+			name:     "строку курсора заменили",
+			old:      []string{"a", "old text here", "c"},
+			next:     []string{"a", "new", "c"},
+			line:     1,
+			col:      10,
+			wantLine: 1,
+			wantCol:  3, // колонка обрезана по новой строке
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab := &editorTab{lines: tt.next}
+			tab.cursor = cursorPosition{Line: tt.line, Col: tt.col}
+			tab.remapCursor(tt.old)
+			if tab.cursor.Line != tt.wantLine || tab.cursor.Col != tt.wantCol {
+				t.Errorf("cursor = %d:%d, want %d:%d", tab.cursor.Line, tab.cursor.Col, tt.wantLine, tt.wantCol)
+			}
+		})
+	}
+}
+
+// Прокрутка сдвигается вместе со строкой курсора, чтобы код не прыгал на экране.
+func TestRemapCursorKeepsScrollOffset(t *testing.T) {
+	old := make([]string, 100)
+	for i := range old {
+		old[i] = fmt.Sprintf("line %d", i)
+	}
+	next := append([]string{"inserted 1", "inserted 2", "inserted 3"}, old...)
+	tab := &editorTab{lines: next, scroll: 40}
+	tab.cursor = cursorPosition{Line: 50}
+	tab.remapCursor(old)
+	if tab.cursor.Line != 53 || tab.scroll != 43 {
+		t.Errorf("cursor line %d, scroll %d; want 53, 43", tab.cursor.Line, tab.scroll)
+	}
+}
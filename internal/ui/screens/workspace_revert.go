@@ -0,0 +1,69 @@
+package screens
+
+import (
+	"os"
+	"time"
+)
+
+// markSaved запоминает текущее содержимое как последнее сохранённое.
+func (t *editorTab) markSaved() {
+	t.savedLines = append([]string(nil), t.lines...)
+	t.clearChanges()
+	t.refreshOutline()
+	t.savedAt = time.Time{}
+	if info, err := os.Stat(t.path); err == nil {
+		t.savedAt = info.ModTime()
+	}
+}
+
+// diskChanged сообщает, что файл на диске изменили после последнего сохранения.
+func (t *editorTab) diskChanged() bool {
+	if t.path == "" || t.savedAt.IsZero() {
+		return false
+	}
+	info, err := os.Stat(t.path)
+	return err == nil && info.ModTime().After(t.savedAt)
+}
+
+// revertFromDisk заменяет буфер содержимым файла и сбрасывает историю undo.
+func (t *editorTab) revertFromDisk() error {
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return err
+	}
+	lines, warnings := decodeBuffer(data)
+	t.decodeWarnings = warnings
+	t.original = nil
+	if len(warnings) > 0 {
+		t.original = data
+	}
+	t.replaceContent(lines)
+	t.markSaved()
+	return nil
+}
+
+// revertToLastSave возвращает буфер к последнему сохранению, даже если файл
+// на диске с тех пор изменился; буфер остаётся изменённым относительно диска.
+func (t *editorTab) revertToLastSave() {
+	t.replaceContent(append([]string(nil), t.savedLines...))
+	t.dirty = t.diskChanged()
+}
+
+// replaceContent подменяет строки буфера, оставляя курсор на той же строке кода.
+// История undo сбрасывается, поэтому прежнее содержимое остаётся контрольной
+// точкой для Rollback Buffer to….
+func (t *editorTab) replaceContent(lines []string) {
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+	old := t.lines
+	t.keepCheckpoint(time.Now(), old, true)
+	t.lines = lines
+	t.markModified()
+	t.dirty = false
+	t.undoStack = nil
+	t.redoStack = nil
+	t.stopVisual()
+	t.clearPending()
+	t.remapCursor(old)
+}
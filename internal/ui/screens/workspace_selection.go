@@ -0,0 +1,126 @@
+package screens
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// startVisual включает режим выделения от текущей позиции курсора.
+// linewise выбирает построчное выделение (V) вместо посимвольного (v).
+func (t *editorTab) startVisual(linewise bool) {
+	t.mode = editorModeVisual
+	t.anchor = t.cursor
+	t.visualLine = linewise
+}
+
+// stopVisual выходит из режима выделения.
+func (t *editorTab) stopVisual() {
+	if t.mode == editorModeVisual {
+		t.mode = editorModeNormal
+	}
+	t.visualLine = false
+}
+
+func (t *editorTab) hasSelection() bool {
+	return t.mode == editorModeVisual
+}
+
+// selectionBounds возвращает начало и конец выделения в порядке следования.
+// Конец включительный: символ под курсором входит в выделение.
+func (t *editorTab) selectionBounds() (cursorPosition, cursorPosition) {
+	start, end := t.anchor, t.cursor
+	if end.Line < start.Line || (end.Line == start.Line && end.Col < start.Col) {
+		start, end = end, start
+	}
+	return start, end
+}
+
+// selectedLineRange возвращает диапазон строк, затронутых выделением.
+func (t *editorTab) selectedLineRange() (int, int) {
+	start, end := t.selectionBounds()
+	return start.Line, end.Line
+}
+
+// lineSelection возвращает выделенный отрезок строки [from, to) в рунах.
+// to может превышать длину строки на единицу — выделение захватывает перевод строки.
+func (t *editorTab) lineSelection(line int) (int, int, bool) {
+	if !t.hasSelection() {
+		return 0, 0, false
+	}
+	start, end := t.selectionBounds()
+	if line < start.Line || line > end.Line {
+		return 0, 0, false
+	}
+	length := utf8.RuneCountInString(t.lines[line])
+	if t.visualLine {
+		return 0, length + 1, true
+	}
+	from, to := 0, length+1
+	if line == start.Line {
+		from = start.Col
+	}
+	if line == end.Line {
+		to = end.Col + 1
+	}
+	return from, to, true
+}
+
+// indentLines добавляет unit в начало каждой непустой строки диапазона.
+// Возвращает true, если хотя бы одна строка изменилась.
+func (t *editorTab) indentLines(from, to int, unit string) bool {
+	width := utf8.RuneCountInString(unit)
+	changed := false
+	for i := from; i <= to && i < len(t.lines); i++ {
+		if t.lines[i] == "" {
+			continue
+		}
+		t.lines[i] = unit + t.lines[i]
+		t.shiftSelectionCol(i, width)
+		t.markLineChanged(i)
+		changed = true
+	}
+	return changed
+}
+
+// outdentLines снимает один уровень отступа с каждой строки диапазона.
+// Возвращает true, если хотя бы одна строка изменилась.
+func (t *editorTab) outdentLines(from, to int, unit string, tabSize int) bool {
+	changed := false
+	for i := from; i <= to && i < len(t.lines); i++ {
+		removed := outdentWidth(t.lines[i], unit, tabSize)
+		if removed == 0 {
+			continue
+		}
+		t.lines[i] = string([]rune(t.lines[i])[removed:])
+		t.shiftSelectionCol(i, -removed)
+		t.markLineChanged(i)
+		changed = true
+	}
+	return changed
+}
+
+// outdentWidth считает, сколько рун ведущего отступа нужно убрать.
+func outdentWidth(line, unit string, tabSize int) int {
+	if unit != "" && strings.HasPrefix(line, unit) {
+		return utf8.RuneCountInString(unit)
+	}
+	if strings.HasPrefix(line, "\t") {
+		return 1
+	}
+	n := 0
+	for n < len(line) && n < max(tabSize, 1) && line[n] == ' ' {
+		n++
+	}
+	return n
+}
+
+// shiftSelectionCol сдвигает курсор и якорь на строке line, чтобы выделение
+// оставалось на том же тексте после изменения отступа.
+func (t *editorTab) shiftSelectionCol(line, delta int) {
+	if t.cursor.Line == line {
+		t.cursor.Col = max(t.cursor.Col+delta, 0)
+	}
+	if t.anchor.Line == line {
+		t.anchor.Col = max(t.anchor.Col+delta, 0)
+	}
+}
@@ -0,0 +1,91 @@
+package screens
+
+import "time"
+
+// maxUndoDepth ограничивает историю изменений одной вкладки.
+const maxUndoDepth = 200
+
+// editorSnapshot хранит состояние буфера для undo/redo и момент, когда оно
+// снято.
+type editorSnapshot struct {
+	lines  []string
+	cursor cursorPosition
+	at     time.Time
+}
+
+func (t *editorTab) snapshot() editorSnapshot {
+	lines := make([]string, len(t.lines))
+	copy(lines, t.lines)
+	return editorSnapshot{lines: lines, cursor: t.cursor, at: time.Now()}
+}
+
+// pushUndo запоминает текущее состояние перед правкой и сбрасывает redo.
+func (t *editorTab) pushUndo() {
+	t.pushSnapshot(t.snapshot())
+}
+
+// pushSnapshot кладёт заранее снятое состояние в историю. Нужен, когда
+// заранее неизвестно, изменит ли операция буфер. Состояние до правки
+// заодно становится контрольной точкой, если прошлая достаточно стара.
+func (t *editorTab) pushSnapshot(s editorSnapshot) {
+	if !s.at.IsZero() {
+		t.keepCheckpoint(s.at, s.lines, false)
+	}
+	t.undoStack = append(t.undoStack, s)
+	if len(t.undoStack) > maxUndoDepth {
+		t.undoStack = t.undoStack[len(t.undoStack)-maxUndoDepth:]
+	}
+	t.redoStack = nil
+}
+
+// dropUnchangedUndo убирает последний снимок, если буфер с тех пор не менялся
+// (например, вход в insert-режим без ввода).
+func (t *editorTab) dropUnchangedUndo() {
+	if len(t.undoStack) == 0 {
+		return
+	}
+	last := t.undoStack[len(t.undoStack)-1]
+	if len(last.lines) != len(t.lines) {
+		return
+	}
+	for i := range last.lines {
+		if last.lines[i] != t.lines[i] {
+			return
+		}
+	}
+	t.undoStack = t.undoStack[:len(t.undoStack)-1]
+}
+
+// undo откатывает последнюю правку. Возвращает false, если откатывать нечего.
+func (t *editorTab) undo() bool {
+	if len(t.undoStack) == 0 {
+		return false
+	}
+	last := t.undoStack[len(t.undoStack)-1]
+	t.undoStack = t.undoStack[:len(t.undoStack)-1]
+	t.redoStack = append(t.redoStack, t.snapshot())
+	t.restore(last)
+	return true
+}
+
+// redo повторяет отменённую правку.
+func (t *editorTab) redo() bool {
+	if len(t.redoStack) == 0 {
+		return false
+	}
+	next := t.redoStack[len(t.redoStack)-1]
+	t.redoStack = t.redoStack[:len(t.redoStack)-1]
+	t.undoStack = append(t.undoStack, t.snapshot())
+	t.restore(next)
+	return true
+}
+
+func (t *editorTab) restore(s editorSnapshot) {
+	t.lines = s.lines
+	if len(t.lines) == 0 {
+		t.lines = []string{""}
+	}
+	t.cursor = s.cursor
+	t.clampCursor()
+	t.markModified()
+}
@@ -0,0 +1,222 @@
+package screens
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Границы слова для движений w/b/e, выделения двойным щелчком и Ctrl+D.
+// Слово — буквы, цифры и символы editor.word_chars ("_" по умолчанию):
+// с "-." идентификатор foo-bar и путь std.io.fmt — одно слово, без них — три.
+
+// isWordRune сообщает, входит ли r в слово при наборе extra.
+func isWordRune(r rune, extra string) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(extra, r)
+}
+
+// Классы символов для движений: пробелы, слово и прочие знаки. Как в vim,
+// подряд идущие знаки (например "->") — тоже отдельное «слово».
+const (
+	runeSpace = iota
+	runeWord
+	runePunct
+)
+
+func runeClass(r rune, extra string) int {
+	switch {
+	case unicode.IsSpace(r):
+		return runeSpace
+	case isWordRune(r, extra):
+		return runeWord
+	}
+	return runePunct
+}
+
+// wordBoundsAt возвращает слово под позицией col строки: [from, to) в рунах.
+// Если под col не слово, а курсор стоит сразу за словом, берётся оно.
+func wordBoundsAt(line []rune, col int, extra string) (int, int, bool) {
+	if col >= len(line) || (col >= 0 && !isWordRune(line[col], extra)) {
+		col--
+	}
+	if col < 0 || col >= len(line) || !isWordRune(line[col], extra) {
+		return 0, 0, false
+	}
+	from, to := col, col+1
+	for from > 0 && isWordRune(line[from-1], extra) {
+		from--
+	}
+	for to < len(line) && isWordRune(line[to], extra) {
+		to++
+	}
+	return from, to, true
+}
+
+// wordCursor обходит буфер по символам; конец строки считается пробелом,
+// чтобы движения переходили на следующую строку.
+type wordCursor struct {
+	tab   *editorTab
+	extra string
+	pos   cursorPosition
+	line  []rune
+}
+
+func newWordCursor(t *editorTab, extra string) *wordCursor {
+	c := &wordCursor{tab: t, extra: extra, pos: t.cursor}
+	c.load()
+	return c
+}
+
+func (c *wordCursor) load() {
+	c.line = []rune(c.tab.lines[c.pos.Line])
+	c.pos.Col = clampInt(c.pos.Col, 0, len(c.line))
+}
+
+func (c *wordCursor) class() int {
+	if c.pos.Col >= len(c.line) {
+		return runeSpace
+	}
+	return runeClass(c.line[c.pos.Col], c.extra)
+}
+
+// next сдвигает позицию вперёд; false — конец буфера.
+func (c *wordCursor) next() bool {
+	if c.pos.Col < len(c.line) {
+		c.pos.Col++
+		return true
+	}
+	if c.pos.Line+1 >= c.tab.lineCount() {
+		return false
+	}
+	c.pos = cursorPosition{Line: c.pos.Line + 1}
+	c.load()
+	return true
+}
+
+// prev сдвигает позицию назад; false — начало буфера.
+func (c *wordCursor) prev() bool {
+	if c.pos.Col > 0 {
+		c.pos.Col--
+		return true
+	}
+	if c.pos.Line == 0 {
+		return false
+	}
+	c.pos.Line--
+	c.load()
+	c.pos.Col = len(c.line)
+	return true
+}
+
+// wordForward ставит курсор на начало следующего слова (w).
+func (t *editorTab) wordForward(extra string) {
+	c := newWordCursor(t, extra)
+	if class := c.class(); class != runeSpace {
+		for c.class() == class && c.next() {
+		}
+	}
+	for c.class() == runeSpace && c.next() {
+	}
+	t.cursor = c.pos
+	t.clampCursor()
+}
+
+// wordBackward ставит курсор на начало текущего или предыдущего слова (b).
+func (t *editorTab) wordBackward(extra string) {
+	c := newWordCursor(t, extra)
+	if !c.prev() {
+		return
+	}
+	for c.class() == runeSpace && c.prev() {
+	}
+	class := c.class()
+	for c.pos.Col > 0 && runeClass(c.line[c.pos.Col-1], extra) == class {
+		c.pos.Col--
+	}
+	t.cursor = c.pos
+	t.clampCursor()
+}
+
+// wordEnd ставит курсор на конец текущего или следующего слова (e).
+func (t *editorTab) wordEnd(extra string) {
+	c := newWordCursor(t, extra)
+	if !c.next() {
+		return
+	}
+	for c.class() == runeSpace && c.next() {
+	}
+	class := c.class()
+	for c.pos.Col+1 < len(c.line) && runeClass(c.line[c.pos.Col+1], extra) == class {
+		c.pos.Col++
+	}
+	t.cursor = c.pos
+	t.clampCursor()
+}
+
+// selectWordAt выделяет слово под pos в посимвольном визуальном режиме.
+func (t *editorTab) selectWordAt(pos cursorPosition, extra string) bool {
+	if pos.Line < 0 || pos.Line >= t.lineCount() {
+		return false
+	}
+	from, to, ok := wordBoundsAt([]rune(t.lines[pos.Line]), pos.Col, extra)
+	if !ok {
+		return false
+	}
+	t.cursor = cursorPosition{Line: pos.Line, Col: from}
+	t.startVisual(false)
+	t.cursor.Col = to - 1
+	return true
+}
+
+// nextOccurrence ищет следующее после выделения вхождение его текста (с
+// переходом через конец буфера). Выделенное слово ищется только целым
+// словом. wrapped — поиск начался сначала буфера.
+func (t *editorTab) nextOccurrence(extra string) (cursorPosition, bool, bool) {
+	start, end := t.selectionBounds()
+	if start.Line != end.Line {
+		return cursorPosition{}, false, false
+	}
+	line := []rune(t.lines[start.Line])
+	to := min(end.Col+1, len(line))
+	if start.Col >= to {
+		return cursorPosition{}, false, false
+	}
+	needle := line[start.Col:to]
+	whole := true
+	for _, r := range needle {
+		whole = whole && isWordRune(r, extra)
+	}
+
+	count := t.lineCount()
+	for i := 0; i <= count; i++ {
+		lineIndex := (start.Line + i) % count
+		runes := []rune(t.lines[lineIndex])
+		from := 0
+		if i == 0 {
+			from = start.Col + 1
+		}
+		for col := from; col+len(needle) <= len(runes); col++ {
+			if i == count && col >= start.Col {
+				break // обошли буфер и вернулись к самому выделению
+			}
+			if !runesEqualAt(runes, col, needle) {
+				continue
+			}
+			if whole && ((col > 0 && isWordRune(runes[col-1], extra)) ||
+				(col+len(needle) < len(runes) && isWordRune(runes[col+len(needle)], extra))) {
+				continue
+			}
+			wrapped := lineIndex < start.Line || (lineIndex == start.Line && i > 0)
+			return cursorPosition{Line: lineIndex, Col: col}, wrapped, true
+		}
+	}
+	return cursorPosition{}, false, false
+}
+
+func runesEqualAt(haystack []rune, at int, needle []rune) bool {
+	for i, r := range needle {
+		if haystack[at+i] != r {
+			return false
+		}
+	}
+	return true
+}
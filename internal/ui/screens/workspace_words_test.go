@@ -0,0 +1,127 @@
+package screens
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWordBoundsAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		col      int
+		extra    string
+		from, to int
+		ok       bool
+	}{
+		{"часть идентификатора без word_chars", "foo-bar", 4, "", 4, 7, true},
+		{"идентификатор целиком с \"-.\"", "foo-bar", 4, "-.", 0, 7, true},
+		{"на дефисе берётся слово слева", "foo-bar", 3, "", 0, 3, true},
+		{"сегмент пути без word_chars", "std.io.print", 5, "", 4, 6, true},
+		{"путь целиком с \"-.\"", "std.io.print", 5, "-.", 0, 12, true},
+		{"сразу за словом", "std.io.print", 12, "", 7, 12, true},
+		{"между знаками слова нет", "a -> b", 3, "", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, ok := wordBoundsAt([]rune(tt.line), tt.col, tt.extra)
+			if from != tt.from || to != tt.to || ok != tt.ok {
+				t.Errorf("wordBoundsAt = (%d, %d, %v), want (%d, %d, %v)", from, to, ok, tt.from, tt.to, tt.ok)
+			}
+		})
+	}
+}
+
+func TestWordMotions(t *testing.T) {
+	lines := []string{"foo-bar std.io.print", "x"}
+	pos := func(line, col int) cursorPosition { return cursorPosition{Line: line, Col: col} }
+	tests := []struct {
+		name  string
+		key   string
+		extra string
+		start cursorPosition
+		want  []cursorPosition
+	}{
+		{"w без word_chars", "w", "", pos(0, 0),
+			[]cursorPosition{pos(0, 3), pos(0, 4), pos(0, 8), pos(0, 11), pos(0, 12), pos(0, 14), pos(0, 15), pos(1, 0)}},
+		{"w с \"-.\"", "w", "-.", pos(0, 0),
+			[]cursorPosition{pos(0, 8), pos(1, 0)}},
+		{"e без word_chars", "e", "", pos(0, 0),
+			[]cursorPosition{pos(0, 2), pos(0, 3), pos(0, 6), pos(0, 10), pos(0, 11), pos(0, 13), pos(0, 14), pos(0, 19), pos(1, 0)}},
+		{"e с \"-.\"", "e", "-.", pos(0, 0),
+			[]cursorPosition{pos(0, 6), pos(0, 19), pos(1, 0)}},
+		{"b без word_chars", "b", "", pos(1, 0),
+			[]cursorPosition{pos(0, 15), pos(0, 14), pos(0, 12), pos(0, 11), pos(0, 8), pos(0, 4), pos(0, 3), pos(0, 0)}},
+		{"b с \"-.\"", "b", "-.", pos(1, 0),
+			[]cursorPosition{pos(0, 8), pos(0, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab := newTestTab(t, "main.sg", lines...)
+			ps := newTestProject(t, tab)
+			ps.editorCfg.WordChars = tt.extra
+			tab.cursor = tt.start
+
+			var got []cursorPosition
+			for range tt.want {
+				press(ps, tt.key)
+				got = append(got, tab.cursor)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("%s: cursor went %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	lines := []string{"foo-bar foo std.io.print", "io foo-bar std.io"}
+	tests := []struct {
+		name    string
+		extra   string
+		at      cursorPosition // слово под курсором становится выделением
+		want    cursorPosition
+		wrapped bool
+		ok      bool
+	}{
+		{"foo целым словом без word_chars", "", cursorPosition{0, 0}, cursorPosition{0, 8}, false, true},
+		{"foo-bar целиком с \"-.\"", "-.", cursorPosition{0, 0}, cursorPosition{1, 3}, false, true},
+		{"foo рядом с дефисом — целое слово без word_chars", "", cursorPosition{0, 8}, cursorPosition{1, 3}, false, true},
+		{"с \"-.\" foo внутри foo-bar не подходит", "-.", cursorPosition{0, 8}, cursorPosition{}, false, false},
+		{"io в пути без word_chars", "", cursorPosition{1, 0}, cursorPosition{1, 15}, false, true},
+		{"переход через конец буфера", "", cursorPosition{1, 15}, cursorPosition{0, 16}, true, true},
+		{"с \"-.\" io внутри std.io не подходит", "-.", cursorPosition{1, 0}, cursorPosition{}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab := newTestTab(t, "main.sg", lines...)
+			if !tab.selectWordAt(tt.at, tt.extra) {
+				t.Fatalf("no word at %v", tt.at)
+			}
+			got, wrapped, ok := tab.nextOccurrence(tt.extra)
+			if got != tt.want || wrapped != tt.wrapped || ok != tt.ok {
+				t.Errorf("nextOccurrence = (%v, %v, %v), want (%v, %v, %v)", got, wrapped, ok, tt.want, tt.wrapped, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCtrlDSelectsWholeWordWithWordChars(t *testing.T) {
+	tab := newTestTab(t, "main.sg", "use std.io.print", "std.io.print(std.io)")
+	ps := newTestProject(t, tab)
+	ps.editorCfg.WordChars = "-."
+	moveTo(tab, 0, 6)
+
+	press(ps, "ctrl+d")
+	if tab.anchor != (cursorPosition{0, 4}) || tab.cursor != (cursorPosition{0, 15}) {
+		t.Fatalf("first ctrl+d selected %v..%v, want the whole std.io.print", tab.anchor, tab.cursor)
+	}
+	press(ps, "ctrl+d")
+	if tab.anchor != (cursorPosition{1, 0}) || tab.cursor != (cursorPosition{1, 11}) {
+		t.Fatalf("second ctrl+d selected %v..%v, want std.io.print on the next line", tab.anchor, tab.cursor)
+	}
+	press(ps, "ctrl+d")
+	if tab.anchor != (cursorPosition{0, 4}) || !statusShown(&ps.status, "Next occurrence (wrapped to top)") {
+		t.Errorf("third ctrl+d: anchor %v, status %q; std.io inside the call must not match", tab.anchor, ps.StatusHistory())
+	}
+}
@@ -0,0 +1,55 @@
+package styles
+
+import "surge-tui/internal/syntax"
+
+// CustomTheme — пользовательская тема из конфига. Пустые и неверные цвета
+// берутся из встроенной темы Base.
+type CustomTheme struct {
+	Base       string // "dark" или "light"
+	Background string
+	Text       string
+	Accent     string
+	Error      string
+	Warning    string
+	Success    string
+	Selection  string
+	Syntax     map[string]string // цвета подсветки по виду токена
+}
+
+// NewCustomTheme создает тему name поверх встроенной темы custom.Base.
+func NewCustomTheme(name string, custom CustomTheme) *Theme {
+	t := NewTheme(custom.Base)
+	t.name = name
+
+	set := func(color string, targets ...*string) {
+		if !syntax.ValidColor(color) {
+			return
+		}
+		for _, target := range targets {
+			*target = color
+		}
+	}
+	c, p := &t.colors, &t.palette
+	set(custom.Background, &c.Background)
+	set(custom.Text, &c.Text, &p.Text, &p.ListSelectionText)
+	set(custom.Accent, &c.Primary, &c.BorderFocus, &p.Primary)
+	set(custom.Error, &c.Error, &p.Error, &p.ErrorSoft, &p.Deleted)
+	set(custom.Warning, &c.Warning, &c.Accent, &p.Warning, &p.Accent)
+	set(custom.Success, &c.Success, &c.Secondary, &p.Success, &p.Added)
+	set(custom.Selection, &p.Selection, &p.ListSelection)
+	t.syntax = custom.Syntax
+
+	t.initStyles()
+	return t
+}
+
+// Base возвращает встроенную тему, на которой основана тема.
+func (t *Theme) Base() string {
+	return t.base
+}
+
+// Highlight строит тему подсветки синтаксиса: палитру встроенной темы с
+// заменами пользовательской.
+func (t *Theme) Highlight() *syntax.HighlightTheme {
+	return syntax.NewHighlightThemeFromPalette(syntax.BuiltinPalette(t.base).WithColors(t.syntax))
+}
@@ -0,0 +1,62 @@
+package styles
+
+import "github.com/charmbracelet/lipgloss"
+
+// FocusMarker дублирует цвет рамки у панели с фокусом: на терминалах с
+// урезанной палитрой и в светлой теме одного цвета бывает недостаточно.
+const FocusMarker = "●"
+
+// FocusStyle — единое оформление панелей с фокусом и без него.
+type FocusStyle struct {
+	Border      lipgloss.Color
+	BorderFocus lipgloss.Color
+	Title       lipgloss.Color
+	TitleFocus  lipgloss.Color
+}
+
+// Focus возвращает оформление фокуса для цветовой схемы темы.
+func (t *Theme) Focus() FocusStyle {
+	return FocusStyle{
+		Border:      lipgloss.Color(t.colors.Border),
+		BorderFocus: lipgloss.Color(t.colors.BorderFocus),
+		Title:       lipgloss.Color(t.colors.TextDim),
+		TitleFocus:  lipgloss.Color(t.colors.Text),
+	}
+}
+
+// DefaultFocus — оформление фокуса тёмной темы, пока экран не получил тему.
+func DefaultFocus() FocusStyle {
+	return NewTheme("dark").Focus()
+}
+
+// BorderColor возвращает цвет рамки панели.
+func (f FocusStyle) BorderColor(focused bool) lipgloss.Color {
+	if focused {
+		return f.BorderFocus
+	}
+	return f.Border
+}
+
+// Panel возвращает стиль рамки панели.
+func (f FocusStyle) Panel(focused bool) lipgloss.Style {
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(f.BorderColor(focused))
+}
+
+// RenderTitle рендерит заголовок панели; у панели с фокусом к нему добавляется маркер.
+func (f FocusStyle) RenderTitle(title string, focused bool) string {
+	if !focused {
+		return lipgloss.NewStyle().Bold(true).Foreground(f.Title).Render(title)
+	}
+	return lipgloss.NewStyle().Bold(true).Foreground(f.TitleFocus).Render(title) +
+		" " + lipgloss.NewStyle().Foreground(f.BorderFocus).Render(FocusMarker)
+}
+
+// Marker возвращает маркер фокуса или пробелы той же ширины, чтобы строка не сдвигалась.
+func (f FocusStyle) Marker(focused bool) string {
+	if !focused {
+		return " "
+	}
+	return lipgloss.NewStyle().Foreground(f.BorderFocus).Render(FocusMarker)
+}
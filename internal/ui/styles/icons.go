@@ -0,0 +1,180 @@
+package styles
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Значки файлов в дереве проекта, выборе проекта и вкладках. Значок ищется
+// по расширению (".sg"), а для каталогов и строки загрузки — по ключам
+// IconDir, IconDirOpen и IconLoading; остальное получает IconFile. Цвет
+// задаётся именем цвета темы ("accent", "text_dim", ...) или #RRGGBB.
+
+// Особые ключи таблицы значков.
+const (
+	IconDir     = "dir"
+	IconDirOpen = "dir_open"
+	IconFile    = "file"
+	IconLoading = "loading"
+)
+
+// Режимы значков (ui.icons).
+const (
+	IconsNerd  = "nerd"
+	IconsASCII = "ascii"
+)
+
+// IconSpec — значок и цвет до разрешения цвета темой.
+type IconSpec struct {
+	Glyph string
+	Color string
+}
+
+// Icon — значок с цветом темы.
+type Icon struct {
+	Glyph string
+	Color lipgloss.Color
+}
+
+// Render рисует значок его цветом.
+func (i Icon) Render() string {
+	if i.Color == "" {
+		return i.Glyph
+	}
+	return lipgloss.NewStyle().Foreground(i.Color).Render(i.Glyph)
+}
+
+// IconSet выбирает значки для имён файлов.
+type IconSet struct {
+	specs  map[string]IconSpec
+	colors ColorScheme
+}
+
+var (
+	imageExts  = []string{".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".bmp", ".ico"}
+	binaryExts = []string{".exe", ".bin", ".so", ".dll", ".dylib", ".o", ".a", ".wasm"}
+)
+
+// nerdIcons — значки Nerd Font.
+func nerdIcons() map[string]IconSpec {
+	icons := map[string]IconSpec{
+		IconDir:     {Glyph: "\uf07b", Color: "accent"},
+		IconDirOpen: {Glyph: "\uf07c", Color: "accent"},
+		IconFile:    {Glyph: "\uf15b", Color: "text_dim"},
+		IconLoading: {Glyph: "\uf110", Color: "text_dim"},
+		".sg":       {Glyph: "\uf121", Color: "primary"},
+		".md":       {Glyph: "\uf48a", Color: "text"},
+		".toml":     {Glyph: "\ue615", Color: "warning"},
+		".json":     {Glyph: "\ue60b", Color: "warning"},
+		".yaml":     {Glyph: "\ue615", Color: "warning"},
+		".yml":      {Glyph: "\ue615", Color: "warning"},
+	}
+	for _, ext := range imageExts {
+		icons[ext] = IconSpec{Glyph: "\uf1c5", Color: "secondary"}
+	}
+	for _, ext := range binaryExts {
+		icons[ext] = IconSpec{Glyph: "\uf471", Color: "error"}
+	}
+	return icons
+}
+
+// asciiIcons — значки для терминалов без Unicode-шрифтов.
+func asciiIcons() map[string]IconSpec {
+	icons := map[string]IconSpec{
+		IconDir:     {Glyph: "+", Color: "accent"},
+		IconDirOpen: {Glyph: "-", Color: "accent"},
+		IconFile:    {Glyph: ".", Color: "text_dim"},
+		IconLoading: {Glyph: "~", Color: "text_dim"},
+		".sg":       {Glyph: "*", Color: "primary"},
+		".md":       {Glyph: "#", Color: "text"},
+		".toml":     {Glyph: "=", Color: "warning"},
+		".json":     {Glyph: "=", Color: "warning"},
+		".yaml":     {Glyph: "=", Color: "warning"},
+		".yml":      {Glyph: "=", Color: "warning"},
+	}
+	for _, ext := range imageExts {
+		icons[ext] = IconSpec{Glyph: "%", Color: "secondary"}
+	}
+	for _, ext := range binaryExts {
+		icons[ext] = IconSpec{Glyph: "!", Color: "error"}
+	}
+	return icons
+}
+
+// Icons собирает значки режима mode (IconsNerd или IconsASCII) с
+// пользовательскими заменами; у замены без цвета остаётся цвет по умолчанию.
+func (t *Theme) Icons(mode string, overrides map[string]IconSpec) IconSet {
+	specs := nerdIcons()
+	if mode == IconsASCII {
+		specs = asciiIcons()
+	}
+	for key, spec := range overrides {
+		key = strings.ToLower(key)
+		if key != IconDir && key != IconDirOpen && key != IconFile && key != IconLoading && !strings.HasPrefix(key, ".") {
+			key = "." + key
+		}
+		if spec.Color == "" {
+			spec.Color = specs[key].Color
+		}
+		if spec.Glyph == "" {
+			spec.Glyph = specs[key].Glyph
+		}
+		specs[key] = spec
+	}
+	return IconSet{specs: specs, colors: t.colors}
+}
+
+// DefaultIcons — значки Nerd Font тёмной темы, пока экран не получил тему.
+func DefaultIcons() IconSet {
+	return NewTheme("dark").Icons(IconsNerd, nil)
+}
+
+// Lookup возвращает значок по ключу таблицы; неизвестный ключ — IconFile.
+func (s IconSet) Lookup(key string) Icon {
+	spec, ok := s.specs[key]
+	if !ok || spec.Glyph == "" {
+		spec = s.specs[IconFile]
+	}
+	return Icon{Glyph: spec.Glyph, Color: s.resolveColor(spec.Color)}
+}
+
+// ForFile возвращает значок файла по расширению имени.
+func (s IconSet) ForFile(name string) Icon {
+	return s.Lookup(strings.ToLower(filepath.Ext(name)))
+}
+
+// ForDir возвращает значок раскрытого или свёрнутого каталога.
+func (s IconSet) ForDir(expanded bool) Icon {
+	if expanded {
+		return s.Lookup(IconDirOpen)
+	}
+	return s.Lookup(IconDir)
+}
+
+// resolveColor переводит имя цвета темы в цвет; #RRGGBB и номера ANSI
+// передаются как есть.
+func (s IconSet) resolveColor(name string) lipgloss.Color {
+	switch strings.ToLower(name) {
+	case "":
+		return ""
+	case "primary":
+		return lipgloss.Color(s.colors.Primary)
+	case "secondary":
+		return lipgloss.Color(s.colors.Secondary)
+	case "accent":
+		return lipgloss.Color(s.colors.Accent)
+	case "text":
+		return lipgloss.Color(s.colors.Text)
+	case "text_dim":
+		return lipgloss.Color(s.colors.TextDim)
+	case "error":
+		return lipgloss.Color(s.colors.Error)
+	case "success":
+		return lipgloss.Color(s.colors.Success)
+	case "warning":
+		return lipgloss.Color(s.colors.Warning)
+	}
+	return lipgloss.Color(name)
+}
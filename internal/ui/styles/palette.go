@@ -0,0 +1,106 @@
+package styles
+
+// Palette — цвета экранов по назначению. ColorScheme задаёт основу темы,
+// а палитра добавляет оттенки, которые экраны раньше держали константами:
+// строки редактора, уровни диагностик, метки изменений.
+type Palette struct {
+	Primary   string // акцент: выбранный пункт, курсор, активная вкладка
+	OnPrimary string // текст поверх Primary
+
+	Text      string // основной текст
+	TextSoft  string // вкладки и полосы состояния
+	TextDim   string // подсказки и второстепенное
+	TextMuted string // номера строк, неактивное
+
+	Surface     string // полосы состояния
+	SurfaceAlt  string // полоса режима редактора
+	CurrentLine string // строка с курсором
+	Border      string // рамки и дорожка полосы прокрутки
+
+	Selection         string // выделение текста
+	ListSelection     string // выбранная строка списка
+	ListSelectionText string // текст выбранной строки списка
+
+	Accent  string // закладки, изменённые настройки, новые файлы
+	Heading string // заголовки и активный режим
+	Info    string
+	Success string
+	Warning string
+	Error   string
+	// ErrorSoft — ошибки в списках и удалённые строки diff, мягче Error
+	ErrorSoft string
+
+	Added   string // метки изменений в редакторе и diff
+	Changed string
+	Deleted string
+}
+
+// Предустановленные палитры
+var (
+	DarkPalette = Palette{
+		Primary:   "#7C3AED",
+		OnPrimary: "#FFFFFF",
+
+		Text:      "#F8FAFC",
+		TextSoft:  "#CBD5F5",
+		TextDim:   "#94A3B8",
+		TextMuted: "#64748B",
+
+		Surface:     "#1E293B",
+		SurfaceAlt:  "#111827",
+		CurrentLine: "#1F2937",
+		Border:      "#334155",
+
+		Selection:         "#3730A3",
+		ListSelection:     "#312E81",
+		ListSelectionText: "#F8FAFC",
+
+		Accent:    "#F59E0B",
+		Heading:   "#38BDF8",
+		Info:      "#A5B4FC",
+		Success:   "#10B981",
+		Warning:   "#FBBF24",
+		Error:     "#EF4444",
+		ErrorSoft: "#F87171",
+
+		Added:   "#22C55E",
+		Changed: "#3B82F6",
+		Deleted: "#EF4444",
+	}
+
+	LightPalette = Palette{
+		Primary:   "#7C3AED",
+		OnPrimary: "#FFFFFF",
+
+		Text:      "#0F172A",
+		TextSoft:  "#334155",
+		TextDim:   "#64748B",
+		TextMuted: "#94A3B8",
+
+		Surface:     "#E2E8F0",
+		SurfaceAlt:  "#F1F5F9",
+		CurrentLine: "#F1F5F9",
+		Border:      "#CBD5E1",
+
+		Selection:         "#C7D2FE",
+		ListSelection:     "#E0E7FF",
+		ListSelectionText: "#1E1B4B",
+
+		Accent:    "#D97706",
+		Heading:   "#0284C7",
+		Info:      "#4F46E5",
+		Success:   "#059669",
+		Warning:   "#B45309",
+		Error:     "#DC2626",
+		ErrorSoft: "#DC2626",
+
+		Added:   "#16A34A",
+		Changed: "#2563EB",
+		Deleted: "#DC2626",
+	}
+)
+
+// Palette возвращает палитру экранов для темы.
+func (t *Theme) Palette() Palette {
+	return t.palette
+}
@@ -11,7 +11,11 @@ type Theme struct {
 	height int
 
 	// Цветовая схема
-	colors ColorScheme
+	name    string
+	base    string // встроенная тема, на которой основана эта
+	colors  ColorScheme
+	palette Palette
+	syntax  map[string]string // замены цветов подсветки
 
 	// Стили компонентов
 	StatusBarStyle   lipgloss.Style
@@ -81,15 +85,20 @@ var (
 // NewTheme создает новую тему
 func NewTheme(themeName string) *Theme {
 	var colors ColorScheme
+	var palette Palette
 	switch themeName {
 	case "light":
-		colors = LightScheme
+		colors, palette = LightScheme, LightPalette
 	default:
-		colors = DarkScheme
+		themeName = "dark"
+		colors, palette = DarkScheme, DarkPalette
 	}
 
 	theme := &Theme{
-		colors: colors,
+		name:    themeName,
+		base:    themeName,
+		colors:  colors,
+		palette: palette,
 	}
 
 	theme.initStyles()
@@ -161,6 +170,11 @@ func (t *Theme) initStyles() {
 		Padding(0, 1)
 }
 
+// Name возвращает имя темы ("dark" или "light").
+func (t *Theme) Name() string {
+	return t.name
+}
+
 // SetDimensions устанавливает размеры экрана
 func (t *Theme) SetDimensions(width, height int) {
 	t.width = width